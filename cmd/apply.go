@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/acheevo/template-engine/internal/generate"
+	"github.com/spf13/cobra"
+)
+
+var (
+	applyInto        string
+	applyProjectName string
+	applyGithubRepo  string
+	applyAuthor      string
+	applyDescription string
+	applyOnly        []string
+	applyOnConflict  string
+	applyNoVerify    bool
+	applyHooks       bool
+	applyAllowHooks  bool
+	applyVars        []string
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply <schema.json>",
+	Short: "Overlay template files onto an existing project",
+	Long: `Render a template schema and overlay its files onto an existing project,
+to adopt pieces of a template (lint config, Makefile, CI workflows) without
+regenerating the project.
+
+Files missing from the project are created and identical files are left
+alone. For files that differ, --on-conflict decides: prompt asks for each
+file (the default), skip keeps the project's version and overwrite takes the
+template's. Nothing is written until every conflict has been decided.
+
+With --hooks, the schema's post_update hooks run in the project directory
+once files have been written. As with generate, hooks of schemas pulled from
+a registry also need --allow-hooks and the hook policy applies.
+
+Examples:
+  template-engine apply api-template.json --into ./my-api \
+    --project-name "My API" --github-repo "user/my-api" --only .github --only .golangci.yml
+  template-engine apply api-template.json --into ./my-api \
+    --project-name "My API" --github-repo "user/my-api" --only Makefile --on-conflict overwrite`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		resolve, err := conflictResolver(applyOnConflict)
+		if err != nil {
+			return err
+		}
+		hooks, err := hookOptions(applyHooks, applyAllowHooks)
+		if err != nil {
+			return err
+		}
+		policies, err := orgPolicies()
+		if err != nil {
+			return err
+		}
+
+		author := applyAuthor
+		if author == "" {
+			author = generate.DefaultAuthor(cmd.Context())
+		}
+		variables, err := generate.ParseVariableAssignments(applyVars)
+		if err != nil {
+			return err
+		}
+		variables["Author"] = author
+		if applyDescription != "" {
+			variables["Description"] = applyDescription
+		}
+
+		result, err := generate.Apply(cmd.Context(), logger, generate.ApplyParams{
+			TemplateFile: args[0],
+			IntoDir:      applyInto,
+			ProjectName:  applyProjectName,
+			GitHubRepo:   applyGithubRepo,
+			Variables:    variables,
+			Only:         applyOnly,
+			NoVerify:     applyNoVerify,
+			Hooks:        hooks,
+			Policies:     policies,
+			Resolve:      resolve,
+		})
+		if err != nil {
+			return err
+		}
+		if jsonOutput {
+			return printJSON(result)
+		}
+		return nil
+	},
+}
+
+func init() {
+	applyCmd.Flags().StringVar(&applyInto, "into", "", "Existing project directory (required)")
+	applyCmd.Flags().StringVar(&applyProjectName, "project-name", "", "Name of the project (required)")
+	applyCmd.Flags().StringVar(&applyGithubRepo, "github-repo", "",
+		"GitHub repository: username/repo-name or its github.com URL (required)")
+	applyCmd.Flags().StringVar(&applyAuthor, "author", "", "Project author (defaults to the git user)")
+	applyCmd.Flags().StringVar(&applyDescription, "description", "", "Project description")
+	applyCmd.Flags().StringArrayVar(&applyVars, "var", nil,
+		"Set a custom variable declared by the schema (NAME=VALUE, repeatable)")
+	applyCmd.Flags().StringArrayVar(&applyOnly, "only", nil,
+		"Apply only files matching this glob, or below this directory (repeatable)")
+	applyCmd.Flags().StringVar(&applyOnConflict, "on-conflict", "prompt",
+		"What to do with files that differ from the template: prompt, skip or overwrite")
+	applyCmd.Flags().BoolVar(&applyNoVerify, "no-verify", false,
+		"Skip file hash verification (for schemas edited by hand, see fix-hashes)")
+	applyCmd.Flags().BoolVar(&applyHooks, "hooks", false, "Run the schema's post_update hooks")
+	applyCmd.Flags().BoolVar(&applyAllowHooks, "allow-hooks", false,
+		"Run the hooks of schemas pulled from a registry")
+	_ = applyCmd.RegisterFlagCompletionFunc("on-conflict", fixedCompletions("prompt", "skip", "overwrite"))
+	_ = applyCmd.MarkFlagRequired("into")
+	_ = applyCmd.MarkFlagRequired("project-name")
+	_ = applyCmd.MarkFlagRequired("github-repo")
+}
+
+// conflictResolver returns the conflict handling selected by --on-conflict
+func conflictResolver(mode string) (func(string, []byte, []byte) (generate.ConflictAction, error), error) {
+	switch mode {
+	case "prompt":
+		return newConflictPrompt(bufio.NewReader(os.Stdin)), nil
+	case string(generate.ConflictSkip), string(generate.ConflictOverwrite):
+		action := generate.ConflictAction(mode)
+		return func(string, []byte, []byte) (generate.ConflictAction, error) { return action, nil }, nil
+	default:
+		return nil, fmt.Errorf("invalid --on-conflict %q, expected prompt, skip or overwrite", mode)
+	}
+}
+
+// newConflictPrompt asks on stderr whether to overwrite each conflicting file, keeping stdout free
+// for results. Answering "a" overwrites all remaining conflicts and "s" skips them.
+func newConflictPrompt(input *bufio.Reader) func(string, []byte, []byte) (generate.ConflictAction, error) {
+	var remaining generate.ConflictAction
+	return func(path string, _, _ []byte) (generate.ConflictAction, error) {
+		if remaining != "" {
+			return remaining, nil
+		}
+
+		for {
+			fmt.Fprintf(os.Stderr, "%s differs from the template. Overwrite? [y/N/a(ll)/s(kip all)]: ", path)
+			answer, err := input.ReadString('\n')
+			if err != nil && answer == "" {
+				return "", fmt.Errorf("failed to read answer for %s: %w", path, err)
+			}
+
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "y", "yes":
+				return generate.ConflictOverwrite, nil
+			case "", "n", "no":
+				return generate.ConflictSkip, nil
+			case "a", "all":
+				remaining = generate.ConflictOverwrite
+				return remaining, nil
+			case "s", "skip":
+				remaining = generate.ConflictSkip
+				return remaining, nil
+			}
+		}
+	}
+}
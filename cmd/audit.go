@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/extract"
+	"github.com/spf13/cobra"
+)
+
+var (
+	auditMaxFileSize string
+	auditStrict      bool
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit <schema.json>",
+	Short: "Report personal and machine-specific data embedded in a schema",
+	Long: `Audit a template schema for things that commonly leak from reference
+projects into published templates: email addresses, absolute local paths
+(/Users/..., /home/..., C:\Users\...), the usernames of those paths wherever
+else they occur, machine-specific strings such as .local hostnames and LAN
+addresses, and files larger than --max-file-size.
+
+Placeholder addresses (example.com, GitHub noreply) and the home directories
+of CI runners and container images are not reported. With --strict, any
+finding makes the command fail, e.g. before publishing to a registry.
+
+Examples:
+  template-engine audit frontend-template.json
+  template-engine audit api-template.json --max-file-size 256KB --strict
+  template-engine audit api-template.json --json`,
+	Args: cobra.ExactArgs(1),
+	// Findings are not usage errors
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAudit(args[0])
+	},
+}
+
+func init() {
+	auditCmd.Flags().StringVar(&auditMaxFileSize, "max-file-size", "1MB",
+		"Size above which files are reported as oversized, e.g. 512KB (0 for no limit)")
+	auditCmd.Flags().BoolVar(&auditStrict, "strict", false, "Fail when anything is found")
+}
+
+func runAudit(schemaFile string) error {
+	maxFileSize, err := extract.ParseSize(auditMaxFileSize)
+	if err != nil {
+		return fmt.Errorf("--max-file-size: %w", err)
+	}
+	schema, err := core.LoadSchemaFile(schemaFile)
+	if err != nil {
+		return err
+	}
+
+	audit, err := core.AuditSchema(schema, maxFileSize)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		if err := printJSON(audit); err != nil {
+			return err
+		}
+	} else {
+		printAudit(schemaFile, audit)
+	}
+
+	if auditStrict && len(audit.Findings) > 0 {
+		return fmt.Errorf("schema %s has %d audit finding(s)", schemaFile, len(audit.Findings))
+	}
+	return nil
+}
+
+// printAudit prints a human-readable audit report
+func printAudit(schemaFile string, audit *core.Audit) {
+	fmt.Printf("Auditing %s\n", schemaFile)
+	fmt.Println()
+
+	for _, finding := range audit.Findings {
+		location := finding.File
+		if finding.Line > 0 {
+			location = fmt.Sprintf("%s:%d", finding.File, finding.Line)
+		}
+		fmt.Printf("  %-10s %s: %s\n", finding.Kind, location, finding.Match)
+	}
+
+	if len(audit.Findings) > 0 {
+		fmt.Println()
+	}
+	fmt.Printf("%d finding(s)\n", len(audit.Findings))
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/acheevo/template-engine/internal/schemacache"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the cache of extracted schemas",
+	Long: `The new command caches the schemas it extracts from reference projects in
+the user cache directory (e.g. ~/.cache/template-engine/schemas), keyed by the
+content of the project, the template type and the engine version.
+
+Examples:
+  template-engine cache dir
+  template-engine cache clear`,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all cached schemas",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cache, err := schemacache.Default()
+		if err != nil {
+			return err
+		}
+
+		removed, err := cache.Clear()
+		if err != nil {
+			return err
+		}
+		if jsonOutput {
+			return printJSON(map[string]int{"removed": removed})
+		}
+		logger.Info("Cleared schema cache", "removed", removed, "dir", cache.Dir())
+		return nil
+	},
+}
+
+var cacheDirCmd = &cobra.Command{
+	Use:   "dir",
+	Short: "Print the cache directory",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cache, err := schemacache.Default()
+		if err != nil {
+			return err
+		}
+		fmt.Println(cache.Dir())
+		return nil
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheClearCmd)
+	cacheCmd.AddCommand(cacheDirCmd)
+}
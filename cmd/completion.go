@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/acheevo/template-engine/internal/config"
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/i18n"
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion bash|zsh|fish|powershell",
+	Short: "Generate the shell completion script",
+	Long: `Generate the shell completion script for template-engine. Besides commands
+and flags, it completes template types and configured reference names
+(template-engine new <TAB>) and schema files.
+
+Load completions in the current shell:
+  bash:       source <(template-engine completion bash)
+  zsh:        source <(template-engine completion zsh)
+  fish:       template-engine completion fish | source
+  powershell: template-engine completion powershell | Out-String | Invoke-Expression
+
+To load them for every session, write the script to your shell's completion
+directory, e.g.:
+  template-engine completion bash > /etc/bash_completion.d/template-engine
+  template-engine completion zsh > "${fpath[1]}/_template-engine"
+  template-engine completion fish > ~/.config/fish/completions/template-engine.fish`,
+	Args:                  cobra.ExactArgs(1),
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell", "pwsh"},
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := os.Stdout
+		switch args[0] {
+		case "bash":
+			return rootCmd.GenBashCompletionV2(out, true)
+		case "zsh":
+			return rootCmd.GenZshCompletion(out)
+		case "fish":
+			return rootCmd.GenFishCompletion(out, true)
+		case "powershell", "pwsh":
+			return rootCmd.GenPowerShellCompletionWithDesc(out)
+		default:
+			return fmt.Errorf("unsupported shell %q, expected bash, zsh, fish or powershell", args[0])
+		}
+	},
+}
+
+func init() {
+	newCmd.ValidArgsFunction = completeNewArgs
+	configRemoveCmd.ValidArgsFunction = completeReferenceNames
+
+	for _, cmd := range []*cobra.Command{
+		generateCmd, applyCmd, validateCmd, inspectCmd, auditCmd, fixHashesCmd, testCmd, schemaDiffCmd,
+		registryPushCmd,
+	} {
+		cmd.ValidArgsFunction = completeSchemaFiles
+	}
+	generateWorkspaceCmd.ValidArgsFunction = func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return []string{"yaml", "yml"}, cobra.ShellCompDirectiveFilterFileExt
+	}
+}
+
+// completeNewArgs completes `new <type> <project-name> <github-repo> [output-dir]`
+func completeNewArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return completeTemplateTypes(cmd, args, toComplete)
+	case 3:
+		return nil, cobra.ShellCompDirectiveFilterDirs
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeTemplateTypes completes the registered template types and configured reference
+// names, described by their reference project or deprecation
+func completeTemplateTypes(
+	cmd *cobra.Command, args []string, toComplete string,
+) ([]string, cobra.ShellCompDirective) {
+	descriptions := map[string]string{}
+	for _, name := range core.ListTemplates() {
+		descriptions[name] = "template type"
+		if deprecation, deprecated := core.TemplateDeprecation(name); deprecated {
+			descriptions[name] = deprecationNote(deprecation.ReplacedBy)
+		}
+	}
+	if cfg, err := config.LoadConfig(); err == nil {
+		for name, reference := range cfg.References {
+			if _, registered := descriptions[name]; !registered || reference.Description != "" {
+				descriptions[name] = reference.Description
+			}
+		}
+	}
+
+	return describedCompletions(descriptions, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeReferenceNames completes the configured reference names
+func completeReferenceNames(
+	cmd *cobra.Command, args []string, toComplete string,
+) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	descriptions := make(map[string]string, len(cfg.References))
+	for name, reference := range cfg.References {
+		descriptions[name] = reference.Description
+	}
+	return describedCompletions(descriptions, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSetDefaultArgs completes the template type and well-known variables of config set-default
+func completeSetDefaultArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return completeTemplateTypes(cmd, args, toComplete)
+	case 1:
+		return []string{"Author", "Description", config.GitHubOwnerVariable}, cobra.ShellCompDirectiveNoFileComp
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeProfiles completes the names of the saved config profiles
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	profiles, err := config.ListProfiles()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return profiles, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeLanguages completes the languages of --lang
+func completeLanguages(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return i18n.Supported(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSchemaFiles completes .json schema files
+func completeSchemaFiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return []string{"json"}, cobra.ShellCompDirectiveFilterFileExt
+}
+
+// fixedCompletions completes a flag with a fixed set of values
+func fixedCompletions(values ...string) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// describedCompletions returns the sorted names starting with prefix as "name\tdescription"
+func describedCompletions(descriptions map[string]string, prefix string) []string {
+	completions := []string{}
+	for name, description := range descriptions {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if description != "" {
+			name += "\t" + description
+		}
+		completions = append(completions, name)
+	}
+	sort.Strings(completions)
+	return completions
+}
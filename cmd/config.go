@@ -1,10 +1,16 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"sort"
+	"strings"
 
 	"github.com/acheevo/template-engine/internal/config"
+	"github.com/acheevo/template-engine/internal/gitcache"
+	"github.com/acheevo/template-engine/internal/registry"
+	"github.com/charmbracelet/huh"
 	"github.com/spf13/cobra"
 )
 
@@ -16,10 +22,24 @@ var configCmd = &cobra.Command{
 Reference projects are existing projects that serve as templates for generating
 new projects. You can add, list, or remove reference project configurations.
 
+Teams share reference configurations with export and import; import replaces
+the configuration unless --merge is given, which adds the imported reference
+projects and replaces those of the same type. With --profile, every command
+uses a named profile instead of the default configuration, e.g. to switch
+between work and personal reference sets.
+
 Examples:
   template-engine config list
   template-engine config add my-template /path/to/template "My custom template"
-  template-engine config remove my-template`,
+  template-engine config remove my-template
+  template-engine config set-registry http://templates.internal:8080
+  template-engine config set-default go-api Author "Platform Team"
+  template-engine config export > team.json
+  template-engine config import team.json --merge
+  template-engine config add --profile work api ../work/api-template "Work API template"
+  template-engine config profiles
+  template-engine config doctor
+  template-engine config discover ~/code`,
 }
 
 var configListCmd = &cobra.Command{
@@ -30,10 +50,22 @@ var configListCmd = &cobra.Command{
 	},
 }
 
+var configAddRef string
+
 var configAddCmd = &cobra.Command{
 	Use:   "add [template-type] [path] [description]",
 	Short: "Add a new reference project",
-	Args:  cobra.ExactArgs(3),
+	Long: `Add a reference project for a template type, replacing any existing one.
+
+The path can also be the URL of a remote git repository, optionally pinned to
+a tag, branch or commit with --ref. The new command then clones it into the
+user cache directory and updates the checkout on every run, so no local
+sibling directory is needed.
+
+Examples:
+  template-engine config add go-api ../api-template "Go API"
+  template-engine config add go-api https://github.com/acheevo/api-template.git "Go API" --ref v1.2.0`,
+	Args: cobra.ExactArgs(3),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runConfigAdd(args[0], args[1], args[2])
 	},
@@ -48,10 +80,142 @@ var configRemoveCmd = &cobra.Command{
 	},
 }
 
+var configSetRegistryCmd = &cobra.Command{
+	Use:   "set-registry [url]",
+	Short: "Set the shared template registry used by the registry commands",
+	Long: `Set the URL of the template registry used by the registry commands.
+Run without a URL to clear it.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		url := ""
+		if len(args) == 1 {
+			url = args[0]
+		}
+		return runConfigSetRegistry(url)
+	},
+}
+
+var configSetDefaultCmd = &cobra.Command{
+	Use:   "set-default [template-type] [variable] [value]",
+	Short: "Set the default value of a variable for a template type",
+	Long: `Set the value new and generate use for a variable of a template type when
+none is given, e.g. the Author or Description. GitHubOwner sets the owner of
+GitHub repos given without one, and other names set custom variables. Run
+without a value to remove the default.
+
+Examples:
+  template-engine config set-default go-api Author "Platform Team"
+  template-engine config set-default go-api GitHubOwner acme
+  template-engine config set-default go-api Author`,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		value := ""
+		if len(args) == 3 {
+			value = args[2]
+		}
+		return runConfigSetDefault(args[0], args[1], value)
+	},
+}
+
+var configDoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that the configured reference projects are usable",
+	Long: `Check every configured reference project before new needs it: the path must
+be an existing directory with the files of its template type (such as go.mod
+and cmd/api for go-api, or package.json and vite.config.* for frontend).
+References that are not git repositories are reported as warnings.
+
+Errors make the command fail; warnings are reported but do not.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfigDoctor()
+	},
+}
+
+var configDiscoverYes bool
+
+var configDiscoverCmd = &cobra.Command{
+	Use:   "discover [dir]",
+	Short: "Find reference projects in a workspace directory",
+	Long: `Scan a workspace directory (the current one by default) and its
+subdirectories for projects that look like reference projects, recognized by
+the files of each template type (go.mod and cmd/api for go-api, package.json
+and vite.config.* for frontend), and offer to add them to the configuration.
+
+With --yes the discovered projects are added without asking, except for
+template types whose configured reference project exists. Without a terminal
+and --yes they are only listed.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := "."
+		if len(args) == 1 {
+			dir = args[0]
+		}
+		return runConfigDiscover(cmd.Context(), dir, configDiscoverYes)
+	},
+}
+
+var configImportMerge bool
+
+var configExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Print the reference configuration as JSON",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfigExport()
+	},
+}
+
+var configImportCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Import a reference configuration written by config export",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfigImport(args[0], configImportMerge)
+	},
+}
+
+var configProfilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "List the saved config profiles",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfigProfiles()
+	},
+}
+
 func init() {
+	configAddCmd.Flags().StringVar(&configAddRef, "ref", "",
+		"Tag, branch or commit to check out when the path is a git URL")
+	configImportCmd.Flags().BoolVar(&configImportMerge, "merge", false,
+		"Add the imported reference projects to the configuration instead of replacing it")
+	configImportCmd.ValidArgsFunction = completeSchemaFiles
+	configSetDefaultCmd.ValidArgsFunction = completeSetDefaultArgs
+	configDiscoverCmd.Flags().BoolVarP(&configDiscoverYes, "yes", "y", false,
+		"Add the discovered reference projects without asking")
+	configDiscoverCmd.ValidArgsFunction = func(
+		cmd *cobra.Command, args []string, toComplete string,
+	) ([]string, cobra.ShellCompDirective) {
+		return nil, cobra.ShellCompDirectiveFilterDirs
+	}
+
 	configCmd.AddCommand(configListCmd)
 	configCmd.AddCommand(configAddCmd)
 	configCmd.AddCommand(configRemoveCmd)
+	configCmd.AddCommand(configSetRegistryCmd)
+	configCmd.AddCommand(configSetDefaultCmd)
+	configCmd.AddCommand(configExportCmd)
+	configCmd.AddCommand(configImportCmd)
+	configCmd.AddCommand(configProfilesCmd)
+	configCmd.AddCommand(configDoctorCmd)
+	configCmd.AddCommand(configDiscoverCmd)
+}
+
+// configEntry is the JSON representation of a configured reference project
+type configEntry struct {
+	Type string `json:"type"`
+	config.ReferenceProject
+	Defaults map[string]string `json:"defaults,omitempty"`
 }
 
 func runConfigList() error {
@@ -60,6 +224,32 @@ func runConfigList() error {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
+	// Sort template types for consistent output
+	var types []string
+	for templateType := range cfg.References {
+		types = append(types, templateType)
+	}
+	sort.Strings(types)
+
+	if jsonOutput {
+		entries := make([]configEntry, 0, len(types))
+		for _, templateType := range types {
+			entries = append(entries, configEntry{
+				Type:             templateType,
+				ReferenceProject: cfg.References[templateType],
+				Defaults:         cfg.Defaults[templateType],
+			})
+		}
+		return printJSON(entries)
+	}
+
+	if name := config.Profile(); name != "" {
+		fmt.Printf("Profile: %s\n\n", name)
+	}
+	if cfg.Registry != "" {
+		fmt.Printf("Registry: %s\n\n", cfg.Registry)
+	}
+
 	if len(cfg.References) == 0 {
 		fmt.Println("No reference projects configured")
 		return nil
@@ -68,21 +258,24 @@ func runConfigList() error {
 	fmt.Println("Configured reference projects:")
 	fmt.Println()
 
-	// Sort template types for consistent output
-	var types []string
-	for templateType := range cfg.References {
-		types = append(types, templateType)
-	}
-	sort.Strings(types)
-
 	for _, templateType := range types {
 		ref := cfg.References[templateType]
-		fmt.Printf("• %s\n", templateType)
-		fmt.Printf("  Path: %s\n", ref.Path)
+		printItem("%s", templateType)
+		if ref.GitURL != "" {
+			fmt.Printf("  Git: %s\n", ref.GitURL)
+			if ref.Ref != "" {
+				fmt.Printf("  Ref: %s\n", ref.Ref)
+			}
+		} else {
+			fmt.Printf("  Path: %s\n", ref.Path)
+		}
 		fmt.Printf("  Description: %s\n", ref.Description)
 		if ref.Version != "" {
 			fmt.Printf("  Version: %s\n", ref.Version)
 		}
+		if defaults := cfg.Defaults[templateType]; len(defaults) > 0 {
+			fmt.Printf("  Defaults: %s\n", formatDefaults(defaults))
+		}
 		fmt.Println()
 	}
 
@@ -95,13 +288,20 @@ func runConfigAdd(templateType, path, description string) error {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
-	cfg.AddReference(templateType, path, description)
+	switch {
+	case gitcache.IsURL(path):
+		cfg.AddGitReference(templateType, path, configAddRef, description)
+	case configAddRef != "":
+		return fmt.Errorf("--ref requires a git URL instead of the path %s", path)
+	default:
+		cfg.AddReference(templateType, path, description)
+	}
 
 	if err := config.SaveConfig(cfg); err != nil {
 		return fmt.Errorf("failed to save configuration: %w", err)
 	}
 
-	fmt.Printf("Added reference project '%s' at %s\n", templateType, path)
+	logger.Info("Added reference project", "type", templateType, "path", path, "ref", configAddRef)
 	return nil
 }
 
@@ -121,6 +321,244 @@ func runConfigRemove(templateType string) error {
 		return fmt.Errorf("failed to save configuration: %w", err)
 	}
 
-	fmt.Printf("Removed reference project '%s'\n", templateType)
+	logger.Info("Removed reference project", "type", templateType)
 	return nil
 }
+
+func runConfigSetRegistry(url string) error {
+	if url != "" {
+		if _, err := registry.NewClient(url, nil); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	cfg.Registry = url
+
+	if err := config.SaveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+
+	if url == "" {
+		logger.Info("Cleared registry")
+	} else {
+		logger.Info("Set registry", "url", url)
+	}
+	return nil
+}
+
+func runConfigSetDefault(templateType, name, value string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if err := cfg.SetDefault(templateType, name, value); err != nil {
+		return err
+	}
+
+	if err := config.SaveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+
+	if value == "" {
+		logger.Info("Removed variable default", "type", templateType, "variable", name)
+	} else {
+		logger.Info("Set variable default", "type", templateType, "variable", name, "value", value)
+	}
+	return nil
+}
+
+// formatDefaults lists variable defaults as sorted Name=value pairs
+func formatDefaults(defaults map[string]string) string {
+	pairs := make([]string, 0, len(defaults))
+	for name, value := range defaults {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", name, value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
+
+func runConfigExport() error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return printJSON(cfg)
+}
+
+func runConfigImport(file string, merge bool) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read configuration: %w", err)
+	}
+
+	imported, err := config.ParseConfig(data)
+	if err != nil {
+		return err
+	}
+
+	cfg := imported
+	var added, replaced []string
+	if merge {
+		if cfg, err = config.LoadConfig(); err != nil {
+			return fmt.Errorf("failed to load configuration: %w", err)
+		}
+		added, replaced = cfg.Merge(imported)
+	}
+
+	if err := config.SaveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+
+	if merge {
+		logger.Info("Merged reference projects", "file", file,
+			"added", strings.Join(added, ","), "replaced", strings.Join(replaced, ","))
+	} else {
+		logger.Info("Imported reference projects", "file", file, "references", len(cfg.References))
+	}
+	return nil
+}
+
+func runConfigProfiles() error {
+	profiles, err := config.ListProfiles()
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		return printJSON(profiles)
+	}
+
+	if len(profiles) == 0 {
+		fmt.Println("No config profiles saved")
+		return nil
+	}
+	for _, name := range profiles {
+		fmt.Println(name)
+	}
+	return nil
+}
+
+func runConfigDoctor() error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	report := cfg.CheckReferences()
+
+	if jsonOutput {
+		if err := printJSON(report); err != nil {
+			return err
+		}
+	} else {
+		printReport("Checking reference projects", report)
+	}
+
+	if report.HasErrors() {
+		return fmt.Errorf("%d reference project problem(s) found", len(report.Errors()))
+	}
+	return nil
+}
+
+func runConfigDiscover(ctx context.Context, dir string, yes bool) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	discovered, err := cfg.Discover(dir)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput && !yes {
+		return printJSON(discovered)
+	}
+	if len(discovered) == 0 {
+		logger.Info("No reference projects found", "dir", dir)
+		return nil
+	}
+
+	ask := !yes && !jsonOutput && stdinIsTerminal()
+	added := 0
+	for i := range discovered {
+		reference := &discovered[i]
+		// Earlier additions count, so a template type found twice is only added once
+		if _, exists := cfg.References[reference.Type]; exists {
+			reference.Existing, _ = cfg.GetReferencePath(reference.Type)
+		}
+
+		switch {
+		case reference.Configured():
+			logger.Info("Already configured", "type", reference.Type, "path", reference.Path)
+			continue
+		case yes && !reference.Available():
+			logger.Info("Skipping, template type already has a reference project",
+				"type", reference.Type, "path", reference.Path, "existing", reference.Existing)
+			continue
+		case !yes && !ask:
+			logger.Info("Found reference project", "type", reference.Type, "path", reference.Path)
+			continue
+		}
+
+		if ask {
+			add, err := confirmDiscovered(ctx, *reference)
+			if err != nil {
+				return err
+			}
+			if !add {
+				continue
+			}
+		}
+
+		cfg.AddReference(reference.Type, reference.Path, discoveredDescription(reference.Type))
+		reference.Existing = reference.Path
+		added++
+		logger.Info("Added reference project", "type", reference.Type, "path", reference.Path)
+	}
+
+	if added > 0 {
+		if err := config.SaveConfig(cfg); err != nil {
+			return fmt.Errorf("failed to save configuration: %w", err)
+		}
+	} else if !yes && !ask {
+		logger.Info("Run with --yes to add them")
+	}
+
+	if jsonOutput {
+		return printJSON(discovered)
+	}
+	return nil
+}
+
+// confirmDiscovered asks whether to add a discovered reference project
+func confirmDiscovered(ctx context.Context, reference config.DiscoveredReference) (bool, error) {
+	title := fmt.Sprintf("Add %s as the %s reference project?", reference.Path, reference.Type)
+	description := ""
+	if reference.Existing != "" {
+		description = "This replaces " + reference.Existing
+	}
+
+	add := reference.Available()
+	confirm := huh.NewConfirm().Title(title).Description(description).Value(&add)
+	if err := huh.NewForm(huh.NewGroup(confirm)).WithAccessible(accessibleForms()).
+		RunWithContext(ctx); err != nil {
+		return false, interactiveError(err)
+	}
+	return add, nil
+}
+
+// discoveredDescription returns the description of a discovered reference project, the default
+// one for the built-in template types
+func discoveredDescription(templateType string) string {
+	if ref, exists := config.DefaultReferenceConfig().References[templateType]; exists {
+		return ref.Description
+	}
+	return templateType + " reference project"
+}
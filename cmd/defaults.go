@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/acheevo/template-engine/internal/config"
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+// variableDefaults returns the variable defaults configured for templateType (see config
+// set-default), none when the configuration cannot be loaded
+func variableDefaults(templateType string) map[string]string {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return map[string]string{}
+	}
+	return cfg.VariableDefaults(templateType)
+}
+
+// applyVariableDefaults sets the variables that have no value yet from defaults. A GitHubRepo
+// given without owner gets the GitHubOwner default as its owner.
+func applyVariableDefaults(variables, defaults map[string]string) {
+	for name, value := range defaults {
+		if name != config.GitHubOwnerVariable && variables[name] == "" {
+			variables[name] = value
+		}
+	}
+	if repo := variables["GitHubRepo"]; repo != "" {
+		variables["GitHubRepo"] = withDefaultOwner(repo, defaults)
+	}
+}
+
+// withDefaultOwner prefixes a GitHub repo given without owner with the GitHubOwner default
+func withDefaultOwner(repo string, defaults map[string]string) string {
+	owner := defaults[config.GitHubOwnerVariable]
+	if owner == "" || strings.Contains(repo, "/") {
+		return repo
+	}
+	return owner + "/" + repo
+}
+
+// customVariables returns the template variables that are not built into every template
+func customVariables(variables map[string]string) map[string]string {
+	custom := map[string]string{}
+	for name, value := range variables {
+		if !core.IsBuiltinVariable(name) && name != config.GitHubOwnerVariable {
+			custom[name] = value
+		}
+	}
+	return custom
+}
+
+// schemaDefaults returns the variable defaults for the type of a schema file, none when it cannot
+// be read. Custom defaults are left out unless the schema declares the variable.
+func schemaDefaults(path string) map[string]string {
+	schema, err := core.LoadSchemaFile(path)
+	if err != nil {
+		return map[string]string{}
+	}
+
+	defaults := variableDefaults(schema.Type)
+	for name := range customVariables(defaults) {
+		if _, declared := schema.Variables[name]; !declared {
+			delete(defaults, name)
+		}
+	}
+	return defaults
+}
@@ -1,13 +1,26 @@
 package cmd
 
 import (
+	"fmt"
+
+	"github.com/acheevo/template-engine/internal/core"
 	"github.com/acheevo/template-engine/internal/extract"
 	"github.com/spf13/cobra"
 )
 
 var (
-	extractOutputFile string
-	extractType       string
+	extractOutputFile     string
+	extractType           string
+	extractStrictMappings bool
+	extractDedupe         bool
+	extractCodec          string
+	extractNoCompress     bool
+	extractSubdir         string
+	extractExplainSkips   bool
+	extractMaxFileSize    string
+	extractLargeFiles     string
+	extractDetectTokens   []string
+	extractNoGitignore    bool
 )
 
 var extractCmd = &cobra.Command{
@@ -16,15 +29,93 @@ var extractCmd = &cobra.Command{
 	Long: `Extract a template schema from an existing project directory.
 	
 This command analyzes a source project and creates a reusable template
-that can be used to generate similar projects.
+that can be used to generate similar projects. Mappings whose find string
+no longer occurs in the reference project are reported as warnings.
+
+The source can also be a .zip archive of the project, such as a GitHub
+source download.
+
+With --subdir, only that directory of the source becomes the template, for
+reference repositories holding several templatable units. File paths and
+mappings are relative to the subdirectory.
+
+Hidden files, build output and dependencies are left out of the schema, as
+are the paths the .gitignore files of the project exclude (the root one and
+those of nested directories) unless --no-gitignore is given. With
+--explain-skips every skipped file is reported with the reason, e.g.
+"matched skip dir node_modules" or "hidden file". Binary files such as images
+are embedded base64 encoded and never templated.
+
+With --max-file-size, files above the size (lockfiles, fixtures, media) are
+handled as --large-files says: warn embeds them with a warning (the default),
+skip leaves them out, and externalize stores them in sidecar blobs next to the
+schema (template.blobs/ for template.json) instead of inlining them. Keep the
+blob directory with the schema; 'registry push' inlines them again.
+
+With --detect-tokens, the given literal strings of the reference project,
+such as its repository and display name, are mapped wherever they occur to
+the variable their shape calls for: acheevo/fullstack-template becomes
+{{.GitHubRepo}}, "Fullstack Template" {{.ProjectName}}, fullstack-template
+{{.ProjectNameKebab}}, fullstack_template {{.ProjectNameSnake}} and
+FullstackTemplate {{.ProjectNamePascal}}. Files containing {{ }} of their own
+are only mapped when the template type templates them.
+
+The generic type templates projects in any language without a built-in file
+list: every file containing a marker token (__PROJECT_NAME__,
+__PROJECT_NAME_KEBAB__, __GITHUB_REPO__, __AUTHOR__, ...) is templated. A
+.templatetokens file in the project adds tokens, one TOKEN=REPLACEMENT per
+line (e.g. __SERVICE__={{.ProjectNameKebab}}), and paths listed in a
+.templateignore file (.gitignore syntax) are left out as well. Its ! patterns
+cannot bring back what a .gitignore excludes, except with --no-gitignore.
 
 Examples:
   template-engine extract ../my-frontend --type frontend -o frontend-template.json
-  template-engine extract ../my-api --type go-api -o api-template.json`,
+  template-engine extract ../my-api --type go-api -o api-template.json
+  template-engine extract my-api-main.zip --type go-api -o api-template.json
+  template-engine extract ../platform --subdir services/auth --type go-api -o auth-template.json
+  template-engine extract ../my-api --type go-api --explain-skips
+  template-engine extract ../my-service --type generic -o service-template.json
+  template-engine extract ../my-app --type fullstack \
+    --detect-tokens "acheevo/fullstack-template,Fullstack Template,fullstack-template"
+  template-engine extract ../my-frontend --type frontend --max-file-size 1MB --large-files externalize`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		sourceDir := args[0]
-		return extract.RunWithParams(sourceDir, extractOutputFile, extractType)
+		codec, err := extractCodecFromFlags()
+		if err != nil {
+			return err
+		}
+		var maxFileSize int64
+		if extractMaxFileSize != "" {
+			if maxFileSize, err = extract.ParseSize(extractMaxFileSize); err != nil {
+				return fmt.Errorf("--max-file-size: %w", err)
+			}
+		}
+		largeFiles, err := core.ParseLargeFilePolicy(extractLargeFiles)
+		if err != nil {
+			return err
+		}
+		result, err := extract.RunWithParams(cmd.Context(), logger, extract.Params{
+			SourceDir:      sourceDir,
+			OutputFile:     extractOutputFile,
+			TemplateType:   extractType,
+			StrictMappings: extractStrictMappings,
+			Dedupe:         extractDedupe,
+			Codec:          codec,
+			Subdir:         extractSubdir,
+			ExplainSkips:   extractExplainSkips,
+			MaxFileSize:    maxFileSize,
+			LargeFiles:     largeFiles,
+			DetectTokens:   extractDetectTokens,
+			NoGitignore:    extractNoGitignore,
+		})
+		if err != nil {
+			return err
+		}
+		if jsonOutput {
+			return printJSON(result)
+		}
+		return nil
 	},
 }
 
@@ -32,5 +123,39 @@ func init() {
 	extractCmd.Flags().StringVarP(&extractOutputFile, "output", "o", "template.json",
 		"Output file for the extracted template")
 	extractCmd.Flags().StringVar(&extractType, "type", "", "Template type (required)")
+	extractCmd.Flags().BoolVar(&extractStrictMappings, "strict-mappings", false,
+		"Fail when a mapping no longer matches the reference project")
+	extractCmd.Flags().BoolVar(&extractDedupe, "dedupe", false,
+		"Store identical file contents once, keyed by content hash")
+	extractCmd.Flags().StringVar(&extractCodec, "codec", string(core.CodecGzip),
+		"Compression codec for file contents (gzip, zstd, none)")
+	extractCmd.Flags().BoolVar(&extractNoCompress, "no-compress", false,
+		"Store file contents uncompressed (same as --codec none)")
+	extractCmd.Flags().StringVar(&extractSubdir, "subdir", "",
+		"Extract only this directory of the source (e.g. services/auth)")
+	extractCmd.Flags().BoolVar(&extractExplainSkips, "explain-skips", false,
+		"Report every file left out of the schema and why")
+	extractCmd.Flags().StringVar(&extractMaxFileSize, "max-file-size", "",
+		"Size above which files are handled by --large-files, e.g. 512KB or 10MB (no limit by default)")
+	extractCmd.Flags().StringVar(&extractLargeFiles, "large-files", string(core.LargeFileWarn),
+		"What to do with files above --max-file-size: warn, skip or externalize")
+	extractCmd.Flags().StringSliceVar(&extractDetectTokens, "detect-tokens", nil,
+		"Map these literal strings of the project (comma-separated) to variables wherever they occur")
+	extractCmd.Flags().BoolVar(&extractNoGitignore, "no-gitignore", false,
+		"Extract the files the project's .gitignore files exclude")
 	_ = extractCmd.MarkFlagRequired("type") // Error is not critical for flag registration
+	_ = extractCmd.RegisterFlagCompletionFunc("type", completeTemplateTypes)
+	_ = extractCmd.RegisterFlagCompletionFunc("codec", fixedCompletions("gzip", "zstd", "none"))
+	_ = extractCmd.RegisterFlagCompletionFunc("large-files", fixedCompletions("warn", "skip", "externalize"))
+}
+
+// extractCodecFromFlags resolves the --codec and --no-compress flags
+func extractCodecFromFlags() (core.Codec, error) {
+	if extractNoCompress {
+		if extractCodec != string(core.CodecGzip) && extractCodec != string(core.CodecNone) {
+			return "", fmt.Errorf("--no-compress cannot be combined with --codec %s", extractCodec)
+		}
+		return core.CodecNone, nil
+	}
+	return core.ParseCodec(extractCodec)
 }
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+)
+
+// selectFeatures asks which optional features of the schema at path to generate, preselecting
+// those enabled by default or by enable and not disabled. It returns the chosen features and
+// the others.
+func selectFeatures(ctx context.Context, path string, enable, disable []string) ([]string, []string, error) {
+	if !stdinIsTerminal() {
+		return nil, nil, fmt.Errorf("--select-features needs a terminal, pass --enable and --disable instead")
+	}
+	schema, err := core.LoadSchemaFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(schema.Features) == 0 {
+		logger.Info("The schema has no optional features")
+		return enable, disable, nil
+	}
+
+	options := make([]huh.Option[string], 0, len(schema.Features))
+	for _, feature := range schema.Features {
+		label := feature.Name
+		if feature.Description != "" {
+			label += " - " + feature.Description
+		}
+		selected := slices.Contains(enable, feature.Name) || feature.Default && !slices.Contains(disable, feature.Name)
+		options = append(options, huh.NewOption(label, feature.Name).Selected(selected))
+	}
+
+	var chosen []string
+	form := huh.NewForm(huh.NewGroup(
+		huh.NewMultiSelect[string]().
+			Title("Optional features").
+			Options(options...).
+			Value(&chosen),
+	)).WithAccessible(accessibleForms())
+	if err := form.RunWithContext(ctx); err != nil {
+		return nil, nil, interactiveError(err)
+	}
+
+	var others []string
+	for _, feature := range schema.Features {
+		if !slices.Contains(chosen, feature.Name) {
+			others = append(others, feature.Name)
+		}
+	}
+	return chosen, others, nil
+}
+
+// completeFeatures completes the optional features of the schema given as first argument
+func completeFeatures(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	schema, err := core.LoadSchemaFile(args[0])
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	descriptions := make(map[string]string, len(schema.Features))
+	for _, feature := range schema.Features {
+		descriptions[feature.Name] = feature.Description
+	}
+	return describedCompletions(descriptions, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
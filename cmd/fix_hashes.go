@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var fixHashesOutput string
+
+// fixHashesResult is printed with --json
+type fixHashesResult struct {
+	Output string   `json:"output"`
+	Fixed  []string `json:"fixed"`
+	Hash   string   `json:"hash"`
+}
+
+var fixHashesCmd = &cobra.Command{
+	Use:   "fix-hashes <schema.json>",
+	Short: "Recompute file sizes and hashes after editing a schema",
+	Long: `Recompute the size and hash of every file in a template schema, and the
+schema hash, so a schema that was edited by hand passes verification again.
+
+The schema is rewritten in place unless --output is given.
+
+Examples:
+  template-engine fix-hashes api-template.json
+  template-engine fix-hashes api-template.json -o api-template-fixed.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runFixHashes(args[0])
+	},
+}
+
+func init() {
+	fixHashesCmd.Flags().StringVarP(&fixHashesOutput, "output", "o", "",
+		"Output file for the repaired schema (defaults to overwriting the input)")
+}
+
+func runFixHashes(schemaFile string) error {
+	schema, err := core.LoadSchemaFile(schemaFile)
+	if err != nil {
+		return err
+	}
+
+	fixed, err := core.FixHashes(schema)
+	if err != nil {
+		return err
+	}
+
+	output := fixHashesOutput
+	if output == "" {
+		output = schemaFile
+	}
+	if err := core.SaveSchemaFile(schema, output); err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		return printJSON(fixHashesResult{Output: output, Fixed: fixed, Hash: schema.Hash})
+	}
+
+	for _, path := range fixed {
+		logger.Info("Updated hash", "file", path)
+	}
+	logger.Info(fmt.Sprintf("Fixed %d file hash(es)", len(fixed)), "output", output)
+	return nil
+}
@@ -1,7 +1,15 @@
 package cmd
 
 import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/acheevo/template-engine/internal/config"
+	"github.com/acheevo/template-engine/internal/core"
 	"github.com/acheevo/template-engine/internal/generate"
+	"github.com/acheevo/template-engine/internal/policy"
 	"github.com/spf13/cobra"
 )
 
@@ -9,6 +17,31 @@ var (
 	generateProjectName string
 	generateGithubRepo  string
 	generateOutputDir   string
+	generateEnvFile     string
+	generateEnv         []string
+	generateEnvPrompt   bool
+	generateEnvExamples bool
+	generateNoVerify    bool
+	generateFormat      string
+	generateVarsStdin   bool
+	generateAuthor      string
+	generateDescription string
+	generateOnly        []string
+	generatePackages    []string
+	generateHooks       bool
+	generateAllowHooks  bool
+	generateVars        []string
+	generateOverwrite   string
+	generateMaterialize bool
+	generateLicense     string
+	generateGoVersion   string
+	generateGoModTidy   bool
+	generateWarnMissing bool
+	generateEnable      []string
+	generateDisable     []string
+	generateSelect      bool
+	generateCheck       bool
+	generatePortable    bool
 )
 
 var generateCmd = &cobra.Command{
@@ -19,21 +52,388 @@ var generateCmd = &cobra.Command{
 This command takes a template schema (created with 'extract') and generates
 a new project with the specified parameters.
 
+With --env-file, an env file is written next to every .env.example of the
+reference project, populated from the documented examples. Values can be set
+with --env KEY=VALUE or entered interactively with --env-prompt. Example
+values of secrets (*_SECRET, *_PASSWORD, *_KEY) are never written unless
+--allow-example-secrets is set.
+
+The env variables of multi-component templates (fullstack) belong to a
+component, api or frontend, each written to its own env file. --env KEY=VALUE
+sets KEY in every component, --env frontend.KEY=VALUE only in the frontend.
+
+With --output-format tar.gz or zip, the project is streamed into an archive
+instead of a directory. --output-dir then names the archive file (or the
+directory to put it in), and "-" writes the archive to stdout.
+
+With --overwrite merge, the project is regenerated into an existing output
+directory: files whose content is unchanged are not rewritten, and the files
+created, updated and left unchanged are counted, so running generate again
+is a no-op, e.g. in reconcile loops. The default, fail, refuses an existing
+output directory.
+
+Symlinks recorded in the schema (e.g. shared config linked into subpackages)
+are recreated as symlinks, also in archives. --materialize-symlinks writes a
+copy of the files they point to instead, e.g. on Windows.
+
+With --license MIT, Apache-2.0 or proprietary, the project gets a LICENSE
+file for that license in place of the reference project's, with the current
+year and the author as copyright holder. When the schema defines a copyright
+header, it is also injected at the top of every source file whose comment
+syntax is known (Go, JavaScript, TypeScript, Python, shell, CSS, ...).
+
+Go templates (go-api, go-cli, go-library, fullstack) declare their go.mod
+files in the schema: the module directive is rewritten to the module of the
+new project and the imports of the reference module in every Go file follow,
+whatever the reference project named its module. Schemas may also rename the
+root package (go-library names it after the project), in its package clauses
+and in the Go files using it. --go-version raises the go directive to a newer
+Go version, and --go-mod-tidy runs go mod tidy in every module once the
+project is written, with its output in the --json result.
+
+Mappings marked required in the schema fail generation when the text they
+replace is missing from their file, which means the template is stale.
+--warn-missing-mappings logs a warning and lists them in the --json result
+instead.
+
+With --only, just the schema files matching the given globs are generated,
+e.g. only the CI workflows or the Docker setup. The output directory may then
+be an existing project; files that already exist there are never overwritten.
+
+Monorepo templates declare the packages of their workspace. With --package,
+only the named packages (by package name or directory, e.g. apps/web) are
+generated; the other packages are left out and the dependencies on them are
+removed from every package.json.
+
+Schemas may declare optional features, such as auth, postgres or ci, each
+owning files and hooks that are left out while the feature is disabled.
+--enable and --disable switch features on and off by name, the others keeping
+their schema default; --select-features picks them in a terminal multi-select.
+The features generated are listed in the --json result.
+
+The author defaults to the git user ("Name <email>" from git config) and the
+description to the schema default, or "A <project name> application".
+Custom variables declared by the schema are set with --var NAME=VALUE; those
+left unset take their schema default. List variables take comma-separated
+items (--var Services=auth,billing) and map variables comma-separated pairs
+(--var Ports=api=8080,web=3000); templates range over them, reaching the
+other variables through $ (e.g. {{range .Services}}{{.}}-{{$.ProjectName}}{{end}}).
+
+With --hooks, the schema's pre_generate hooks run in the new output directory
+before any file is written and its post_generate hooks once the project is
+complete (e.g. go mod tidy). Hooks are commands of the schema, so they are
+off by default; review them first. They receive the template variables as
+TE_VAR_<Name> environment variables and never run for archives. Hooks of
+schemas pulled from a registry are skipped unless --allow-hooks is also
+given, and a policy file (~/.config/template-engine/hooks.json) can restrict
+hooks to a list of binaries: {"allowed_binaries": ["go", "npm"]}. Under such
+a policy hooks may only set harmless variables such as CGO_ENABLED or
+NODE_ENV (not LD_PRELOAD, PATH or GOFLAGS) and only redirect to /dev/null.
+
+With --check, the schema's verify hooks (e.g. go build ./... or npm run
+typecheck) run once the project is written, even without --hooks, to tell
+whether the scaffold builds. Every check runs; their results are summarized
+and listed in the --json result, and the command fails if any check did.
+
+An organization policy (~/.config/template-engine/policy.json, or the file
+named by TEMPLATE_ENGINE_POLICY) rejects requests breaking its rules before
+anything is written, e.g. {"allowed_owners": ["acme"], "project_name_pattern":
+"^[a-z][a-z0-9-]+$", "required_variables": {"go-api": ["Team"]}}. A "rego"
+file is evaluated as well with the opa tool, denying with data.template_engine.deny.
+
+Variables can also come from the environment as TE_VAR_<Name> (e.g.
+TE_VAR_ProjectName) or from a JSON object piped to stdin with
+--vars-from-stdin. Flags take precedence over stdin, which takes precedence
+over the environment. Defaults stored for the schema's template type with
+'config set-default' apply last, including a GitHubOwner for repos given
+without owner.
+
 Examples:
   template-engine generate frontend-template.json --project-name "My App" --github-repo "user/my-app"
-  template-engine generate api-template.json --project-name "My API" --github-repo "user/my-api"`,
+  template-engine generate api-template.json --project-name "My API" --github-repo "user/my-api"
+  template-engine generate api-template.json --project-name "My API" --github-repo "user/my-api" \
+    --env-file .env --env DB_PASSWORD=secret
+  template-engine generate api-template.json --project-name "My API" --github-repo "user/my-api" \
+    --output-format zip --output-dir my-api.zip
+  template-engine generate api-template.json --project-name "My API" --github-repo "user/my-api" \
+    --only .github --only 'docker/**' --output-dir ./my-existing-api
+  template-engine generate api-template.json --project-name "My API" --github-repo "user/my-api" \
+    --enable auth,postgres --disable ci
+  template-engine generate api-template.json --project-name "My API" --github-repo "user/my-api" \
+    --var Team=payments --var Region=eu-west-1
+  template-engine generate api-template.json --project-name "My API" --github-repo "user/my-api" \
+    --license Apache-2.0
+  template-engine generate api-template.json --project-name "My API" --github-repo "user/my-api" \
+    --go-version 1.23 --go-mod-tidy
+  echo '{"ProjectName": "My API", "GitHubRepo": "user/my-api"}' | \
+    template-engine generate api-template.json --vars-from-stdin`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		templateFile := args[0]
-		return generate.RunWithParams(templateFile, generateOutputDir, generateProjectName, generateGithubRepo)
+		if generateOutputDir == "-" && jsonOutput {
+			return fmt.Errorf("--json cannot be used when writing the archive to stdout")
+		}
+		if generateOutputDir == "-" && generateFormat == "" {
+			return fmt.Errorf("--output-dir - requires --output-format")
+		}
+
+		if generateVarsStdin && generateEnvPrompt {
+			return fmt.Errorf("--vars-from-stdin cannot be used with --env-prompt, both read stdin")
+		}
+		if generateVarsStdin && generateSelect {
+			return fmt.Errorf("--vars-from-stdin cannot be used with --select-features, both read stdin")
+		}
+
+		variables, err := generateVariables()
+		if err != nil {
+			return err
+		}
+		applyVariableDefaults(variables, schemaDefaults(args[0]))
+		if variables["Author"] == "" {
+			variables["Author"] = generate.DefaultAuthor(cmd.Context())
+		}
+		projectName, githubRepo := variables["ProjectName"], variables["GitHubRepo"]
+		delete(variables, "ProjectName")
+		delete(variables, "GitHubRepo")
+		if projectName == "" {
+			return fmt.Errorf("--project-name is required (or TE_VAR_ProjectName, or ProjectName on stdin)")
+		}
+		if githubRepo == "" {
+			return fmt.Errorf("--github-repo is required (or TE_VAR_GitHubRepo, or GitHubRepo on stdin)")
+		}
+
+		env, err := generateEnvOptions()
+		if err != nil {
+			return err
+		}
+		hooks, err := hookOptions(generateHooks, generateAllowHooks)
+		if err != nil {
+			return err
+		}
+		policies, err := orgPolicies()
+		if err != nil {
+			return err
+		}
+		overwrite, err := generate.ParseOverwritePolicy(generateOverwrite)
+		if err != nil {
+			return err
+		}
+		license, err := generate.ParseLicense(generateLicense)
+		if err != nil {
+			return err
+		}
+		goVersion, err := generate.ParseGoVersion(generateGoVersion)
+		if err != nil {
+			return err
+		}
+		enable, disable := generateEnable, generateDisable
+		if generateSelect {
+			if enable, disable, err = selectFeatures(cmd.Context(), args[0], enable, disable); err != nil {
+				return err
+			}
+		}
+
+		result, err := generate.RunWithParams(cmd.Context(), logger, generate.Params{
+			TemplateFile:        args[0],
+			OutputDir:           generateOutputDir,
+			ProjectName:         projectName,
+			GitHubRepo:          githubRepo,
+			Variables:           variables,
+			Env:                 env,
+			Hooks:               hooks,
+			NoVerify:            generateNoVerify,
+			Only:                generateOnly,
+			Packages:            generatePackages,
+			OutputFormat:        generateFormat,
+			Overwrite:           overwrite,
+			MaterializeSymlinks: generateMaterialize,
+			License:             license,
+			GoMod:               generate.GoModOptions{GoVersion: goVersion, Tidy: generateGoModTidy},
+			WarnMissingMappings: generateWarnMissing,
+			Enable:              enable,
+			Disable:             disable,
+			Verify:              generateCheck,
+			Offline:             offline,
+			PortablePaths:       generatePortable,
+			Policies:            policies,
+		})
+		if err != nil {
+			return err
+		}
+		if jsonOutput {
+			if err := printJSON(result); err != nil {
+				return err
+			}
+		}
+		if result.Verification != nil && !result.Verification.Passed {
+			return fmt.Errorf("project generated in %s, but %d verify check(s) failed",
+				result.OutputDir, len(result.Verification.Failed()))
+		}
+		return nil
 	},
 }
 
 func init() {
 	generateCmd.Flags().StringVar(&generateProjectName, "project-name", "", "Name of the project (required)")
 	generateCmd.Flags().StringVar(&generateGithubRepo, "github-repo", "",
-		"GitHub repository (e.g., username/repo-name) (required)")
+		"GitHub repository: username/repo-name or its github.com URL (required)")
+	generateCmd.Flags().StringVar(&generateAuthor, "author", "", "Project author (defaults to the git user)")
+	generateCmd.Flags().StringVar(&generateDescription, "description", "", "Project description")
+	generateCmd.Flags().StringArrayVar(&generateVars, "var", nil,
+		"Set a custom variable declared by the schema (NAME=VALUE, repeatable)")
+	generateCmd.Flags().StringArrayVar(&generateOnly, "only", nil,
+		"Generate only files matching this glob, or below this directory (repeatable)")
+	generateCmd.Flags().StringArrayVar(&generatePackages, "package", nil,
+		"Generate only this package of a monorepo, by name or directory (repeatable)")
+	generateCmd.Flags().StringSliceVar(&generateEnable, "enable", nil,
+		"Enable an optional feature of the schema (repeatable or comma-separated)")
+	generateCmd.Flags().StringSliceVar(&generateDisable, "disable", nil,
+		"Disable an optional feature of the schema (repeatable or comma-separated)")
+	generateCmd.Flags().BoolVar(&generateSelect, "select-features", false,
+		"Choose the optional features of the schema interactively")
+	generateCmd.Flags().BoolVar(&generateVarsStdin, "vars-from-stdin", false,
+		"Read variables from a JSON object on stdin, e.g. {\"ProjectName\": \"My App\"}")
 	generateCmd.Flags().StringVar(&generateOutputDir, "output-dir", "./", "Output directory for generated project")
-	_ = generateCmd.MarkFlagRequired("project-name")
-	_ = generateCmd.MarkFlagRequired("github-repo")
+	generateCmd.Flags().StringVar(&generateEnvFile, "env-file", "",
+		"Write an env file with this name (e.g. .env or .env.local) from the schema's env config")
+	generateCmd.Flags().StringArrayVar(&generateEnv, "env", nil,
+		"Set an env file value as KEY=VALUE or component.KEY=VALUE (repeatable, implies --env-file .env)")
+	generateCmd.Flags().BoolVar(&generateEnvPrompt, "env-prompt", false,
+		"Prompt for env file values (implies --env-file .env)")
+	generateCmd.Flags().BoolVar(&generateEnvExamples, "allow-example-secrets", false,
+		"Write the example values of secret env variables instead of refusing")
+	generateCmd.Flags().BoolVar(&generateNoVerify, "no-verify", false,
+		"Skip file hash verification (for schemas edited by hand, see fix-hashes)")
+	generateCmd.Flags().BoolVar(&generateHooks, "hooks", false, "Run the schema's pre_generate and post_generate hooks")
+	generateCmd.Flags().BoolVar(&generateCheck, "check", false,
+		"Run the schema's verify hooks once the project is written and report whether they pass")
+	generateCmd.Flags().BoolVar(&generatePortable, "portable-paths", false,
+		"Reject paths Windows cannot hold (reserved names, invalid characters, over 260 characters) on every OS")
+	generateCmd.Flags().BoolVar(&generateAllowHooks, "allow-hooks", false,
+		"Run the hooks of schemas pulled from a registry")
+	generateCmd.Flags().StringVar(&generateFormat, "output-format", "",
+		"Write the project as an archive instead of a directory: tar.gz or zip")
+	generateCmd.Flags().StringVar(&generateOverwrite, "overwrite", string(generate.OverwriteFail),
+		"What to do when the output directory exists: fail, or merge to rewrite only changed files")
+	generateCmd.Flags().BoolVar(&generateMaterialize, "materialize-symlinks", false,
+		"Write copies of the files symlinks point to instead of recreating the symlinks")
+	generateCmd.Flags().StringVar(&generateLicense, "license", "",
+		"License the project under: MIT, Apache-2.0 or proprietary (adds LICENSE and copyright headers)")
+	generateCmd.Flags().StringVar(&generateGoVersion, "go-version", "",
+		"Raise the go directive of the schema's go.mod files to this Go version (e.g. 1.23)")
+	generateCmd.Flags().BoolVar(&generateGoModTidy, "go-mod-tidy", false,
+		"Run go mod tidy in every Go module of the project once it is written")
+	generateCmd.Flags().BoolVar(&generateWarnMissing, "warn-missing-mappings", false,
+		"Warn instead of failing when a required mapping is not found in its file")
+	_ = generateCmd.RegisterFlagCompletionFunc("output-format", fixedCompletions("tar.gz", "zip"))
+	_ = generateCmd.RegisterFlagCompletionFunc("overwrite", fixedCompletions("fail", "merge"))
+	_ = generateCmd.RegisterFlagCompletionFunc("license", fixedCompletions("MIT", "Apache-2.0", "proprietary"))
+	_ = generateCmd.RegisterFlagCompletionFunc("enable", completeFeatures)
+	_ = generateCmd.RegisterFlagCompletionFunc("disable", completeFeatures)
+}
+
+// generateVariables merges the template variables from TE_VAR_* environment variables,
+// stdin (with --vars-from-stdin) and flags, later sources taking precedence
+func generateVariables() (map[string]string, error) {
+	variables := generate.VariablesFromEnviron(os.Environ())
+
+	if generateVarsStdin {
+		values, err := generate.ParseVariablesJSON(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range values {
+			variables[name] = value
+		}
+	}
+
+	if generateProjectName != "" {
+		variables["ProjectName"] = generateProjectName
+	}
+	if generateGithubRepo != "" {
+		variables["GitHubRepo"] = generateGithubRepo
+	}
+	if generateAuthor != "" {
+		variables["Author"] = generateAuthor
+	}
+	if generateDescription != "" {
+		variables["Description"] = generateDescription
+	}
+
+	custom, err := generate.ParseVariableAssignments(generateVars)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range custom {
+		variables[name] = value
+	}
+	return variables, nil
+}
+
+// hookOptions builds the hook options from the --hooks and --allow-hooks flags and the hook policy.
+// The policy and --allow-hooks also apply to the verify hooks of --check, which run without --hooks.
+func hookOptions(runHooks, allowHooks bool) (generate.HookOptions, error) {
+	policy, err := config.LoadHookPolicy()
+	if err != nil {
+		return generate.HookOptions{}, err
+	}
+	return generate.HookOptions{
+		Enabled:         runHooks,
+		Output:          os.Stderr,
+		AllowRemote:     allowHooks,
+		AllowedBinaries: policy.AllowedBinaries,
+	}, nil
+}
+
+// orgPolicies returns the organization policy generation requests must satisfy, if one is
+// configured
+func orgPolicies() ([]policy.Policy, error) {
+	rules, err := config.LoadOrgPolicy()
+	if err != nil || rules == nil {
+		return nil, err
+	}
+	return []policy.Policy{rules}, nil
+}
+
+// generateEnvOptions builds the env file options from the --env* flags
+func generateEnvOptions() (generate.EnvOptions, error) {
+	values, err := generate.ParseEnvAssignments(generateEnv)
+	if err != nil {
+		return generate.EnvOptions{}, err
+	}
+
+	opts := generate.EnvOptions{File: generateEnvFile, Values: values, AllowExampleSecrets: generateEnvExamples}
+	if opts.File == "" && (len(values) > 0 || generateEnvPrompt) {
+		opts.File = ".env"
+	}
+	if generateEnvPrompt {
+		opts.Prompt = newEnvPrompt(bufio.NewReader(os.Stdin))
+	}
+	return opts, nil
+}
+
+// newEnvPrompt asks for env values on stderr, keeping stdout free for results.
+// An empty answer keeps the example value, except for secrets which have no default.
+func newEnvPrompt(input *bufio.Reader) func(core.EnvVariable) (string, error) {
+	return func(variable core.EnvVariable) (string, error) {
+		if variable.Description != "" {
+			fmt.Fprintf(os.Stderr, "# %s\n", variable.Description)
+		}
+		if variable.Secret {
+			variable.Example = ""
+			fmt.Fprintf(os.Stderr, "%s (secret): ", variable.Key())
+		} else {
+			fmt.Fprintf(os.Stderr, "%s [%s]: ", variable.Key(), variable.Example)
+		}
+
+		answer, err := input.ReadString('\n')
+		if err != nil && answer == "" {
+			return "", fmt.Errorf("failed to read value for %s: %w", variable.Key(), err)
+		}
+
+		answer = strings.TrimRight(answer, "\r\n")
+		if answer == "" {
+			return variable.Example, nil
+		}
+		return answer, nil
+	}
 }
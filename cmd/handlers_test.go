@@ -1,11 +1,23 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
 	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/acheevo/template-engine/internal/config"
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/extract"
+	"github.com/acheevo/template-engine/internal/generate"
+	_ "github.com/acheevo/template-engine/internal/templates"
+	"github.com/spf13/cobra"
 )
 
 func setupTempConfig(t *testing.T) func() {
@@ -150,3 +162,209 @@ func TestGetReferenceProjectPath(t *testing.T) {
 		t.Error("Expected error for invalid template type")
 	}
 }
+
+func TestCompleteNewArgs(t *testing.T) {
+	cleanup := setupTempConfig(t)
+	defer cleanup()
+
+	if err := runConfigAdd("go-worker", "/test/worker", "Background worker"); err != nil {
+		t.Fatal(err)
+	}
+
+	completions, directive := completeNewArgs(newCmd, nil, "go-")
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("Expected no file completion, got directive %d", directive)
+	}
+	if len(completions) != 4 || !strings.HasPrefix(completions[0], "go-api\t") ||
+		!strings.HasPrefix(completions[1], "go-cli\t") || !strings.HasPrefix(completions[2], "go-library\t") ||
+		completions[3] != "go-worker\tBackground worker" {
+		t.Errorf("Unexpected completions %q", completions)
+	}
+
+	if _, directive := completeNewArgs(newCmd, []string{"go-api", "My API", "user/my-api"}, ""); directive !=
+		cobra.ShellCompDirectiveFilterDirs {
+		t.Errorf("Expected directory completion for the output dir, got directive %d", directive)
+	}
+}
+
+func TestTemplateOptions(t *testing.T) {
+	cfg := &config.ReferenceConfig{References: map[string]config.ReferenceProject{
+		"frontend": {Description: "React app"},
+		"go-api":   {},
+	}}
+	templateTypes := []string{"frontend", "go-api"}
+
+	options := templateOptions(cfg, templateTypes, "")
+	if len(options) != 2 || options[0].Key != "frontend - React app" || options[1].Key != "go-api" {
+		t.Errorf("Unexpected options %+v", options)
+	}
+
+	options = templateOptions(cfg, templateTypes, core.CategoryBackend)
+	if len(options) != 1 || options[0].Value != "go-api" {
+		t.Errorf("Expected only the backend template, got %+v", options)
+	}
+}
+
+func TestApplyVariableDefaults(t *testing.T) {
+	defaults := map[string]string{"Author": "Platform Team", "Team": "billing", config.GitHubOwnerVariable: "acme"}
+	variables := map[string]string{"GitHubRepo": "billing-api", "Author": "Jane Doe"}
+
+	applyVariableDefaults(variables, defaults)
+
+	want := map[string]string{"GitHubRepo": "acme/billing-api", "Author": "Jane Doe", "Team": "billing"}
+	if !reflect.DeepEqual(variables, want) {
+		t.Errorf("applyVariableDefaults() = %v, want %v", variables, want)
+	}
+	if custom := customVariables(variables); !reflect.DeepEqual(custom, map[string]string{"Team": "billing"}) {
+		t.Errorf("customVariables() = %v", custom)
+	}
+	if repo := withDefaultOwner("other/billing-api", defaults); repo != "other/billing-api" {
+		t.Errorf("withDefaultOwner() should keep an explicit owner, got %q", repo)
+	}
+}
+
+func TestDecoratePlainOutput(t *testing.T) {
+	original := plainOutput
+	defer func() { plainOutput = original }()
+
+	plainOutput = false
+	if got := decorate(bulletSymbol, "go-api"); got != "• go-api" {
+		t.Errorf("decorate() = %q, want a bullet", got)
+	}
+	if got := decorate(successSymbol, "Project created successfully!"); got != "✨ Project created successfully!" {
+		t.Errorf("decorate() = %q, want the emoji", got)
+	}
+
+	plainOutput = true
+	if got := decorate(bulletSymbol, "go-api"); got != "- go-api" {
+		t.Errorf("plain decorate() = %q, want an ASCII bullet", got)
+	}
+	if got := decorate(warningSymbol, "deprecated"); got != "! deprecated" {
+		t.Errorf("plain decorate() = %q, want an ASCII warning", got)
+	}
+	if got := decorate(successSymbol, "Project created successfully!"); got != "Project created successfully!" {
+		t.Errorf("plain decorate() = %q, want the emoji dropped", got)
+	}
+	if !accessibleForms() {
+		t.Error("accessibleForms() = false, want plain prompts with plain output")
+	}
+}
+
+// captureJSON runs fn in --json mode and decodes what it printed to stdout into v
+func captureJSON(t *testing.T, v any, fn func() error) error {
+	t.Helper()
+	originalJSON, originalStdout := jsonOutput, os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonOutput, os.Stdout = true, w
+	defer func() { jsonOutput, os.Stdout = originalJSON, originalStdout }()
+
+	output := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- data
+	}()
+	runErr := fn()
+	w.Close()
+	data := <-output
+
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, data)
+	}
+	return runErr
+}
+
+// executeJSON runs the command line args with --json, decoding its output into v
+func executeJSON(t *testing.T, v any, args ...string) error {
+	t.Helper()
+	originalPlain := plainOutput
+	defer func() { plainOutput = originalPlain }()
+
+	return captureJSON(t, v, func() error {
+		rootCmd.SetArgs(append(args, "--json", "--quiet"))
+		defer rootCmd.SetArgs(nil)
+		return rootCmd.ExecuteContext(context.Background())
+	})
+}
+
+func TestRunListJSON(t *testing.T) {
+	var entries []listEntry
+	if err := captureJSON(t, &entries, runList); err != nil {
+		t.Fatalf("runList() error = %v", err)
+	}
+
+	i := slices.IndexFunc(entries, func(e listEntry) bool { return e.Name == "go-api" })
+	if i < 0 {
+		t.Fatalf("list --json = %+v, want the go-api type", entries)
+	}
+	if entries[i].Category != core.CategoryBackend || !slices.Contains(entries[i].Tags, "go") {
+		t.Errorf("go-api entry = %+v, want its category and tags", entries[i])
+	}
+}
+
+func TestRunConfigListJSON(t *testing.T) {
+	cleanup := setupTempConfig(t)
+	defer cleanup()
+
+	if err := runConfigAdd("go-api", "/test/go-api", "Go API"); err != nil {
+		t.Fatal(err)
+	}
+
+	var entries []configEntry
+	if err := captureJSON(t, &entries, runConfigList); err != nil {
+		t.Fatalf("runConfigList() error = %v", err)
+	}
+	i := slices.IndexFunc(entries, func(e configEntry) bool { return e.Type == "go-api" })
+	if i < 0 || entries[i].Path != "/test/go-api" || entries[i].Description != "Go API" {
+		t.Errorf("config list --json = %+v, want the go-api reference", entries)
+	}
+}
+
+func TestExtractGenerateJSON(t *testing.T) {
+	cleanup := setupTempConfig(t)
+	defer cleanup()
+
+	source := t.TempDir()
+	if err := os.WriteFile(filepath.Join(source, "README.md"), []byte("# __PROJECT_NAME__\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(source, "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	schemaFile := filepath.Join(t.TempDir(), "template.json")
+
+	var extracted extract.Result
+	if err := executeJSON(t, &extracted, "extract", source, "--type", "generic", "-o", schemaFile); err != nil {
+		t.Fatalf("extract error = %v", err)
+	}
+	if extracted.Output != schemaFile || extracted.Type != "generic" || extracted.Files != 2 ||
+		extracted.Templated != 1 {
+		t.Errorf("extract --json = %+v, want 2 generic files, 1 templated, in %s", extracted, schemaFile)
+	}
+
+	outputDir := filepath.Join(t.TempDir(), "my-app")
+	var generated generate.Result
+	err := executeJSON(t, &generated, "generate", schemaFile,
+		"--project-name", "My App", "--github-repo", "user/my-app", "--author", "Jane", "--output-dir", outputDir)
+	if err != nil {
+		t.Fatalf("generate error = %v", err)
+	}
+	slices.Sort(generated.Files)
+	if generated.OutputDir != outputDir || generated.FileCount != 2 ||
+		!slices.Equal(generated.Files, []string{"README.md", "main.go"}) {
+		t.Errorf("generate --json = %+v, want README.md and main.go in %s", generated, outputDir)
+	}
+}
+
+func TestPrintErrorJSON(t *testing.T) {
+	var output errorOutput
+	_ = captureJSON(t, &output, func() error {
+		printError(errors.New("template file not found"))
+		return nil
+	})
+	if output.Error != "template file not found" {
+		t.Errorf("error --json = %+v, want the error message", output)
+	}
+}
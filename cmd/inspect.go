@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var (
+	inspectTop    int
+	inspectDedupe bool
+	inspectOutput string
+)
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect <schema.json>",
+	Short: "Show how a template schema stores its files",
+	Long: `Inspect a template schema and report per-file sizes, compression ratios,
+groups of files with identical content and the largest contributors.
+
+With --dedupe, identical file contents are stored once in the schema's blob
+table (keyed by content hash) and the result is written to --output.
+
+Examples:
+  template-engine inspect frontend-template.json
+  template-engine inspect frontend-template.json --top 20 --json
+  template-engine inspect frontend-template.json --dedupe -o frontend-deduped.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runInspect(args[0])
+	},
+}
+
+func init() {
+	inspectCmd.Flags().IntVar(&inspectTop, "top", 10, "Number of largest files to show")
+	inspectCmd.Flags().BoolVar(&inspectDedupe, "dedupe", false, "Store identical file contents once")
+	inspectCmd.Flags().StringVarP(&inspectOutput, "output", "o", "",
+		"Output file for the deduplicated schema (defaults to overwriting the input)")
+}
+
+func runInspect(schemaFile string) error {
+	schema, err := core.LoadSchemaFile(schemaFile)
+	if err != nil {
+		return err
+	}
+
+	if inspectDedupe {
+		output := inspectOutput
+		if output == "" {
+			output = schemaFile
+		}
+		saved := core.DedupeSchema(schema)
+		if err := core.SaveSchemaFile(schema, output); err != nil {
+			return err
+		}
+		logger.Info("Deduplicated schema", "output", output, "saved", formatBytes(int64(saved)))
+	}
+
+	inspection := core.InspectSchema(schema, inspectTop)
+	if jsonOutput {
+		return printJSON(inspection)
+	}
+
+	printInspection(schemaFile, inspection)
+	return nil
+}
+
+// printInspection prints a human-readable storage report
+func printInspection(schemaFile string, inspection *core.Inspection) {
+	fmt.Printf("Inspecting %s\n", schemaFile)
+	fmt.Println()
+	fmt.Printf("%d file(s), %s original, %s stored\n",
+		len(inspection.Files), formatBytes(inspection.TotalSize), formatBytes(inspection.TotalStored))
+
+	if len(inspection.Largest) > 0 {
+		fmt.Println()
+		fmt.Println("Largest files:")
+		for _, file := range inspection.Largest {
+			note := ""
+			switch {
+			case file.Shared:
+				note = " (shared)"
+			case file.Compressed:
+				note = " (compressed)"
+			}
+			fmt.Printf("  %10s  %5.1f%%  %s%s\n",
+				formatBytes(file.Stored), file.Ratio*100, file.Path, note)
+		}
+	}
+
+	if len(inspection.Duplicates) > 0 {
+		fmt.Println()
+		fmt.Println("Duplicate content:")
+		for _, group := range inspection.Duplicates {
+			fmt.Printf("  %s x%d\n", formatBytes(group.Size), len(group.Paths))
+			for _, path := range group.Paths {
+				fmt.Printf("    %s\n", path)
+			}
+		}
+	}
+}
+
+// formatBytes formats a byte count with a binary unit suffix
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
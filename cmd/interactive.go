@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/acheevo/template-engine/internal/config"
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/generate"
+	"github.com/acheevo/template-engine/internal/i18n"
+	"github.com/charmbracelet/huh"
+)
+
+// Optional features offered by interactive mode, each mirroring a flag of the new command
+const (
+	featureHooks   = "hooks"
+	featureGitInit = "git-init"
+)
+
+// interactiveAnswers holds what the user entered in the interactive form
+type interactiveAnswers struct {
+	Category     string
+	TemplateType string
+	ProjectName  string
+	GitHubRepo   string
+	Author       string
+	Description  string
+	OutputDir    string
+	Features     []string
+}
+
+func runInteractiveNew(ctx context.Context) error {
+	if !stdinIsTerminal() {
+		return errors.New(i18n.T("interactive mode needs a terminal, pass <type> <project-name> <github-repo> instead"))
+	}
+
+	// Load configuration to get available template types
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	templateTypes := cfg.ListTemplateTypes()
+	if len(templateTypes) == 0 {
+		return fmt.Errorf("no template types configured")
+	}
+	sort.Strings(templateTypes)
+	categories := referenceCategories(templateTypes)
+
+	answers := interactiveAnswers{Author: newAuthor, Description: newDescription}
+	if newHooks {
+		answers.Features = append(answers.Features, featureHooks)
+	}
+	if newGitInit {
+		answers.Features = append(answers.Features, featureGitInit)
+	}
+	defaultAuthor := generate.DefaultAuthor(ctx)
+	accessible := accessibleForms()
+
+	// Optional category filter ("show only backend templates")
+	if len(categories) > 1 {
+		filter := huh.NewForm(huh.NewGroup(
+			huh.NewSelect[string]().
+				Title(i18n.T("Category")).
+				Options(categoryOptions(categories)...).
+				Value(&answers.Category),
+		)).WithAccessible(accessible)
+		if err := filter.RunWithContext(ctx); err != nil {
+			return interactiveError(err)
+		}
+	}
+
+	selectType := huh.NewForm(huh.NewGroup(
+		huh.NewSelect[string]().
+			Title(i18n.T("Template type")).
+			Options(templateOptions(cfg, templateTypes, answers.Category)...).
+			Value(&answers.TemplateType),
+	)).WithAccessible(accessible)
+	if err := selectType.RunWithContext(ctx); err != nil {
+		return interactiveError(err)
+	}
+
+	// Prefill the details with the defaults stored for the template type (config set-default)
+	defaults := cfg.VariableDefaults(answers.TemplateType)
+	if answers.Author == "" {
+		answers.Author = defaults["Author"]
+	}
+	if answers.Description == "" {
+		answers.Description = defaults["Description"]
+	}
+	repoPlaceholder := "user/repo-name"
+	if owner := defaults[config.GitHubOwnerVariable]; owner != "" {
+		repoPlaceholder = owner + "/repo-name"
+	}
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title(i18n.T("Project name")).
+				Validate(core.ValidateProjectName).
+				Value(&answers.ProjectName),
+			huh.NewInput().
+				Title(i18n.T("GitHub repo")).
+				Placeholder(repoPlaceholder).
+				Validate(func(repo string) error {
+					return core.ValidateGitHubRepo(withDefaultOwner(repo, defaults))
+				}).
+				Value(&answers.GitHubRepo),
+			huh.NewInput().
+				Title(i18n.T("Author")).
+				Placeholder(defaultAuthor).
+				Value(&answers.Author),
+			huh.NewInput().
+				Title(i18n.T("Description")).
+				PlaceholderFunc(func() string {
+					return fmt.Sprintf("A %s application", answers.ProjectName)
+				}, &answers.ProjectName).
+				Value(&answers.Description),
+			huh.NewInput().
+				Title(i18n.T("Output directory")).
+				PlaceholderFunc(func() string {
+					return defaultOutputDir(answers.ProjectName)
+				}, &answers.ProjectName).
+				Value(&answers.OutputDir),
+		),
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title(i18n.T("Optional features")).
+				Options(
+					huh.NewOption(i18n.T("Run template hooks (e.g. go mod tidy, npm install)"), featureHooks).
+						Selected(slices.Contains(answers.Features, featureHooks)),
+					huh.NewOption(i18n.T("Initialize a git repository"), featureGitInit).
+						Selected(slices.Contains(answers.Features, featureGitInit)),
+				).
+				Value(&answers.Features),
+		),
+	).WithAccessible(accessible)
+
+	if err := form.RunWithContext(ctx); err != nil {
+		return interactiveError(err)
+	}
+
+	answers.GitHubRepo = withDefaultOwner(answers.GitHubRepo, defaults)
+	if answers.Author == "" {
+		answers.Author = defaultAuthor
+	}
+	if answers.OutputDir == "" {
+		answers.OutputDir = defaultOutputDir(answers.ProjectName)
+	}
+
+	confirmed := true
+	confirm := huh.NewForm(huh.NewGroup(
+		huh.NewConfirm().
+			Title(i18n.T("Create this project?")).
+			Description(answers.summary()).
+			Affirmative(i18n.T("Create")).
+			Negative(i18n.T("Cancel")).
+			Value(&confirmed),
+	)).WithAccessible(accessible)
+	if err := confirm.RunWithContext(ctx); err != nil {
+		return interactiveError(err)
+	}
+	if !confirmed {
+		logger.Info(i18n.T("Cancelled, nothing was generated"))
+		return nil
+	}
+
+	newAuthor = answers.Author
+	newDescription = answers.Description
+	newHooks = slices.Contains(answers.Features, featureHooks)
+	newGitInit = slices.Contains(answers.Features, featureGitInit)
+
+	return runNew(ctx, answers.TemplateType, answers.ProjectName, answers.GitHubRepo, answers.OutputDir)
+}
+
+// summary lists the answers for the confirmation screen, in the language of the prompts. The
+// default description stays English, like the one generated.
+func (a interactiveAnswers) summary() string {
+	description := a.Description
+	if description == "" {
+		description = fmt.Sprintf("A %s application", a.ProjectName)
+	}
+	features := i18n.T("none")
+	if len(a.Features) > 0 {
+		features = strings.Join(a.Features, ", ")
+	}
+
+	rows := [][2]string{
+		{i18n.T("Template"), a.TemplateType},
+		{i18n.T("Project"), a.ProjectName},
+		{i18n.T("Repo"), a.GitHubRepo},
+		{i18n.T("Author"), a.Author},
+		{i18n.T("Description"), description},
+		{i18n.T("Output"), a.OutputDir},
+		{i18n.T("Features"), features},
+	}
+	// Align the values past the longest translated label
+	width := 0
+	for _, row := range rows {
+		width = max(width, utf8.RuneCountInString(row[0]))
+	}
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		lines[i] = row[0] + ":" + strings.Repeat(" ", width-utf8.RuneCountInString(row[0])+1) + row[1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// interactiveError turns an aborted form into a friendlier error
+func interactiveError(err error) error {
+	if errors.Is(err, huh.ErrUserAborted) {
+		return errors.New(i18n.T("cancelled, nothing was generated"))
+	}
+	return err
+}
+
+// stdinIsTerminal reports whether standard input is an interactive terminal
+func stdinIsTerminal() bool {
+	return isTerminal(os.Stdin)
+}
+
+// defaultOutputDir returns the output directory new uses when none is given
+func defaultOutputDir(projectName string) string {
+	return "./" + strings.ToLower(strings.ReplaceAll(projectName, " ", "-"))
+}
+
+// categoryOptions returns the category filter choices, led by one keeping every category
+func categoryOptions(categories []string) []huh.Option[string] {
+	options := []huh.Option[string]{huh.NewOption(i18n.T("All"), "")}
+	for _, category := range categories {
+		options = append(options, huh.NewOption(category, category))
+	}
+	return options
+}
+
+// templateOptions returns the template type choices of category, or of every category when empty
+func templateOptions(cfg *config.ReferenceConfig, templateTypes []string, category string) []huh.Option[string] {
+	if category != "" {
+		templateTypes = filterByCategory(templateTypes, category)
+	}
+
+	options := make([]huh.Option[string], 0, len(templateTypes))
+	for _, templateType := range templateTypes {
+		label := templateType
+		if description := cfg.References[templateType].Description; description != "" {
+			label += " - " + description
+		}
+		options = append(options, huh.NewOption(label, templateType))
+	}
+	return options
+}
+
+// typeCategory returns the category of a configured reference, known for built-in template types
+func typeCategory(templateType string) string {
+	registered, err := core.GetTemplate(templateType)
+	if err != nil {
+		return ""
+	}
+	category, _ := core.TypeMetadata(registered)
+	return category
+}
+
+// referenceCategories returns the sorted distinct categories of the configured references
+func referenceCategories(templateTypes []string) []string {
+	var categories []string
+	for _, templateType := range templateTypes {
+		if category := typeCategory(templateType); category != "" && !slices.Contains(categories, category) {
+			categories = append(categories, category)
+		}
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// filterByCategory keeps the template types of category
+func filterByCategory(templateTypes []string, category string) []string {
+	var filtered []string
+	for _, templateType := range templateTypes {
+		if strings.EqualFold(typeCategory(templateType), category) {
+			filtered = append(filtered, templateType)
+		}
+	}
+	return filtered
+}
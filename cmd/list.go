@@ -3,11 +3,20 @@ package cmd
 import (
 	"fmt"
 	"sort"
+	"strings"
 
+	"github.com/acheevo/template-engine/internal/config"
 	"github.com/acheevo/template-engine/internal/core"
 	"github.com/spf13/cobra"
 )
 
+var (
+	listSearch   string
+	listType     string
+	listTags     []string
+	listCategory string
+)
+
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List available template types",
@@ -15,27 +24,82 @@ var listCmd = &cobra.Command{
 
 Template types define how different kinds of projects should be processed
 (file patterns to include/exclude, template variables, etc.).
+Deprecated types are marked along with their successor.
 
-Example:
-  template-engine list`,
+--search keeps the types whose name, reference project description or tags
+contain the text, --type a single type, --tag the types carrying a tag and
+--category the types of a category (backend, frontend, fullstack).
+
+Examples:
+  template-engine list
+  template-engine list --search react
+  template-engine list --search react --type frontend
+  template-engine list --category backend`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runList()
 	},
 }
 
+func init() {
+	listCmd.Flags().StringVar(&listSearch, "search", "", "Only list types whose name or description contains this text")
+	listCmd.Flags().StringVar(&listType, "type", "", "Only list this template type")
+	listCmd.Flags().StringArrayVar(&listTags, "tag", nil, "Only list types with this tag (repeatable)")
+	listCmd.Flags().StringVar(&listCategory, "category", "", "Only list types of this category (e.g. backend)")
+	_ = listCmd.RegisterFlagCompletionFunc("category",
+		fixedCompletions(core.CategoryBackend, core.CategoryFrontend, core.CategoryFullstack, core.CategoryCLI,
+			core.CategoryLibrary, core.CategoryInfra))
+	_ = listCmd.RegisterFlagCompletionFunc("type", completeTemplateTypes)
+}
+
+// listEntry is the JSON representation of a template type
+type listEntry struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Category    string   `json:"category,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Deprecated  bool     `json:"deprecated,omitempty"`
+	ReplacedBy  string   `json:"replaced_by,omitempty"`
+}
+
 func runList() error {
+	query := core.SchemaQuery{Search: listSearch, Type: listType, Tags: listTags, Category: listCategory}
+	entries := listEntries(query)
+
+	if jsonOutput {
+		return printJSON(entries)
+	}
+
 	fmt.Println("Available template types:")
 	fmt.Println()
 
-	templates := core.ListTemplates()
-	if len(templates) == 0 {
-		fmt.Println("No templates registered")
+	if len(entries) == 0 {
+		if query.Search != "" || query.Type != "" || len(query.Tags) > 0 || query.Category != "" {
+			fmt.Println("No template types match the filters")
+		} else {
+			fmt.Println("No templates registered")
+		}
 		return nil
 	}
 
-	sort.Strings(templates)
-	for _, templateName := range templates {
-		fmt.Printf("• %s\n", templateName)
+	for _, entry := range entries {
+		var notes []string
+		if entry.Category != "" {
+			notes = append(notes, entry.Category)
+		}
+		if entry.Deprecated {
+			notes = append(notes, deprecationNote(entry.ReplacedBy))
+		}
+		if len(notes) > 0 {
+			printItem("%s (%s)", entry.Name, strings.Join(notes, ", "))
+		} else {
+			printItem("%s", entry.Name)
+		}
+		if entry.Description != "" {
+			fmt.Printf("  %s\n", entry.Description)
+		}
+		if len(entry.Tags) > 0 {
+			fmt.Printf("  tags: %s\n", strings.Join(entry.Tags, ", "))
+		}
 	}
 
 	fmt.Println()
@@ -43,3 +107,50 @@ func runList() error {
 
 	return nil
 }
+
+// listEntries returns the registered template types matching query, described by their
+// configured reference project
+func listEntries(query core.SchemaQuery) []listEntry {
+	templates := core.ListTemplates()
+	sort.Strings(templates)
+
+	references := map[string]config.ReferenceProject{}
+	if cfg, err := config.LoadConfig(); err == nil {
+		references = cfg.References
+	}
+
+	entries := make([]listEntry, 0, len(templates))
+	for _, templateName := range templates {
+		templateType, err := core.GetTemplate(templateName)
+		if err != nil {
+			continue
+		}
+		category, tags := core.TypeMetadata(templateType)
+
+		description := references[templateName].Description
+		if !query.Matches(&core.TemplateSchema{
+			Name: templateName, Type: templateName, Description: description, Tags: tags, Category: category,
+		}) {
+			continue
+		}
+
+		deprecation, deprecated := core.TemplateDeprecation(templateName)
+		entries = append(entries, listEntry{
+			Name:        templateName,
+			Description: description,
+			Category:    category,
+			Tags:        tags,
+			Deprecated:  deprecated,
+			ReplacedBy:  deprecation.ReplacedBy,
+		})
+	}
+	return entries
+}
+
+// deprecationNote describes a deprecated template type or schema in listings
+func deprecationNote(replacedBy string) string {
+	if replacedBy == "" {
+		return "deprecated"
+	}
+	return "deprecated, use " + replacedBy
+}
@@ -3,16 +3,30 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 
 	"github.com/acheevo/template-engine/internal/config"
+	"github.com/acheevo/template-engine/internal/generate"
+	"github.com/acheevo/template-engine/internal/i18n"
 	"github.com/acheevo/template-engine/sdk"
 	"github.com/spf13/cobra"
 )
 
-var interactive bool
+var (
+	interactive    bool
+	newAuthor      string
+	newDescription string
+	newHooks       bool
+	newGitInit     bool
+	newNoCache     bool
+	newVars        []string
+	newEnable      []string
+	newDisable     []string
+)
 
 var newCmd = &cobra.Command{
 	Use:   "new [type] [project-name] [github-repo] [output-dir]",
@@ -24,13 +38,35 @@ The command looks for reference projects in sibling directories:
 - frontend: ../frontend-template
 - go-api:   ../api-template
 
+The schema extracted from a reference project is cached (see cache clear)
+and reused until the project changes; --no-cache always extracts it anew.
+
+References configured with a git URL (see config add) are cloned into the
+user cache directory instead and updated to their pinned ref on every run.
+
+The author defaults to the git user ("Name <email>" from git config), unless
+a default is stored for the template type with 'config set-default', which
+can also set the description, custom variables and a GitHubOwner for repos
+given without owner. Custom variables are also set with --var NAME=VALUE,
+and optional features of the template switched with --enable and --disable.
+With --hooks the template's hooks run after generation (e.g. go mod tidy,
+npm install), restricted by the binary whitelist in hooks.json, and
+--git-init initializes a git repository in the new project. With --json the
+files written, their size, hook results and the duration are printed as JSON.
+
+Interactive mode walks through template selection, project details and
+optional features in a terminal UI, then asks to confirm a summary. Set
+ACCESSIBLE=1 for plain prompts that suit screen readers. Prompts and
+messages follow the locale (LC_ALL, LC_MESSAGES, LANG) or --lang, in English,
+German, Spanish, French or Portuguese.
+
 Examples:
   template-engine new frontend "My React App" "user/my-app"
-  template-engine new go-api "My API Service" "user/my-api"
+  template-engine new go-api "My API Service" "user/my-api" --author "Jane Doe" --description "Billing API"
   template-engine new --interactive`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if interactive {
-			return runInteractiveNew()
+			return runInteractiveNew(cmd.Context())
 		}
 
 		if len(args) < 3 {
@@ -41,28 +77,39 @@ Examples:
 		projectName := args[1]
 		githubRepo := args[2]
 
-		outputDir := "./" + strings.ToLower(strings.ReplaceAll(projectName, " ", "-"))
+		outputDir := defaultOutputDir(projectName)
 		if len(args) > 3 {
 			outputDir = args[3]
 		}
 
-		return runNew(templateType, projectName, githubRepo, outputDir)
+		return runNew(cmd.Context(), templateType, projectName, githubRepo, outputDir)
 	},
 }
 
 func init() {
 	newCmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Interactive project creation mode")
+	newCmd.Flags().StringVar(&newAuthor, "author", "", "Project author (defaults to the git user)")
+	newCmd.Flags().StringVar(&newDescription, "description", "", "Project description")
+	newCmd.Flags().StringArrayVar(&newVars, "var", nil,
+		"Set a custom variable declared by the template (NAME=VALUE, repeatable)")
+	newCmd.Flags().StringSliceVar(&newEnable, "enable", nil,
+		"Enable an optional feature of the template (repeatable or comma-separated)")
+	newCmd.Flags().StringSliceVar(&newDisable, "disable", nil,
+		"Disable an optional feature of the template (repeatable or comma-separated)")
+	newCmd.Flags().BoolVar(&newHooks, "hooks", false, "Run the template hooks after generation")
+	newCmd.Flags().BoolVar(&newGitInit, "git-init", false, "Initialize a git repository in the new project")
+	newCmd.Flags().BoolVar(&newNoCache, "no-cache", false, "Extract the reference project even if it is unchanged")
 }
 
-func runNew(templateType, projectName, githubRepo, outputDir string) error {
+func runNew(ctx context.Context, templateType, projectName, githubRepo, outputDir string) error {
 	// Load reference configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
-	// Get reference project path
-	referenceDir, err := cfg.GetReferencePath(templateType)
+	// Get reference project path, cloning or updating a remote reference
+	referenceDir, err := cfg.CheckoutReference(ctx, logger, templateType, offline)
 	if err != nil {
 		return err
 	}
@@ -72,88 +119,109 @@ func runNew(templateType, projectName, githubRepo, outputDir string) error {
 		return fmt.Errorf("reference project not found: %s. Make sure you have the reference project available", referenceDir)
 	}
 
-	fmt.Printf("🚀 Creating %s project...\n", templateType)
-	fmt.Printf("   Reference: %s\n", referenceDir)
-	fmt.Printf("   Name: %s\n", projectName)
-	fmt.Printf("   Repo: %s\n", githubRepo)
-	fmt.Printf("   Output: %s\n", outputDir)
-	fmt.Println()
-
-	// Use SDK to extract and generate
-	client := sdk.New()
-
-	err = client.ExtractAndGenerate(context.Background(), referenceDir, templateType, projectName, githubRepo, outputDir)
+	// Fill in what was not given from the defaults stored with config set-default
+	variables, err := generate.ParseVariableAssignments(newVars)
 	if err != nil {
-		return fmt.Errorf("failed to generate project: %w", err)
+		return err
 	}
+	maps.Copy(variables, map[string]string{"GitHubRepo": githubRepo, "Author": newAuthor, "Description": newDescription})
+	applyVariableDefaults(variables, cfg.VariableDefaults(templateType))
+	githubRepo = variables["GitHubRepo"]
 
-	// Print success message and next steps
-	fmt.Println()
-	fmt.Printf("✨ Project created successfully!\n")
-	fmt.Println()
-	fmt.Println("Next steps:")
-	fmt.Printf("  cd %s\n", filepath.Base(outputDir))
+	logger.Info(decorate(startSymbol, i18n.T("Creating %s project...", templateType)))
+	logger.Info("   " + i18n.T("Reference") + ": " + referenceDir)
+	logger.Info("   " + i18n.T("Name") + ": " + projectName)
+	logger.Info("   " + i18n.T("Repo") + ": " + githubRepo)
+	logger.Info("   " + i18n.T("Output") + ": " + outputDir)
 
-	switch templateType {
-	case "frontend":
-		fmt.Println("  npm install")
-		fmt.Println("  npm run dev")
-	case "go-api", "api":
-		fmt.Println("  go mod tidy")
-		fmt.Println("  make run")
+	// Use SDK to extract and generate
+	opts := []sdk.Option{sdk.WithLogger(logger)}
+	if !newNoCache {
+		opts = append(opts, sdk.WithSchemaCache(""))
 	}
-
-	return nil
-}
-
-func runInteractiveNew() error {
-	fmt.Println("🎯 Interactive Project Generator")
-	fmt.Println()
-
-	// Load configuration to get available template types
-	cfg, err := config.LoadConfig()
+	if offline {
+		opts = append(opts, sdk.WithOffline())
+	}
+	policies, err := orgPolicies()
 	if err != nil {
-		return fmt.Errorf("failed to load configuration: %w", err)
+		return err
 	}
-
-	templateTypes := cfg.ListTemplateTypes()
-	if len(templateTypes) == 0 {
-		return fmt.Errorf("no template types configured")
+	opts = append(opts, sdk.WithPolicies(policies...))
+	if newHooks {
+		policy, err := config.LoadHookPolicy()
+		if err != nil {
+			return err
+		}
+		opts = append(opts, sdk.WithHooks(policy.AllowedBinaries...))
 	}
-
-	// Template type selection
-	fmt.Println("Select template type:")
-	for i, templateType := range templateTypes {
-		ref := cfg.References[templateType]
-		fmt.Printf("%d. %s - %s\n", i+1, templateType, ref.Description)
+	client := sdk.New(opts...)
+
+	result, err := client.ExtractAndGenerateWithVariables(ctx, referenceDir, templateType, sdk.Variables{
+		ProjectName: projectName,
+		GitHubRepo:  githubRepo,
+		OutputDir:   outputDir,
+		Author:      variables["Author"],
+		Description: variables["Description"],
+		Custom:      customVariables(variables),
+
+		EnableFeatures:  newEnable,
+		DisableFeatures: newDisable,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to generate project: %w", err)
 	}
-	fmt.Printf("Enter choice (1-%d): ", len(templateTypes))
 
-	var choice int
-	if _, err := fmt.Scanln(&choice); err != nil {
-		return fmt.Errorf("invalid input: %w", err)
+	if newGitInit {
+		if err := gitInit(ctx, outputDir); err != nil {
+			return err
+		}
 	}
-
-	if choice < 1 || choice > len(templateTypes) {
-		return fmt.Errorf("invalid choice")
+	if jsonOutput {
+		return printJSON(result)
 	}
 
-	templateType := templateTypes[choice-1]
-
-	// Project details
-	var projectName, githubRepo string
-
-	fmt.Print("Project name: ")
-	if _, err := fmt.Scanln(&projectName); err != nil {
-		return fmt.Errorf("invalid input: %w", err)
-	}
+	// Print success message and next steps
+	logger.Info(decorate(successSymbol, i18n.T("Project created successfully!")))
+	logger.Info(i18n.T("Next steps:"))
+	logger.Info("  cd " + filepath.Base(outputDir))
 
-	fmt.Print("GitHub repo (user/repo-name): ")
-	if _, err := fmt.Scanln(&githubRepo); err != nil {
-		return fmt.Errorf("invalid input: %w", err)
+	switch templateType {
+	case "frontend":
+		logger.Info("  npm install")
+		logger.Info("  npm run dev")
+	case "go-api", "api":
+		logger.Info("  go mod tidy")
+		logger.Info("  make run")
+	case "go-cli":
+		logger.Info("  go mod tidy")
+		logger.Info("  go run . --help")
+	case "go-library":
+		logger.Info("  go mod tidy")
+		logger.Info("  go test ./...")
+	case "python-api":
+		logger.Info("  uv sync")
+		logger.Info("  uv run fastapi dev app/main.py")
+	case "infra":
+		logger.Info("  terraform init")
+		logger.Info("  terraform plan")
+	case "helm-chart":
+		logger.Info("  helm lint .")
+		logger.Info("  helm template .")
+	case "monorepo":
+		logger.Info("  pnpm install")
+		logger.Info("  pnpm dev")
 	}
 
-	outputDir := "./" + strings.ToLower(strings.ReplaceAll(projectName, " ", "-"))
+	return nil
+}
 
-	return runNew(templateType, projectName, githubRepo, outputDir)
+// gitInit initializes a git repository in dir
+func gitInit(ctx context.Context, dir string) error {
+	cmd := exec.CommandContext(ctx, "git", "init", "--quiet")
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git init failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	logger.Info("   " + i18n.T("Initialized git repository"))
+	return nil
 }
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/oci"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ociPlainHTTP bool
+	ociOutput    string
+)
+
+// ociResult is printed with --json
+type ociResult struct {
+	Reference string `json:"reference"`
+	Digest    string `json:"digest,omitempty"`
+	Output    string `json:"output,omitempty"`
+}
+
+var pushCmd = &cobra.Command{
+	Use:   "push <reference> <schema.json>",
+	Short: "Push a template schema to an OCI registry",
+	Long: `Push a template schema as an OCI artifact, so it can be versioned and
+distributed through any OCI registry (GitHub Container Registry, Docker Hub,
+Harbor, ...).
+
+Credentials are read from the Docker config, so run "docker login" first.
+
+Examples:
+  template-engine push ghcr.io/org/templates/frontend:1.2.0 frontend-template.json
+  template-engine push localhost:5000/templates/go-api:dev api-template.json --plain-http`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ref, err := oci.ParseReference(args[0])
+		if err != nil {
+			return err
+		}
+
+		schema, err := core.LoadSchemaFile(args[1])
+		if err != nil {
+			return err
+		}
+		// Artifacts hold a single document, without the sidecar blobs of external files
+		if err := core.InlineExternal(schema); err != nil {
+			return err
+		}
+		if err := core.ValidateSchema(schema); err != nil {
+			return fmt.Errorf("invalid schema: %w", err)
+		}
+		schema.Origin = ref.String()
+
+		data, err := json.Marshal(schema)
+		if err != nil {
+			return fmt.Errorf("failed to marshal schema: %w", err)
+		}
+
+		client := oci.NewClient()
+		client.PlainHTTP = ociPlainHTTP
+		client.Offline = offline
+		digest, err := client.Push(cmd.Context(), ref, data, map[string]string{
+			"org.opencontainers.image.title":       schema.Name,
+			"org.opencontainers.image.version":     schema.Version,
+			"org.opencontainers.image.description": schema.Description,
+		})
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(ociResult{Reference: ref.String(), Digest: digest})
+		}
+		logger.Info("Pushed schema", "reference", ref.String(), "digest", digest)
+		return nil
+	},
+}
+
+var pullCmd = &cobra.Command{
+	Use:   "pull <reference>",
+	Short: "Pull a template schema from an OCI registry",
+	Long: `Pull a template schema pushed with "template-engine push". The artifact
+digest is verified, and the schema is validated before it is written. The
+reference is recorded as the schema's origin, so its hooks only run when
+generating with --hooks --allow-hooks.
+
+Examples:
+  template-engine pull ghcr.io/org/templates/frontend:1.2.0 -o frontend-template.json
+  template-engine pull ghcr.io/org/templates/frontend@sha256:...`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ref, err := oci.ParseReference(args[0])
+		if err != nil {
+			return err
+		}
+
+		client := oci.NewClient()
+		client.PlainHTTP = ociPlainHTTP
+		client.Offline = offline
+		data, err := client.Pull(cmd.Context(), ref)
+		if err != nil {
+			return err
+		}
+
+		schema, err := core.ParseSchema(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse schema: %w", err)
+		}
+		if err := core.ValidateSchema(schema); err != nil {
+			return fmt.Errorf("invalid schema: %w", err)
+		}
+		schema.Origin = ref.String()
+
+		output := ociOutput
+		if output == "" {
+			output = schema.Name + ".json"
+		}
+		if err := core.SaveSchemaFile(schema, output); err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(ociResult{Reference: ref.String(), Output: output})
+		}
+		logger.Info("Pulled schema", "reference", ref.String(), "output", output, "files", len(schema.Files))
+		return nil
+	},
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{pushCmd, pullCmd} {
+		cmd.Flags().BoolVar(&ociPlainHTTP, "plain-http", false, "Use HTTP instead of HTTPS (local registries)")
+	}
+	pullCmd.Flags().StringVarP(&ociOutput, "output", "o", "", "Output file (defaults to <schema name>.json)")
+}
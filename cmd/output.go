@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/mattn/go-isatty"
+)
+
+var (
+	// jsonOutput switches commands to machine-readable JSON results on stdout
+	jsonOutput bool
+	// plainOutput drops emoji, symbols and terminal styling from human-readable output so CI logs
+	// stay clean. Besides --plain, it is on when stdout or stderr is not a terminal or TERM=dumb.
+	plainOutput bool
+)
+
+// errorOutput is the JSON shape printed when a command fails in --json mode
+type errorOutput struct {
+	Error string `json:"error"`
+}
+
+// printJSON writes v to stdout as indented JSON
+func printJSON(v any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// printError reports the error a command failed with, as an errorOutput object in --json mode
+func printError(err error) {
+	if jsonOutput {
+		_ = printJSON(errorOutput{Error: err.Error()}) // Best effort, we exit with failure anyway
+		return
+	}
+	logger.Error(err.Error())
+}
+
+// symbol decorates human-readable output, replaced by its ASCII stand-in in plain output
+type symbol struct {
+	fancy string
+	plain string // Empty drops the symbol
+}
+
+var (
+	bulletSymbol  = symbol{fancy: "•", plain: "-"}
+	warningSymbol = symbol{fancy: "⚠", plain: "!"}
+	startSymbol   = symbol{fancy: "🚀"}
+	successSymbol = symbol{fancy: "✨"}
+)
+
+// decorate prefixes text with s, as plain output has it
+func decorate(s symbol, text string) string {
+	prefix := s.fancy
+	if plainOutput {
+		prefix = s.plain
+	}
+	if prefix == "" {
+		return text
+	}
+	return prefix + " " + text
+}
+
+// printItem prints an item of a list, printf-style
+func printItem(format string, args ...any) {
+	fmt.Println(decorate(bulletSymbol, fmt.Sprintf(format, args...)))
+}
+
+// detectPlainOutput reports whether output should be plain without --plain: when it goes to a
+// file or pipe, as in CI, or to a terminal that cannot show styling
+func detectPlainOutput() bool {
+	return !isTerminal(os.Stdout) || !isTerminal(os.Stderr) || os.Getenv("TERM") == "dumb"
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// accessibleForms reports whether prompts run in huh's accessible mode, plain prompts without
+// styling: with ACCESSIBLE set, for screen readers, or for plain output
+func accessibleForms() bool {
+	return plainOutput || os.Getenv("ACCESSIBLE") != ""
+}
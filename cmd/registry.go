@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/acheevo/template-engine/internal/config"
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/registry"
+	"github.com/spf13/cobra"
+)
+
+var (
+	registryURL    string
+	registryOutput string
+	registryName   string
+	registryCodec  string
+	registryDigest string
+)
+
+var registryCmd = &cobra.Command{
+	Use:   "registry",
+	Short: "Work with a shared template registry",
+	Long: `List, pull and push template schemas on a registry started with
+"template-engine serve".
+
+The registry URL comes from --registry or "template-engine config set-registry".
+Registries serving with a token need it in TEMPLATE_ENGINE_REGISTRY_TOKEN to
+push and extract.
+
+Examples:
+  template-engine registry list
+  template-engine registry pull go-api -o api-template.json
+  template-engine registry push api-template.json --name go-api
+  template-engine registry extract go-api`,
+}
+
+var registryListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List schemas in the registry",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := registryClient()
+		if err != nil {
+			return err
+		}
+
+		entries, err := client.List(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(entries)
+		}
+
+		if len(entries) == 0 {
+			fmt.Println("No schemas in registry")
+			return nil
+		}
+		for _, entry := range entries {
+			printItem("%s (%s %s, %d files)", entry.Name, entry.Type, entry.Version, entry.Files)
+			if entry.Deprecated {
+				fmt.Println("  " + decorate(warningSymbol, deprecationNote(entry.ReplacedBy)))
+			}
+			if entry.Description != "" {
+				fmt.Printf("  %s\n", entry.Description)
+			}
+		}
+		return nil
+	},
+}
+
+var registryPullCmd = &cobra.Command{
+	Use:   "pull <name>",
+	Short: "Download a schema from the registry",
+	Long: `Download a schema from the registry. The registry is recorded as the
+schema's origin, so its hooks only run when generating with --hooks --allow-hooks.
+
+Failed downloads are retried and interrupted ones resumed. The schema is
+verified against the digest the registry publishes, or against --digest to
+pin the exact schema.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := registryClient()
+		if err != nil {
+			return err
+		}
+
+		schema, err := client.GetVerified(cmd.Context(), args[0], registryDigest)
+		if err != nil {
+			return err
+		}
+
+		output := registryOutput
+		if output == "" {
+			output = args[0] + ".json"
+		}
+		if err := core.SaveSchemaFile(schema, output); err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(map[string]string{"name": args[0], "output": output})
+		}
+		logger.Info("Pulled schema", "name", args[0], "output", output, "files", len(schema.Files))
+		return nil
+	},
+}
+
+var registryPushCmd = &cobra.Command{
+	Use:   "push <schema.json>",
+	Short: "Upload a schema to the registry",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := registryClient()
+		if err != nil {
+			return err
+		}
+
+		schema, err := core.LoadSchemaFile(args[0])
+		if err != nil {
+			return err
+		}
+		// The registry stores single documents, without the sidecar blobs of external files
+		if err := core.InlineExternal(schema); err != nil {
+			return err
+		}
+
+		name := registryName
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(args[0]), filepath.Ext(args[0]))
+		}
+
+		entry, err := client.Put(cmd.Context(), name, schema)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(entry)
+		}
+		logger.Info("Pushed schema", "name", entry.Name, "files", entry.Files)
+		return nil
+	},
+}
+
+var registryExtractCmd = &cobra.Command{
+	Use:   "extract <template-type>",
+	Short: "Have the registry extract a schema from its reference project",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := registryClient()
+		if err != nil {
+			return err
+		}
+
+		entry, err := client.Extract(cmd.Context(), registry.ExtractRequest{
+			Type:  args[0],
+			Name:  registryName,
+			Codec: registryCodec,
+		})
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			return printJSON(entry)
+		}
+		logger.Info("Extracted schema on registry", "name", entry.Name, "type", entry.Type, "files", entry.Files)
+		return nil
+	},
+}
+
+func init() {
+	registryCmd.PersistentFlags().StringVar(&registryURL, "registry", "",
+		"Registry URL (defaults to the configured registry)")
+
+	registryPullCmd.Flags().StringVarP(&registryOutput, "output", "o", "", "Output file (defaults to <name>.json)")
+	registryPullCmd.Flags().StringVar(&registryDigest, "digest", "", "Expected sha256:<hex> digest of the schema")
+	registryPushCmd.Flags().StringVar(&registryName, "name", "",
+		"Name to store the schema under (defaults to the file name)")
+	registryExtractCmd.Flags().StringVar(&registryName, "name", "",
+		"Name to store the schema under (defaults to the template type)")
+	registryExtractCmd.Flags().StringVar(&registryCodec, "codec", "", "Compression codec: gzip, zstd or none")
+
+	registryCmd.AddCommand(registryListCmd)
+	registryCmd.AddCommand(registryPullCmd)
+	registryCmd.AddCommand(registryPushCmd)
+	registryCmd.AddCommand(registryExtractCmd)
+}
+
+// registryClient creates a client for --registry or the configured registry
+func registryClient() (*registry.Client, error) {
+	url := registryURL
+	if url == "" {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load configuration: %w", err)
+		}
+		url = cfg.Registry
+	}
+
+	if url == "" {
+		return nil, fmt.Errorf("no registry configured: use --registry or template-engine config set-registry <url>")
+	}
+
+	client, err := registry.NewClient(url, nil)
+	if err != nil {
+		return nil, err
+	}
+	client.SetOffline(offline)
+	client.SetToken(os.Getenv(registry.TokenEnv))
+	return client, nil
+}
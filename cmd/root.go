@@ -1,12 +1,30 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"os"
+	"os/signal"
 
+	"github.com/acheevo/template-engine/internal/config"
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/i18n"
+	"github.com/acheevo/template-engine/internal/logging"
 	"github.com/spf13/cobra"
 )
 
+var (
+	verbose bool
+	quiet   bool
+	profile string
+	offline bool
+	lang    string
+
+	// logger receives all status output from commands; results still go to stdout
+	logger = logging.New(os.Stderr, slog.LevelInfo)
+)
+
 var rootCmd = &cobra.Command{
 	Use:   "template-engine",
 	Short: "Generate projects from templates",
@@ -20,21 +38,84 @@ Quick Start:
 Advanced Usage:
   template-engine extract <source-dir> --type <template-type> [-o output.json]
   template-engine generate <template.json> --project-name <name> --github-repo <repo>
-  template-engine list [--verbose]`,
+  template-engine apply <template.json> --into <project-dir> [--only glob]
+  template-engine generate-workspace <workspace.yaml> [--output-dir dir]
+  template-engine validate <template.json>
+  template-engine inspect <template.json> [--dedupe]
+  template-engine fix-hashes <template.json>
+  template-engine schema-diff <old.json> <new.json>
+  template-engine test <template.json> [--case name]
+  template-engine bench <source-dir> --type <template-type> [--iterations n]
+  template-engine serve [--addr :8080] [--dir schemas]
+  template-engine registry list|pull|push|extract
+  template-engine push <oci-reference> <template.json>
+  template-engine pull <oci-reference> [-o template.json]
+  template-engine list [--verbose]
+  template-engine config doctor|discover|export|import|profiles [--profile name]
+  template-engine cache clear|dir
+  template-engine completion bash|zsh|fish|powershell`,
+	Version:       core.EngineVersion,
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if verbose && quiet {
+			return fmt.Errorf("--verbose and --quiet cannot be used together")
+		}
+		logger = logging.New(os.Stderr, logging.LevelFromFlags(verbose, quiet))
+		plainOutput = plainOutput || detectPlainOutput()
+		if err := i18n.SetLang(lang); err != nil {
+			return err
+		}
+		return config.SetProfile(profile)
+	},
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
+	// Cancel running extraction/generation on Ctrl+C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		printError(err)
+		stop()
 		os.Exit(1)
 	}
 }
 
 func init() {
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Show debug output")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only show warnings and errors")
+	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Print machine-readable JSON results")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "",
+		"Use the reference projects of this config profile")
+	_ = rootCmd.RegisterFlagCompletionFunc("profile", completeProfiles)
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false,
+		"Forbid network access: no git clone or fetch, registry requests or downloads (air-gapped builds)")
+	rootCmd.PersistentFlags().StringVar(&lang, "lang", "",
+		"Language of prompts and messages (en, de, es, fr, pt), detected from LC_ALL, LC_MESSAGES or LANG by default")
+	_ = rootCmd.RegisterFlagCompletionFunc("lang", completeLanguages)
+	rootCmd.PersistentFlags().BoolVar(&plainOutput, "plain", false,
+		"Print no emoji, symbols or styling (the default when output is not a terminal)")
+
 	// Add all subcommands
 	rootCmd.AddCommand(extractCmd)
 	rootCmd.AddCommand(generateCmd)
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(newCmd)
 	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(inspectCmd)
+	rootCmd.AddCommand(auditCmd)
+	rootCmd.AddCommand(benchCmd)
+	rootCmd.AddCommand(fixHashesCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(registryCmd)
+	rootCmd.AddCommand(pushCmd)
+	rootCmd.AddCommand(pullCmd)
+	rootCmd.AddCommand(testCmd)
+	rootCmd.AddCommand(schemaDiffCmd)
+	rootCmd.AddCommand(generateWorkspaceCmd)
+	rootCmd.AddCommand(applyCmd)
+	rootCmd.AddCommand(completionCmd)
+	rootCmd.AddCommand(cacheCmd)
+	rootCmd.AddCommand(schemaCmd)
 }
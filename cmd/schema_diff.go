@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var schemaDiffCmd = &cobra.Command{
+	Use:   "schema-diff <old.json> <new.json>",
+	Short: "Show what changed between two versions of a template schema",
+	Long: `Compare two versions of a template schema and report files that were added,
+removed or changed, mappings added to or removed from each file, and variables
+that were added, removed or redefined.
+
+File contents are compared after decompression, so recompressing or
+deduplicating a schema does not show up as a change.
+
+Examples:
+  template-engine schema-diff api-template-v1.json api-template-v2.json
+  template-engine schema-diff api-template-v1.json api-template-v2.json --json`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSchemaDiff(args[0], args[1])
+	},
+}
+
+func runSchemaDiff(oldFile, newFile string) error {
+	oldSchema, err := core.LoadSchemaFile(oldFile)
+	if err != nil {
+		return err
+	}
+	newSchema, err := core.LoadSchemaFile(newFile)
+	if err != nil {
+		return err
+	}
+
+	diff := core.DiffSchemas(oldSchema, newSchema)
+	if jsonOutput {
+		return printJSON(diff)
+	}
+
+	printSchemaDiff(oldFile, newFile, diff)
+	return nil
+}
+
+// printSchemaDiff prints a human-readable schema diff
+func printSchemaDiff(oldFile, newFile string, diff *core.SchemaDiff) {
+	fmt.Printf("Comparing %s (%s) with %s (%s)\n", oldFile, diff.OldVersion, newFile, diff.NewVersion)
+	fmt.Println()
+
+	if diff.Empty() {
+		fmt.Println("No changes")
+		return
+	}
+
+	for _, path := range diff.AddedFiles {
+		fmt.Printf("  + %s\n", path)
+	}
+	for _, path := range diff.RemovedFiles {
+		fmt.Printf("  - %s\n", path)
+	}
+	for _, change := range diff.ChangedFiles {
+		fmt.Printf("  ~ %s\n", change.Path)
+		if change.ContentChanged {
+			fmt.Println("      content changed")
+		}
+		if change.TemplateChanged {
+			fmt.Println("      templating toggled")
+		}
+		for _, mapping := range change.AddedMappings {
+			fmt.Printf("      + mapping %q -> %q\n", mapping.Find, mapping.Replace)
+		}
+		for _, mapping := range change.RemovedMappings {
+			fmt.Printf("      - mapping %q -> %q\n", mapping.Find, mapping.Replace)
+		}
+	}
+
+	if len(diff.Variables) > 0 {
+		fmt.Println()
+		fmt.Println("Variables:")
+		for _, change := range diff.Variables {
+			fmt.Printf("  %-7s %s\n", change.Change, change.Name)
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("%d added, %d removed, %d changed file(s), %d variable change(s)\n",
+		len(diff.AddedFiles), len(diff.RemovedFiles), len(diff.ChangedFiles), len(diff.Variables))
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/acheevo/template-engine/internal/config"
+	"github.com/acheevo/template-engine/internal/registry"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveAddr  string
+	serveDir   string
+	serveToken string
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run a shared template registry server",
+	Long: `Run an HTTP template registry backed by a local directory of schemas,
+so a team can share templates and point clients at it with
+"template-engine config set-registry <url>".
+
+Endpoints:
+  GET  /schemas         list schemas
+  GET  /schemas/{name}  fetch a schema
+  PUT  /schemas/{name}  upload a schema
+  POST /extract         extract a schema from a reference project configured on the server
+  POST /generate        generate a project from a schema and return it as a tar.gz or zip
+
+A generate request names the schema and the project variables, for example
+  {"schema": "go-api", "project_name": "My API", "github_repo": "org/my-api", "format": "zip"}
+and may include "author", "description", the custom "variables" the schema
+declares ({"Team": "billing"}), optional features to "enable" or "disable"
+and "env" values for a .env file. Templates cannot read the server's
+environment: their env function returns an empty value.
+
+Extraction only reads the reference projects from the server's own configuration
+("template-engine config list"), never arbitrary paths sent by clients.
+
+The server only listens on localhost by default. Before exposing it to the
+team with --addr, set a token in TEMPLATE_ENGINE_REGISTRY_TOKEN (or --token):
+uploads and extraction then need "Authorization: Bearer <token>", which
+clients send from the same environment variable. Without one, anyone reaching
+the server can replace the schemas everyone pulls.
+
+Examples:
+  template-engine serve --dir ./schemas
+  TEMPLATE_ENGINE_REGISTRY_TOKEN=... template-engine serve --addr :9000 --dir /var/lib/template-engine`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runServe(cmd.Context())
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:8080", "Address to listen on")
+	serveCmd.Flags().StringVar(&serveToken, "token", "",
+		"Bearer token uploads and extraction require (default $TEMPLATE_ENGINE_REGISTRY_TOKEN)")
+	serveCmd.Flags().StringVar(&serveDir, "dir", "schemas", "Directory where schemas are stored")
+}
+
+func runServe(ctx context.Context) error {
+	store, err := registry.NewStore(serveDir)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	references, err := cfg.ReferencePaths()
+	if err != nil {
+		return err
+	}
+
+	token := serveToken
+	if token == "" {
+		token = os.Getenv(registry.TokenEnv)
+	}
+	if token == "" && !isLoopback(serveAddr) {
+		logger.Warn("Registry accepts uploads from anyone who can reach it, set --token or "+registry.TokenEnv,
+			"addr", serveAddr)
+	}
+	handler := registry.NewServer(store, references, logger)
+	handler.SetToken(token)
+
+	server := &http.Server{
+		Addr:              serveAddr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// Shut down gracefully on Ctrl+C
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx) // ListenAndServe reports the outcome
+	}()
+
+	logger.Info("Serving template registry", "addr", serveAddr, "dir", serveDir)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	logger.Info("Registry server stopped")
+	return nil
+}
+
+// isLoopback reports whether addr only listens on the loopback interface
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/acheevo/template-engine/internal/config"
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/harness"
+	"github.com/spf13/cobra"
+)
+
+var (
+	testCase    string
+	testKeep    bool
+	testTimeout time.Duration
+	testAllow   bool
+)
+
+var testCmd = &cobra.Command{
+	Use:   "test <schema.json>",
+	Short: "Generate sample projects from a schema and verify they build",
+	Long: `Generate a project for every entry of the schema's test_matrix into a
+temporary directory, run the schema's post_generate hooks and then its
+verification commands (test_commands, or the commands of the entry), and
+report pass/fail per entry.
+
+Schemas without a test matrix are tested once with sample variables.
+
+Hooks and commands are restricted by the binary whitelist in hooks.json, and
+schemas pulled from a registry are only tested with --allow-hooks.
+
+Example schema fields:
+  "test_matrix": [
+    {"name": "basic", "variables": {"ProjectName": "My API", "GitHubRepo": "org/my-api"}},
+    {"name": "long-name", "variables": {"ProjectName": "My Very Long Service Name"}}
+  ],
+  "test_commands": ["go build ./...", "go vet ./..."]
+
+Examples:
+  template-engine test api-template.json
+  template-engine test api-template.json --case basic --keep`,
+	Args: cobra.ExactArgs(1),
+	// Failing test cases are not usage errors
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		schema, err := core.LoadSchemaFile(args[0])
+		if err != nil {
+			return err
+		}
+
+		policy, err := config.LoadHookPolicy()
+		if err != nil {
+			return err
+		}
+
+		results, err := harness.Run(cmd.Context(), logger, schema, harness.Options{
+			Case:            testCase,
+			KeepDirs:        testKeep,
+			CommandTimeout:  testTimeout,
+			AllowRemote:     testAllow,
+			AllowedBinaries: policy.AllowedBinaries,
+		})
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			if err := printJSON(results); err != nil {
+				return err
+			}
+		} else {
+			printTestResults(results)
+		}
+
+		if !harness.Passed(results) {
+			return fmt.Errorf("template tests failed")
+		}
+		return nil
+	},
+}
+
+func init() {
+	testCmd.Flags().StringVar(&testCase, "case", "", "Only run the test case with this name")
+	testCmd.Flags().BoolVar(&testKeep, "keep", false, "Keep the generated projects for inspection")
+	testCmd.Flags().BoolVar(&testAllow, "allow-hooks", false,
+		"Run the hooks and commands of a schema pulled from a registry (review them first)")
+	testCmd.Flags().DurationVar(&testTimeout, "timeout", 10*time.Minute, "Timeout for each command (0 for none)")
+}
+
+// printTestResults shows the outcome of every test case, with the output of failed commands
+func printTestResults(results []harness.CaseResult) {
+	passed := 0
+	for _, result := range results {
+		status := "FAIL"
+		if result.Passed {
+			status = "PASS"
+			passed++
+		}
+		fmt.Printf("%s  %s (%s)\n", status, result.Name, time.Duration(result.DurationMS)*time.Millisecond)
+		if result.Dir != "" {
+			fmt.Printf("      dir: %s\n", result.Dir)
+		}
+		if !result.Passed {
+			fmt.Printf("      %s\n", result.Error)
+			for _, command := range result.Commands {
+				if command.ExitCode != 0 && command.Output != "" {
+					fmt.Println(command.Output)
+				}
+			}
+		}
+	}
+	fmt.Printf("\n%d/%d test case(s) passed\n", passed, len(results))
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate <schema.json>",
+	Short: "Validate a template schema file",
+	Long: `Validate a template schema for integrity and completeness.
+
+Besides the structural checks run before every generation, this verifies
+file hashes, reports mappings whose find string is not present in the file
+content, and flags template variables that the schema does not define.
+
+Errors make the command fail; warnings are reported but do not.
+
+Examples:
+  template-engine validate frontend-template.json
+  template-engine validate api-template.json --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runValidate(args[0])
+	},
+}
+
+func runValidate(schemaFile string) error {
+	schema, err := core.LoadSchemaFile(schemaFile)
+	if err != nil {
+		return err
+	}
+
+	report := core.CheckSchema(schema)
+
+	if jsonOutput {
+		if err := printJSON(report); err != nil {
+			return err
+		}
+	} else {
+		printReport("Validating "+schemaFile, report)
+	}
+
+	if report.HasErrors() {
+		return fmt.Errorf("schema %s has %d error(s)", schemaFile, len(report.Errors()))
+	}
+
+	return nil
+}
+
+// printReport prints a human-readable validation report under title
+func printReport(title string, report *core.Report) {
+	fmt.Println(title)
+	fmt.Println()
+
+	for _, issue := range report.Issues {
+		location := ""
+		if issue.File != "" {
+			location = issue.File + ": "
+		}
+		fmt.Printf("  %-7s %s%s\n", issue.Severity, location, issue.Message)
+	}
+
+	if len(report.Issues) > 0 {
+		fmt.Println()
+	}
+	fmt.Printf("%d error(s), %d warning(s)\n", len(report.Errors()), len(report.Warnings()))
+}
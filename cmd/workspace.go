@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"github.com/acheevo/template-engine/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+var (
+	workspaceOutputDir string
+	workspaceNoVerify  bool
+)
+
+var generateWorkspaceCmd = &cobra.Command{
+	Use:   "generate-workspace <workspace.yaml>",
+	Short: "Generate several projects into one monorepo",
+	Long: `Generate every project listed in a workspace spec, each into its own
+subdirectory of --output-dir, so a whole monorepo (api + frontend + infra)
+is scaffolded in one command.
+
+Variables listed at the top of the spec are shared by every project and can
+be overridden per project. Schema paths are relative to the workspace file.
+
+  name: acme-platform
+  variables:
+    GitHubRepo: acme/platform
+    Author: Jane Doe
+  projects:
+    - schema: schemas/api.json
+      path: services/api
+      variables:
+        ProjectName: Acme API
+    - schema: schemas/frontend.json
+      path: web
+      variables:
+        ProjectName: Acme Web
+
+Examples:
+  template-engine generate-workspace workspace.yaml --output-dir acme-platform`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spec, err := workspace.Load(args[0])
+		if err != nil {
+			return err
+		}
+
+		result, err := workspace.Run(cmd.Context(), logger, spec, workspaceOutputDir, workspaceNoVerify)
+		if err != nil {
+			return err
+		}
+
+		logger.Info("Workspace generated", "output", result.OutputDir, "projects", len(result.Projects))
+		if jsonOutput {
+			return printJSON(result)
+		}
+		return nil
+	},
+}
+
+func init() {
+	generateWorkspaceCmd.Flags().StringVar(&workspaceOutputDir, "output-dir", "./",
+		"Root directory of the workspace, projects are generated below it")
+	generateWorkspaceCmd.Flags().BoolVar(&workspaceNoVerify, "no-verify", false,
+		"Skip file hash verification (for schemas edited by hand, see fix-hashes)")
+}
@@ -0,0 +1,134 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"time"
+)
+
+// Format is an archive format generated projects can be packaged as
+type Format string
+
+const (
+	FormatTarGz Format = "tar.gz"
+	FormatZip   Format = "zip"
+)
+
+// Formats lists the supported archive formats
+var Formats = []Format{FormatTarGz, FormatZip}
+
+// ParseFormat returns the format with the given name
+func ParseFormat(name string) (Format, error) {
+	switch name {
+	case "tar.gz", "tgz":
+		return FormatTarGz, nil
+	case "zip":
+		return FormatZip, nil
+	default:
+		return "", fmt.Errorf("unknown archive format %q, expected one of %v", name, Formats)
+	}
+}
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	if f == FormatZip {
+		return "application/zip"
+	}
+	return "application/gzip"
+}
+
+// Extension returns the file name extension of the format, including the leading dot
+func (f Format) Extension() string {
+	return "." + string(f)
+}
+
+// Writer streams files into an archive. Nothing is written to the underlying
+// writer before the first file, so callers can still report errors that occur earlier.
+type Writer struct {
+	format  Format
+	gz      *gzip.Writer
+	tw      *tar.Writer
+	zw      *zip.Writer
+	modTime time.Time
+}
+
+// NewWriter creates an archive writer in the given format. Close must be called to finish the archive.
+func NewWriter(w io.Writer, format Format) (*Writer, error) {
+	archive := &Writer{format: format, modTime: time.Now()}
+
+	switch format {
+	case FormatTarGz:
+		archive.gz = gzip.NewWriter(w)
+		archive.tw = tar.NewWriter(archive.gz)
+	case FormatZip:
+		archive.zw = zip.NewWriter(w)
+	default:
+		return nil, fmt.Errorf("unknown archive format %q", format)
+	}
+
+	return archive, nil
+}
+
+// WriteFile adds a file to the archive. name is a slash-separated relative path.
+func (w *Writer) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if w.tw != nil {
+		header := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Mode:     int64(perm.Perm()),
+			Size:     int64(len(data)),
+			ModTime:  w.modTime,
+		}
+		if err := w.tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err := w.tw.Write(data)
+		return err
+	}
+
+	header := &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: w.modTime}
+	header.SetMode(perm.Perm())
+	entry, err := w.zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = entry.Write(data)
+	return err
+}
+
+// Symlink adds a symbolic link named name pointing to target to the archive
+func (w *Writer) Symlink(name, target string) error {
+	if w.tw != nil {
+		return w.tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeSymlink,
+			Name:     name,
+			Linkname: target,
+			Mode:     0o777,
+			ModTime:  w.modTime,
+		})
+	}
+	// Zip stores the target as the content of an entry with the symlink mode, as Info-ZIP does
+	header := &zip.FileHeader{Name: name, Method: zip.Store, Modified: w.modTime}
+	header.SetMode(fs.ModeSymlink | 0o777)
+	entry, err := w.zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(entry, target)
+	return err
+}
+
+// Close finishes the archive. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if w.tw != nil {
+		if err := w.tw.Close(); err != nil {
+			return err
+		}
+		return w.gz.Close()
+	}
+	return w.zw.Close()
+}
@@ -0,0 +1,121 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"testing"
+)
+
+// writeTestArchive writes a few files, including a private .env, and a symlink in the given format
+func writeTestArchive(t *testing.T, format Format) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, format)
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("NewWriter() wrote %d bytes before the first file", buf.Len())
+	}
+
+	files := []struct {
+		name    string
+		content string
+		perm    fs.FileMode
+	}{
+		{"README.md", "# App\n", 0o644},
+		{"src/main.go", "package main\n", 0o644},
+		{".env", "SECRET=x\n", 0o600},
+	}
+	for _, file := range files {
+		if err := w.WriteFile(file.name, []byte(file.content), file.perm); err != nil {
+			t.Fatalf("WriteFile(%s) error = %v", file.name, err)
+		}
+	}
+	if err := w.Symlink("docs/README.md", "../README.md"); err != nil {
+		t.Fatalf("Symlink() error = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	return &buf
+}
+
+func TestWriterTarGz(t *testing.T) {
+	buf := writeTestArchive(t, FormatTarGz)
+
+	gz, err := gzip.NewReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+
+	got := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(tr)
+		got[header.Name] = string(content)
+		if header.Name == ".env" && header.Mode&0o777 != 0o600 {
+			t.Errorf(".env mode = %o, want 600", header.Mode&0o777)
+		}
+		if header.Name == "docs/README.md" && (header.Typeflag != tar.TypeSymlink || header.Linkname != "../README.md") {
+			t.Errorf("docs/README.md = %+v, want a symlink to ../README.md", header)
+		}
+	}
+
+	if len(got) != 4 || got["src/main.go"] != "package main\n" {
+		t.Errorf("tar.gz entries = %v", got)
+	}
+}
+
+func TestWriterZip(t *testing.T) {
+	buf := writeTestArchive(t, FormatZip)
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]string{}
+	for _, file := range zr.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		got[file.Name] = string(content)
+		if file.Name == ".env" && file.Mode().Perm() != 0o600 {
+			t.Errorf(".env mode = %v, want 0600", file.Mode().Perm())
+		}
+		if file.Name == "docs/README.md" && (file.Mode()&fs.ModeSymlink == 0 || got[file.Name] != "../README.md") {
+			t.Errorf("docs/README.md = %v %q, want a symlink to ../README.md", file.Mode(), got[file.Name])
+		}
+	}
+
+	if len(got) != 4 || got["README.md"] != "# App\n" {
+		t.Errorf("zip entries = %v", got)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if format, err := ParseFormat("tgz"); err != nil || format != FormatTarGz {
+		t.Errorf("ParseFormat(tgz) = %q, %v", format, err)
+	}
+	if _, err := ParseFormat("rar"); err == nil {
+		t.Error("ParseFormat(rar) should fail")
+	}
+}
@@ -0,0 +1,243 @@
+// Package bench measures how fast template schemas are extracted from a reference project and
+// generated again, so regressions in walking, compression and rendering are noticed
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"time"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/generate"
+)
+
+// Stages measured by Run
+const (
+	StageExtract  = "extract"
+	StageGenerate = "generate"
+)
+
+// Options configures a benchmark run
+type Options struct {
+	SourceDir    string
+	TemplateType string
+	// Iterations runs each stage this many times, once when zero
+	Iterations int
+	// Codec compresses the extracted file contents, gzip when empty
+	Codec core.Codec
+	// CPUProfile writes a pprof CPU profile of the whole run to this file when set
+	CPUProfile string
+	// MemProfile writes a pprof heap profile to this file once the run is over when set
+	MemProfile string
+}
+
+// Stage reports the measurements of one stage, averaged over its iterations
+type Stage struct {
+	Name       string  `json:"name"`
+	Iterations int     `json:"iterations"`
+	MinMS      float64 `json:"min_ms"`
+	MeanMS     float64 `json:"mean_ms"`
+	MaxMS      float64 `json:"max_ms"`
+	// MBPerSec is the size of the project files processed per second, at the mean duration
+	MBPerSec    float64 `json:"mb_per_sec"`
+	FilesPerSec float64 `json:"files_per_sec"`
+	// AllocBytes and Allocs are the memory allocated by one iteration
+	AllocBytes uint64 `json:"alloc_bytes"`
+	Allocs     uint64 `json:"allocs"`
+}
+
+// Result reports a benchmark run
+type Result struct {
+	Source string `json:"source"`
+	Type   string `json:"type"`
+	Files  int    `json:"files"`
+	// Bytes is the size of the project files, SchemaBytes that of the encoded schema
+	Bytes       int64   `json:"bytes"`
+	SchemaBytes int64   `json:"schema_bytes"`
+	Stages      []Stage `json:"stages"`
+}
+
+// benchVariables are the variables the benchmark generates with
+var benchVariables = core.TemplateVariables{ProjectName: "Bench Project", GitHubRepo: "bench/bench-project"}
+
+// Run extracts the reference project opts.SourceDir with its template type, then generates the
+// schema in memory, each opts.Iterations times
+func Run(ctx context.Context, logger *slog.Logger, opts Options) (*Result, error) {
+	template, err := core.GetTemplate(opts.TemplateType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template type: %w", err)
+	}
+	if info, err := os.Stat(opts.SourceDir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("source directory does not exist: %s", opts.SourceDir)
+	}
+	iterations := max(opts.Iterations, 1)
+
+	if opts.CPUProfile != "" {
+		file, err := os.Create(opts.CPUProfile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create CPU profile: %w", err)
+		}
+		defer file.Close()
+		if err := pprof.StartCPUProfile(file); err != nil {
+			return nil, fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+		defer pprof.StopCPUProfile()
+	}
+
+	var schema *core.TemplateSchema
+	extractOpts := core.ExtractOptions{Codec: opts.Codec}
+	extractStage, err := measure(ctx, StageExtract, iterations, func() error {
+		schema, err = template.ExtractFS(ctx, core.DirFS(opts.SourceDir), extractOpts)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract template: %w", err)
+	}
+	logger.Info("Measured extraction", "files", len(schema.Files), "mean_ms", extractStage.MeanMS)
+
+	encoded, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{
+		Source:      opts.SourceDir,
+		Type:        schema.Type,
+		Files:       len(schema.Files),
+		Bytes:       projectSize(schema),
+		SchemaBytes: int64(len(encoded)),
+	}
+
+	variables := benchVariables
+	variables.Custom = requiredVariables(schema)
+	generateStage, err := measure(ctx, StageGenerate, iterations, func() error {
+		generator := generate.NewGeneratorFromSchema(schema, variables, "")
+		generator.SetLogger(logger)
+		generator.SetOutput(discardOutput{})
+		return generator.Generate(ctx)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate project: %w", err)
+	}
+	logger.Info("Measured generation", "files", len(schema.Files), "mean_ms", generateStage.MeanMS)
+
+	for _, stage := range []*Stage{&extractStage, &generateStage} {
+		if seconds := stage.MeanMS / 1000; seconds > 0 {
+			stage.MBPerSec = float64(result.Bytes) / (1 << 20) / seconds
+			stage.FilesPerSec = float64(result.Files) / seconds
+		}
+	}
+	result.Stages = []Stage{extractStage, generateStage}
+
+	if opts.MemProfile != "" {
+		if err := writeHeapProfile(opts.MemProfile); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// measure runs fn iterations times, timing each run and counting the memory it allocates
+func measure(ctx context.Context, name string, iterations int, fn func() error) (Stage, error) {
+	stage := Stage{Name: name, Iterations: iterations}
+	var total time.Duration
+	var before, after runtime.MemStats
+
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	for i := 0; i < iterations; i++ {
+		if err := ctx.Err(); err != nil {
+			return stage, err
+		}
+		start := time.Now()
+		if err := fn(); err != nil {
+			return stage, err
+		}
+		elapsed := time.Since(start)
+		total += elapsed
+
+		ms := float64(elapsed) / float64(time.Millisecond)
+		if i == 0 || ms < stage.MinMS {
+			stage.MinMS = ms
+		}
+		stage.MaxMS = max(stage.MaxMS, ms)
+	}
+	runtime.ReadMemStats(&after)
+
+	stage.MeanMS = float64(total) / float64(time.Millisecond) / float64(iterations)
+	stage.AllocBytes = (after.TotalAlloc - before.TotalAlloc) / uint64(iterations)
+	stage.Allocs = (after.Mallocs - before.Mallocs) / uint64(iterations)
+	return stage, nil
+}
+
+// projectSize returns the size of the files of a schema as they were in the project
+func projectSize(schema *core.TemplateSchema) int64 {
+	var size int64
+	for _, file := range schema.Files {
+		size += file.Size
+	}
+	return size
+}
+
+// requiredVariables returns placeholder values for the required variables of a schema lacking
+// a default, so generation can run without user input
+func requiredVariables(schema *core.TemplateSchema) map[string]string {
+	values := map[string]string{}
+	for name, variable := range schema.Variables {
+		if !variable.Required || variable.Default != "" {
+			continue
+		}
+		switch variable.Type {
+		case core.VariableTypeMap:
+			values[name] = "bench=bench"
+		default:
+			values[name] = "bench"
+		}
+	}
+	for _, name := range core.BuiltinVariables {
+		delete(values, name)
+	}
+	return values
+}
+
+// writeHeapProfile writes a pprof heap profile to path, after a garbage collection so it shows
+// the memory still in use
+func writeHeapProfile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create memory profile: %w", err)
+	}
+	defer file.Close()
+
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(file); err != nil {
+		return fmt.Errorf("failed to write memory profile: %w", err)
+	}
+	return nil
+}
+
+// discardOutput drops the generated files, so generation is measured without the disk
+type discardOutput struct{}
+
+func (discardOutput) WriteFile(string, []byte, fs.FileMode) error {
+	return nil
+}
+
+func (discardOutput) CreateFile(string, fs.FileMode) (io.WriteCloser, error) {
+	return nopCloser{io.Discard}, nil
+}
+
+// nopCloser is a writer with a Close method doing nothing
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
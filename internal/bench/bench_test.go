@@ -0,0 +1,74 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/acheevo/template-engine/internal/logging"
+	_ "github.com/acheevo/template-engine/internal/templates" // Register template types
+)
+
+// writeProject writes a generic reference project of files source files below dir
+func writeProject(t testing.TB, dir string, files int) {
+	t.Helper()
+
+	for i := 0; i < files; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("pkg%d", i%10), fmt.Sprintf("file%d.go", i))
+		content := "// Package __PROJECT_NAME_KEBAB__\n" + strings.Repeat("var value = 42 // {{ .Values.x }}\n", 50)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	writeProject(t, dir, 20)
+	memProfile := filepath.Join(t.TempDir(), "mem.out")
+
+	result, err := Run(context.Background(), logging.Discard(), Options{
+		SourceDir: dir, TemplateType: "generic", Iterations: 2, MemProfile: memProfile,
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if result.Files != 20 || result.Bytes == 0 || result.SchemaBytes == 0 {
+		t.Errorf("Run() result = %+v", result)
+	}
+	if len(result.Stages) != 2 || result.Stages[0].Name != StageExtract || result.Stages[1].Name != StageGenerate {
+		t.Fatalf("Run() stages = %+v", result.Stages)
+	}
+	for _, stage := range result.Stages {
+		if stage.Iterations != 2 || stage.MeanMS <= 0 || stage.MinMS > stage.MaxMS || stage.AllocBytes == 0 {
+			t.Errorf("stage %s = %+v", stage.Name, stage)
+		}
+	}
+	if info, err := os.Stat(memProfile); err != nil || info.Size() == 0 {
+		t.Errorf("memory profile not written: %v", err)
+	}
+
+	if _, err := Run(context.Background(), logging.Discard(), Options{SourceDir: dir, TemplateType: "cobol"}); err == nil {
+		t.Error("Run() with an unknown template type should fail")
+	}
+}
+
+func BenchmarkRun(b *testing.B) {
+	dir := b.TempDir()
+	writeProject(b, dir, 200)
+
+	opts := Options{SourceDir: dir, TemplateType: "generic"}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Run(context.Background(), logging.Discard(), opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
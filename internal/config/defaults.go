@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// GitHubOwnerVariable names the default owner of GitHub repos given without one, e.g. with
+// GitHubOwner "platform-team" the repo "billing-api" becomes "platform-team/billing-api"
+const GitHubOwnerVariable = "GitHubOwner"
+
+// variableName matches the names of template variables
+var variableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SetDefault stores the value new and generate use for a variable of templateType projects when
+// none is given, removing the default when value is empty
+func (c *ReferenceConfig) SetDefault(templateType, name, value string) error {
+	if templateType == "" {
+		return fmt.Errorf("template type is required")
+	}
+	if !variableName.MatchString(name) {
+		return fmt.Errorf("invalid variable name %q", name)
+	}
+	if name == "ProjectName" {
+		return fmt.Errorf("ProjectName cannot have a default")
+	}
+
+	if value == "" {
+		delete(c.Defaults[templateType], name)
+		if len(c.Defaults[templateType]) == 0 {
+			delete(c.Defaults, templateType)
+		}
+		return nil
+	}
+
+	if c.Defaults == nil {
+		c.Defaults = make(map[string]map[string]string)
+	}
+	if c.Defaults[templateType] == nil {
+		c.Defaults[templateType] = make(map[string]string)
+	}
+	c.Defaults[templateType][name] = value
+	return nil
+}
+
+// VariableDefaults returns a copy of the variable defaults of templateType
+func (c *ReferenceConfig) VariableDefaults(templateType string) map[string]string {
+	defaults := make(map[string]string, len(c.Defaults[templateType]))
+	for name, value := range c.Defaults[templateType] {
+		defaults[name] = value
+	}
+	return defaults
+}
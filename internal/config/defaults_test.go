@@ -0,0 +1,40 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSetDefault(t *testing.T) {
+	cfg := DefaultReferenceConfig()
+
+	if err := cfg.SetDefault("go-api", "Author", "Platform Team"); err != nil {
+		t.Fatalf("SetDefault() error = %v", err)
+	}
+	if err := cfg.SetDefault("go-api", GitHubOwnerVariable, "acme"); err != nil {
+		t.Fatalf("SetDefault() error = %v", err)
+	}
+	want := map[string]string{"Author": "Platform Team", GitHubOwnerVariable: "acme"}
+	if got := cfg.VariableDefaults("go-api"); !reflect.DeepEqual(got, want) {
+		t.Errorf("VariableDefaults() = %v, want %v", got, want)
+	}
+
+	// The returned defaults are a copy
+	cfg.VariableDefaults("go-api")["Author"] = "Someone"
+	if cfg.Defaults["go-api"]["Author"] != "Platform Team" {
+		t.Error("VariableDefaults() should not expose the stored map")
+	}
+
+	// An empty value removes the default, and the template type once none is left
+	_ = cfg.SetDefault("go-api", "Author", "")
+	_ = cfg.SetDefault("go-api", GitHubOwnerVariable, "")
+	if _, exists := cfg.Defaults["go-api"]; exists {
+		t.Errorf("Expected no defaults left, got %v", cfg.Defaults)
+	}
+
+	for _, name := range []string{"", "Team Name", "ProjectName"} {
+		if err := cfg.SetDefault("go-api", name, "x"); err == nil {
+			t.Errorf("SetDefault(%q) should fail", name)
+		}
+	}
+}
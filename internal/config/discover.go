@@ -0,0 +1,82 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+// DiscoveredReference is a directory that looks like a reference project of a template type
+type DiscoveredReference struct {
+	Type string `json:"type"`
+	Path string `json:"path"`
+	// Existing is the path currently configured for the template type, empty when there is none
+	Existing string `json:"existing,omitempty"`
+}
+
+// Configured reports whether the directory already is the reference of its template type
+func (d DiscoveredReference) Configured() bool {
+	return d.Existing == d.Path
+}
+
+// Available reports whether the directory can be added without displacing a usable reference
+// project: the template type has none yet, or its path does not exist (like the sibling
+// directories of the default configuration)
+func (d DiscoveredReference) Available() bool {
+	if d.Existing == "" {
+		return true
+	}
+	_, err := os.Stat(d.Existing)
+	return os.IsNotExist(err)
+}
+
+// Discover scans dir and its immediate subdirectories for reference projects, recognized by the
+// markers of the registered template types (see core.ReferenceMarkers). A directory matching
+// several template types is reported once for each. Paths are absolute.
+func (c *ReferenceConfig) Discover(dir string) ([]DiscoveredReference, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workspace directory: %w", err)
+	}
+
+	candidates := []string{root}
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") && entry.Name() != "node_modules" {
+			candidates = append(candidates, filepath.Join(root, entry.Name()))
+		}
+	}
+
+	types := core.ListTemplates()
+	sort.Strings(types)
+
+	discovered := []DiscoveredReference{}
+	for _, path := range candidates {
+		fsys := os.DirFS(path)
+		for _, templateType := range types {
+			registered, err := core.GetTemplate(templateType)
+			if err != nil {
+				continue
+			}
+			markers := core.TypeMarkers(registered)
+			if len(markers) == 0 || len(core.MissingMarkers(fsys, markers)) > 0 {
+				continue
+			}
+
+			existing := ""
+			if _, exists := c.References[templateType]; exists {
+				existing, _ = c.GetReferencePath(templateType)
+			}
+			discovered = append(discovered, DiscoveredReference{Type: templateType, Path: path, Existing: existing})
+		}
+	}
+	return discovered, nil
+}
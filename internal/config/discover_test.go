@@ -0,0 +1,51 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDiscover(t *testing.T) {
+	dir := t.TempDir()
+	for _, path := range []string{
+		"api/go.mod", "api/cmd/api/main.go",
+		"web/package.json", "web/vite.config.ts",
+		"scripts/run.sh",
+		"node_modules/vite/package.json", "node_modules/vite/vite.config.ts",
+	} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, path), []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &ReferenceConfig{References: map[string]ReferenceProject{
+		"frontend": {Path: filepath.Join(dir, "web")},
+		"go-api":   {Path: filepath.Join(dir, "old-api")},
+	}}
+
+	discovered, err := cfg.Discover(dir)
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+
+	want := []DiscoveredReference{
+		{Type: "go-api", Path: filepath.Join(dir, "api"), Existing: filepath.Join(dir, "old-api")},
+		{Type: "frontend", Path: filepath.Join(dir, "web"), Existing: filepath.Join(dir, "web")},
+	}
+	if !reflect.DeepEqual(discovered, want) {
+		t.Fatalf("Discover() = %+v, want %+v", discovered, want)
+	}
+
+	// The configured go-api reference does not exist, so the discovered one may replace it
+	if discovered[0].Configured() || !discovered[0].Available() {
+		t.Errorf("Expected the api project to be available, got %+v", discovered[0])
+	}
+	if !discovered[1].Configured() || discovered[1].Available() {
+		t.Errorf("Expected the web project to be configured already, got %+v", discovered[1])
+	}
+}
@@ -1,15 +1,24 @@
 package config
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
+
+	"github.com/acheevo/template-engine/internal/gitcache"
 )
 
 // ReferenceConfig defines where reference projects are located
 type ReferenceConfig struct {
 	References map[string]ReferenceProject `json:"references"`
+	// Registry is the URL of a shared template registry (see `template-engine serve`)
+	Registry string `json:"registry,omitempty"`
+	// Defaults holds variable defaults by template type, see SetDefault
+	Defaults map[string]map[string]string `json:"defaults,omitempty"`
 }
 
 // ReferenceProject defines a reference project location and metadata
@@ -17,6 +26,11 @@ type ReferenceProject struct {
 	Path        string `json:"path"`
 	Description string `json:"description"`
 	Version     string `json:"version,omitempty"`
+	// GitURL makes the reference a remote repository, checked out into the user cache
+	// directory instead of being read from Path
+	GitURL string `json:"git_url,omitempty"`
+	// Ref pins a remote reference to a tag, branch or commit, the default branch when empty
+	Ref string `json:"ref,omitempty"`
 }
 
 // DefaultReferenceConfig returns the default configuration
@@ -84,13 +98,64 @@ func SaveConfig(config *ReferenceConfig) error {
 	return nil
 }
 
-// GetReferencePath returns the path to a reference project
+// ParseConfig parses a reference config, such as one written by `config export`. Unlike
+// LoadConfig it reports malformed data instead of falling back to the defaults.
+func ParseConfig(data []byte) (*ReferenceConfig, error) {
+	var config ReferenceConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid reference config: %w", err)
+	}
+
+	for templateType, ref := range config.References {
+		if ref.Path == "" && ref.GitURL == "" {
+			return nil, fmt.Errorf("reference project %q has no path or git URL", templateType)
+		}
+	}
+	return &config, nil
+}
+
+// Merge adds the reference projects of other, replacing those of the same template type, and
+// takes over its registry when set and its variable defaults. It returns the template types that
+// were added or replaced.
+func (c *ReferenceConfig) Merge(other *ReferenceConfig) (added, replaced []string) {
+	if c.References == nil {
+		c.References = make(map[string]ReferenceProject)
+	}
+
+	for templateType, ref := range other.References {
+		if _, exists := c.References[templateType]; exists {
+			replaced = append(replaced, templateType)
+		} else {
+			added = append(added, templateType)
+		}
+		c.References[templateType] = ref
+	}
+	if other.Registry != "" {
+		c.Registry = other.Registry
+	}
+	for templateType, defaults := range other.Defaults {
+		for name, value := range defaults {
+			_ = c.SetDefault(templateType, name, value) // Invalid names are skipped
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(replaced)
+	return added, replaced
+}
+
+// GetReferencePath returns the path to a reference project, for a remote one the directory of
+// its cached checkout (see CheckoutReference)
 func (c *ReferenceConfig) GetReferencePath(templateType string) (string, error) {
 	ref, exists := c.References[templateType]
 	if !exists {
 		return "", fmt.Errorf("unknown template type: %s", templateType)
 	}
 
+	if ref.GitURL != "" {
+		return gitcache.Dir(ref.GitURL)
+	}
+
 	// Convert relative paths to absolute
 	if !filepath.IsAbs(ref.Path) {
 		wd, err := os.Getwd()
@@ -103,6 +168,32 @@ func (c *ReferenceConfig) GetReferencePath(templateType string) (string, error)
 	return ref.Path, nil
 }
 
+// CheckoutReference returns the path to a reference project like GetReferencePath, first
+// cloning or updating the cached checkout of a remote one at its pinned ref. In offline mode
+// the cached checkout is used as is (see gitcache.Checkout).
+func (c *ReferenceConfig) CheckoutReference(ctx context.Context, logger *slog.Logger,
+	templateType string, offline bool,
+) (string, error) {
+	ref, exists := c.References[templateType]
+	if !exists || ref.GitURL == "" {
+		return c.GetReferencePath(templateType)
+	}
+	return gitcache.Checkout(ctx, logger, ref.GitURL, ref.Ref, offline)
+}
+
+// ReferencePaths returns the absolute path of every configured reference project, keyed by template type
+func (c *ReferenceConfig) ReferencePaths() (map[string]string, error) {
+	paths := make(map[string]string, len(c.References))
+	for templateType := range c.References {
+		path, err := c.GetReferencePath(templateType)
+		if err != nil {
+			return nil, err
+		}
+		paths[templateType] = path
+	}
+	return paths, nil
+}
+
 // ListTemplateTypes returns all configured template types
 func (c *ReferenceConfig) ListTemplateTypes() []string {
 	var types []string
@@ -124,17 +215,42 @@ func (c *ReferenceConfig) AddReference(templateType, path, description string) {
 	}
 }
 
-// getConfigPath returns the path to the config file
+// AddGitReference adds a reference project checked out from a remote repository at ref
+func (c *ReferenceConfig) AddGitReference(templateType, url, ref, description string) {
+	if c.References == nil {
+		c.References = make(map[string]ReferenceProject)
+	}
+
+	c.References[templateType] = ReferenceProject{
+		GitURL:      url,
+		Ref:         ref,
+		Description: description,
+	}
+}
+
+// getConfigPath returns the path to the config file of the active profile
 func getConfigPath() string {
-	// Try to use XDG config directory or fallback to home
+	configDir, err := getConfigDir()
+	if err != nil {
+		return ".template-engine.json" // Fallback to current directory
+	}
+	if profile != "" {
+		return filepath.Join(configDir, "profiles", profile+".json")
+	}
+	return filepath.Join(configDir, "references.json")
+}
+
+// getConfigDir returns the template-engine config directory, using the XDG config
+// directory or falling back to ~/.config
+func getConfigDir() (string, error) {
 	configDir := os.Getenv("XDG_CONFIG_HOME")
 	if configDir == "" {
 		home, err := os.UserHomeDir()
 		if err != nil {
-			return ".template-engine.json" // Fallback to current directory
+			return "", err
 		}
 		configDir = filepath.Join(home, ".config")
 	}
 
-	return filepath.Join(configDir, "template-engine", "references.json")
+	return filepath.Join(configDir, "template-engine"), nil
 }
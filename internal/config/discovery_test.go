@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -316,3 +317,39 @@ func TestAddReference(t *testing.T) {
 		t.Errorf("Expected updated path '/updated/path', got %q", updatedRef.Path)
 	}
 }
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`{"references": {"api": {"path": "/api", "description": "API"}}}`))
+	if err != nil || cfg.References["api"].Path != "/api" {
+		t.Errorf("ParseConfig() = %+v, %v", cfg, err)
+	}
+
+	if _, err := ParseConfig([]byte(`{"references": `)); err == nil {
+		t.Error("ParseConfig() should reject malformed JSON")
+	}
+	if _, err := ParseConfig([]byte(`{"references": {"api": {"description": "API"}}}`)); err == nil {
+		t.Error("ParseConfig() should reject references without a path")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	cfg := DefaultReferenceConfig()
+	other := &ReferenceConfig{
+		References: map[string]ReferenceProject{
+			"go-api": {Path: "/team/api-template", Description: "Team API"},
+			"worker": {Path: "/team/worker-template", Description: "Team worker"},
+		},
+		Registry: "http://templates.internal:8080",
+	}
+
+	added, replaced := cfg.Merge(other)
+	if !reflect.DeepEqual(added, []string{"worker"}) || !reflect.DeepEqual(replaced, []string{"go-api"}) {
+		t.Errorf("Merge() = %v, %v, want [worker], [go-api]", added, replaced)
+	}
+	if len(cfg.References) != 3 || cfg.References["go-api"].Path != "/team/api-template" {
+		t.Errorf("Unexpected merged references %+v", cfg.References)
+	}
+	if cfg.Registry != other.Registry {
+		t.Errorf("Registry = %q, want %q", cfg.Registry, other.Registry)
+	}
+}
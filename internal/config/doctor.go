@@ -0,0 +1,80 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+// CheckReferences verifies every configured reference project: its path must be an existing
+// directory containing the markers of a registered template type (see core.ReferenceMarkers).
+// References that are not git repositories are reported as warnings. Issues carry the template
+// type in their File field.
+func (c *ReferenceConfig) CheckReferences() *core.Report {
+	report := &core.Report{Issues: []core.Issue{}}
+
+	types := c.ListTemplateTypes()
+	sort.Strings(types)
+	for _, templateType := range types {
+		for _, issue := range c.checkReference(templateType) {
+			issue.File = templateType
+			report.Issues = append(report.Issues, issue)
+		}
+	}
+	return report
+}
+
+// checkReference returns the issues of one reference project
+func (c *ReferenceConfig) checkReference(templateType string) []core.Issue {
+	var issues []core.Issue
+	fail := func(severity core.Severity, format string, args ...any) {
+		issues = append(issues, core.Issue{Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+
+	registered, err := core.GetTemplate(templateType)
+	if err != nil {
+		fail(core.SeverityError, "unknown template type, new cannot extract it (available: %s)",
+			strings.Join(core.ListTemplates(), ", "))
+	}
+
+	path, err := c.GetReferencePath(templateType)
+	if err != nil {
+		fail(core.SeverityError, "%v", err)
+		return issues
+	}
+
+	info, err := os.Stat(path)
+	remote := c.References[templateType].GitURL
+	switch {
+	case os.IsNotExist(err) && remote != "":
+		fail(core.SeverityWarning, "%s is not checked out yet, new clones it", remote)
+		return issues
+	case os.IsNotExist(err):
+		fail(core.SeverityError, "reference path %s does not exist, clone the project there or update it with "+
+			"`template-engine config add %s <path> <description>`", path, templateType)
+		return issues
+	case err != nil:
+		fail(core.SeverityError, "cannot access reference path: %v", err)
+		return issues
+	case !info.IsDir():
+		fail(core.SeverityError, "reference path %s is not a directory", path)
+		return issues
+	}
+
+	if _, err := os.Stat(filepath.Join(path, ".git")); err != nil {
+		fail(core.SeverityWarning, "%s is not a git repository, changes to the reference are not tracked", path)
+	}
+
+	if registered != nil {
+		if missing := core.MissingMarkers(os.DirFS(path), core.TypeMarkers(registered)); len(missing) > 0 {
+			fail(core.SeverityError, "%s does not look like a %s project, missing %s",
+				path, templateType, strings.Join(missing, ", "))
+		}
+	}
+
+	return issues
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/acheevo/template-engine/internal/core"
+	_ "github.com/acheevo/template-engine/internal/templates" // Registers the built-in template types
+)
+
+func TestCheckReferences(t *testing.T) {
+	dir := t.TempDir()
+	api := filepath.Join(dir, "api-template")
+	for _, path := range []string{"go.mod", "cmd/api/main.go", ".git/HEAD"} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(api, path)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(api, path), []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	frontend := filepath.Join(dir, "frontend-template")
+	if err := os.MkdirAll(frontend, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &ReferenceConfig{References: map[string]ReferenceProject{
+		"go-api":   {Path: api},
+		"frontend": {Path: frontend},
+		"missing":  {Path: filepath.Join(dir, "missing")},
+	}}
+
+	report := cfg.CheckReferences()
+
+	got := map[string][]core.Severity{}
+	for _, issue := range report.Issues {
+		got[issue.File] = append(got[issue.File], issue.Severity)
+	}
+	if len(got["go-api"]) != 0 {
+		t.Errorf("Expected a healthy go-api reference, got %+v", report.Issues)
+	}
+	// Not a git repository and missing package.json and vite.config.*
+	if len(got["frontend"]) != 2 || got["frontend"][0] != core.SeverityWarning {
+		t.Errorf("Expected a warning and an error for frontend, got %+v", report.Issues)
+	}
+	// Unknown template type and missing path
+	if len(got["missing"]) != 2 {
+		t.Errorf("Expected two errors for the missing reference, got %+v", report.Issues)
+	}
+}
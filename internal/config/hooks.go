@@ -0,0 +1,49 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// HookPolicy restricts the commands schema hooks may run. It is read from hooks.json in the
+// config directory, e.g.
+//
+//	{"allowed_binaries": ["go", "npm", "make"]}
+type HookPolicy struct {
+	// AllowedBinaries lists the programs hook commands may invoke, any program when empty
+	AllowedBinaries []string `json:"allowed_binaries"`
+}
+
+// HookPolicyPath returns the path of the hook policy file
+func HookPolicyPath() (string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(configDir, "hooks.json"), nil
+}
+
+// LoadHookPolicy loads the hook policy, an empty policy when no policy file exists. Unlike the
+// reference config, a policy file that cannot be read is an error rather than silently ignored.
+func LoadHookPolicy() (*HookPolicy, error) {
+	policyPath, err := HookPolicyPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(policyPath)
+	if os.IsNotExist(err) {
+		return &HookPolicy{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hook policy: %w", err)
+	}
+
+	var policy HookPolicy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("invalid hook policy %s: %w", policyPath, err)
+	}
+	return &policy, nil
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadHookPolicy(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+
+	policy, err := LoadHookPolicy()
+	if err != nil {
+		t.Fatalf("LoadHookPolicy() without a policy file error = %v", err)
+	}
+	if len(policy.AllowedBinaries) != 0 {
+		t.Errorf("Expected an empty policy, got %+v", policy)
+	}
+
+	policyPath := filepath.Join(configHome, "template-engine", "hooks.json")
+	if err := os.MkdirAll(filepath.Dir(policyPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(policyPath, []byte(`{"allowed_binaries": ["go", "npm"]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err = LoadHookPolicy()
+	if err != nil {
+		t.Fatalf("LoadHookPolicy() error = %v", err)
+	}
+	if !reflect.DeepEqual(policy.AllowedBinaries, []string{"go", "npm"}) {
+		t.Errorf("AllowedBinaries = %v, want [go npm]", policy.AllowedBinaries)
+	}
+
+	if err := os.WriteFile(policyPath, []byte(`{"allowed_binaries": "go"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadHookPolicy(); err == nil {
+		t.Error("LoadHookPolicy() should reject a malformed policy")
+	}
+}
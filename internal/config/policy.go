@@ -0,0 +1,40 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/acheevo/template-engine/internal/policy"
+)
+
+// PolicyEnv names the environment variable pointing at an organization policy file to use
+// instead of policy.json in the config directory
+const PolicyEnv = "TEMPLATE_ENGINE_POLICY"
+
+// OrgPolicyPath returns the path of the organization policy file: $TEMPLATE_ENGINE_POLICY, or
+// policy.json in the config directory
+func OrgPolicyPath() (string, error) {
+	if path := os.Getenv(PolicyEnv); path != "" {
+		return path, nil
+	}
+	configDir, err := getConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(configDir, "policy.json"), nil
+}
+
+// LoadOrgPolicy loads the organization policy generation requests are checked against (see
+// policy.Rules), nil when no policy file exists. A policy file that cannot be read is an error,
+// like the hook policy.
+func LoadOrgPolicy() (*policy.Rules, error) {
+	policyPath, err := OrgPolicyPath()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(policyPath); os.IsNotExist(err) && os.Getenv(PolicyEnv) == "" {
+		return nil, nil
+	}
+	return policy.Load(policyPath)
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadOrgPolicy(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv(PolicyEnv, "")
+
+	rules, err := LoadOrgPolicy()
+	if err != nil || rules != nil {
+		t.Fatalf("LoadOrgPolicy() without a policy file = %v, %v, want none", rules, err)
+	}
+
+	policyPath := filepath.Join(configHome, "template-engine", "policy.json")
+	if err := os.MkdirAll(filepath.Dir(policyPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(policyPath, []byte(`{"allowed_owners": ["acme"]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	rules, err = LoadOrgPolicy()
+	if err != nil {
+		t.Fatalf("LoadOrgPolicy() error = %v", err)
+	}
+	if !reflect.DeepEqual(rules.AllowedOwners, []string{"acme"}) {
+		t.Errorf("AllowedOwners = %v, want [acme]", rules.AllowedOwners)
+	}
+
+	// A policy named by the environment must exist
+	t.Setenv(PolicyEnv, filepath.Join(configHome, "missing.json"))
+	if _, err := LoadOrgPolicy(); err == nil {
+		t.Error("LoadOrgPolicy() should fail when the policy named by the environment is missing")
+	}
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// profile names the reference config LoadConfig and SaveConfig use, the default one when empty
+var profile string
+
+// SetProfile switches LoadConfig and SaveConfig to the named profile, a separate set of
+// reference projects stored in profiles/<name>.json. An empty name selects the default config.
+func SetProfile(name string) error {
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-_.", r) {
+			return fmt.Errorf("profile name %q contains invalid character %q", name, r)
+		}
+	}
+	if strings.HasPrefix(name, ".") {
+		return fmt.Errorf("profile name %q must not start with a dot", name)
+	}
+
+	profile = name
+	return nil
+}
+
+// Profile returns the active profile, empty for the default config
+func Profile() string {
+	return profile
+}
+
+// ListProfiles returns the sorted names of the saved profiles
+func ListProfiles() ([]string, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate config directory: %w", err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(configDir, "profiles"))
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles: %w", err)
+	}
+
+	profiles := []string{}
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".json"); ok && !entry.IsDir() {
+			profiles = append(profiles, name)
+		}
+	}
+	sort.Strings(profiles)
+	return profiles, nil
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestProfiles(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tempDir)
+	t.Cleanup(func() { profile = "" })
+
+	if err := SetProfile("../work"); err == nil {
+		t.Error("SetProfile() should reject names with path separators")
+	}
+	if err := SetProfile("work"); err != nil {
+		t.Fatalf("SetProfile() error = %v", err)
+	}
+
+	cfg := &ReferenceConfig{}
+	cfg.AddReference("api", "/work/api-template", "Work API")
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "template-engine", "profiles", "work.json")); err != nil {
+		t.Errorf("Profile config was not written: %v", err)
+	}
+
+	profiles, err := ListProfiles()
+	if err != nil || !reflect.DeepEqual(profiles, []string{"work"}) {
+		t.Errorf("ListProfiles() = %v, %v, want [work]", profiles, err)
+	}
+
+	// The default config keeps its own reference projects
+	if err := SetProfile(""); err != nil {
+		t.Fatal(err)
+	}
+	defaults, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, exists := defaults.References["api"]; exists {
+		t.Error("Default config should not contain the profile's references")
+	}
+}
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// AuditKind classifies what an audit finding may leak
+type AuditKind string
+
+const (
+	// AuditEmail is an email address, such as the author's in a package manifest
+	AuditEmail AuditKind = "email"
+	// AuditLocalPath is an absolute path of the machine the reference project lived on
+	AuditLocalPath AuditKind = "local-path"
+	// AuditUsername is the name of a local user, taken from the home directories of local paths
+	AuditUsername AuditKind = "username"
+	// AuditMachine is a machine-specific string such as a .local hostname or a LAN address
+	AuditMachine AuditKind = "machine"
+	// AuditOversized is a file larger than the size limit of the audit
+	AuditOversized AuditKind = "oversized"
+)
+
+// AuditFinding is something of a schema that commonly leaks from a reference project into a
+// published template
+type AuditFinding struct {
+	Kind AuditKind `json:"kind"`
+	File string    `json:"file"`
+	// Line is the first line of the file the match occurs on, 0 for oversized files
+	Line  int    `json:"line,omitempty"`
+	Match string `json:"match"`
+}
+
+// Audit lists the findings of AuditSchema, by file then line
+type Audit struct {
+	Findings []AuditFinding `json:"findings"`
+}
+
+var (
+	emailPattern = regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`)
+	// localPathPattern captures the user of home directories on macOS, Linux and Windows
+	localPathPattern = regexp.MustCompile(`(?:/Users/|/home/|[A-Za-z]:\\Users\\)([A-Za-z0-9._-]+)[^\s"'` + "`" + `]*`)
+	machinePattern   = regexp.MustCompile(`\b[A-Za-z0-9-]+\.local\b|\b192\.168\.\d{1,3}\.\d{1,3}\b`)
+)
+
+// placeholderEmailDomains are the domains of addresses that identify nobody
+var placeholderEmailDomains = []string{"example.com", "example.org", "example.net", "users.noreply.github.com"}
+
+// placeholderUsers are the users of home directories shared by every machine, such as those of
+// container images and CI runners
+var placeholderUsers = []string{"user", "username", "runner", "node", "ubuntu", "vscode", "app", "you"}
+
+// AuditSchema reports the email addresses, local paths, usernames and machine-specific strings
+// of the text files of a schema, and the files larger than maxFileSize (no limit when 0). Each
+// match is reported once per file, at its first line.
+func AuditSchema(schema *TemplateSchema, maxFileSize int64) (*Audit, error) {
+	audit := &Audit{Findings: []AuditFinding{}}
+	contents := make(map[string]string)
+	var users []string
+
+	for _, file := range schema.Files {
+		if maxFileSize > 0 && file.Size > maxFileSize {
+			audit.Findings = append(audit.Findings, AuditFinding{
+				Kind: AuditOversized, File: file.Path, Match: fmt.Sprintf("%d bytes", file.Size),
+			})
+		}
+		if file.IsSymlink() || file.Encoding != "" {
+			continue
+		}
+		content, err := ResolveContent(schema, file)
+		if err != nil {
+			return nil, fmt.Errorf("%s failed to decompress: %w", file.Path, err)
+		}
+		contents[file.Path] = content
+
+		for _, match := range emailPattern.FindAllStringIndex(content, -1) {
+			email := content[match[0]:match[1]]
+			local, domain, _ := strings.Cut(email, "@")
+			if local != "git" && !slices.Contains(placeholderEmailDomains, strings.ToLower(domain)) {
+				audit.add(AuditEmail, file.Path, content, match[0], email)
+			}
+		}
+		for _, match := range localPathPattern.FindAllStringSubmatchIndex(content, -1) {
+			audit.add(AuditLocalPath, file.Path, content, match[0], content[match[0]:match[1]])
+			if user := content[match[2]:match[3]]; !slices.Contains(placeholderUsers, strings.ToLower(user)) &&
+				!slices.Contains(users, user) {
+				users = append(users, user)
+			}
+		}
+		for _, match := range machinePattern.FindAllStringIndex(content, -1) {
+			audit.add(AuditMachine, file.Path, content, match[0], content[match[0]:match[1]])
+		}
+	}
+
+	// Users found in local paths are looked for everywhere else, e.g. in author fields
+	for _, user := range users {
+		userPattern := regexp.MustCompile(`\b` + regexp.QuoteMeta(user) + `\b`)
+		for _, file := range schema.Files {
+			content, ok := contents[file.Path]
+			if !ok {
+				continue
+			}
+			paths := localPathPattern.FindAllStringIndex(content, -1)
+			for _, match := range userPattern.FindAllStringIndex(content, -1) {
+				if !slices.ContainsFunc(paths, func(path []int) bool { return match[0] >= path[0] && match[0] < path[1] }) {
+					audit.add(AuditUsername, file.Path, content, match[0], user)
+				}
+			}
+		}
+	}
+
+	slices.SortStableFunc(audit.Findings, func(a, b AuditFinding) int {
+		if a.File != b.File {
+			return strings.Compare(a.File, b.File)
+		}
+		return a.Line - b.Line
+	})
+	return audit, nil
+}
+
+// add records match of kind at offset of content, unless file already has it
+func (a *Audit) add(kind AuditKind, file, content string, offset int, match string) {
+	if slices.ContainsFunc(a.Findings, func(f AuditFinding) bool {
+		return f.Kind == kind && f.File == file && f.Match == match
+	}) {
+		return
+	}
+	line := strings.Count(content[:offset], "\n") + 1
+	a.Findings = append(a.Findings, AuditFinding{Kind: kind, File: file, Line: line, Match: match})
+}
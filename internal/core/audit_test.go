@@ -0,0 +1,35 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAuditSchema(t *testing.T) {
+	schema := &TemplateSchema{Files: []FileSpec{
+		{Path: "package.json", Size: 80, Content: `{"author": "Jane Doe <jane@acme.io> (jdoe)", "bugs": "dev@example.com"}`},
+		{Path: "Makefile", Size: 60, Content: "run:\n\tgo run . --config /Users/jdoe/src/app/config.yml\n" +
+			"\tcurl http://192.168.1.20:8080 http://jdoe-mbp.local\n\tcp build /home/runner/out\n"},
+		{Path: "README.md", Size: 40, Content: "git clone git@github.com:acheevo/app.git\n"},
+		{Path: "logo.png", Size: 4096, Encoding: EncodingBase64, Content: "amFuZUBhY21lLmlv"},
+	}}
+
+	audit, err := AuditSchema(schema, 1024)
+	if err != nil {
+		t.Fatalf("AuditSchema() error = %v", err)
+	}
+
+	want := []AuditFinding{
+		{Kind: AuditLocalPath, File: "Makefile", Line: 2, Match: "/Users/jdoe/src/app/config.yml"},
+		{Kind: AuditMachine, File: "Makefile", Line: 3, Match: "192.168.1.20"},
+		{Kind: AuditMachine, File: "Makefile", Line: 3, Match: "jdoe-mbp.local"},
+		{Kind: AuditUsername, File: "Makefile", Line: 3, Match: "jdoe"},
+		{Kind: AuditLocalPath, File: "Makefile", Line: 4, Match: "/home/runner/out"},
+		{Kind: AuditOversized, File: "logo.png", Match: "4096 bytes"},
+		{Kind: AuditEmail, File: "package.json", Line: 1, Match: "jane@acme.io"},
+		{Kind: AuditUsername, File: "package.json", Line: 1, Match: "jdoe"},
+	}
+	if !reflect.DeepEqual(audit.Findings, want) {
+		t.Errorf("AuditSchema() findings =\n%+v\nwant\n%+v", audit.Findings, want)
+	}
+}
@@ -0,0 +1,222 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+
+	"github.com/acheevo/template-engine/pkg/schema"
+)
+
+// Severity classifies a schema check issue
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is a single finding produced by CheckSchema
+type Issue struct {
+	Severity Severity `json:"severity"`
+	File     string   `json:"file,omitempty"`
+	Message  string   `json:"message"`
+}
+
+// Report collects the issues found while checking a schema
+type Report struct {
+	Issues []Issue `json:"issues"`
+}
+
+// variableReference matches the variable name in actions like {{.ProjectName}} or {{ .Author | upper }}
+var variableReference = regexp.MustCompile(`\{\{-?\s*\.([A-Za-z_][A-Za-z0-9_]*)`)
+
+func (r *Report) add(severity Severity, file, format string, args ...any) {
+	r.Issues = append(r.Issues, Issue{
+		Severity: severity,
+		File:     file,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Errors returns the issues with error severity
+func (r *Report) Errors() []Issue {
+	return r.filter(SeverityError)
+}
+
+// Warnings returns the issues with warning severity
+func (r *Report) Warnings() []Issue {
+	return r.filter(SeverityWarning)
+}
+
+// HasErrors reports whether any error-level issue was found
+func (r *Report) HasErrors() bool {
+	return len(r.Errors()) > 0
+}
+
+func (r *Report) filter(severity Severity) []Issue {
+	issues := []Issue{}
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// CheckSchema runs ValidateSchema-equivalent checks on every part of the schema instead of
+// stopping at the first failure, plus deeper checks on mappings and variable references
+func CheckSchema(s *TemplateSchema) *Report {
+	report := &Report{Issues: []Issue{}}
+
+	for _, err := range schema.ValidateFields(s) {
+		report.add(SeverityError, "", "%v", err)
+	}
+
+	if err := CheckCompatibility(s); err != nil {
+		report.add(SeverityError, "", "%v", err)
+	}
+
+	if len(s.Files) == 0 {
+		report.add(SeverityError, "", "schema must contain at least one file")
+	}
+
+	for i, file := range s.Files {
+		checkFile(report, s, file, i)
+	}
+
+	for _, miss := range LintMappings(s) {
+		report.add(SeverityWarning, miss.location(), "%s", miss.message())
+	}
+
+	return report
+}
+
+// checkFile runs all per-file checks
+func checkFile(report *Report, s *TemplateSchema, file FileSpec, index int) {
+	if err := schema.ValidateFile(s, index); err != nil {
+		report.add(SeverityError, file.Path, "%v", err)
+		return
+	}
+
+	if file.Hash == "" {
+		report.add(SeverityWarning, file.Path, "file has no hash, integrity cannot be verified")
+	}
+
+	if !file.Template {
+		if len(file.Mappings) > 0 {
+			report.add(SeverityWarning, file.Path, "file has mappings but is not templated, mappings are ignored")
+		}
+		return
+	}
+
+	content, err := ResolveContent(s, file)
+	if err != nil {
+		report.add(SeverityError, file.Path, "failed to decompress content: %v", err)
+		return
+	}
+
+	for _, mapping := range file.Mappings {
+		for _, name := range ReferencedVariables(mapping.Replace) {
+			if !isKnownVariable(s, name) {
+				report.add(SeverityError, file.Path, "mapping references undefined variable %q", name)
+			}
+		}
+	}
+
+	for _, name := range ReferencedVariables(content) {
+		if !isKnownVariable(s, name) {
+			report.add(SeverityWarning, file.Path,
+				"content references undefined variable %q, it will be rendered literally", name)
+		}
+	}
+}
+
+// MappingMiss describes a mapping whose Find string was not found where it is applied
+type MappingMiss struct {
+	Find  string   `json:"find"`
+	Paths []string `json:"paths"`
+}
+
+func (m MappingMiss) location() string {
+	if len(m.Paths) == 1 {
+		return m.Paths[0]
+	}
+	return ""
+}
+
+func (m MappingMiss) message() string {
+	if len(m.Paths) == 1 {
+		return fmt.Sprintf("mapping find string %q not found in content", m.Find)
+	}
+	return fmt.Sprintf("mapping find string %q not found in any of the %d files it applies to",
+		m.Find, len(m.Paths))
+}
+
+// LintMappings reports mappings that no longer match their templated files.
+// A mapping applied to a single file must occur in that file. A mapping shared by many files
+// (such as an import path rewrite for every .go file) is only reported when it matches none of them.
+func LintMappings(schema *TemplateSchema) []MappingMiss {
+	applied := make(map[string][]string)
+	matched := make(map[string]bool)
+	var order []string
+
+	for _, file := range schema.Files {
+		if !file.Template || len(file.Mappings) == 0 {
+			continue
+		}
+
+		content, err := ResolveContent(schema, file)
+		if err != nil {
+			continue // Reported by schema validation
+		}
+
+		for _, mapping := range file.Mappings {
+			if _, seen := applied[mapping.Find]; !seen {
+				order = append(order, mapping.Find)
+			}
+			applied[mapping.Find] = append(applied[mapping.Find], file.Path)
+			if MappingMatches(content, mapping) {
+				matched[mapping.Find] = true
+			}
+		}
+	}
+
+	var misses []MappingMiss
+	for _, find := range order {
+		if !matched[find] {
+			misses = append(misses, MappingMiss{Find: find, Paths: applied[find]})
+		}
+	}
+
+	return misses
+}
+
+// ReferencedVariables returns the sorted, unique variable names referenced by template actions in content
+func ReferencedVariables(content string) []string {
+	seen := make(map[string]bool)
+	for _, match := range variableReference.FindAllStringSubmatch(content, -1) {
+		seen[match[1]] = true
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isKnownVariable reports whether name is declared by the schema or built in
+func isKnownVariable(schema *TemplateSchema, name string) bool {
+	if _, exists := schema.Variables[name]; exists {
+		return true
+	}
+	return IsBuiltinVariable(name) || slices.Contains(derivedVariables, name)
+}
+
+// IsBuiltinVariable reports whether name is one of the variables every template receives
+func IsBuiltinVariable(name string) bool {
+	return slices.Contains(schema.BuiltinVariables, name)
+}
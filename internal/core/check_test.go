@@ -0,0 +1,108 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckSchema(t *testing.T) {
+	schema := &TemplateSchema{
+		Name:    "test-template",
+		Type:    "frontend",
+		Version: "1.0.0",
+		Variables: map[string]Variable{
+			"ProjectName": {Type: "string", Required: true},
+		},
+		Files: []FileSpec{
+			{
+				Path:     "README.md",
+				Template: true,
+				Content:  "# Frontend Template\n{{.Team}}",
+				Hash:     CalculateContentHash("# Frontend Template\n{{.Team}}"),
+				Mappings: []Mapping{
+					{Find: "# Frontend Template", Replace: "# {{.ProjectName}}"},
+					{Find: "Old Name", Replace: "{{.Owner | upper}}"},
+				},
+			},
+			{
+				Path:    "main.go",
+				Content: "package main",
+				Hash:    "deadbeef",
+			},
+		},
+	}
+
+	report := CheckSchema(schema)
+
+	expected := []Issue{
+		{Severity: SeverityError, File: "README.md", Message: `mapping references undefined variable "Owner"`},
+		{
+			Severity: SeverityWarning,
+			File:     "README.md",
+			Message:  `content references undefined variable "Team", it will be rendered literally`,
+		},
+		{
+			Severity: SeverityError,
+			File:     "main.go",
+			Message: "file main.go hash mismatch: expected deadbeef, got " +
+				CalculateContentHash("package main"),
+		},
+		{Severity: SeverityWarning, File: "README.md", Message: `mapping find string "Old Name" not found in content`},
+	}
+
+	if !reflect.DeepEqual(report.Issues, expected) {
+		t.Errorf("CheckSchema() issues mismatch.\nExpected: %+v\nGot: %+v", expected, report.Issues)
+	}
+	if !report.HasErrors() {
+		t.Error("Expected report to have errors")
+	}
+	if len(report.Warnings()) != 2 {
+		t.Errorf("Expected 2 warnings, got %d", len(report.Warnings()))
+	}
+}
+
+func TestLintMappings(t *testing.T) {
+	importMapping := Mapping{Find: `"github.com/acheevo/api-template/`, Replace: `"github.com/{{.GitHubRepo}}/`}
+	staleMapping := Mapping{Find: "docker build -t api-template .", Replace: "docker build -t {{.ProjectName}} ."}
+
+	files := []FileSpec{
+		{Path: "cmd/api/main.go", Template: true, Content: `import "github.com/acheevo/api-template/internal"`,
+			Mappings: []Mapping{importMapping}},
+		{Path: "internal/util.go", Template: true, Content: "package util", Mappings: []Mapping{importMapping}},
+		{Path: "Makefile", Template: true, Content: "docker build -t api .", Mappings: []Mapping{staleMapping}},
+		{Path: "static.txt", Content: "ignored", Mappings: []Mapping{staleMapping}},
+	}
+
+	misses := LintMappings(&TemplateSchema{Files: files})
+	expected := []MappingMiss{{Find: staleMapping.Find, Paths: []string{"Makefile"}}}
+	if !reflect.DeepEqual(misses, expected) {
+		t.Errorf("LintMappings() = %+v, want %+v", misses, expected)
+	}
+
+	// A shared mapping that matches nowhere is reported with every file it applies to
+	misses = LintMappings(&TemplateSchema{Files: []FileSpec{
+		files[1],
+		{Path: "internal/other.go", Template: true, Content: "package other", Mappings: []Mapping{importMapping}},
+	}})
+	if len(misses) != 1 || len(misses[0].Paths) != 2 {
+		t.Errorf("Expected shared mapping miss, got %+v", misses)
+	}
+}
+
+func TestCheckSchemaMissingBasics(t *testing.T) {
+	report := CheckSchema(&TemplateSchema{})
+
+	if len(report.Errors()) != 3 {
+		t.Errorf("Expected 3 errors (basic fields, variables, files), got %+v", report.Errors())
+	}
+}
+
+func TestReferencedVariables(t *testing.T) {
+	content := "{{.ProjectName}} {{ .Author | upper }} {{- .ProjectName }} {{ range .Items }}"
+
+	got := ReferencedVariables(content)
+	want := []string{"Author", "ProjectName"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReferencedVariables() = %v, want %v", got, want)
+	}
+}
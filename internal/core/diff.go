@@ -0,0 +1,155 @@
+package core
+
+import "sort"
+
+// Change kinds reported by DiffSchemas
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// SchemaDiff describes what changed between two versions of a schema
+type SchemaDiff struct {
+	OldVersion   string           `json:"old_version"`
+	NewVersion   string           `json:"new_version"`
+	AddedFiles   []string         `json:"added_files"`
+	RemovedFiles []string         `json:"removed_files"`
+	ChangedFiles []FileChange     `json:"changed_files"`
+	Variables    []VariableChange `json:"variables"`
+}
+
+// FileChange describes a file present in both schemas whose content, templating or mappings changed
+type FileChange struct {
+	Path            string    `json:"path"`
+	ContentChanged  bool      `json:"content_changed"`
+	TemplateChanged bool      `json:"template_changed"`
+	AddedMappings   []Mapping `json:"added_mappings,omitempty"`
+	RemovedMappings []Mapping `json:"removed_mappings,omitempty"`
+}
+
+// VariableChange describes a variable that was added, removed or redefined
+type VariableChange struct {
+	Name   string    `json:"name"`
+	Change string    `json:"change"`
+	Old    *Variable `json:"old,omitempty"`
+	New    *Variable `json:"new,omitempty"`
+}
+
+// Empty reports whether the schemas differ in files, mappings or variables
+func (d *SchemaDiff) Empty() bool {
+	return len(d.AddedFiles) == 0 && len(d.RemovedFiles) == 0 &&
+		len(d.ChangedFiles) == 0 && len(d.Variables) == 0
+}
+
+// DiffSchemas compares the files, mappings and variables of two schemas.
+// Files are matched by path and compared by resolved content, so compression and
+// deduplication do not show up as changes.
+func DiffSchemas(oldSchema, newSchema *TemplateSchema) *SchemaDiff {
+	diff := &SchemaDiff{
+		OldVersion:   oldSchema.Version,
+		NewVersion:   newSchema.Version,
+		AddedFiles:   []string{},
+		RemovedFiles: []string{},
+		ChangedFiles: []FileChange{},
+		Variables:    []VariableChange{},
+	}
+
+	oldFiles := make(map[string]FileSpec, len(oldSchema.Files))
+	for _, file := range oldSchema.Files {
+		oldFiles[file.Path] = file
+	}
+
+	for _, file := range newSchema.Files {
+		oldFile, exists := oldFiles[file.Path]
+		delete(oldFiles, file.Path)
+		if !exists {
+			diff.AddedFiles = append(diff.AddedFiles, file.Path)
+			continue
+		}
+
+		change := FileChange{
+			Path:            file.Path,
+			ContentChanged:  contentHash(oldSchema, oldFile) != contentHash(newSchema, file),
+			TemplateChanged: oldFile.Template != file.Template,
+			AddedMappings:   missingMappings(file.Mappings, oldFile.Mappings),
+			RemovedMappings: missingMappings(oldFile.Mappings, file.Mappings),
+		}
+		if change.ContentChanged || change.TemplateChanged ||
+			len(change.AddedMappings) > 0 || len(change.RemovedMappings) > 0 {
+			diff.ChangedFiles = append(diff.ChangedFiles, change)
+		}
+	}
+
+	for path := range oldFiles {
+		diff.RemovedFiles = append(diff.RemovedFiles, path)
+	}
+	sort.Strings(diff.AddedFiles)
+	sort.Strings(diff.RemovedFiles)
+	sort.Slice(diff.ChangedFiles, func(i, j int) bool {
+		return diff.ChangedFiles[i].Path < diff.ChangedFiles[j].Path
+	})
+
+	diff.Variables = diffVariables(oldSchema.Variables, newSchema.Variables)
+	return diff
+}
+
+// contentHash returns the hash of a file's resolved content, falling back to the stored content
+// for files that cannot be decompressed so they still compare unequal to anything else.
+// Symlinks hash their target.
+func contentHash(schema *TemplateSchema, file FileSpec) string {
+	if file.IsSymlink() {
+		return CalculateContentHash(FileTypeSymlink + ":" + file.Target)
+	}
+	content, err := ResolveContent(schema, file)
+	if err != nil {
+		content, _ = StoredContent(schema, file)
+	}
+	return CalculateContentHash(content)
+}
+
+// missingMappings returns the mappings of a that are not in b
+func missingMappings(a, b []Mapping) []Mapping {
+	var missing []Mapping
+	for _, mapping := range a {
+		found := false
+		for _, other := range b {
+			if mapping == other {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, mapping)
+		}
+	}
+	return missing
+}
+
+// diffVariables returns the variable changes sorted by name
+func diffVariables(oldVariables, newVariables map[string]Variable) []VariableChange {
+	changes := []VariableChange{}
+
+	for name, newVariable := range newVariables {
+		oldVariable, exists := oldVariables[name]
+		switch {
+		case !exists:
+			changes = append(changes, VariableChange{Name: name, Change: ChangeAdded, New: &newVariable})
+		case oldVariable != newVariable:
+			changes = append(changes, VariableChange{
+				Name: name, Change: ChangeChanged, Old: &oldVariable, New: &newVariable,
+			})
+		}
+	}
+
+	for name, oldVariable := range oldVariables {
+		if _, exists := newVariables[name]; !exists {
+			changes = append(changes, VariableChange{Name: name, Change: ChangeRemoved, Old: &oldVariable})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	importMapping := Mapping{Find: "acheevo/api-template", Replace: "{{.GitHubRepo}}"}
+	nameMapping := Mapping{Find: "api-template", Replace: "{{.ProjectName}}"}
+
+	oldSchema := &TemplateSchema{
+		Version: "1.0.0",
+		Variables: map[string]Variable{
+			"ProjectName": {Type: "string", Required: true},
+			"Author":      {Type: "string"},
+		},
+		Files: []FileSpec{
+			{Path: "README.md", Template: true, Content: "# api-template", Mappings: []Mapping{nameMapping}},
+			{Path: "main.go", Template: true, Content: "package main", Mappings: []Mapping{importMapping}},
+			{Path: "old.txt", Content: "old"},
+			{Path: "same.txt", Content: "same"},
+		},
+	}
+	newSchema := &TemplateSchema{
+		Version: "1.1.0",
+		Variables: map[string]Variable{
+			"ProjectName": {Type: "string", Required: true, Description: "Project name"},
+			"Description": {Type: "string"},
+		},
+		Files: []FileSpec{
+			{Path: "README.md", Template: true, Content: "# api-template\nMore", Mappings: []Mapping{nameMapping}},
+			{Path: "main.go", Template: true, Content: "package main", Mappings: []Mapping{nameMapping}},
+			{Path: "new.txt", Content: "new"},
+			{Path: "same.txt", Content: "same"},
+		},
+	}
+
+	diff := DiffSchemas(oldSchema, newSchema)
+
+	if diff.OldVersion != "1.0.0" || diff.NewVersion != "1.1.0" {
+		t.Errorf("versions = %s -> %s", diff.OldVersion, diff.NewVersion)
+	}
+	if !reflect.DeepEqual(diff.AddedFiles, []string{"new.txt"}) {
+		t.Errorf("AddedFiles = %v", diff.AddedFiles)
+	}
+	if !reflect.DeepEqual(diff.RemovedFiles, []string{"old.txt"}) {
+		t.Errorf("RemovedFiles = %v", diff.RemovedFiles)
+	}
+
+	expectedFiles := []FileChange{
+		{Path: "README.md", ContentChanged: true},
+		{Path: "main.go", AddedMappings: []Mapping{nameMapping}, RemovedMappings: []Mapping{importMapping}},
+	}
+	if !reflect.DeepEqual(diff.ChangedFiles, expectedFiles) {
+		t.Errorf("ChangedFiles = %+v, want %+v", diff.ChangedFiles, expectedFiles)
+	}
+
+	var changes []string
+	for _, change := range diff.Variables {
+		changes = append(changes, change.Name+" "+change.Change)
+	}
+	expectedChanges := []string{"Author removed", "Description added", "ProjectName changed"}
+	if !reflect.DeepEqual(changes, expectedChanges) {
+		t.Errorf("Variables = %v, want %v", changes, expectedChanges)
+	}
+
+	if !DiffSchemas(oldSchema, oldSchema).Empty() {
+		t.Error("a schema should not differ from itself")
+	}
+}
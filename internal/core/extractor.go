@@ -0,0 +1,389 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/acheevo/template-engine/pkg/schema"
+)
+
+// EnvExampleFile is parsed into the schema's EnvConfig wherever it appears in the source directory
+const EnvExampleFile = ".env.example"
+
+// ExtractPolicy decides which files of a reference project end up in a schema and how they
+// are templated. Every TemplateType is an ExtractPolicy.
+type ExtractPolicy interface {
+	ShouldSkip(path string) bool
+	ShouldTemplate(filePath string) bool
+	GetMappings(filePath string) []Mapping
+}
+
+// PatchPolicy is implemented by policies editing structured files with patches rather than
+// mappings. The patches of .json files are recorded as their FileSpec.JSONPatch, those of
+// .yml and .yaml files as their FileSpec.YAMLPatch.
+type PatchPolicy interface {
+	GetPatches(filePath string) []Patch
+}
+
+// EnvComponentPolicy is implemented by policies of multi-component projects, naming the
+// component (e.g. api or frontend) the variables of the env example at source belong to
+type EnvComponentPolicy interface {
+	EnvComponent(source string) string
+}
+
+// Reasons reported for files left out of a schema, see SkippedFile
+const (
+	SkipReasonHidden = "hidden file"
+	SkipReasonGit    = "git metadata"
+	// SkipReasonSymlink is reported for symlinks to directories outside the project, which
+	// cannot be recorded as symlinks nor embedded as files
+	SkipReasonSymlink = "symlink to a directory outside the project"
+	// SkipReasonTooLarge is reported for files above ExtractOptions.MaxFileSize with LargeFileSkip
+	SkipReasonTooLarge = "larger than the max file size"
+	// SkipReasonPolicy is reported for policies that do not implement SkipExplainer
+	SkipReasonPolicy = "excluded by template type"
+	// SkipReasonGitignore is reported for the files and directories a .gitignore of the project
+	// excludes
+	SkipReasonGitignore = "matched " + GitignoreFile
+)
+
+// LargeFilePolicy decides what extraction does with files larger than ExtractOptions.MaxFileSize
+type LargeFilePolicy string
+
+const (
+	// LargeFileWarn embeds large files, reporting them to ExtractOptions.OnLargeFile
+	LargeFileWarn LargeFilePolicy = "warn"
+	// LargeFileSkip leaves large files out of the schema, reporting them to ExtractOptions.OnSkip
+	LargeFileSkip LargeFilePolicy = "skip"
+	// LargeFileExternalize stores large files in sidecar blobs next to the schema file once it
+	// is saved (see FileSpec.External), reporting them to ExtractOptions.OnLargeFile
+	LargeFileExternalize LargeFilePolicy = "externalize"
+)
+
+// ParseLargeFilePolicy validates a large file policy name, LargeFileWarn when empty
+func ParseLargeFilePolicy(name string) (LargeFilePolicy, error) {
+	switch policy := LargeFilePolicy(name); policy {
+	case "":
+		return LargeFileWarn, nil
+	case LargeFileWarn, LargeFileSkip, LargeFileExternalize:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown large file policy %q (available: warn, skip, externalize)", name)
+	}
+}
+
+// SkipDirReason is the reason reported for files below a directory a policy never extracts
+func SkipDirReason(dir string) string {
+	return "matched skip dir " + dir
+}
+
+// SkipExplainer is implemented by extract policies that tell why they skip a path
+type SkipExplainer interface {
+	// SkipReason returns why path is skipped, empty when it is extracted
+	SkipReason(path string) string
+}
+
+// SkippedFile is a file of a reference project that was left out of the schema. Directories
+// excluded by a .gitignore are reported once rather than file by file.
+type SkippedFile struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// ExtractOptions controls how a TemplateType builds a schema
+type ExtractOptions struct {
+	// Codec used for file contents: gzip when empty, CodecNone disables compression
+	Codec Codec
+	// OnSkip is called for every file left out of the schema, with the reason
+	OnSkip func(SkippedFile)
+	// MaxFileSize is the size in bytes above which files are handled by LargeFiles, 0 for no limit
+	MaxFileSize int64
+	// LargeFiles decides what happens to files larger than MaxFileSize, LargeFileWarn when empty
+	LargeFiles LargeFilePolicy
+	// OnLargeFile is called for every file larger than MaxFileSize that is extracted, with its size
+	OnLargeFile func(path string, size int64)
+
+	// NoGitignore extracts the files the .gitignore files of the project exclude, which are left
+	// out like build artifacts and local settings otherwise
+	NoGitignore bool
+}
+
+// tooLarge reports whether a file of size exceeds MaxFileSize
+func (opts ExtractOptions) tooLarge(size int64) bool {
+	return opts.MaxFileSize > 0 && size > opts.MaxFileSize
+}
+
+// skip reports a file left out of the schema to OnSkip
+func (opts ExtractOptions) skip(path, reason string) {
+	if opts.OnSkip != nil {
+		opts.OnSkip(SkippedFile{Path: path, Reason: reason})
+	}
+}
+
+// CompressExtracted compresses the files of a freshly extracted schema as configured by opts.
+// Every TemplateType calls it so schemas are stored the same way whatever their type.
+func CompressExtracted(ctx context.Context, schema *TemplateSchema, opts ExtractOptions) error {
+	codec := opts.Codec
+	if codec == "" {
+		codec = CodecGzip
+	}
+	return CompressFiles(ctx, schema.Files, codec)
+}
+
+// ReadLinkFS is implemented by file systems exposing symbolic links, such as the directory
+// Extract walks. Symlinks of other file systems are followed like regular files.
+type ReadLinkFS interface {
+	fs.FS
+	// ReadLink returns the target of the symlink name
+	ReadLink(name string) (string, error)
+}
+
+// DirFS returns the file tree of dir like os.DirFS, exposing its symlinks through ReadLinkFS
+func DirFS(dir string) fs.FS {
+	return dirFS{FS: os.DirFS(dir), dir: dir}
+}
+
+// dirFS is os.DirFS exposing the symlinks of the directory
+type dirFS struct {
+	fs.FS
+	dir string
+}
+
+func (d dirFS) ReadLink(name string) (string, error) {
+	target, err := os.Readlink(filepath.Join(d.dir, filepath.FromSlash(name)))
+	return filepath.ToSlash(target), err
+}
+
+// Extractor walks a reference project and assembles a schema, so template types only supply
+// policy: the skip, templating and mapping rules plus the schema metadata
+type Extractor struct {
+	Policy ExtractPolicy
+	// ParseEnv turns the content of each .env.example into EnvConfig entries; env files are only
+	// embedded as regular files when nil
+	ParseEnv func(content string) []EnvVariable
+}
+
+// Extract fills schema with the files of sourceDir, see ExtractFS
+func (e *Extractor) Extract(
+	ctx context.Context, sourceDir string, schema *TemplateSchema, opts ExtractOptions,
+) (*TemplateSchema, error) {
+	return e.ExtractFS(ctx, DirFS(sourceDir), schema, opts)
+}
+
+// ExtractFS fills schema with the files of fsys (a directory, go:embed bundle, zip archive or
+// in-memory tree), the variables of every extracted .env.example (the root one first, then
+// nested ones such as frontend/.env.example) and the schema hash, then compresses file contents
+// as configured by opts. The policy sees paths relative to the root of fsys. Files the policy
+// skips are left out and reported to opts.OnSkip, files larger than opts.MaxFileSize are
+// handled as opts.LargeFiles says. Binary files are embedded base64 encoded and never templated.
+// Unless opts.NoGitignore is set, the .gitignore files of fsys and its directories exclude the
+// paths they match before the policy is asked.
+//
+// When fsys implements ReadLinkFS, symlinks pointing inside the tree are recorded as symlinks
+// (see FileTypeSymlink) rather than duplicated. Symlinks to files outside the tree are embedded
+// as the file they point to, those to directories outside the tree are skipped.
+func (e *Extractor) ExtractFS(
+	ctx context.Context, fsys fs.FS, schema *TemplateSchema, opts ExtractOptions,
+) (*TemplateSchema, error) {
+	schema.SchemaVersion = CurrentSchemaVersion
+	if schema.Files == nil {
+		schema.Files = []FileSpec{}
+	}
+	schema.EnvConfig = []EnvVariable{}
+
+	ignores := gitignores{}
+	err := fs.WalkDir(fsys, ".", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// Stop walking as soon as the caller cancels
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Skip directories and files that should be skipped
+		relPath := filepath.FromSlash(path)
+		if !opts.NoGitignore && path != "." && ignores.ignores(path, entry.IsDir()) {
+			opts.skip(relPath, SkipReasonGitignore)
+			if entry.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			if opts.NoGitignore {
+				return nil
+			}
+			return ignores.load(fsys, path)
+		}
+		if e.Policy.ShouldSkip(relPath) {
+			opts.skip(relPath, e.skipReason(relPath))
+			return nil
+		}
+
+		if entry.Type()&fs.ModeSymlink != 0 {
+			link, follow, err := e.symlinkSpec(fsys, path)
+			switch {
+			case err != nil:
+				return err
+			case link != nil:
+				schema.Files = append(schema.Files, *link)
+				return nil
+			case !follow:
+				opts.skip(relPath, SkipReasonSymlink)
+				return nil
+			}
+		}
+
+		// Leave large files out before reading them
+		if opts.LargeFiles == LargeFileSkip && opts.MaxFileSize > 0 {
+			info, err := fs.Stat(fsys, path)
+			if err != nil {
+				return err
+			}
+			if opts.tooLarge(info.Size()) {
+				opts.skip(relPath, SkipReasonTooLarge)
+				return nil
+			}
+		}
+
+		content, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+		fileSpec := e.fileSpec(relPath, content)
+		if opts.tooLarge(fileSpec.Size) {
+			fileSpec.External = opts.LargeFiles == LargeFileExternalize
+			if opts.OnLargeFile != nil {
+				opts.OnLargeFile(relPath, fileSpec.Size)
+			}
+		}
+
+		schema.Files = append(schema.Files, fileSpec)
+		schema.EnvConfig = append(schema.EnvConfig, e.parseEnvFile(fileSpec)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Variables of the root env file come first, nested ones keep walk order
+	sort.SliceStable(schema.EnvConfig, func(i, j int) bool {
+		return strings.Count(schema.EnvConfig[i].Source, "/") < strings.Count(schema.EnvConfig[j].Source, "/")
+	})
+
+	// Go modules declared by the template type only apply to the go.mod files extracted
+	schema.GoModules = slices.DeleteFunc(schema.GoModules, func(module GoModule) bool {
+		return !slices.ContainsFunc(schema.Files, func(file FileSpec) bool {
+			return filepath.ToSlash(file.Path) == module.Path && !file.IsSymlink()
+		})
+	})
+	// Likewise for the workspace packages and their package.json files
+	schema.Packages = slices.DeleteFunc(schema.Packages, func(pkg WorkspacePackage) bool {
+		return !slices.ContainsFunc(schema.Files, func(file FileSpec) bool {
+			return filepath.ToSlash(file.Path) == path.Join(pkg.Dir, "package.json")
+		})
+	})
+
+	schema.Hash = CalculateSchemaHash(schema)
+
+	// Compress large files in parallel
+	if err := CompressExtracted(ctx, schema, opts); err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+// skipReason returns why the policy skips path
+func (e *Extractor) skipReason(path string) string {
+	if explainer, ok := e.Policy.(SkipExplainer); ok {
+		if reason := explainer.SkipReason(path); reason != "" {
+			return reason
+		}
+	}
+	return SkipReasonPolicy
+}
+
+// symlinkSpec returns the FileSpec recording the symlink at path when it points inside fsys.
+// Otherwise follow tells whether the file it points to can be embedded instead.
+func (e *Extractor) symlinkSpec(fsys fs.FS, path string) (link *FileSpec, follow bool, err error) {
+	if links, ok := fsys.(ReadLinkFS); ok {
+		target, err := links.ReadLink(path)
+		if err != nil {
+			return nil, false, err
+		}
+		if _, inside := schema.SymlinkTarget(path, target); inside {
+			return &FileSpec{
+				Path:   filepath.FromSlash(path),
+				Type:   FileTypeSymlink,
+				Target: target,
+				Hash:   CalculateContentHash(target),
+			}, false, nil
+		}
+	}
+
+	info, err := fs.Stat(fsys, path)
+	if err != nil {
+		return nil, false, err
+	}
+	return nil, !info.IsDir(), nil
+}
+
+// fileSpec builds the FileSpec of a single file (go-fsck pattern: always include full content).
+// Binary files are base64 encoded and never templated, whatever the policy says.
+func (e *Extractor) fileSpec(relPath string, content []byte) FileSpec {
+	stored, encoding := schema.EncodeContent(content)
+	fileSpec := FileSpec{
+		Path:     relPath,
+		Template: encoding == "" && e.Policy.ShouldTemplate(relPath),
+		Content:  stored, // Compressed once the walk is done
+		Encoding: encoding,
+		Size:     int64(len(content)),
+		Hash:     CalculateContentHash(string(content)),
+	}
+
+	// Add mappings for templated files
+	if fileSpec.Template {
+		fileSpec.Mappings = e.Policy.GetMappings(relPath)
+	}
+
+	if patcher, ok := e.Policy.(PatchPolicy); ok && encoding == "" {
+		switch filepath.Ext(relPath) {
+		case ".json":
+			fileSpec.JSONPatch = patcher.GetPatches(relPath)
+		case ".yml", ".yaml":
+			fileSpec.YAMLPatch = patcher.GetPatches(relPath)
+		}
+	}
+
+	return fileSpec
+}
+
+// parseEnvFile returns the variables declared by file when it is an env example file
+func (e *Extractor) parseEnvFile(file FileSpec) []EnvVariable {
+	if e.ParseEnv == nil || filepath.Base(file.Path) != EnvExampleFile || file.Encoding != "" {
+		return nil
+	}
+
+	source := filepath.ToSlash(file.Path)
+	var component string
+	if policy, ok := e.Policy.(EnvComponentPolicy); ok {
+		component = policy.EnvComponent(source)
+	}
+
+	envVars := e.ParseEnv(file.Content)
+	for i := range envVars {
+		envVars[i].Source = source
+		envVars[i].Component = component
+	}
+	return envVars
+}
@@ -0,0 +1,281 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// testPolicy templates markdown files, skips anything under "build" and maps "acme"
+type testPolicy struct{}
+
+func (testPolicy) ShouldSkip(path string) bool {
+	return strings.Contains(path, "build")
+}
+
+func (testPolicy) ShouldTemplate(filePath string) bool {
+	return strings.HasSuffix(filePath, ".md")
+}
+
+func (testPolicy) GetMappings(string) []Mapping {
+	return []Mapping{{Find: "acme", Replace: "{{.ProjectName}}"}}
+}
+
+func TestExtractorExtract(t *testing.T) {
+	sourceDir := t.TempDir()
+	files := map[string]string{
+		"README.md":         "# acme",
+		"src/main.txt":      "plain",
+		"build/output.txt":  "ignored",
+		"logo.bin":          "\x00\x01binary",
+		EnvExampleFile:      "PORT=8080",
+		"-web/.env.example": "API_URL=http://localhost",
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(sourceDir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	extractor := &Extractor{
+		Policy: testPolicy{},
+		ParseEnv: func(content string) []EnvVariable {
+			name, example, _ := strings.Cut(content, "=")
+			return []EnvVariable{{Name: name, Example: example}}
+		},
+	}
+
+	var skipped []SkippedFile
+	opts := ExtractOptions{OnSkip: func(file SkippedFile) { skipped = append(skipped, file) }}
+	schema, err := extractor.Extract(context.Background(), sourceDir,
+		&TemplateSchema{Name: "test", Type: "test", Version: "1.0.0"}, opts)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	// testPolicy does not explain its skips
+	wantSkipped := SkippedFile{Path: filepath.Join("build", "output.txt"), Reason: SkipReasonPolicy}
+	if len(skipped) != 1 || skipped[0] != wantSkipped {
+		t.Errorf("skipped = %+v, want %+v", skipped, wantSkipped)
+	}
+
+	byPath := make(map[string]FileSpec)
+	for _, file := range schema.Files {
+		byPath[file.Path] = file
+	}
+	if _, exists := byPath[filepath.Join("build", "output.txt")]; exists {
+		t.Error("skipped files must not be extracted")
+	}
+
+	readme := byPath["README.md"]
+	if !readme.Template || len(readme.Mappings) != 1 || readme.Hash != CalculateContentHash("# acme") {
+		t.Errorf("README.md = %+v, want templated with one mapping and a content hash", readme)
+	}
+	if main := byPath[filepath.Join("src", "main.txt")]; main.Template || len(main.Mappings) != 0 {
+		t.Errorf("src/main.txt = %+v, want a static file", main)
+	}
+
+	// Binary files are embedded base64 encoded, whatever the policy
+	logo := byPath["logo.bin"]
+	if logo.Encoding != EncodingBase64 || logo.Template || logo.Hash != CalculateContentHash("\x00\x01binary") {
+		t.Errorf("logo.bin = %+v, want an encoded static file hashed over its raw content", logo)
+	}
+	if content, err := ResolveContent(schema, logo); err != nil || content != "\x00\x01binary" {
+		t.Errorf("ResolveContent(logo.bin) = %q, %v", content, err)
+	}
+
+	// The root env file comes first even though "-web" sorts before it
+	wantEnv := []EnvVariable{
+		{Name: "PORT", Example: "8080", Source: EnvExampleFile},
+		{Name: "API_URL", Example: "http://localhost", Source: "-web/" + EnvExampleFile},
+	}
+	if len(schema.EnvConfig) != len(wantEnv) {
+		t.Fatalf("EnvConfig = %+v, want %+v", schema.EnvConfig, wantEnv)
+	}
+	for i, want := range wantEnv {
+		if schema.EnvConfig[i] != want {
+			t.Errorf("EnvConfig[%d] = %+v, want %+v", i, schema.EnvConfig[i], want)
+		}
+	}
+	if schema.Hash != CalculateSchemaHash(schema) {
+		t.Error("schema hash was not calculated")
+	}
+}
+
+func TestExtractorGitignore(t *testing.T) {
+	fsys := fstest.MapFS{
+		".gitignore":           {Data: []byte("# artifacts\nout/\n.env.*\n!.env.example\n")},
+		".env.local":           {Data: []byte("SECRET=1\n")},
+		".env.example":         {Data: []byte("PORT=8080\n")},
+		"out/app":              {Data: []byte("binary\n")},
+		"web/.gitignore":       {Data: []byte("*.cache\n/generated.ts\n")},
+		"web/index.ts":         {Data: []byte("export {}\n")},
+		"web/generated.ts":     {Data: []byte("export {}\n")},
+		"web/lib/generated.ts": {Data: []byte("export {}\n")},
+		"web/lib/x.cache":      {Data: []byte("cached\n")},
+		"lib/x.cache":          {Data: []byte("kept outside web\n")},
+	}
+
+	paths := func(opts ExtractOptions) []string {
+		schema, err := (&Extractor{Policy: testPolicy{}}).ExtractFS(context.Background(), fsys,
+			&TemplateSchema{Name: "test", Type: "test", Version: "1.0.0"}, opts)
+		if err != nil {
+			t.Fatalf("ExtractFS() error = %v", err)
+		}
+		var paths []string
+		for _, file := range schema.Files {
+			paths = append(paths, filepath.ToSlash(file.Path))
+		}
+		return paths
+	}
+
+	var skipped []SkippedFile
+	got := paths(ExtractOptions{OnSkip: func(file SkippedFile) { skipped = append(skipped, file) }})
+	want := []string{".env.example", ".gitignore", "lib/x.cache", "web/.gitignore", "web/index.ts", "web/lib/generated.ts"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractFS() files = %v, want %v", got, want)
+	}
+	wantSkipped := []SkippedFile{
+		{Path: ".env.local", Reason: SkipReasonGitignore},
+		{Path: "out", Reason: SkipReasonGitignore},
+		{Path: filepath.Join("web", "generated.ts"), Reason: SkipReasonGitignore},
+		{Path: filepath.Join("web", "lib", "x.cache"), Reason: SkipReasonGitignore},
+	}
+	if !reflect.DeepEqual(skipped, wantSkipped) {
+		t.Errorf("skipped = %+v, want %+v", skipped, wantSkipped)
+	}
+
+	if got := paths(ExtractOptions{NoGitignore: true}); len(got) != len(fsys) {
+		t.Errorf("ExtractFS() with NoGitignore files = %v, want all of them", got)
+	}
+}
+
+func TestExtractorSymlinks(t *testing.T) {
+	sourceDir := t.TempDir()
+	outsideDir := t.TempDir()
+	for path, content := range map[string]string{
+		filepath.Join(sourceDir, "config", "eslint.json"): "{}",
+		filepath.Join(outsideDir, "LICENSE"):              "MIT",
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	links := map[string]string{
+		"pkg/web/eslint.json": "../../config/eslint.json",
+		"pkg/api/config":      "../../config",
+		"LICENSE":             filepath.Join(outsideDir, "LICENSE"),
+		"vendor":              outsideDir,
+	}
+	for path, target := range links {
+		fullPath := filepath.Join(sourceDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(filepath.FromSlash(target), fullPath); err != nil {
+			t.Skipf("symlinks unsupported: %v", err)
+		}
+	}
+
+	var skipped []SkippedFile
+	opts := ExtractOptions{OnSkip: func(file SkippedFile) { skipped = append(skipped, file) }}
+	schema, err := (&Extractor{Policy: testPolicy{}}).Extract(context.Background(), sourceDir,
+		&TemplateSchema{Name: "test", Type: "test", Version: "1.0.0", Variables: map[string]Variable{}}, opts)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	byPath := make(map[string]FileSpec)
+	for _, file := range schema.Files {
+		byPath[filepath.ToSlash(file.Path)] = file
+	}
+	for _, path := range []string{"pkg/web/eslint.json", "pkg/api/config"} {
+		if file := byPath[path]; !file.IsSymlink() || file.Target != links[path] {
+			t.Errorf("%s = %+v, want a symlink to %s", path, file, links[path])
+		}
+	}
+	if _, exists := byPath["pkg/api/config/eslint.json"]; exists {
+		t.Error("the directory a symlink points to must not be duplicated")
+	}
+	if license := byPath["LICENSE"]; license.IsSymlink() || license.Size != 3 {
+		t.Errorf("LICENSE = %+v, want the file outside the project embedded", license)
+	}
+	if len(skipped) != 1 || skipped[0] != (SkippedFile{Path: "vendor", Reason: SkipReasonSymlink}) {
+		t.Errorf("skipped = %+v, want vendor skipped as a symlink to a directory outside", skipped)
+	}
+
+	if err := ValidateSchema(schema); err != nil {
+		t.Errorf("ValidateSchema() error = %v", err)
+	}
+}
+
+func TestExtractorLargeFiles(t *testing.T) {
+	sourceDir := t.TempDir()
+	for path, content := range map[string]string{
+		"main.txt":         "small",
+		"package-lock.txt": strings.Repeat("lock\n", 100),
+	} {
+		if err := os.WriteFile(filepath.Join(sourceDir, path), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		policy       LargeFilePolicy
+		wantFiles    int
+		wantExternal bool
+		wantReported bool
+	}{
+		{LargeFileWarn, 2, false, true},
+		{LargeFileSkip, 1, false, false},
+		{LargeFileExternalize, 2, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			var skipped []SkippedFile
+			var reported []string
+			opts := ExtractOptions{
+				MaxFileSize: 100,
+				LargeFiles:  tt.policy,
+				OnSkip:      func(file SkippedFile) { skipped = append(skipped, file) },
+				OnLargeFile: func(path string, size int64) { reported = append(reported, path) },
+			}
+			schema, err := (&Extractor{Policy: testPolicy{}}).Extract(context.Background(), sourceDir,
+				&TemplateSchema{Name: "test", Type: "test", Version: "1.0.0"}, opts)
+			if err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			}
+
+			if len(schema.Files) != tt.wantFiles {
+				t.Fatalf("files = %d, want %d", len(schema.Files), tt.wantFiles)
+			}
+			for _, file := range schema.Files {
+				if want := tt.wantExternal && file.Path == "package-lock.txt"; file.External != want {
+					t.Errorf("%s external = %v, want %v", file.Path, file.External, want)
+				}
+			}
+			if got := len(reported) == 1 && reported[0] == "package-lock.txt"; got != tt.wantReported {
+				t.Errorf("OnLargeFile calls = %v", reported)
+			}
+			if tt.policy == LargeFileSkip &&
+				(len(skipped) != 1 || skipped[0] != (SkippedFile{Path: "package-lock.txt", Reason: SkipReasonTooLarge})) {
+				t.Errorf("skipped = %+v, want package-lock.txt too large", skipped)
+			}
+		})
+	}
+
+	if _, err := ParseLargeFilePolicy("truncate"); err == nil {
+		t.Error("ParseLargeFilePolicy(truncate) should fail")
+	}
+}
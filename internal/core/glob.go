@@ -0,0 +1,86 @@
+package core
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// MappingRule applies a set of mappings to every file matching a glob pattern
+type MappingRule struct {
+	Pattern  string
+	Mappings []Mapping
+}
+
+// MatchGlob reports whether a slash-separated relative path matches pattern.
+// Segments support path.Match syntax (*, ?, [a-z]) and "**" matches zero or more
+// whole directories, so "frontend/src/**/*.ts" matches every TypeScript file under src.
+func MatchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(filepath.ToSlash(name), "/"))
+}
+
+// MatchAnyGlob reports whether name matches at least one of patterns
+func MatchAnyGlob(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if MatchGlob(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// MappingsFor collects the mappings of every rule matching name, in rule order
+func MappingsFor(rules []MappingRule, name string) []Mapping {
+	mappings := []Mapping{}
+	for _, rule := range rules {
+		if MatchGlob(rule.Pattern, name) {
+			mappings = append(mappings, rule.Mappings...)
+		}
+	}
+	return mappings
+}
+
+// PatchRule associates structured patches with a file glob pattern
+type PatchRule struct {
+	Pattern string
+	Patches []Patch
+}
+
+// PatchesFor collects the patches of every rule matching name, in rule order
+func PatchesFor(rules []PatchRule, name string) []Patch {
+	var patches []Patch
+	for _, rule := range rules {
+		if MatchGlob(rule.Pattern, name) {
+			patches = append(patches, rule.Patches...)
+		}
+	}
+	return patches
+}
+
+// matchSegments matches path segments against pattern segments
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(rest, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+
+		matched, err := path.Match(pattern[0], name[0])
+		if err != nil || !matched {
+			return false
+		}
+
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
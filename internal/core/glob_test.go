@@ -0,0 +1,54 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "go.mod", name: "go.mod", want: true},
+		{pattern: "go.mod", name: "sub/go.mod", want: false},
+		{pattern: "**/*.go", name: "main.go", want: true},
+		{pattern: "**/*.go", name: "internal/user/handler.go", want: true},
+		{pattern: "**/*.go", name: "README.md", want: false},
+		{pattern: "frontend/src/**/*.ts", name: "frontend/src/config/app.ts", want: true},
+		{pattern: "frontend/src/**/*.ts", name: "frontend/src/app.ts", want: true},
+		{pattern: "frontend/src/**/*.ts", name: "src/app.ts", want: false},
+		{pattern: "*.md", name: "docs/guide.md", want: false},
+		{pattern: "docs/**", name: "docs/a/b/c.md", want: true},
+		{pattern: ".github/workflows/*.y*ml", name: ".github/workflows/ci.yml", want: true},
+		{pattern: "[", name: "[", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			if got := MatchGlob(tt.pattern, tt.name); got != tt.want {
+				t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMappingsFor(t *testing.T) {
+	importMapping := Mapping{Find: "old/module/", Replace: "{{.GitHubRepo}}/"}
+	configMapping := Mapping{Find: "old-service", Replace: "{{.ProjectName | kebab}}"}
+	rules := []MappingRule{
+		{Pattern: "internal/config/config.go", Mappings: []Mapping{configMapping}},
+		{Pattern: "**/*.go", Mappings: []Mapping{importMapping}},
+	}
+
+	got := MappingsFor(rules, "internal/config/config.go")
+	want := []Mapping{configMapping, importMapping}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MappingsFor() = %v, want %v", got, want)
+	}
+
+	if got := MappingsFor(rules, "README.md"); len(got) != 0 {
+		t.Errorf("Expected no mappings for README.md, got %v", got)
+	}
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// GitignoreFile lists the files of a reference project that extraction leaves out, unless
+// ExtractOptions.NoGitignore is set. Every directory may have its own.
+const GitignoreFile = ".gitignore"
+
+// IgnoreRules are the patterns of a .gitignore or .templateignore file, see ParseIgnore
+type IgnoreRules []ignoreRule
+
+// ignoreRule is a pattern of an ignore file
+type ignoreRule struct {
+	pattern string
+	negate  bool
+	dirOnly bool
+}
+
+// ParseIgnore parses the subset of .gitignore syntax ignore files support: blank lines and #
+// comments are ignored, ! negates a pattern, a trailing / only matches directories and a pattern
+// without a / but the trailing one matches at any depth. Patterns are globs (see MatchGlob)
+// relative to the directory of the ignore file.
+func ParseIgnore(content string) IgnoreRules {
+	var rules IgnoreRules
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var rule ignoreRule
+		if rest, ok := strings.CutPrefix(line, "!"); ok {
+			rule.negate, line = true, rest
+		}
+		if rest, ok := strings.CutSuffix(line, "/"); ok {
+			rule.dirOnly, line = true, rest
+		}
+		if !strings.Contains(line, "/") {
+			line = "**/" + line
+		}
+		rule.pattern = strings.TrimPrefix(line, "/")
+		if rule.pattern != "" {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// Ignores reports whether the rules ignore the slash-separated file path, because it or one of
+// its directories matches. The last matching rule wins.
+func (rules IgnoreRules) Ignores(path string) bool {
+	ignored, _ := rules.match(path, false)
+	return ignored
+}
+
+// match reports whether the rules ignore path, a directory when dir is set. matched is false
+// when no rule applies to it.
+func (rules IgnoreRules) match(path string, dir bool) (ignored, matched bool) {
+	segments := strings.Split(path, "/")
+	for _, rule := range rules {
+		for i := 1; i <= len(segments); i++ {
+			if rule.dirOnly && i == len(segments) && !dir {
+				break // Files never match directory patterns
+			}
+			if MatchGlob(rule.pattern, strings.Join(segments[:i], "/")) {
+				ignored, matched = !rule.negate, true
+				break
+			}
+		}
+	}
+	return ignored, matched
+}
+
+// gitignores holds the .gitignore rules of the directories walked so far, by slash-separated
+// directory
+type gitignores map[string]IgnoreRules
+
+// load reads the .gitignore of dir, if any
+func (g gitignores) load(fsys fs.FS, dir string) error {
+	content, err := fs.ReadFile(fsys, path.Join(dir, GitignoreFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	g[dir] = ParseIgnore(string(content))
+	return nil
+}
+
+// ignores reports whether the .gitignore files of the directories of the slash-separated
+// path, a directory when dir is set, ignore it. Deeper files override the rules of their parents.
+func (g gitignores) ignores(filePath string, dir bool) bool {
+	var ignored bool
+	for base, rest := ".", filePath; ; {
+		if ignoredHere, matched := g[base].match(rest, dir); matched {
+			ignored = ignoredHere
+		}
+		segment, tail, ok := strings.Cut(rest, "/")
+		if !ok {
+			return ignored
+		}
+		base, rest = path.Join(base, segment), tail
+	}
+}
@@ -0,0 +1,28 @@
+package core
+
+import "testing"
+
+func TestIgnoreRules(t *testing.T) {
+	rules := ParseIgnore("*.log\nbuild/\n/secrets.txt\n!keep.log\ndocs/**/*.tmp\n")
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"app.log", true},
+		{"logs/app.log", true},
+		{"keep.log", false},
+		{"build/out.bin", true},
+		{"src/build/out.bin", true},
+		{"build", false}, // A file, not the directory
+		{"secrets.txt", true},
+		{"config/secrets.txt", false},
+		{"docs/a/b/c.tmp", true},
+		{"src/main.go", false},
+	}
+
+	for _, tt := range tests {
+		if got := rules.Ignores(tt.path); got != tt.want {
+			t.Errorf("Ignores(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package core
+
+import "sort"
+
+// FileStats describes how a single file is stored in a schema
+type FileStats struct {
+	Path       string  `json:"path"`
+	Size       int64   `json:"size"`
+	Stored     int64   `json:"stored"`
+	Ratio      float64 `json:"ratio"`
+	Compressed bool    `json:"compressed"`
+	Shared     bool    `json:"shared"`
+}
+
+// DuplicateGroup lists files with identical content
+type DuplicateGroup struct {
+	Hash  string   `json:"hash"`
+	Size  int64    `json:"size"`
+	Paths []string `json:"paths"`
+}
+
+// Inspection summarizes the storage of a schema
+type Inspection struct {
+	Files       []FileStats      `json:"files"`
+	TotalSize   int64            `json:"total_size"`
+	TotalStored int64            `json:"total_stored"`
+	Duplicates  []DuplicateGroup `json:"duplicates"`
+	Largest     []FileStats      `json:"largest"`
+}
+
+// InspectSchema reports per-file sizes and compression ratios, groups of files with identical
+// content and the top largest files by stored size. Blobs shared through ContentRef are counted once.
+func InspectSchema(schema *TemplateSchema, top int) *Inspection {
+	inspection := &Inspection{
+		Files:      []FileStats{},
+		Duplicates: []DuplicateGroup{},
+	}
+
+	byHash := make(map[string]*DuplicateGroup)
+	var hashes []string
+
+	for _, file := range schema.Files {
+		stored, _ := StoredContent(schema, file)
+		stats := FileStats{
+			Path:       file.Path,
+			Size:       file.Size,
+			Stored:     int64(len(stored)),
+			Compressed: file.Compressed,
+			Shared:     file.ContentRef != "",
+		}
+		if stats.Size > 0 {
+			stats.Ratio = float64(stats.Stored) / float64(stats.Size)
+		}
+		inspection.Files = append(inspection.Files, stats)
+		inspection.TotalSize += stats.Size
+
+		if file.ContentRef == "" {
+			inspection.TotalStored += stats.Stored
+		}
+
+		if file.Hash == "" || file.IsSymlink() {
+			continue
+		}
+		group, exists := byHash[file.Hash]
+		if !exists {
+			group = &DuplicateGroup{Hash: file.Hash, Size: file.Size}
+			byHash[file.Hash] = group
+			hashes = append(hashes, file.Hash)
+		}
+		group.Paths = append(group.Paths, file.Path)
+	}
+
+	for _, blob := range schema.Blobs {
+		inspection.TotalStored += int64(len(blob))
+	}
+
+	for _, hash := range hashes {
+		if group := byHash[hash]; len(group.Paths) > 1 {
+			inspection.Duplicates = append(inspection.Duplicates, *group)
+		}
+	}
+	sort.SliceStable(inspection.Duplicates, func(i, j int) bool {
+		a, b := inspection.Duplicates[i], inspection.Duplicates[j]
+		return a.Size*int64(len(a.Paths)) > b.Size*int64(len(b.Paths))
+	})
+
+	largest := make([]FileStats, len(inspection.Files))
+	copy(largest, inspection.Files)
+	sort.SliceStable(largest, func(i, j int) bool {
+		return largest[i].Stored > largest[j].Stored
+	})
+	if top >= 0 && len(largest) > top {
+		largest = largest[:top]
+	}
+	inspection.Largest = largest
+
+	return inspection
+}
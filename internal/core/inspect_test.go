@@ -0,0 +1,33 @@
+package core
+
+import "testing"
+
+func TestInspectSchema(t *testing.T) {
+	schema := &TemplateSchema{
+		Files: []FileSpec{
+			{Path: "a.txt", Content: "aaaa", Hash: "h1", Size: 4},
+			{Path: "b.txt", Content: "aaaa", Hash: "h1", Size: 4},
+			{Path: "big.txt", Content: "0123456789", Hash: "h2", Size: 20, Compressed: true},
+		},
+	}
+
+	inspection := InspectSchema(schema, 2)
+
+	if inspection.TotalSize != 28 || inspection.TotalStored != 18 {
+		t.Errorf("totals = %d/%d, want 28/18", inspection.TotalSize, inspection.TotalStored)
+	}
+	if inspection.Files[2].Ratio != 0.5 {
+		t.Errorf("ratio of big.txt = %v, want 0.5", inspection.Files[2].Ratio)
+	}
+	if len(inspection.Duplicates) != 1 || len(inspection.Duplicates[0].Paths) != 2 {
+		t.Errorf("duplicates = %+v, want one group of two files", inspection.Duplicates)
+	}
+	if len(inspection.Largest) != 2 || inspection.Largest[0].Path != "big.txt" {
+		t.Errorf("largest = %+v, want big.txt first and 2 entries", inspection.Largest)
+	}
+
+	DedupeSchema(schema)
+	if got := InspectSchema(schema, 0).TotalStored; got != 14 {
+		t.Errorf("TotalStored after dedupe = %d, want 14", got)
+	}
+}
@@ -0,0 +1,354 @@
+package core
+
+import (
+	"fmt"
+	"go/token"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Naming limits for ProjectName and GitHubRepo, following GitHub's own rules for the repository part
+const (
+	MaxProjectNameLength = 100
+	maxRepoOwnerLength   = 39
+	maxRepoNameLength    = 100
+	maxNpmNameLength     = 214
+)
+
+// derivedVariables are computed from ProjectName and GitHubRepo (see DeriveVariables)
+// and available to every template, so mappings don't re-derive them inline
+var derivedVariables = []string{
+	"ProjectNameKebab", "ProjectNameSnake", "ProjectNamePascal", "ProjectNameCamel", "ProjectNamePackage",
+	"RepoOwner", "RepoName",
+}
+
+// transliterations are the ASCII spellings of the letters Unicode does not decompose into an
+// ASCII letter and diacritics
+var transliterations = map[rune]string{
+	'ß': "ss", 'Æ': "AE", 'æ': "ae", 'Œ': "OE", 'œ': "oe", 'Ø': "O", 'ø': "o", 'Ł': "L", 'ł': "l",
+	'Đ': "D", 'đ': "d", 'Ð': "D", 'ð': "d", 'Þ': "Th", 'þ': "th", 'ı': "i", 'Ħ': "H", 'ħ': "h",
+}
+
+// nodeBuiltins are Node.js core modules, which npm refuses as package names
+var nodeBuiltins = map[string]bool{
+	"assert": true, "buffer": true, "cluster": true, "console": true, "crypto": true, "dgram": true,
+	"dns": true, "events": true, "fs": true, "http": true, "http2": true, "https": true, "module": true,
+	"net": true, "os": true, "path": true, "process": true, "punycode": true, "querystring": true,
+	"readline": true, "repl": true, "stream": true, "timers": true, "tls": true, "tty": true, "url": true,
+	"util": true, "v8": true, "vm": true, "worker-threads": true, "zlib": true,
+}
+
+// ValidateProjectName checks that name is usable as a project name: letters, digits, spaces,
+// '-', '_' and '.', with at least one letter or digit. Letters without an ASCII spelling are
+// accepted but dropped from the derived identifiers, see NameWarnings.
+func ValidateProjectName(name string) error {
+	if name == "" {
+		return fmt.Errorf("project name is required")
+	}
+	if len(name) > MaxProjectNameLength {
+		return fmt.Errorf("project name must be at most %d characters", MaxProjectNameLength)
+	}
+
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(" -_.", r) {
+			return fmt.Errorf("project name %q contains invalid character %q", name, r)
+		}
+	}
+	if len(SplitWords(name)) == 0 {
+		return fmt.Errorf("project name %q must contain a letter or digit", name)
+	}
+
+	return nil
+}
+
+// GitHubRepo is a GitHub repository, see ParseGitHubRepo
+type GitHubRepo struct {
+	Owner string
+	Name  string
+}
+
+// String returns the repository in the owner/repo format
+func (r GitHubRepo) String() string {
+	return r.Owner + "/" + r.Name
+}
+
+// ParseGitHubRepo parses a GitHub repository given as owner/repo or as a URL of it, such as
+// https://github.com/owner/repo, github.com/owner/repo or git@github.com:owner/repo.git, and
+// checks that its owner and name are valid GitHub names
+func ParseGitHubRepo(repo string) (GitHubRepo, error) {
+	path := strings.TrimSpace(repo)
+	if rest, ok := strings.CutPrefix(path, "git@github.com:"); ok {
+		path = strings.TrimSuffix(rest, ".git")
+	} else if u, err := url.Parse(path); err == nil && u.Host != "" {
+		if host := strings.ToLower(u.Hostname()); host != "github.com" && host != "www.github.com" {
+			return GitHubRepo{}, fmt.Errorf("github repo %q is not a github.com URL", repo)
+		}
+		path = strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	} else if rest, ok := strings.CutPrefix(path, "github.com/"); ok {
+		path = strings.TrimSuffix(strings.Trim(rest, "/"), ".git")
+	}
+
+	owner, name, found := strings.Cut(path, "/")
+	if !found || strings.Contains(name, "/") {
+		return GitHubRepo{}, fmt.Errorf("github repo %q must have the format owner/repo", repo)
+	}
+
+	if owner == "" || len(owner) > maxRepoOwnerLength {
+		return GitHubRepo{}, fmt.Errorf("github repo owner %q must be 1 to %d characters", owner, maxRepoOwnerLength)
+	}
+	if strings.HasPrefix(owner, "-") || strings.HasSuffix(owner, "-") {
+		return GitHubRepo{}, fmt.Errorf("github repo owner %q must not start or end with a hyphen", owner)
+	}
+	if r, found := invalidRune(owner, "-"); found {
+		return GitHubRepo{}, fmt.Errorf("github repo owner %q contains invalid character %q", owner, r)
+	}
+
+	if name == "" || len(name) > maxRepoNameLength {
+		return GitHubRepo{}, fmt.Errorf("github repo name %q must be 1 to %d characters", name, maxRepoNameLength)
+	}
+	if name == "." || name == ".." {
+		return GitHubRepo{}, fmt.Errorf("github repo name %q is reserved", name)
+	}
+	if r, found := invalidRune(name, "-_."); found {
+		return GitHubRepo{}, fmt.Errorf("github repo name %q contains invalid character %q", name, r)
+	}
+
+	return GitHubRepo{Owner: owner, Name: name}, nil
+}
+
+// ValidateGitHubRepo checks that repo is owner/repo or a GitHub URL of it with valid GitHub names,
+// see ParseGitHubRepo
+func ValidateGitHubRepo(repo string) error {
+	_, err := ParseGitHubRepo(repo)
+	return err
+}
+
+// NormalizeGitHubRepo reduces a GitHub URL to the owner/repo format templates get as GitHubRepo,
+// leaving repo unchanged when it does not parse (reported by ValidateGitHubRepo)
+func NormalizeGitHubRepo(repo string) string {
+	parsed, err := ParseGitHubRepo(repo)
+	if err != nil {
+		return repo
+	}
+	return parsed.String()
+}
+
+// DeriveVariables computes the identifiers derived from ProjectName and GitHubRepo:
+// ProjectNameKebab (my-app), ProjectNameSnake (my_app), ProjectNamePascal (MyApp),
+// ProjectNameCamel (myApp), ProjectNamePackage (myapp), RepoOwner and RepoName
+func DeriveVariables(variables *TemplateVariables) map[string]string {
+	owner, name, _ := strings.Cut(NormalizeGitHubRepo(variables.GitHubRepo), "/")
+	return map[string]string{
+		"ProjectNameKebab":   KebabCase(variables.ProjectName),
+		"ProjectNameSnake":   SnakeCase(variables.ProjectName),
+		"ProjectNamePascal":  PascalCase(variables.ProjectName),
+		"ProjectNameCamel":   CamelCase(variables.ProjectName),
+		"ProjectNamePackage": PackageCase(variables.ProjectName),
+		"RepoOwner":          owner,
+		"RepoName":           name,
+	}
+}
+
+// NameWarnings reports the identifiers derived from the project name (see DeriveVariables) that
+// are not valid where templates commonly use them: ProjectNameKebab as an npm package name and
+// ProjectNamePackage as a Go package name. Generation still goes ahead.
+func NameWarnings(variables *TemplateVariables) []string {
+	name := variables.ProjectName
+	if name == "" {
+		return nil
+	}
+
+	var warnings []string
+	if dropped := untransliterated(name); dropped != "" {
+		warnings = append(warnings, fmt.Sprintf(
+			"project name %q has characters without an ASCII spelling (%s), left out of derived identifiers",
+			name, dropped))
+	}
+	if err := CheckNpmName(KebabCase(name)); err != nil {
+		warnings = append(warnings, fmt.Sprintf("ProjectNameKebab: %v", err))
+	}
+	if pkg := PackageCase(name); pkg == "" {
+		warnings = append(warnings, fmt.Sprintf("ProjectNamePackage of %q is empty, not a valid Go package name", name))
+	} else if token.IsKeyword(pkg) {
+		warnings = append(warnings, fmt.Sprintf("ProjectNamePackage %q is a Go keyword, not a valid package name", pkg))
+	}
+	return warnings
+}
+
+// CheckNpmName checks that name is a valid name for a new npm package: at most 214 lowercase
+// URL-safe characters, not starting with '.' or '_', and not a Node.js core module
+func CheckNpmName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("npm package name is empty")
+	case len(name) > maxNpmNameLength:
+		return fmt.Errorf("npm package name %q is longer than %d characters", name, maxNpmNameLength)
+	case strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_"):
+		return fmt.Errorf("npm package name %q must not start with '.' or '_'", name)
+	case nodeBuiltins[name]:
+		return fmt.Errorf("npm package name %q is a Node.js core module", name)
+	case strings.ToLower(name) != name:
+		return fmt.Errorf("npm package name %q must be lowercase", name)
+	}
+	if r, found := invalidRune(name, "-._~"); found {
+		return fmt.Errorf("npm package name %q contains invalid character %q", name, r)
+	}
+	return nil
+}
+
+// CheckModulePath checks that path is a valid Go module path: slash-separated elements of ASCII
+// letters, digits and '-', '.', '_' and '~', none of them empty or starting or ending with a dot
+func CheckModulePath(path string) error {
+	if strings.HasPrefix(path, "-") {
+		return fmt.Errorf("module path %q must not start with a hyphen", path)
+	}
+	for _, element := range strings.Split(path, "/") {
+		if element == "" || strings.HasPrefix(element, ".") || strings.HasSuffix(element, ".") {
+			return fmt.Errorf("module path %q has an empty element or one starting or ending with a dot", path)
+		}
+		if r, found := invalidRune(element, "-._~"); found {
+			return fmt.Errorf("module path %q contains invalid character %q", path, r)
+		}
+	}
+	return nil
+}
+
+// Transliterate spells the letters of s in ASCII, dropping diacritics ("Crème Brûlée" -> "Creme
+// Brulee", "Straße" -> "Strasse"), and replaces the runes without an ASCII spelling with spaces
+func Transliterate(s string) string {
+	var result strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		switch {
+		case r <= unicode.MaxASCII:
+			result.WriteRune(r)
+		case unicode.Is(unicode.Mn, r):
+			// Diacritics split off their letter by the decomposition
+		case transliterations[r] != "":
+			result.WriteString(transliterations[r])
+		default:
+			result.WriteRune(' ')
+		}
+	}
+	return result.String()
+}
+
+// untransliterated returns the letters and digits of s Transliterate cannot spell in ASCII
+func untransliterated(s string) string {
+	var dropped []rune
+	for _, r := range norm.NFD.String(s) {
+		if r > unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) && transliterations[r] == "" {
+			dropped = append(dropped, r)
+		}
+	}
+	return string(dropped)
+}
+
+// SplitWords breaks s into words on separators and lower-to-upper case transitions
+func SplitWords(s string) []string {
+	var words []string
+	var current []rune
+
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = nil
+		}
+	}
+
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+
+	return words
+}
+
+// asciiWords breaks s into words spelled in ASCII, see Transliterate
+func asciiWords(s string) []string {
+	return SplitWords(Transliterate(s))
+}
+
+// PascalCase converts s to PascalCase ("my api" -> "MyApi")
+func PascalCase(s string) string {
+	var result strings.Builder
+	for _, word := range asciiWords(s) {
+		result.WriteString(upperFirst(strings.ToLower(word)))
+	}
+	return result.String()
+}
+
+// CamelCase converts s to camelCase ("my api" -> "myApi")
+func CamelCase(s string) string {
+	words := asciiWords(s)
+	var result strings.Builder
+	for i, word := range words {
+		word = strings.ToLower(word)
+		if i > 0 {
+			word = upperFirst(word)
+		}
+		result.WriteString(word)
+	}
+	return result.String()
+}
+
+// PackageCase converts s to a Go package name, its words lower-cased and joined ("My API" ->
+// "myapi"), prefixed with "pkg" when it would start with a digit
+func PackageCase(s string) string {
+	name := joinLower(s, "")
+	if name != "" && unicode.IsDigit([]rune(name)[0]) {
+		name = "pkg" + name
+	}
+	return name
+}
+
+// KebabCase lower-cases s and joins its words with single hyphens ("My  API!" -> "my-api",
+// "Café Crème" -> "cafe-creme")
+func KebabCase(s string) string {
+	return joinLower(s, "-")
+}
+
+// SnakeCase lower-cases s and joins its words with single underscores ("My API" -> "my_api")
+func SnakeCase(s string) string {
+	return joinLower(s, "_")
+}
+
+func joinLower(s, separator string) string {
+	words := asciiWords(s)
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+	return strings.Join(words, separator)
+}
+
+// upperFirst upper-cases the first rune of s
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	runes := []rune(s)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// invalidRune returns the first rune of s that is neither an ASCII letter or digit nor in extra
+func invalidRune(s, extra string) (rune, bool) {
+	for _, r := range s {
+		isAlphanumeric := r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+		if !isAlphanumeric && !strings.ContainsRune(extra, r) {
+			return r, true
+		}
+	}
+	return 0, false
+}
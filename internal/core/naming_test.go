@@ -0,0 +1,170 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCaseConversions(t *testing.T) {
+	tests := []struct {
+		input  string
+		camel  string
+		pascal string
+		kebab  string
+		snake  string
+	}{
+		{input: "My React App", camel: "myReactApp", pascal: "MyReactApp", kebab: "my-react-app", snake: "my_react_app"},
+		{input: "user-service_v2", camel: "userServiceV2", pascal: "UserServiceV2", kebab: "user-service-v2",
+			snake: "user_service_v2"},
+		{input: "billingAPI", camel: "billingApi", pascal: "BillingApi", kebab: "billing-api", snake: "billing_api"},
+		{input: "  Hello,  World! ", camel: "helloWorld", pascal: "HelloWorld", kebab: "hello-world",
+			snake: "hello_world"},
+		{input: "Crème Brûlée", camel: "cremeBrulee", pascal: "CremeBrulee", kebab: "creme-brulee",
+			snake: "creme_brulee"},
+		{input: "Straße Øst", camel: "strasseOst", pascal: "StrasseOst", kebab: "strasse-ost", snake: "strasse_ost"},
+		{input: "東京 App", camel: "app", pascal: "App", kebab: "app", snake: "app"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := CamelCase(tt.input); got != tt.camel {
+				t.Errorf("CamelCase(%q) = %q, want %q", tt.input, got, tt.camel)
+			}
+			if got := PascalCase(tt.input); got != tt.pascal {
+				t.Errorf("PascalCase(%q) = %q, want %q", tt.input, got, tt.pascal)
+			}
+			if got := KebabCase(tt.input); got != tt.kebab {
+				t.Errorf("KebabCase(%q) = %q, want %q", tt.input, got, tt.kebab)
+			}
+			if got := SnakeCase(tt.input); got != tt.snake {
+				t.Errorf("SnakeCase(%q) = %q, want %q", tt.input, got, tt.snake)
+			}
+		})
+	}
+}
+
+func TestPackageCase(t *testing.T) {
+	for input, want := range map[string]string{"My React App": "myreactapp", "user-service_v2": "userservicev2",
+		"3D Tools": "pkg3dtools"} {
+		if got := PackageCase(input); got != want {
+			t.Errorf("PackageCase(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestValidateProjectName(t *testing.T) {
+	for _, valid := range []string{"My App", "user-service_v2", "api.v2", "Café"} {
+		if err := ValidateProjectName(valid); err != nil {
+			t.Errorf("ValidateProjectName(%q) error = %v", valid, err)
+		}
+	}
+
+	for _, invalid := range []string{"", "---", "my/app", "app; rm -rf", string(make([]byte, 101))} {
+		if err := ValidateProjectName(invalid); err == nil {
+			t.Errorf("ValidateProjectName(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestValidateGitHubRepo(t *testing.T) {
+	for _, valid := range []string{"user/my-app", "my-org/api.v2", "a/b_c"} {
+		if err := ValidateGitHubRepo(valid); err != nil {
+			t.Errorf("ValidateGitHubRepo(%q) error = %v", valid, err)
+		}
+	}
+
+	invalid := []string{
+		"my-app", "user/", "/my-app", "org/group/repo", "-user/app", "user-/app", "us_er/app", "user/..", "user/my app",
+	}
+	for _, repo := range invalid {
+		if err := ValidateGitHubRepo(repo); err == nil {
+			t.Errorf("ValidateGitHubRepo(%q) should fail", repo)
+		}
+	}
+}
+
+func TestNameWarnings(t *testing.T) {
+	tests := []struct {
+		name string
+		want int
+	}{
+		{name: "Billing API", want: 0},
+		{name: "Café Crème", want: 0},
+		{name: "東京 App", want: 1},
+		{name: "東京", want: 3},
+		{name: "Go", want: 1},
+		{name: "HTTP", want: 1},
+	}
+
+	for _, tt := range tests {
+		warnings := NameWarnings(&TemplateVariables{ProjectName: tt.name})
+		if len(warnings) != tt.want {
+			t.Errorf("NameWarnings(%q) = %q, want %d warnings", tt.name, warnings, tt.want)
+		}
+	}
+}
+
+func TestCheckNpmName(t *testing.T) {
+	for _, valid := range []string{"my-app", "app.js", "a~b"} {
+		if err := CheckNpmName(valid); err != nil {
+			t.Errorf("CheckNpmName(%q) error = %v", valid, err)
+		}
+	}
+	for _, invalid := range []string{"", "My-App", ".app", "_app", "fs", "my app", "café"} {
+		if err := CheckNpmName(invalid); err == nil {
+			t.Errorf("CheckNpmName(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestCheckModulePath(t *testing.T) {
+	for _, valid := range []string{"github.com/acme/billing-api", "example.com/app/v2", "myapp"} {
+		if err := CheckModulePath(valid); err != nil {
+			t.Errorf("CheckModulePath(%q) error = %v", valid, err)
+		}
+	}
+	for _, invalid := range []string{"", "github.com//app", "github.com/acme/café", "-app", "github.com/app.", "a b"} {
+		if err := CheckModulePath(invalid); err == nil {
+			t.Errorf("CheckModulePath(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestParseGitHubRepo(t *testing.T) {
+	want := GitHubRepo{Owner: "acme", Name: "billing-api"}
+	for _, input := range []string{
+		"acme/billing-api", "https://github.com/acme/billing-api", "https://github.com/acme/billing-api.git",
+		"http://www.github.com/acme/billing-api/", "github.com/acme/billing-api", "git@github.com:acme/billing-api.git",
+		"ssh://git@github.com/acme/billing-api.git", " acme/billing-api ",
+	} {
+		got, err := ParseGitHubRepo(input)
+		if err != nil || got != want {
+			t.Errorf("ParseGitHubRepo(%q) = %v, %v, want %v", input, got, err, want)
+		}
+	}
+
+	for _, invalid := range []string{
+		"https://gitlab.com/acme/billing-api", "https://github.com/acme/billing-api/tree/main", "https://github.com/acme",
+	} {
+		if _, err := ParseGitHubRepo(invalid); err == nil {
+			t.Errorf("ParseGitHubRepo(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestDeriveVariables(t *testing.T) {
+	derived := DeriveVariables(&TemplateVariables{ProjectName: "Billing API", GitHubRepo: "acme/billing"})
+
+	expected := map[string]string{
+		"ProjectNameKebab":   "billing-api",
+		"ProjectNameSnake":   "billing_api",
+		"ProjectNamePascal":  "BillingApi",
+		"ProjectNameCamel":   "billingApi",
+		"ProjectNamePackage": "billingapi",
+		"RepoOwner":          "acme",
+		"RepoName":           "billing",
+	}
+	if !reflect.DeepEqual(derived, expected) {
+		t.Errorf("DeriveVariables() = %v, want %v", derived, expected)
+	}
+}
@@ -2,27 +2,104 @@ package core
 
 import (
 	"fmt"
+	"sort"
+	"sync"
 )
 
-// TemplateRegistry manages different template types
+// TemplateRegistry manages different template types. It is safe for concurrent use.
 type TemplateRegistry struct {
-	templates map[string]TemplateType
+	mu           sync.RWMutex
+	templates    map[string]TemplateType
+	deprecations map[string]Deprecation
+}
+
+// RegisteredType describes a registered template type at the time List was called
+type RegisteredType struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Category    string       `json:"category,omitempty"`
+	Tags        []string     `json:"tags,omitempty"`
+	Deprecation *Deprecation `json:"deprecation,omitempty"`
+}
+
+// Deprecation marks a template type as deprecated. Deprecated types keep working but warn.
+type Deprecation struct {
+	// ReplacedBy names the successor template type, if any
+	ReplacedBy string `json:"replaced_by,omitempty"`
+}
+
+// Warning describes the deprecation of the template type name
+func (d Deprecation) Warning(name string) string {
+	return deprecationMessage("template type", name, d.ReplacedBy)
+}
+
+// deprecationMessage describes a deprecated template type or schema
+func deprecationMessage(kind, name, replacedBy string) string {
+	if replacedBy == "" {
+		return fmt.Sprintf("%s %s is deprecated", kind, name)
+	}
+	return fmt.Sprintf("%s %s is deprecated, use %s instead", kind, name, replacedBy)
 }
 
 // NewTemplateRegistry creates a new template registry
 func NewTemplateRegistry() *TemplateRegistry {
 	return &TemplateRegistry{
-		templates: make(map[string]TemplateType),
+		templates:    make(map[string]TemplateType),
+		deprecations: make(map[string]Deprecation),
 	}
 }
 
+// Clone returns a new registry holding the template types and deprecations of r, so it can be
+// extended without affecting r
+func (r *TemplateRegistry) Clone() *TemplateRegistry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	clone := NewTemplateRegistry()
+	for name, templateType := range r.templates {
+		clone.templates[name] = templateType
+	}
+	for name, deprecation := range r.deprecations {
+		clone.deprecations[name] = deprecation
+	}
+	return clone
+}
+
 // Register adds a template type to the registry
 func (r *TemplateRegistry) Register(templateType TemplateType) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.templates[templateType.Name()] = templateType
 }
 
+// Replace swaps a registered template type for templateType of the same name, keeping its
+// deprecation. It fails when no template type of that name is registered.
+func (r *TemplateRegistry) Replace(templateType TemplateType) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	name := templateType.Name()
+	if _, exists := r.templates[name]; !exists {
+		return fmt.Errorf("template type not found: %s", name)
+	}
+	r.templates[name] = templateType
+	return nil
+}
+
+// Unregister removes a template type and its deprecation from the registry
+func (r *TemplateRegistry) Unregister(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.templates[name]; !exists {
+		return fmt.Errorf("template type not found: %s", name)
+	}
+	delete(r.templates, name)
+	delete(r.deprecations, name)
+	return nil
+}
+
 // Get retrieves a template type by name
 func (r *TemplateRegistry) Get(name string) (TemplateType, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	template, exists := r.templates[name]
 	if !exists {
 		return nil, fmt.Errorf("template type not found: %s", name)
@@ -30,15 +107,55 @@ func (r *TemplateRegistry) Get(name string) (TemplateType, error) {
 	return template, nil
 }
 
-// List returns all registered template types
-func (r *TemplateRegistry) List() []string {
+// Deprecate marks a registered template type as deprecated
+func (r *TemplateRegistry) Deprecate(name string, deprecation Deprecation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.templates[name]; !exists {
+		return fmt.Errorf("template type not found: %s", name)
+	}
+	r.deprecations[name] = deprecation
+	return nil
+}
+
+// Deprecation returns the deprecation of a template type, if it is deprecated
+func (r *TemplateRegistry) Deprecation(name string) (Deprecation, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	deprecation, deprecated := r.deprecations[name]
+	return deprecation, deprecated
+}
+
+// Names returns the sorted names of all registered template types
+func (r *TemplateRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	names := make([]string, 0, len(r.templates))
 	for name := range r.templates {
 		names = append(names, name)
 	}
+	sort.Strings(names)
 	return names
 }
 
+// List returns a snapshot of all registered template types sorted by name. Later changes to
+// the registry do not affect it.
+func (r *TemplateRegistry) List() []RegisteredType {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	types := make([]RegisteredType, 0, len(r.templates))
+	for name, templateType := range r.templates {
+		registered := RegisteredType{Name: name, Description: TypeDescription(templateType)}
+		registered.Category, registered.Tags = TypeMetadata(templateType)
+		if deprecation, deprecated := r.deprecations[name]; deprecated {
+			registered.Deprecation = &deprecation
+		}
+		types = append(types, registered)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types
+}
+
 // Global registry instance
 var GlobalRegistry = NewTemplateRegistry()
 
@@ -47,12 +164,32 @@ func RegisterTemplate(templateType TemplateType) {
 	GlobalRegistry.Register(templateType)
 }
 
+// ReplaceTemplate swaps a globally registered template type for templateType
+func ReplaceTemplate(templateType TemplateType) error {
+	return GlobalRegistry.Replace(templateType)
+}
+
+// UnregisterTemplate removes a template type from the global registry
+func UnregisterTemplate(name string) error {
+	return GlobalRegistry.Unregister(name)
+}
+
 // GetTemplate retrieves a template type from global registry
 func GetTemplate(name string) (TemplateType, error) {
 	return GlobalRegistry.Get(name)
 }
 
-// ListTemplates returns all registered template types
+// ListTemplates returns the sorted names of all registered template types
 func ListTemplates() []string {
-	return GlobalRegistry.List()
+	return GlobalRegistry.Names()
+}
+
+// DeprecateTemplate marks a globally registered template type as deprecated
+func DeprecateTemplate(name string, deprecation Deprecation) error {
+	return GlobalRegistry.Deprecate(name, deprecation)
+}
+
+// TemplateDeprecation returns the deprecation of a globally registered template type
+func TemplateDeprecation(name string) (Deprecation, bool) {
+	return GlobalRegistry.Deprecation(name)
 }
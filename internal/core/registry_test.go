@@ -0,0 +1,150 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// namedType is a template type stub, only its name is used by the registry
+type namedType struct {
+	TemplateType
+	name string
+}
+
+func (n namedType) Name() string { return n.name }
+
+func TestRegistryDeprecation(t *testing.T) {
+	registry := NewTemplateRegistry()
+	registry.Register(namedType{name: "go-api"})
+	registry.Register(namedType{name: "go-api-v2"})
+
+	if err := registry.Deprecate("missing", Deprecation{}); err == nil {
+		t.Error("Deprecate() should reject unknown template types")
+	}
+	if err := registry.Deprecate("go-api", Deprecation{ReplacedBy: "go-api-v2"}); err != nil {
+		t.Fatalf("Deprecate() error = %v", err)
+	}
+
+	deprecation, deprecated := registry.Deprecation("go-api")
+	if !deprecated || deprecation.ReplacedBy != "go-api-v2" {
+		t.Errorf("Deprecation(go-api) = %+v, %v", deprecation, deprecated)
+	}
+	if _, deprecated := registry.Deprecation("go-api-v2"); deprecated {
+		t.Error("go-api-v2 should not be deprecated")
+	}
+
+	expected := "template type go-api is deprecated, use go-api-v2 instead"
+	if got := deprecation.Warning("go-api"); got != expected {
+		t.Errorf("Warning() = %q, want %q", got, expected)
+	}
+}
+
+// describedType is a template type stub with a description
+type describedType struct {
+	namedType
+	description string
+}
+
+func (d describedType) Description() string { return d.description }
+
+func TestRegistryReplaceAndUnregister(t *testing.T) {
+	registry := NewTemplateRegistry()
+	registry.Register(namedType{name: "go-api"})
+	if err := registry.Deprecate("go-api", Deprecation{}); err != nil {
+		t.Fatalf("Deprecate() error = %v", err)
+	}
+
+	if err := registry.Replace(namedType{name: "missing"}); err == nil {
+		t.Error("Replace() should reject unknown template types")
+	}
+	replacement := describedType{namedType: namedType{name: "go-api"}, description: "Reloaded"}
+	if err := registry.Replace(replacement); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	if got, _ := registry.Get("go-api"); got != TemplateType(replacement) {
+		t.Errorf("Get(go-api) = %v after Replace()", got)
+	}
+	if _, deprecated := registry.Deprecation("go-api"); !deprecated {
+		t.Error("Replace() should keep the deprecation")
+	}
+
+	if err := registry.Unregister("go-api"); err != nil {
+		t.Fatalf("Unregister() error = %v", err)
+	}
+	if _, err := registry.Get("go-api"); err == nil {
+		t.Error("Get() found an unregistered template type")
+	}
+	if _, deprecated := registry.Deprecation("go-api"); deprecated {
+		t.Error("Unregister() should drop the deprecation")
+	}
+	if err := registry.Unregister("go-api"); err == nil {
+		t.Error("Unregister() should reject unknown template types")
+	}
+}
+
+func TestRegistryList(t *testing.T) {
+	registry := NewTemplateRegistry()
+	registry.Register(describedType{namedType: namedType{name: "go-api"}, description: "Go API"})
+	registry.Register(namedType{name: "frontend"})
+	if err := registry.Deprecate("frontend", Deprecation{ReplacedBy: "go-api"}); err != nil {
+		t.Fatalf("Deprecate() error = %v", err)
+	}
+
+	list := registry.List()
+	if len(list) != 2 || list[0].Name != "frontend" || list[1].Name != "go-api" {
+		t.Fatalf("List() = %+v, want frontend and go-api", list)
+	}
+	if list[0].Description != "frontend template type" || list[1].Description != "Go API" {
+		t.Errorf("List() descriptions = %q, %q", list[0].Description, list[1].Description)
+	}
+	if list[0].Deprecation == nil || list[0].Deprecation.ReplacedBy != "go-api" || list[1].Deprecation != nil {
+		t.Errorf("List() deprecations = %+v, %+v", list[0].Deprecation, list[1].Deprecation)
+	}
+
+	// The snapshot does not follow later changes
+	registry.Register(namedType{name: "fullstack"})
+	if len(list) != 2 {
+		t.Errorf("List() snapshot changed to %+v", list)
+	}
+}
+
+func TestRegistryConcurrentUse(t *testing.T) {
+	registry := NewTemplateRegistry()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("type-%d", i)
+			registry.Register(namedType{name: name})
+			_, _ = registry.Get(name)
+			_ = registry.List()
+			_ = registry.Deprecate(name, Deprecation{})
+			_ = registry.Replace(namedType{name: name})
+		}(i)
+	}
+	wg.Wait()
+
+	if names := registry.Names(); len(names) != 10 {
+		t.Errorf("Names() = %v, want 10 template types", names)
+	}
+}
+
+func TestSchemaDeprecationWarning(t *testing.T) {
+	schema := &TemplateSchema{Name: "billing-api"}
+	if got := schema.DeprecationWarning(); got != "" {
+		t.Errorf("DeprecationWarning() = %q for a current schema", got)
+	}
+
+	schema.Deprecated = true
+	if got := schema.DeprecationWarning(); got != "template schema billing-api is deprecated" {
+		t.Errorf("DeprecationWarning() = %q", got)
+	}
+
+	schema.ReplacedBy = "billing-api-v2"
+	expected := "template schema billing-api is deprecated, use billing-api-v2 instead"
+	if got := schema.DeprecationWarning(); got != expected {
+		t.Errorf("DeprecationWarning() = %q, want %q", got, expected)
+	}
+}
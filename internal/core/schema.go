@@ -0,0 +1,214 @@
+package core
+
+import (
+	"context"
+	"io"
+
+	"github.com/acheevo/template-engine/pkg/schema"
+)
+
+// Schema types are defined in the public pkg/schema package; core keeps its historical names
+type (
+	TemplateSchema  = schema.Schema
+	FileSpec        = schema.File
+	TestCase        = schema.TestCase
+	Delims          = schema.Delims
+	Variable        = schema.Variable
+	EnvVariable     = schema.EnvVariable
+	Mapping         = schema.Mapping
+	Codec           = schema.Codec
+	Hook            = schema.Hook
+	Hooks           = schema.Hooks
+	GoModule        = schema.GoModule
+	Patch           = schema.Patch
+	HookStage       = schema.HookStage
+	ValidateOptions = schema.ValidateOptions
+
+	WorkspacePackage = schema.WorkspacePackage
+	Feature          = schema.Feature
+)
+
+const (
+	CurrentSchemaVersion = schema.CurrentVersion
+	CompressionThreshold = schema.CompressionThreshold
+
+	CodecGzip = schema.CodecGzip
+	CodecZstd = schema.CodecZstd
+	CodecNone = schema.CodecNone
+
+	HookPreGenerate  = schema.HookPreGenerate
+	HookPostGenerate = schema.HookPostGenerate
+	HookVerify       = schema.HookVerify
+	HookPostUpdate   = schema.HookPostUpdate
+
+	ConditionExists  = schema.ConditionExists
+	ConditionMissing = schema.ConditionMissing
+	ConditionCommand = schema.ConditionCommand
+
+	DefaultLeftDelim  = schema.DefaultLeftDelim
+	DefaultRightDelim = schema.DefaultRightDelim
+
+	FileTypeSymlink = schema.FileTypeSymlink
+	EncodingBase64  = schema.EncodingBase64
+
+	VariableTypeList = schema.VariableTypeList
+	VariableTypeMap  = schema.VariableTypeMap
+)
+
+var (
+	Codecs           = schema.Codecs
+	HookStages       = schema.HookStages
+	BuiltinVariables = schema.BuiltinVariables
+)
+
+// ValidateSchema validates the structure and content of a schema (see schema.Validate)
+func ValidateSchema(s *TemplateSchema) error {
+	return schema.Validate(s)
+}
+
+// ValidateSchemaWithOptions validates a schema, relaxed by opts (see schema.ValidateWithOptions)
+func ValidateSchemaWithOptions(s *TemplateSchema, opts ValidateOptions) error {
+	return schema.ValidateWithOptions(s, opts)
+}
+
+// CalculateContentHash calculates SHA256 hash of content
+func CalculateContentHash(content string) string {
+	return schema.ContentHash(content)
+}
+
+// CalculateSchemaHash calculates a hash for the entire schema from its identity and file hashes
+func CalculateSchemaHash(s *TemplateSchema) string {
+	return schema.Hash(s)
+}
+
+// FixHashes recomputes outdated file and schema hashes (see schema.FixHashes)
+func FixHashes(s *TemplateSchema) ([]string, error) {
+	return schema.FixHashes(s)
+}
+
+// MigrateSchema upgrades a schema loaded from an older format to CurrentSchemaVersion
+func MigrateSchema(s *TemplateSchema) {
+	schema.Migrate(s)
+}
+
+// LoadSchemaFile reads, decodes and migrates a schema file (see schema.Load)
+func LoadSchemaFile(path string) (*TemplateSchema, error) {
+	return schema.Load(path)
+}
+
+// ParseSchema decodes and migrates a schema (see schema.Parse)
+func ParseSchema(data []byte) (*TemplateSchema, error) {
+	return schema.Parse(data)
+}
+
+// ParseSchemaFile decodes and migrates the schema read from path (see schema.ParseFile)
+func ParseSchemaFile(path string, data []byte) (*TemplateSchema, error) {
+	return schema.ParseFile(path, data)
+}
+
+// SaveSchemaFile writes a schema as indented JSON (see schema.Save)
+func SaveSchemaFile(s *TemplateSchema, path string) error {
+	return schema.Save(s, path)
+}
+
+// SaveSchemaFileCompact writes a schema as JSON without whitespace (see schema.SaveCompact)
+func SaveSchemaFileCompact(s *TemplateSchema, path string) error {
+	return schema.SaveCompact(s, path)
+}
+
+// NormalizeSchema strips fields that have no effect from a schema (see schema.Normalize)
+func NormalizeSchema(s *TemplateSchema) []string {
+	return schema.Normalize(s)
+}
+
+// DedupeSchema stores identical file contents once in the schema blobs (see schema.Dedupe)
+func DedupeSchema(s *TemplateSchema) int {
+	return schema.Dedupe(s)
+}
+
+// EncodeContent returns raw as file content with its encoding, base64 for binary content
+// (see schema.EncodeContent)
+func EncodeContent(raw []byte) (string, string) {
+	return schema.EncodeContent(raw)
+}
+
+// InlineExternal reads external file contents back into the schema (see schema.InlineExternal)
+func InlineExternal(s *TemplateSchema) error {
+	return schema.InlineExternal(s)
+}
+
+// StoredContent returns a file's content as stored, following its blob reference
+func StoredContent(s *TemplateSchema, file FileSpec) (string, error) {
+	return schema.StoredContent(s, file)
+}
+
+// ResolveContent returns a file's decompressed content
+func ResolveContent(s *TemplateSchema, file FileSpec) (string, error) {
+	return schema.ResolveContent(s, file)
+}
+
+// OpenContent returns a reader decompressing a file's content as it is read
+func OpenContent(s *TemplateSchema, file FileSpec) (io.ReadCloser, error) {
+	return schema.OpenContent(s, file)
+}
+
+// EffectiveDelims returns the delimiters used to render a file
+func EffectiveDelims(s *TemplateSchema, file FileSpec) (string, string) {
+	return schema.EffectiveDelims(s, file)
+}
+
+// ApplyMappings applies the mappings of a file to its content in order
+func ApplyMappings(content string, mappings []Mapping) (string, error) {
+	return schema.ApplyMappings(content, mappings)
+}
+
+// ApplyMappingsVerified applies mappings like ApplyMappings, also returning the required ones
+// whose Find string was missing
+func ApplyMappingsVerified(content string, mappings []Mapping) (string, []Mapping, error) {
+	return schema.ApplyMappingsVerified(content, mappings)
+}
+
+// MappingMatches reports whether a mapping finds anything in content
+func MappingMatches(content string, mapping Mapping) bool {
+	return schema.MappingMatches(content, mapping)
+}
+
+// ParseCondition splits a hook condition into its kind and argument
+func ParseCondition(condition string) (kind, argument string, err error) {
+	return schema.ParseCondition(condition)
+}
+
+// ParseCodec validates a codec name
+func ParseCodec(name string) (Codec, error) {
+	return schema.ParseCodec(name)
+}
+
+// FileCodec returns the codec a file's content is stored with
+func FileCodec(file FileSpec) Codec {
+	return schema.FileCodec(file)
+}
+
+// CompressContent compresses content with gzip if it's above the threshold
+func CompressContent(content string) (string, bool, error) {
+	return schema.CompressContent(content)
+}
+
+// CompressWith compresses content with codec if it's above the threshold and compression saves space
+func CompressWith(content string, codec Codec) (string, bool, error) {
+	return schema.CompressWith(content, codec)
+}
+
+// DecompressContent decompresses gzip content if it was compressed
+func DecompressContent(content string, compressed bool) (string, error) {
+	return schema.DecompressContent(content, compressed)
+}
+
+// DecompressWith decodes base64 content compressed with codec
+func DecompressWith(content string, codec Codec) (string, error) {
+	return schema.DecompressWith(content, codec)
+}
+
+// CompressFiles (re)compresses the inline content of files with codec
+func CompressFiles(ctx context.Context, files []FileSpec, codec Codec) error {
+	return schema.CompressFiles(ctx, files, codec)
+}
@@ -0,0 +1,49 @@
+package core
+
+import (
+	"slices"
+	"strings"
+)
+
+// SchemaQuery filters template schemas. Empty fields match every schema.
+type SchemaQuery struct {
+	// Search matches schemas whose name, description or one of whose tags contains it,
+	// ignoring case
+	Search string `json:"search,omitempty"`
+	// Type matches schemas of this template type
+	Type string `json:"type,omitempty"`
+	// Tags matches schemas carrying every one of these tags, ignoring case
+	Tags []string `json:"tags,omitempty"`
+	// Category matches schemas of this category, ignoring case
+	Category string `json:"category,omitempty"`
+}
+
+// Matches reports whether schema satisfies the query
+func (q SchemaQuery) Matches(schema *TemplateSchema) bool {
+	if q.Type != "" && schema.Type != q.Type {
+		return false
+	}
+	if q.Category != "" && !strings.EqualFold(schema.Category, q.Category) {
+		return false
+	}
+
+	tags := make([]string, len(schema.Tags))
+	for i, tag := range schema.Tags {
+		tags[i] = strings.ToLower(tag)
+	}
+	for _, tag := range q.Tags {
+		if !slices.Contains(tags, strings.ToLower(tag)) {
+			return false
+		}
+	}
+
+	if q.Search == "" {
+		return true
+	}
+	search := strings.ToLower(q.Search)
+	if strings.Contains(strings.ToLower(schema.Name), search) ||
+		strings.Contains(strings.ToLower(schema.Description), search) {
+		return true
+	}
+	return slices.ContainsFunc(tags, func(tag string) bool { return strings.Contains(tag, search) })
+}
@@ -0,0 +1,39 @@
+package core
+
+import "testing"
+
+func TestSchemaQueryMatches(t *testing.T) {
+	schema := &TemplateSchema{
+		Name:        "react-dashboard",
+		Type:        "frontend",
+		Description: "Admin dashboard with Vite and Tailwind",
+		Tags:        []string{"React", "admin"},
+		Category:    CategoryFrontend,
+	}
+
+	tests := []struct {
+		name  string
+		query SchemaQuery
+		want  bool
+	}{
+		{"empty query", SchemaQuery{}, true},
+		{"name", SchemaQuery{Search: "dashboard"}, true},
+		{"description ignoring case", SchemaQuery{Search: "TAILWIND"}, true},
+		{"tag substring", SchemaQuery{Search: "adm"}, true},
+		{"no match", SchemaQuery{Search: "postgres"}, false},
+		{"type", SchemaQuery{Search: "react", Type: "frontend"}, true},
+		{"other type", SchemaQuery{Search: "react", Type: "go-api"}, false},
+		{"all tags", SchemaQuery{Tags: []string{"react", "Admin"}}, true},
+		{"missing tag", SchemaQuery{Tags: []string{"react", "backend"}}, false},
+		{"category ignoring case", SchemaQuery{Category: "Frontend"}, true},
+		{"other category", SchemaQuery{Search: "react", Category: CategoryBackend}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.query.Matches(schema); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
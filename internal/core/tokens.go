@@ -0,0 +1,99 @@
+package core
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// DetectedToken reports a literal string of a reference project that DetectTokens mapped
+type DetectedToken struct {
+	Find    string `json:"find"`
+	Replace string `json:"replace"`
+	// Paths of the files the mapping was added to, none when the string occurs nowhere
+	Paths []string `json:"paths"`
+}
+
+// repoToken matches owner/repo strings such as acheevo/fullstack-template
+var repoToken = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+
+// TokenReplacement returns the variable expression replacing a literal string of a reference
+// project, chosen by its shape: owner/repo is the GitHub repository, "Fullstack Template" the
+// project name, fullstack-template, fullstack_template, FULLSTACK_TEMPLATE, FullstackTemplate,
+// fullstackTemplate and fullstack its kebab, snake, upper snake, Pascal, camel and package forms.
+func TokenReplacement(token string) string {
+	hasUpper := strings.ContainsFunc(token, unicode.IsUpper)
+	hasLower := strings.ContainsFunc(token, unicode.IsLower)
+
+	switch {
+	case repoToken.MatchString(token):
+		return "{{.GitHubRepo}}"
+	case strings.Contains(token, " "):
+		return "{{.ProjectName}}"
+	case strings.Contains(token, "-"):
+		return "{{.ProjectNameKebab}}"
+	case strings.Contains(token, "_") && !hasLower:
+		return "{{.ProjectNameSnake | upper}}"
+	case strings.Contains(token, "_"):
+		return "{{.ProjectNameSnake}}"
+	case hasUpper && unicode.IsUpper([]rune(token)[0]):
+		return "{{.ProjectNamePascal}}"
+	case hasUpper:
+		return "{{.ProjectNameCamel}}"
+	case hasLower:
+		return "{{.ProjectNamePackage}}"
+	default:
+		return "{{.ProjectName}}"
+	}
+}
+
+// DetectTokens maps every occurrence of the literal strings tokens in the text files of schema
+// to the variable their shape calls for (see TokenReplacement), so reference projects need no
+// hand-written mappings for their names. The mappings are appended to those of the template
+// type, longest tokens first so a token is never replaced inside a longer one, and the files
+// are templated. Files the template type does not template are left alone when they contain
+// template delimiters of their own, which templating would interpret.
+func DetectTokens(schema *TemplateSchema, tokens []string) ([]DetectedToken, error) {
+	detected := make([]DetectedToken, 0, len(tokens))
+	for _, token := range tokens {
+		token = strings.TrimSpace(token)
+		if token == "" || slices.ContainsFunc(detected, func(d DetectedToken) bool { return d.Find == token }) {
+			continue
+		}
+		detected = append(detected, DetectedToken{Find: token, Replace: TokenReplacement(token), Paths: []string{}})
+	}
+	slices.SortStableFunc(detected, func(a, b DetectedToken) int {
+		return cmp.Compare(len(b.Find), len(a.Find))
+	})
+
+	for i := range schema.Files {
+		file := &schema.Files[i]
+		if file.IsSymlink() || file.Encoding != "" {
+			continue
+		}
+		content, err := ResolveContent(schema, *file)
+		if err != nil {
+			return nil, fmt.Errorf("%s failed to decompress: %w", file.Path, err)
+		}
+		if left, _ := EffectiveDelims(schema, *file); !file.Template && strings.Contains(content, left) {
+			continue
+		}
+
+		for j := range detected {
+			token := &detected[j]
+			if !strings.Contains(content, token.Find) || slices.ContainsFunc(file.Mappings, func(m Mapping) bool {
+				return m.Find == token.Find
+			}) {
+				continue
+			}
+			file.Template = true
+			file.Mappings = append(file.Mappings, Mapping{Find: token.Find, Replace: token.Replace})
+			token.Paths = append(token.Paths, filepath.ToSlash(file.Path))
+		}
+	}
+	return detected, nil
+}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTokenReplacement(t *testing.T) {
+	tests := map[string]string{
+		"acheevo/fullstack-template": "{{.GitHubRepo}}",
+		"Fullstack Template":         "{{.ProjectName}}",
+		"fullstack-template":         "{{.ProjectNameKebab}}",
+		"fullstack_template":         "{{.ProjectNameSnake}}",
+		"FULLSTACK_TEMPLATE":         "{{.ProjectNameSnake | upper}}",
+		"FullstackTemplate":          "{{.ProjectNamePascal}}",
+		"fullstackTemplate":          "{{.ProjectNameCamel}}",
+		"fullstack":                  "{{.ProjectNamePackage}}",
+	}
+
+	for token, want := range tests {
+		if got := TokenReplacement(token); got != want {
+			t.Errorf("TokenReplacement(%q) = %q, want %q", token, got, want)
+		}
+	}
+}
+
+func TestDetectTokens(t *testing.T) {
+	schema := &TemplateSchema{Files: []FileSpec{
+		{
+			Path: "README.md", Template: true, Content: "# Fullstack Template\ngit clone acheevo/fullstack-template",
+			Mappings: []Mapping{{Find: "# Fullstack Template", Replace: "# {{.ProjectName}}"}},
+		},
+		{Path: "go.mod", Content: "module github.com/acheevo/fullstack-template"},
+		{Path: ".goreleaser.yml", Content: "project_name: fullstack-template\nname_template: '{{ .ProjectName }}'"},
+		{Path: "main.go", Content: "package main"},
+	}}
+
+	detected, err := DetectTokens(schema, []string{"fullstack-template", "acheevo/fullstack-template", "Nope", ""})
+	if err != nil {
+		t.Fatalf("DetectTokens() error = %v", err)
+	}
+
+	var summary []string
+	for _, token := range detected {
+		summary = append(summary, token.Find+"="+strings.Join(token.Paths, "+"))
+	}
+	want := "acheevo/fullstack-template=README.md+go.mod,fullstack-template=README.md+go.mod,Nope="
+	if got := strings.Join(summary, ","); got != want {
+		t.Errorf("DetectTokens() = %s, want %s", got, want)
+	}
+
+	rendered, err := ApplyMappings(schema.Files[1].Content, schema.Files[1].Mappings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !schema.Files[1].Template || rendered != "module github.com/{{.GitHubRepo}}" {
+		t.Errorf("go.mod mapped to %q, want the repository mapped before the name", rendered)
+	}
+	if len(schema.Files[0].Mappings) != 3 {
+		t.Errorf("README.md mappings = %+v, want the detected ones after the template type's", schema.Files[0].Mappings)
+	}
+	if schema.Files[2].Template || schema.Files[3].Template {
+		t.Error("DetectTokens() should leave files with their own delimiters and without tokens alone")
+	}
+}
@@ -1,49 +1,10 @@
 package core
 
-// TemplateSchema represents the complete template configuration
-type TemplateSchema struct {
-	Name        string              `json:"name"`
-	Type        string              `json:"type"`
-	Version     string              `json:"version"`
-	Description string              `json:"description"`
-	Variables   map[string]Variable `json:"variables"`
-	Files       []FileSpec          `json:"files"`
-	Hooks       map[string][]string `json:"hooks,omitempty"`
-	Hash        string              `json:"hash,omitempty"`
-	EnvConfig   []EnvVariable       `json:"env_config,omitempty"`
-}
-
-// Variable represents a template variable definition
-type Variable struct {
-	Type        string `json:"type"`
-	Required    bool   `json:"required"`
-	Default     string `json:"default,omitempty"`
-	Description string `json:"description,omitempty"`
-}
-
-// EnvVariable represents an environment variable from .env.example
-type EnvVariable struct {
-	Name        string `json:"name"`
-	Description string `json:"description,omitempty"`
-	Example     string `json:"example,omitempty"`
-}
-
-// FileSpec represents a file in the template (go-fsck pattern: all content embedded)
-type FileSpec struct {
-	Path       string    `json:"path"`
-	Template   bool      `json:"template"`
-	Content    string    `json:"content"`              // Always includes full content
-	Size       int64     `json:"size"`                 // Original file size
-	Hash       string    `json:"hash,omitempty"`       // Content hash for validation
-	Compressed bool      `json:"compressed,omitempty"` // If content is compressed
-	Mappings   []Mapping `json:"mappings,omitempty"`
-}
-
-// Mapping represents a string replacement mapping
-type Mapping struct {
-	Find    string `json:"find"`
-	Replace string `json:"replace"`
-}
+import (
+	"context"
+	"fmt"
+	"io/fs"
+)
 
 // TemplateVariables represents the variables to substitute during generation
 type TemplateVariables struct {
@@ -51,14 +12,83 @@ type TemplateVariables struct {
 	GitHubRepo  string `json:"github_repo"`
 	Author      string `json:"author,omitempty"`
 	Description string `json:"description,omitempty"`
+	// Custom holds the values of the variables declared by the schema beyond the built-in ones
+	Custom map[string]string `json:"custom,omitempty"`
 }
 
 // TemplateType represents different types of templates (frontend, go-api, etc.)
 type TemplateType interface {
 	Name() string
-	Extract(sourceDir string) (*TemplateSchema, error)
-	GetMappings(filePath string) []Mapping
+	Extract(ctx context.Context, sourceDir string, opts ExtractOptions) (*TemplateSchema, error)
+	// ExtractFS extracts from any file tree, such as a go:embed bundle or a zip archive
+	ExtractFS(ctx context.Context, fsys fs.FS, opts ExtractOptions) (*TemplateSchema, error)
 	GetVariables() map[string]Variable
-	ShouldTemplate(filePath string) bool
-	ShouldSkip(filePath string) bool
+	ExtractPolicy
+}
+
+// Template categories used by the built-in template types
+const (
+	CategoryBackend   = "backend"
+	CategoryFrontend  = "frontend"
+	CategoryFullstack = "fullstack"
+	CategoryCLI       = "cli"
+	CategoryLibrary   = "library"
+	CategoryInfra     = "infra"
+)
+
+// TemplateMetadata is implemented by template types describing the projects they extract.
+// Extracted schemas carry the same category and tags.
+type TemplateMetadata interface {
+	Category() string
+	Tags() []string
+}
+
+// TemplateDescriber is implemented by template types describing themselves in listings
+type TemplateDescriber interface {
+	Description() string
+}
+
+// TypeDescription returns the description of a template type, a generic one when it does not
+// implement TemplateDescriber
+func TypeDescription(templateType TemplateType) string {
+	if describer, ok := templateType.(TemplateDescriber); ok {
+		return describer.Description()
+	}
+	return fmt.Sprintf("%s template type", templateType.Name())
+}
+
+// ReferenceMarkers is implemented by template types that recognize their reference projects.
+// Markers are glob patterns (see fs.Glob) that each match at least one path of a reference
+// project, such as "go.mod" or "vite.config.*".
+type ReferenceMarkers interface {
+	Markers() []string
+}
+
+// TypeMarkers returns the markers of a template type, none when it does not implement
+// ReferenceMarkers
+func TypeMarkers(templateType TemplateType) []string {
+	if markers, ok := templateType.(ReferenceMarkers); ok {
+		return markers.Markers()
+	}
+	return nil
+}
+
+// MissingMarkers returns the markers that match no path of fsys
+func MissingMarkers(fsys fs.FS, markers []string) []string {
+	var missing []string
+	for _, marker := range markers {
+		if matches, err := fs.Glob(fsys, marker); err != nil || len(matches) == 0 {
+			missing = append(missing, marker)
+		}
+	}
+	return missing
+}
+
+// TypeMetadata returns the category and tags of a template type, empty when it does not
+// implement TemplateMetadata
+func TypeMetadata(templateType TemplateType) (category string, tags []string) {
+	if metadata, ok := templateType.(TemplateMetadata); ok {
+		return metadata.Category(), metadata.Tags()
+	}
+	return "", nil
 }
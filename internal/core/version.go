@@ -0,0 +1,34 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/acheevo/template-engine/pkg/schema"
+)
+
+// EngineVersion is the version of this template engine, set at build time with
+// -ldflags "-X github.com/acheevo/template-engine/internal/core.EngineVersion=1.2.3"
+var EngineVersion = "1.0.0"
+
+// CheckCompatibility reports whether this engine can generate from schema
+func CheckCompatibility(s *TemplateSchema) error {
+	if s.SchemaVersion > CurrentSchemaVersion {
+		return fmt.Errorf("schema format version %d is newer than the supported version %d, upgrade template-engine",
+			s.SchemaVersion, CurrentSchemaVersion)
+	}
+
+	if s.MinEngineVersion == "" {
+		return nil
+	}
+
+	cmp, err := schema.CompareVersions(EngineVersion, s.MinEngineVersion)
+	if err != nil {
+		return err
+	}
+	if cmp < 0 {
+		return fmt.Errorf("schema requires template-engine %s or newer, this is %s",
+			s.MinEngineVersion, EngineVersion)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	original := EngineVersion
+	EngineVersion = "1.4.2"
+	defer func() { EngineVersion = original }()
+
+	tests := []struct {
+		name    string
+		schema  TemplateSchema
+		wantErr string
+	}{
+		{name: "no constraints", schema: TemplateSchema{SchemaVersion: CurrentSchemaVersion}},
+		{name: "older engine required", schema: TemplateSchema{MinEngineVersion: "1.3"}},
+		{name: "same engine required", schema: TemplateSchema{MinEngineVersion: "v1.4.2"}},
+		{name: "newer engine required", schema: TemplateSchema{MinEngineVersion: "1.10.0"}, wantErr: "1.10.0 or newer"},
+		{name: "newer schema format", schema: TemplateSchema{SchemaVersion: CurrentSchemaVersion + 1}, wantErr: "upgrade"},
+		{name: "invalid version", schema: TemplateSchema{MinEngineVersion: "latest"}, wantErr: "invalid version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckCompatibility(&tt.schema)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("CheckCompatibility() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CheckCompatibility() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,410 @@
+// Package download fetches remote schemas over HTTP, retrying transient failures with
+// exponential backoff, resuming interrupted transfers with range requests and verifying the
+// content against a published digest
+package download
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/acheevo/template-engine/internal/logging"
+)
+
+const (
+	// DefaultRetries is how many times a failed request is retried unless configured
+	DefaultRetries = 3
+	// DefaultBackoff is the delay before the first retry unless configured, doubled for each
+	// further retry
+	DefaultBackoff = 500 * time.Millisecond
+
+	// ReprDigestHeader publishes the digest of the full content (RFC 9530), so it also
+	// verifies transfers resumed with range requests
+	ReprDigestHeader = "Repr-Digest"
+
+	// maxRetryAfter bounds how long a Retry-After header of a rate-limited response is honored
+	maxRetryAfter = time.Minute
+	// maxErrorBody bounds the body kept for an error response
+	maxErrorBody = 1 << 20
+)
+
+// ErrOffline is returned for any network access in offline mode
+var ErrOffline = errors.New("network access is disabled in offline mode")
+
+// Options configures downloads
+type Options struct {
+	// Client sends the requests, http.DefaultClient when nil. Configure its transport for
+	// proxies and TLS.
+	Client *http.Client
+	// Header is sent with every request, e.g. Accept or Authorization
+	Header http.Header
+	// Retries is how many times a failed request is retried, DefaultRetries when zero and
+	// none when negative
+	Retries int
+	// Backoff is the delay before the first retry, DefaultBackoff when zero
+	Backoff time.Duration
+	// Digest is the expected sha256:<hex> digest of the content. When empty, the digest the
+	// server publishes in a Repr-Digest header is verified instead, if any.
+	Digest string
+	// MaxSize bounds the content, unbounded when zero
+	MaxSize int64
+	// Logger logs retries, discarded when nil
+	Logger *slog.Logger
+	// Offline fails every download with ErrOffline, without sending a request
+	Offline bool
+}
+
+// StatusError is an unexpected response status
+type StatusError struct {
+	StatusCode int
+	Status     string
+	Header     http.Header
+	Body       []byte // Start of the response body
+}
+
+func (e *StatusError) Error() string {
+	return "server returned " + e.Status
+}
+
+// retryable reports whether the status is worth retrying: timeouts, rate limits and
+// server-side failures
+func (e *StatusError) retryable() bool {
+	switch e.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusInternalServerError,
+		http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryAfter returns the delay a rate-limited response asks for, zero when it does not
+func (e *StatusError) retryAfter() time.Duration {
+	value := e.Header.Get("Retry-After")
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return min(time.Duration(seconds)*time.Second, maxRetryAfter)
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return min(max(time.Until(at), 0), maxRetryAfter)
+	}
+	return 0
+}
+
+// permanentError is an error Retry does not retry
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks err as not worth retrying
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Retry calls attempt until it succeeds, returns an error marked Permanent or the retries of
+// opts are used up, waiting with exponential backoff in between. Rate-limited responses wait
+// as long as their Retry-After header asks instead.
+func Retry(ctx context.Context, opts Options, attempt func() error) error {
+	retries := opts.Retries
+	switch {
+	case retries == 0:
+		retries = DefaultRetries
+	case retries < 0:
+		retries = 0
+	}
+	delay := opts.Backoff
+	if delay <= 0 {
+		delay = DefaultBackoff
+	}
+	logger := opts.Logger
+	if logger == nil {
+		logger = logging.Discard()
+	}
+
+	for i := 0; ; i++ {
+		err := attempt()
+		if err == nil {
+			return nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+		if i >= retries || ctx.Err() != nil {
+			return err
+		}
+
+		wait := delay
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.retryAfter() > 0 {
+			wait = statusErr.retryAfter()
+		}
+		logger.Warn("Retrying download", "attempt", i+1, "retries", retries, "wait", wait, "error", err)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// Bytes downloads url into memory
+func Bytes(ctx context.Context, url string, opts Options) ([]byte, error) {
+	if opts.Offline {
+		return nil, fmt.Errorf("cannot download %s: %w", url, ErrOffline)
+	}
+
+	var buf memorySink
+	published, err := fetch(ctx, url, opts, &buf)
+	if err != nil {
+		return nil, err
+	}
+	if err := verify(url, opts.Digest, published, bytes.NewReader(buf.Bytes())); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// File downloads url to dest through dest.part, which is kept when the download fails. With a
+// digest in opts, a later download resumes from the kept part; without one it cannot tell
+// whether the part is still current and starts over.
+func File(ctx context.Context, url, dest string, opts Options) error {
+	if opts.Offline {
+		return fmt.Errorf("cannot download %s: %w", url, ErrOffline)
+	}
+
+	part := dest + ".part"
+	flags := os.O_RDWR | os.O_CREATE
+	if opts.Digest == "" {
+		flags |= os.O_TRUNC
+	}
+	file, err := os.OpenFile(part, flags, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	size, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	published, err := fetch(ctx, url, opts, &fileSink{file: file, n: size})
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := verify(url, opts.Digest, published, file); err != nil {
+		file.Close()
+		os.Remove(part) // Corrupt, never resume from it
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(part, dest)
+}
+
+// sink receives downloaded content, across the attempts of a download
+type sink interface {
+	io.Writer
+	// size returns how much content was received so far
+	size() int64
+	// reset drops the received content, when the server cannot resume the transfer
+	reset() error
+}
+
+// fetch downloads url into out, resuming after what out already holds when an attempt is
+// interrupted, and returns the digest the server published, if any
+func fetch(ctx context.Context, url string, opts Options, out sink) (string, error) {
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	// validator identifies the content across attempts, so a resumed transfer starts over
+	// when the content changed in between
+	var validator, published string
+	err := Retry(ctx, opts, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return Permanent(err)
+		}
+		for key, values := range opts.Header {
+			req.Header[key] = values
+		}
+		offset := out.size()
+		if offset > 0 {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+			if validator != "" {
+				req.Header.Set("If-Range", validator)
+			}
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		switch {
+		case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		case resp.StatusCode == http.StatusOK:
+			if offset > 0 {
+				if err := out.reset(); err != nil {
+					return Permanent(err)
+				}
+			}
+		case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+			if err := out.reset(); err != nil {
+				return Permanent(err)
+			}
+			return fmt.Errorf("cannot resume download of %s", url)
+		default:
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+			statusErr := &StatusError{
+				StatusCode: resp.StatusCode, Status: resp.Status, Header: resp.Header, Body: body,
+			}
+			if !statusErr.retryable() {
+				return Permanent(statusErr)
+			}
+			return statusErr
+		}
+
+		if validator = resp.Header.Get("ETag"); validator == "" || strings.HasPrefix(validator, "W/") {
+			validator = resp.Header.Get("Last-Modified")
+		}
+		if digest := resp.Header.Get(ReprDigestHeader); digest != "" {
+			published = digest
+		}
+
+		body := io.Reader(resp.Body)
+		if opts.MaxSize > 0 {
+			body = io.LimitReader(resp.Body, opts.MaxSize-out.size()+1)
+		}
+		if _, err := io.Copy(out, body); err != nil {
+			return fmt.Errorf("download of %s interrupted: %w", url, err)
+		}
+		if opts.MaxSize > 0 && out.size() > opts.MaxSize {
+			return Permanent(fmt.Errorf("%s exceeds %d bytes", url, opts.MaxSize))
+		}
+		return nil
+	})
+	return published, err
+}
+
+// verify checks content against the expected digest, or else the published one
+func verify(url, expected, published string, content io.Reader) error {
+	if expected == "" && published != "" {
+		digest, ok := parseReprDigest(published)
+		if !ok {
+			return nil // Only sha-256 is verified
+		}
+		expected = digest
+	}
+	if expected == "" {
+		return nil
+	}
+
+	algorithm, _, _ := strings.Cut(expected, ":")
+	if algorithm != "sha256" {
+		return fmt.Errorf("unsupported digest %q: expected sha256:<hex>", expected)
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, content); err != nil {
+		return err
+	}
+	if actual := "sha256:" + hex.EncodeToString(hash.Sum(nil)); actual != expected {
+		return fmt.Errorf("digest mismatch for %s: expected %s, got %s", url, expected, actual)
+	}
+	return nil
+}
+
+// ReprDigest returns the Repr-Digest header value of content
+func ReprDigest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha-256=:" + base64.StdEncoding.EncodeToString(sum[:]) + ":"
+}
+
+// parseReprDigest returns the sha256:<hex> digest of a Repr-Digest header, false when it has
+// no sha-256 digest
+func parseReprDigest(header string) (string, bool) {
+	for _, member := range strings.Split(header, ",") {
+		algorithm, value, ok := strings.Cut(strings.TrimSpace(member), "=")
+		if !ok || algorithm != "sha-256" {
+			continue
+		}
+		sum, err := base64.StdEncoding.DecodeString(strings.Trim(value, ":"))
+		if err != nil || len(sum) != sha256.Size {
+			return "", false
+		}
+		return "sha256:" + hex.EncodeToString(sum), true
+	}
+	return "", false
+}
+
+// memorySink keeps downloaded content in memory
+type memorySink struct {
+	bytes.Buffer
+}
+
+func (m *memorySink) size() int64 {
+	return int64(m.Len())
+}
+
+func (m *memorySink) reset() error {
+	m.Reset()
+	return nil
+}
+
+// fileSink appends downloaded content to a file
+type fileSink struct {
+	file *os.File
+	n    int64
+}
+
+func (f *fileSink) Write(p []byte) (int, error) {
+	n, err := f.file.Write(p)
+	f.n += int64(n)
+	return n, err
+}
+
+func (f *fileSink) size() int64 {
+	return f.n
+}
+
+func (f *fileSink) reset() error {
+	if err := f.file.Truncate(0); err != nil {
+		return err
+	}
+	f.n = 0
+	_, err := f.file.Seek(0, io.SeekStart)
+	return err
+}
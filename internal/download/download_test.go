@@ -0,0 +1,199 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var content = []byte(strings.Repeat("schema content\n", 1000))
+
+// digest returns the sha256:<hex> digest of data
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// serve serves content with range support and its digest published
+func serve(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("ETag", `"v1"`)
+	w.Header().Set(ReprDigestHeader, ReprDigest(content))
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(content))
+}
+
+func TestBytesResumesInterruptedDownload(t *testing.T) {
+	var requests atomic.Int32
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		if requests.Add(1) == 1 {
+			// Cut the connection halfway through the body
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Content-Length", "15000")
+			w.Write(content[:len(content)/2])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		serve(w, r)
+	}))
+	defer server.Close()
+
+	data, err := Bytes(context.Background(), server.URL, Options{Backoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+	if !bytes.Equal(data, content) {
+		t.Errorf("Bytes() = %d bytes, want %d", len(data), len(content))
+	}
+	if len(ranges) != 2 || ranges[1] != "bytes=7500-" {
+		t.Errorf("requests asked for ranges %q, want the second one to resume", ranges)
+	}
+}
+
+func TestBytesRetriesRateLimits(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) < 3 {
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+			return
+		}
+		serve(w, r)
+	}))
+	defer server.Close()
+
+	if _, err := Bytes(context.Background(), server.URL, Options{Backoff: time.Millisecond}); err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+	if requests.Load() != 3 {
+		t.Errorf("server got %d requests, want 3", requests.Load())
+	}
+
+	requests.Store(0)
+	_, err := Bytes(context.Background(), server.URL, Options{Retries: 1, Backoff: time.Millisecond})
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Bytes() with retries used up error = %v, want a 429 StatusError", err)
+	}
+}
+
+func TestBytesDoesNotRetryClientErrors(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		http.Error(w, `{"error":"not found"}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := Bytes(context.Background(), server.URL, Options{Backoff: time.Millisecond})
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("Bytes() error = %v, want a 404 StatusError", err)
+	}
+	if !strings.Contains(string(statusErr.Body), "not found") {
+		t.Errorf("StatusError.Body = %q", statusErr.Body)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("server got %d requests, want 1", requests.Load())
+	}
+}
+
+func TestBytesVerifiesDigest(t *testing.T) {
+	published := ReprDigest(content)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(ReprDigestHeader, published)
+		w.Write(content)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	if _, err := Bytes(ctx, server.URL, Options{Digest: digest(content)}); err != nil {
+		t.Errorf("Bytes() with the right digest error = %v", err)
+	}
+	if _, err := Bytes(ctx, server.URL, Options{Digest: digest([]byte("other"))}); err == nil {
+		t.Error("Bytes() with a wrong digest should fail")
+	}
+
+	published = ReprDigest([]byte("tampered"))
+	if _, err := Bytes(ctx, server.URL, Options{}); err == nil {
+		t.Error("Bytes() should fail when the published digest does not match")
+	}
+}
+
+func TestFileResumesFromPart(t *testing.T) {
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		serve(w, r)
+	}))
+	defer server.Close()
+
+	dest := filepath.Join(t.TempDir(), "schema.json")
+	if err := os.WriteFile(dest+".part", content[:1000], 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := File(context.Background(), server.URL, dest, Options{Digest: digest(content)}); err != nil {
+		t.Fatalf("File() error = %v", err)
+	}
+	data, err := os.ReadFile(dest)
+	if err != nil || !bytes.Equal(data, content) {
+		t.Errorf("downloaded file = %d bytes, %v, want %d bytes", len(data), err, len(content))
+	}
+	if _, err := os.Stat(dest + ".part"); !os.IsNotExist(err) {
+		t.Error("the part file should be renamed to the destination")
+	}
+	if len(ranges) != 1 || ranges[0] != "bytes=1000-" {
+		t.Errorf("requests asked for ranges %q, want to resume from the part", ranges)
+	}
+}
+
+func TestOffline(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		serve(w, r)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	if _, err := Bytes(ctx, server.URL, Options{Offline: true}); !errors.Is(err, ErrOffline) {
+		t.Errorf("Bytes() offline error = %v, want ErrOffline", err)
+	}
+	dest := filepath.Join(t.TempDir(), "schema.json")
+	if err := File(ctx, server.URL, dest, Options{Offline: true}); !errors.Is(err, ErrOffline) {
+		t.Errorf("File() offline error = %v, want ErrOffline", err)
+	}
+	if requests.Load() != 0 {
+		t.Errorf("server got %d requests offline, want none", requests.Load())
+	}
+}
+
+func TestParseReprDigest(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+		ok     bool
+	}{
+		{ReprDigest(content), digest(content), true},
+		{"sha-512=:AAAA:, " + ReprDigest(content), digest(content), true},
+		{"sha-512=:AAAA:", "", false},
+		{"sha-256=:not base64:", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseReprDigest(tt.header)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseReprDigest(%q) = %q, %v, want %q, %v", tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
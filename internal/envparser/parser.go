@@ -1,55 +1,301 @@
 package envparser
 
 import (
-	"bufio"
+	"os"
 	"strings"
 
 	"github.com/acheevo/template-engine/internal/core"
 )
 
+// secretSuffixes mark variables holding credentials by naming convention
+var secretSuffixes = []string{"_SECRET", "_PASSWORD", "_KEY"}
+
+// File is a parsed env file. Lines are kept verbatim so String returns the original
+// content unchanged until values are modified with Set.
+type File struct {
+	entries []entry
+}
+
+// entry is one logical line of an env file; a quoted multiline value spans several physical lines
+type entry struct {
+	raw      string // Exact text, including the trailing newline
+	variable *core.EnvVariable
+	export   bool
+	quote    byte // Quote character around the value, 0 when unquoted
+}
+
 // ParseEnvExample parses a .env.example file and returns environment variables
 func ParseEnvExample(content string) []core.EnvVariable {
-	var envVars []core.EnvVariable
-	scanner := bufio.NewScanner(strings.NewReader(content))
+	return Parse(content).Variables()
+}
 
-	var currentDescription string
+// Parse reads env file content. It understands comments (used as variable descriptions),
+// `export` prefixes, single and double quoted values spanning several lines and inline
+// comments after values. Lines that are not assignments are kept but ignored.
+func Parse(content string) *File {
+	file := &File{}
+	lines := strings.SplitAfter(content, "\n")
 
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+	var currentDescription string
 
-		// Skip empty lines
-		if line == "" {
-			currentDescription = ""
+	for i := 0; i < len(lines); i++ {
+		raw := lines[i]
+		if raw == "" {
 			continue
 		}
+		line := strings.TrimSpace(raw)
 
-		// Handle comment lines for descriptions
-		if strings.HasPrefix(line, "#") {
+		switch {
+		case line == "":
+			// Empty lines end the description block
+			currentDescription = ""
+		case strings.HasPrefix(line, "#"):
+			// Handle comment lines for descriptions
 			comment := strings.TrimSpace(strings.TrimPrefix(line, "#"))
 			if comment != "" {
 				currentDescription = comment
 			}
+		case strings.Contains(line, "="):
+			parsed, consumed := parseAssignment(lines[i:])
+			raw = strings.Join(lines[i:i+consumed], "")
+			i += consumed - 1
+
+			if parsed.variable.Description == "" {
+				parsed.variable.Description = currentDescription
+			}
+			parsed.variable.Secret = IsSecretName(parsed.variable.Name)
+			parsed.variable.Required = isRequiredDescription(parsed.variable.Description)
+			parsed.raw = raw
+			file.entries = append(file.entries, parsed)
+			currentDescription = "" // Reset description after use
 			continue
 		}
 
-		// Handle environment variable lines
-		if strings.Contains(line, "=") {
-			parts := strings.SplitN(line, "=", 2)
-			if len(parts) == 2 {
-				name := strings.TrimSpace(parts[0])
-				example := strings.TrimSpace(parts[1])
+		file.entries = append(file.entries, entry{raw: raw})
+	}
 
-				envVar := core.EnvVariable{
-					Name:        name,
-					Description: currentDescription,
-					Example:     example,
-				}
+	return file
+}
 
-				envVars = append(envVars, envVar)
-				currentDescription = "" // Reset description after use
-			}
+// parseAssignment parses the NAME=value assignment starting at lines[0] and returns it
+// with the number of physical lines it spans
+func parseAssignment(lines []string) (entry, int) {
+	// Keep the line break: a quoted value may continue on the next line
+	line := strings.TrimLeft(lines[0], " \t")
+
+	parsed := entry{}
+	if rest, found := strings.CutPrefix(line, "export "); found {
+		parsed.export = true
+		line = strings.TrimLeft(rest, " \t")
+	}
+
+	name, value, _ := strings.Cut(line, "=")
+	value = strings.TrimLeft(value, " \t")
+	parsed.variable = &core.EnvVariable{Name: strings.TrimSpace(name)}
+
+	if value == "" || (value[0] != '"' && value[0] != '\'') {
+		example, comment := splitInlineComment(value)
+		parsed.variable.Example = example
+		parsed.variable.Description = comment
+		return parsed, 1
+	}
+
+	// Quoted value, possibly continuing on the following lines
+	parsed.quote = value[0]
+	text := value[1:]
+	consumed := 1
+	for {
+		if end := closingQuote(text, parsed.quote); end >= 0 {
+			parsed.variable.Example = unquote(text[:end], parsed.quote)
+			_, parsed.variable.Description = splitInlineComment(strings.TrimSpace(text[end+1:]))
+			return parsed, consumed
+		}
+		if consumed == len(lines) {
+			// Unterminated quote: keep the rest of the file as the value
+			parsed.variable.Example = unquote(strings.TrimRight(text, "\r\n"), parsed.quote)
+			return parsed, consumed
+		}
+		text += lines[consumed]
+		consumed++
+	}
+}
+
+// closingQuote returns the index of the quote ending a value, skipping escaped double quotes
+func closingQuote(text string, quote byte) int {
+	for i := 0; i < len(text); i++ {
+		switch {
+		case quote == '"' && text[i] == '\\':
+			i++
+		case text[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// unquote decodes the escapes allowed in double quoted values; single quoted values are literal
+func unquote(text string, quote byte) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if quote != '"' {
+		return text
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(text); i++ {
+		if text[i] != '\\' || i+1 == len(text) {
+			out.WriteByte(text[i])
+			continue
+		}
+		i++
+		switch text[i] {
+		case 'n':
+			out.WriteByte('\n')
+		case 't':
+			out.WriteByte('\t')
+		case 'r':
+			out.WriteByte('\r')
+		case '"', '\\', '$':
+			out.WriteByte(text[i])
+		default:
+			out.WriteByte('\\')
+			out.WriteByte(text[i])
+		}
+	}
+	return out.String()
+}
+
+// splitInlineComment separates an unquoted value from a trailing " # comment"
+func splitInlineComment(value string) (string, string) {
+	if strings.HasPrefix(value, "#") {
+		return "", strings.TrimSpace(value[1:])
+	}
+	for i := 1; i < len(value); i++ {
+		if value[i] == '#' && (value[i-1] == ' ' || value[i-1] == '\t') {
+			return strings.TrimSpace(value[:i]), strings.TrimSpace(value[i+1:])
 		}
 	}
+	return strings.TrimSpace(value), ""
+}
 
+// Variables returns the variables declared by the file, in order
+func (f *File) Variables() []core.EnvVariable {
+	envVars := []core.EnvVariable{}
+	for _, e := range f.entries {
+		if e.variable != nil {
+			envVars = append(envVars, *e.variable)
+		}
+	}
 	return envVars
 }
+
+// Set changes the value of a variable, appending it when the file does not declare it.
+// Lines of variables whose value does not change are left untouched.
+func (f *File) Set(name, value string) {
+	for i := range f.entries {
+		e := &f.entries[i]
+		if e.variable == nil || e.variable.Name != name {
+			continue
+		}
+		if e.variable.Example == value {
+			return
+		}
+
+		e.variable.Example = value
+		prefix := ""
+		if e.export {
+			prefix = "export "
+		}
+		formatted := FormatValue(value)
+		indent := e.raw[:len(e.raw)-len(strings.TrimLeft(e.raw, " \t"))]
+		e.raw = indent + prefix + name + "=" + formatted + "\n"
+		e.quote = 0
+		if strings.HasPrefix(formatted, `"`) {
+			e.quote = '"'
+		}
+		return
+	}
+
+	f.ensureTrailingNewline()
+	f.entries = append(f.entries, entry{
+		raw:      name + "=" + FormatValue(value) + "\n",
+		variable: &core.EnvVariable{Name: name, Example: value, Secret: IsSecretName(name)},
+	})
+}
+
+// ensureTrailingNewline makes sure appended lines start on a line of their own
+func (f *File) ensureTrailingNewline() {
+	if n := len(f.entries); n > 0 && !strings.HasSuffix(f.entries[n-1].raw, "\n") {
+		f.entries[n-1].raw += "\n"
+	}
+}
+
+// String returns the file content
+func (f *File) String() string {
+	var out strings.Builder
+	for _, e := range f.entries {
+		out.WriteString(e.raw)
+	}
+	return out.String()
+}
+
+// Expand returns the value of every variable with ${NAME} and $NAME references to variables
+// defined earlier in the file interpolated. Single quoted values are taken literally and
+// unknown references are kept as written.
+func (f *File) Expand() map[string]string {
+	values := make(map[string]string)
+	for _, e := range f.entries {
+		if e.variable == nil {
+			continue
+		}
+		value := e.variable.Example
+		if e.quote != '\'' {
+			value = Interpolate(value, func(name string) (string, bool) {
+				v, ok := values[name]
+				return v, ok
+			})
+		}
+		values[e.variable.Name] = value
+	}
+	return values
+}
+
+// Interpolate replaces ${NAME} and $NAME references using lookup, keeping unknown ones
+func Interpolate(value string, lookup func(name string) (string, bool)) string {
+	if !strings.Contains(value, "$") {
+		return value
+	}
+	return os.Expand(value, func(name string) string {
+		if v, ok := lookup(name); ok {
+			return v
+		}
+		return "${" + name + "}"
+	})
+}
+
+// FormatValue renders a value so that Parse reads it back unchanged, double quoting
+// values with whitespace, quotes, comment markers or newlines
+func FormatValue(value string) string {
+	if !strings.ContainsAny(value, " \t\r\n\"'#\\") {
+		return value
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// IsSecretName reports whether a variable name looks like it holds a credential
+// (DB_PASSWORD, JWT_SECRET, STRIPE_API_KEY)
+func IsSecretName(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, suffix := range secretSuffixes {
+		if upper == strings.TrimPrefix(suffix, "_") || strings.HasSuffix(upper, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRequiredDescription reports whether a variable's comment marks it as required
+func isRequiredDescription(description string) bool {
+	return strings.Contains(strings.ToLower(description), "required")
+}
@@ -43,6 +43,7 @@ READ_TIMEOUT=15s`,
 					Name:        "JWT_SECRET",
 					Description: "Secret key for signing JWT tokens (CHANGE IN PRODUCTION!)",
 					Example:     "your-jwt-secret-key",
+					Secret:      true,
 				},
 				{Name: "READ_TIMEOUT", Description: "Maximum duration for reading the entire request", Example: "15s"},
 			},
@@ -54,8 +55,8 @@ PROJECT_NAME="My Project"
 # API URL
 API_URL="http://localhost:8000"`,
 			expected: []core.EnvVariable{
-				{Name: "PROJECT_NAME", Description: "Project name", Example: "\"My Project\""},
-				{Name: "API_URL", Description: "API URL", Example: "\"http://localhost:8000\""},
+				{Name: "PROJECT_NAME", Description: "Project name", Example: "My Project"},
+				{Name: "API_URL", Description: "API URL", Example: "http://localhost:8000"},
 			},
 		},
 		{
@@ -83,6 +84,48 @@ SERVICE_ID=`,
 				{Name: "SERVICE_ID", Description: "Optional service ID", Example: ""},
 			},
 		},
+		{
+			name: "secret and required variables",
+			content: `# Database password (required)
+DB_PASSWORD=postgres
+STRIPE_API_KEY=
+# Public key id
+KEY_ID=abc`,
+			expected: []core.EnvVariable{
+				{Name: "DB_PASSWORD", Description: "Database password (required)", Example: "postgres",
+					Secret: true, Required: true},
+				{Name: "STRIPE_API_KEY", Secret: true},
+				{Name: "KEY_ID", Description: "Public key id", Example: "abc"},
+			},
+		},
+		{
+			name: "export prefix and inline comments",
+			content: `export APP_ENV=development # dev, staging or production
+# Listen port
+export PORT=8080
+URL=http://host/#anchor`,
+			expected: []core.EnvVariable{
+				{Name: "APP_ENV", Description: "dev, staging or production", Example: "development"},
+				{Name: "PORT", Description: "Listen port", Example: "8080"},
+				{Name: "URL", Example: "http://host/#anchor"},
+			},
+		},
+		{
+			name: "multiline quoted values",
+			content: `# TLS certificate
+TLS_CERT="-----BEGIN CERTIFICATE-----
+MIIB
+-----END CERTIFICATE-----"
+GREETING='Hello
+"world"' # literal
+ESCAPED="line1\nline2 \"quoted\""`,
+			expected: []core.EnvVariable{
+				{Name: "TLS_CERT", Description: "TLS certificate",
+					Example: "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----"},
+				{Name: "GREETING", Description: "literal", Example: "Hello\n\"world\""},
+				{Name: "ESCAPED", Example: "line1\nline2 \"quoted\""},
+			},
+		},
 		{
 			name:     "empty content",
 			content:  "",
@@ -133,6 +176,10 @@ ANOTHER_VAR=another_value`,
 				if actual.Example != expected.Example {
 					t.Errorf("Variable %d example = %v, expected %v", i, actual.Example, expected.Example)
 				}
+				if actual.Secret != expected.Secret || actual.Required != expected.Required {
+					t.Errorf("Variable %d secret/required = %v/%v, expected %v/%v", i,
+						actual.Secret, actual.Required, expected.Secret, expected.Required)
+				}
 			}
 		})
 	}
@@ -202,3 +249,69 @@ METRICS_ENABLED=true`
 		}
 	}
 }
+
+func TestParseRoundTrip(t *testing.T) {
+	content := `# Database
+export DB_HOST=localhost   # primary host
+DB_URL="postgres://${DB_HOST}:5432"
+KEY='-----BEGIN-----
+abc
+-----END-----'
+
+not an assignment
+LAST=1`
+
+	file := Parse(content)
+	if got := file.String(); got != content {
+		t.Fatalf("String() is not lossless.\nExpected: %q\nGot: %q", content, got)
+	}
+
+	file.Set("DB_HOST", "db.internal")
+	file.Set("LAST", "1")
+	file.Set("NEW_VAR", "two words")
+
+	expected := `# Database
+export DB_HOST=db.internal
+DB_URL="postgres://${DB_HOST}:5432"
+KEY='-----BEGIN-----
+abc
+-----END-----'
+
+not an assignment
+LAST=1
+NEW_VAR="two words"
+`
+	if got := file.String(); got != expected {
+		t.Errorf("String() after Set mismatch.\nExpected: %q\nGot: %q", expected, got)
+	}
+
+	reparsed := Parse(file.String()).Variables()
+	if len(reparsed) != 5 || reparsed[4].Example != "two words" || reparsed[0].Example != "db.internal" {
+		t.Errorf("reparsed variables = %+v", reparsed)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	file := Parse(`DB_USER=app
+DB_HOST=localhost
+DB_URL="postgres://${DB_USER}@$DB_HOST/${DB_NAME}"
+LITERAL='${DB_USER}'`)
+
+	values := file.Expand()
+	if got := values["DB_URL"]; got != "postgres://app@localhost/${DB_NAME}" {
+		t.Errorf("DB_URL = %q", got)
+	}
+	if got := values["LITERAL"]; got != "${DB_USER}" {
+		t.Errorf("LITERAL = %q, single quoted values must not be interpolated", got)
+	}
+}
+
+func TestFormatValueRoundTrip(t *testing.T) {
+	for _, value := range []string{"plain", "two words", "multi\nline", `quote " and \ slash`, "a # b", ""} {
+		content := "VALUE=" + FormatValue(value) + "\n"
+		variables := ParseEnvExample(content)
+		if len(variables) != 1 || variables[0].Example != value {
+			t.Errorf("FormatValue(%q) = %q did not round trip: %+v", value, content, variables)
+		}
+	}
+}
@@ -1,23 +1,77 @@
 package extract
 
 import (
-	"encoding/json"
+	"archive/zip"
+	"context"
 	"fmt"
+	"io/fs"
+	"log/slog"
 	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/acheevo/template-engine/internal/core"
 )
 
-func RunWithParams(sourceDir, outputFile, templateType string) error {
-	if templateType == "" {
-		return fmt.Errorf("--type flag is required. Available types: %v", core.ListTemplates())
+// Params holds the inputs of an extraction run
+type Params struct {
+	SourceDir    string
+	OutputFile   string
+	TemplateType string
+	// StrictMappings fails the extraction when a mapping no longer matches its file
+	StrictMappings bool
+	// Codec compresses file contents, gzip when empty and disabled with core.CodecNone
+	Codec core.Codec
+	// Dedupe stores identical file contents once in the schema blob table
+	Dedupe bool
+	// Subdir extracts only this directory of the source (e.g. services/auth),
+	// with paths and mappings relative to it
+	Subdir string
+	// ExplainSkips logs every file left out of the schema with the reason and lists them in the result
+	ExplainSkips bool
+	// MaxFileSize is the size in bytes above which files are handled by LargeFiles, 0 for no limit
+	MaxFileSize int64
+	// LargeFiles embeds files above MaxFileSize with a warning, skips them or externalizes them
+	// into sidecar blobs next to OutputFile, in a directory named after it (template.blobs)
+	LargeFiles core.LargeFilePolicy
+	// DetectTokens are literal strings of the reference project (its name, repository, ...)
+	// turned into mappings wherever they occur, see core.DetectTokens
+	DetectTokens []string
+	// NoGitignore extracts the files the .gitignore files of the source exclude
+	NoGitignore bool
+}
+
+// Result summarizes a completed extraction
+type Result struct {
+	Output        string             `json:"output"`
+	Type          string             `json:"type"`
+	Files         int                `json:"files"`
+	Templated     int                `json:"templated"`
+	Size          int64              `json:"size"`
+	MappingMisses []core.MappingMiss `json:"mapping_misses"`
+	Skipped       []core.SkippedFile `json:"skipped,omitempty"` // Only with Params.ExplainSkips
+	DurationMS    int64              `json:"duration_ms"`
+
+	// DetectedTokens reports the mappings added for Params.DetectTokens
+	DetectedTokens []core.DetectedToken `json:"detected_tokens,omitempty"`
+}
+
+// RunWithParams extracts a template with specified parameters (called by cobra command)
+func RunWithParams(ctx context.Context, logger *slog.Logger, params Params) (*Result, error) {
+	if params.TemplateType == "" {
+		return nil, fmt.Errorf("--type flag is required. Available types: %v", core.ListTemplates())
 	}
 
-	fmt.Printf("Extracting %s template from %s to %s\n", templateType, sourceDir, outputFile)
+	logger.Info("Extracting template",
+		"type", params.TemplateType, "source", params.SourceDir, "subdir", params.Subdir, "output", params.OutputFile)
 
-	return extract(sourceDir, outputFile, templateType)
+	return extract(ctx, logger, params)
 }
 
+// Run extracts a template using command line argument parsing (legacy)
 func Run() error {
 	args := os.Args[2:]
 
@@ -49,50 +103,193 @@ func Run() error {
 		}
 	}
 
-	return RunWithParams(sourceDir, outputFile, templateType)
+	_, err := RunWithParams(context.Background(), slog.Default(), Params{
+		SourceDir:    sourceDir,
+		OutputFile:   outputFile,
+		TemplateType: templateType,
+	})
+	return err
 }
 
-func extract(sourceDir, outputFile, templateType string) error {
+func extract(ctx context.Context, logger *slog.Logger, params Params) (*Result, error) {
+	start := time.Now()
+
 	// Check if source directory exists
-	if _, err := os.Stat(sourceDir); os.IsNotExist(err) {
-		return fmt.Errorf("source directory does not exist: %s", sourceDir)
+	if _, err := os.Stat(params.SourceDir); os.IsNotExist(err) {
+		return nil, fmt.Errorf("source directory does not exist: %s", params.SourceDir)
 	}
 
 	// Get template type from registry
-	template, err := core.GetTemplate(templateType)
+	template, err := core.GetTemplate(params.TemplateType)
 	if err != nil {
-		return fmt.Errorf("failed to get template type: %w", err)
+		return nil, fmt.Errorf("failed to get template type: %w", err)
+	}
+	if deprecation, deprecated := core.TemplateDeprecation(params.TemplateType); deprecated {
+		logger.Warn("Template type is deprecated", "type", params.TemplateType, "replaced_by", deprecation.ReplacedBy)
+	}
+
+	fsys, closeSource, err := openSource(params.SourceDir)
+	if err != nil {
+		return nil, err
+	}
+	defer closeSource()
+
+	if params.Subdir != "" {
+		if fsys, err = subdirFS(fsys, params.Subdir); err != nil {
+			return nil, err
+		}
 	}
 
 	// Extract using the specific template type
-	schema, err := template.Extract(sourceDir)
+	opts := core.ExtractOptions{
+		Codec:       params.Codec,
+		MaxFileSize: params.MaxFileSize,
+		LargeFiles:  params.LargeFiles,
+		NoGitignore: params.NoGitignore,
+	}
+	opts.OnLargeFile = func(path string, size int64) {
+		if params.LargeFiles == core.LargeFileExternalize {
+			logger.Info("Externalizing large file", "path", path, "size", formatSize(size))
+			return
+		}
+		logger.Warn("Embedding large file", "path", path, "size", formatSize(size),
+			"max", formatSize(params.MaxFileSize))
+	}
+	var skipped []core.SkippedFile
+	if params.ExplainSkips {
+		skipped = []core.SkippedFile{}
+		opts.OnSkip = func(file core.SkippedFile) {
+			logger.Info("Skipped file", "path", file.Path, "reason", file.Reason)
+			skipped = append(skipped, file)
+		}
+	}
+	schema, err := template.ExtractFS(ctx, fsys, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract template: %w", err)
+	}
+
+	detected, err := detectTokens(logger, schema, params.DetectTokens)
 	if err != nil {
-		return fmt.Errorf("failed to extract template: %w", err)
+		return nil, err
+	}
+
+	// Lint mappings so stale Find strings in the template type are noticed
+	misses := lintMappings(logger, schema)
+	if params.StrictMappings && len(misses) > 0 {
+		return nil, fmt.Errorf("%d mapping(s) no longer match the reference project", len(misses))
+	}
+
+	if params.Dedupe {
+		saved := core.DedupeSchema(schema)
+		logger.Debug("Deduplicated file contents", "blobs", len(schema.Blobs), "saved", formatSize(int64(saved)))
+	}
+
+	// Save to file, external files into sidecar blobs next to it
+	err = core.SaveSchemaFile(schema, params.OutputFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save template to file: %w", err)
+	}
+
+	result := &Result{
+		Output:        params.OutputFile,
+		Type:          schema.Type,
+		Files:         len(schema.Files),
+		Templated:     countTemplatedFiles(schema.Files),
+		Size:          calculateTotalSize(schema.Files),
+		MappingMisses: misses,
+		Skipped:       skipped,
+		DurationMS:    time.Since(start).Milliseconds(),
+
+		DetectedTokens: detected,
+	}
+
+	logger.Info("Template extracted successfully",
+		"output", result.Output,
+		"type", result.Type,
+		"files", result.Files,
+		"templated", result.Templated,
+		"size", formatSize(result.Size))
+
+	return result, nil
+}
+
+// openSource returns the file tree of a reference project directory or .zip archive, and a
+// function releasing it. Archives holding a single top-level directory, like GitHub source
+// downloads, are opened at that directory.
+func openSource(source string) (fs.FS, func(), error) {
+	if !strings.HasSuffix(source, ".zip") {
+		return core.DirFS(source), func() {}, nil
 	}
 
-	// Save to file
-	err = saveSchemaToFile(schema, outputFile)
+	reader, err := zip.OpenReader(source)
 	if err != nil {
-		return fmt.Errorf("failed to save template to file: %w", err)
+		return nil, nil, fmt.Errorf("failed to open zip archive: %w", err)
 	}
+	closeReader := func() { _ = reader.Close() }
 
-	fmt.Printf("Template extracted successfully to %s\n", outputFile)
-	fmt.Printf("Template type: %s\n", schema.Type)
-	fmt.Printf("Found %d files (%d templated)\n",
-		len(schema.Files),
-		countTemplatedFiles(schema.Files))
-	fmt.Printf("Total size: %s\n", formatSize(calculateTotalSize(schema.Files)))
+	var fsys fs.FS = reader
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		closeReader()
+		return nil, nil, err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		if fsys, err = fs.Sub(fsys, entries[0].Name()); err != nil {
+			closeReader()
+			return nil, nil, err
+		}
+	}
 
-	return nil
+	return fsys, closeReader, nil
 }
 
-func saveSchemaToFile(schema *core.TemplateSchema, filename string) error {
-	data, err := json.MarshalIndent(schema, "", "  ")
+// subdirFS narrows fsys to the directory subdir, given relative to the source root
+func subdirFS(fsys fs.FS, subdir string) (fs.FS, error) {
+	name := path.Clean(filepath.ToSlash(subdir))
+	if !fs.ValidPath(name) || name == "." {
+		return nil, fmt.Errorf("subdir %q must be a directory inside the source", subdir)
+	}
+
+	info, err := fs.Stat(fsys, name)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("subdir %q not found in source: %w", subdir, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("subdir %q is not a directory", subdir)
+	}
+
+	return fs.Sub(fsys, name)
+}
+
+// lintMappings reports mappings that did not match the extracted content
+func lintMappings(logger *slog.Logger, schema *core.TemplateSchema) []core.MappingMiss {
+	misses := core.LintMappings(schema)
+	for _, miss := range misses {
+		logger.Warn("Mapping did not match", "find", miss.Find, "files", strings.Join(miss.Paths, ","))
 	}
+	if misses == nil {
+		misses = []core.MappingMiss{}
+	}
+	return misses
+}
 
-	return os.WriteFile(filename, data, 0o600)
+// detectTokens maps the given literal strings of the reference project, logging what was found
+func detectTokens(logger *slog.Logger, schema *core.TemplateSchema, tokens []string) ([]core.DetectedToken, error) {
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	detected, err := core.DetectTokens(schema, tokens)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect tokens: %w", err)
+	}
+	for _, token := range detected {
+		if len(token.Paths) == 0 {
+			logger.Warn("Token not found in the reference project", "find", token.Find)
+			continue
+		}
+		logger.Info("Detected token", "find", token.Find, "replace", token.Replace, "files", len(token.Paths))
+	}
+	return detected, nil
 }
 
 func countTemplatedFiles(files []core.FileSpec) int {
@@ -113,6 +310,26 @@ func calculateTotalSize(files []core.FileSpec) int64 {
 	return total
 }
 
+// ParseSize parses a file size such as "512KB", "10MB" or "1GB" (units of 1024 bytes) or a
+// plain number of bytes
+func ParseSize(size string) (int64, error) {
+	number := strings.TrimSpace(strings.ToUpper(size))
+	multiplier := int64(1)
+	for i, unit := range []string{"KB", "MB", "GB"} {
+		if trimmed, ok := strings.CutSuffix(number, unit); ok {
+			number, multiplier = trimmed, int64(1)<<(10*(i+1))
+			break
+		}
+	}
+	number = strings.TrimSpace(strings.TrimSuffix(number, "B"))
+
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size %q, expected e.g. 512KB, 10MB or 1GB", size)
+	}
+	return int64(value * float64(multiplier)), nil
+}
+
 func formatSize(bytes int64) string {
 	const unit = 1024
 	if bytes < unit {
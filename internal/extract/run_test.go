@@ -0,0 +1,96 @@
+package extract
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/logging"
+	_ "github.com/acheevo/template-engine/internal/templates"
+)
+
+func TestSubdirFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"services/auth/go.mod": &fstest.MapFile{Data: []byte("module auth\n")},
+		"README.md":            &fstest.MapFile{Data: []byte("# monorepo\n")},
+	}
+
+	for _, subdir := range []string{"../x", ".", "", "services/billing", "README.md"} {
+		if _, err := subdirFS(fsys, subdir); err == nil {
+			t.Errorf("subdirFS(%q) should fail", subdir)
+		}
+	}
+
+	sub, err := subdirFS(fsys, "services/auth/")
+	if err != nil {
+		t.Fatalf("subdirFS() error = %v", err)
+	}
+	if _, err := fs.Stat(sub, "go.mod"); err != nil {
+		t.Errorf("go.mod should be at the root of the subdir: %v", err)
+	}
+}
+
+func TestRunWithParamsSubdir(t *testing.T) {
+	source := t.TempDir()
+	files := map[string]string{
+		"services/auth/README.md": "# __PROJECT_NAME__\n",
+		"services/auth/go.mod":    "module github.com/acme/acme-auth\n",
+		"services/billing/go.mod": "module github.com/acme/acme-billing\n",
+		"README.md":               "# __PROJECT_NAME__ monorepo\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(source, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	output := filepath.Join(t.TempDir(), "auth.json")
+	result, err := RunWithParams(context.Background(), logging.Discard(), Params{
+		SourceDir:    source,
+		OutputFile:   output,
+		TemplateType: "generic",
+		Subdir:       "services/auth",
+		Codec:        core.CodecNone,
+		DetectTokens: []string{"acme-auth"},
+	})
+	if err != nil {
+		t.Fatalf("RunWithParams() error = %v", err)
+	}
+
+	schema, err := core.LoadSchemaFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, file := range schema.Files {
+		paths = append(paths, file.Path)
+	}
+	slices.Sort(paths)
+	if want := []string{"README.md", "go.mod"}; !slices.Equal(paths, want) {
+		t.Fatalf("schema paths = %v, want %v relative to the subdir", paths, want)
+	}
+
+	for _, file := range schema.Files {
+		var finds []string
+		for _, mapping := range file.Mappings {
+			finds = append(finds, mapping.Find)
+		}
+		want := map[string]string{"README.md": "__PROJECT_NAME__", "go.mod": "acme-auth"}[file.Path]
+		if !file.Template || !slices.Contains(finds, want) {
+			t.Errorf("%s: template = %v, mappings = %v, want %s mapped", file.Path, file.Template, finds, want)
+		}
+	}
+
+	if len(result.DetectedTokens) != 1 || !slices.Equal(result.DetectedTokens[0].Paths, []string{"go.mod"}) {
+		t.Errorf("DetectedTokens = %+v, want acme-auth found in go.mod", result.DetectedTokens)
+	}
+}
@@ -0,0 +1,168 @@
+package generate
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/hooks"
+	"github.com/acheevo/template-engine/internal/policy"
+)
+
+// ConflictAction decides what happens to an existing file whose content differs from the template
+type ConflictAction string
+
+const (
+	ConflictSkip      ConflictAction = "skip"
+	ConflictOverwrite ConflictAction = "overwrite"
+)
+
+// ApplyParams holds the inputs of an apply run, overlaying template files onto an existing project
+type ApplyParams struct {
+	TemplateFile string
+	IntoDir      string
+	ProjectName  string
+	GitHubRepo   string
+	// Variables sets further template variables (Author, Description) by name
+	Variables map[string]string
+	// Only applies just the schema files matching these globs, see Params.Only
+	Only []string
+	// NoVerify skips file hash verification for intentionally edited schemas
+	NoVerify bool
+	// Hooks runs the schema's post_update hooks once files have been written
+	Hooks HookOptions
+	// Policies reject requests violating organization rules, see Generator.SetPolicies
+	Policies []policy.Policy
+	// Resolve is asked about every existing file that differs from the template.
+	// Conflicting files are skipped when nil.
+	Resolve func(path string, existing, generated []byte) (ConflictAction, error)
+}
+
+// ApplyResult lists what an apply run did with every template file
+type ApplyResult struct {
+	IntoDir     string         `json:"into_dir"`
+	Created     []string       `json:"created"`
+	Overwritten []string       `json:"overwritten"`
+	Skipped     []string       `json:"skipped"`
+	Unchanged   []string       `json:"unchanged"`
+	Hooks       []hooks.Result `json:"hooks,omitempty"`
+}
+
+// bufferedFile is a generated file held in memory until conflicts are resolved
+type bufferedFile struct {
+	path string
+	data []byte
+	perm fs.FileMode
+}
+
+// bufferOutput collects generated files in memory
+type bufferOutput struct {
+	files []bufferedFile
+}
+
+func (b *bufferOutput) WriteFile(path string, data []byte, perm fs.FileMode) error {
+	b.files = append(b.files, bufferedFile{path: path, data: data, perm: perm})
+	return nil
+}
+
+// Apply renders the template in memory and overlays its files onto an existing project.
+// New files are created, identical files left alone and conflicting ones resolved by
+// params.Resolve. Nothing is written until every conflict has been resolved.
+func Apply(ctx context.Context, logger *slog.Logger, params ApplyParams) (*ApplyResult, error) {
+	info, err := os.Stat(params.IntoDir)
+	if err != nil {
+		return nil, fmt.Errorf("project directory not found: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("not a directory: %s", params.IntoDir)
+	}
+
+	generator, err := NewGenerator(params.TemplateFile, core.TemplateVariables{
+		ProjectName: params.ProjectName,
+		GitHubRepo:  params.GitHubRepo,
+	}, params.IntoDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create generator: %w", err)
+	}
+	for name, value := range params.Variables {
+		if err := generator.SetVariable(name, value); err != nil {
+			return nil, err
+		}
+	}
+	buffer := &bufferOutput{}
+	generator.SetLogger(logger)
+	generator.SetOutput(buffer)
+	generator.SetFileFilter(params.Only)
+	generator.SetPolicies(params.Policies...)
+	generator.SetValidateOptions(core.ValidateOptions{SkipHashes: params.NoVerify})
+
+	if err := generator.Generate(ctx); err != nil {
+		return nil, fmt.Errorf("failed to render template: %w", err)
+	}
+
+	result := &ApplyResult{
+		IntoDir:     params.IntoDir,
+		Created:     []string{},
+		Overwritten: []string{},
+		Skipped:     []string{},
+		Unchanged:   []string{},
+	}
+
+	var writes []bufferedFile
+	for _, file := range buffer.files {
+		existing, err := os.ReadFile(filepath.Join(params.IntoDir, filepath.FromSlash(file.path)))
+		switch {
+		case os.IsNotExist(err):
+			writes = append(writes, file)
+			result.Created = append(result.Created, file.path)
+			continue
+		case err != nil:
+			return nil, err
+		case bytes.Equal(existing, file.data):
+			result.Unchanged = append(result.Unchanged, file.path)
+			continue
+		}
+
+		action := ConflictSkip
+		if params.Resolve != nil {
+			if action, err = params.Resolve(file.path, existing, file.data); err != nil {
+				return nil, err
+			}
+		}
+		if action == ConflictOverwrite {
+			writes = append(writes, file)
+			result.Overwritten = append(result.Overwritten, file.path)
+		} else {
+			result.Skipped = append(result.Skipped, file.path)
+		}
+	}
+
+	into := dirOutput{dir: params.IntoDir}
+	for _, file := range writes {
+		if err := into.WriteFile(file.path, file.data, file.perm); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", file.path, err)
+		}
+	}
+
+	logger.Info("Template applied",
+		"into", params.IntoDir,
+		"created", len(result.Created),
+		"overwritten", len(result.Overwritten),
+		"skipped", len(result.Skipped),
+		"unchanged", len(result.Unchanged))
+
+	if len(writes) > 0 && params.Hooks.hooksAllowed(logger, generator.schema, core.HookPostUpdate) {
+		result.Hooks, err = hooks.Run(ctx, logger, generator.schema.Hooks.ForStage(core.HookPostUpdate),
+			params.Hooks.runOptions(params.IntoDir, generator.Variables()))
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,88 @@
+package generate
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/logging"
+)
+
+func TestApply(t *testing.T) {
+	tempDir := t.TempDir()
+	schemaFile := filepath.Join(tempDir, "schema.json")
+	schema := testSchema(
+		core.FileSpec{Path: ".golangci.yml", Content: "linters: {}"},
+		core.FileSpec{Path: "Makefile", Content: "build:"},
+		core.FileSpec{Path: "README.md", Template: true, Content: "# {{.ProjectName}}"},
+		core.FileSpec{Path: ".github/workflows/ci.yml", Content: "ci"},
+	)
+	if err := core.SaveSchemaFile(schema, schemaFile); err != nil {
+		t.Fatal(err)
+	}
+
+	projectDir := filepath.Join(tempDir, "project")
+	if err := os.MkdirAll(projectDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	existing := map[string]string{
+		".golangci.yml": "linters: {}", // Identical
+		"Makefile":      "test:",       // Conflict, overwritten
+		"README.md":     "# Mine",      // Conflict, skipped
+	}
+	for path, content := range existing {
+		if err := os.WriteFile(filepath.Join(projectDir, path), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var asked []string
+	result, err := Apply(context.Background(), logging.Discard(), ApplyParams{
+		TemplateFile: schemaFile,
+		IntoDir:      projectDir,
+		ProjectName:  "My App",
+		GitHubRepo:   "user/my-app",
+		Resolve: func(path string, existing, generated []byte) (ConflictAction, error) {
+			asked = append(asked, path)
+			if path == "Makefile" {
+				return ConflictOverwrite, nil
+			}
+			return ConflictSkip, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	expected := &ApplyResult{
+		IntoDir:     projectDir,
+		Created:     []string{".github/workflows/ci.yml"},
+		Overwritten: []string{"Makefile"},
+		Skipped:     []string{"README.md"},
+		Unchanged:   []string{".golangci.yml"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Apply() = %+v, want %+v", result, expected)
+	}
+	if !reflect.DeepEqual(asked, []string{"Makefile", "README.md"}) {
+		t.Errorf("Resolve asked about %v", asked)
+	}
+
+	for path, want := range map[string]string{
+		"Makefile": "build:", "README.md": "# Mine", ".github/workflows/ci.yml": "ci",
+	} {
+		if got := readOutput(t, projectDir, path); got != want {
+			t.Errorf("%s = %q, want %q", path, got, want)
+		}
+	}
+
+	if _, err := Apply(context.Background(), logging.Discard(), ApplyParams{
+		TemplateFile: schemaFile, IntoDir: filepath.Join(tempDir, "missing"), ProjectName: "My App",
+		GitHubRepo: "user/my-app",
+	}); err == nil {
+		t.Error("Apply() should fail for a missing project directory")
+	}
+}
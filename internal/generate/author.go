@@ -0,0 +1,39 @@
+package generate
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+)
+
+// fallbackAuthor is used when git has no user configured
+const fallbackAuthor = "Developer"
+
+// DefaultAuthor returns the git user configured for the current directory as "Name <email>",
+// falling back to "Developer" when git or both settings are unavailable
+func DefaultAuthor(ctx context.Context) string {
+	return formatAuthor(gitConfig(ctx, "user.name"), gitConfig(ctx, "user.email"))
+}
+
+// formatAuthor combines a name and email into an author string
+func formatAuthor(name, email string) string {
+	switch {
+	case name != "" && email != "":
+		return name + " <" + email + ">"
+	case name != "":
+		return name
+	case email != "":
+		return email
+	default:
+		return fallbackAuthor
+	}
+}
+
+// gitConfig returns the value of a git config key, or "" when it is unset or git is missing
+func gitConfig(ctx context.Context, key string) string {
+	output, err := exec.CommandContext(ctx, "git", "config", "--get", key).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
@@ -0,0 +1,45 @@
+package generate
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestFormatAuthor(t *testing.T) {
+	tests := []struct {
+		name, email, want string
+	}{
+		{"Jane Doe", "jane@example.com", "Jane Doe <jane@example.com>"},
+		{"Jane Doe", "", "Jane Doe"},
+		{"", "jane@example.com", "jane@example.com"},
+		{"", "", "Developer"},
+	}
+
+	for _, tt := range tests {
+		if got := formatAuthor(tt.name, tt.email); got != tt.want {
+			t.Errorf("formatAuthor(%q, %q) = %q, want %q", tt.name, tt.email, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultAuthorFromGitConfig(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	globalConfig := filepath.Join(t.TempDir(), "gitconfig")
+	content := "[user]\n\tname = Jane Doe\n\temail = jane@example.com\n"
+	if err := os.WriteFile(globalConfig, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", globalConfig)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_DIR", t.TempDir()) // Keep the repository's own config out of the lookup
+
+	if got := DefaultAuthor(context.Background()); got != "Jane Doe <jane@example.com>" {
+		t.Errorf("DefaultAuthor() = %q", got)
+	}
+}
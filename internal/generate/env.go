@@ -0,0 +1,209 @@
+package generate
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/envparser"
+)
+
+// EnvOptions controls writing env files populated from the schema's EnvConfig
+type EnvOptions struct {
+	// File is the name of the env file written next to each .env.example (e.g. .env or .env.local).
+	// No env file is written when empty.
+	File string
+	// Values override the examples, keyed by variable name (--env KEY=VALUE). Keys of the form
+	// component.NAME (see core.EnvVariable.Key) only set the variable of that component, taking
+	// precedence over NAME.
+	Values map[string]string
+	// Prompt, when set, asks for the value of every variable not given in Values.
+	// It receives the variable with its example as the default.
+	Prompt func(variable core.EnvVariable) (string, error)
+	// AllowExampleSecrets permits writing the example value of secret variables
+	AllowExampleSecrets bool
+}
+
+// ParseEnvAssignments parses KEY=VALUE pairs as given to --env
+func ParseEnvAssignments(assignments []string) (map[string]string, error) {
+	values := make(map[string]string, len(assignments))
+	for _, assignment := range assignments {
+		name, value, found := strings.Cut(assignment, "=")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid env assignment %q, expected KEY=VALUE", assignment)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// SetEnvOptions configures env file generation (disabled by default)
+func (g *Generator) SetEnvOptions(opts EnvOptions) {
+	g.env = opts
+}
+
+// envFile is an env file to write, relative to the output directory
+type envFile struct {
+	path      string
+	source    string // Env example the variables come from, relative to the project root
+	variables []core.EnvVariable
+}
+
+// writeEnvFiles writes the env files prepared before generation
+func (g *Generator) writeEnvFiles(files []envFile) error {
+	for _, file := range files {
+		content, err := g.renderEnvFile(file)
+		if err != nil {
+			return err
+		}
+
+		// Env files commonly hold credentials, keep them private to the owner
+		if err := g.output.WriteFile(file.path, []byte(content), 0o600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.path, err)
+		}
+
+		g.logger.Debug("Writing env file", "path", file.path, "variables", len(file.variables))
+		g.result.EnvFiles = append(g.result.EnvFiles, file.path)
+		g.result.BytesWritten += int64(len(content))
+	}
+
+	return nil
+}
+
+// prepareEnvFiles resolves one env file per env example directory of the reference project,
+// so one per component of multi-component projects, with values taken from overrides, prompts
+// and examples. Overrides for variables the schema does not declare are added to the env file
+// of the component they name, or to the root env file.
+func (g *Generator) prepareEnvFiles() ([]envFile, error) {
+	if g.env.File == "" {
+		return nil, nil
+	}
+
+	var files []envFile
+	index := make(map[string]int)
+	declared := make(map[string]bool)
+	componentDirs := make(map[string]string)
+	var exampleSecrets, missing []string
+
+	add := func(dir string, variable core.EnvVariable) {
+		i, exists := index[dir]
+		if !exists {
+			i = len(files)
+			index[dir] = i
+			files = append(files, envFile{path: path.Join(dir, g.env.File), source: variable.Source})
+		}
+		files[i].variables = append(files[i].variables, variable)
+	}
+
+	for _, variable := range g.schema.EnvConfig {
+		declared[variable.Name] = true
+		declared[variable.Key()] = true
+
+		value, err := g.envValue(variable)
+		if err != nil {
+			return nil, err
+		}
+		if variable.Secret && value != "" && value == variable.Example && !g.env.AllowExampleSecrets {
+			exampleSecrets = append(exampleSecrets, variable.Key())
+		}
+		if variable.Required && value == "" {
+			missing = append(missing, variable.Key())
+		}
+		variable.Example = value
+
+		dir := "."
+		if variable.Source != "" {
+			dir = path.Dir(variable.Source)
+		}
+		if !filepath.IsLocal(filepath.FromSlash(dir)) {
+			return nil, fmt.Errorf("env variable %s comes from %s, outside the project", variable.Key(), variable.Source)
+		}
+		if variable.Component != "" {
+			componentDirs[variable.Component] = dir
+		}
+		add(dir, variable)
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("required env variables have no value: %s (set them with --env KEY=VALUE)",
+			strings.Join(missing, ", "))
+	}
+	if len(exampleSecrets) > 0 {
+		return nil, fmt.Errorf("refusing to write example values of secret env variables %s: "+
+			"set them with --env KEY=VALUE or --env-prompt, or pass --allow-example-secrets",
+			strings.Join(exampleSecrets, ", "))
+	}
+
+	var extra []string
+	for name := range g.env.Values {
+		if !declared[name] {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	for _, key := range extra {
+		dir, name := ".", key
+		if component, rest, found := strings.Cut(key, "."); found && componentDirs[component] != "" {
+			dir, name = componentDirs[component], rest
+		}
+		add(dir, core.EnvVariable{Name: name, Example: g.env.Values[key]})
+	}
+
+	return files, nil
+}
+
+// envValue returns the value written for a single variable
+func (g *Generator) envValue(variable core.EnvVariable) (string, error) {
+	if value, exists := g.env.Values[variable.Key()]; exists {
+		return value, nil
+	}
+	if value, exists := g.env.Values[variable.Name]; exists {
+		return value, nil
+	}
+	if g.env.Prompt != nil {
+		return g.env.Prompt(variable)
+	}
+	return variable.Example, nil
+}
+
+// renderEnvFile produces the content of an env file. When the schema embeds the env example
+// the variables come from, its layout and comments are kept and only the values change.
+func (g *Generator) renderEnvFile(file envFile) (string, error) {
+	for _, fileSpec := range g.schema.Files {
+		if file.source == "" || filepath.ToSlash(fileSpec.Path) != file.source {
+			continue
+		}
+
+		content, err := core.ResolveContent(g.schema, fileSpec)
+		if err != nil {
+			return "", fmt.Errorf("failed to decompress %s: %w", fileSpec.Path, err)
+		}
+
+		example := envparser.Parse(content)
+		for _, variable := range file.variables {
+			example.Set(variable.Name, variable.Example)
+		}
+		return example.String(), nil
+	}
+
+	return formatEnvFile(file.variables), nil
+}
+
+// formatEnvFile renders variables as NAME=value lines, preceded by their description
+func formatEnvFile(variables []core.EnvVariable) string {
+	var out strings.Builder
+	for i, variable := range variables {
+		if variable.Description != "" {
+			if i > 0 {
+				out.WriteString("\n")
+			}
+			fmt.Fprintf(&out, "# %s\n", variable.Description)
+		}
+		fmt.Fprintf(&out, "%s=%s\n", variable.Name, envparser.FormatValue(variable.Example))
+	}
+	return out.String()
+}
@@ -0,0 +1,197 @@
+package generate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/envparser"
+)
+
+func TestGenerateEnvFiles(t *testing.T) {
+	schema := testSchema(core.FileSpec{Path: "README.md", Content: "readme"})
+	schema.EnvConfig = []core.EnvVariable{
+		{Name: "DB_HOST", Description: "Database host", Example: "localhost", Source: ".env.example"},
+		{Name: "DB_PASSWORD", Example: "postgres", Source: ".env.example"},
+		{Name: "VITE_API_URL", Example: "http://localhost:8080", Source: "frontend/.env.example"},
+	}
+
+	var prompted []string
+	outputDir := generateSchema(t, schema, func(g *Generator) {
+		g.SetEnvOptions(EnvOptions{
+			File:   ".env.local",
+			Values: map[string]string{"DB_PASSWORD": "s3cret pass", "EXTRA": "1"},
+			Prompt: func(variable core.EnvVariable) (string, error) {
+				prompted = append(prompted, variable.Name)
+				if variable.Name == "VITE_API_URL" {
+					return "https://api.example.com", nil
+				}
+				return variable.Example, nil
+			},
+		})
+	})
+
+	expectedRoot := "# Database host\nDB_HOST=localhost\nDB_PASSWORD=\"s3cret pass\"\nEXTRA=1\n"
+	if got := readOutput(t, outputDir, ".env.local"); got != expectedRoot {
+		t.Errorf(".env.local mismatch.\nExpected: %q\nGot: %q", expectedRoot, got)
+	}
+
+	expectedFrontend := "VITE_API_URL=https://api.example.com\n"
+	if got := readOutput(t, outputDir, "frontend/.env.local"); got != expectedFrontend {
+		t.Errorf("frontend/.env.local mismatch.\nExpected: %q\nGot: %q", expectedFrontend, got)
+	}
+
+	if len(prompted) != 2 || prompted[0] != "DB_HOST" || prompted[1] != "VITE_API_URL" {
+		t.Errorf("prompted = %v, want only variables without a --env value", prompted)
+	}
+
+	info, err := os.Stat(filepath.Join(outputDir, ".env.local"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf(".env.local permissions = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestGenerateEnvFilesPerComponent(t *testing.T) {
+	schema := testSchema(core.FileSpec{Path: "README.md", Content: "readme"})
+	schema.EnvConfig = []core.EnvVariable{
+		{Name: "PORT", Example: "8080", Source: ".env.example", Component: "api"},
+		{Name: "LOG_LEVEL", Example: "info", Source: ".env.example", Component: "api"},
+		{Name: "PORT", Example: "3000", Source: "frontend/.env.example", Component: "frontend"},
+		{Name: "LOG_LEVEL", Example: "warn", Source: "frontend/.env.example", Component: "frontend"},
+	}
+
+	outputDir := generateSchema(t, schema, func(g *Generator) {
+		g.SetEnvOptions(EnvOptions{
+			File:   ".env",
+			Values: map[string]string{"frontend.PORT": "5173", "LOG_LEVEL": "debug", "frontend.VITE_MODE": "dev"},
+		})
+	})
+
+	expectedAPI := "PORT=8080\nLOG_LEVEL=debug\n"
+	if got := readOutput(t, outputDir, ".env"); got != expectedAPI {
+		t.Errorf(".env mismatch.\nExpected: %q\nGot: %q", expectedAPI, got)
+	}
+
+	expectedFrontend := "PORT=5173\nLOG_LEVEL=debug\nVITE_MODE=dev\n"
+	if got := readOutput(t, outputDir, "frontend/.env"); got != expectedFrontend {
+		t.Errorf("frontend/.env mismatch.\nExpected: %q\nGot: %q", expectedFrontend, got)
+	}
+}
+
+func TestGenerateWithoutEnvFile(t *testing.T) {
+	schema := testSchema(core.FileSpec{Path: "README.md", Content: "readme"})
+	schema.EnvConfig = []core.EnvVariable{{Name: "PORT", Example: "8080"}}
+
+	outputDir := generateSchema(t, schema)
+
+	if _, err := os.Stat(filepath.Join(outputDir, ".env")); !os.IsNotExist(err) {
+		t.Errorf("no env file should be written unless requested, stat error = %v", err)
+	}
+}
+
+func TestParseEnvAssignments(t *testing.T) {
+	values, err := ParseEnvAssignments([]string{"A=1", "B=x=y", "C="})
+	if err != nil {
+		t.Fatalf("ParseEnvAssignments() error = %v", err)
+	}
+	if values["A"] != "1" || values["B"] != "x=y" || values["C"] != "" {
+		t.Errorf("ParseEnvAssignments() = %v", values)
+	}
+
+	for _, invalid := range []string{"NOVALUE", "=value"} {
+		if _, err := ParseEnvAssignments([]string{invalid}); err == nil {
+			t.Errorf("ParseEnvAssignments(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestGenerateEnvSecrets(t *testing.T) {
+	schema := testSchema(core.FileSpec{Path: "README.md", Content: "readme"})
+	schema.EnvConfig = []core.EnvVariable{
+		{Name: "JWT_SECRET", Example: "change-me", Secret: true},
+		{Name: "API_KEY", Secret: true},
+		{Name: "DB_NAME", Example: "", Required: true},
+	}
+
+	tempDir := t.TempDir()
+	schemaFile := filepath.Join(tempDir, "schema.json")
+	if err := core.SaveSchemaFile(schema, schemaFile); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    EnvOptions
+		wantErr string
+	}{
+		{
+			name:    "required variable without value",
+			opts:    EnvOptions{File: ".env"},
+			wantErr: "DB_NAME",
+		},
+		{
+			name:    "example secret refused",
+			opts:    EnvOptions{File: ".env", Values: map[string]string{"DB_NAME": "app"}},
+			wantErr: "JWT_SECRET",
+		},
+		{
+			name: "example secret allowed",
+			opts: EnvOptions{File: ".env", Values: map[string]string{"DB_NAME": "app"}, AllowExampleSecrets: true},
+		},
+		{
+			name: "secret provided",
+			opts: EnvOptions{File: ".env", Values: map[string]string{"DB_NAME": "app", "JWT_SECRET": "real"}},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputDir := filepath.Join(tempDir, fmt.Sprintf("output-%d", i))
+			generator, err := NewGenerator(schemaFile, testVariables, outputDir)
+			if err != nil {
+				t.Fatalf("NewGenerator() error = %v", err)
+			}
+			generator.SetEnvOptions(tt.opts)
+
+			err = generator.Generate(context.Background())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Generate() error = %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Generate() error = %v, want mention of %s", err, tt.wantErr)
+			}
+			if _, err := os.Stat(outputDir); !os.IsNotExist(err) {
+				t.Error("nothing should be written when env values are rejected")
+			}
+		})
+	}
+}
+
+func TestGenerateEnvFileKeepsExampleLayout(t *testing.T) {
+	example := "# Server\nexport PORT=8080 # listen port\n\n# Database password\nDB_PASSWORD=postgres\n"
+	schema := testSchema(core.FileSpec{Path: ".env.example", Content: example})
+	schema.EnvConfig = envparser.ParseEnvExample(example)
+	for i := range schema.EnvConfig {
+		schema.EnvConfig[i].Source = ".env.example"
+	}
+
+	outputDir := generateSchema(t, schema, func(g *Generator) {
+		g.SetEnvOptions(EnvOptions{File: ".env", Values: map[string]string{"DB_PASSWORD": "hunter2"}})
+	})
+
+	expected := "# Server\nexport PORT=8080 # listen port\n\n# Database password\nDB_PASSWORD=hunter2\n"
+	if got := readOutput(t, outputDir, ".env"); got != expected {
+		t.Errorf(".env mismatch.\nExpected: %q\nGot: %q", expected, got)
+	}
+}
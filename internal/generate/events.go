@@ -0,0 +1,134 @@
+package generate
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/hooks"
+)
+
+// EventType identifies what an Event reports
+type EventType string
+
+const (
+	// EventFile reports a file written to the output
+	EventFile EventType = "file"
+	// EventHookStart reports a hook about to run
+	EventHookStart EventType = "hook_start"
+	// EventHookOutput carries one line of output of the running hook
+	EventHookOutput EventType = "hook_output"
+	// EventHook reports the result of a hook
+	EventHook EventType = "hook"
+	// EventDone is the last event of a successful generation, with its Result
+	EventDone EventType = "done"
+	// EventError is the last event of a failed generation
+	EventError EventType = "error"
+)
+
+// Event reports the progress of a generation as it happens, see SetEventHandler
+type Event struct {
+	Type EventType `json:"type"`
+	// Path, Bytes, Template and State describe the file of an EventFile; Index counts the
+	// files generated so far out of Total
+	Path     string    `json:"path,omitempty"`
+	Bytes    int       `json:"bytes,omitempty"`
+	Template bool      `json:"template,omitempty"`
+	State    FileState `json:"state,omitempty"`
+	Index    int       `json:"index,omitempty"`
+	Total    int       `json:"total,omitempty"`
+	// Hook labels the hook of hook events, Line is a line of its output
+	Hook       string        `json:"hook,omitempty"`
+	Line       string        `json:"line,omitempty"`
+	HookResult *hooks.Result `json:"hook_result,omitempty"`
+	Result     *Result       `json:"result,omitempty"`
+	Error      string        `json:"error,omitempty"`
+}
+
+// SetEventHandler reports the progress of Generate to handle: every file written, hook runs
+// with their output line by line, and finally EventDone or EventError. handle is called
+// synchronously, so a slow handler slows down generation.
+func (g *Generator) SetEventHandler(handle func(Event)) {
+	g.events = handle
+}
+
+// emit reports an event to the event handler, if any
+func (g *Generator) emit(event Event) {
+	if g.events != nil {
+		g.events(event)
+	}
+}
+
+// emitResult reports the end of Generate as EventDone or EventError
+func (g *Generator) emitResult(err error) {
+	if err != nil {
+		g.emit(Event{Type: EventError, Error: err.Error()})
+		return
+	}
+	g.emit(Event{Type: EventDone, Result: g.Result()})
+}
+
+// hookEvents wires the event handler into hook runs: start and result events, and an output
+// writer emitting a hook output event per line
+func (g *Generator) hookEvents(opts *hooks.Options) {
+	if g.events == nil {
+		return
+	}
+
+	lines := &lineWriter{}
+	if opts.Output != nil {
+		opts.Output = io.MultiWriter(opts.Output, lines)
+	} else {
+		opts.Output = lines
+	}
+
+	opts.OnStart = func(hook core.Hook) {
+		label := hook.Label()
+		lines.emit = func(line string) {
+			g.emit(Event{Type: EventHookOutput, Hook: label, Line: line})
+		}
+		g.emit(Event{Type: EventHookStart, Hook: label})
+	}
+	opts.OnResult = func(result hooks.Result) {
+		lines.Flush()
+		label := result.Name
+		if label == "" {
+			label = result.Command
+		}
+		g.emit(Event{Type: EventHook, Hook: label, HookResult: &result})
+	}
+}
+
+// lineWriter splits the output written to it into lines
+type lineWriter struct {
+	emit    func(line string)
+	pending []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+	for {
+		end := bytes.IndexByte(w.pending, '\n')
+		if end < 0 {
+			break
+		}
+		w.emitLine(w.pending[:end])
+		w.pending = w.pending[end+1:]
+	}
+	return len(p), nil
+}
+
+// Flush emits the last line when it was not terminated by a newline
+func (w *lineWriter) Flush() {
+	if len(w.pending) > 0 {
+		w.emitLine(w.pending)
+		w.pending = nil
+	}
+}
+
+func (w *lineWriter) emitLine(line []byte) {
+	if w.emit != nil {
+		w.emit(strings.TrimSuffix(string(line), "\r"))
+	}
+}
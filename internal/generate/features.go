@@ -0,0 +1,70 @@
+package generate
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+// SetFeatures enables and disables optional features of the schema (see
+// core.TemplateSchema.Features) by name; the others keep their default. The files and hooks of
+// disabled features are left out.
+func (g *Generator) SetFeatures(enable, disable []string) {
+	g.enable, g.disable = enable, disable
+}
+
+// EnabledFeatures returns the names of the features of the schema generation includes, in
+// schema order
+func (g *Generator) EnabledFeatures() ([]string, error) {
+	for _, name := range append(slices.Clone(g.enable), g.disable...) {
+		if !slices.ContainsFunc(g.schema.Features, func(feature core.Feature) bool { return feature.Name == name }) {
+			return nil, fmt.Errorf("unknown feature %q", name)
+		}
+		if slices.Contains(g.enable, name) && slices.Contains(g.disable, name) {
+			return nil, fmt.Errorf("feature %s cannot be both enabled and disabled", name)
+		}
+	}
+
+	enabled := []string{}
+	for _, feature := range g.schema.Features {
+		if slices.Contains(g.enable, feature.Name) || feature.Default && !slices.Contains(g.disable, feature.Name) {
+			enabled = append(enabled, feature.Name)
+		}
+	}
+	return enabled, nil
+}
+
+// disabledFeatures returns the features of the schema generation leaves out
+func (g *Generator) disabledFeatures() ([]core.Feature, error) {
+	enabled, err := g.EnabledFeatures()
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(slices.Clone(g.schema.Features), func(feature core.Feature) bool {
+		return slices.Contains(enabled, feature.Name)
+	}), nil
+}
+
+// dropFeatures removes the files of the disabled features from files
+func dropFeatures(files []core.FileSpec, disabled []core.Feature) []core.FileSpec {
+	if len(disabled) == 0 {
+		return files
+	}
+	return slices.DeleteFunc(slices.Clone(files), func(file core.FileSpec) bool {
+		return slices.ContainsFunc(disabled, func(feature core.Feature) bool {
+			return slices.ContainsFunc(feature.Files, func(pattern string) bool {
+				return matchesDir(pattern, file.Path)
+			})
+		})
+	})
+}
+
+// featureHooks returns hooks without those of the disabled features
+func featureHooks(hooks []core.Hook, disabled []core.Feature) []core.Hook {
+	return slices.DeleteFunc(slices.Clone(hooks), func(hook core.Hook) bool {
+		return hook.Name != "" && slices.ContainsFunc(disabled, func(feature core.Feature) bool {
+			return slices.Contains(feature.Hooks, hook.Name)
+		})
+	})
+}
@@ -0,0 +1,63 @@
+package generate
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+// forEachItem is the name templates see the item of a file generated per item under
+const forEachItem = "item"
+
+// expandForEach replaces each file generated per item of a list variable with one file per
+// item, at its path rendered with the item. The item of every such path is recorded for
+// rendering the content.
+func (g *Generator) expandForEach(files []core.FileSpec) ([]core.FileSpec, error) {
+	g.items = map[string]string{}
+	expanded := make([]core.FileSpec, 0, len(files))
+
+	for _, file := range files {
+		if file.ForEach == "" {
+			expanded = append(expanded, file)
+			continue
+		}
+
+		value, err := g.schema.Variables[file.ForEach].ParseValue(g.variables.Custom[file.ForEach])
+		if err != nil {
+			return nil, fmt.Errorf("variable %s: %w", file.ForEach, err)
+		}
+		items, ok := value.([]string)
+		if !ok {
+			return nil, fmt.Errorf("file %s: variable %s is not a list", file.Path, file.ForEach)
+		}
+
+		left, right := core.EffectiveDelims(g.schema, file)
+		for _, item := range items {
+			path, err := newRenderer(g.templateFuncMap, g.itemData(item)).render(file.Path, left, right)
+			if err != nil {
+				return nil, fmt.Errorf("failed to render path of %s for %q: %w", file.Path, item, err)
+			}
+			if !filepath.IsLocal(filepath.FromSlash(path)) {
+				return nil, fmt.Errorf("file %s renders outside the project for %q: %s", file.Path, item, path)
+			}
+			if _, duplicate := g.items[path]; duplicate {
+				return nil, fmt.Errorf("file %s renders to %s for several items of %s", file.Path, path, file.ForEach)
+			}
+
+			g.items[path] = item
+			instance := file
+			instance.Path = path
+			expanded = append(expanded, instance)
+		}
+	}
+
+	return expanded, nil
+}
+
+// itemData returns the template data of a file generated for item
+func (g *Generator) itemData(item string) map[string]any {
+	data := g.templateData()
+	data[forEachItem] = item
+	return data
+}
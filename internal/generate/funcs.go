@@ -0,0 +1,130 @@
+package generate
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+// randomAlphabet is the character set used by randomString
+const randomAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// TemplateFuncs returns the functions available to templated files
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"kebab":         core.KebabCase,
+		"snake":         core.SnakeCase,
+		"transliterate": core.Transliterate,
+		"upper":         strings.ToUpper,
+		"lower":         strings.ToLower,
+		"title":         title,
+		"camel":         core.CamelCase,
+		"pascal":        core.PascalCase,
+		"pluralize":     pluralize,
+		"slugify":       core.KebabCase,
+		"trimPrefix":    func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix":    func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"replace":       func(old, replacement, s string) string { return strings.ReplaceAll(s, old, replacement) },
+		"join":          func(sep string, items []string) string { return strings.Join(items, sep) },
+		"env":           os.Getenv,
+		"uuid":          newUUID,
+		"now":           time.Now,
+		"date":          func(layout string, t time.Time) string { return t.Format(layout) },
+		"randomString":  randomString,
+	}
+}
+
+// SetUntrusted treats the schema as untrusted, like the schemas pulled from a registry (see
+// core.TemplateSchema.IsRemote): its templates cannot read the environment of the generating
+// process, which could hold credentials. Servers generating uploaded schemas set it.
+func (g *Generator) SetUntrusted(untrusted bool) {
+	g.untrusted = untrusted
+}
+
+// restrictFuncs replaces the template functions untrusted schemas may not use: env returns ""
+func (g *Generator) restrictFuncs() {
+	if !g.untrusted && !g.schema.IsRemote() {
+		return
+	}
+	warned := map[string]bool{}
+	g.templateFuncMap["env"] = func(name string) string {
+		if !warned[name] {
+			warned[name] = true
+			g.logger.Warn("Untrusted schema cannot read environment variables, using an empty value",
+				"variable", name, "schema", g.schema.Name)
+		}
+		return ""
+	}
+}
+
+// title upper-cases the first rune of s
+func title(s string) string {
+	if s == "" {
+		return s
+	}
+	runes := []rune(s)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// pluralize applies basic English pluralization rules to s
+func pluralize(s string) string {
+	lower := strings.ToLower(s)
+	switch {
+	case s == "":
+		return s
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return s[:len(s)-1] + "ies"
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return s + "es"
+	default:
+		return s + "s"
+	}
+}
+
+// newUUID returns a random (version 4) UUID
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// randomString returns a random alphanumeric string of length n
+func randomString(n int) (string, error) {
+	if n < 0 {
+		return "", fmt.Errorf("randomString length must not be negative")
+	}
+
+	result := make([]byte, n)
+	limit := big.NewInt(int64(len(randomAlphabet)))
+	for i := range result {
+		index, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", err
+		}
+		result[i] = randomAlphabet[index.Int64()]
+	}
+	return string(result), nil
+}
+
+// checkRequiredFuncs ensures every function a schema declares is provided by this engine
+func checkRequiredFuncs(required []string, funcs template.FuncMap) error {
+	for _, name := range required {
+		if _, exists := funcs[name]; !exists {
+			return fmt.Errorf("schema requires template function %q which this engine does not provide", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package generate
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestPluralize(t *testing.T) {
+	tests := map[string]string{
+		"service": "services",
+		"policy":  "policies",
+		"day":     "days",
+		"box":     "boxes",
+		"branch":  "branches",
+		"":        "",
+	}
+
+	for input, want := range tests {
+		if got := pluralize(input); got != want {
+			t.Errorf("pluralize(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestSlugFuncs(t *testing.T) {
+	tmpl := template.Must(template.New("test").Funcs(TemplateFuncs()).Parse(
+		`{{kebab .}}|{{snake .}}|{{slugify .}}|{{transliterate .}}`))
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, "Café  Crème API"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if want := "cafe-creme-api|cafe_creme_api|cafe-creme-api|Cafe  Creme API"; out.String() != want {
+		t.Errorf("Execute() = %q, want %q", out.String(), want)
+	}
+}
+
+func TestTemplateFuncsInTemplates(t *testing.T) {
+	t.Setenv("TEMPLATE_ENGINE_TEST", "from-env")
+
+	tmpl := template.Must(template.New("test").Funcs(TemplateFuncs()).Parse(
+		`{{.Name | trimPrefix "go-" | replace "-" "_"}}|{{env "TEMPLATE_ENGINE_TEST"}}|` +
+			`{{now | date "2006"}}|{{randomString 8}}|{{uuid}}`))
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, map[string]string{"Name": "go-user-api"}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	parts := strings.Split(out.String(), "|")
+	if parts[0] != "user_api" || parts[1] != "from-env" {
+		t.Errorf("Unexpected string func output: %q", out.String())
+	}
+	if len(parts[2]) != 4 {
+		t.Errorf("Expected a four digit year, got %q", parts[2])
+	}
+	if len(parts[3]) != 8 {
+		t.Errorf("Expected an 8 character random string, got %q", parts[3])
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(parts[4]) {
+		t.Errorf("Expected a v4 UUID, got %q", parts[4])
+	}
+}
+
+func TestCheckRequiredFuncs(t *testing.T) {
+	funcs := TemplateFuncs()
+
+	if err := checkRequiredFuncs([]string{"camel", "uuid"}, funcs); err != nil {
+		t.Errorf("Expected provided functions to pass, got %v", err)
+	}
+	if err := checkRequiredFuncs([]string{"camel", "sha512"}, funcs); err == nil {
+		t.Error("Expected error for unknown required function")
+	}
+}
@@ -1,16 +1,22 @@
 package generate
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
+	"io"
+	"log/slog"
+	"maps"
 	"os"
-	"path/filepath"
+	"slices"
 	"strings"
 	"text/template"
-	"unicode"
+	"time"
 
 	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/hooks"
+	"github.com/acheevo/template-engine/internal/logging"
+	"github.com/acheevo/template-engine/internal/policy"
+	"github.com/acheevo/template-engine/pkg/schema"
 )
 
 // Generator handles the generation of projects from template schemas
@@ -18,206 +24,547 @@ type Generator struct {
 	schema          *core.TemplateSchema
 	variables       *core.TemplateVariables
 	outputDir       string
+	output          Output
 	templateFuncMap template.FuncMap
+	logger          *slog.Logger
+	env             EnvOptions
+	hooks           HookOptions
+	validate        core.ValidateOptions
+	only            []string
+	result          Result
+	events          func(Event)
+	overwrite       OverwritePolicy
+	materialize     bool
+	license         License
+	header          string
+	goMod           GoModOptions
+	modules         []goModule
+	warnMissing     bool
+	packages        []string
+	enable          []string
+	disable         []string
+	verify          bool
+	offline         bool
+	portablePaths   bool
+	policies        []policy.Policy
+	untrusted       bool
+
+	// items maps the paths of files generated per item of a list variable to their item
+	items map[string]string
 }
 
-// NewGenerator creates a new generator instance
-func NewGenerator(schemaFile, outputDir, projectName, githubRepo string) (*Generator, error) {
-	// Read and parse schema file
-	data, err := os.ReadFile(schemaFile)
+// Result describes what a generation run wrote to disk
+type Result struct {
+	OutputDir    string           `json:"output_dir"`
+	Files        []string         `json:"files"`
+	EnvFiles     []string         `json:"env_files,omitempty"`
+	Hooks        []hooks.Result   `json:"hooks,omitempty"`
+	FileCount    int              `json:"file_count"`
+	Templated    int              `json:"templated"`
+	Created      int              `json:"created"`
+	Updated      int              `json:"updated"`
+	Unchanged    int              `json:"unchanged"`
+	License      string           `json:"license,omitempty"`
+	Headers      int              `json:"headers,omitempty"`
+	GoModules    []GoModuleResult `json:"go_modules,omitempty"`
+	BytesWritten int64            `json:"bytes_written"`
+	DurationMS   int64            `json:"duration_ms"`
+
+	// MissingMappings lists the required mappings not found, when warned about instead of failing
+	MissingMappings []MissingMapping `json:"missing_mappings,omitempty"`
+	// Features lists the optional features of the schema that were generated
+	Features []string `json:"features,omitempty"`
+	// Verification reports the verify hooks, when verification was asked for and the schema has some
+	Verification *Verification `json:"verification,omitempty"`
+	// NameWarnings lists the identifiers derived from the project name or rendered as Go module
+	// paths that are not valid npm package, Go package or module names
+	NameWarnings []string `json:"name_warnings,omitempty"`
+}
+
+// NewGenerator creates a generator for the schema file at schemaFile (see NewGeneratorFromSchema)
+func NewGenerator(schemaFile string, variables core.TemplateVariables, outputDir string) (*Generator, error) {
+	// Read and parse schema file, which resolves the contents of external files
+	schema, err := core.LoadSchemaFile(schemaFile)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read schema file: %w", err)
-	}
-
-	var schema core.TemplateSchema
-	if err := json.Unmarshal(data, &schema); err != nil {
-		return nil, fmt.Errorf("failed to parse schema file: %w", err)
-	}
-
-	// Create template variables
-	variables := &core.TemplateVariables{
-		ProjectName: projectName,
-		GitHubRepo:  githubRepo,
-		Author:      "Developer", // Default value
-		Description: fmt.Sprintf("A %s application", projectName),
-	}
-
-	// Create template function map
-	funcMap := template.FuncMap{
-		"kebab": func(s string) string {
-			return strings.ToLower(strings.ReplaceAll(s, " ", "-"))
-		},
-		"snake": func(s string) string {
-			return strings.ToLower(strings.ReplaceAll(s, " ", "_"))
-		},
-		"upper": strings.ToUpper,
-		"lower": strings.ToLower,
-		"title": func(s string) string {
-			if s == "" {
-				return s
-			}
-			runes := []rune(s)
-			runes[0] = unicode.ToUpper(runes[0])
-			return string(runes)
-		},
+		return nil, err
 	}
 
-	return &Generator{
-		schema:          &schema,
-		variables:       variables,
-		outputDir:       outputDir,
-		templateFuncMap: funcMap,
-	}, nil
+	return NewGeneratorFromSchema(schema, variables, outputDir), nil
 }
 
-// Generate creates the project from the template schema
-func (g *Generator) Generate() error {
-	// Validate schema
-	if err := core.ValidateSchema(g.schema); err != nil {
-		return fmt.Errorf("invalid schema: %w", err)
+// NewGeneratorFromSchema creates a generator for an already loaded schema, rendering it with
+// variables. Variables left empty take the default declared by the schema when generating;
+// without one the author is "Developer" and the description "A <project name> application".
+// A GitHubRepo given as a URL is reduced to owner/repo.
+func NewGeneratorFromSchema(
+	schema *core.TemplateSchema, variables core.TemplateVariables, outputDir string,
+) *Generator {
+	variables.Custom = maps.Clone(variables.Custom)
+	if variables.Custom == nil {
+		variables.Custom = map[string]string{}
 	}
+	variables.GitHubRepo = core.NormalizeGitHubRepo(variables.GitHubRepo)
 
-	// Validate variables
-	if err := core.ValidateVariables(g.schema, g.variables); err != nil {
-		return fmt.Errorf("invalid variables: %w", err)
+	return &Generator{
+		schema:          schema,
+		variables:       &variables,
+		outputDir:       outputDir,
+		output:          dirOutput{dir: outputDir},
+		templateFuncMap: TemplateFuncs(),
+		logger:          logging.Discard(),
 	}
+}
 
-	// Create output directory
-	if err := os.MkdirAll(g.outputDir, 0o755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+// SetLogger sets the logger used for progress output (defaults to discarding everything)
+func (g *Generator) SetLogger(logger *slog.Logger) {
+	if logger == nil {
+		logger = logging.Discard()
 	}
+	g.logger = logger
+}
 
-	// Process each file in the schema
-	for _, fileSpec := range g.schema.Files {
-		if err := g.processFile(fileSpec); err != nil {
-			return fmt.Errorf("failed to process file %s: %w", fileSpec.Path, err)
+// SetOutput writes the generated files to out instead of the output directory
+func (g *Generator) SetOutput(out Output) {
+	g.output = out
+}
+
+// SetVariable sets a built-in template variable (ProjectName, GitHubRepo, Author or
+// Description) or a custom variable declared by the schema
+func (g *Generator) SetVariable(name, value string) error {
+	switch name {
+	case "ProjectName":
+		g.variables.ProjectName = value
+	case "GitHubRepo":
+		g.variables.GitHubRepo = core.NormalizeGitHubRepo(value)
+	case "Author":
+		g.variables.Author = value
+	case "Description":
+		g.variables.Description = value
+	default:
+		if _, declared := g.schema.Variables[name]; !declared {
+			return fmt.Errorf("unknown variable %q, the schema does not declare it", name)
 		}
+		g.variables.Custom[name] = value
 	}
-
 	return nil
 }
 
-// processFile processes a single file from the schema
-func (g *Generator) processFile(fileSpec core.FileSpec) error {
-	destPath := filepath.Join(g.outputDir, fileSpec.Path)
+// applyDefaults fills the variables left empty with the defaults declared by the schema,
+// falling back to a generic author and description
+func (g *Generator) applyDefaults() {
+	for name, variable := range g.schema.Variables {
+		if variable.Default == "" {
+			continue
+		}
+		switch name {
+		case "ProjectName", "GitHubRepo":
+			// Identify the project, never defaulted
+		case "Author":
+			if g.variables.Author == "" {
+				g.variables.Author = variable.Default
+			}
+		case "Description":
+			if g.variables.Description == "" {
+				g.variables.Description = variable.Default
+			}
+		default:
+			if g.variables.Custom[name] == "" {
+				g.variables.Custom[name] = variable.Default
+			}
+		}
+	}
 
-	// Create directory if it doesn't exist
-	if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
-		return err
+	if g.variables.Author == "" {
+		g.variables.Author = fallbackAuthor
+	}
+	if g.variables.Description == "" {
+		g.variables.Description = fmt.Sprintf("A %s application", g.variables.ProjectName)
 	}
+}
 
-	if fileSpec.Template {
-		// Process templated file
-		return g.processTemplatedFile(fileSpec, destPath)
-	} else {
-		// Copy static file
-		return g.copyStaticFile(fileSpec, destPath)
+// SetValidateOptions relaxes schema validation, e.g. to accept hand-edited schemas
+// whose hashes no longer match
+func (g *Generator) SetValidateOptions(opts core.ValidateOptions) {
+	g.validate = opts
+}
+
+// SetFileFilter restricts generation to the files matching at least one glob pattern (see
+// core.MatchGlob). A pattern naming a directory, such as ".github", selects everything below it.
+func (g *Generator) SetFileFilter(patterns []string) {
+	g.only = patterns
+}
+
+// SetMaterializeSymlinks writes copies of the files symlinks point to instead of recreating the
+// symlinks, e.g. for platforms without symlinks. Symlinks are always materialized for outputs
+// that cannot create them (see SymlinkOutput).
+func (g *Generator) SetMaterializeSymlinks(materialize bool) {
+	g.materialize = materialize
+}
+
+// SetOffline forbids network access during generation: go mod tidy only resolves modules from
+// the module cache. Hooks are commands of the schema and are not restricted.
+func (g *Generator) SetOffline(offline bool) {
+	g.offline = offline
+}
+
+// PlannedFiles returns the paths Generate writes from the schema, after the file filter and the
+// package selection
+func (g *Generator) PlannedFiles() []string {
+	paths := []string{}
+	files, err := g.selectedFiles()
+	if err != nil {
+		return paths // Reported by Generate
 	}
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+	return paths
 }
 
-// processTemplatedFile processes a file that needs template substitution
-func (g *Generator) processTemplatedFile(fileSpec core.FileSpec, destPath string) error {
-	// Decompress content if needed
-	content, err := core.DecompressContent(fileSpec.Content, fileSpec.Compressed)
+// selectedFiles returns the schema files passing the file filter and the package selection,
+// symlinks materialized when they cannot or should not be recreated and files generated per
+// item expanded
+func (g *Generator) selectedFiles() ([]core.FileSpec, error) {
+	files := g.schema.Files
+	if _, ok := g.output.(SymlinkOutput); g.materialize || !ok {
+		var err error
+		if files, err = schema.MaterializeSymlinks(files); err != nil {
+			return nil, err
+		}
+	}
+	excluded, err := g.excludedPackages()
+	if err != nil {
+		return nil, err
+	}
+	files = dropPackages(files, excluded)
+	disabled, err := g.disabledFeatures()
 	if err != nil {
-		return fmt.Errorf("failed to decompress content: %w", err)
+		return nil, err
+	}
+	files = dropFeatures(files, disabled)
+	if files, err = g.expandForEach(files); err != nil {
+		return nil, err
+	}
+	if len(g.only) > 0 {
+		files = slices.DeleteFunc(slices.Clone(files), func(file core.FileSpec) bool {
+			return !g.selects(file.Path)
+		})
 	}
+	return g.withLicense(files), nil
+}
+
+// selects reports whether the file filter selects path
+func (g *Generator) selects(path string) bool {
+	if len(g.only) == 0 {
+		return true
+	}
+	for _, pattern := range g.only {
+		if matchesDir(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesDir reports whether path matches the glob pattern or lies below a directory matching it
+func matchesDir(pattern, path string) bool {
+	pattern = strings.TrimSuffix(pattern, "/")
+	return core.MatchGlob(pattern, path) || core.MatchGlob(pattern+"/**", path)
+}
+
+// Result returns a summary of the last Generate call
+func (g *Generator) Result() *Result {
+	result := g.result
+	return &result
+}
+
+// Generate creates the project from the template schema.
+// Cancelling ctx stops generation before the next file is written.
+func (g *Generator) Generate(ctx context.Context) (err error) {
+	defer func() { g.emitResult(err) }()
+	start := time.Now()
+	g.result = Result{OutputDir: g.outputDir, Files: []string{}, License: string(g.license)}
+	defer func() {
+		g.result.DurationMS = time.Since(start).Milliseconds()
+	}()
 
-	// Apply mappings first
-	for _, mapping := range fileSpec.Mappings {
-		content = strings.ReplaceAll(content, mapping.Find, mapping.Replace)
+	// Validate schema
+	if err := core.ValidateSchemaWithOptions(g.schema, g.validate); err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
 	}
 
-	// Temporarily replace our project template variables and functions with placeholders
-	templateReplacements := map[string]string{
-		"{{.ProjectName}}":         "__PROJECT_NAME_PLACEHOLDER__",
-		"{{.GitHubRepo}}":          "__GITHUB_REPO_PLACEHOLDER__",
-		"{{.Author}}":              "__AUTHOR_PLACEHOLDER__",
-		"{{.Description}}":         "__DESCRIPTION_PLACEHOLDER__",
-		"{{.ProjectName | kebab}}": "__PROJECT_NAME_KEBAB_PLACEHOLDER__",
-		"{{.ProjectName | snake}}": "__PROJECT_NAME_SNAKE_PLACEHOLDER__",
-		"{{.ProjectName | upper}}": "__PROJECT_NAME_UPPER_PLACEHOLDER__",
-		"{{.ProjectName | lower}}": "__PROJECT_NAME_LOWER_PLACEHOLDER__",
-		"{{.ProjectName | title}}": "__PROJECT_NAME_TITLE_PLACEHOLDER__",
+	// Refuse schemas written for a newer engine or schema format
+	if err := core.CheckCompatibility(g.schema); err != nil {
+		return err
 	}
 
-	for find, replace := range templateReplacements {
-		content = strings.ReplaceAll(content, find, replace)
+	if g.schema.Deprecated {
+		g.logger.Warn("Template schema is deprecated", "schema", g.schema.Name, "replaced_by", g.schema.ReplacedBy)
 	}
 
-	// Escape all remaining Go template syntax
-	content = strings.ReplaceAll(content, "{{", "__ESCAPED_LEFT_BRACE__")
-	content = strings.ReplaceAll(content, "}}", "__ESCAPED_RIGHT_BRACE__")
+	// Validate variables, once the schema defaults are applied
+	g.applyDefaults()
+	if err := core.ValidateVariables(g.schema, g.variables); err != nil {
+		return fmt.Errorf("invalid variables: %w", err)
+	}
+	for _, warning := range core.NameWarnings(g.variables) {
+		g.warnName(warning)
+	}
+	// The Go version is written into go.mod files as is, whoever set it
+	if _, err := ParseGoVersion(g.goMod.GoVersion); err != nil {
+		return err
+	}
 
-	// Restore our project template variables
-	for find, replace := range templateReplacements {
-		content = strings.ReplaceAll(content, replace, find)
+	// Make sure the schema doesn't depend on functions this engine lacks
+	if err := checkRequiredFuncs(g.schema.RequiredFuncs, g.templateFuncMap); err != nil {
+		return err
 	}
+	g.restrictFuncs()
 
-	// Parse and execute template
-	tmpl, err := template.New("file").Funcs(g.templateFuncMap).Parse(content)
+	// Resolve env values up front so missing or example secrets fail before anything is written
+	envFiles, err := g.prepareEnvFiles()
 	if err != nil {
-		return fmt.Errorf("failed to parse template: %w", err)
+		return err
 	}
 
-	// Execute template to buffer first
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, g.variables); err != nil {
-		return fmt.Errorf("failed to execute template: %w", err)
+	if err := g.checkHookPolicy(); err != nil {
+		return err
 	}
 
-	// Restore escaped Go template syntax
-	result := buf.String()
-	result = strings.ReplaceAll(result, "__ESCAPED_LEFT_BRACE__", "{{")
-	result = strings.ReplaceAll(result, "__ESCAPED_RIGHT_BRACE__", "}}")
-
-	// Create destination file and write the final content
-	file, err := os.Create(destPath)
+	files, err := g.selectedFiles()
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+	if len(files) == 0 && len(g.only) > 0 {
+		return fmt.Errorf("no schema files match %s", strings.Join(g.only, ", "))
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no schema files to generate: the enabled features, packages and for_each items select none")
+	}
+	if g.result.Features, err = g.EnabledFeatures(); err != nil {
+		return err
+	}
+	if err := g.enforcePolicies(ctx); err != nil {
+		return err
+	}
+	if err := g.renderLicense(files); err != nil {
+		return err
+	}
+	if err := g.prepareGoModules(); err != nil {
+		return err
+	}
 
-	if _, err := file.WriteString(result); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+	// Report every path that cannot be written at once, rather than failing halfway through
+	paths := make([]string, 0, len(files)+len(envFiles))
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+	for _, file := range envFiles {
+		paths = append(paths, file.path)
+	}
+	if err := g.checkPaths(paths); err != nil {
+		return err
 	}
 
-	return nil
+	// Create output directory, even for schemas whose files all live in subdirectories
+	if dir, ok := g.output.(dirOutput); ok {
+		if err := os.MkdirAll(dir.dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+
+	if err := g.runHooks(ctx, core.HookPreGenerate); err != nil {
+		return err
+	}
+
+	// Process each file in the schema
+	for i, fileSpec := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		g.logger.Debug("Writing file", "path", fileSpec.Path, "template", fileSpec.Template)
+		written, state, err := g.processFile(fileSpec)
+		if err != nil {
+			return fmt.Errorf("failed to process file %s: %w", fileSpec.Path, err)
+		}
+		g.result.count(state)
+
+		g.result.Files = append(g.result.Files, fileSpec.Path)
+		g.result.FileCount++
+		if state != FileUnchanged {
+			g.result.BytesWritten += int64(written)
+		}
+		if fileSpec.Template {
+			g.result.Templated++
+		}
+		g.emit(Event{
+			Type: EventFile, Path: fileSpec.Path, Bytes: written, Template: fileSpec.Template, State: state,
+			Index: i + 1, Total: len(files),
+		})
+	}
+
+	if err := g.writeEnvFiles(envFiles); err != nil {
+		return err
+	}
+
+	if err := g.tidyGoModules(ctx); err != nil {
+		return err
+	}
+
+	if err := g.runHooks(ctx, core.HookPostGenerate); err != nil {
+		return err
+	}
+
+	return g.verifyProject(ctx)
 }
 
-// copyStaticFile copies a static file that doesn't need templating
-func (g *Generator) copyStaticFile(fileSpec core.FileSpec, destPath string) error {
+// warnName logs and records a derived name that is not a valid identifier
+func (g *Generator) warnName(warning string) {
+	g.logger.Warn("Invalid derived name", "warning", warning)
+	g.result.NameWarnings = append(g.result.NameWarnings, warning)
+}
+
+// processFile processes a single file from the schema and returns its size and what writing it did
+func (g *Generator) processFile(fileSpec core.FileSpec) (int, FileState, error) {
+	if fileSpec.IsSymlink() {
+		state, err := g.writeSymlink(fileSpec.Path, fileSpec.Target)
+		if err != nil {
+			return 0, "", fmt.Errorf("failed to create symlink: %w", err)
+		}
+		return 0, state, nil
+	}
+	if g.streams(fileSpec) {
+		return g.streamFile(fileSpec)
+	}
+
 	// Decompress content if needed
-	content, err := core.DecompressContent(fileSpec.Content, fileSpec.Compressed)
+	content, err := core.ResolveContent(g.schema, fileSpec)
 	if err != nil {
-		return fmt.Errorf("failed to decompress content: %w", err)
+		return 0, "", fmt.Errorf("failed to decompress content: %w", err)
+	}
+
+	if content, err = g.removeDependencies(fileSpec, content); err != nil {
+		return 0, "", err
+	}
+	if fileSpec.Template {
+		// Process templated file
+		if content, err = g.renderFile(fileSpec, content); err != nil {
+			return 0, "", err
+		}
+	}
+	if content, err = g.patchFile(fileSpec, content); err != nil {
+		return 0, "", err
+	}
+	if fileSpec.Encoding == "" {
+		content = g.rewriteGoModules(fileSpec.Path, content)
+	}
+	if headed, ok := g.addHeader(fileSpec, content); ok {
+		content = headed
+		g.result.Headers++
 	}
 
-	// With go-fsck pattern, all content is embedded in the schema
-	file, err := os.Create(destPath)
+	state, err := g.writeFile(fileSpec.Path, []byte(content))
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return len(content), state, nil
+}
+
+// renderFile applies mappings and template substitution to the content of a templated file
+func (g *Generator) renderFile(fileSpec core.FileSpec, content string) (string, error) {
+	var buf strings.Builder
+	if err := g.renderFileTo(&buf, fileSpec, content); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// renderFileTo renders a templated file like renderFile, writing the output to w
+func (g *Generator) renderFileTo(w io.Writer, fileSpec core.FileSpec, content string) error {
+	left, right := core.EffectiveDelims(g.schema, fileSpec)
+
+	// Apply mappings first, converting their {{ }} replacements to the file's delimiters
+	mappings := make([]core.Mapping, len(fileSpec.Mappings))
+	for i, mapping := range fileSpec.Mappings {
+		mapping.Replace = convertDelims(mapping.Replace, left, right)
+		mappings[i] = mapping
+	}
+
+	content, missing, err := core.ApplyMappingsVerified(content, mappings)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+	if err := g.reportMissingMappings(fileSpec.Path, missing); err != nil {
+		return err
+	}
 
-	// Write the embedded content directly
-	_, err = file.WriteString(content)
-	return err
+	data := g.templateData()
+	if fileSpec.ForEach != "" {
+		data = g.itemData(g.items[fileSpec.Path])
+	}
+	return newRenderer(g.templateFuncMap, data).renderTo(w, content, left, right)
 }
 
-// PrintSummary prints a summary of what was generated
-func (g *Generator) PrintSummary() {
-	fmt.Printf("Project generated successfully!\n")
-	fmt.Printf("Location: %s\n", g.outputDir)
-	fmt.Printf("Project Name: %s\n", g.variables.ProjectName)
-	fmt.Printf("GitHub Repo: %s\n", g.variables.GitHubRepo)
-	fmt.Printf("Files processed: %d\n", len(g.schema.Files))
+// convertDelims rewrites a mapping replacement written with {{ }} to use the given delimiters
+func convertDelims(replacement, left, right string) string {
+	if left == core.DefaultLeftDelim && right == core.DefaultRightDelim {
+		return replacement
+	}
+	replacement = strings.ReplaceAll(replacement, core.DefaultLeftDelim, left)
+	return strings.ReplaceAll(replacement, core.DefaultRightDelim, right)
+}
+
+// templateData returns the variables visible to templated files, custom ones included
+func (g *Generator) templateData() map[string]any {
+	data := map[string]any{}
+	for name, value := range g.variables.Custom {
+		data[name] = value
+	}
+	// Lists and maps are ranged over, empty ones included; their values are validated with the
+	// variables
+	for name, variable := range g.schema.Variables {
+		if variable.Type != core.VariableTypeList && variable.Type != core.VariableTypeMap {
+			continue
+		}
+		if parsed, err := variable.ParseValue(g.variables.Custom[name]); err == nil {
+			data[name] = parsed
+		}
+	}
+	data["ProjectName"] = g.variables.ProjectName
+	data["GitHubRepo"] = g.variables.GitHubRepo
+	data["Author"] = g.variables.Author
+	data["Description"] = g.variables.Description
+	for name, value := range core.DeriveVariables(g.variables) {
+		data[name] = value
+	}
+	return data
+}
 
-	templatedCount := 0
-	for _, file := range g.schema.Files {
-		if file.Template {
-			templatedCount++
+// PrintSummary logs a summary of what was generated
+func (g *Generator) PrintSummary() {
+	g.logger.Info("Project generated successfully",
+		"location", g.outputDir,
+		"project_name", g.variables.ProjectName,
+		"github_repo", g.variables.GitHubRepo,
+		"files", g.result.FileCount,
+		"templated", g.result.Templated,
+		"created", g.result.Created,
+		"updated", g.result.Updated,
+		"unchanged", g.result.Unchanged)
+
+	if verification := g.result.Verification; verification != nil {
+		if verification.Passed {
+			g.logger.Info("Verification passed", "checks", len(verification.Checks))
+			return
+		}
+		for _, check := range verification.Failed() {
+			label := check.Name
+			if label == "" {
+				label = check.Command
+			}
+			g.logger.Error("Verification failed", "check", label, "exit_code", check.ExitCode, "output", check.Output)
 		}
 	}
-	fmt.Printf("Templated files: %d\n", templatedCount)
 }
@@ -0,0 +1,847 @@
+package generate
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/logging"
+	"github.com/acheevo/template-engine/internal/policy"
+)
+
+// testVariables are the variables the tests generate with
+var testVariables = core.TemplateVariables{ProjectName: "My App", GitHubRepo: "user/my-app"}
+
+// generateSchema writes schema to a temp file, generates it and returns the output directory.
+// configure runs on the generator before generation.
+func generateSchema(t *testing.T, schema *core.TemplateSchema, configure ...func(*Generator)) string {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	schemaFile := filepath.Join(tempDir, "schema.json")
+	if err := core.SaveSchemaFile(schema, schemaFile); err != nil {
+		t.Fatal(err)
+	}
+
+	outputDir := filepath.Join(tempDir, "output")
+	generator, err := NewGenerator(schemaFile, testVariables, outputDir)
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+	for _, fn := range configure {
+		fn(generator)
+	}
+	if err := generator.Generate(context.Background()); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	return outputDir
+}
+
+// readOutput reads a generated file
+func readOutput(t *testing.T, outputDir, path string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join(outputDir, path))
+	if err != nil {
+		t.Fatalf("Failed to read generated %s: %v", path, err)
+	}
+	return string(content)
+}
+
+// testSchema returns a minimal valid schema containing files
+func testSchema(files ...core.FileSpec) *core.TemplateSchema {
+	return &core.TemplateSchema{
+		Name:    "test-template",
+		Type:    "frontend",
+		Version: "1.0.0",
+		Variables: map[string]core.Variable{
+			"ProjectName": {Type: "string", Required: true},
+			"GitHubRepo":  {Type: "string", Required: true},
+		},
+		Files: files,
+	}
+}
+
+func TestGenerateCustomDelims(t *testing.T) {
+	schema := testSchema(
+		core.FileSpec{
+			Path:     "src/App.vue",
+			Template: true,
+			Content:  "<h1>Frontend Template</h1>\n<p>{{ message }}</p>\n<p>[[.GitHubRepo]]</p>",
+			Mappings: []core.Mapping{{Find: "Frontend Template", Replace: "{{.ProjectName | upper}}"}},
+			Delims:   &core.Delims{Left: "[[", Right: "]]"},
+		},
+		core.FileSpec{
+			Path:     "chart/templates/deployment.yaml",
+			Template: true,
+			Content:  "name: {{ .Release.Name }}\napp: <<.ProjectName | kebab>>",
+		},
+		core.FileSpec{
+			Path:      "chart/Chart.yaml",
+			Content:   "name: chart-template\n",
+			YAMLPatch: []core.Patch{{Path: "name", Value: "{{.ProjectName | kebab}}"}},
+		},
+	)
+	schema.Delims = &core.Delims{Left: "<<", Right: ">>"}
+
+	outputDir := generateSchema(t, schema)
+
+	expectedVue := "<h1>MY APP</h1>\n<p>{{ message }}</p>\n<p>user/my-app</p>"
+	if got := readOutput(t, outputDir, "src/App.vue"); got != expectedVue {
+		t.Errorf("App.vue mismatch.\nExpected: %q\nGot: %q", expectedVue, got)
+	}
+
+	expectedChart := "name: {{ .Release.Name }}\napp: my-app"
+	if got := readOutput(t, outputDir, "chart/templates/deployment.yaml"); got != expectedChart {
+		t.Errorf("deployment.yaml mismatch.\nExpected: %q\nGot: %q", expectedChart, got)
+	}
+
+	// Patch values are written with {{ }} like mapping replacements
+	if got := readOutput(t, outputDir, "chart/Chart.yaml"); got != "name: my-app\n" {
+		t.Errorf("Chart.yaml = %q, want the patched name", got)
+	}
+}
+
+func TestGenerateDefaultDelimsEscapesFileSyntax(t *testing.T) {
+	outputDir := generateSchema(t, testSchema(core.FileSpec{
+		Path:     "README.md",
+		Template: true,
+		Content:  "# {{.ProjectName}}\nUse {{ .Values.image }} and {{.ProjectName | kebab}}",
+	}))
+
+	expected := "# My App\nUse {{ .Values.image }} and my-app"
+	if got := readOutput(t, outputDir, "README.md"); got != expected {
+		t.Errorf("README.md mismatch.\nExpected: %q\nGot: %q", expected, got)
+	}
+}
+
+func TestGenerateDerivedVariables(t *testing.T) {
+	outputDir := generateSchema(t, testSchema(core.FileSpec{
+		Path:     "go.mod",
+		Template: true,
+		Content:  "module github.com/{{.RepoOwner}}/{{.RepoName}} // {{.ProjectNameKebab}} {{.ProjectNamePascal}}",
+	}))
+
+	expected := "module github.com/user/my-app // my-app MyApp"
+	if got := readOutput(t, outputDir, "go.mod"); got != expected {
+		t.Errorf("go.mod mismatch.\nExpected: %q\nGot: %q", expected, got)
+	}
+
+	// A repository URL is reduced to owner/repo
+	outputDir = generateSchema(t, testSchema(core.FileSpec{
+		Path: "README.md", Template: true, Content: "{{.GitHubRepo}} {{.RepoOwner}} {{.RepoName}}",
+	}), func(g *Generator) {
+		if err := g.SetVariable("GitHubRepo", "https://github.com/acme/billing-api.git"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if got := readOutput(t, outputDir, "README.md"); got != "acme/billing-api acme billing-api" {
+		t.Errorf("README.md = %q, want the repository parsed from its URL", got)
+	}
+}
+
+func TestGenerateFileFilter(t *testing.T) {
+	schema := testSchema(
+		core.FileSpec{Path: "README.md", Content: "readme"},
+		core.FileSpec{Path: ".github/workflows/ci.yml", Content: "ci"},
+		core.FileSpec{Path: "docker/Dockerfile", Content: "FROM scratch"},
+		core.FileSpec{Path: "src/main.go", Content: "package main"},
+	)
+
+	outputDir := generateSchema(t, schema, func(g *Generator) {
+		g.SetFileFilter([]string{".github", "**/Dockerfile"})
+	})
+
+	for _, path := range []string{".github/workflows/ci.yml", "docker/Dockerfile"} {
+		readOutput(t, outputDir, path)
+	}
+	for _, path := range []string{"README.md", "src/main.go"} {
+		if _, err := os.Stat(filepath.Join(outputDir, path)); !os.IsNotExist(err) {
+			t.Errorf("%s should not be generated", path)
+		}
+	}
+
+	generator := NewGeneratorFromSchema(schema, testVariables, t.TempDir())
+	generator.SetFileFilter([]string{"*.txt"})
+	if err := generator.Generate(context.Background()); err == nil {
+		t.Error("Generate() should fail when no file matches the filter")
+	}
+}
+
+func TestGeneratePackages(t *testing.T) {
+	web := `{"name": "@acme/web", "dependencies": {"@acme/ui": "workspace:*", "@acme/api.client": "workspace:*"}}`
+	schema := testSchema(
+		core.FileSpec{Path: "package.json", Content: `{"name": "acme", "devDependencies": {"@acme/ui": "*"}}`},
+		core.FileSpec{
+			Path: "apps/web/package.json", Content: web, Template: true,
+			Mappings: []core.Mapping{{Find: "@acme/", Replace: "@{{.ProjectNameKebab}}/"}},
+		},
+		core.FileSpec{Path: "apps/web/src/main.ts", Content: "import '@acme/ui'"},
+		core.FileSpec{Path: "packages/ui/package.json", Content: `{"name": "@acme/ui"}`},
+		core.FileSpec{Path: "packages/api-client/package.json", Content: `{"name": "@acme/api.client"}`},
+	)
+	schema.Packages = []core.WorkspacePackage{
+		{Name: "@acme/web", Dir: "apps/web"},
+		{Name: "@acme/ui", Dir: "packages/ui"},
+		{Name: "@acme/api.client", Dir: "packages/api-client"},
+	}
+
+	outputDir := generateSchema(t, schema, func(g *Generator) {
+		g.SetPackages([]string{"apps/web", "@acme/ui"})
+	})
+
+	for _, path := range []string{"package.json", "apps/web/src/main.ts", "packages/ui/package.json"} {
+		readOutput(t, outputDir, path)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "packages/api-client")); !os.IsNotExist(err) {
+		t.Error("packages/api-client should not be generated")
+	}
+	if got := readOutput(t, outputDir, "apps/web/package.json"); strings.Contains(got, "api.client") ||
+		!strings.Contains(got, `"@my-app/ui"`) {
+		t.Errorf("apps/web/package.json = %s, want only the dependency on the ui package", got)
+	}
+
+	generator := NewGeneratorFromSchema(schema, testVariables, t.TempDir())
+	generator.SetPackages([]string{"@acme/docs"})
+	if err := generator.Generate(context.Background()); err == nil {
+		t.Error("Generate() should fail for an unknown package")
+	}
+}
+
+func TestGenerateHooks(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	schema := testSchema(core.FileSpec{Path: "README.md", Content: "readme"})
+	schema.Hooks = core.Hooks{
+		{Stage: core.HookPostGenerate, Command: `cat README.md > post.txt; echo " $TE_VAR_ProjectNameKebab" >> post.txt`},
+		{Stage: core.HookPreGenerate, Command: "test -e README.md || touch pre.txt"},
+		{Stage: core.HookPostUpdate, Command: "touch update.txt"},
+	}
+
+	outputDir := generateSchema(t, schema, func(g *Generator) {
+		g.SetHookOptions(HookOptions{Enabled: true})
+	})
+
+	readOutput(t, outputDir, "pre.txt") // Only written when the hook ran before README.md
+	if got := readOutput(t, outputDir, "post.txt"); got != "readme my-app\n" {
+		t.Errorf("post_generate hook wrote %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "update.txt")); !os.IsNotExist(err) {
+		t.Error("post_update hook should not run on generate")
+	}
+
+	outputDir = generateSchema(t, schema)
+	if _, err := os.Stat(filepath.Join(outputDir, "post.txt")); !os.IsNotExist(err) {
+		t.Error("hooks should not run unless enabled")
+	}
+}
+
+func TestGenerateRemoteSchemaHooks(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	schema := testSchema(core.FileSpec{Path: "README.md", Content: "readme"})
+	schema.Hooks = core.Hooks{{Stage: core.HookPostGenerate, Command: "touch hook.txt"}}
+	schema.Origin = "https://templates.example.com/schemas/frontend"
+
+	outputDir := generateSchema(t, schema, func(g *Generator) {
+		g.SetHookOptions(HookOptions{Enabled: true})
+	})
+	if _, err := os.Stat(filepath.Join(outputDir, "hook.txt")); !os.IsNotExist(err) {
+		t.Error("hooks of a remote schema should not run unless allowed")
+	}
+
+	outputDir = generateSchema(t, schema, func(g *Generator) {
+		g.SetHookOptions(HookOptions{Enabled: true, AllowRemote: true})
+	})
+	readOutput(t, outputDir, "hook.txt")
+
+	generator := NewGeneratorFromSchema(schema, testVariables, filepath.Join(t.TempDir(), "output"))
+	generator.SetHookOptions(HookOptions{Enabled: true, AllowRemote: true, AllowedBinaries: []string{"go"}})
+	if err := generator.Generate(context.Background()); err == nil {
+		t.Error("Generate() should fail when a hook breaks the hook policy")
+	}
+}
+
+func TestRunPartialIntoExistingProject(t *testing.T) {
+	tempDir := t.TempDir()
+	schemaFile := filepath.Join(tempDir, "schema.json")
+	schema := testSchema(
+		core.FileSpec{Path: "README.md", Content: "readme"},
+		core.FileSpec{Path: "Makefile", Content: "build:"},
+	)
+	if err := core.SaveSchemaFile(schema, schemaFile); err != nil {
+		t.Fatal(err)
+	}
+
+	projectDir := filepath.Join(tempDir, "project")
+	if err := os.MkdirAll(projectDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, "README.md"), []byte("mine"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	params := Params{
+		TemplateFile: schemaFile, OutputDir: projectDir, ProjectName: "My App", GitHubRepo: "user/my-app",
+		Only: []string{"Makefile"},
+	}
+	if _, err := RunWithParams(context.Background(), logging.Discard(), params); err != nil {
+		t.Fatalf("RunWithParams() error = %v", err)
+	}
+	if got := readOutput(t, projectDir, "Makefile"); got != "build:" {
+		t.Errorf("Makefile = %q", got)
+	}
+
+	// Selected files that already exist are never overwritten
+	params.Only = []string{"README.md"}
+	if _, err := RunWithParams(context.Background(), logging.Discard(), params); err == nil {
+		t.Error("RunWithParams() should refuse to overwrite README.md")
+	}
+	if got := readOutput(t, projectDir, "README.md"); got != "mine" {
+		t.Errorf("README.md was overwritten: %q", got)
+	}
+}
+
+func TestValidateDelims(t *testing.T) {
+	schema := testSchema(core.FileSpec{
+		Path:    "a.txt",
+		Content: "a",
+		Delims:  &core.Delims{Left: "[[", Right: ""},
+	})
+
+	if err := core.ValidateSchema(schema); err == nil {
+		t.Error("Expected error for incomplete delimiters")
+	}
+}
+
+func TestGenerateVariableDefaults(t *testing.T) {
+	schema := &core.TemplateSchema{
+		Name:    "test",
+		Type:    "test",
+		Version: "1.0.0",
+		Variables: map[string]core.Variable{
+			"Author": {Type: "string", Default: "Platform Team"},
+			"Team":   {Type: "string", Default: "core"},
+			"Region": {Type: "string", Required: true},
+		},
+		Files: []core.FileSpec{{
+			Path:     "README.md",
+			Template: true,
+			Content:  "{{.Author}} / {{.Description}} / {{.Team}} / {{.Region}}",
+		}},
+	}
+
+	outputDir := generateSchema(t, schema, func(g *Generator) {
+		if err := g.SetVariable("Region", "eu-west-1"); err != nil {
+			t.Fatalf("SetVariable(Region) error = %v", err)
+		}
+	})
+	want := "Platform Team / A My App application / core / eu-west-1"
+	if got := readOutput(t, outputDir, "README.md"); got != want {
+		t.Errorf("README.md = %q, want %q", got, want)
+	}
+
+	generator := NewGeneratorFromSchema(schema, testVariables, filepath.Join(t.TempDir(), "output"))
+	if err := generator.SetVariable("Unknown", "value"); err == nil {
+		t.Error("SetVariable() of an undeclared variable should fail")
+	}
+	if err := generator.Generate(context.Background()); err == nil {
+		t.Error("Generate() without the required Region should fail")
+	}
+
+	variables := testVariables
+	variables.Custom = map[string]string{"Region": "us-east-1", "Team": "payments"}
+	generator = NewGeneratorFromSchema(schema, variables, filepath.Join(t.TempDir(), "output"))
+	if err := generator.Generate(context.Background()); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if got := generator.Variables()["Team"]; got != "payments" {
+		t.Errorf("Team = %q, want payments", got)
+	}
+}
+
+func TestGenerateMerge(t *testing.T) {
+	schema := &core.TemplateSchema{
+		Name:      "test",
+		Type:      "test",
+		Version:   "1.0.0",
+		Variables: map[string]core.Variable{},
+		Files: []core.FileSpec{
+			{Path: "README.md", Template: true, Content: "# {{.ProjectName}}"},
+			{Path: "main.go", Content: "package main"},
+			{Path: "docs/guide.md", Content: "guide"},
+		},
+	}
+	outputDir := filepath.Join(t.TempDir(), "output")
+
+	generate := func() *Result {
+		t.Helper()
+		generator := NewGeneratorFromSchema(schema, testVariables, outputDir)
+		generator.SetOverwritePolicy(OverwriteMerge)
+		if err := generator.Generate(context.Background()); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		return generator.Result()
+	}
+
+	if result := generate(); result.Created != 3 || result.Updated != 0 || result.Unchanged != 0 {
+		t.Errorf("first run created/updated/unchanged = %d/%d/%d, want 3/0/0",
+			result.Created, result.Updated, result.Unchanged)
+	}
+
+	if err := os.WriteFile(filepath.Join(outputDir, "main.go"), []byte("package edited"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(outputDir, "docs", "guide.md")); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(filepath.Join(outputDir, "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := generate()
+	if result.Created != 1 || result.Updated != 1 || result.Unchanged != 1 {
+		t.Errorf("second run created/updated/unchanged = %d/%d/%d, want 1/1/1",
+			result.Created, result.Updated, result.Unchanged)
+	}
+	if got := readOutput(t, outputDir, "main.go"); got != "package main" {
+		t.Errorf("main.go = %q, want it restored", got)
+	}
+	if after, err := os.Stat(filepath.Join(outputDir, "README.md")); err != nil || !after.ModTime().Equal(info.ModTime()) {
+		t.Error("unchanged README.md was rewritten")
+	}
+}
+
+func TestGenerateSymlinks(t *testing.T) {
+	schema := testSchema(
+		core.FileSpec{Path: "config/eslint.json", Content: "{}"},
+		core.FileSpec{Path: "pkg/web/eslint.json", Type: core.FileTypeSymlink, Target: "../../config/eslint.json"},
+		core.FileSpec{Path: "pkg/api/config", Type: core.FileTypeSymlink, Target: "../../config"},
+	)
+
+	outputDir := filepath.Join(t.TempDir(), "output")
+	generator := NewGeneratorFromSchema(schema, testVariables, outputDir)
+	generator.SetOverwritePolicy(OverwriteMerge)
+	if err := generator.Generate(context.Background()); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	for path, want := range map[string]string{
+		"pkg/web/eslint.json": "../../config/eslint.json",
+		"pkg/api/config":      "../../config",
+	} {
+		target, err := os.Readlink(filepath.Join(outputDir, filepath.FromSlash(path)))
+		if err != nil || filepath.ToSlash(target) != want {
+			t.Errorf("Readlink(%s) = %q, %v, want %q", path, target, err, want)
+		}
+	}
+	if got := readOutput(t, outputDir, "pkg/api/config/eslint.json"); got != "{}" {
+		t.Errorf("pkg/api/config/eslint.json = %q through the symlink", got)
+	}
+
+	if err := generator.Generate(context.Background()); err != nil {
+		t.Fatalf("second Generate() error = %v", err)
+	}
+	if result := generator.Result(); result.Unchanged != 3 {
+		t.Errorf("second run unchanged = %d, want 3", result.Unchanged)
+	}
+
+	materializedDir := filepath.Join(t.TempDir(), "materialized")
+	generator = NewGeneratorFromSchema(schema, testVariables, materializedDir)
+	generator.SetMaterializeSymlinks(true)
+	if err := generator.Generate(context.Background()); err != nil {
+		t.Fatalf("Generate() materializing error = %v", err)
+	}
+	for _, path := range []string{"pkg/web/eslint.json", "pkg/api/config/eslint.json"} {
+		info, err := os.Lstat(filepath.Join(materializedDir, filepath.FromSlash(path)))
+		if err != nil || !info.Mode().IsRegular() {
+			t.Errorf("%s = %v, %v, want a regular file", path, info, err)
+		}
+	}
+}
+
+func TestGenerateRequiredMappings(t *testing.T) {
+	schema := testSchema(core.FileSpec{
+		Path:     "README.md",
+		Template: true,
+		Content:  "# My App\n",
+		Mappings: []core.Mapping{
+			{Find: "acme-api", Replace: "{{.ProjectName}}", Required: true},
+			{Find: "optional", Replace: "{{.ProjectName}}"},
+		},
+	})
+	tempDir := t.TempDir()
+	schemaFile := filepath.Join(tempDir, "schema.json")
+	if err := core.SaveSchemaFile(schema, schemaFile); err != nil {
+		t.Fatal(err)
+	}
+
+	generator, err := NewGenerator(schemaFile, testVariables, filepath.Join(tempDir, "strict"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := generator.Generate(context.Background()); err == nil {
+		t.Error("Generate() with a missing required mapping should fail")
+	}
+
+	generator, err = NewGenerator(schemaFile, testVariables, filepath.Join(tempDir, "warn"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	generator.SetWarnMissingMappings(true)
+	if err := generator.Generate(context.Background()); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	missing := generator.Result().MissingMappings
+	if len(missing) != 1 || missing[0] != (MissingMapping{Path: "README.md", Find: "acme-api"}) {
+		t.Errorf("MissingMappings = %+v", missing)
+	}
+}
+
+func TestGenerateForEach(t *testing.T) {
+	schema := testSchema(
+		core.FileSpec{
+			Path:     "internal/{{.item}}/handler.go",
+			Template: true,
+			Content:  "package {{.item}}\n\n// Handler serves {{.item | title}} for {{.ProjectName}}\n",
+			ForEach:  "Services",
+		},
+		core.FileSpec{Path: "README.md", Template: true, Content: "{{range .Services}}- {{.}}\n{{end}}"},
+	)
+	schema.Variables["Services"] = core.Variable{Type: core.VariableTypeList, Default: "auth, billing"}
+
+	outputDir := generateSchema(t, schema)
+	for _, service := range []string{"auth", "billing"} {
+		want := "package " + service + "\n\n// Handler serves " + strings.ToUpper(service[:1]) + service[1:] +
+			" for My App\n"
+		if got := readOutput(t, outputDir, "internal/"+service+"/handler.go"); got != want {
+			t.Errorf("%s handler = %q, want %q", service, got, want)
+		}
+	}
+	if got := readOutput(t, outputDir, "README.md"); got != "- auth\n- billing\n" {
+		t.Errorf("README.md = %q", got)
+	}
+
+	variables := testVariables
+	variables.Custom = map[string]string{"Services": "../../escape"}
+	generator := NewGeneratorFromSchema(schema, variables, filepath.Join(t.TempDir(), "output"))
+	if err := generator.Generate(context.Background()); err == nil {
+		t.Error("Generate() should refuse items rendering paths outside the project")
+	}
+}
+
+func TestGenerateFeatures(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	schema := testSchema(
+		core.FileSpec{Path: "README.md", Content: "readme"},
+		core.FileSpec{Path: "internal/auth/auth.go", Content: "package auth"},
+		core.FileSpec{Path: "migrations/001_init.sql", Content: "CREATE TABLE users ();"},
+		core.FileSpec{Path: ".github/workflows/ci.yml", Content: "ci"},
+	)
+	schema.Hooks = core.Hooks{{Name: "migrate", Stage: core.HookPostGenerate, Command: "touch migrated.txt"}}
+	schema.Features = []core.Feature{
+		{Name: "auth", Default: true, Files: []string{"internal/auth"}},
+		{Name: "postgres", Files: []string{"migrations/*.sql"}, Hooks: []string{"migrate"}},
+		{Name: "ci", Default: true, Files: []string{".github/**"}},
+	}
+
+	var generator *Generator
+	outputDir := generateSchema(t, schema, func(g *Generator) {
+		g.SetHookOptions(HookOptions{Enabled: true})
+		g.SetFeatures([]string{"postgres"}, []string{"ci"})
+		generator = g
+	})
+
+	for _, path := range []string{"README.md", "internal/auth/auth.go", "migrations/001_init.sql", "migrated.txt"} {
+		readOutput(t, outputDir, path)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, ".github/workflows/ci.yml")); !os.IsNotExist(err) {
+		t.Error("files of the disabled ci feature should not be generated")
+	}
+	if got := strings.Join(generator.Result().Features, ","); got != "auth,postgres" {
+		t.Errorf("Result().Features = %s, want auth,postgres", got)
+	}
+
+	outputDir = generateSchema(t, schema, func(g *Generator) { g.SetHookOptions(HookOptions{Enabled: true}) })
+	for _, path := range []string{"migrations/001_init.sql", "migrated.txt"} {
+		if _, err := os.Stat(filepath.Join(outputDir, path)); !os.IsNotExist(err) {
+			t.Errorf("%s of the postgres feature, disabled by default, should not be generated", path)
+		}
+	}
+
+	for _, features := range [][2][]string{{{"redis"}, nil}, {{"auth"}, {"auth"}}} {
+		generator := NewGeneratorFromSchema(schema, testVariables, filepath.Join(t.TempDir(), "output"))
+		generator.SetFeatures(features[0], features[1])
+		if err := generator.Generate(context.Background()); err == nil {
+			t.Errorf("Generate() with --enable %v --disable %v should fail", features[0], features[1])
+		}
+	}
+}
+
+// hookStubs stand in for the commands the hooks of the shipped examples run, failing like the
+// real ones would when the generated project cannot satisfy them
+var hookStubs = map[string]string{
+	"go": `case "$*" in
+"mod tidy") test -f go.mod ;;
+"build ./...") test -f go.mod && find . -name '*.go' | grep -q . ;;
+"build") ls ./*.go >/dev/null 2>&1 || { echo "no Go files in $PWD" >&2; exit 1; } ;;
+*) echo "unexpected go $*" >&2; exit 1 ;;
+esac`,
+	"npm": `test "$*" = install && test -f package.json`,
+}
+
+func TestGenerateExamplesWithHooks(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	bin := t.TempDir()
+	for name, script := range hookStubs {
+		if err := os.WriteFile(filepath.Join(bin, name), []byte("#!/bin/sh\n"+script+"\n"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	examples, err := filepath.Glob("../../examples/templates/*.json")
+	if err != nil || len(examples) == 0 {
+		t.Fatalf("no shipped examples found: %v", err)
+	}
+	for _, example := range examples {
+		t.Run(filepath.Base(example), func(t *testing.T) {
+			generator, err := NewGenerator(example, testVariables, filepath.Join(t.TempDir(), "output"))
+			if err != nil {
+				t.Fatalf("NewGenerator() error = %v", err)
+			}
+			generator.SetHookOptions(HookOptions{Enabled: true})
+			if err := generator.Generate(context.Background()); err != nil {
+				t.Fatalf("Generate() with hooks error = %v", err)
+			}
+			if len(generator.Result().Hooks) == 0 {
+				t.Error("Generate() ran no hooks, want those the example ships")
+			}
+		})
+	}
+}
+
+func TestGenerateNothingSelected(t *testing.T) {
+	schema := testSchema(core.FileSpec{Path: "Dockerfile", Content: "FROM scratch"})
+	schema.Features = []core.Feature{{Name: "docker", Files: []string{"Dockerfile"}}}
+
+	generator := NewGeneratorFromSchema(schema, testVariables, filepath.Join(t.TempDir(), "output"))
+	err := generator.Generate(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "enabled features") {
+		t.Errorf("Generate() with every file disabled error = %v, want the features blamed", err)
+	}
+
+	generator = NewGeneratorFromSchema(schema, testVariables, filepath.Join(t.TempDir(), "output"))
+	generator.SetFeatures([]string{"docker"}, nil)
+	generator.SetFileFilter([]string{"docs/**"})
+	err = generator.Generate(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "no schema files match docs/**") {
+		t.Errorf("Generate() with an unmatched --only error = %v, want the patterns named", err)
+	}
+}
+
+func TestGenerateVerify(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	schema := testSchema(core.FileSpec{Path: "README.md", Content: "readme"})
+	schema.Hooks = core.Hooks{
+		{Name: "readme", Stage: core.HookVerify, Command: "test -f README.md"},
+		{Name: "build", Stage: core.HookVerify, Command: "echo missing main.go; exit 1"},
+		{Name: "lint", Stage: core.HookVerify, Command: "test -f README.md"},
+	}
+
+	var generator *Generator
+	generateSchema(t, schema, func(g *Generator) {
+		g.SetVerify(true)
+		generator = g
+	})
+	verification := generator.Result().Verification
+	if verification == nil || verification.Passed || len(verification.Checks) != 3 {
+		t.Fatalf("Result().Verification = %+v, want three checks, failed", verification)
+	}
+	if failed := verification.Failed(); len(failed) != 1 || failed[0].Name != "build" {
+		t.Errorf("Failed() = %+v, want the build check", failed)
+	}
+
+	generateSchema(t, schema, func(g *Generator) { generator = g })
+	if generator.Result().Verification != nil {
+		t.Error("verify hooks should only run when asked for")
+	}
+}
+
+func TestGenerateStreamsContent(t *testing.T) {
+	large := strings.Repeat("static line\n", 1000)
+	template := strings.Repeat("name: {{.ProjectName}}\n", 1000)
+	binary, encoding := core.EncodeContent([]byte{0x89, 'P', 'N', 'G', 0, 1, 2, 3})
+
+	var files []core.FileSpec
+	for _, codec := range core.Codecs {
+		for path, content := range map[string]string{"static.txt": large, "templated.yml": template} {
+			compressed, ok, err := core.CompressWith(content, codec)
+			if err != nil || !ok && codec != core.CodecNone {
+				t.Fatalf("CompressWith(%s) = %v, %v", codec, ok, err)
+			}
+			files = append(files, core.FileSpec{
+				Path: string(codec) + "/" + path, Content: compressed, Compressed: ok, Codec: string(codec),
+				Template: path == "templated.yml",
+			})
+		}
+	}
+	files = append(files, core.FileSpec{Path: "logo.png", Content: binary, Encoding: encoding})
+	schema := testSchema(files...)
+
+	var streamed, buffered *Generator
+	outputDir := generateSchema(t, schema, func(g *Generator) { streamed = g })
+	mergedDir := generateSchema(t, schema, func(g *Generator) {
+		g.SetOverwritePolicy(OverwriteMerge) // Merging compares the full content, never streamed
+		buffered = g
+	})
+
+	rendered := strings.Repeat("name: My App\n", 1000)
+	for _, file := range files {
+		got := readOutput(t, outputDir, file.Path)
+		if got != readOutput(t, mergedDir, file.Path) {
+			t.Errorf("streamed %s differs from the buffered output", file.Path)
+		}
+		if file.Template && got != rendered {
+			t.Errorf("%s was not rendered", file.Path)
+		}
+		if strings.HasSuffix(file.Path, "static.txt") && got != large {
+			t.Errorf("%s = %d bytes, want the static content", file.Path, len(got))
+		}
+	}
+	if got, want := streamed.Result().BytesWritten, buffered.Result().BytesWritten; got != want {
+		t.Errorf("Result().BytesWritten = %d streamed, %d buffered", got, want)
+	}
+}
+
+func TestGeneratePortablePaths(t *testing.T) {
+	schema := testSchema(
+		core.FileSpec{Path: "README.md", Content: "# App\n"},
+		core.FileSpec{Path: "docs/aux.md", Content: "aux\n"},
+		core.FileSpec{Path: "con/notes.txt", Content: "notes\n"},
+		core.FileSpec{Path: "what?.txt", Content: "?\n"},
+		core.FileSpec{Path: "trailing./file.txt", Content: "dot\n"},
+		core.FileSpec{Path: "deep/" + strings.Repeat("nested/", 40) + "file.txt", Content: "deep\n"},
+	)
+	outputDir := filepath.Join(t.TempDir(), "output")
+	generator := NewGeneratorFromSchema(schema, testVariables, outputDir)
+	generator.SetPortablePaths(true)
+
+	err := generator.Generate(context.Background())
+	var invalid *InvalidPathsError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("Generate() error = %v, want an InvalidPathsError", err)
+	}
+	var paths []string
+	for _, problem := range invalid.Problems {
+		paths = append(paths, problem.Path)
+	}
+	want := []string{"docs/aux.md", "con/notes.txt", "what?.txt", "trailing./file.txt", schema.Files[5].Path}
+	if !slices.Equal(paths, want) {
+		t.Errorf("InvalidPathsError paths = %q, want %q", paths, want)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "README.md")); !os.IsNotExist(err) {
+		t.Error("Generate() should not write anything when paths are invalid")
+	}
+
+	// Names too long for any filesystem are rejected on every OS
+	long := testSchema(core.FileSpec{Path: strings.Repeat("a", 256) + ".txt", Content: "long\n"})
+	generator = NewGeneratorFromSchema(long, testVariables, t.TempDir())
+	if err := generator.Generate(context.Background()); !errors.As(err, &invalid) {
+		t.Errorf("Generate() with a 260 byte name error = %v, want an InvalidPathsError", err)
+	}
+}
+
+func TestGenerateRejectsEscapingPaths(t *testing.T) {
+	parent := t.TempDir()
+	outputDir := filepath.Join(parent, "out", "proj")
+	schema := testSchema(
+		core.FileSpec{Path: "README.md", Content: "# App\n"},
+		core.FileSpec{Path: "../escaped.txt", Content: "escaped\n"},
+		core.FileSpec{Path: "/etc/absolute.txt", Content: "absolute\n"},
+	)
+
+	err := NewGeneratorFromSchema(schema, testVariables, outputDir).Generate(context.Background())
+	var invalid *InvalidPathsError
+	if !errors.As(err, &invalid) || len(invalid.Problems) != 2 {
+		t.Fatalf("Generate() error = %v, want both escaping paths rejected", err)
+	}
+	if _, err := os.Stat(filepath.Join(parent, "out", "escaped.txt")); !os.IsNotExist(err) {
+		t.Error("Generate() wrote a file outside the output directory")
+	}
+
+	// Env files follow the directory of their source, which must stay inside the project too
+	schema = testSchema(core.FileSpec{Path: "README.md", Content: "# App\n"})
+	schema.EnvConfig = []core.EnvVariable{{Name: "PORT", Example: "8080", Source: "../../.env.example"}}
+	generator := NewGeneratorFromSchema(schema, testVariables, outputDir)
+	generator.SetEnvOptions(EnvOptions{File: ".env"})
+	if err := generator.Generate(context.Background()); err == nil || !strings.Contains(err.Error(), "outside") {
+		t.Errorf("Generate() error = %v, want the env source outside the project rejected", err)
+	}
+
+	// The output directory refuses such paths as well, whatever calls it
+	if err := (dirOutput{dir: outputDir}).WriteFile("../escaped.txt", nil, 0o644); err == nil {
+		t.Error("dirOutput.WriteFile() should refuse a path outside the directory")
+	}
+}
+
+func TestGenerateUntrustedEnv(t *testing.T) {
+	t.Setenv("GENERATE_TEST_SECRET", "s3cret")
+	newSchema := func() *core.TemplateSchema {
+		return testSchema(core.FileSpec{Path: "secret.txt", Content: `{{ env "GENERATE_TEST_SECRET" }}`, Template: true})
+	}
+
+	if got := readOutput(t, generateSchema(t, newSchema()), "secret.txt"); got != "s3cret" {
+		t.Errorf("local schema secret.txt = %q, want the environment readable", got)
+	}
+
+	remote := newSchema()
+	remote.Origin = "https://registry.example.com/schemas/app"
+	if got := readOutput(t, generateSchema(t, remote), "secret.txt"); got != "" {
+		t.Errorf("remote schema secret.txt = %q, want the environment hidden", got)
+	}
+
+	untrusted := generateSchema(t, newSchema(), func(g *Generator) { g.SetUntrusted(true) })
+	if got := readOutput(t, untrusted, "secret.txt"); got != "" {
+		t.Errorf("untrusted schema secret.txt = %q, want the environment hidden", got)
+	}
+}
+
+func TestGeneratePolicies(t *testing.T) {
+	schema := testSchema(core.FileSpec{Path: "README.md", Content: "# App\n"})
+	outputDir := filepath.Join(t.TempDir(), "output")
+	generator := NewGeneratorFromSchema(schema, testVariables, outputDir)
+	generator.SetPolicies(&policy.Rules{AllowedOwners: []string{"acme"}})
+
+	err := generator.Generate(context.Background())
+	var violations *policy.ViolationError
+	if !errors.As(err, &violations) || violations.Violations[0].Rule != "allowed_owners" {
+		t.Fatalf("Generate() error = %v, want an allowed_owners violation", err)
+	}
+	if _, err := os.Stat(outputDir); !os.IsNotExist(err) {
+		t.Error("Generate() should not write anything when the policy rejects the request")
+	}
+
+	generateSchema(t, schema, func(g *Generator) {
+		g.SetPolicies(&policy.Rules{AllowedOwners: []string{"user"}})
+	})
+}
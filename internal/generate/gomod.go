@@ -0,0 +1,270 @@
+package generate
+
+import (
+	"context"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+// GoModOptions configures the rewrite of the go.mod files the schema declares (see
+// core.TemplateSchema.GoModules)
+type GoModOptions struct {
+	// GoVersion raises the go directive of every module to this version, e.g. "1.23", leaving
+	// modules already requiring a newer one alone
+	GoVersion string
+	// Tidy runs go mod tidy in every module once the project is written, its output captured
+	// in the result. Only for directory output.
+	Tidy bool
+}
+
+// GoModuleResult reports the rewrite of a go.mod file
+type GoModuleResult struct {
+	Path      string `json:"path"`
+	Module    string `json:"module"`
+	GoVersion string `json:"go_version,omitempty"`
+	// Package is the name the root package of the module was renamed to, if any
+	Package string `json:"package,omitempty"`
+	// TidyOutput is the combined output of go mod tidy, when it ran
+	TidyOutput string `json:"tidy_output,omitempty"`
+}
+
+// goVersionPattern matches the versions of go directives, e.g. 1.21 or 1.22.3
+var goVersionPattern = regexp.MustCompile(`^1\.\d+(\.\d+)?$`)
+
+// ParseGoVersion validates a Go version for GoModOptions.GoVersion, "" for none
+func ParseGoVersion(version string) (string, error) {
+	if version != "" && !goVersionPattern.MatchString(version) {
+		return "", fmt.Errorf("invalid Go version %q, expected e.g. 1.23 or 1.23.4", version)
+	}
+	return version, nil
+}
+
+// SetGoModOptions configures the rewrite of the schema's go.mod files
+func (g *Generator) SetGoModOptions(opts GoModOptions) {
+	g.goMod = opts
+}
+
+// goModule is a go module of the schema being generated
+type goModule struct {
+	result GoModuleResult
+	dir    string
+	// from is the module path of the reference project
+	from string
+	// fromPackage is the name of the root package of the reference project when renamed
+	fromPackage string
+}
+
+// prepareGoModules reads the module path of every go module of the schema and renders the
+// module path it is rewritten to
+func (g *Generator) prepareGoModules() error {
+	g.modules = nil
+	for _, declared := range g.schema.GoModules {
+		index := -1
+		for i, file := range g.schema.Files {
+			if filepath.ToSlash(file.Path) == declared.Path {
+				index = i
+			}
+		}
+		if index < 0 {
+			return fmt.Errorf("go module %s is not a file of the schema", declared.Path)
+		}
+		content, err := core.ResolveContent(g.schema, g.schema.Files[index])
+		if err != nil {
+			return fmt.Errorf("go module %s failed to decompress: %w", declared.Path, err)
+		}
+		from := modulePath(content)
+		if from == "" {
+			return fmt.Errorf("go module %s has no module directive", declared.Path)
+		}
+
+		left, right := core.EffectiveDelims(g.schema, core.FileSpec{})
+		renderer := newRenderer(g.templateFuncMap, g.templateData())
+		module, err := renderer.render(declared.Module, left, right)
+		if err != nil {
+			return fmt.Errorf("go module %s: %w", declared.Path, err)
+		}
+
+		module = strings.TrimSpace(module)
+		if err := core.CheckModulePath(module); err != nil {
+			g.warnName(fmt.Sprintf("go module %s: %v", declared.Path, err))
+		}
+
+		prepared := goModule{
+			result: GoModuleResult{Path: declared.Path, Module: module},
+			dir:    path.Dir(declared.Path),
+			from:   from,
+		}
+		if declared.Package != "" {
+			name, err := renderer.render(declared.Package, left, right)
+			if err != nil {
+				return fmt.Errorf("go module %s: %w", declared.Path, err)
+			}
+			if err := g.preparePackageRename(&prepared, strings.TrimSpace(name)); err != nil {
+				return fmt.Errorf("go module %s: %w", declared.Path, err)
+			}
+		}
+		g.modules = append(g.modules, prepared)
+	}
+	return nil
+}
+
+// rewriteGoModules applies the go module rewrites to a generated file: the module and go
+// directives of the declared go.mod files, and the imports of their modules in Go files
+func (g *Generator) rewriteGoModules(filePath, content string) string {
+	filePath = filepath.ToSlash(filePath)
+	for i := range g.modules {
+		module := &g.modules[i]
+		if filePath == module.result.Path {
+			content, module.result.GoVersion = rewriteGoMod(content, module.result.Module, g.goMod.GoVersion)
+		}
+	}
+	if path.Ext(filePath) != ".go" {
+		return content
+	}
+	for _, module := range g.modules {
+		content = rewriteImports(content, module.from, module.result.Module)
+		if module.fromPackage != "" {
+			content = renamePackage(content, path.Dir(filePath) == module.dir, module.result.Module,
+				module.fromPackage, module.result.Package)
+		}
+	}
+	return content
+}
+
+// tidyGoModules runs go mod tidy in every go module of the output directory when enabled
+func (g *Generator) tidyGoModules(ctx context.Context) error {
+	dir, ok := g.output.(dirOutput)
+	for i := range g.modules {
+		module := &g.modules[i]
+		if g.goMod.Tidy && ok && g.selects(module.result.Path) {
+			g.logger.Info("Running go mod tidy", "module", module.result.Module)
+			cmd := exec.CommandContext(ctx, "go", "mod", "tidy")
+			cmd.Dir = filepath.Join(dir.dir, filepath.FromSlash(module.dir))
+			if g.offline {
+				cmd.Env = append(os.Environ(), "GOPROXY=off")
+			}
+			output, err := cmd.CombinedOutput()
+			module.result.TidyOutput = strings.TrimSpace(string(output))
+			if err != nil {
+				g.result.GoModules = append(g.result.GoModules, module.result)
+				return fmt.Errorf("go mod tidy in %s failed: %w: %s", module.result.Path, err, module.result.TidyOutput)
+			}
+		}
+		g.result.GoModules = append(g.result.GoModules, module.result)
+	}
+	return nil
+}
+
+// modulePath returns the module path declared by the content of a go.mod file
+func modulePath(gomod string) string {
+	for _, line := range strings.Split(gomod, "\n") {
+		fields := strings.Fields(stripComment(line))
+		if len(fields) == 2 && fields[0] == "module" {
+			if unquoted, err := strconv.Unquote(fields[1]); err == nil {
+				return unquoted
+			}
+			return fields[1]
+		}
+	}
+	return ""
+}
+
+// rewriteGoMod sets the module directive of a go.mod file to module and raises its go directive
+// to goVersion when older, adding it when missing. It returns the rewritten file and the
+// resulting go version.
+func rewriteGoMod(gomod, module, goVersion string) (string, string) {
+	lines := strings.Split(gomod, "\n")
+	moduleLine, current := -1, ""
+	for i, line := range lines {
+		fields := strings.Fields(stripComment(line))
+		switch {
+		case len(fields) == 2 && fields[0] == "module" && moduleLine < 0:
+			moduleLine = i
+			lines[i] = strings.Replace(line, fields[1], module, 1)
+		case len(fields) == 2 && fields[0] == "go" && current == "":
+			current = fields[1]
+			if goVersion != "" && compareGoVersions(current, goVersion) < 0 {
+				lines[i] = strings.Replace(line, current, goVersion, 1)
+				current = goVersion
+			}
+		}
+	}
+
+	if current == "" && goVersion != "" && moduleLine >= 0 {
+		lines = append(lines[:moduleLine+1], append([]string{"", "go " + goVersion}, lines[moduleLine+1:]...)...)
+		current = goVersion
+	}
+	return strings.Join(lines, "\n"), current
+}
+
+// rewriteImports rewrites the imports of module from and its packages to module to. Imports are
+// found with go/parser, so aliased, blank and dot imports and files with build constraints are
+// handled alike, while strings and comments mentioning the module are left alone; content whose
+// imports do not parse is unchanged.
+func rewriteImports(content, from, to string) string {
+	if from == to {
+		return content
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", content, parser.ImportsOnly)
+	if err != nil {
+		return content
+	}
+
+	var edits []sourceEdit
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		rest, ok := strings.CutPrefix(importPath, from)
+		if !ok || (rest != "" && !strings.HasPrefix(rest, "/")) {
+			continue
+		}
+
+		literal := strconv.Quote(to + rest)
+		if strings.HasPrefix(spec.Path.Value, "`") {
+			literal = "`" + to + rest + "`"
+		}
+		offset := fset.Position(spec.Path.Pos()).Offset
+		edits = append(edits, sourceEdit{start: offset, end: offset + len(spec.Path.Value), text: literal})
+	}
+	return applySourceEdits(content, edits)
+}
+
+// stripComment removes the // comment ending a go.mod line
+func stripComment(line string) string {
+	if i := strings.Index(line, "//"); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
+
+// compareGoVersions compares two Go versions such as 1.21 and 1.21.3, an absent patch version
+// sorting first
+func compareGoVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	return len(as) - len(bs)
+}
@@ -0,0 +1,159 @@
+package generate
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+func TestGenerateGoModules(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not installed")
+	}
+
+	schema := testSchema(
+		core.FileSpec{Path: "go.mod", Content: "module github.com/acme/api-template // reference\n\ngo 1.21\n"},
+		core.FileSpec{Path: "main.go", Template: true, Content: "package main\n\n" +
+			"import \"github.com/acme/api-template/internal/greet\"\n\nfunc main() { greet.Hello() }\n"},
+		core.FileSpec{Path: "internal/greet/greet.go", Content: "package greet\n\nfunc Hello() {}\n"},
+		core.FileSpec{Path: "README.md", Content: "see github.com/acme/api-template/internal\n"},
+	)
+	schema.GoModules = []core.GoModule{{Path: "go.mod", Module: "github.com/{{.GitHubRepo}}"}}
+
+	var generator *Generator
+	outputDir := generateSchema(t, schema, func(g *Generator) {
+		g.SetGoModOptions(GoModOptions{GoVersion: "1.23", Tidy: true})
+		generator = g
+	})
+
+	tests := map[string]string{
+		"go.mod": "module github.com/user/my-app // reference\n\ngo 1.23\n",
+		"main.go": "package main\n\nimport \"github.com/user/my-app/internal/greet\"\n\n" +
+			"func main() { greet.Hello() }\n",
+		"README.md": "see github.com/acme/api-template/internal\n",
+	}
+	for path, want := range tests {
+		if got := readOutput(t, outputDir, path); got != want {
+			t.Errorf("%s = %q, want %q", path, got, want)
+		}
+	}
+
+	modules := generator.Result().GoModules
+	if len(modules) != 1 || modules[0].Module != "github.com/user/my-app" || modules[0].GoVersion != "1.23" {
+		t.Errorf("result go modules = %+v", modules)
+	}
+}
+
+func TestRewriteGoMod(t *testing.T) {
+	tests := []struct {
+		name, gomod, goVersion, want, wantVersion string
+	}{
+		{"keeps newer version", "module a\n\ngo 1.24.1\n", "1.23", "module b\n\ngo 1.24.1\n", "1.24.1"},
+		{"raises older version", "module \"a\"\n\ngo 1.21\n", "1.21.3", "module b\n\ngo 1.21.3\n", "1.21.3"},
+		{"adds missing version", "module a\n", "1.23", "module b\n\ngo 1.23\n", "1.23"},
+		{"no version", "module a\n\ngo 1.21\n", "", "module b\n\ngo 1.21\n", "1.21"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, version := rewriteGoMod(tt.gomod, "b", tt.goVersion)
+			if got != tt.want || version != tt.wantVersion {
+				t.Errorf("rewriteGoMod() = %q, %q, want %q, %q", got, version, tt.want, tt.wantVersion)
+			}
+		})
+	}
+
+	if _, err := ParseGoVersion("go1.23"); err == nil {
+		t.Error("ParseGoVersion(go1.23) should fail")
+	}
+
+	schema := testSchema(core.FileSpec{Path: "go.mod", Content: "module a\n\ngo 1.21\n"})
+	schema.GoModules = []core.GoModule{{Path: "go.mod", Module: "github.com/{{.GitHubRepo}}"}}
+	generator := NewGeneratorFromSchema(schema, testVariables, filepath.Join(t.TempDir(), "output"))
+	generator.SetGoModOptions(GoModOptions{GoVersion: "1.23\ntoolchain go1.99"})
+	if err := generator.Generate(context.Background()); err == nil {
+		t.Error("Generate() should refuse an invalid Go version set through SetGoModOptions")
+	}
+}
+
+func TestRewriteImports(t *testing.T) {
+	const from, to = "github.com/acme/api-template", "github.com/user/my-app"
+	tests := []struct {
+		name, content, want string
+	}{
+		{
+			"aliased blank and dot imports",
+			"package main\n\nimport (\n\tapi \"github.com/acme/api-template\"\n" +
+				"\t_ \"github.com/acme/api-template/internal/db\"\n\t. `github.com/acme/api-template/internal/greet`\n)\n",
+			"package main\n\nimport (\n\tapi \"github.com/user/my-app\"\n" +
+				"\t_ \"github.com/user/my-app/internal/db\"\n\t. `github.com/user/my-app/internal/greet`\n)\n",
+		},
+		{
+			"build constraint",
+			"//go:build integration\n\npackage main\n\nimport \"github.com/acme/api-template/internal/db\"\n",
+			"//go:build integration\n\npackage main\n\nimport \"github.com/user/my-app/internal/db\"\n",
+		},
+		{
+			"strings comments and other modules",
+			"package main\n\n// see github.com/acme/api-template/internal\nimport \"github.com/acme/api-template-extra\"\n\n" +
+				"const pkg = \"github.com/acme/api-template/internal\"\n",
+			"package main\n\n// see github.com/acme/api-template/internal\nimport \"github.com/acme/api-template-extra\"\n\n" +
+				"const pkg = \"github.com/acme/api-template/internal\"\n",
+		},
+		{
+			"invalid source",
+			"package main\n\nimport \"github.com/acme/api-template\n",
+			"package main\n\nimport \"github.com/acme/api-template\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rewriteImports(tt.content, from, to); got != tt.want {
+				t.Errorf("rewriteImports() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateGoPackageRename(t *testing.T) {
+	schema := testSchema(
+		core.FileSpec{Path: "go.mod", Content: "module github.com/acme/libtemplate\n\ngo 1.23\n"},
+		core.FileSpec{Path: "doc.go", Content: "// Package libtemplate greets.\n//\n" +
+			"// See libtemplate.Hello.\npackage libtemplate\n"},
+		core.FileSpec{Path: "hello.go", Content: "package libtemplate\n\n" +
+			"// Hello greets from libtemplate\nfunc Hello() string { return \"libtemplate\" }\n"},
+		core.FileSpec{Path: "hello_test.go", Content: "package libtemplate_test\n\n" +
+			"import \"github.com/acme/libtemplate\"\n\nvar _ = libtemplate.Hello\n"},
+		core.FileSpec{Path: "cmd/demo/main.go", Content: "package main\n\n" +
+			"import (\n\t\"fmt\"\n\n\t\"github.com/acme/libtemplate\"\n)\n\n" +
+			"func main() {\n\tfmt.Println(libtemplate.Hello())\n\tlibtemplate := 1\n\t_ = libtemplate\n}\n"},
+		core.FileSpec{Path: "examples/alias.go", Content: "package examples\n\n" +
+			"import lib \"github.com/acme/libtemplate\"\n\nvar libtemplate = lib.Hello\n"},
+	)
+	schema.GoModules = []core.GoModule{
+		{Path: "go.mod", Module: "github.com/{{.GitHubRepo}}", Package: "{{.ProjectNamePackage}}"},
+	}
+
+	var generator *Generator
+	outputDir := generateSchema(t, schema, func(g *Generator) { generator = g })
+
+	tests := map[string]string{
+		"doc.go": "// Package myapp greets.\n//\n// See libtemplate.Hello.\npackage myapp\n",
+		"hello.go": "package myapp\n\n" +
+			"// Hello greets from libtemplate\nfunc Hello() string { return \"libtemplate\" }\n",
+		"hello_test.go": "package myapp_test\n\nimport \"github.com/user/my-app\"\n\nvar _ = myapp.Hello\n",
+		"cmd/demo/main.go": "package main\n\nimport (\n\t\"fmt\"\n\n\t\"github.com/user/my-app\"\n)\n\n" +
+			"func main() {\n\tfmt.Println(myapp.Hello())\n\tlibtemplate := 1\n\t_ = libtemplate\n}\n",
+		"examples/alias.go": "package examples\n\nimport lib \"github.com/user/my-app\"\n\nvar libtemplate = lib.Hello\n",
+	}
+	for path, want := range tests {
+		if got := readOutput(t, outputDir, path); got != want {
+			t.Errorf("%s = %q, want %q", path, got, want)
+		}
+	}
+	if modules := generator.Result().GoModules; len(modules) != 1 || modules[0].Package != "myapp" {
+		t.Errorf("result go modules = %+v", modules)
+	}
+}
@@ -0,0 +1,125 @@
+package generate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+// preparePackageRename records the rename of the root package of module to name, reading the
+// current name from the package clause of a Go file in the module directory
+func (g *Generator) preparePackageRename(module *goModule, name string) error {
+	if !token.IsIdentifier(name) || name == "_" || name == "main" {
+		return fmt.Errorf("invalid package name %q", name)
+	}
+
+	for _, file := range g.schema.Files {
+		filePath := filepath.ToSlash(file.Path)
+		if path.Dir(filePath) != module.dir || path.Ext(filePath) != ".go" ||
+			strings.HasSuffix(filePath, "_test.go") || file.IsSymlink() || file.Encoding != "" {
+			continue
+		}
+		content, err := core.ResolveContent(g.schema, file)
+		if err != nil {
+			return fmt.Errorf("%s failed to decompress: %w", file.Path, err)
+		}
+		parsed, err := parser.ParseFile(token.NewFileSet(), "", content, parser.PackageClauseOnly)
+		if err != nil || parsed.Name.Name == "main" {
+			continue // A command has no package to rename
+		}
+
+		if parsed.Name.Name != name {
+			module.fromPackage = parsed.Name.Name
+		}
+		module.result.Package = name
+		return nil
+	}
+	return fmt.Errorf("no Go package in %s to rename", module.dir)
+}
+
+// sourceEdit replaces the bytes between start and end of a source file with text
+type sourceEdit struct {
+	start, end int
+	text       string
+}
+
+// renamePackage renames the package from to "to" in a Go file: its package clause and the
+// "Package from" doc comment when inRoot is set (the file is in the package directory, external
+// test packages included), and the references to the package when the file imports importPath
+// under its default name. Identifiers are found with go/parser, so strings, comments and other
+// identifiers spelled like the package are left alone; content that does not parse is unchanged.
+func renamePackage(content string, inRoot bool, importPath, from, to string) string {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", content, parser.ParseComments)
+	if err != nil {
+		return content
+	}
+
+	var edits []sourceEdit
+	replace := func(pos token.Pos, old, text string) {
+		offset := fset.Position(pos).Offset
+		edits = append(edits, sourceEdit{start: offset, end: offset + len(old), text: text})
+	}
+
+	if inRoot {
+		switch file.Name.Name {
+		case from:
+			replace(file.Name.Pos(), from, to)
+			if file.Doc != nil {
+				docPattern := regexp.MustCompile(`\bPackage ` + regexp.QuoteMeta(from) + `\b`)
+				for _, comment := range file.Doc.List {
+					if loc := docPattern.FindStringIndex(comment.Text); loc != nil {
+						replace(comment.Slash+token.Pos(loc[0]), comment.Text[loc[0]:loc[1]], "Package "+to)
+						break
+					}
+				}
+			}
+		case from + "_test":
+			replace(file.Name.Pos(), file.Name.Name, to+"_test")
+		}
+	}
+
+	if importsDefault(file, importPath) {
+		ast.Inspect(file, func(node ast.Node) bool {
+			selector, ok := node.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			// Identifiers resolved in the file are local declarations shadowing the import
+			if ident, ok := selector.X.(*ast.Ident); ok && ident.Name == from && ident.Obj == nil {
+				replace(ident.Pos(), from, to)
+			}
+			return true
+		})
+	}
+
+	return applySourceEdits(content, edits)
+}
+
+// applySourceEdits applies non-overlapping edits to content, last first so offsets stay valid
+func applySourceEdits(content string, edits []sourceEdit) string {
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	for _, edit := range edits {
+		content = content[:edit.start] + edit.text + content[edit.end:]
+	}
+	return content
+}
+
+// importsDefault reports whether file imports importPath without naming the import
+func importsDefault(file *ast.File, importPath string) bool {
+	for _, spec := range file.Imports {
+		if unquoted, err := strconv.Unquote(spec.Path.Value); err == nil && unquoted == importPath {
+			return spec.Name == nil
+		}
+	}
+	return false
+}
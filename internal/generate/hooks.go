@@ -0,0 +1,101 @@
+package generate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/hooks"
+)
+
+// HookOptions controls running the schema hooks
+type HookOptions struct {
+	// Enabled runs the pre_generate and post_generate hooks (disabled by default). Hooks only
+	// run when generating into a directory.
+	Enabled bool
+	// Output receives the output of the hooks as they run, discarded when nil
+	Output io.Writer
+	// AllowRemote also runs the hooks of schemas pulled from a registry (see
+	// core.TemplateSchema.Origin), which are skipped otherwise
+	AllowRemote bool
+	// AllowedBinaries restricts the programs hooks may run, any when empty (see config.HookPolicy)
+	AllowedBinaries []string
+}
+
+// hooksAllowed reports whether the hooks of schema may run, warning when a remote schema's
+// hooks are skipped
+func (opts HookOptions) hooksAllowed(logger *slog.Logger, schema *core.TemplateSchema, stage core.HookStage) bool {
+	if !opts.Enabled || len(schema.Hooks.ForStage(stage)) == 0 {
+		return false
+	}
+	if schema.IsRemote() && !opts.AllowRemote {
+		logger.Warn("Skipping hooks of remote schema, review them and pass --allow-hooks to run them",
+			"stage", stage, "origin", schema.Origin)
+		return false
+	}
+	return true
+}
+
+// runOptions returns the options to run hooks in dir with
+func (opts HookOptions) runOptions(dir string, variables map[string]string) hooks.Options {
+	return hooks.Options{
+		Dir:             dir,
+		Variables:       variables,
+		Output:          opts.Output,
+		AllowedBinaries: opts.AllowedBinaries,
+	}
+}
+
+// SetHookOptions configures running the schema hooks
+func (g *Generator) SetHookOptions(opts HookOptions) {
+	g.hooks = opts
+}
+
+// Variables returns the template variables, including the derived ones, as passed to hooks
+func (g *Generator) Variables() map[string]string {
+	variables := map[string]string{}
+	for name, value := range g.templateData() {
+		variables[name], _ = value.(string)
+	}
+	return variables
+}
+
+// runHooks runs the schema hooks of a stage in the output directory when hooks are enabled
+// and allowed for the schema
+func (g *Generator) runHooks(ctx context.Context, stage core.HookStage) error {
+	dir, ok := g.output.(dirOutput)
+	if !ok || !g.hooks.hooksAllowed(g.logger, g.schema, stage) {
+		return nil
+	}
+
+	disabled, err := g.disabledFeatures()
+	if err != nil {
+		return err
+	}
+
+	opts := g.hooks.runOptions(dir.dir, g.Variables())
+	g.hookEvents(&opts)
+	results, err := hooks.Run(ctx, g.logger, featureHooks(g.schema.Hooks.ForStage(stage), disabled), opts)
+	g.result.Hooks = append(g.result.Hooks, results...)
+	return err
+}
+
+// checkHookPolicy refuses schemas whose generate hooks break the hook policy before anything is written
+func (g *Generator) checkHookPolicy() error {
+	if _, ok := g.output.(dirOutput); !ok || !g.hooks.Enabled && !g.verify || g.schema.IsRemote() && !g.hooks.AllowRemote {
+		return nil
+	}
+
+	for _, hook := range g.schema.Hooks {
+		if hook.Stage == core.HookPostUpdate || hook.Stage == core.HookVerify && !g.verify ||
+			hook.Stage != core.HookVerify && !g.hooks.Enabled {
+			continue
+		}
+		if err := hooks.CheckHook(hook, g.hooks.AllowedBinaries); err != nil {
+			return fmt.Errorf("hook %s refused by hook policy: %w", hook.Label(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package generate
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+// License is a license the generated project is published under, see SetLicense
+type License string
+
+const (
+	LicenseMIT         License = "MIT"
+	LicenseApache      License = "Apache-2.0"
+	LicenseProprietary License = "proprietary"
+)
+
+// licenseFile is the path the license is written to
+const licenseFile = "LICENSE"
+
+// licenseFiles are the schema files replaced by the chosen license
+var licenseFiles = []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "LICENCE", "LICENCE.md", "COPYING"}
+
+// ParseLicense validates a license name, "" for no license
+func ParseLicense(name string) (License, error) {
+	for _, license := range []License{LicenseMIT, LicenseApache, LicenseProprietary} {
+		if strings.EqualFold(name, string(license)) {
+			return license, nil
+		}
+	}
+	if name == "" {
+		return "", nil
+	}
+	return "", fmt.Errorf("unknown license %q (available: MIT, Apache-2.0, proprietary)", name)
+}
+
+// SetLicense writes license to the LICENSE file of the project, replacing the license files of
+// the schema, and injects the schema's copyright header (see core.TemplateSchema.Header) at the
+// top of the source files whose comment syntax is known. No license is written when empty.
+func (g *Generator) SetLicense(license License) {
+	g.license = license
+}
+
+// commentStyle is the comment syntax of a kind of source file
+type commentStyle struct {
+	// line prefixes every header line, or start and end enclose the header
+	line, start, end string
+}
+
+var (
+	slashComments = commentStyle{line: "//"}
+	hashComments  = commentStyle{line: "#"}
+	dashComments  = commentStyle{line: "--"}
+	blockComments = commentStyle{start: "/*", line: " *", end: " */"}
+)
+
+// headerStyles maps the extensions of the source files headers are injected into to their
+// comment syntax
+var headerStyles = map[string]commentStyle{
+	".go": slashComments, ".js": slashComments, ".jsx": slashComments, ".mjs": slashComments,
+	".cjs": slashComments, ".ts": slashComments, ".tsx": slashComments, ".java": slashComments,
+	".kt": slashComments, ".swift": slashComments, ".rs": slashComments, ".c": slashComments,
+	".h": slashComments, ".cpp": slashComments, ".cs": slashComments, ".scss": slashComments,
+	".py": hashComments, ".rb": hashComments, ".sh": hashComments, ".bash": hashComments,
+	".sql": dashComments,
+	".css": blockComments,
+}
+
+// licenseData returns the template data of the license and the copyright header
+func (g *Generator) licenseData() map[string]any {
+	data := g.templateData()
+	data["License"] = string(g.license)
+	data["Year"] = strconv.Itoa(time.Now().Year())
+	return data
+}
+
+// withLicense replaces the license files among files with the LICENSE file of the chosen
+// license, when the file filter selects it; its content is filled in by renderLicense
+func (g *Generator) withLicense(files []core.FileSpec) []core.FileSpec {
+	if g.license == "" {
+		return files
+	}
+
+	files = slices.DeleteFunc(slices.Clone(files), func(file core.FileSpec) bool {
+		return slices.Contains(licenseFiles, file.Path)
+	})
+	if g.selects(licenseFile) {
+		files = append(files, core.FileSpec{Path: licenseFile})
+	}
+	return files
+}
+
+// renderLicense renders the license text into the LICENSE file of files and the copyright
+// header of the schema, once the template variables are final
+func (g *Generator) renderLicense(files []core.FileSpec) error {
+	g.header = ""
+	if g.license == "" {
+		return nil
+	}
+
+	text, ok := licenseTexts[g.license]
+	if !ok {
+		return fmt.Errorf("unknown license %q", g.license)
+	}
+
+	renderer := newRenderer(g.templateFuncMap, g.licenseData())
+	if g.schema.Header != "" {
+		left, right := core.EffectiveDelims(g.schema, core.FileSpec{})
+		header, err := renderer.render(g.schema.Header, left, right)
+		if err != nil {
+			return fmt.Errorf("failed to render copyright header: %w", err)
+		}
+		g.header = strings.TrimRight(header, "\n")
+	}
+
+	index := slices.IndexFunc(files, func(file core.FileSpec) bool { return file.Path == licenseFile })
+	if index < 0 {
+		return nil
+	}
+	rendered, err := renderer.render(text, core.DefaultLeftDelim, core.DefaultRightDelim)
+	if err != nil {
+		return fmt.Errorf("failed to render license: %w", err)
+	}
+	files[index].Content = rendered
+	return nil
+}
+
+// addHeader prepends the copyright header to the content of a source file, after any shebang
+// line. Other files, binary ones included, are returned unchanged.
+func (g *Generator) addHeader(fileSpec core.FileSpec, content string) (string, bool) {
+	style, ok := headerStyles[path.Ext(fileSpec.Path)]
+	if g.header == "" || !ok || fileSpec.Encoding != "" {
+		return content, false
+	}
+
+	var shebang string
+	if strings.HasPrefix(content, "#!") {
+		end := strings.IndexByte(content, '\n') + 1
+		if end == 0 {
+			end = len(content)
+		}
+		shebang, content = content[:end], content[end:]
+	}
+	return shebang + style.comment(g.header) + "\n" + content, true
+}
+
+// comment turns text into a comment followed by a newline
+func (s commentStyle) comment(text string) string {
+	var b strings.Builder
+	if s.start != "" {
+		b.WriteString(s.start + "\n")
+	}
+	for _, line := range strings.Split(text, "\n") {
+		if line == "" {
+			b.WriteString(s.line + "\n")
+		} else {
+			b.WriteString(s.line + " " + line + "\n")
+		}
+	}
+	if s.end != "" {
+		b.WriteString(s.end + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,62 @@
+package generate
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+func TestGenerateLicense(t *testing.T) {
+	schema := testSchema(
+		core.FileSpec{Path: "LICENSE", Content: "Copyright Acme Corp"},
+		core.FileSpec{Path: "main.go", Content: "package main\n"},
+		core.FileSpec{Path: "scripts/run.sh", Content: "#!/bin/sh\necho run\n"},
+		core.FileSpec{Path: "style.css", Content: "body {}\n"},
+		core.FileSpec{Path: "README.md", Content: "# App\n"},
+	)
+	schema.Header = "Copyright {{.Year}} {{.ProjectName}}\nSPDX-License-Identifier: {{.License}}"
+
+	var generator *Generator
+	outputDir := generateSchema(t, schema, func(g *Generator) {
+		g.SetLicense(LicenseApache)
+		generator = g
+	})
+
+	year := strconv.Itoa(time.Now().Year())
+	license := readOutput(t, outputDir, "LICENSE")
+	if !strings.Contains(license, "Apache License") || !strings.Contains(license, "Copyright "+year) {
+		t.Errorf("LICENSE = %q, want the Apache-2.0 license of %s", license[:200], year)
+	}
+
+	header := "Copyright " + year + " My App\n"
+	tests := map[string]string{
+		"main.go":        "// " + header + "// SPDX-License-Identifier: Apache-2.0\n\npackage main\n",
+		"scripts/run.sh": "#!/bin/sh\n# " + header + "# SPDX-License-Identifier: Apache-2.0\n\necho run\n",
+		"style.css":      "/*\n * " + header + " * SPDX-License-Identifier: Apache-2.0\n */\n\nbody {}\n",
+		"README.md":      "# App\n",
+	}
+	for path, want := range tests {
+		if got := readOutput(t, outputDir, path); got != want {
+			t.Errorf("%s = %q, want %q", path, got, want)
+		}
+	}
+
+	result := generator.Result()
+	if result.Headers != 3 || result.License != "Apache-2.0" || result.FileCount != 5 {
+		t.Errorf("result headers = %d, license = %q, files = %d", result.Headers, result.License, result.FileCount)
+	}
+}
+
+func TestParseLicense(t *testing.T) {
+	for name, want := range map[string]License{"": "", "mit": LicenseMIT, "Apache-2.0": LicenseApache} {
+		if got, err := ParseLicense(name); err != nil || got != want {
+			t.Errorf("ParseLicense(%q) = %q, %v, want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseLicense("GPL-3.0"); err == nil {
+		t.Error("ParseLicense(GPL-3.0) should fail")
+	}
+}
@@ -0,0 +1,43 @@
+package generate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+// MissingMapping is a required mapping whose Find string was not in the file it applies to
+type MissingMapping struct {
+	Path string `json:"path"`
+	Find string `json:"find"`
+}
+
+// SetWarnMissingMappings makes required mappings missing from their file a warning, listed in
+// Result.MissingMappings, instead of failing generation
+func (g *Generator) SetWarnMissingMappings(warn bool) {
+	g.warnMissing = warn
+}
+
+// reportMissingMappings fails on the required mappings of the file at path that found nothing,
+// or warns about them
+func (g *Generator) reportMissingMappings(path string, missing []core.Mapping) error {
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if !g.warnMissing {
+		finds := make([]string, len(missing))
+		for i, mapping := range missing {
+			finds[i] = fmt.Sprintf("%q", mapping.Find)
+		}
+		return fmt.Errorf("required mapping %s not found in %s, the template may be stale",
+			strings.Join(finds, ", "), path)
+	}
+
+	for _, mapping := range missing {
+		g.logger.Warn("Required mapping not found, the template may be stale", "path", path, "find", mapping.Find)
+		g.result.MissingMappings = append(g.result.MissingMappings, MissingMapping{Path: path, Find: mapping.Find})
+	}
+	return nil
+}
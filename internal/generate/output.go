@@ -0,0 +1,86 @@
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Output receives the files of a generated project. Paths are slash-separated and
+// relative to the project root. *archive.Writer implements it to stream projects as archives.
+type Output interface {
+	WriteFile(path string, data []byte, perm fs.FileMode) error
+}
+
+// SymlinkOutput is implemented by outputs able to create symbolic links, such as the output
+// directory and *archive.Writer. Symlinks are materialized as regular files for other outputs.
+type SymlinkOutput interface {
+	// Symlink creates a symlink at path pointing to target, relative to the directory of path
+	Symlink(path, target string) error
+}
+
+// StreamOutput is implemented by outputs able to write a file as it is produced, such as the
+// output directory. Files are only streamed when nothing has to rewrite them afterwards.
+type StreamOutput interface {
+	// CreateFile creates or truncates the file at path, the caller closing it once written
+	CreateFile(path string, perm fs.FileMode) (io.WriteCloser, error)
+}
+
+// dirOutput writes generated files below a directory
+type dirOutput struct {
+	dir string
+}
+
+func (d dirOutput) WriteFile(path string, data []byte, perm fs.FileMode) error {
+	destPath, err := d.destPath(path)
+	if err != nil {
+		return err
+	}
+
+	// Create directory if it doesn't exist
+	if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(destPath, data, perm)
+}
+
+func (d dirOutput) CreateFile(path string, perm fs.FileMode) (io.WriteCloser, error) {
+	destPath, err := d.destPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
+		return nil, err
+	}
+
+	return os.OpenFile(destPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+}
+
+func (d dirOutput) Symlink(path, target string) error {
+	destPath, err := d.destPath(path)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
+		return err
+	}
+
+	// Replace whatever is in the way, like WriteFile does
+	if err := os.Remove(destPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return os.Symlink(filepath.FromSlash(target), destPath)
+}
+
+// destPath returns where path is written, refusing paths that would leave the directory
+func (d dirOutput) destPath(path string) (string, error) {
+	local := filepath.FromSlash(path)
+	if !filepath.IsLocal(local) {
+		return "", fmt.Errorf("refusing to write %s outside the output directory", path)
+	}
+	return filepath.Join(d.dir, local), nil
+}
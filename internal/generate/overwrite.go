@@ -0,0 +1,107 @@
+package generate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// OverwritePolicy decides how generation treats an output directory that already exists
+type OverwritePolicy string
+
+const (
+	// OverwriteFail refuses to generate into an existing output directory
+	OverwriteFail OverwritePolicy = "fail"
+	// OverwriteMerge regenerates into an existing output directory, writing only the files
+	// whose content changed, so generating twice in a row is a no-op
+	OverwriteMerge OverwritePolicy = "merge"
+)
+
+// ParseOverwritePolicy validates an overwrite policy name, OverwriteFail when empty
+func ParseOverwritePolicy(name string) (OverwritePolicy, error) {
+	switch OverwritePolicy(name) {
+	case "", OverwriteFail:
+		return OverwriteFail, nil
+	case OverwriteMerge:
+		return OverwriteMerge, nil
+	default:
+		return "", fmt.Errorf("unknown overwrite policy %q (available: fail, merge)", name)
+	}
+}
+
+// FileState tells what generating a file did to the output
+type FileState string
+
+const (
+	FileCreated   FileState = "created"
+	FileUpdated   FileState = "updated"
+	FileUnchanged FileState = "unchanged"
+)
+
+// SetOverwritePolicy configures how files already in the output directory are treated. With
+// OverwriteMerge files whose content is unchanged are not rewritten and the result counts the
+// files created, updated and left unchanged; otherwise every file is written and counted as created.
+func (g *Generator) SetOverwritePolicy(policy OverwritePolicy) {
+	g.overwrite = policy
+}
+
+// writeFile writes a generated file to the output, skipping files already up to date when merging
+func (g *Generator) writeFile(path string, data []byte) (FileState, error) {
+	state := FileCreated
+	if dir, ok := g.output.(dirOutput); ok && g.overwrite == OverwriteMerge {
+		existing, err := os.ReadFile(filepath.Join(dir.dir, filepath.FromSlash(path)))
+		switch {
+		case err == nil && bytes.Equal(existing, data):
+			return FileUnchanged, nil
+		case err == nil:
+			state = FileUpdated
+		case !errors.Is(err, fs.ErrNotExist):
+			return "", err
+		}
+	}
+
+	if err := g.output.WriteFile(path, data, 0o644); err != nil {
+		return "", err
+	}
+	return state, nil
+}
+
+// writeSymlink creates a symlink in the output, leaving alone one already pointing to target
+// when merging
+func (g *Generator) writeSymlink(path, target string) (FileState, error) {
+	state := FileCreated
+	if dir, ok := g.output.(dirOutput); ok && g.overwrite == OverwriteMerge {
+		destPath := filepath.Join(dir.dir, filepath.FromSlash(path))
+		existing, err := os.Readlink(destPath)
+		switch {
+		case err == nil && filepath.ToSlash(existing) == target:
+			return FileUnchanged, nil
+		case err == nil:
+			state = FileUpdated
+		default:
+			if _, err := os.Lstat(destPath); err == nil {
+				state = FileUpdated
+			}
+		}
+	}
+
+	if err := g.output.(SymlinkOutput).Symlink(path, target); err != nil {
+		return "", err
+	}
+	return state, nil
+}
+
+// count records the state of a generated file in the result
+func (r *Result) count(state FileState) {
+	switch state {
+	case FileCreated:
+		r.Created++
+	case FileUpdated:
+		r.Updated++
+	case FileUnchanged:
+		r.Unchanged++
+	}
+}
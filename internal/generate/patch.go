@@ -0,0 +1,349 @@
+package generate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/pkg/schema"
+)
+
+// patchFile applies the structured patches of a file to its generated content
+func (g *Generator) patchFile(fileSpec core.FileSpec, content string) (string, error) {
+	patch, patches := patchJSON, fileSpec.JSONPatch
+	if len(fileSpec.YAMLPatch) > 0 {
+		patch, patches = patchYAML, fileSpec.YAMLPatch
+	}
+	if len(patches) == 0 {
+		return content, nil
+	}
+
+	rendered, err := g.renderPatches(fileSpec, patches)
+	if err != nil {
+		return "", err
+	}
+	return patch(content, rendered)
+}
+
+// renderPatches renders the template strings of the patch values, written with {{ }} like
+// mapping replacements
+func (g *Generator) renderPatches(fileSpec core.FileSpec, patches []core.Patch) ([]core.Patch, error) {
+	left, right := core.EffectiveDelims(g.schema, fileSpec)
+	renderer := newRenderer(g.templateFuncMap, g.templateData())
+
+	rendered := make([]core.Patch, len(patches))
+	for i, patch := range patches {
+		value, err := renderValue(renderer, patch.Value, left, right)
+		if err != nil {
+			return nil, fmt.Errorf("patch %s: %w", patch.Path, err)
+		}
+		patch.Value = value
+		rendered[i] = patch
+	}
+	return rendered, nil
+}
+
+// renderValue renders every string of a decoded JSON value
+func renderValue(renderer *renderer, value any, left, right string) (any, error) {
+	switch value := value.(type) {
+	case string:
+		return renderer.render(convertDelims(value, left, right), left, right)
+	case map[string]any:
+		rendered := make(map[string]any, len(value))
+		for key, item := range value {
+			var err error
+			if rendered[key], err = renderValue(renderer, item, left, right); err != nil {
+				return nil, err
+			}
+		}
+		return rendered, nil
+	case []any:
+		rendered := make([]any, len(value))
+		for i, item := range value {
+			var err error
+			if rendered[i], err = renderValue(renderer, item, left, right); err != nil {
+				return nil, err
+			}
+		}
+		return rendered, nil
+	default:
+		return value, nil
+	}
+}
+
+// jsonObject is a decoded JSON object keeping the order of its keys, so patching does not
+// shuffle the keys of the file
+type jsonObject struct {
+	keys   []string
+	values map[string]any
+}
+
+// set sets key, appending it when new
+func (o *jsonObject) set(key string, value any) {
+	if _, exists := o.values[key]; !exists {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// rename renames key to name, keeping its position
+func (o *jsonObject) rename(key, name string) error {
+	if key == name {
+		return nil
+	}
+	if _, exists := o.values[name]; exists {
+		return fmt.Errorf("cannot rename %q to %q, the key already exists", key, name)
+	}
+	o.keys[slices.Index(o.keys, key)] = name
+	o.values[name] = o.values[key]
+	delete(o.values, key)
+	return nil
+}
+
+// remove removes key if present
+func (o *jsonObject) remove(key string) {
+	if _, exists := o.values[key]; exists {
+		delete(o.values, key)
+		o.keys = slices.DeleteFunc(o.keys, func(k string) bool { return k == key })
+	}
+}
+
+// patchJSON applies patches to a JSON document, keeping its key order and indentation. The
+// document is written back indented like package.json files, one value per line.
+func patchJSON(content string, patches []core.Patch) (string, error) {
+	decoder := json.NewDecoder(strings.NewReader(content))
+	decoder.UseNumber()
+	document, err := decodeJSON(decoder)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	for _, patch := range patches {
+		if document, err = applyPatch(document, schema.SplitPatchPath(patch.Path), patch); err != nil {
+			return "", fmt.Errorf("patch %s: %w", patch.Path, err)
+		}
+	}
+
+	var out bytes.Buffer
+	encodeJSON(&out, document, detectIndent(content), "")
+	if strings.HasSuffix(content, "\n") {
+		out.WriteByte('\n')
+	}
+	return out.String(), nil
+}
+
+// applyPatch applies a patch to the value below keys of node and returns the patched node
+func applyPatch(node any, keys []string, patch core.Patch) (any, error) {
+	if len(keys) == 0 {
+		return orderedValue(patch.Value), nil
+	}
+	key, rest := keys[0], keys[1:]
+	op, last := patch.Operation(), len(rest) == 0
+
+	switch node := node.(type) {
+	case *jsonObject:
+		targets := []string{key}
+		if key == schema.PatchWildcard {
+			targets = slices.Clone(node.keys)
+		}
+		for _, target := range targets {
+			child, exists := node.values[target]
+			switch {
+			case last && op == schema.PatchRemove:
+				node.remove(target)
+				continue
+			case last && op == schema.PatchRename:
+				if exists {
+					if err := node.rename(target, patch.Value.(string)); err != nil {
+						return nil, err
+					}
+				}
+				continue
+			case !exists && (op != schema.PatchSet || slices.Contains(rest, schema.PatchWildcard)):
+				continue // Wildcards only reach existing values
+			case !exists:
+				child = &jsonObject{values: map[string]any{}}
+			}
+			patched, err := applyPatch(child, rest, patch)
+			if err != nil {
+				return nil, err
+			}
+			node.set(target, patched)
+		}
+		return node, nil
+
+	case []any:
+		if last && op == schema.PatchRename {
+			return nil, fmt.Errorf("cannot rename the elements of an array")
+		}
+		if key == schema.PatchWildcard {
+			if last && op == schema.PatchRemove {
+				return []any{}, nil
+			}
+			for i := range node {
+				patched, err := applyPatch(node[i], rest, patch)
+				if err != nil {
+					return nil, err
+				}
+				node[i] = patched
+			}
+			return node, nil
+		}
+
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index > len(node) || (index == len(node) && (op != schema.PatchSet || !last)) {
+			if op != schema.PatchSet {
+				return node, nil
+			}
+			return nil, fmt.Errorf("invalid index %q for an array of %d values", key, len(node))
+		}
+		if last && op == schema.PatchRemove {
+			return slices.Delete(node, index, index+1), nil
+		}
+		if index == len(node) {
+			return append(node, orderedValue(patch.Value)), nil
+		}
+		patched, err := applyPatch(node[index], rest, patch)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = patched
+		return node, nil
+
+	default:
+		if op != schema.PatchSet {
+			return node, nil
+		}
+		return nil, fmt.Errorf("cannot set %q below a value that is not an object or array", key)
+	}
+}
+
+// orderedValue converts a value decoded by encoding/json to the ordered representation,
+// object keys sorted
+func orderedValue(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		object := &jsonObject{values: map[string]any{}}
+		for _, key := range slices.Sorted(maps.Keys(value)) {
+			object.set(key, orderedValue(value[key]))
+		}
+		return object
+	case []any:
+		ordered := make([]any, len(value))
+		for i, item := range value {
+			ordered[i] = orderedValue(item)
+		}
+		return ordered
+	default:
+		return value
+	}
+}
+
+// decodeJSON decodes the next JSON value of decoder, keeping the order of object keys
+func decodeJSON(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		object := &jsonObject{values: map[string]any{}}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeJSON(decoder)
+			if err != nil {
+				return nil, err
+			}
+			object.set(key.(string), value)
+		}
+		_, err := decoder.Token() // Closing brace
+		return object, err
+
+	case json.Delim('['):
+		array := []any{}
+		for decoder.More() {
+			value, err := decodeJSON(decoder)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err := decoder.Token() // Closing bracket
+		return array, err
+
+	default:
+		return token, nil
+	}
+}
+
+// encodeJSON writes value as indented JSON, without escaping HTML characters
+func encodeJSON(w *bytes.Buffer, value any, indent, prefix string) {
+	switch value := value.(type) {
+	case *jsonObject:
+		if len(value.keys) == 0 {
+			w.WriteString("{}")
+			return
+		}
+		w.WriteString("{\n")
+		for i, key := range value.keys {
+			w.WriteString(prefix + indent)
+			encodeScalar(w, key)
+			w.WriteString(": ")
+			encodeJSON(w, value.values[key], indent, prefix+indent)
+			if i < len(value.keys)-1 {
+				w.WriteByte(',')
+			}
+			w.WriteByte('\n')
+		}
+		w.WriteString(prefix + "}")
+
+	case []any:
+		if len(value) == 0 {
+			w.WriteString("[]")
+			return
+		}
+		w.WriteString("[\n")
+		for i, item := range value {
+			w.WriteString(prefix + indent)
+			encodeJSON(w, item, indent, prefix+indent)
+			if i < len(value)-1 {
+				w.WriteByte(',')
+			}
+			w.WriteByte('\n')
+		}
+		w.WriteString(prefix + "]")
+
+	default:
+		encodeScalar(w, value)
+	}
+}
+
+// encodeScalar writes a JSON string, number, boolean or null
+func encodeScalar(w *bytes.Buffer, value any) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(value) // Scalars always encode
+	w.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
+
+// detectIndent returns the indentation of the first indented line of a JSON document, two
+// spaces when there is none
+func detectIndent(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed != "" && len(trimmed) < len(line) {
+			return line[:len(line)-len(trimmed)]
+		}
+	}
+	return "  "
+}
@@ -0,0 +1,162 @@
+package generate
+
+import (
+	"testing"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+func TestPatchJSON(t *testing.T) {
+	content := "{\n\t\"name\": \"frontend-template\",\n\t\"version\": \"1.0.0\",\n" +
+		"\t\"keywords\": [\"react\", \"vite\"],\n\t\"private\": true\n}\n"
+
+	got, err := patchJSON(content, []core.Patch{
+		{Path: "name", Value: "my-app"},
+		{Path: "repository", Value: map[string]any{"url": "https://github.com/user/my-app.git", "type": "git"}},
+		{Path: "scripts.dev", Value: "vite --host <all>"},
+		{Path: "keywords.1", Value: "vue"},
+		{Path: "keywords.2", Value: "ts"},
+		{Op: "remove", Path: "private"},
+		{Op: "remove", Path: "missing.key"},
+	})
+	if err != nil {
+		t.Fatalf("patchJSON() error = %v", err)
+	}
+
+	want := "{\n\t\"name\": \"my-app\",\n\t\"version\": \"1.0.0\",\n" +
+		"\t\"keywords\": [\n\t\t\"react\",\n\t\t\"vue\",\n\t\t\"ts\"\n\t],\n\t\"repository\": {\n\t\t\"type\": \"git\",\n" +
+		"\t\t\"url\": \"https://github.com/user/my-app.git\"\n\t},\n" +
+		"\t\"scripts\": {\n\t\t\"dev\": \"vite --host <all>\"\n\t}\n}\n"
+	if got != want {
+		t.Errorf("patchJSON() =\n%s\nwant\n%s", got, want)
+	}
+
+	for _, patch := range []core.Patch{{Path: "name.first", Value: "x"}, {Path: "keywords.5", Value: "x"}} {
+		if _, err := patchJSON(`{"name": "app", "keywords": []}`, []core.Patch{patch}); err == nil {
+			t.Errorf("patchJSON(%s) should fail", patch.Path)
+		}
+	}
+	if _, err := patchJSON("not json", nil); err == nil {
+		t.Error("patchJSON() of invalid JSON should fail")
+	}
+}
+
+func TestGenerateJSONPatch(t *testing.T) {
+	schema := testSchema(core.FileSpec{
+		Path:    "package.json",
+		Content: "{\n  \"name\": \"frontend-template\",\n  \"version\": \"1.0.0\"\n}\n",
+		JSONPatch: []core.Patch{
+			{Path: "name", Value: "{{.ProjectNameKebab}}"},
+			{Path: "repository", Value: map[string]any{"url": "https://github.com/{{.GitHubRepo}}.git"}},
+		},
+	})
+
+	outputDir := generateSchema(t, schema)
+
+	want := "{\n  \"name\": \"my-app\",\n  \"version\": \"1.0.0\",\n  \"repository\": {\n" +
+		"    \"url\": \"https://github.com/user/my-app.git\"\n  }\n}\n"
+	if got := readOutput(t, outputDir, "package.json"); got != want {
+		t.Errorf("package.json =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestPatchJSONRenameAndWildcard(t *testing.T) {
+	content := `{"workspaces": {"api-template": {"private": true}, "web": {"private": true}}}`
+
+	got, err := patchJSON(content, []core.Patch{
+		{Op: "rename", Path: "workspaces.api-template", Value: "my-api"},
+		{Path: "workspaces.*.private", Value: false},
+	})
+	if err != nil {
+		t.Fatalf("patchJSON() error = %v", err)
+	}
+	want := "{\n  \"workspaces\": {\n    \"my-api\": {\n      \"private\": false\n    },\n" +
+		"    \"web\": {\n      \"private\": false\n    }\n  }\n}"
+	if got != want {
+		t.Errorf("patchJSON() =\n%s\nwant\n%s", got, want)
+	}
+
+	rename := core.Patch{Op: "rename", Path: "workspaces.web", Value: "api-template"}
+	if _, err := patchJSON(content, []core.Patch{rename}); err == nil {
+		t.Error("patchJSON() renaming onto an existing key should fail")
+	}
+	wildcard := core.Patch{Path: "builds.*.binary", Value: "my-cli"}
+	if got, err := patchJSON(`{"name": "cli"}`, []core.Patch{wildcard}); err != nil || got != "{\n  \"name\": \"cli\"\n}" {
+		t.Errorf("patchJSON() wildcard below a missing key = %q, %v, want no change", got, err)
+	}
+}
+
+func TestPatchYAML(t *testing.T) {
+	content := `# Local development stack
+services:
+  api-template:
+    image: api-template:latest # built by make docker
+    ports:
+      - "8080:8080"
+  db:
+    image: postgres:16
+    environment:
+      POSTGRES_DB: api_template
+---
+kind: ConfigMap
+`
+
+	got, err := patchYAML(content, []core.Patch{
+		{Op: "rename", Path: "services.api-template", Value: "my-api"},
+		{Path: "services.my-api.image", Value: "my-api:latest"},
+		{Path: "services.*.restart", Value: "unless-stopped"},
+		{Path: "services.db.environment.POSTGRES_DB", Value: "my_api"},
+		{Path: "services.my-api.ports.1", Value: "9090:9090"},
+		{Op: "remove", Path: "services.db.image"},
+		{Path: "volumes.data", Value: map[string]any{}},
+	})
+	if err != nil {
+		t.Fatalf("patchYAML() error = %v", err)
+	}
+
+	want := `# Local development stack
+services:
+  my-api:
+    image: my-api:latest # built by make docker
+    ports:
+      - "8080:8080"
+      - 9090:9090
+    restart: unless-stopped
+  db:
+    environment:
+      POSTGRES_DB: my_api
+    restart: unless-stopped
+volumes:
+  data: {}
+---
+kind: ConfigMap
+`
+	if got != want {
+		t.Errorf("patchYAML() =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := patchYAML("services: [a]\n", []core.Patch{{Path: "services.web.image", Value: "x"}}); err == nil {
+		t.Error("patchYAML() setting a key below a sequence should fail")
+	}
+	if _, err := patchYAML("services: {\n", nil); err == nil {
+		t.Error("patchYAML() of invalid YAML should fail")
+	}
+}
+
+func TestGenerateYAMLPatch(t *testing.T) {
+	schema := testSchema(core.FileSpec{
+		Path:    "chart/values.yaml",
+		Content: "image:\n    repository: acme/api-template\n    tag: latest\n",
+		YAMLPatch: []core.Patch{
+			{Path: "image.repository", Value: "{{.GitHubRepo}}"},
+			{Path: "nameOverride", Value: "{{.ProjectNameKebab}}"},
+		},
+	})
+
+	outputDir := generateSchema(t, schema)
+
+	want := "image:\n    repository: user/my-app\n    tag: latest\nnameOverride: my-app\n"
+	if got := readOutput(t, outputDir, "chart/values.yaml"); got != want {
+		t.Errorf("values.yaml =\n%s\nwant\n%s", got, want)
+	}
+}
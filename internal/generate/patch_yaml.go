@@ -0,0 +1,215 @@
+package generate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/pkg/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// patchYAML applies patches to the first document of a YAML file, keeping its comments and key
+// order. The file is written back with its indentation; quoting and blank lines may change.
+func patchYAML(content string, patches []core.Patch) (string, error) {
+	var documents []*yaml.Node
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to parse YAML: %w", err)
+		}
+		documents = append(documents, &document)
+	}
+	if len(documents) == 0 {
+		documents = append(documents, &yaml.Node{Kind: yaml.DocumentNode})
+	}
+	if len(documents[0].Content) == 0 {
+		documents[0].Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+
+	root := documents[0].Content[0]
+	for _, patch := range patches {
+		if err := applyYAMLPatch(root, schema.SplitPatchPath(patch.Path), patch); err != nil {
+			return "", fmt.Errorf("patch %s: %w", patch.Path, err)
+		}
+	}
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(detectYAMLIndent(content))
+	for _, document := range documents {
+		if err := encoder.Encode(document); err != nil {
+			return "", fmt.Errorf("failed to write YAML: %w", err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("failed to write YAML: %w", err)
+	}
+
+	patched := out.String()
+	if !strings.HasSuffix(content, "\n") {
+		patched = strings.TrimSuffix(patched, "\n")
+	}
+	return patched, nil
+}
+
+// applyYAMLPatch applies a patch to the value below keys of node
+func applyYAMLPatch(node *yaml.Node, keys []string, patch core.Patch) error {
+	key, rest := keys[0], keys[1:]
+	op, last := patch.Operation(), len(rest) == 0
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		matched := false
+		for i := 0; i < len(node.Content); i += 2 {
+			if key != schema.PatchWildcard && node.Content[i].Value != key {
+				continue
+			}
+			matched = true
+
+			switch {
+			case last && op == schema.PatchRemove:
+				node.Content = slices.Delete(node.Content, i, i+2)
+				i -= 2
+			case last && op == schema.PatchRename:
+				name := patch.Value.(string)
+				if name != key && yamlKeyIndex(node, name) >= 0 {
+					return fmt.Errorf("cannot rename %q to %q, the key already exists", key, name)
+				}
+				node.Content[i].Value = name
+			case last:
+				value, err := yamlValue(patch.Value)
+				if err != nil {
+					return err
+				}
+				node.Content[i+1] = keepComments(node.Content[i+1], value)
+			default:
+				if err := applyYAMLPatch(node.Content[i+1], rest, patch); err != nil {
+					return err
+				}
+			}
+		}
+		if matched || key == schema.PatchWildcard || op != schema.PatchSet || slices.Contains(rest, schema.PatchWildcard) {
+			return nil // Wildcards only reach existing values
+		}
+
+		child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		if last {
+			var err error
+			if child, err = yamlValue(patch.Value); err != nil {
+				return err
+			}
+		} else if err := applyYAMLPatch(child, rest, patch); err != nil {
+			return err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, child)
+		return nil
+
+	case yaml.SequenceNode:
+		if last && op == schema.PatchRename {
+			return fmt.Errorf("cannot rename the elements of a sequence")
+		}
+		if key == schema.PatchWildcard {
+			if last && op == schema.PatchRemove {
+				node.Content = nil
+				return nil
+			}
+			for i := range node.Content {
+				if err := yamlSetOrRecurse(node, i, rest, patch); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		index, err := strconv.Atoi(key)
+		appending := index == len(node.Content)
+		if err != nil || index < 0 || index > len(node.Content) || (appending && (op != schema.PatchSet || !last)) {
+			if op != schema.PatchSet {
+				return nil
+			}
+			return fmt.Errorf("invalid index %q for a sequence of %d values", key, len(node.Content))
+		}
+		if last && op == schema.PatchRemove {
+			node.Content = slices.Delete(node.Content, index, index+1)
+			return nil
+		}
+		if appending {
+			node.Content = append(node.Content, nil)
+		}
+		return yamlSetOrRecurse(node, index, rest, patch)
+
+	case yaml.AliasNode:
+		return applyYAMLPatch(node.Alias, keys, patch)
+
+	default:
+		if op != schema.PatchSet {
+			return nil
+		}
+		return fmt.Errorf("cannot set %q below a value that is not a mapping or sequence", key)
+	}
+}
+
+// yamlSetOrRecurse sets the element at index of a sequence when rest is empty, or patches
+// below it otherwise
+func yamlSetOrRecurse(node *yaml.Node, index int, rest []string, patch core.Patch) error {
+	if len(rest) > 0 {
+		return applyYAMLPatch(node.Content[index], rest, patch)
+	}
+	value, err := yamlValue(patch.Value)
+	if err != nil {
+		return err
+	}
+	node.Content[index] = keepComments(node.Content[index], value)
+	return nil
+}
+
+// keepComments moves the comments of a replaced node to the node replacing it
+func keepComments(replaced, value *yaml.Node) *yaml.Node {
+	if replaced != nil {
+		value.HeadComment, value.LineComment, value.FootComment =
+			replaced.HeadComment, replaced.LineComment, replaced.FootComment
+	}
+	return value
+}
+
+// yamlValue encodes a patch value as a YAML node
+func yamlValue(value any) (*yaml.Node, error) {
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return nil, fmt.Errorf("failed to encode value: %w", err)
+	}
+	return &node, nil
+}
+
+// yamlKeyIndex returns the index of key in the content of a mapping node, -1 when missing
+func yamlKeyIndex(node *yaml.Node, key string) int {
+	for i := 0; i < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// detectYAMLIndent returns the number of spaces the first nested line of a YAML file is
+// indented with, two when there is none
+func detectYAMLIndent(content string) int {
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") && len(trimmed) < len(line) {
+			return len(line) - len(trimmed)
+		}
+	}
+	return 2
+}
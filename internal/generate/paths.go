@@ -0,0 +1,125 @@
+package generate
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxNameLength is the longest file name most filesystems accept, in bytes
+	maxNameLength = 255
+	// maxWindowsPath is the longest path Windows tools accept without long paths enabled
+	// (MAX_PATH, 260 characters with the terminating NUL)
+	maxWindowsPath = 259
+)
+
+// windowsReservedNames are the device names Windows reserves, with or without an extension
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true,
+	"COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true,
+	"LPT8": true, "LPT9": true,
+}
+
+// PathProblem is an output path that cannot be written
+type PathProblem struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// InvalidPathsError lists every output path that cannot be written, found before generation
+// writes anything
+type InvalidPathsError struct {
+	Problems []PathProblem
+}
+
+func (e *InvalidPathsError) Error() string {
+	lines := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		lines[i] = fmt.Sprintf("  %s: %s", problem.Path, problem.Reason)
+	}
+	return fmt.Sprintf("%d invalid output paths:\n%s", len(e.Problems), strings.Join(lines, "\n"))
+}
+
+// SetPortablePaths checks output paths against the Windows rules (reserved names, invalid
+// characters and the 260 character path limit) on every OS, so projects generated elsewhere can
+// still be checked out on Windows. They are always checked on Windows.
+func (g *Generator) SetPortablePaths(portable bool) {
+	g.portablePaths = portable
+}
+
+// checkPaths checks every output path of the project, returning an *InvalidPathsError listing
+// those that cannot be written
+func (g *Generator) checkPaths(paths []string) error {
+	windows := g.portablePaths || runtime.GOOS == "windows"
+	root := ""
+	if dir, ok := g.output.(dirOutput); ok {
+		if abs, err := filepath.Abs(dir.dir); err == nil {
+			root = abs
+		}
+	}
+
+	var problems []PathProblem
+	for _, p := range paths {
+		if reason := checkPath(p, windows); reason != "" {
+			problems = append(problems, PathProblem{Path: p, Reason: reason})
+			continue
+		}
+		if windows {
+			full := p
+			if root != "" {
+				full = root + `\` + strings.ReplaceAll(p, "/", `\`)
+			}
+			if n := utf8.RuneCountInString(full); n > maxWindowsPath {
+				problems = append(problems, PathProblem{Path: p, Reason: fmt.Sprintf(
+					"path is %d characters long, over the %d Windows allows without long paths enabled",
+					n, maxWindowsPath)})
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return &InvalidPathsError{Problems: problems}
+	}
+	return nil
+}
+
+// checkPath returns why the slash-separated relative path p cannot be written, "" if it can:
+// it must stay inside the project, as schemas may come from registries. With windows set, the
+// Windows naming rules apply too.
+func checkPath(p string, windows bool) string {
+	if !filepath.IsLocal(filepath.FromSlash(p)) {
+		return "path leaves the project directory"
+	}
+	for _, name := range strings.Split(path.Clean(p), "/") {
+		if strings.ContainsRune(name, 0) {
+			return fmt.Sprintf("name %q contains a NUL character", name)
+		}
+		if len(name) > maxNameLength {
+			return fmt.Sprintf("a name is %d bytes long, over the %d filesystems allow", len(name), maxNameLength)
+		}
+		if !windows {
+			continue
+		}
+		if i := strings.IndexFunc(name, invalidWindowsRune); i >= 0 {
+			return fmt.Sprintf("name %q contains %q, invalid on Windows", name, name[i])
+		}
+		if strings.HasSuffix(name, ".") || strings.HasSuffix(name, " ") {
+			return fmt.Sprintf("name %q ends with a dot or a space, invalid on Windows", name)
+		}
+		base, _, _ := strings.Cut(name, ".")
+		if windowsReservedNames[strings.ToUpper(strings.TrimRight(base, " "))] {
+			return fmt.Sprintf("name %q is reserved on Windows", name)
+		}
+	}
+	return ""
+}
+
+// invalidWindowsRune reports whether r cannot appear in Windows file names
+func invalidWindowsRune(r rune) bool {
+	return r < 32 || strings.ContainsRune(`<>:"|?*\`, r)
+}
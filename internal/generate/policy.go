@@ -0,0 +1,46 @@
+package generate
+
+import (
+	"context"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/policy"
+)
+
+// SetPolicies rejects generation requests violating any of the policies, with a
+// *policy.ViolationError listing every violation, before anything is written
+func (g *Generator) SetPolicies(policies ...policy.Policy) {
+	g.policies = policies
+}
+
+// enforcePolicies checks the generation request against the policies, once the variables are
+// validated and the features resolved
+func (g *Generator) enforcePolicies(ctx context.Context) error {
+	if len(g.policies) == 0 {
+		return nil
+	}
+
+	variables := map[string]string{}
+	for name, value := range g.variables.Custom {
+		variables[name] = value
+	}
+	variables["ProjectName"] = g.variables.ProjectName
+	variables["GitHubRepo"] = g.variables.GitHubRepo
+	variables["Author"] = g.variables.Author
+	variables["Description"] = g.variables.Description
+
+	repo, _ := core.ParseGitHubRepo(g.variables.GitHubRepo) // Validated with the variables
+	return policy.Enforce(ctx, policy.Request{
+		Schema:      g.schema.Name,
+		Type:        g.schema.Type,
+		Version:     g.schema.Version,
+		Origin:      g.schema.Origin,
+		ProjectName: g.variables.ProjectName,
+		GitHubRepo:  g.variables.GitHubRepo,
+		RepoOwner:   repo.Owner,
+		RepoName:    repo.Name,
+		Variables:   variables,
+		Features:    g.result.Features,
+		OutputDir:   g.outputDir,
+	}, g.policies...)
+}
@@ -0,0 +1,277 @@
+package generate
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// renderer renders templated files. Only actions that reference known project variables
+// and engine functions are executed, along with range blocks over list and map variables;
+// every other action (Helm, Vue, Go templates in the reference project itself) is kept
+// verbatim by turning it into a quoted string literal.
+type renderer struct {
+	funcs template.FuncMap
+	data  map[string]any
+}
+
+// newRenderer creates a renderer executing templates against data
+func newRenderer(funcs template.FuncMap, data map[string]any) *renderer {
+	return &renderer{funcs: funcs, data: data}
+}
+
+// render parses content once with the given delimiters and executes it
+func (r *renderer) render(content, left, right string) (string, error) {
+	var buf bytes.Buffer
+	if err := r.renderTo(&buf, content, left, right); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// renderTo renders content like render, writing the output to w as it is executed
+func (r *renderer) renderTo(w io.Writer, content, left, right string) error {
+	prepared := r.escapeForeignActions(content, left, right)
+
+	tmpl, err := template.New("file").Delims(left, right).Funcs(r.funcs).Option("missingkey=error").Parse(prepared)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	if err := tmpl.Execute(w, r.data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	return nil
+}
+
+// block is a block action (range, if, with, ...) left open while escaping a file
+type block struct {
+	// project is set for range blocks over project variables, which are executed
+	project bool
+	// vars holds the variables a project range declares, e.g. $name and $port
+	vars []string
+}
+
+// rangeScope is what the project range blocks around an action make visible: the item (.) and
+// the variables the ranges declare
+type rangeScope struct {
+	dot  bool
+	vars []string
+}
+
+// scope returns what the open project range blocks make visible. Foreign blocks are printed
+// rather than executed, so they never change the item.
+func scope(blocks []block) rangeScope {
+	var scope rangeScope
+	for _, b := range blocks {
+		if b.project {
+			scope.dot = true
+			scope.vars = append(scope.vars, b.vars...)
+		}
+	}
+	return scope
+}
+
+// escapeForeignActions rewrites every action that is not a project action into an action
+// printing its original text, so a single parse renders the file faithfully. Block actions
+// are tracked so the else and end of a project range are kept and those of foreign blocks
+// are not.
+func (r *renderer) escapeForeignActions(content, left, right string) string {
+	var out strings.Builder
+	var blocks []block
+
+	for {
+		start := strings.Index(content, left)
+		if start < 0 {
+			out.WriteString(content)
+			return out.String()
+		}
+
+		out.WriteString(content[:start])
+		end := findActionEnd(content, start+len(left), right)
+		if end < 0 {
+			// Unterminated action: the rest of the file is literal text
+			out.WriteString(quoteAction(content[start:], left, right))
+			return out.String()
+		}
+
+		action := content[start : end+len(right)]
+		if r.keepAction(action, left, right, &blocks) {
+			out.WriteString(action)
+		} else {
+			out.WriteString(quoteAction(action, left, right))
+		}
+		content = content[end+len(right):]
+	}
+}
+
+// findActionEnd returns the index of the right delimiter closing an action, skipping
+// delimiters that appear inside quoted strings, or -1 if the action is unterminated
+func findActionEnd(content string, from int, right string) int {
+	var quote byte
+	for i := from; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote != '`' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '`' || c == '\'':
+			quote = c
+		case strings.HasPrefix(content[i:], right):
+			return i
+		}
+	}
+	return -1
+}
+
+// quoteAction wraps literal text in an action that prints it unchanged
+func quoteAction(text, left, right string) string {
+	return left + strconv.Quote(text) + right
+}
+
+// keepAction reports whether action is executed, opening and closing the blocks of block actions
+func (r *renderer) keepAction(action, left, right string, blocks *[]block) bool {
+	innermost := func() bool { return len(*blocks) > 0 && (*blocks)[len(*blocks)-1].project }
+
+	switch actionKeyword(action, left, right) {
+	case "range":
+		vars, ok := r.projectRange(action, left, right, scope(*blocks))
+		*blocks = append(*blocks, block{project: ok, vars: vars})
+		return ok
+	case "if", "with", "block", "define":
+		*blocks = append(*blocks, block{})
+		return false
+	case "else", "break", "continue":
+		return innermost()
+	case "end":
+		project := innermost()
+		if len(*blocks) > 0 {
+			*blocks = (*blocks)[:len(*blocks)-1]
+		}
+		return project
+	default:
+		return r.isProjectAction(action, left, right, scope(*blocks))
+	}
+}
+
+// actionKeyword returns the first word of an action, such as range or end
+func actionKeyword(action, left, right string) string {
+	text := strings.TrimSuffix(strings.TrimPrefix(action, left), right)
+	if trimmed, ok := strings.CutPrefix(text, "-"); ok && strings.TrimSpace(trimmed) != trimmed {
+		text = trimmed
+	}
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// projectRange reports whether action opens a range over a list or map variable, returning
+// the variables it declares
+func (r *renderer) projectRange(action, left, right string, scope rangeScope) ([]string, bool) {
+	trees, err := parse.Parse("action", action+left+"end"+right, left, right, r.funcs)
+	if err != nil {
+		return nil, false
+	}
+	root := trees["action"].Root
+	if len(root.Nodes) != 1 {
+		return nil, false
+	}
+	node, ok := root.Nodes[0].(*parse.RangeNode)
+	if !ok || len(node.Pipe.Cmds) != 1 || len(node.Pipe.Cmds[0].Args) != 1 {
+		return nil, false
+	}
+
+	field, ok := node.Pipe.Cmds[0].Args[0].(*parse.FieldNode)
+	if !ok || len(field.Ident) != 1 || scope.dot {
+		return nil, false // Fields of the item are not project variables
+	}
+	switch r.data[field.Ident[0]].(type) {
+	case []string, map[string]string:
+	default:
+		return nil, false
+	}
+
+	var vars []string
+	for _, variable := range node.Pipe.Decl {
+		vars = append(vars, variable.Ident[0])
+	}
+	return vars, true
+}
+
+// isProjectAction reports whether action is a single pipeline built only from known
+// variables, engine functions and literals, or the item and variables of the ranges around it
+func (r *renderer) isProjectAction(action, left, right string, scope rangeScope) bool {
+	// Declare the variables of the ranges so the action parses on its own
+	var declarations strings.Builder
+	for _, variable := range scope.vars {
+		declarations.WriteString(left + variable + ` := ""` + right)
+	}
+	trees, err := parse.Parse("action", declarations.String()+action, left, right, r.funcs)
+	if err != nil {
+		return false
+	}
+
+	root := trees["action"].Root
+	if len(root.Nodes) != len(scope.vars)+1 {
+		return false
+	}
+
+	node, ok := root.Nodes[len(scope.vars)].(*parse.ActionNode)
+	if !ok || len(node.Pipe.Decl) > 0 {
+		return false
+	}
+
+	return r.isProjectPipe(node.Pipe, scope)
+}
+
+// isProjectPipe checks every command and argument of a pipeline
+func (r *renderer) isProjectPipe(pipe *parse.PipeNode, scope rangeScope) bool {
+	for _, cmd := range pipe.Cmds {
+		for _, arg := range cmd.Args {
+			if !r.isProjectArg(arg, scope) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isProjectArg reports whether a single pipeline argument is allowed. Within ranges the
+// project variables are reached through $ (e.g. $.ProjectName), . being the item.
+func (r *renderer) isProjectArg(arg parse.Node, scope rangeScope) bool {
+	switch n := arg.(type) {
+	case *parse.FieldNode:
+		if len(n.Ident) != 1 || scope.dot {
+			return false
+		}
+		_, known := r.data[n.Ident[0]]
+		return known
+	case *parse.DotNode:
+		return scope.dot
+	case *parse.VariableNode:
+		if len(n.Ident) == 2 && n.Ident[0] == "$" {
+			_, known := r.data[n.Ident[1]]
+			return known
+		}
+		return len(n.Ident) == 1 && slices.Contains(scope.vars, n.Ident[0])
+	case *parse.IdentifierNode:
+		_, known := r.funcs[n.Ident]
+		return known
+	case *parse.StringNode, *parse.NumberNode, *parse.BoolNode:
+		return true
+	case *parse.PipeNode:
+		return len(n.Decl) == 0 && r.isProjectPipe(n, scope)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,113 @@
+package generate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRendererRender(t *testing.T) {
+	r := newRenderer(TemplateFuncs(), map[string]any{
+		"ProjectName": "My App",
+		"GitHubRepo":  "user/my-app",
+		"Services":    []string{"auth", "billing"},
+		"Ports":       map[string]string{"api": "8080", "web": "3000"},
+	})
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "compact and spaced actions",
+			content:  "{{.ProjectName}} {{ .ProjectName }} {{ .ProjectName | kebab }} {{.GitHubRepo|upper}}",
+			expected: "My App My App my-app USER/MY-APP",
+		},
+		{
+			name:     "trim markers",
+			content:  "a  {{- .ProjectName -}}  b",
+			expected: "aMy Appb",
+		},
+		{
+			name:     "function arguments",
+			content:  `{{ .ProjectName | replace " " "_" | trimSuffix "_App" }}`,
+			expected: "My",
+		},
+		{
+			name:     "helm syntax is preserved",
+			content:  "{{ include \"chart.labels\" . | nindent 4 }}\nimage: {{ .Values.image }}",
+			expected: "{{ include \"chart.labels\" . | nindent 4 }}\nimage: {{ .Values.image }}",
+		},
+		{
+			name:     "go template control flow is preserved",
+			content:  "{{range .Items}}{{.Name}}{{end}} {{ $x := .ProjectName }}",
+			expected: "{{range .Items}}{{.Name}}{{end}} {{ $x := .ProjectName }}",
+		},
+		{
+			name:     "comments and dot are preserved",
+			content:  "{{/* note */}}{{ . }}",
+			expected: "{{/* note */}}{{ . }}",
+		},
+		{
+			name:     "delimiters inside quotes",
+			content:  `{{ printf "}}" }} {{ .ProjectName | replace "}}" "x" }}`,
+			expected: `{{ printf "}}" }} My App`,
+		},
+		{
+			name:     "unterminated action",
+			content:  "{{.ProjectName}} and {{ broken",
+			expected: "My App and {{ broken",
+		},
+		{
+			name:     "range over a list variable",
+			content:  "{{range .Services}}- {{.}} of {{$.ProjectName}}\n{{end}}{{.Services | join \",\"}}",
+			expected: "- auth of My App\n- billing of My App\nauth,billing",
+		},
+		{
+			name:     "range over a map variable with declared variables",
+			content:  "{{- range $name, $port := .Ports }}{{ $name | upper }}={{ $port }};{{ end -}}",
+			expected: "API=8080;WEB=3000;",
+		},
+		{
+			name: "foreign actions within a range are preserved",
+			content: "{{range .Services}}{{if .Values.enabled}}{{.}} {{.ProjectName}}{{end}}" +
+				"{{else}}none{{end}} {{ . }}",
+			expected: "{{if .Values.enabled}}auth {{.ProjectName}}{{end}}" +
+				"{{if .Values.enabled}}billing {{.ProjectName}}{{end}} {{ . }}",
+		},
+		{
+			name:     "range over a string variable is preserved",
+			content:  "{{range .ProjectName}}{{.}}{{end}}",
+			expected: "{{range .ProjectName}}{{.}}{{end}}",
+		},
+		{
+			name:     "unknown variable is preserved",
+			content:  "{{.Team}}",
+			expected: "{{.Team}}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.render(tt.content, "{{", "}}")
+			if err != nil {
+				t.Fatalf("render() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("render() mismatch.\nExpected: %q\nGot: %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func BenchmarkRendererRender(b *testing.B) {
+	r := newRenderer(TemplateFuncs(), map[string]any{"ProjectName": "My App", "GitHubRepo": "user/my-app"})
+	content := strings.Repeat("# {{.ProjectName}}\nimage: {{ .Values.image }} from {{ .GitHubRepo | lower }}\n", 500)
+
+	b.SetBytes(int64(len(content)))
+	for i := 0; i < b.N; i++ {
+		if _, err := r.render(content, "{{", "}}"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -1,18 +1,68 @@
 package generate
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/acheevo/template-engine/internal/archive"
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/policy"
 )
 
-// RunWithParams generates a project with specified parameters (called by cobra command)
-func RunWithParams(templateFile, outputDir, projectName, githubRepo string) error {
-	fmt.Printf("Generating project from %s\n", templateFile)
-	fmt.Printf("Project name: %s\n", projectName)
-	fmt.Printf("GitHub repo: %s\n", githubRepo)
-	fmt.Printf("Output dir: %s\n", outputDir)
+// Params holds the inputs of a generation run
+type Params struct {
+	TemplateFile string
+	OutputDir    string
+	ProjectName  string
+	GitHubRepo   string
+	// Variables sets further template variables (Author, Description) by name
+	Variables map[string]string
+	// Env configures the env files written from the schema's EnvConfig
+	Env EnvOptions
+	// Hooks runs the schema's pre_generate and post_generate hooks, not for archive output
+	Hooks HookOptions
+	// NoVerify skips file hash verification for intentionally edited schemas
+	NoVerify bool
+	// Only generates just the schema files matching these globs. The output directory may then
+	// already exist, as long as none of the selected files does.
+	Only []string
+	// Packages generates just these workspace packages of a monorepo schema, by name or directory
+	Packages []string
+	// OutputFormat packages the project as an archive (tar.gz or zip) instead of a directory.
+	// OutputDir then names the archive file, "-" for stdout.
+	OutputFormat string
+	// Overwrite decides what to do when OutputDir exists: fail (the default) or merge into it
+	Overwrite OverwritePolicy
+	// MaterializeSymlinks writes copies of the files symlinks point to instead of the symlinks
+	MaterializeSymlinks bool
+	// License writes this license to LICENSE and the schema's copyright header into source files
+	License License
+	// GoMod configures the rewrite of the go.mod files declared by the schema
+	GoMod GoModOptions
+	// WarnMissingMappings warns about required mappings not found instead of failing
+	WarnMissingMappings bool
+	// Enable and Disable switch optional features of the schema on and off by name, the others
+	// keeping their default
+	Enable  []string
+	Disable []string
+	// Verify runs the schema's verify hooks once the project is written, see Result.Verification
+	Verify bool
+	// Offline forbids network access, see Generator.SetOffline
+	Offline bool
+	// PortablePaths checks output paths against the Windows rules on every OS
+	PortablePaths bool
+	// Policies reject generation requests violating organization rules, see Generator.SetPolicies
+	Policies []policy.Policy
+}
 
-	return generate(templateFile, outputDir, projectName, githubRepo)
+// RunWithParams generates a project with specified parameters (called by cobra command)
+func RunWithParams(ctx context.Context, logger *slog.Logger, params Params) (*Result, error) {
+	return generate(ctx, logger, params)
 }
 
 // Run generates a project using command line argument parsing (legacy)
@@ -62,38 +112,154 @@ func Run() error {
 		return fmt.Errorf("--github-repo is required")
 	}
 
-	fmt.Printf("Generating project from %s\n", templateFile)
-	fmt.Printf("Project name: %s\n", projectName)
-	fmt.Printf("GitHub repo: %s\n", githubRepo)
-	fmt.Printf("Output dir: %s\n", outputDir)
-
-	return generate(templateFile, outputDir, projectName, githubRepo)
+	_, err := generate(context.Background(), slog.Default(), Params{
+		TemplateFile: templateFile,
+		OutputDir:    outputDir,
+		ProjectName:  projectName,
+		GitHubRepo:   githubRepo,
+	})
+	return err
 }
 
-func generate(templateFile, outputDir, projectName, githubRepo string) error {
+func generate(ctx context.Context, logger *slog.Logger, params Params) (*Result, error) {
+	logger.Info("Generating project",
+		"template", params.TemplateFile,
+		"project_name", params.ProjectName,
+		"github_repo", params.GitHubRepo,
+		"output", params.OutputDir)
+
 	// Check if template file exists
-	if _, err := os.Stat(templateFile); os.IsNotExist(err) {
-		return fmt.Errorf("template file does not exist: %s", templateFile)
+	if _, err := os.Stat(params.TemplateFile); os.IsNotExist(err) {
+		return nil, fmt.Errorf("template file does not exist: %s", params.TemplateFile)
 	}
 
-	// Check if output directory already exists
-	if _, err := os.Stat(outputDir); err == nil {
-		return fmt.Errorf("output directory already exists: %s", outputDir)
+	var format archive.Format
+	if params.OutputFormat != "" {
+		parsed, err := archive.ParseFormat(params.OutputFormat)
+		if err != nil {
+			return nil, err
+		}
+		format = parsed
+		params.OutputDir = archivePath(params.OutputDir, params.ProjectName, format)
+	}
+
+	merge := params.Overwrite == OverwriteMerge
+	if merge && format != "" {
+		return nil, fmt.Errorf("merging into an existing output is not supported for archives")
+	}
+
+	// Partial generation may add files to an existing project, anything else needs a fresh output
+	// unless merging into it
+	partial := len(params.Only) > 0 && format == ""
+	if _, err := os.Stat(params.OutputDir); err == nil && !partial && !merge {
+		return nil, fmt.Errorf("output already exists: %s (use --overwrite merge to regenerate into it)",
+			params.OutputDir)
 	}
 
 	// Create generator
-	generator, err := NewGenerator(templateFile, outputDir, projectName, githubRepo)
+	generator, err := NewGenerator(params.TemplateFile, core.TemplateVariables{
+		ProjectName: params.ProjectName,
+		GitHubRepo:  params.GitHubRepo,
+	}, params.OutputDir)
 	if err != nil {
-		return fmt.Errorf("failed to create generator: %w", err)
+		return nil, fmt.Errorf("failed to create generator: %w", err)
+	}
+	generator.SetFileFilter(params.Only)
+	generator.SetPackages(params.Packages)
+	generator.SetFeatures(params.Enable, params.Disable)
+	generator.SetOverwritePolicy(params.Overwrite)
+	generator.SetMaterializeSymlinks(params.MaterializeSymlinks)
+	generator.SetLicense(params.License)
+	generator.SetGoModOptions(params.GoMod)
+	generator.SetWarnMissingMappings(params.WarnMissingMappings)
+	if partial && !merge {
+		if err := checkExistingFiles(params.OutputDir, generator.PlannedFiles()); err != nil {
+			return nil, err
+		}
+	}
+	for name, value := range params.Variables {
+		if err := generator.SetVariable(name, value); err != nil {
+			return nil, err
+		}
+	}
+	generator.SetLogger(logger)
+	generator.SetEnvOptions(params.Env)
+	generator.SetHookOptions(params.Hooks)
+	generator.SetVerify(params.Verify)
+	generator.SetOffline(params.Offline)
+	generator.SetPortablePaths(params.PortablePaths)
+	generator.SetPolicies(params.Policies...)
+	generator.SetValidateOptions(core.ValidateOptions{SkipHashes: params.NoVerify})
+	if params.NoVerify {
+		logger.Warn("Skipping file hash verification")
 	}
 
 	// Generate project
-	if err := generator.Generate(); err != nil {
-		return fmt.Errorf("failed to generate project: %w", err)
+	if format != "" {
+		err = generateArchive(ctx, generator, params.OutputDir, format)
+	} else {
+		err = generator.Generate(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate project: %w", err)
 	}
 
 	// Print summary
 	generator.PrintSummary()
 
+	return generator.Result(), nil
+}
+
+// checkExistingFiles refuses to overwrite files of an existing project
+func checkExistingFiles(outputDir string, paths []string) error {
+	for _, path := range paths {
+		if _, err := os.Lstat(filepath.Join(outputDir, filepath.FromSlash(path))); err == nil {
+			return fmt.Errorf("file already exists: %s", filepath.Join(outputDir, path))
+		}
+	}
 	return nil
 }
+
+// archivePath returns the archive file written for output: inside output when it is an
+// existing directory, otherwise output itself with the format's extension
+func archivePath(output, projectName string, format archive.Format) string {
+	if output == "-" {
+		return output
+	}
+	if info, err := os.Stat(output); err == nil && info.IsDir() {
+		return filepath.Join(output, core.KebabCase(projectName)+format.Extension())
+	}
+	if !strings.HasSuffix(output, format.Extension()) {
+		output += format.Extension()
+	}
+	return output
+}
+
+// generateArchive streams the generated project into an archive at path, or stdout for "-".
+// A partially written archive is removed when generation fails.
+func generateArchive(ctx context.Context, generator *Generator, path string, format archive.Format) error {
+	var w io.Writer = os.Stdout
+	if path != "-" {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	archiveWriter, err := archive.NewWriter(w, format)
+	if err != nil {
+		return err
+	}
+	generator.SetOutput(archiveWriter)
+
+	err = generator.Generate(ctx)
+	if err == nil {
+		err = archiveWriter.Close()
+	}
+	if err != nil && path != "-" {
+		_ = os.Remove(path) // Don't leave a truncated archive behind
+	}
+	return err
+}
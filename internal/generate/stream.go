@@ -0,0 +1,89 @@
+package generate
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+// streams reports whether a file is written to the output as its content is decompressed or
+// rendered rather than held in memory: the output supports it, nothing rewrites the content
+// afterwards and merging does not compare it with the existing file
+func (g *Generator) streams(fileSpec core.FileSpec) bool {
+	if _, ok := g.output.(StreamOutput); !ok || g.overwrite == OverwriteMerge {
+		return false
+	}
+	if fileSpec.IsSymlink() || len(fileSpec.JSONPatch) > 0 || len(fileSpec.YAMLPatch) > 0 {
+		return false
+	}
+	if fileSpec.Encoding != "" {
+		return true // Binary content is written as is
+	}
+
+	filePath := filepath.ToSlash(fileSpec.Path)
+	if len(g.modules) > 0 && (path.Ext(filePath) == ".go" || path.Base(filePath) == "go.mod") {
+		return false
+	}
+	if len(g.packages) > 0 && path.Base(filePath) == "package.json" {
+		return false
+	}
+	_, headed := headerStyles[path.Ext(filePath)]
+	return g.header == "" || !headed
+}
+
+// streamFile writes a file to the output as its content is decompressed, rendering templated
+// files straight into it, and returns the number of bytes written
+func (g *Generator) streamFile(fileSpec core.FileSpec) (int, FileState, error) {
+	file, err := g.output.(StreamOutput).CreateFile(fileSpec.Path, 0o644)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to write file: %w", err)
+	}
+
+	out := &countingWriter{w: file}
+	if err := g.copyContent(out, fileSpec); err != nil {
+		file.Close()
+		return 0, "", err
+	}
+	if err := file.Close(); err != nil {
+		return 0, "", fmt.Errorf("failed to write file: %w", err)
+	}
+	return out.n, FileCreated, nil
+}
+
+// copyContent writes the content of a file to w. Templated files are read whole, their
+// mappings needing the full content, but rendered without buffering the output.
+func (g *Generator) copyContent(w io.Writer, fileSpec core.FileSpec) error {
+	if fileSpec.Template {
+		content, err := core.ResolveContent(g.schema, fileSpec)
+		if err != nil {
+			return fmt.Errorf("failed to decompress content: %w", err)
+		}
+		return g.renderFileTo(w, fileSpec, content)
+	}
+
+	content, err := core.OpenContent(g.schema, fileSpec)
+	if err != nil {
+		return fmt.Errorf("failed to decompress content: %w", err)
+	}
+	defer content.Close()
+
+	if _, err := io.Copy(w, content); err != nil {
+		return fmt.Errorf("failed to copy content: %w", err)
+	}
+	return nil
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += n
+	return n, err
+}
@@ -0,0 +1,55 @@
+package generate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/acheevo/template-engine/internal/hooks"
+)
+
+// VariableEnvPrefix marks environment variables holding template variables, e.g. TE_VAR_ProjectName
+// (the same prefix hooks receive them with)
+const VariableEnvPrefix = hooks.VariablePrefix
+
+// VariablesFromEnviron collects the template variables set as TE_VAR_<Name>=value in environ
+// (as returned by os.Environ)
+func VariablesFromEnviron(environ []string) map[string]string {
+	values := make(map[string]string)
+	for _, entry := range environ {
+		name, value, found := strings.Cut(entry, "=")
+		if !found || !strings.HasPrefix(name, VariableEnvPrefix) {
+			continue
+		}
+		if name = strings.TrimPrefix(name, VariableEnvPrefix); name != "" {
+			values[name] = value
+		}
+	}
+	return values
+}
+
+// ParseVariablesJSON reads template variables from a JSON object of strings,
+// such as {"ProjectName": "My App", "GitHubRepo": "user/my-app"}
+func ParseVariablesJSON(r io.Reader) (map[string]string, error) {
+	var values map[string]string
+	if err := json.NewDecoder(r).Decode(&values); err != nil {
+		return nil, fmt.Errorf("failed to parse variables, expected a JSON object of strings: %w", err)
+	}
+	return values, nil
+}
+
+// ParseVariableAssignments parses NAME=VALUE template variable assignments, such as the values
+// of the --var flag
+func ParseVariableAssignments(assignments []string) (map[string]string, error) {
+	values := make(map[string]string, len(assignments))
+	for _, assignment := range assignments {
+		name, value, found := strings.Cut(assignment, "=")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid variable assignment %q, expected NAME=VALUE", assignment)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
@@ -0,0 +1,37 @@
+package generate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVariablesFromEnviron(t *testing.T) {
+	values := VariablesFromEnviron([]string{
+		"TE_VAR_ProjectName=My App",
+		"TE_VAR_Description=a=b",
+		"TE_VAR_=ignored",
+		"HOME=/root",
+	})
+
+	expected := map[string]string{"ProjectName": "My App", "Description": "a=b"}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("VariablesFromEnviron() = %v, want %v", values, expected)
+	}
+}
+
+func TestParseVariablesJSON(t *testing.T) {
+	values, err := ParseVariablesJSON(strings.NewReader(`{"ProjectName": "My App", "Author": "Jane"}`))
+	if err != nil {
+		t.Fatalf("ParseVariablesJSON() error = %v", err)
+	}
+	if values["ProjectName"] != "My App" || values["Author"] != "Jane" {
+		t.Errorf("ParseVariablesJSON() = %v", values)
+	}
+
+	for _, invalid := range []string{`["My App"]`, `{"ProjectName": 1}`, ``} {
+		if _, err := ParseVariablesJSON(strings.NewReader(invalid)); err == nil {
+			t.Errorf("ParseVariablesJSON(%q) should fail", invalid)
+		}
+	}
+}
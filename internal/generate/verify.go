@@ -0,0 +1,62 @@
+package generate
+
+import (
+	"context"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/hooks"
+)
+
+// Verification reports the verify hooks run on a generated project
+type Verification struct {
+	// Passed is set when every verify hook that ran exited successfully
+	Passed bool           `json:"passed"`
+	Checks []hooks.Result `json:"checks"`
+}
+
+// Failed returns the checks that ran and failed
+func (v *Verification) Failed() []hooks.Result {
+	var failed []hooks.Result
+	for _, check := range v.Checks {
+		if !check.Skipped && check.ExitCode != 0 {
+			failed = append(failed, check)
+		}
+	}
+	return failed
+}
+
+// SetVerify runs the verify hooks of the schema (see core.HookVerify) once the project is
+// written, reporting them in Result.Verification. Failing checks do not fail generation. Verify
+// hooks follow the hook policy, but also run when the other hooks are disabled.
+func (g *Generator) SetVerify(verify bool) {
+	g.verify = verify
+}
+
+// verifyProject runs every verify hook in the output directory when verification is enabled
+func (g *Generator) verifyProject(ctx context.Context) error {
+	dir, ok := g.output.(dirOutput)
+	if !ok || !g.verify {
+		return nil
+	}
+	disabled, err := g.disabledFeatures()
+	if err != nil {
+		return err
+	}
+	checks := featureHooks(g.schema.Hooks.ForStage(core.HookVerify), disabled)
+	if len(checks) == 0 {
+		return nil
+	}
+	if g.schema.IsRemote() && !g.hooks.AllowRemote {
+		g.logger.Warn("Skipping verification of remote schema, review its hooks and pass --allow-hooks to run them",
+			"origin", g.schema.Origin)
+		return nil
+	}
+
+	opts := g.hooks.runOptions(dir.dir, g.Variables())
+	opts.KeepGoing = true
+	g.hookEvents(&opts)
+	results, err := hooks.Run(ctx, g.logger, checks, opts)
+	g.result.Verification = &Verification{Checks: results}
+	g.result.Verification.Passed = err == nil && len(g.result.Verification.Failed()) == 0
+	return err
+}
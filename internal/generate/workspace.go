@@ -0,0 +1,86 @@
+package generate
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/pkg/schema"
+)
+
+// dependencyFields are the package.json fields listing the dependencies of a package
+var dependencyFields = []string{"dependencies", "devDependencies", "peerDependencies", "optionalDependencies"}
+
+// SetPackages restricts generation to the workspace packages of the schema (see
+// core.TemplateSchema.Packages) named by their package name or directory. The other packages
+// are left out, along with the dependencies of the generated package.json files on them;
+// files outside every package are always generated.
+func (g *Generator) SetPackages(names []string) {
+	g.packages = names
+}
+
+// excludedPackages returns the workspace packages left out by the package selection, none
+// without a selection
+func (g *Generator) excludedPackages() ([]core.WorkspacePackage, error) {
+	if len(g.packages) == 0 {
+		return nil, nil
+	}
+	for _, name := range g.packages {
+		if !slices.ContainsFunc(g.schema.Packages, func(pkg core.WorkspacePackage) bool {
+			return pkg.Name == name || pkg.Dir == strings.TrimSuffix(name, "/")
+		}) {
+			return nil, fmt.Errorf("unknown workspace package %q", name)
+		}
+	}
+
+	var excluded []core.WorkspacePackage
+	for _, pkg := range g.schema.Packages {
+		if !slices.Contains(g.packages, pkg.Name) && !slices.Contains(g.packages, pkg.Dir) &&
+			!slices.Contains(g.packages, pkg.Dir+"/") {
+			excluded = append(excluded, pkg)
+		}
+	}
+	return excluded, nil
+}
+
+// dropPackages removes the files of the excluded packages from files
+func dropPackages(files []core.FileSpec, excluded []core.WorkspacePackage) []core.FileSpec {
+	if len(excluded) == 0 {
+		return files
+	}
+	return slices.DeleteFunc(slices.Clone(files), func(file core.FileSpec) bool {
+		return slices.ContainsFunc(excluded, func(pkg core.WorkspacePackage) bool {
+			return strings.HasPrefix(filepath.ToSlash(file.Path), pkg.Dir+"/")
+		})
+	})
+}
+
+// removeDependencies removes the dependencies on the excluded packages from the content of a
+// package.json. It runs before mappings rename the packages, on the reference names.
+func (g *Generator) removeDependencies(fileSpec core.FileSpec, content string) (string, error) {
+	if path.Base(filepath.ToSlash(fileSpec.Path)) != "package.json" || fileSpec.Encoding != "" {
+		return content, nil
+	}
+	excluded, err := g.excludedPackages()
+	if err != nil {
+		return "", err
+	}
+
+	var removals []core.Patch
+	for _, pkg := range excluded {
+		if !strings.Contains(content, `"`+pkg.Name+`"`) {
+			continue
+		}
+		key := strings.ReplaceAll(pkg.Name, ".", `\.`)
+		for _, field := range dependencyFields {
+			removals = append(removals, core.Patch{Op: schema.PatchRemove, Path: field + "." + key})
+		}
+	}
+	if len(removals) == 0 {
+		return content, nil
+	}
+	return patchJSON(content, removals)
+}
@@ -0,0 +1,104 @@
+// Package gitcache keeps checkouts of remote reference projects in the user cache directory
+package gitcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/acheevo/template-engine/internal/download"
+)
+
+// Dir returns the directory of the cached checkout of a remote repository
+func Dir(url string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate cache directory: %w", err)
+	}
+
+	sum := sha256.Sum256([]byte(url))
+	name := strings.TrimSuffix(path.Base(strings.TrimRight(url, "/")), ".git")
+	return filepath.Join(cacheDir, "template-engine", "references", name+"-"+hex.EncodeToString(sum[:6])), nil
+}
+
+// IsURL reports whether s names a remote git repository rather than a local directory
+func IsURL(s string) bool {
+	for _, prefix := range []string{"https://", "http://", "ssh://", "git://", "file://", "git@"} {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Checkout clones url into the cache, or fetches the latest changes into an existing clone, and
+// checks out ref: a tag, branch or commit, the remote's default branch when empty. When fetching
+// fails, for example without a connection, an existing checkout is used with a warning. In
+// offline mode the existing checkout is used without fetching, and a missing one fails with
+// download.ErrOffline. It returns the checkout directory.
+func Checkout(ctx context.Context, logger *slog.Logger, url, ref string, offline bool) (string, error) {
+	dir, err := Dir(url)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = os.Stat(filepath.Join(dir, ".git"))
+	switch {
+	case os.IsNotExist(err) && offline:
+		return "", fmt.Errorf("reference project %s is not cached, cannot clone it: %w", url, download.ErrOffline)
+	case os.IsNotExist(err):
+		logger.Info("Cloning reference project", "url", url)
+		if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+			return "", fmt.Errorf("failed to create cache directory: %w", err)
+		}
+		// Network failures are retried with backoff, starting over from a clean directory
+		err := download.Retry(ctx, download.Options{Logger: logger}, func() error {
+			_ = os.RemoveAll(dir) // Leftovers of an interrupted clone
+			return git(ctx, "", "clone", "--quiet", url, dir)
+		})
+		if err != nil {
+			return "", err
+		}
+	case offline:
+		logger.Debug("Offline, using the cached reference project", "url", url)
+	default:
+		logger.Debug("Fetching reference project", "url", url)
+		if err := git(ctx, dir, "fetch", "--quiet", "--tags", "--force", "origin"); err != nil {
+			logger.Warn("Failed to update reference project, using the cached checkout", "url", url, "error", err)
+		}
+	}
+
+	if err := git(ctx, dir, "checkout", "--quiet", "--force", "--detach", revision(ctx, dir, ref)); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// revision resolves ref to what to check out, preferring the fetched remote branch of that name
+// so branches follow their remote
+func revision(ctx context.Context, dir, ref string) string {
+	if ref == "" {
+		return "origin/HEAD"
+	}
+	if git(ctx, dir, "rev-parse", "--verify", "--quiet", "refs/remotes/origin/"+ref) == nil {
+		return "origin/" + ref
+	}
+	return ref
+}
+
+// git runs a git command in dir, returning its output as part of the error when it fails
+func git(ctx context.Context, dir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package gitcache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/acheevo/template-engine/internal/download"
+	"github.com/acheevo/template-engine/internal/logging"
+)
+
+// commit writes README.md in repo and commits it
+func commit(t *testing.T, repo, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(repo, "README.md"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run(t, repo, "add", "README.md")
+	run(t, repo, "-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", content)
+}
+
+func run(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, output)
+	}
+}
+
+func TestCheckout(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	repo := t.TempDir()
+	run(t, repo, "init", "--quiet", "--initial-branch", "main")
+	commit(t, repo, "v1")
+	run(t, repo, "tag", "v1.0.0")
+	commit(t, repo, "v2")
+	url := "file://" + repo
+
+	readme := func(dir string) string {
+		data, err := os.ReadFile(filepath.Join(dir, "README.md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	ctx := context.Background()
+	dir, err := Checkout(ctx, logging.Discard(), url, "v1.0.0", false)
+	if err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	if cached, _ := Dir(url); dir != cached {
+		t.Errorf("Checkout() = %s, want the cache directory %s", dir, cached)
+	}
+	if got := readme(dir); got != "v1" {
+		t.Errorf("Pinned checkout README = %q, want v1", got)
+	}
+
+	// Branches follow their remote once the cached clone is updated
+	commit(t, repo, "v3")
+	if dir, err = Checkout(ctx, logging.Discard(), url, "main", false); err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	if got := readme(dir); got != "v3" {
+		t.Errorf("Branch checkout README = %q, want v3", got)
+	}
+
+	if _, err := Checkout(ctx, logging.Discard(), url, "v9.9.9", false); err == nil {
+		t.Error("Checkout() should fail for an unknown ref")
+	}
+
+	// Offline, the cached clone is used without fetching
+	commit(t, repo, "v4")
+	if dir, err = Checkout(ctx, logging.Discard(), url, "main", true); err != nil {
+		t.Fatalf("Checkout() offline error = %v", err)
+	}
+	if got := readme(dir); got != "v3" {
+		t.Errorf("Offline checkout README = %q, want the cached v3", got)
+	}
+	if _, err := Checkout(ctx, logging.Discard(), "file://"+t.TempDir(), "", true); !errors.Is(err, download.ErrOffline) {
+		t.Errorf("Checkout() offline of an uncached repository error = %v, want ErrOffline", err)
+	}
+}
+
+func TestIsURL(t *testing.T) {
+	for s, want := range map[string]bool{
+		"https://github.com/acheevo/api-template.git": true,
+		"git@github.com:acheevo/api-template.git":     true,
+		"../api-template":        false,
+		"/home/dev/api-template": false,
+	} {
+		if got := IsURL(s); got != want {
+			t.Errorf("IsURL(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
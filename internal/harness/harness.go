@@ -0,0 +1,281 @@
+package harness
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/generate"
+	"github.com/acheevo/template-engine/internal/hooks"
+)
+
+// maxOutput bounds the command output kept for a failing command
+const maxOutput = 4096
+
+// defaultCase is used for schemas without a test matrix
+var defaultCase = core.TestCase{
+	Name: "default",
+	Variables: map[string]string{
+		"ProjectName": "Test Project",
+		"GitHubRepo":  "example/test-project",
+	},
+}
+
+// Options configures a test run
+type Options struct {
+	// Case runs only the test case with this name
+	Case string
+	// KeepDirs leaves the generated projects on disk for inspection
+	KeepDirs bool
+	// CommandTimeout limits each command, no limit when zero
+	CommandTimeout time.Duration
+	// AllowRemote tests schemas pulled from a registry (see core.TemplateSchema.IsRemote), whose
+	// hooks and commands are refused otherwise
+	AllowRemote bool
+	// AllowedBinaries restricts the programs hooks and commands may run (see hooks.CheckHook),
+	// any when empty
+	AllowedBinaries []string
+}
+
+// CaseResult is the outcome of one test matrix entry
+type CaseResult struct {
+	Name       string          `json:"name"`
+	Passed     bool            `json:"passed"`
+	Dir        string          `json:"dir,omitempty"` // Only set with KeepDirs
+	Error      string          `json:"error,omitempty"`
+	Commands   []CommandResult `json:"commands"`
+	DurationMS int64           `json:"duration_ms"`
+}
+
+// CommandResult is the outcome of one verification command
+type CommandResult struct {
+	Command    string `json:"command"`
+	ExitCode   int    `json:"exit_code"`
+	Output     string `json:"output,omitempty"` // Tail of the combined output, only for failures
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// Run generates a project for every test case of schema into a temporary directory and runs the
+// post_generate and verify hooks and the verification commands in it. A failing case does not
+// stop the others; the error is only set when the run itself could not happen, as when a hook or
+// command breaks opts.AllowedBinaries.
+func Run(ctx context.Context, logger *slog.Logger, schema *core.TemplateSchema, opts Options) ([]CaseResult, error) {
+	if err := checkCommands(schema, opts); err != nil {
+		return nil, err
+	}
+
+	cases := schema.TestMatrix
+	if len(cases) == 0 {
+		cases = []core.TestCase{defaultCase}
+	}
+
+	if opts.Case != "" {
+		var selected []core.TestCase
+		for _, testCase := range cases {
+			if testCase.Name == opts.Case {
+				selected = append(selected, testCase)
+			}
+		}
+		if len(selected) == 0 {
+			return nil, fmt.Errorf("test case %q not found", opts.Case)
+		}
+		cases = selected
+	}
+
+	results := make([]CaseResult, 0, len(cases))
+	for _, testCase := range cases {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		logger.Info("Running test case", "case", testCase.Name)
+		result := runCase(ctx, logger, schema, testCase, opts)
+		if result.Passed {
+			logger.Info("Test case passed", "case", result.Name, "duration_ms", result.DurationMS)
+		} else {
+			logger.Error("Test case failed", "case", result.Name, "error", result.Error)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// Passed reports whether every case passed
+func Passed(results []CaseResult) bool {
+	for _, result := range results {
+		if !result.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+func runCase(
+	ctx context.Context, logger *slog.Logger, schema *core.TemplateSchema, testCase core.TestCase, opts Options,
+) CaseResult {
+	start := time.Now()
+	result := CaseResult{Name: testCase.Name, Commands: []CommandResult{}}
+	defer func() {
+		result.DurationMS = time.Since(start).Milliseconds()
+	}()
+
+	tempDir, err := os.MkdirTemp("", "template-engine-test-")
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if opts.KeepDirs {
+		result.Dir = tempDir
+	} else {
+		defer os.RemoveAll(tempDir)
+	}
+
+	projectDir := filepath.Join(tempDir, "project")
+	generator, err := generateCase(ctx, logger, schema, testCase, projectDir)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if opts.KeepDirs {
+		result.Dir = projectDir
+	}
+
+	hookOpts := hooks.Options{Dir: projectDir, Variables: generator.Variables(), AllowedBinaries: opts.AllowedBinaries}
+	projectHooks := append(schema.Hooks.ForStage(core.HookPostGenerate), schema.Hooks.ForStage(core.HookVerify)...)
+	for _, hook := range projectHooks {
+		if hook.Timeout == "" && opts.CommandTimeout > 0 {
+			hook.Timeout = opts.CommandTimeout.String()
+		}
+		logger.Debug("Running hook", "case", testCase.Name, "hook", hook.Label())
+		hookResult := hooks.RunHook(ctx, hook, hookOpts)
+		if hookResult.Skipped {
+			continue
+		}
+		result.Commands = append(result.Commands, CommandResult{
+			Command:    hook.Command,
+			ExitCode:   hookResult.ExitCode,
+			Output:     hookResult.Output,
+			DurationMS: hookResult.DurationMS,
+		})
+		if hookResult.ExitCode != 0 {
+			result.Error = fmt.Sprintf("hook %s exited with code %d", hook.Label(), hookResult.ExitCode)
+			return result
+		}
+	}
+
+	commands := schema.TestCommands
+	if len(testCase.Commands) > 0 {
+		commands = testCase.Commands
+	}
+
+	for _, command := range commands {
+		logger.Debug("Running command", "case", testCase.Name, "command", command)
+		commandResult := runCommand(ctx, projectDir, command, opts.CommandTimeout)
+		result.Commands = append(result.Commands, commandResult)
+		if commandResult.ExitCode != 0 {
+			result.Error = fmt.Sprintf("command %q exited with code %d", command, commandResult.ExitCode)
+			return result
+		}
+	}
+
+	result.Passed = true
+	return result
+}
+
+// generateCase generates the project of a test case into dir
+func generateCase(
+	ctx context.Context, logger *slog.Logger, schema *core.TemplateSchema, testCase core.TestCase, dir string,
+) (*generate.Generator, error) {
+	generator := generate.NewGeneratorFromSchema(schema, core.TemplateVariables{}, dir)
+	generator.SetLogger(logger)
+	for name, value := range defaultCase.Variables {
+		_ = generator.SetVariable(name, value) // Built-in names, cannot fail
+	}
+	for name, value := range testCase.Variables {
+		if err := generator.SetVariable(name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := generator.Generate(ctx); err != nil {
+		return nil, fmt.Errorf("generation failed: %w", err)
+	}
+	return generator, nil
+}
+
+// checkCommands refuses to test remote schemas unless opts.AllowRemote is set, and schemas whose
+// hooks or commands break the hook policy, before anything runs
+func checkCommands(schema *core.TemplateSchema, opts Options) error {
+	if schema.IsRemote() && !opts.AllowRemote {
+		return fmt.Errorf("refusing to run the hooks and commands of remote schema %s (%s), "+
+			"review them and allow remote hooks", schema.Name, schema.Origin)
+	}
+
+	for _, hook := range schema.Hooks {
+		if hook.Stage != core.HookPostGenerate && hook.Stage != core.HookVerify {
+			continue
+		}
+		if err := hooks.CheckHook(hook, opts.AllowedBinaries); err != nil {
+			return fmt.Errorf("hook %s refused by hook policy: %w", hook.Label(), err)
+		}
+	}
+	commands := slices.Clone(schema.TestCommands)
+	for _, testCase := range schema.TestMatrix {
+		commands = append(commands, testCase.Commands...)
+	}
+	for _, command := range commands {
+		if err := hooks.CheckAllowed(command, opts.AllowedBinaries); err != nil {
+			return fmt.Errorf("command %q refused by hook policy: %w", command, err)
+		}
+	}
+	return nil
+}
+
+// runCommand runs a shell command in dir
+func runCommand(ctx context.Context, dir, command string, timeout time.Duration) CommandResult {
+	start := time.Now()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	result := CommandResult{Command: command}
+	if err := cmd.Run(); err != nil {
+		result.ExitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			result.ExitCode = exitErr.ExitCode()
+		}
+		result.Output = tail(output.String(), maxOutput)
+		if result.Output == "" {
+			result.Output = err.Error()
+		}
+	}
+	result.DurationMS = time.Since(start).Milliseconds()
+
+	return result
+}
+
+// tail returns at most the last n bytes of s
+func tail(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return "..." + s[len(s)-n:]
+}
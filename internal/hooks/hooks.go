@@ -0,0 +1,219 @@
+// Package hooks runs the lifecycle hooks declared by template schemas
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+// VariablePrefix prefixes the template variables injected into the hook environment
+// (ProjectName becomes TE_VAR_ProjectName)
+const VariablePrefix = "TE_VAR_"
+
+// maxOutput bounds the output kept in a Result
+const maxOutput = 4096
+
+// waitDelay bounds how long a timed out hook may keep its output open, e.g. through a
+// background process started by the shell
+const waitDelay = time.Second
+
+// Options configures a hook run
+type Options struct {
+	// Dir is the project root the hooks run in
+	Dir string
+	// Variables are the template variables injected into the environment
+	Variables map[string]string
+	// Output receives the combined output of every hook as it runs, discarded when nil
+	Output io.Writer
+	// AllowedBinaries restricts the programs hooks may run (see CheckHook), any when empty
+	AllowedBinaries []string
+	// OnStart and OnResult, when set, are called by Run before and after each hook
+	OnStart  func(hook core.Hook)
+	OnResult func(result Result)
+	// KeepGoing runs the remaining hooks after one fails, for checks that are reported rather
+	// than enforced; Run then only fails when the hooks cannot run
+	KeepGoing bool
+}
+
+// Result is the outcome of one hook
+type Result struct {
+	Name       string         `json:"name,omitempty"`
+	Stage      core.HookStage `json:"stage"`
+	Command    string         `json:"command"`
+	Skipped    bool           `json:"skipped,omitempty"` // Condition did not hold
+	ExitCode   int            `json:"exit_code"`
+	Output     string         `json:"output,omitempty"` // Tail of the combined output, only for failures
+	DurationMS int64          `json:"duration_ms"`
+}
+
+// Run runs hooks in order, stopping at the first one that fails unless opts.KeepGoing is set.
+// The results of the hooks run so far are returned along with the error. Nothing runs when a
+// hook breaks opts.AllowedBinaries.
+func Run(ctx context.Context, logger *slog.Logger, hooks []core.Hook, opts Options) ([]Result, error) {
+	for _, hook := range hooks {
+		if err := CheckHook(hook, opts.AllowedBinaries); err != nil {
+			return nil, fmt.Errorf("hook %s refused by hook policy: %w", hook.Label(), err)
+		}
+	}
+
+	results := make([]Result, 0, len(hooks))
+	for _, hook := range hooks {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		logger.Info("Running hook", "stage", hook.Stage, "hook", hook.Label())
+		if opts.OnStart != nil {
+			opts.OnStart(hook)
+		}
+		result := RunHook(ctx, hook, opts)
+		results = append(results, result)
+		if opts.OnResult != nil {
+			opts.OnResult(result)
+		}
+		if result.Skipped {
+			logger.Debug("Skipped hook, condition not met", "hook", hook.Label(), "condition", hook.Condition)
+			continue
+		}
+		if result.ExitCode != 0 && opts.KeepGoing {
+			logger.Warn("Hook failed", "hook", hook.Label(), "exit_code", result.ExitCode)
+			continue
+		}
+		if result.ExitCode != 0 {
+			return results, fmt.Errorf("hook %s exited with code %d: %s", hook.Label(), result.ExitCode, result.Output)
+		}
+	}
+	return results, nil
+}
+
+// RunHook runs a single hook with sh -c in the project directory
+func RunHook(ctx context.Context, hook core.Hook, opts Options) Result {
+	start := time.Now()
+	result := Result{Name: hook.Name, Stage: hook.Stage, Command: hook.Command}
+	defer func() {
+		result.DurationMS = time.Since(start).Milliseconds()
+	}()
+
+	if err := CheckHook(hook, opts.AllowedBinaries); err != nil {
+		return failed(result, fmt.Errorf("refused by hook policy: %w", err))
+	}
+
+	met, err := conditionMet(hook.Condition, opts.Dir)
+	if err != nil {
+		return failed(result, err)
+	}
+	if !met {
+		result.Skipped = true
+		return result
+	}
+
+	timeout, err := hook.TimeoutDuration()
+	if err != nil {
+		return failed(result, fmt.Errorf("invalid timeout %q: %w", hook.Timeout, err))
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var output bytes.Buffer
+	var stream io.Writer = &output
+	if opts.Output != nil {
+		stream = io.MultiWriter(&output, opts.Output)
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", hook.Command)
+	cmd.Dir = filepath.Join(opts.Dir, filepath.FromSlash(hook.Workdir))
+	cmd.Env = Environ(opts.Variables, hook.Env)
+	cmd.Stdout = stream
+	cmd.Stderr = stream
+	cmd.WaitDelay = waitDelay
+
+	if err := cmd.Run(); err != nil {
+		result.ExitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			result.ExitCode = exitErr.ExitCode()
+		}
+		result.Output = tail(output.String(), maxOutput)
+		if result.Output == "" {
+			result.Output = err.Error()
+		}
+	}
+
+	return result
+}
+
+// Environ returns the environment of a hook: the engine's own environment, then every template
+// variable as TE_VAR_<Name>, then the hook's env entries
+func Environ(variables, env map[string]string) []string {
+	environ := os.Environ()
+	environ = append(environ, sortedAssignments(variables, VariablePrefix)...)
+	return append(environ, sortedAssignments(env, "")...)
+}
+
+// sortedAssignments returns NAME=value pairs sorted by name
+func sortedAssignments(values map[string]string, prefix string) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	assignments := make([]string, 0, len(names))
+	for _, name := range names {
+		assignments = append(assignments, prefix+name+"="+values[name])
+	}
+	return assignments
+}
+
+// conditionMet evaluates a hook condition against the project directory, true when empty
+func conditionMet(condition, dir string) (bool, error) {
+	if condition == "" {
+		return true, nil
+	}
+
+	kind, argument, err := core.ParseCondition(condition)
+	if err != nil {
+		return false, err
+	}
+
+	switch kind {
+	case core.ConditionExists, core.ConditionMissing:
+		_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(argument)))
+		if err != nil && !os.IsNotExist(err) {
+			return false, err
+		}
+		return (err == nil) == (kind == core.ConditionExists), nil
+	default: // core.ConditionCommand
+		_, err := exec.LookPath(argument)
+		return err == nil, nil
+	}
+}
+
+// failed marks a result as failed without the command having run
+func failed(result Result, err error) Result {
+	result.ExitCode = -1
+	result.Output = err.Error()
+	return result
+}
+
+// tail returns at most the last n bytes of s
+func tail(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return "..." + s[len(s)-n:]
+}
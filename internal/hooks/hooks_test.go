@@ -0,0 +1,111 @@
+package hooks
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/logging"
+)
+
+func TestRun(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "web"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	hooks := []core.Hook{
+		{Name: "variables", Stage: core.HookPostGenerate, Command: `echo "$TE_VAR_ProjectName:$MODE" > vars.txt`,
+			Env: map[string]string{"MODE": "dev"}},
+		{Name: "workdir", Stage: core.HookPostGenerate, Command: "pwd > ../workdir.txt", Workdir: "web"},
+		{Name: "skipped", Stage: core.HookPostGenerate, Command: "touch skipped.txt", Condition: "missing:web"},
+		{Name: "conditional", Stage: core.HookPostGenerate, Command: "touch ran.txt", Condition: "exists:vars.txt"},
+	}
+
+	results, err := Run(context.Background(), logging.Discard(), hooks, Options{
+		Dir:       dir,
+		Variables: map[string]string{"ProjectName": "My App"},
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(results) != 4 || !results[2].Skipped || results[3].Skipped {
+		t.Fatalf("Run() results = %+v", results)
+	}
+
+	assertFile(t, filepath.Join(dir, "vars.txt"), "My App:dev\n")
+	workdir, err := os.ReadFile(filepath.Join(dir, "workdir.txt"))
+	if err != nil || filepath.Base(strings.TrimSpace(string(workdir))) != "web" {
+		t.Errorf("hook ran in %q, want the web directory (error %v)", workdir, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "skipped.txt")); !os.IsNotExist(err) {
+		t.Error("hook with unmet condition should not run")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "ran.txt")); err != nil {
+		t.Errorf("hook with met condition should run: %v", err)
+	}
+}
+
+func TestRunStopsAtFailure(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	dir := t.TempDir()
+	hooks := []core.Hook{
+		{Name: "fail", Stage: core.HookPreGenerate, Command: "echo broken; exit 3"},
+		{Name: "after", Stage: core.HookPreGenerate, Command: "touch after.txt"},
+	}
+
+	results, err := Run(context.Background(), logging.Discard(), hooks, Options{Dir: dir})
+	if err == nil {
+		t.Fatal("Run() should fail")
+	}
+	if len(results) != 1 || results[0].ExitCode != 3 || results[0].Output != "broken\n" {
+		t.Errorf("Run() results = %+v", results)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "after.txt")); !os.IsNotExist(err) {
+		t.Error("hooks after a failure should not run")
+	}
+
+	results, err = Run(context.Background(), logging.Discard(), hooks, Options{Dir: dir, KeepGoing: true})
+	if err != nil {
+		t.Fatalf("Run(KeepGoing) error = %v", err)
+	}
+	if len(results) != 2 || results[0].ExitCode != 3 || results[1].ExitCode != 0 {
+		t.Errorf("Run(KeepGoing) results = %+v", results)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "after.txt")); err != nil {
+		t.Errorf("KeepGoing should run the hooks after a failure: %v", err)
+	}
+}
+
+func TestRunHookTimeout(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	result := RunHook(context.Background(), core.Hook{Command: "sleep 5", Timeout: "50ms"}, Options{Dir: t.TempDir()})
+	if result.ExitCode == 0 {
+		t.Errorf("RunHook() = %+v, want the timeout to stop the command", result)
+	}
+}
+
+func assertFile(t *testing.T, path, expected string) {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	if string(content) != expected {
+		t.Errorf("%s = %q, want %q", path, content, expected)
+	}
+}
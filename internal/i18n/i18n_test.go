@@ -0,0 +1,132 @@
+package i18n
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   string
+		ok     bool
+	}{
+		{"de", "de", true},
+		{"de_DE.UTF-8", "de", true},
+		{"pt-BR", "pt", true},
+		{"FR_ca", "fr", true},
+		{"es_ES@euro", "es", true},
+		{"C", English, true},
+		{"POSIX", English, true},
+		{"C.UTF-8", English, true},
+		{"ja_JP.UTF-8", "ja", false},
+	}
+	for _, tt := range tests {
+		got, ok := Parse(tt.locale)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Parse(%q) = %q, %v, want %q, %v", tt.locale, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name                       string
+		lcAll, lcMessages, langVar string
+		want                       string
+	}{
+		{"unset", "", "", "", English},
+		{"LANG", "", "", "es_MX.UTF-8", "es"},
+		{"LC_MESSAGES over LANG", "", "fr_FR.UTF-8", "es_MX.UTF-8", "fr"},
+		{"LC_ALL over all", "de_AT.UTF-8", "fr_FR.UTF-8", "es_MX.UTF-8", "de"},
+		{"unsupported locale", "ja_JP.UTF-8", "", "es_MX.UTF-8", English},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LC_ALL", tt.lcAll)
+			t.Setenv("LC_MESSAGES", tt.lcMessages)
+			t.Setenv("LANG", tt.langVar)
+			if got := Detect(); got != tt.want {
+				t.Errorf("Detect() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetLang(t *testing.T) {
+	t.Cleanup(func() { _ = SetLang(English) })
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "pt_BR.UTF-8")
+
+	if err := SetLang(""); err != nil {
+		t.Fatal(err)
+	}
+	if Lang() != "pt" {
+		t.Errorf("Lang() = %q, want pt detected from LANG", Lang())
+	}
+	if err := SetLang("de_DE"); err != nil {
+		t.Fatal(err)
+	}
+	if Lang() != "de" {
+		t.Errorf("Lang() = %q, want de", Lang())
+	}
+
+	err := SetLang("klingon")
+	if err == nil || !strings.Contains(err.Error(), "de, en, es, fr, pt") {
+		t.Errorf("SetLang(klingon) = %v, want an error listing the supported languages", err)
+	}
+	if Lang() != "de" {
+		t.Errorf("Lang() = %q after a rejected language, want de kept", Lang())
+	}
+}
+
+func TestT(t *testing.T) {
+	t.Cleanup(func() { _ = SetLang(English) })
+
+	if got := T("Creating %s project...", "go-api"); got != "Creating go-api project..." {
+		t.Errorf("English T = %q", got)
+	}
+	if err := SetLang("es"); err != nil {
+		t.Fatal(err)
+	}
+	if got := T("Creating %s project...", "go-api"); got != "Creando proyecto go-api..." {
+		t.Errorf("Spanish T = %q", got)
+	}
+	if got := T("Create this project?"); got != "¿Crear este proyecto?" {
+		t.Errorf("Spanish T = %q", got)
+	}
+	if got := T("Not in the catalog"); got != "Not in the catalog" {
+		t.Errorf("missing message = %q, want the English one", got)
+	}
+}
+
+func TestCatalogsComplete(t *testing.T) {
+	// Every language translates the same messages, keeping their format verbs
+	var messages []string
+	for _, catalog := range catalogs {
+		for message := range maps.Keys(catalog) {
+			if !slices.Contains(messages, message) {
+				messages = append(messages, message)
+			}
+		}
+	}
+
+	for lang, catalog := range catalogs {
+		if lang == English {
+			continue
+		}
+		for _, message := range messages {
+			translation, ok := catalog[message]
+			if !ok {
+				t.Errorf("%s: missing translation of %q", lang, message)
+				continue
+			}
+			if strings.Count(translation, "%s") != strings.Count(message, "%s") {
+				t.Errorf("%s: translation %q of %q changes its format verbs", lang, translation, message)
+			}
+		}
+	}
+}
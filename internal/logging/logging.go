@@ -0,0 +1,129 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// New creates a logger that writes human-readable lines to w at the given level
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(NewCLIHandler(w, level))
+}
+
+// Discard returns a logger that drops every record
+func Discard() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError + 1}))
+}
+
+// LevelFromFlags maps the --verbose/--quiet CLI flags to a log level
+func LevelFromFlags(verbose, quiet bool) slog.Level {
+	switch {
+	case quiet:
+		return slog.LevelWarn
+	case verbose:
+		return slog.LevelDebug
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// CLIHandler is a slog.Handler that prints plain messages followed by key=value attributes,
+// without timestamps, so CLI output stays readable
+type CLIHandler struct {
+	mu     *sync.Mutex
+	w      io.Writer
+	level  slog.Leveler
+	attrs  string
+	prefix string
+}
+
+// NewCLIHandler creates a CLI handler writing to w
+func NewCLIHandler(w io.Writer, level slog.Leveler) *CLIHandler {
+	return &CLIHandler{
+		mu:    &sync.Mutex{},
+		w:     w,
+		level: level,
+	}
+}
+
+// Enabled reports whether records at the given level are printed
+func (h *CLIHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle formats and writes a single record
+func (h *CLIHandler) Handle(_ context.Context, record slog.Record) error {
+	var line strings.Builder
+
+	switch {
+	case record.Level >= slog.LevelError:
+		line.WriteString("error: ")
+	case record.Level >= slog.LevelWarn:
+		line.WriteString("warning: ")
+	}
+	line.WriteString(record.Message)
+	line.WriteString(h.attrs)
+
+	record.Attrs(func(attr slog.Attr) bool {
+		writeAttr(&line, h.prefix, attr)
+		return true
+	})
+	line.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, line.String())
+	return err
+}
+
+// WithAttrs returns a handler that includes attrs on every record
+func (h *CLIHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var formatted strings.Builder
+	formatted.WriteString(h.attrs)
+	for _, attr := range attrs {
+		writeAttr(&formatted, h.prefix, attr)
+	}
+
+	clone := *h
+	clone.attrs = formatted.String()
+	return &clone
+}
+
+// WithGroup returns a handler that qualifies subsequent attribute keys with name
+func (h *CLIHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+// writeAttr appends a single key=value pair, flattening groups
+func writeAttr(line *strings.Builder, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if attr.Key != "" {
+			groupPrefix += attr.Key + "."
+		}
+		for _, child := range attr.Value.Group() {
+			writeAttr(line, groupPrefix, child)
+		}
+		return
+	}
+
+	value := attr.Value.String()
+	if strings.ContainsAny(value, " \t\"=") || value == "" {
+		value = fmt.Sprintf("%q", value)
+	}
+	fmt.Fprintf(line, " %s%s=%s", prefix, attr.Key, value)
+}
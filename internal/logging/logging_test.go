@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestCLIHandlerFormatsRecords(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelInfo)
+
+	logger.Info("Template extracted", "files", 3, "output", "my template.json")
+	logger.Warn("Mapping not found", "path", "go.mod")
+	logger.Debug("hidden")
+
+	expected := "Template extracted files=3 output=\"my template.json\"\n" +
+		"warning: Mapping not found path=go.mod\n"
+	if buf.String() != expected {
+		t.Errorf("Unexpected output.\nExpected: %q\nGot: %q", expected, buf.String())
+	}
+}
+
+func TestCLIHandlerWithAttrsAndGroups(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelDebug).With("op", "extract").WithGroup("file")
+
+	logger.Debug("Writing", "path", "README.md")
+
+	expected := "Writing op=extract file.path=README.md\n"
+	if buf.String() != expected {
+		t.Errorf("Unexpected output.\nExpected: %q\nGot: %q", expected, buf.String())
+	}
+}
+
+func TestLevelFromFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		verbose bool
+		quiet   bool
+		want    slog.Level
+	}{
+		{name: "default", want: slog.LevelInfo},
+		{name: "verbose", verbose: true, want: slog.LevelDebug},
+		{name: "quiet", quiet: true, want: slog.LevelWarn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LevelFromFlags(tt.verbose, tt.quiet); got != tt.want {
+				t.Errorf("LevelFromFlags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscardDropsEverything(t *testing.T) {
+	logger := Discard()
+	if logger.Enabled(context.Background(), slog.LevelError) {
+		t.Error("Expected discard logger to be disabled at every level")
+	}
+}
@@ -0,0 +1,141 @@
+package oci
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Credentials authenticate against a registry
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// dockerConfig is the part of ~/.docker/config.json used for registry auth
+type dockerConfig struct {
+	Auths map[string]struct {
+		Auth          string `json:"auth"`
+		Username      string `json:"username"`
+		Password      string `json:"password"`
+		IdentityToken string `json:"identitytoken"`
+	} `json:"auths"`
+	CredsStore  string            `json:"credsStore"`
+	CredHelpers map[string]string `json:"credHelpers"`
+}
+
+// dockerHubAuthKey is the key Docker uses for Docker Hub credentials
+const dockerHubAuthKey = "https://index.docker.io/v1/"
+
+// DockerCredentials looks up the credentials for registry in the Docker config
+// ($DOCKER_CONFIG/config.json or ~/.docker/config.json), including credential helpers.
+// It returns nil without error when no credentials are configured.
+func DockerCredentials(registry string) (*Credentials, error) {
+	path, err := dockerConfigPath()
+	if err != nil {
+		return nil, nil // No home directory, so no Docker config either
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read docker config: %w", err)
+	}
+
+	var config dockerConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse docker config %s: %w", path, err)
+	}
+
+	key := registry
+	if registry == dockerHubHost {
+		key = dockerHubAuthKey
+	}
+
+	if helper := config.CredHelpers[registry]; helper != "" {
+		return helperCredentials(helper, key)
+	}
+
+	for server, entry := range config.Auths {
+		if authHost(server) != registry && server != key {
+			continue
+		}
+		if entry.IdentityToken != "" {
+			return &Credentials{Password: entry.IdentityToken}, nil
+		}
+		if entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("invalid auth for %s in docker config: %w", server, err)
+			}
+			username, password, _ := strings.Cut(string(decoded), ":")
+			return &Credentials{Username: username, Password: password}, nil
+		}
+		if entry.Username != "" {
+			return &Credentials{Username: entry.Username, Password: entry.Password}, nil
+		}
+	}
+
+	if config.CredsStore != "" {
+		return helperCredentials(config.CredsStore, key)
+	}
+
+	return nil, nil
+}
+
+// helperCredentials asks a docker-credential-<helper> program for the credentials of server
+func helperCredentials(helper, server string) (*Credentials, error) {
+	cmd := exec.Command("docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(server)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		// Helpers report unknown servers as an error, which just means no credentials
+		if strings.Contains(string(out)+stderr.String(), "credentials not found") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("credential helper %s failed: %w", helper, err)
+	}
+
+	var creds struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}
+	if err := json.Unmarshal(out, &creds); err != nil {
+		return nil, fmt.Errorf("credential helper %s returned invalid output: %w", helper, err)
+	}
+
+	// "<token>" marks an identity token rather than a username
+	if creds.Username == "<token>" {
+		creds.Username = ""
+	}
+	return &Credentials{Username: creds.Username, Password: creds.Secret}, nil
+}
+
+// authHost strips the scheme and path from a Docker config auths key
+func authHost(server string) string {
+	server = strings.TrimPrefix(server, "https://")
+	server = strings.TrimPrefix(server, "http://")
+	host, _, _ := strings.Cut(server, "/")
+	return host
+}
+
+func dockerConfigPath() (string, error) {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return filepath.Join(dir, "config.json"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".docker", "config.json"), nil
+}
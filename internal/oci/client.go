@@ -0,0 +1,456 @@
+package oci
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/acheevo/template-engine/internal/download"
+)
+
+const (
+	// SchemaMediaType identifies the layer holding a template schema
+	SchemaMediaType = "application/vnd.acheevo.template-engine.schema.v1+json"
+
+	manifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	emptyMediaType    = "application/vnd.oci.empty.v1+json"
+
+	// maxArtifactSize bounds manifests and schema blobs read from a registry
+	maxArtifactSize = 256 << 20 // 256MB
+)
+
+// emptyConfig is the config blob of artifacts that have no config
+var emptyConfig = []byte("{}")
+
+// Descriptor points to a blob in a registry
+type Descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Manifest is an OCI image manifest describing an artifact
+type Manifest struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType"`
+	ArtifactType  string            `json:"artifactType,omitempty"`
+	Config        Descriptor        `json:"config"`
+	Layers        []Descriptor      `json:"layers"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+// Client pushes and pulls template schemas as OCI artifacts
+type Client struct {
+	// HTTPClient sends the requests, http.DefaultClient when nil
+	HTTPClient *http.Client
+	// PlainHTTP talks to the registry over HTTP instead of HTTPS
+	PlainHTTP bool
+	// Credentials returns the credentials for a registry host, or nil for anonymous access
+	Credentials func(registry string) (*Credentials, error)
+	// Retries and Backoff configure how failed pulls are retried (see download.Options)
+	Retries int
+	Backoff time.Duration
+	// Offline fails every push and pull with download.ErrOffline instead of reaching the registry
+	Offline bool
+
+	mu     sync.Mutex
+	tokens map[string]string // Bearer tokens by registry and scope
+}
+
+// NewClient creates a client authenticating with the Docker config
+func NewClient() *Client {
+	return &Client{Credentials: DockerCredentials}
+}
+
+// Push uploads a schema as the single layer of an artifact tagged ref and returns the manifest digest
+func (c *Client) Push(ctx context.Context, ref Reference, schema []byte,
+	annotations map[string]string,
+) (string, error) {
+	if ref.Tag == "" {
+		return "", fmt.Errorf("push needs a tag: %s", ref)
+	}
+	if c.Offline {
+		return "", fmt.Errorf("cannot push %s: %w", ref, download.ErrOffline)
+	}
+	scope := "repository:" + ref.Repository + ":pull,push"
+
+	layer := Descriptor{
+		MediaType:   SchemaMediaType,
+		Digest:      digestOf(schema),
+		Size:        int64(len(schema)),
+		Annotations: map[string]string{"org.opencontainers.image.title": "schema.json"},
+	}
+	config := Descriptor{MediaType: emptyMediaType, Digest: digestOf(emptyConfig), Size: int64(len(emptyConfig))}
+
+	for _, blob := range []struct {
+		desc Descriptor
+		data []byte
+	}{{config, emptyConfig}, {layer, schema}} {
+		if err := c.pushBlob(ctx, ref, scope, blob.desc, blob.data); err != nil {
+			return "", err
+		}
+	}
+
+	manifest, err := json.Marshal(Manifest{
+		SchemaVersion: 2,
+		MediaType:     manifestMediaType,
+		ArtifactType:  SchemaMediaType,
+		Config:        config,
+		Layers:        []Descriptor{layer},
+		Annotations:   annotations,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.do(ctx, ref, scope, http.MethodPut, "/manifests/"+ref.Tag, manifest,
+		http.Header{"Content-Type": {manifestMediaType}})
+	if err != nil {
+		return "", err
+	}
+	if err := expectStatus(resp, http.StatusCreated, "push manifest"); err != nil {
+		return "", err
+	}
+
+	return digestOf(manifest), nil
+}
+
+// Pull downloads the schema stored in the artifact ref
+func (c *Client) Pull(ctx context.Context, ref Reference) ([]byte, error) {
+	scope := "repository:" + ref.Repository + ":pull"
+
+	data, err := c.fetch(ctx, ref, scope, "/manifests/"+ref.manifestReference(), manifestMediaType, ref.Digest)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest for %s: %w", ref, err)
+	}
+
+	layer, err := schemaLayer(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ref, err)
+	}
+
+	return c.fetch(ctx, ref, scope, "/blobs/"+layer.Digest, "", layer.Digest)
+}
+
+// schemaLayer finds the layer holding the schema
+func schemaLayer(manifest Manifest) (Descriptor, error) {
+	for _, layer := range manifest.Layers {
+		if layer.MediaType == SchemaMediaType {
+			return layer, nil
+		}
+	}
+	return Descriptor{}, errors.New("artifact does not contain a template schema")
+}
+
+// pushBlob uploads data unless the registry already has it
+func (c *Client) pushBlob(ctx context.Context, ref Reference, scope string, desc Descriptor, data []byte) error {
+	resp, err := c.do(ctx, ref, scope, http.MethodHead, "/blobs/"+desc.Digest, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	resp, err = c.do(ctx, ref, scope, http.MethodPost, "/blobs/uploads/", nil, nil)
+	if err != nil {
+		return err
+	}
+	if err := expectStatus(resp, http.StatusAccepted, "start blob upload"); err != nil {
+		return err
+	}
+
+	location, err := resp.Location()
+	if err != nil {
+		return fmt.Errorf("registry did not return an upload location: %w", err)
+	}
+	query := location.Query()
+	query.Set("digest", desc.Digest)
+	location.RawQuery = query.Encode()
+
+	resp, err = c.doURL(ctx, ref, scope, http.MethodPut, location.String(), data,
+		http.Header{"Content-Type": {"application/octet-stream"}})
+	if err != nil {
+		return err
+	}
+	return expectStatus(resp, http.StatusCreated, "upload blob")
+}
+
+// fetch GETs a manifest or blob and verifies it against digest when not empty. Transient
+// failures are retried and interrupted transfers of large blobs resumed.
+func (c *Client) fetch(ctx context.Context, ref Reference, scope, path, accept, digest string) ([]byte, error) {
+	header := http.Header{}
+	if accept != "" {
+		header.Set("Accept", accept)
+	}
+	if auth := c.cachedAuth(ref.Registry, scope); auth != "" {
+		header.Set("Authorization", auth)
+	}
+	opts := download.Options{
+		Client:  c.httpClient(),
+		Header:  header,
+		Retries: c.Retries,
+		Backoff: c.Backoff,
+		Digest:  digest,
+		MaxSize: maxArtifactSize,
+		Offline: c.Offline,
+	}
+
+	data, err := download.Bytes(ctx, c.baseURL(ref)+path, opts)
+	var statusErr *download.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusUnauthorized {
+		auth, authErr := c.authorize(ctx, ref.Registry, scope, statusErr.Header.Get("WWW-Authenticate"))
+		if authErr != nil {
+			return nil, authErr
+		}
+		header.Set("Authorization", auth)
+		data, err = download.Bytes(ctx, c.baseURL(ref)+path, opts)
+	}
+
+	switch {
+	case errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%s not found", ref)
+	case errors.As(err, &statusErr):
+		return nil, registryError("fetch "+path, statusErr.Status, bytes.NewReader(statusErr.Body))
+	case err != nil:
+		return nil, fmt.Errorf("registry request failed: %w", err)
+	}
+	return data, nil
+}
+
+// do sends a request to a path of the repository API
+func (c *Client) do(ctx context.Context, ref Reference, scope, method, path string, body []byte,
+	header http.Header,
+) (*http.Response, error) {
+	return c.doURL(ctx, ref, scope, method, c.baseURL(ref)+path, body, header)
+}
+
+// doURL sends a request, authenticating and retrying once when the registry asks for credentials
+func (c *Client) doURL(ctx context.Context, ref Reference, scope, method, target string, body []byte,
+	header http.Header,
+) (*http.Response, error) {
+	// Upload locations may be relative to the registry
+	if strings.HasPrefix(target, "/") {
+		target = c.registryURL(ref) + target
+	}
+
+	send := func(auth string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		resp, err := c.httpClient().Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("registry request failed: %w", err)
+		}
+		return resp, nil
+	}
+
+	resp, err := send(c.cachedAuth(ref.Registry, scope))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	resp.Body.Close()
+
+	auth, err := c.authorize(ctx, ref.Registry, scope, resp.Header.Get("WWW-Authenticate"))
+	if err != nil {
+		return nil, err
+	}
+	return send(auth)
+}
+
+// authorize answers an authentication challenge and returns the Authorization header to use
+func (c *Client) authorize(ctx context.Context, registry, scope, challenge string) (string, error) {
+	var creds *Credentials
+	if c.Credentials != nil {
+		var err error
+		if creds, err = c.Credentials(registry); err != nil {
+			return "", err
+		}
+	}
+
+	scheme, params := parseChallenge(challenge)
+	switch scheme {
+	case "basic":
+		if creds == nil {
+			return "", fmt.Errorf("%s requires authentication, log in with docker login", registry)
+		}
+		return basicAuth(creds), nil
+	case "bearer":
+		token, err := c.fetchToken(ctx, params, scope, creds)
+		if err != nil {
+			return "", err
+		}
+		auth := "Bearer " + token
+		c.mu.Lock()
+		if c.tokens == nil {
+			c.tokens = make(map[string]string)
+		}
+		c.tokens[registry+" "+scope] = auth
+		c.mu.Unlock()
+		return auth, nil
+	default:
+		return "", fmt.Errorf("%s returned an unsupported authentication challenge %q", registry, challenge)
+	}
+}
+
+// fetchToken gets a bearer token from the registry's token service
+func (c *Client) fetchToken(ctx context.Context, params map[string]string, scope string,
+	creds *Credentials,
+) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("invalid token realm %q", params["realm"])
+	}
+
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if creds != nil {
+		req.Header.Set("Authorization", basicAuth(creds))
+	}
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", registryError("fetch token", resp.Status, resp.Body)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("invalid token response: %w", err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	if token.Token == "" {
+		return "", errors.New("token service returned no token")
+	}
+	return token.Token, nil
+}
+
+// cachedAuth returns a previously obtained Authorization header for scope
+func (c *Client) cachedAuth(registry, scope string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.tokens[registry+" "+scope]
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+func (c *Client) registryURL(ref Reference) string {
+	scheme := "https"
+	if c.PlainHTTP {
+		scheme = "http"
+	}
+	return scheme + "://" + ref.host()
+}
+
+func (c *Client) baseURL(ref Reference) string {
+	return c.registryURL(ref) + "/v2/" + ref.Repository
+}
+
+// parseChallenge parses a WWW-Authenticate header like: Bearer realm="...",service="..."
+func parseChallenge(header string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(header), " ")
+	params := make(map[string]string)
+
+	for rest != "" {
+		var pair string
+		rest = strings.TrimLeft(rest, ", ")
+		key, value, found := strings.Cut(rest, "=")
+		if !found {
+			break
+		}
+		if strings.HasPrefix(value, `"`) {
+			end := strings.Index(value[1:], `"`)
+			if end < 0 {
+				break
+			}
+			pair, rest = value[1:end+1], value[end+2:]
+		} else {
+			pair, rest, _ = strings.Cut(value, ",")
+		}
+		params[strings.ToLower(strings.TrimSpace(key))] = pair
+	}
+
+	return strings.ToLower(scheme), params
+}
+
+func basicAuth(creds *Credentials) string {
+	req := &http.Request{Header: http.Header{}}
+	req.SetBasicAuth(creds.Username, creds.Password)
+	return req.Header.Get("Authorization")
+}
+
+func digestOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// expectStatus closes the response body and fails unless the response has the given status
+func expectStatus(resp *http.Response, status int, action string) error {
+	defer resp.Body.Close()
+	if resp.StatusCode != status {
+		return registryError(action, resp.Status, resp.Body)
+	}
+	return nil
+}
+
+// registryError describes an unexpected registry response
+func registryError(action, status string, body io.Reader) error {
+	var response struct {
+		Errors []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	err := json.NewDecoder(io.LimitReader(body, 1<<20)).Decode(&response)
+	if err == nil && len(response.Errors) > 0 {
+		return fmt.Errorf("failed to %s: %s: %s", action, response.Errors[0].Code, response.Errors[0].Message)
+	}
+	return fmt.Errorf("failed to %s: registry returned %s", action, status)
+}
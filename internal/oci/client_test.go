@@ -0,0 +1,212 @@
+package oci
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRegistry is a minimal OCI distribution server that requires bearer tokens
+type fakeRegistry struct {
+	mu        sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string][]byte
+	uploads   int
+	// unavailable fails that many blob downloads with 503 Service Unavailable
+	unavailable int
+}
+
+func (f *fakeRegistry) handler(tokenURL string) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /token", func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "alice" || pass != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = io.WriteString(w, `{"token": "valid"}`)
+	})
+
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer valid" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+tokenURL+`",service="fake"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		path := strings.TrimPrefix(r.URL.Path, "/v2/org/templates/")
+		switch {
+		case r.Method == http.MethodHead && strings.HasPrefix(path, "blobs/"):
+			if _, ok := f.blobs[strings.TrimPrefix(path, "blobs/")]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case r.Method == http.MethodGet && strings.HasPrefix(path, "blobs/") && f.unavailable > 0:
+			f.unavailable--
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.Method == http.MethodGet && strings.HasPrefix(path, "blobs/"):
+			data, ok := f.blobs[strings.TrimPrefix(path, "blobs/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(data)
+		case r.Method == http.MethodPost && path == "blobs/uploads/":
+			w.Header().Set("Location", "/v2/org/templates/blobs/uploads/session")
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodPut && path == "blobs/uploads/session":
+			data, _ := io.ReadAll(r.Body)
+			f.blobs[r.URL.Query().Get("digest")] = data
+			f.uploads++
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPut && strings.HasPrefix(path, "manifests/"):
+			data, _ := io.ReadAll(r.Body)
+			f.manifests[strings.TrimPrefix(path, "manifests/")] = data
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodGet && strings.HasPrefix(path, "manifests/"):
+			data, ok := f.manifests[strings.TrimPrefix(path, "manifests/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", manifestMediaType)
+			_, _ = w.Write(data)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+
+	return mux
+}
+
+// newFakeRegistry starts a fake registry and returns a client for it and the registry host
+func newFakeRegistry(t *testing.T) (*Client, *fakeRegistry, string) {
+	t.Helper()
+
+	registry := &fakeRegistry{blobs: map[string][]byte{}, manifests: map[string][]byte{}}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		registry.handler(server.URL+"/token").ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	// Credentials come from a Docker config, as they would after docker login
+	host := strings.TrimPrefix(server.URL, "http://")
+	dockerDir := t.TempDir()
+	auth := base64.StdEncoding.EncodeToString([]byte("alice:s3cret"))
+	config := `{"auths": {"` + host + `": {"auth": "` + auth + `"}}}`
+	if err := os.WriteFile(filepath.Join(dockerDir, "config.json"), []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DOCKER_CONFIG", dockerDir)
+
+	client := NewClient()
+	client.PlainHTTP = true
+	return client, registry, host
+}
+
+func TestPushPull(t *testing.T) {
+	client, registry, host := newFakeRegistry(t)
+	ctx := context.Background()
+
+	ref, err := ParseReference(host + "/org/templates:1.2.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema := []byte(`{"name": "frontend"}`)
+	digest, err := client.Push(ctx, ref, schema, map[string]string{"org.opencontainers.image.version": "1.2.0"})
+	if err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+	if !strings.HasPrefix(digest, "sha256:") {
+		t.Errorf("Push() digest = %q", digest)
+	}
+
+	// Pushing again reuses the blobs already in the registry
+	if _, err := client.Push(ctx, ref, schema, nil); err != nil {
+		t.Fatalf("Push() again error = %v", err)
+	}
+	if registry.uploads != 2 {
+		t.Errorf("Expected 2 blob uploads, got %d", registry.uploads)
+	}
+
+	pulled, err := client.Pull(ctx, ref)
+	if err != nil {
+		t.Fatalf("Pull() error = %v", err)
+	}
+	if string(pulled) != string(schema) {
+		t.Errorf("Pull() = %s, want %s", pulled, schema)
+	}
+}
+
+func TestPullDetectsTampering(t *testing.T) {
+	client, registry, host := newFakeRegistry(t)
+	ctx := context.Background()
+
+	ref, _ := ParseReference(host + "/org/templates:1.0.0")
+	if _, err := client.Push(ctx, ref, []byte(`{"name": "frontend"}`), nil); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+
+	for digest := range registry.blobs {
+		if digest != digestOf(emptyConfig) {
+			registry.blobs[digest] = []byte(`{"name": "evil"}`)
+		}
+	}
+
+	if _, err := client.Pull(ctx, ref); err == nil || !strings.Contains(err.Error(), "digest mismatch") {
+		t.Errorf("Pull() error = %v, want digest mismatch", err)
+	}
+}
+
+func TestPullRetriesUnavailableRegistry(t *testing.T) {
+	client, registry, host := newFakeRegistry(t)
+	client.Backoff = time.Millisecond
+	ctx := context.Background()
+
+	ref, _ := ParseReference(host + "/org/templates:1.0.0")
+	if _, err := client.Push(ctx, ref, []byte(`{"name": "frontend"}`), nil); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+
+	registry.unavailable = 2
+	if _, err := client.Pull(ctx, ref); err != nil {
+		t.Errorf("Pull() error = %v, want the blob download retried", err)
+	}
+
+	registry.unavailable = 2
+	client.Retries = 1
+	if _, err := client.Pull(ctx, ref); err == nil {
+		t.Error("Pull() should fail once the retries are used up")
+	}
+}
+
+func TestPullWithoutCredentials(t *testing.T) {
+	client, _, host := newFakeRegistry(t)
+	client.Credentials = nil
+
+	ref, _ := ParseReference(host + "/org/templates:1.0.0")
+	if _, err := client.Pull(context.Background(), ref); err == nil {
+		t.Error("Pull() without credentials should fail")
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	header := `Bearer realm="https://ghcr.io/token",service="ghcr.io",scope="repository:org/x:pull"`
+	scheme, params := parseChallenge(header)
+	if scheme != "bearer" || params["realm"] != "https://ghcr.io/token" || params["service"] != "ghcr.io" ||
+		params["scope"] != "repository:org/x:pull" {
+		t.Errorf("parseChallenge() = %q, %v", scheme, params)
+	}
+}
@@ -0,0 +1,102 @@
+package oci
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	dockerHubHost     = "docker.io"
+	dockerHubRegistry = "registry-1.docker.io"
+	defaultTag        = "latest"
+)
+
+var (
+	pathComponent     = `[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*`
+	repositoryPattern = regexp.MustCompile(`^` + pathComponent + `(?:/` + pathComponent + `)*$`)
+	tagPattern        = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+	digestPattern     = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+)
+
+// Reference identifies an artifact in an OCI registry, e.g. ghcr.io/org/templates/frontend:1.2.0
+type Reference struct {
+	Registry   string // Host, with port if any
+	Repository string
+	Tag        string
+	Digest     string // Set instead of Tag for references like repo@sha256:...
+}
+
+// ParseReference parses an image-style reference. References without a registry host
+// point to Docker Hub and default to the "latest" tag.
+func ParseReference(s string) (Reference, error) {
+	var ref Reference
+	rest := s
+
+	if name, digest, found := strings.Cut(rest, "@"); found {
+		if !digestPattern.MatchString(digest) {
+			return ref, fmt.Errorf("invalid reference %q: bad digest", s)
+		}
+		ref.Digest = digest
+		rest = name
+	}
+
+	// The first component is a registry host if it looks like one
+	if host, path, found := strings.Cut(rest, "/"); found &&
+		(strings.ContainsAny(host, ".:") || host == "localhost") {
+		ref.Registry = host
+		rest = path
+	} else {
+		ref.Registry = dockerHubHost
+		if !strings.Contains(rest, "/") {
+			rest = "library/" + rest
+		}
+	}
+
+	// A tag follows the last colon after the last slash
+	if i := strings.LastIndex(rest, ":"); i > strings.LastIndex(rest, "/") {
+		ref.Tag = rest[i+1:]
+		rest = rest[:i]
+		if !tagPattern.MatchString(ref.Tag) {
+			return ref, fmt.Errorf("invalid reference %q: bad tag %q", s, ref.Tag)
+		}
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = defaultTag
+	}
+
+	if !repositoryPattern.MatchString(rest) {
+		return ref, fmt.Errorf("invalid reference %q: bad repository %q", s, rest)
+	}
+	ref.Repository = rest
+
+	return ref, nil
+}
+
+// String formats the reference
+func (r Reference) String() string {
+	s := r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+// manifestReference returns the tag or digest used in manifest URLs
+func (r Reference) manifestReference() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+	return r.Tag
+}
+
+// host returns the host serving the registry API
+func (r Reference) host() string {
+	if r.Registry == dockerHubHost {
+		return dockerHubRegistry
+	}
+	return r.Registry
+}
@@ -0,0 +1,43 @@
+package oci
+
+import "testing"
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Reference
+	}{
+		{
+			"ghcr.io/org/templates/frontend:1.2.0",
+			Reference{Registry: "ghcr.io", Repository: "org/templates/frontend", Tag: "1.2.0"},
+		},
+		{"localhost:5000/frontend", Reference{Registry: "localhost:5000", Repository: "frontend", Tag: "latest"}},
+		{"frontend:v1", Reference{Registry: "docker.io", Repository: "library/frontend", Tag: "v1"}},
+		{"org/frontend", Reference{Registry: "docker.io", Repository: "org/frontend", Tag: "latest"}},
+		{
+			"ghcr.io/org/frontend@sha256:" + sha,
+			Reference{Registry: "ghcr.io", Repository: "org/frontend", Digest: "sha256:" + sha},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseReference(tt.input)
+		if err != nil {
+			t.Errorf("ParseReference(%q) error = %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseReference(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseReferenceInvalid(t *testing.T) {
+	for _, input := range []string{"", "ghcr.io/Org/Frontend", "ghcr.io/org/frontend:bad tag", "ghcr.io/org@sha256:xyz"} {
+		if _, err := ParseReference(input); err == nil {
+			t.Errorf("ParseReference(%q) should fail", input)
+		}
+	}
+}
+
+const sha = "4a5c8b0b4e4b3d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6"
@@ -0,0 +1,70 @@
+// Package policy enforces organization rules on generation requests, such as the GitHub
+// organization projects live under or the variables some templates must be given
+package policy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Request describes a generation about to happen, once the variables are validated and their
+// defaults applied. It is the input of rego policies, hence the JSON names.
+type Request struct {
+	Schema      string `json:"schema"`
+	Type        string `json:"type"`
+	Version     string `json:"version"`
+	Origin      string `json:"origin,omitempty"` // Registry or OCI reference the schema came from
+	ProjectName string `json:"project_name"`
+	GitHubRepo  string `json:"github_repo"`
+	RepoOwner   string `json:"repo_owner"`
+	RepoName    string `json:"repo_name"`
+	// Variables holds every template variable: the built-in ones and those the schema declares
+	Variables map[string]string `json:"variables"`
+	// Features lists the optional features of the schema being generated
+	Features  []string `json:"features,omitempty"`
+	OutputDir string   `json:"output_dir,omitempty"`
+}
+
+// Policy decides whether a generation request may proceed
+type Policy interface {
+	// Check returns the rules req violates, none when it may proceed. An error means the policy
+	// itself could not be evaluated.
+	Check(ctx context.Context, req Request) ([]Violation, error)
+}
+
+// Violation is a rule a request breaks
+type Violation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ViolationError lists every rule a rejected request breaks
+type ViolationError struct {
+	Violations []Violation
+}
+
+func (e *ViolationError) Error() string {
+	lines := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		lines[i] = fmt.Sprintf("  %s: %s", violation.Rule, violation.Message)
+	}
+	return "generation violates organization policy:\n" + strings.Join(lines, "\n")
+}
+
+// Enforce checks req against every policy, returning a *ViolationError listing the violations
+// of all of them when there are any
+func Enforce(ctx context.Context, req Request, policies ...Policy) error {
+	var violations []Violation
+	for _, policy := range policies {
+		found, err := policy.Check(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to evaluate policy: %w", err)
+		}
+		violations = append(violations, found...)
+	}
+	if len(violations) > 0 {
+		return &ViolationError{Violations: violations}
+	}
+	return nil
+}
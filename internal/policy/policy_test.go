@@ -0,0 +1,115 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// request is a generation request satisfying the rules of the tests
+var request = Request{
+	Schema:      "api-template",
+	Type:        "go-api",
+	ProjectName: "billing-api",
+	GitHubRepo:  "acme/billing-api",
+	RepoOwner:   "acme",
+	RepoName:    "billing-api",
+	Variables:   map[string]string{"ProjectName": "billing-api", "Team": "payments"},
+}
+
+func TestRulesCheck(t *testing.T) {
+	rules := &Rules{
+		AllowedOwners:      []string{"Acme"},
+		ProjectNamePattern: "^[a-z][a-z0-9-]+$",
+		RequiredVariables:  map[string][]string{"go-api": {"Team"}, "*": {"ProjectName"}},
+	}
+
+	violations, err := rules.Check(context.Background(), request)
+	if err != nil || len(violations) != 0 {
+		t.Fatalf("Check() = %v, %v, want no violations", violations, err)
+	}
+
+	rejected := request
+	rejected.GitHubRepo, rejected.RepoOwner = "someone/billing-api", "someone"
+	rejected.ProjectName = "Billing API"
+	rejected.Variables = map[string]string{"ProjectName": "Billing API"}
+	violations, err = rules.Check(context.Background(), rejected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, violation := range violations {
+		names = append(names, violation.Rule)
+	}
+	if got := strings.Join(names, ","); got != "allowed_owners,project_name_pattern,required_variables" {
+		t.Errorf("Check() violated %s", got)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "policy.json")
+	if err := os.WriteFile(path, []byte(`{"allowed_owners": ["acme"], "rego": "org.rego"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rules, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if rules.Rego != filepath.Join(dir, "org.rego") {
+		t.Errorf("Rego = %s, want it relative to the policy file", rules.Rego)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"project_name_pattern": "["}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load() should reject an invalid project name pattern")
+	}
+}
+
+func TestRegoCheck(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake opa tool is a shell script")
+	}
+
+	// A fake opa tool denying requests whose input mentions someone's repositories
+	bin := t.TempDir()
+	script := `#!/bin/sh
+if grep -q '"repo_owner":"someone"' -; then
+  echo '{"result":[{"expressions":[{"value":["repositories live under acme"]}]}]}'
+else
+  echo '{}'
+fi
+`
+	if err := os.WriteFile(filepath.Join(bin, "opa"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	rego := &Rego{Path: "org.rego"}
+	if violations, err := rego.Check(context.Background(), request); err != nil || len(violations) != 0 {
+		t.Fatalf("Check() = %v, %v, want no violations", violations, err)
+	}
+
+	rejected := request
+	rejected.RepoOwner = "someone"
+	err := Enforce(context.Background(), rejected, rego, &Rules{ProjectNamePattern: "^x"})
+	var violationErr *ViolationError
+	if !errors.As(err, &violationErr) || len(violationErr.Violations) != 2 {
+		t.Fatalf("Enforce() error = %v, want both policies' violations", err)
+	}
+	want := Violation{Rule: DefaultRegoQuery, Message: "repositories live under acme"}
+	if got := violationErr.Violations[0]; got != want {
+		t.Errorf("rego violation = %+v, want %+v", got, want)
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	if _, err := rego.Check(context.Background(), request); err == nil {
+		t.Error("Check() should fail without the opa tool")
+	}
+}
@@ -0,0 +1,91 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// DefaultRegoQuery is the rule of rego policies listing the violations
+const DefaultRegoQuery = "data.template_engine.deny"
+
+// Rego evaluates a rego policy with the opa command line tool, which must be installed. The
+// Request is the input and every message of the query result, a set of strings, is a violation:
+//
+//	package template_engine
+//
+//	deny contains msg if {
+//		input.type == "go-api"
+//		not input.variables.Team
+//		msg := "go-api projects need a Team"
+//	}
+type Rego struct {
+	// Path is the rego file, or a directory of them
+	Path string
+	// Query is the rule evaluated, DefaultRegoQuery when empty
+	Query string
+}
+
+// Check implements Policy
+func (r *Rego) Check(ctx context.Context, req Request) ([]Violation, error) {
+	opa, err := exec.LookPath("opa")
+	if err != nil {
+		return nil, fmt.Errorf("rego policy %s needs the opa command line tool: %w", r.Path, err)
+	}
+	query := r.Query
+	if query == "" {
+		query = DefaultRegoQuery
+	}
+
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, opa, "eval", "--format", "json", "--stdin-input", "--data", r.Path, query)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("rego policy %s: %w: %s", r.Path, err, strings.TrimSpace(stderr.String()))
+		}
+		return nil, fmt.Errorf("rego policy %s: %w", r.Path, err)
+	}
+
+	var evaluated struct {
+		Result []struct {
+			Expressions []struct {
+				Value json.RawMessage `json:"value"`
+			} `json:"expressions"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(output, &evaluated); err != nil {
+		return nil, fmt.Errorf("rego policy %s: unexpected opa output: %w", r.Path, err)
+	}
+
+	// An undefined rule has no result, so nothing is denied
+	var violations []Violation
+	for _, result := range evaluated.Result {
+		for _, expression := range result.Expressions {
+			var messages []any
+			if err := json.Unmarshal(expression.Value, &messages); err != nil {
+				return nil, fmt.Errorf("rego policy %s: %s must be a set of messages", r.Path, query)
+			}
+			for _, message := range messages {
+				text, ok := message.(string)
+				if !ok {
+					encoded, _ := json.Marshal(message)
+					text = string(encoded)
+				}
+				violations = append(violations, Violation{Rule: query, Message: text})
+			}
+		}
+	}
+	return violations, nil
+}
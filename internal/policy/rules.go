@@ -0,0 +1,89 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Rules is a declarative policy, read from JSON (see Load) such as
+//
+//	{
+//	  "allowed_owners": ["acme", "acme-labs"],
+//	  "project_name_pattern": "^[a-z][a-z0-9-]+$",
+//	  "required_variables": {"go-api": ["Team"], "*": ["CostCenter"]},
+//	  "rego": "policy.rego"
+//	}
+type Rules struct {
+	// AllowedOwners lists the GitHub owners repositories must live under, any when empty
+	AllowedOwners []string `json:"allowed_owners,omitempty"`
+	// ProjectNamePattern is a regular expression project names must match
+	ProjectNamePattern string `json:"project_name_pattern,omitempty"`
+	// RequiredVariables lists the variables that must be set, by template type or schema name,
+	// "*" applying to every template
+	RequiredVariables map[string][]string `json:"required_variables,omitempty"`
+	// Rego is a rego policy evaluated as well, relative to the policy file (see Rego)
+	Rego string `json:"rego,omitempty"`
+}
+
+// Load reads the rules of the policy file at path
+func Load(path string) (*Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy: %w", err)
+	}
+
+	var rules Rules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %w", path, err)
+	}
+	if rules.Rego != "" && !filepath.IsAbs(rules.Rego) {
+		rules.Rego = filepath.Join(filepath.Dir(path), rules.Rego)
+	}
+	if _, err := regexp.Compile(rules.ProjectNamePattern); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: project_name_pattern: %w", path, err)
+	}
+	return &rules, nil
+}
+
+// Check implements Policy
+func (r *Rules) Check(ctx context.Context, req Request) ([]Violation, error) {
+	pattern, err := regexp.Compile(r.ProjectNamePattern)
+	if err != nil {
+		return nil, fmt.Errorf("project_name_pattern: %w", err)
+	}
+
+	var violations []Violation
+	if len(r.AllowedOwners) > 0 && !slices.ContainsFunc(r.AllowedOwners, func(owner string) bool {
+		return strings.EqualFold(owner, req.RepoOwner)
+	}) {
+		violations = append(violations, Violation{Rule: "allowed_owners", Message: fmt.Sprintf(
+			"repository %s must belong to %s", req.GitHubRepo, strings.Join(r.AllowedOwners, ", "))})
+	}
+	if r.ProjectNamePattern != "" && !pattern.MatchString(req.ProjectName) {
+		violations = append(violations, Violation{Rule: "project_name_pattern", Message: fmt.Sprintf(
+			"project name %q must match %s", req.ProjectName, r.ProjectNamePattern)})
+	}
+	for _, key := range slices.Compact([]string{"*", req.Type, req.Schema}) {
+		for _, name := range r.RequiredVariables[key] {
+			if req.Variables[name] == "" {
+				violations = append(violations, Violation{Rule: "required_variables", Message: fmt.Sprintf(
+					"template %s requires variable %s", req.Schema, name)})
+			}
+		}
+	}
+
+	if r.Rego != "" {
+		found, err := (&Rego{Path: r.Rego}).Check(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, found...)
+	}
+	return violations, nil
+}
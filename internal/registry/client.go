@@ -0,0 +1,234 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/download"
+)
+
+// Client talks to a registry server
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	retries    int
+	backoff    time.Duration
+	offline    bool
+	token      string
+}
+
+// NewClient creates a client for the registry at baseURL
+func NewClient(baseURL string, httpClient *http.Client) (*Client, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid registry URL %q: expected http(s)://host[:port]", baseURL)
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), httpClient: httpClient}, nil
+}
+
+// SetRetries configures how often a failed schema download is retried and the delay before the
+// first retry (see download.Options)
+func (c *Client) SetRetries(retries int, backoff time.Duration) {
+	c.retries = retries
+	c.backoff = backoff
+}
+
+// SetOffline makes every request fail with download.ErrOffline instead of reaching the registry
+func (c *Client) SetOffline(offline bool) {
+	c.offline = offline
+}
+
+// SetToken sets the bearer token sent with every request, which the registry requires to write
+// schemas (see Server.SetToken)
+func (c *Client) SetToken(token string) {
+	c.token = token
+}
+
+// authorize adds the bearer token, if any, to the headers of a request
+func (c *Client) authorize(header http.Header) http.Header {
+	if c.token != "" {
+		header.Set("Authorization", "Bearer "+c.token)
+	}
+	return header
+}
+
+// List returns the schemas stored in the registry
+func (c *Client) List(ctx context.Context) ([]Entry, error) {
+	var entries []Entry
+	if err := c.do(ctx, http.MethodGet, "/schemas", nil, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Get fetches a schema from the registry, recording the registry as its origin. Transient
+// failures are retried and interrupted downloads resumed; the schema is verified against the
+// digest the registry publishes.
+func (c *Client) Get(ctx context.Context, name string) (*core.TemplateSchema, error) {
+	return c.GetVerified(ctx, name, "")
+}
+
+// GetVerified fetches a schema like Get, verifying it against digest (sha256:<hex>) instead
+// when not empty
+func (c *Client) GetVerified(ctx context.Context, name, digest string) (*core.TemplateSchema, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+
+	raw, err := download.Bytes(ctx, c.baseURL+"/schemas/"+name, download.Options{
+		Client:  c.httpClient,
+		Header:  c.authorize(http.Header{"Accept": {"application/json"}}),
+		Retries: c.retries,
+		Backoff: c.backoff,
+		Digest:  digest,
+		Offline: c.offline,
+	})
+	var statusErr *download.StatusError
+	if errors.As(err, &statusErr) {
+		return nil, remoteError(statusErr.StatusCode, statusErr.Status, bytes.NewReader(statusErr.Body))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("registry request failed: %w", err)
+	}
+	schema, err := core.ParseSchema(raw)
+	if err != nil {
+		return nil, err
+	}
+	schema.Origin = c.baseURL + "/schemas/" + name
+	return schema, nil
+}
+
+// Put uploads a schema to the registry under name
+func (c *Client) Put(ctx context.Context, name string, schema *core.TemplateSchema) (*Entry, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal schema: %w", err)
+	}
+
+	var entry Entry
+	if err := c.do(ctx, http.MethodPut, "/schemas/"+name, body, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Extract asks the registry to extract a schema from one of its reference projects
+func (c *Client) Extract(ctx context.Context, req ExtractRequest) (*Entry, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry Entry
+	if err := c.do(ctx, http.MethodPost, "/extract", body, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Generate asks the registry to generate a project and copies the returned archive to w
+func (c *Client) Generate(ctx context.Context, req GenerateRequest, w io.Writer) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.send(ctx, http.MethodPost, "/generate", body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to download archive: %w", err)
+	}
+	return nil
+}
+
+// do sends a request and decodes the JSON response into out
+func (c *Client) do(ctx context.Context, method, path string, body []byte, out any) error {
+	resp, err := c.send(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode registry response: %w", err)
+	}
+	return nil
+}
+
+// send sends a request and turns error responses into a RemoteError.
+// The caller must close the body of the returned response.
+func (c *Client) send(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	if c.offline {
+		return nil, fmt.Errorf("cannot reach registry %s: %w", c.baseURL, download.ErrOffline)
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	c.authorize(req.Header)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("registry request failed: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		return nil, remoteError(resp.StatusCode, resp.Status, resp.Body)
+	}
+
+	return resp, nil
+}
+
+// remoteError turns an error response into a RemoteError
+func remoteError(statusCode int, status string, body io.Reader) error {
+	var apiErr errorResponse
+	if err := json.NewDecoder(body).Decode(&apiErr); err != nil || apiErr.Error == "" {
+		return fmt.Errorf("registry returned %s", status)
+	}
+	return &RemoteError{StatusCode: statusCode, Message: apiErr.Error}
+}
+
+// RemoteError is an error reported by the registry server
+type RemoteError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *RemoteError) Error() string {
+	return fmt.Sprintf("registry error (%d): %s", e.StatusCode, e.Message)
+}
+
+// Is makes errors.Is(err, ErrNotFound) work for missing schemas
+func (e *RemoteError) Is(target error) bool {
+	return target == ErrNotFound && e.StatusCode == http.StatusNotFound
+}
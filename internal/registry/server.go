@@ -0,0 +1,353 @@
+package registry
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/acheevo/template-engine/internal/archive"
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/download"
+	"github.com/acheevo/template-engine/internal/generate"
+)
+
+// maxUploadSize limits the size of uploaded schemas
+const maxUploadSize = 64 << 20 // 64MB
+
+// TokenEnv names the environment variable holding the bearer token of a registry: the token
+// the server requires for writes, and the one clients send
+const TokenEnv = "TEMPLATE_ENGINE_REGISTRY_TOKEN"
+
+// ExtractRequest asks the server to extract a schema from one of its reference projects
+type ExtractRequest struct {
+	Type  string `json:"type"`
+	Name  string `json:"name,omitempty"` // Defaults to the template type
+	Codec string `json:"codec,omitempty"`
+}
+
+// GenerateRequest asks the server to generate a project from a stored schema
+type GenerateRequest struct {
+	Schema      string `json:"schema"`
+	ProjectName string `json:"project_name"`
+	GitHubRepo  string `json:"github_repo"`
+	Author      string `json:"author,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Variables sets the custom variables the schema declares, or built-in ones by name
+	Variables map[string]string `json:"variables,omitempty"`
+	// Enable and Disable switch optional features of the schema
+	Enable  []string `json:"enable,omitempty"`
+	Disable []string `json:"disable,omitempty"`
+	// Env values are written to a .env file next to each .env.example in the schema
+	Env map[string]string `json:"env,omitempty"`
+	// Format of the returned archive, tar.gz (default) or zip
+	Format string `json:"format,omitempty"`
+}
+
+// errorResponse is the body of every failed request
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Server exposes a Store over HTTP:
+//
+//	GET  /schemas         list schemas
+//	GET  /schemas/{name}  fetch a schema
+//	PUT  /schemas/{name}  upload a schema
+//	POST /extract         extract a schema from a server-side reference project
+//	POST /generate        generate a project from a schema, returned as an archive
+//
+// Uploads and extraction change the schemas every client pulls, so they require the bearer
+// token set with SetToken.
+type Server struct {
+	store      *Store
+	references map[string]string
+	logger     *slog.Logger
+	mux        *http.ServeMux
+	token      string
+}
+
+// NewServer creates a registry server. references maps template types to the reference project
+// directories the server may extract from; extraction is limited to these.
+func NewServer(store *Store, references map[string]string, logger *slog.Logger) *Server {
+	s := &Server{
+		store:      store,
+		references: references,
+		logger:     logger,
+		mux:        http.NewServeMux(),
+	}
+
+	s.mux.HandleFunc("GET /schemas", s.handleList)
+	s.mux.HandleFunc("GET /schemas/{name}", s.handleGet)
+	s.mux.HandleFunc("PUT /schemas/{name}", s.requireToken(s.handlePut))
+	s.mux.HandleFunc("POST /extract", s.requireToken(s.handleExtract))
+	s.mux.HandleFunc("POST /generate", s.handleGenerate)
+
+	return s
+}
+
+// SetToken sets the bearer token requests writing schemas must send in their Authorization
+// header. Without a token anyone reaching the server may write schemas.
+func (s *Server) SetToken(token string) {
+	s.token = token
+}
+
+// requireToken refuses requests without the server's bearer token, if it has one
+func (s *Server) requireToken(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.token != "" {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="template-engine"`)
+				s.writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+				return
+			}
+		}
+		handler(w, r)
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.logger.Debug("Request", "method", r.Method, "path", r.URL.Path)
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleList(w http.ResponseWriter, _ *http.Request) {
+	entries, err := s.store.List()
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, entries)
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	schema, err := s.store.Get(r.PathValue("name"))
+	if err != nil {
+		s.writeError(w, statusFor(err), err)
+		return
+	}
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	// Range requests and the published digest let clients resume and verify downloads
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", `"`+core.CalculateContentHash(string(data))+`"`)
+	w.Header().Set(download.ReprDigestHeader, download.ReprDigest(data))
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+}
+
+func (s *Server) handlePut(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxUploadSize))
+	if err != nil {
+		s.writeError(w, http.StatusRequestEntityTooLarge, err)
+		return
+	}
+
+	schema, err := core.ParseSchema(data)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("failed to parse schema: %w", err))
+		return
+	}
+
+	if err := s.store.Put(name, schema); err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.logger.Info("Schema uploaded", "name", name, "files", len(schema.Files))
+	s.writeJSON(w, http.StatusCreated, entryFor(name, schema))
+}
+
+func (s *Server) handleExtract(w http.ResponseWriter, r *http.Request) {
+	var req ExtractRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return
+	}
+
+	sourceDir, exists := s.references[req.Type]
+	if !exists {
+		s.writeError(w, http.StatusNotFound, fmt.Errorf("no reference project configured for type %q", req.Type))
+		return
+	}
+	if req.Name == "" {
+		req.Name = req.Type
+	}
+	if err := ValidateName(req.Name); err != nil {
+		s.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var codec core.Codec
+	if req.Codec != "" {
+		parsed, err := core.ParseCodec(req.Codec)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		codec = parsed
+	}
+
+	templateType, err := core.GetTemplate(req.Type)
+	if err != nil {
+		s.writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	if _, err := os.Stat(sourceDir); err != nil {
+		s.writeError(w, http.StatusInternalServerError, fmt.Errorf("reference project unavailable: %w", err))
+		return
+	}
+
+	schema, err := templateType.Extract(r.Context(), sourceDir, core.ExtractOptions{Codec: codec})
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, fmt.Errorf("failed to extract template: %w", err))
+		return
+	}
+
+	if err := s.store.Put(req.Name, schema); err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.logger.Info("Schema extracted", "name", req.Name, "type", req.Type, "files", len(schema.Files))
+	s.writeJSON(w, http.StatusCreated, entryFor(req.Name, schema))
+}
+
+func (s *Server) handleGenerate(w http.ResponseWriter, r *http.Request) {
+	var req GenerateRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+		return
+	}
+
+	format := archive.FormatTarGz
+	if req.Format != "" {
+		parsed, err := archive.ParseFormat(req.Format)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		format = parsed
+	}
+
+	schema, err := s.store.Get(req.Schema)
+	if err != nil {
+		s.writeError(w, statusFor(err), err)
+		return
+	}
+
+	// Stream the archive straight into the response, the project never touches the disk
+	response := &archiveResponse{w: w, format: format, filename: req.Schema + format.Extension()}
+	archiveWriter, err := archive.NewWriter(response, format)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	generator := generate.NewGeneratorFromSchema(schema, core.TemplateVariables{
+		ProjectName: req.ProjectName,
+		GitHubRepo:  req.GitHubRepo,
+		Author:      req.Author,
+		Description: req.Description,
+	}, "")
+	for _, name := range slices.Sorted(maps.Keys(req.Variables)) {
+		if err := generator.SetVariable(name, req.Variables[name]); err != nil {
+			s.writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	generator.SetFeatures(req.Enable, req.Disable)
+	generator.SetLogger(s.logger)
+	generator.SetOutput(archiveWriter)
+	// Anyone may upload schemas, which must not read the server's environment
+	generator.SetUntrusted(true)
+	if len(req.Env) > 0 {
+		generator.SetEnvOptions(generate.EnvOptions{File: ".env", Values: req.Env})
+	}
+
+	err = generator.Generate(r.Context())
+	if err == nil {
+		err = archiveWriter.Close()
+	}
+	if err != nil {
+		if !response.started {
+			s.writeError(w, http.StatusUnprocessableEntity, fmt.Errorf("failed to generate project: %w", err))
+			return
+		}
+		// The status is already sent, a failure now can only be logged and the archive is truncated
+		s.logger.Warn("Failed to write archive", "schema", req.Schema, "error", err)
+		return
+	}
+
+	s.logger.Info("Project generated", "schema", req.Schema, "project_name", req.ProjectName,
+		"files", generator.Result().FileCount, "format", format)
+}
+
+// archiveResponse sends the archive headers along with the first archive bytes,
+// so errors found before any file is generated can still be reported as JSON
+type archiveResponse struct {
+	w        http.ResponseWriter
+	format   archive.Format
+	filename string
+	started  bool
+}
+
+func (a *archiveResponse) Write(p []byte) (int, error) {
+	if !a.started {
+		a.started = true
+		a.w.Header().Set("Content-Type", a.format.ContentType())
+		a.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", a.filename))
+		a.w.WriteHeader(http.StatusOK)
+	}
+	return a.w.Write(p)
+}
+
+// entryFor summarizes a schema stored under name
+func entryFor(name string, schema *core.TemplateSchema) Entry {
+	return Entry{
+		Name:        name,
+		Type:        schema.Type,
+		Version:     schema.Version,
+		Description: schema.Description,
+		Files:       len(schema.Files),
+		Hash:        schema.Hash,
+	}
+}
+
+// statusFor maps store errors to HTTP status codes
+func statusFor(err error) int {
+	if errors.Is(err, ErrNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.logger.Warn("Failed to write response", "error", err)
+	}
+}
+
+func (s *Server) writeError(w http.ResponseWriter, status int, err error) {
+	s.logger.Debug("Request failed", "status", status, "error", err)
+	s.writeJSON(w, status, errorResponse{Error: err.Error()})
+}
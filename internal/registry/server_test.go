@@ -0,0 +1,407 @@
+package registry
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/download"
+	"github.com/acheevo/template-engine/internal/logging"
+	_ "github.com/acheevo/template-engine/internal/templates" // Register template types
+)
+
+// newTestClient starts a registry server and returns a client for it
+func newTestClient(t *testing.T, references map[string]string) (*Client, *Store) {
+	t.Helper()
+
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(NewServer(store, references, logging.Discard()))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(server.URL, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, store
+}
+
+// testSchema returns a minimal valid schema
+func testSchema() *core.TemplateSchema {
+	content := "# {{.ProjectName}}\n"
+	return &core.TemplateSchema{
+		Name:      "test-template",
+		Type:      "frontend",
+		Version:   "1.0.0",
+		Variables: map[string]core.Variable{"ProjectName": {Type: "string", Required: true}},
+		Files: []core.FileSpec{
+			{Path: "README.md", Content: content, Template: true, Hash: core.CalculateContentHash(content)},
+		},
+	}
+}
+
+func TestPutGetList(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	ctx := context.Background()
+
+	entry, err := client.Put(ctx, "frontend", testSchema())
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if entry.Name != "frontend" || entry.Files != 1 {
+		t.Errorf("Put() entry = %+v", entry)
+	}
+
+	schema, err := client.Get(ctx, "frontend")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if schema.Name != "test-template" || schema.SchemaVersion != core.CurrentSchemaVersion {
+		t.Errorf("Get() returned %+v", schema)
+	}
+	if !schema.IsRemote() || !strings.HasSuffix(schema.Origin, "/schemas/frontend") {
+		t.Errorf("Get() origin = %q, want the registry URL of the schema", schema.Origin)
+	}
+
+	entries, err := client.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name != "frontend" || entries[0].Type != "frontend" {
+		t.Errorf("List() = %+v", entries)
+	}
+}
+
+func TestListDeprecatedSchema(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	ctx := context.Background()
+
+	schema := testSchema()
+	schema.Deprecated = true
+	schema.ReplacedBy = "frontend-v2"
+	if _, err := client.Put(ctx, "frontend", schema); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	entries, err := client.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(entries) != 1 || !entries[0].Deprecated || entries[0].ReplacedBy != "frontend-v2" {
+		t.Errorf("List() = %+v, want the deprecated entry with its successor", entries)
+	}
+}
+
+func TestGetMissingSchema(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+
+	if _, err := client.Get(context.Background(), "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGetResumableAndVerified(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	ctx := context.Background()
+
+	if _, err := client.Put(ctx, "frontend", testSchema()); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, client.baseURL+"/schemas/frontend", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Range", "bytes=10-")
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusPartialContent || resp.Header.Get(download.ReprDigestHeader) == "" {
+		t.Errorf("range request = %s with digest %q, want a partial response with the schema digest",
+			resp.Status, resp.Header.Get(download.ReprDigestHeader))
+	}
+
+	if _, err := client.GetVerified(ctx, "frontend", "sha256:"+strings.Repeat("0", 64)); err == nil {
+		t.Error("GetVerified() with a wrong digest should fail")
+	}
+}
+
+func TestPutRejectsInvalidSchema(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+
+	schema := testSchema()
+	schema.Files[0].Hash = "bogus"
+	if _, err := client.Put(context.Background(), "frontend", schema); err == nil {
+		t.Error("Put() should reject a schema with a hash mismatch")
+	}
+}
+
+func TestInvalidNames(t *testing.T) {
+	for _, name := range []string{"", "..", "../etc", "a/b", ".hidden", "a..b"} {
+		if err := ValidateName(name); err == nil {
+			t.Errorf("ValidateName(%q) should fail", name)
+		}
+	}
+
+	client, _ := newTestClient(t, nil)
+	resp, err := http.Get(client.baseURL + "/schemas/..%2Fsecret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest && resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET with traversal name returned %d", resp.StatusCode)
+	}
+}
+
+func TestExtract(t *testing.T) {
+	sourceDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(sourceDir, "package.json"), []byte(`{"name": "app"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	client, store := newTestClient(t, map[string]string{"frontend": sourceDir})
+	ctx := context.Background()
+
+	entry, err := client.Extract(ctx, ExtractRequest{Type: "frontend", Name: "web"})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if entry.Name != "web" || entry.Type != "frontend" || entry.Files != 1 {
+		t.Errorf("Extract() entry = %+v", entry)
+	}
+	if _, err := store.Get("web"); err != nil {
+		t.Errorf("Extracted schema was not stored: %v", err)
+	}
+
+	// Only configured reference projects can be extracted
+	_, err = client.Extract(ctx, ExtractRequest{Type: "go-api"})
+	if err == nil || !strings.Contains(err.Error(), "no reference project") {
+		t.Errorf("Extract() for unconfigured type error = %v", err)
+	}
+}
+
+func TestServerToken(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := NewServer(store, nil, logging.Discard())
+	handler.SetToken("s3cret")
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	ctx := context.Background()
+
+	client, err := NewClient(server.URL, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Put(ctx, "frontend", testSchema())
+	var remote *RemoteError
+	if !errors.As(err, &remote) || remote.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Put() without a token error = %v, want 401", err)
+	}
+	client.SetToken("wrong")
+	if _, err := client.Put(ctx, "frontend", testSchema()); !errors.As(err, &remote) ||
+		remote.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Put() with a wrong token error = %v, want 401", err)
+	}
+	if _, err := client.Extract(ctx, ExtractRequest{Type: "frontend"}); !errors.As(err, &remote) ||
+		remote.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Extract() with a wrong token error = %v, want 401", err)
+	}
+
+	client.SetToken("s3cret")
+	if _, err := client.Put(ctx, "frontend", testSchema()); err != nil {
+		t.Fatalf("Put() with the token error = %v", err)
+	}
+
+	// Reading needs no token
+	anonymous, err := NewClient(server.URL, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := anonymous.Get(ctx, "frontend"); err != nil {
+		t.Errorf("Get() without a token error = %v", err)
+	}
+}
+
+func TestNewClientRejectsInvalidURL(t *testing.T) {
+	for _, url := range []string{"", "localhost:8080", "ftp://example.com"} {
+		if _, err := NewClient(url, nil); err == nil {
+			t.Errorf("NewClient(%q) should fail", url)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	ctx := context.Background()
+
+	if _, err := client.Put(ctx, "frontend", testSchema()); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	req := GenerateRequest{Schema: "frontend", ProjectName: "My App", GitHubRepo: "user/my-app", Format: "zip"}
+	if err := client.Generate(ctx, req, &buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Response is not a zip archive: %v", err)
+	}
+	if len(zr.File) != 1 || zr.File[0].Name != "README.md" {
+		t.Fatalf("Unexpected archive entries: %v", zr.File)
+	}
+	rc, err := zr.File[0].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	content, _ := io.ReadAll(rc)
+	if string(content) != "# My App\n" {
+		t.Errorf("README.md = %q", content)
+	}
+}
+
+func TestGenerateHidesServerEnvironment(t *testing.T) {
+	t.Setenv("REGISTRY_TEST_SECRET", "s3cret")
+	client, _ := newTestClient(t, nil)
+	ctx := context.Background()
+
+	schema := testSchema()
+	content := "secret={{ env \"REGISTRY_TEST_SECRET\" }}\n"
+	schema.Files[0] = core.FileSpec{Path: "README.md", Content: content, Template: true,
+		Hash: core.CalculateContentHash(content)}
+	if _, err := client.Put(ctx, "frontend", schema); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	req := GenerateRequest{Schema: "frontend", ProjectName: "My App", GitHubRepo: "user/my-app", Format: "zip"}
+	if err := client.Generate(ctx, req, &buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if got := readArchiveFile(t, buf.Bytes(), "README.md"); got != "secret=\n" {
+		t.Errorf("README.md = %q, want the server environment hidden from uploaded schemas", got)
+	}
+}
+
+// readArchiveFile returns the content of the file at name in a zip archive
+func readArchiveFile(t *testing.T, data []byte, name string) string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Response is not a zip archive: %v", err)
+	}
+	rc, err := zr.Open(name)
+	if err != nil {
+		t.Fatalf("Archive has no %s: %v", name, err)
+	}
+	defer rc.Close()
+	content, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestGenerateVariablesAndFeatures(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	ctx := context.Background()
+
+	schema := testSchema()
+	content := "# {{.ProjectName}} by {{.Author}}: {{.Description}} ({{.Team}})\n"
+	schema.Variables["Team"] = core.Variable{Type: "string", Required: true}
+	schema.Files = []core.FileSpec{
+		{Path: "README.md", Content: content, Template: true, Hash: core.CalculateContentHash(content)},
+		{Path: "docker/Dockerfile", Content: "FROM scratch\n", Hash: core.CalculateContentHash("FROM scratch\n")},
+	}
+	schema.Features = []core.Feature{{Name: "docker", Default: true, Files: []string{"docker/**"}}}
+	if _, err := client.Put(ctx, "frontend", schema); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	req := GenerateRequest{
+		Schema: "frontend", ProjectName: "My App", GitHubRepo: "user/my-app", Format: "zip",
+		Author: "Jane", Description: "Billing UI", Variables: map[string]string{"Team": "billing"},
+		Disable: []string{"docker"},
+	}
+	var buf bytes.Buffer
+	if err := client.Generate(ctx, req, &buf); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if got := readArchiveFile(t, buf.Bytes(), "README.md"); got != "# My App by Jane: Billing UI (billing)\n" {
+		t.Errorf("README.md = %q", got)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zr.File) != 1 {
+		t.Errorf("Archive has %d files, want the disabled docker feature left out", len(zr.File))
+	}
+
+	// Required custom variables must be given, and only declared ones are accepted
+	tests := []struct {
+		name      string
+		variables map[string]string
+		want      int
+	}{
+		{"missing custom variable", nil, http.StatusUnprocessableEntity},
+		{"undeclared variable", map[string]string{"Team": "billing", "Other": "x"}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req.Variables = tt.variables
+		err := client.Generate(ctx, req, io.Discard)
+		var remote *RemoteError
+		if !errors.As(err, &remote) || remote.StatusCode != tt.want {
+			t.Errorf("%s: Generate() error = %v, want status %d", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	client, _ := newTestClient(t, nil)
+	ctx := context.Background()
+
+	if _, err := client.Put(ctx, "frontend", testSchema()); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		req  GenerateRequest
+		want int
+	}{
+		{"missing schema", GenerateRequest{Schema: "missing", ProjectName: "x"}, http.StatusNotFound},
+		{"unknown format", GenerateRequest{Schema: "frontend", ProjectName: "x", Format: "rar"}, http.StatusBadRequest},
+		{"missing variable", GenerateRequest{Schema: "frontend"}, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := client.Generate(ctx, tt.req, io.Discard)
+			var remote *RemoteError
+			if !errors.As(err, &remote) || remote.StatusCode != tt.want {
+				t.Errorf("Generate() error = %v, want status %d", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+// ErrNotFound is returned when a schema does not exist in the store
+var ErrNotFound = errors.New("schema not found")
+
+// schemaName restricts schema names to safe file names
+var schemaName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Entry summarizes a stored schema
+type Entry struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+	Files       int    `json:"files"`
+	Hash        string `json:"hash,omitempty"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
+	ReplacedBy  string `json:"replaced_by,omitempty"`
+}
+
+// Store keeps schemas as <name>.json files in a directory
+type Store struct {
+	dir string
+}
+
+// NewStore creates a store backed by dir, creating it if needed
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create registry directory: %w", err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+// ValidateName checks that name can be used as a schema name
+func ValidateName(name string) error {
+	if !schemaName.MatchString(name) || strings.Contains(name, "..") {
+		return fmt.Errorf("invalid schema name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+	return nil
+}
+
+// List returns the stored schemas sorted by name
+func (s *Store) List() ([]Entry, error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	entries := []Entry{}
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".json")
+		schema, err := core.LoadSchemaFile(path)
+		if err != nil {
+			continue // Not a schema, ignore
+		}
+		entries = append(entries, Entry{
+			Name:        name,
+			Type:        schema.Type,
+			Version:     schema.Version,
+			Description: schema.Description,
+			Files:       len(schema.Files),
+			Hash:        schema.Hash,
+			Deprecated:  schema.Deprecated,
+			ReplacedBy:  schema.ReplacedBy,
+		})
+	}
+
+	return entries, nil
+}
+
+// Get loads a stored schema
+func (s *Store) Get(name string) (*core.TemplateSchema, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+
+	path := s.path(name)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return core.LoadSchemaFile(path)
+}
+
+// Put validates and stores a schema under name, replacing any previous version
+func (s *Store) Put(name string, schema *core.TemplateSchema) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if err := core.ValidateSchema(schema); err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
+	}
+
+	// Write to a temporary file first so readers never see a partial schema
+	tmp := s.path(name) + ".tmp"
+	if err := core.SaveSchemaFile(schema, tmp); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path(name))
+}
+
+func (s *Store) path(name string) string {
+	return filepath.Join(s.dir, name+".json")
+}
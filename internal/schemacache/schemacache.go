@@ -0,0 +1,150 @@
+// Package schemacache stores extracted schemas in the user cache directory, keyed by the content
+// of the reference project, the template type and the engine version
+package schemacache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+// Cache is a directory of extracted schemas
+type Cache struct {
+	dir string
+}
+
+// New returns a cache storing schemas in dir
+func New(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// Default returns the cache in the user cache directory, e.g. ~/.cache/template-engine/schemas
+func Default() (*Cache, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate cache directory: %w", err)
+	}
+	return New(filepath.Join(cacheDir, "template-engine", "schemas")), nil
+}
+
+// Dir returns the directory the cache stores schemas in
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Key returns the cache key of extracting fsys with templateType: a hash over the paths and
+// contents of the files the template type extracts, its name, the codec and the engine version.
+// Files the template type skips, such as node_modules, do not affect the key.
+func Key(ctx context.Context, fsys fs.FS, templateType core.TemplateType, codec core.Codec) (string, error) {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\x00%s\x00%s\x00", templateType.Name(), codec, core.EngineVersion)
+
+	err := fs.WalkDir(fsys, ".", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if entry.IsDir() || templateType.ShouldSkip(filepath.FromSlash(path)) {
+			return nil
+		}
+
+		if entry.Type()&fs.ModeSymlink != 0 {
+			if links, ok := fsys.(core.ReadLinkFS); ok {
+				target, err := links.ReadLink(path)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(hash, "%s\x00->%s\x00", path, target)
+			}
+			// Only symlinks to files may be embedded, dangling ones and directories never are
+			if info, err := fs.Stat(fsys, path); err != nil || info.IsDir() {
+				return nil // The target alone identifies such symlinks
+			}
+		}
+
+		file, err := fsys.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		content := sha256.New()
+		if _, err := io.Copy(content, file); err != nil {
+			return err
+		}
+		fmt.Fprintf(hash, "%s\x00%x\x00", path, content.Sum(nil))
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash reference project: %w", err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Get returns the schema stored under key, false when there is none or it cannot be read
+func (c *Cache) Get(key string) (*core.TemplateSchema, bool) {
+	schema, err := core.LoadSchemaFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	return schema, true
+}
+
+// Put stores schema under key
+func (c *Cache) Put(key string, schema *core.TemplateSchema) error {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// Write to a temporary file first so concurrent runs never read a partial schema
+	temp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write cached schema: %w", err)
+	}
+	temp.Close()
+	defer os.Remove(temp.Name())
+
+	if err := core.SaveSchemaFile(schema, temp.Name()); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), c.path(key))
+}
+
+// Clear removes every cached schema and returns how many there were
+func (c *Cache) Clear() (int, error) {
+	entries, err := os.ReadDir(c.dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		if err := os.Remove(filepath.Join(c.dir, entry.Name())); err != nil {
+			return removed, fmt.Errorf("failed to remove cached schema: %w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// path returns the file of the schema stored under key
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
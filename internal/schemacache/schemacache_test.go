@@ -0,0 +1,79 @@
+package schemacache
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/templates"
+)
+
+func TestKey(t *testing.T) {
+	ctx := context.Background()
+	templateType := &templates.GoAPITemplate{}
+	project := func() fstest.MapFS {
+		return fstest.MapFS{
+			"go.mod":     {Data: []byte("module example.com/app\n")},
+			"cmd/api.go": {Data: []byte("package main\n")},
+		}
+	}
+
+	key, err := Key(ctx, project(), templateType, core.CodecGzip)
+	if err != nil {
+		t.Fatalf("Key() error = %v", err)
+	}
+
+	same, _ := Key(ctx, project(), templateType, core.CodecGzip)
+	if same != key {
+		t.Errorf("Key() is not stable: %s != %s", same, key)
+	}
+
+	changed := project()
+	changed["cmd/api.go"] = &fstest.MapFile{Data: []byte("package main\n\nfunc main() {}\n")}
+	if other, _ := Key(ctx, changed, templateType, core.CodecGzip); other == key {
+		t.Error("Key() did not change with file content")
+	}
+
+	if other, _ := Key(ctx, project(), templateType, core.CodecZstd); other == key {
+		t.Error("Key() did not change with codec")
+	}
+
+	skipped := project()
+	skipped["vendor/lib/lib.go"] = &fstest.MapFile{Data: []byte("package lib\n")}
+	if other, _ := Key(ctx, skipped, templateType, core.CodecGzip); other != key {
+		t.Error("Key() changed with a skipped file")
+	}
+}
+
+func TestCache(t *testing.T) {
+	cache := New(t.TempDir())
+
+	if _, ok := cache.Get("missing"); ok {
+		t.Error("Get() found a schema that was never stored")
+	}
+
+	schema := &core.TemplateSchema{Name: "app", Type: "go-api", Version: "1.0.0"}
+	if err := cache.Put("key", schema); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	cached, ok := cache.Get("key")
+	if !ok {
+		t.Fatal("Get() did not find the stored schema")
+	}
+	if cached.Name != schema.Name || cached.Type != schema.Type {
+		t.Errorf("Get() = %+v, want %+v", cached, schema)
+	}
+
+	removed, err := cache.Clear()
+	if err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Clear() removed %d schemas, want 1", removed)
+	}
+	if _, ok := cache.Get("key"); ok {
+		t.Error("Get() found a schema after Clear()")
+	}
+}
@@ -1,8 +1,13 @@
 package templates
 
 import (
+	"cmp"
 	"path/filepath"
+	"slices"
 	"strings"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/envparser"
 )
 
 // Common template file names
@@ -10,38 +15,82 @@ const (
 	ReadmeFile = "README.md"
 )
 
-// shouldSkipCommon contains common logic for skipping files during template extraction
-func shouldSkipCommon(path string, skipDirs []string) bool {
-	// Always include .github directories and their contents
-	if strings.Contains(path, ".github") {
-		return false
+// packageJSONPatches sets the project fields of a package.json in dir of the repository
+func packageJSONPatches(dir string) []core.Patch {
+	repository := map[string]any{"type": "git", "url": "https://github.com/{{.GitHubRepo}}.git"}
+	if dir != "" {
+		repository["directory"] = dir
 	}
-
-	// Skip .git directory and all its contents
-	if strings.Contains(path, ".git") && !strings.Contains(path, ".github") {
-		return true
+	return []core.Patch{
+		{Path: "name", Value: "{{.ProjectNameKebab}}"},
+		{Path: "description", Value: "{{.Description}}"},
+		{Path: "repository", Value: repository},
+		{Path: "author", Value: "{{.Author}}"},
 	}
+}
+
+// newExtractor creates the shared extraction walker for a template type
+func newExtractor(policy core.ExtractPolicy) *core.Extractor {
+	return &core.Extractor{Policy: policy, ParseEnv: envparser.ParseEnvExample}
+}
 
-	baseName := filepath.Base(path)
+// skipRules declares the files a template type leaves out of its schemas. Patterns follow
+// .gitignore syntax (see core.ParseIgnore) and match whole path segments, so a bin directory is
+// skipped but binomial.go is not.
+type skipRules struct {
+	// Files are skipped with their reason before any other rule, such as compiled binaries
+	Files []skipPattern
+	// Dirs are skipped wherever they appear, dotfiles they contain included
+	Dirs []string
+	// Keep lists the hidden files extracted nonetheless, besides the .claude and .github
+	// directories which always are
+	Keep []string
+}
 
-	// Skip other hidden files/directories (starting with .) except .github
-	if strings.HasPrefix(baseName, ".") && baseName != ".github" && !strings.Contains(path, ".github") {
-		return true
-	}
+// skipPattern is a pattern of skipped files and the reason reported for them, "matched skip
+// pattern" and the pattern when empty
+type skipPattern struct {
+	Pattern string
+	Reason  string
+}
 
-	// Skip specific directories (check if directory appears anywhere in path)
-	for _, dir := range skipDirs {
-		if strings.Contains(path, string(filepath.Separator)+dir+string(filepath.Separator)) ||
-			strings.HasSuffix(path, string(filepath.Separator)+dir) ||
-			strings.HasPrefix(path, dir+string(filepath.Separator)) {
-			return true
+// alwaysKept are the hidden directories extracted by every template type
+var alwaysKept = []string{".claude", ".github"}
+
+// reason returns why path is skipped, empty when it is extracted. Besides the rules, git
+// metadata, hidden files and logs are skipped.
+func (r skipRules) reason(path string) string {
+	slashPath := filepath.ToSlash(path)
+
+	for _, file := range r.Files {
+		if matchPattern(file.Pattern, slashPath) {
+			return cmp.Or(file.Reason, "matched skip pattern "+file.Pattern)
+		}
+	}
+	for _, dir := range r.Dirs {
+		if matchPattern(dir+"/", slashPath) {
+			return core.SkipDirReason(dir)
+		}
+	}
+	for _, keep := range slices.Concat(alwaysKept, r.Keep) {
+		if matchPattern(keep, slashPath) {
+			return ""
 		}
 	}
 
-	// Skip file patterns
-	if strings.HasSuffix(baseName, ".log") {
-		return true
+	switch {
+	case matchPattern(".git", slashPath):
+		return core.SkipReasonGit
+	case strings.HasPrefix(filepath.Base(path), "."):
+		return core.SkipReasonHidden
+	case matchPattern("*.log", slashPath):
+		return "matched skip pattern *.log"
 	}
+	return ""
+}
 
-	return false
+// matchPattern reports whether the slash-separated path or one of its directories matches the
+// .gitignore pattern
+func matchPattern(pattern, path string) bool {
+	return core.ParseIgnore(pattern).Ignores(path)
 }
@@ -1,135 +1,152 @@
 package templates
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
-	"os"
-	"path/filepath"
-	"strings"
+	"context"
+	"io/fs"
 
 	"github.com/acheevo/template-engine/internal/core"
-	"github.com/acheevo/template-engine/internal/envparser"
 )
 
 // FrontendTemplate implements TemplateType for React/frontend projects
 type FrontendTemplate struct{}
 
+// frontendName is the name of the reference project, which also names its import alias
+// (@frontend-template/*) and its base path
+const frontendName = "frontend-template"
+
+// frontendAliasMappings rename the @frontend-template/* import alias in its tsconfig and vite
+// declarations and in the imports using it
+var frontendAliasMappings = []core.Mapping{
+	{Find: `(["'])@` + frontendName + `(["'/])`, Replace: "${1}@{{.ProjectNameKebab}}${2}", Regex: true},
+}
+
 // Name returns the template type name
 func (f *FrontendTemplate) Name() string {
 	return "frontend"
 }
 
+// Description describes the projects the template type extracts
+func (f *FrontendTemplate) Description() string {
+	return "React + TypeScript + Vite frontend template"
+}
+
+// Category returns the kind of project the template type extracts
+func (f *FrontendTemplate) Category() string {
+	return core.CategoryFrontend
+}
+
+// Tags returns the keywords of the projects the template type extracts
+func (f *FrontendTemplate) Tags() []string {
+	return []string{"react", "typescript", "vite", "tailwind"}
+}
+
+// Markers returns the paths a reference project of the template type contains
+func (f *FrontendTemplate) Markers() []string {
+	return []string{"package.json", "vite.config.*"}
+}
+
 // Extract analyzes a frontend project and creates a template schema
-func (f *FrontendTemplate) Extract(sourceDir string) (*core.TemplateSchema, error) {
+func (f *FrontendTemplate) Extract(
+	ctx context.Context, sourceDir string, opts core.ExtractOptions,
+) (*core.TemplateSchema, error) {
+	return f.ExtractFS(ctx, core.DirFS(sourceDir), opts)
+}
+
+// ExtractFS extracts the template from any file tree, such as a go:embed bundle
+func (f *FrontendTemplate) ExtractFS(
+	ctx context.Context, fsys fs.FS, opts core.ExtractOptions,
+) (*core.TemplateSchema, error) {
 	schema := &core.TemplateSchema{
 		Name:        "frontend-react-template",
 		Type:        "frontend",
 		Version:     "1.0.0",
 		Description: "React TypeScript frontend template with Tailwind CSS",
 		Variables:   f.GetVariables(),
-		Files:       []core.FileSpec{},
-		EnvConfig:   []core.EnvVariable{}, // Initialize as empty slice
-		Hooks: map[string][]string{
-			"post_generate": {"npm install"},
+		Tags:        f.Tags(),
+		Category:    f.Category(),
+		Hooks: core.Hooks{
+			{Name: "install", Stage: core.HookPostGenerate, Command: "npm install", Condition: "command:npm"},
 		},
 	}
 
-	err := filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// Skip directories and files that should be skipped
-		if info.IsDir() || f.ShouldSkip(path) {
-			return nil
-		}
-
-		relPath, err := filepath.Rel(sourceDir, path)
-		if err != nil {
-			return err
-		}
-
-		// Read file content (go-fsck pattern: always include full content)
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return err
-		}
-
-		// Process content (compression if needed)
-		contentStr := string(content)
-		compressedContent, isCompressed, err := core.CompressContent(contentStr)
-		if err != nil {
-			return err
-		}
-
-		// Calculate hash of original content
-		hash := sha256.Sum256(content)
-		hashStr := hex.EncodeToString(hash[:])
-
-		// Determine if this file needs templating
-		isTemplate := f.ShouldTemplate(relPath)
-
-		fileSpec := core.FileSpec{
-			Path:       relPath,
-			Template:   isTemplate,
-			Content:    compressedContent, // May be compressed
-			Size:       info.Size(),
-			Hash:       hashStr,
-			Compressed: isCompressed,
-		}
-
-		// Add mappings for templated files
-		if isTemplate {
-			fileSpec.Mappings = f.GetMappings(relPath)
-		}
-
-		schema.Files = append(schema.Files, fileSpec)
-		return nil
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	// Parse .env.example if it exists
-	envExamplePath := filepath.Join(sourceDir, ".env.example")
-	if _, err := os.Stat(envExamplePath); err == nil {
-		envContent, err := os.ReadFile(envExamplePath)
-		if err == nil {
-			schema.EnvConfig = envparser.ParseEnvExample(string(envContent))
-		}
-	}
-
-	// Calculate schema hash
-	schema.Hash = f.calculateSchemaHash(schema)
-
-	return schema, nil
+	return newExtractor(f).ExtractFS(ctx, fsys, schema, opts)
 }
 
-// GetMappings returns the string replacement mappings for a specific file
-func (f *FrontendTemplate) GetMappings(filePath string) []core.Mapping {
-	switch filePath {
-	case "package.json":
-		return []core.Mapping{
-			{Find: "\"frontend-template\"", Replace: "\"{{.ProjectName}}\""},
-			{Find: "\"Your Name\"", Replace: "\"{{.Author}}\""},
-		}
-	case "src/config/app.ts":
-		return []core.Mapping{
+// frontendMappingRules holds the string replacement mappings per file pattern
+var frontendMappingRules = []core.MappingRule{
+	{
+		Pattern: "src/config/app.ts",
+		Mappings: []core.Mapping{
 			{Find: "'Frontend Template'", Replace: "'{{.ProjectName}}'"},
 			{Find: "'Your Name'", Replace: "'{{.Author}}'"},
-		}
-	case ReadmeFile:
-		return []core.Mapping{
+		},
+	},
+	{
+		Pattern: ReadmeFile,
+		Mappings: []core.Mapping{
 			{Find: "# Frontend Template", Replace: "# {{.ProjectName}}"},
 			{Find: "https://github.com/your-username/frontend-template", Replace: "https://github.com/{{.GitHubRepo}}"},
-		}
-	case "index.html":
-		return []core.Mapping{
+		},
+	},
+	{
+		Pattern: "index.html",
+		Mappings: []core.Mapping{
 			{Find: "<title>Frontend Template</title>", Replace: "<title>{{.ProjectName}}</title>"},
-		}
-	default:
-		return []core.Mapping{}
-	}
+		},
+	},
+	{
+		Pattern: "vite.config.*",
+		Mappings: []core.Mapping{
+			{Find: `(base:\s*["'])/` + frontendName + `/`, Replace: "${1}/{{.RepoName}}/", Regex: true},
+		},
+	},
+	{Pattern: "vite.config.*", Mappings: frontendAliasMappings},
+	{Pattern: "tsconfig*.json", Mappings: frontendAliasMappings},
+	{Pattern: "src/**/*.ts", Mappings: frontendAliasMappings},
+	{Pattern: "src/**/*.tsx", Mappings: frontendAliasMappings},
+	{
+		Pattern: "public/manifest.json",
+		Mappings: []core.Mapping{
+			{Find: `"Frontend Template"`, Replace: `"{{.ProjectName}}"`},
+		},
+	},
+	{
+		Pattern: "public/*.webmanifest",
+		Mappings: []core.Mapping{
+			{Find: `"Frontend Template"`, Replace: `"{{.ProjectName}}"`},
+		},
+	},
+}
+
+// frontendTemplatePatterns lists the files that need template processing: besides the
+// documents naming the project, the build configuration and the sources using the import alias
+var frontendTemplatePatterns = []string{
+	ReadmeFile,
+	"index.html",
+	"vite.config.*",
+	"tsconfig*.json",
+	"src/**/*.ts",
+	"src/**/*.tsx",
+	"public/manifest.json",
+	"public/*.webmanifest",
+}
+
+// GetMappings returns the string replacement mappings for a specific file
+func (f *FrontendTemplate) GetMappings(filePath string) []core.Mapping {
+	return core.MappingsFor(frontendMappingRules, filePath)
+}
+
+// frontendPatchRules holds the structured patches per file pattern
+var frontendPatchRules = []core.PatchRule{
+	{
+		Pattern: "package.json",
+		Patches: packageJSONPatches(""),
+	},
+}
+
+// GetPatches returns the structured patches for a specific file
+func (f *FrontendTemplate) GetPatches(filePath string) []core.Patch {
+	return core.PatchesFor(frontendPatchRules, filePath)
 }
 
 // GetVariables returns the variables used by this template type
@@ -162,28 +179,18 @@ func (f *FrontendTemplate) GetVariables() map[string]core.Variable {
 
 // ShouldTemplate determines if a file needs template processing
 func (f *FrontendTemplate) ShouldTemplate(filePath string) bool {
-	templatedFiles := []string{
-		"package.json",
-		ReadmeFile,
-		"src/config/app.ts",
-		"index.html",
-	}
-
-	for _, file := range templatedFiles {
-		if filePath == file {
-			return true
-		}
-	}
-
-	return false
+	return core.MatchAnyGlob(frontendTemplatePatterns, filePath)
 }
 
 // ShouldSkip determines if a file/directory should be skipped during extraction
 func (f *FrontendTemplate) ShouldSkip(path string) bool {
-	baseName := filepath.Base(path)
+	return f.SkipReason(path) != ""
+}
 
-	// Always include important frontend dotfiles
-	importantDotfiles := []string{
+// frontendSkipRules holds the files left out of frontend schemas
+var frontendSkipRules = skipRules{
+	Dirs: []string{"node_modules", "dist", "build", "coverage"},
+	Keep: []string{
 		".eslintrc.cjs",
 		".eslintrc.js",
 		".eslintrc.json",
@@ -192,41 +199,10 @@ func (f *FrontendTemplate) ShouldSkip(path string) bool {
 		".dockerignore",
 		".gitignore",
 		".env.example",
-	}
-
-	for _, dotfile := range importantDotfiles {
-		if baseName == dotfile {
-			return false
-		}
-	}
-
-	// Always include .claude directory and its contents
-	if strings.Contains(path, ".claude") {
-		return false
-	}
-
-	skipDirs := []string{
-		"node_modules",
-		"dist",
-		"build",
-		"coverage",
-	}
-	return shouldSkipCommon(path, skipDirs)
+	},
 }
 
-// calculateSchemaHash calculates a hash for the entire schema
-func (f *FrontendTemplate) calculateSchemaHash(schema *core.TemplateSchema) string {
-	// Create a deterministic string representation of the schema
-	var content strings.Builder
-	content.WriteString(schema.Name)
-	content.WriteString(schema.Type)
-	content.WriteString(schema.Version)
-
-	for _, file := range schema.Files {
-		content.WriteString(file.Path)
-		content.WriteString(file.Hash)
-	}
-
-	hash := sha256.Sum256([]byte(content.String()))
-	return hex.EncodeToString(hash[:])
+// SkipReason returns why a file/directory is skipped during extraction, empty when it is not
+func (f *FrontendTemplate) SkipReason(path string) string {
+	return frontendSkipRules.reason(path)
 }
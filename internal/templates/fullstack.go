@@ -1,14 +1,11 @@
 package templates
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
-	"os"
-	"path/filepath"
+	"context"
+	"io/fs"
 	"strings"
 
 	"github.com/acheevo/template-engine/internal/core"
-	"github.com/acheevo/template-engine/internal/envparser"
 )
 
 // FullstackTemplate implements TemplateType for fullstack projects with Go API and React frontend
@@ -19,148 +16,148 @@ func (f *FullstackTemplate) Name() string {
 	return "fullstack"
 }
 
+// Description describes the projects the template type extracts
+func (f *FullstackTemplate) Description() string {
+	return "Fullstack template with Go API backend and React frontend"
+}
+
+// Category returns the kind of project the template type extracts
+func (f *FullstackTemplate) Category() string {
+	return core.CategoryFullstack
+}
+
+// Tags returns the keywords of the projects the template type extracts
+func (f *FullstackTemplate) Tags() []string {
+	return []string{"go", "react", "typescript", "postgres"}
+}
+
+// Markers returns the paths a reference project of the template type contains
+func (f *FullstackTemplate) Markers() []string {
+	return []string{"go.mod", "frontend/package.json"}
+}
+
 // Extract analyzes a fullstack project and creates a template schema
-func (f *FullstackTemplate) Extract(sourceDir string) (*core.TemplateSchema, error) {
+func (f *FullstackTemplate) Extract(
+	ctx context.Context, sourceDir string, opts core.ExtractOptions,
+) (*core.TemplateSchema, error) {
+	return f.ExtractFS(ctx, core.DirFS(sourceDir), opts)
+}
+
+// ExtractFS extracts the template from any file tree, such as a go:embed bundle
+func (f *FullstackTemplate) ExtractFS(
+	ctx context.Context, fsys fs.FS, opts core.ExtractOptions,
+) (*core.TemplateSchema, error) {
 	schema := &core.TemplateSchema{
 		Name:        "fullstack-template",
 		Type:        "fullstack",
 		Version:     "1.0.0",
 		Description: "Fullstack template with Go API backend and React frontend",
 		Variables:   f.GetVariables(),
-		Files:       []core.FileSpec{},
-		EnvConfig:   []core.EnvVariable{},
-		Hooks: map[string][]string{
-			"post_generate": {"go mod tidy", "cd frontend && npm install"},
+		Tags:        f.Tags(),
+		Category:    f.Category(),
+		GoModules:   []core.GoModule{{Path: "go.mod", Module: "github.com/{{.GitHubRepo}}"}},
+		Hooks: core.Hooks{
+			{Name: "tidy", Stage: core.HookPostGenerate, Command: "go mod tidy", Condition: "command:go"},
+			{
+				Name: "install", Stage: core.HookPostGenerate, Command: "npm install",
+				Workdir: "frontend", Condition: "command:npm",
+			},
 		},
 	}
 
-	err := filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// Skip directories and files that should be skipped
-		if info.IsDir() || f.ShouldSkip(path) {
-			return nil
-		}
-
-		relPath, err := filepath.Rel(sourceDir, path)
-		if err != nil {
-			return err
-		}
-
-		// Read file content
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return err
-		}
-
-		// Calculate hash
-		hash := sha256.Sum256(content)
-		hashStr := hex.EncodeToString(hash[:])
-
-		// Determine if this file needs templating
-		isTemplate := f.ShouldTemplate(relPath)
-
-		fileSpec := core.FileSpec{
-			Path:     relPath,
-			Template: isTemplate,
-			Content:  string(content),
-			Size:     info.Size(),
-			Hash:     hashStr,
-		}
-
-		// Add mappings for templated files
-		if isTemplate {
-			fileSpec.Mappings = f.GetMappings(relPath)
-		}
-
-		schema.Files = append(schema.Files, fileSpec)
-		return nil
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	// Parse .env.example if it exists
-	envExamplePath := filepath.Join(sourceDir, ".env.example")
-	if _, err := os.Stat(envExamplePath); err == nil {
-		envContent, err := os.ReadFile(envExamplePath)
-		if err == nil {
-			schema.EnvConfig = envparser.ParseEnvExample(string(envContent))
-		}
-	}
-
-	// Calculate schema hash
-	schema.Hash = f.calculateSchemaHash(schema)
-
-	return schema, nil
+	return newExtractor(f).ExtractFS(ctx, fsys, schema, opts)
 }
 
-// GetMappings returns the string replacement mappings for a specific file
-func (f *FullstackTemplate) GetMappings(filePath string) []core.Mapping {
-	switch filePath {
-	case "go.mod":
-		return []core.Mapping{
-			{Find: "module github.com/acheevo/fullstack-template", Replace: "module github.com/{{.GitHubRepo}}"},
-		}
-	case "cmd/api/main.go":
-		return []core.Mapping{
-			{Find: "\"github.com/acheevo/fullstack-template/", Replace: "\"github.com/{{.GitHubRepo}}/"},
-		}
-	case "README.md":
-		return []core.Mapping{
+// fullstackMappingRules holds the string replacement mappings per file pattern
+var fullstackMappingRules = []core.MappingRule{
+	{
+		Pattern: ReadmeFile,
+		Mappings: []core.Mapping{
 			{Find: "# Fullstack Template", Replace: "# {{.ProjectName}}"},
 			{Find: "# Go + React Fullstack Template", Replace: "# {{.ProjectName}}"},
 			{
 				Find:    "git clone https://github.com/acheevo/fullstack-template.git",
 				Replace: "git clone https://github.com/{{.GitHubRepo}}.git",
 			},
-			{Find: "cd fullstack-template", Replace: "cd {{.ProjectName | kebab}}"},
-		}
-	case "docker-compose.yml":
-		return []core.Mapping{
-			{Find: "fullstack-template", Replace: "{{.ProjectName | kebab}}"},
-			{Find: "fullstack_template", Replace: "{{.ProjectName | snake}}"},
-		}
-	case "internal/shared/config/config.go":
-		return []core.Mapping{
+			{Find: "cd fullstack-template", Replace: "cd {{.ProjectNameKebab}}"},
+		},
+	},
+	{
+		Pattern: "docker-compose.yml",
+		Mappings: []core.Mapping{
+			{Find: "fullstack-template", Replace: "{{.ProjectNameKebab}}"},
+			{Find: "fullstack_template", Replace: "{{.ProjectNameSnake}}"},
+		},
+	},
+	{
+		Pattern: "internal/shared/config/config.go",
+		Mappings: []core.Mapping{
 			{
 				Find:    "ServiceName    string `envconfig:\"SERVICE_NAME\" default:\"fullstack-template\"`",
-				Replace: "ServiceName    string `envconfig:\"SERVICE_NAME\" default:\"{{.ProjectName | kebab}}\"`",
+				Replace: "ServiceName    string `envconfig:\"SERVICE_NAME\" default:\"{{.ProjectNameKebab}}\"`",
 			},
 			{
 				Find:    "DBName            string `envconfig:\"DB_NAME\" default:\"fullstack_template\"`",
-				Replace: "DBName            string `envconfig:\"DB_NAME\" default:\"{{.ProjectName | snake}}\"`",
+				Replace: "DBName            string `envconfig:\"DB_NAME\" default:\"{{.ProjectNameSnake}}\"`",
 			},
-		}
-	case "Makefile":
-		return []core.Mapping{
-			{Find: "docker build -t fullstack-template", Replace: "docker build -t {{.ProjectName | kebab}}"},
-			{Find: "docker rmi fullstack-template", Replace: "docker rmi {{.ProjectName | kebab}}"},
-		}
-	case "frontend/package.json":
-		return []core.Mapping{
-			{Find: "\"name\": \"fullstack-template\"", Replace: "\"name\": \"{{.ProjectName | kebab}}\""},
-			{Find: "\"description\": \"Fullstack template\"", Replace: "\"description\": \"{{.Description}}\""},
-		}
-	case "frontend/index.html":
-		return []core.Mapping{
+		},
+	},
+	{
+		Pattern: "Makefile",
+		Mappings: []core.Mapping{
+			{Find: "docker build -t fullstack-template", Replace: "docker build -t {{.ProjectNameKebab}}"},
+			{Find: "docker rmi fullstack-template", Replace: "docker rmi {{.ProjectNameKebab}}"},
+		},
+	},
+	{
+		Pattern: "frontend/index.html",
+		Mappings: []core.Mapping{
 			{Find: "<title>Fullstack Template</title>", Replace: "<title>{{.ProjectName}}</title>"},
-		}
-	case "frontend/src/config/app.ts":
-		return []core.Mapping{
+		},
+	},
+	{
+		Pattern: "frontend/src/config/app.ts",
+		Mappings: []core.Mapping{
 			{Find: "APP_NAME: 'Fullstack Template'", Replace: "APP_NAME: '{{.ProjectName}}'"},
-		}
-	default:
-		// Apply global replacements for import paths in all Go files
-		if strings.HasSuffix(filePath, ".go") {
-			return []core.Mapping{
-				{Find: "\"github.com/acheevo/fullstack-template/", Replace: "\"github.com/{{.GitHubRepo}}/"},
-			}
-		}
-		return []core.Mapping{}
+		},
+	},
+}
+
+// fullstackTemplatePatterns lists the files that need template processing
+var fullstackTemplatePatterns = []string{
+	ReadmeFile,
+	"docker-compose.yml",
+	"Makefile",
+	"frontend/index.html",
+	"frontend/src/config/app.ts",
+	"**/*.go",
+}
+
+// GetMappings returns the string replacement mappings for a specific file
+func (f *FullstackTemplate) GetMappings(filePath string) []core.Mapping {
+	return core.MappingsFor(fullstackMappingRules, filePath)
+}
+
+// fullstackPatchRules holds the structured patches per file pattern
+var fullstackPatchRules = []core.PatchRule{
+	{
+		Pattern: "frontend/package.json",
+		Patches: packageJSONPatches("frontend"),
+	},
+}
+
+// GetPatches returns the structured patches for a specific file
+func (f *FullstackTemplate) GetPatches(filePath string) []core.Patch {
+	return core.PatchesFor(fullstackPatchRules, filePath)
+}
+
+// EnvComponent returns the component the variables of an env example belong to: frontend for
+// those below frontend/, api for the others
+func (f *FullstackTemplate) EnvComponent(source string) string {
+	if strings.HasPrefix(source, "frontend/") {
+		return "frontend"
 	}
+	return "api"
 }
 
 // GetVariables returns the variables used by this template type
@@ -193,90 +190,22 @@ func (f *FullstackTemplate) GetVariables() map[string]core.Variable {
 
 // ShouldTemplate determines if a file needs template processing
 func (f *FullstackTemplate) ShouldTemplate(filePath string) bool {
-	templatedFiles := []string{
-		"go.mod",
-		"README.md",
-		"docker-compose.yml",
-		"cmd/api/main.go",
-		"internal/shared/config/config.go",
-		"Makefile",
-		"frontend/package.json",
-		"frontend/index.html",
-		"frontend/src/config/app.ts",
-	}
-
-	for _, file := range templatedFiles {
-		if filePath == file {
-			return true
-		}
-	}
-
-	// Specific Go files that need import path replacements (not all Go files)
-	if strings.HasSuffix(filePath, ".go") {
-		return true
-	}
-
-	return false
+	return core.MatchAnyGlob(fullstackTemplatePatterns, filePath)
 }
 
 // ShouldSkip determines if a file/directory should be skipped during extraction
 func (f *FullstackTemplate) ShouldSkip(path string) bool {
-	baseName := filepath.Base(path)
-
-	// Skip node_modules explicitly (most important skip rule)
-	if strings.Contains(path, "node_modules") {
-		return true
-	}
-
-	// Skip compiled binaries and executables
-	if baseName == "api" && !strings.HasSuffix(path, ".go") {
-		return true
-	}
-
-	// Always include important project dotfiles
-	importantDotfiles := []string{
-		".dockerignore",
-		".gitignore",
-		".golangci.yml",
-		".golangci.yaml",
-		".env.example",
-	}
-
-	for _, dotfile := range importantDotfiles {
-		if baseName == dotfile {
-			return false
-		}
-	}
-
-	// Always include .claude directory and its contents
-	if strings.Contains(path, ".claude") {
-		return false
-	}
-
-	skipDirs := []string{
-		"vendor",
-		"bin",
-		"tmp",
-		"coverage",
-		"dist",
-		"build",
-	}
-	return shouldSkipCommon(path, skipDirs)
+	return f.SkipReason(path) != ""
 }
 
-// calculateSchemaHash calculates a hash for the entire schema
-func (f *FullstackTemplate) calculateSchemaHash(schema *core.TemplateSchema) string {
-	// Create a deterministic string representation of the schema
-	var content strings.Builder
-	content.WriteString(schema.Name)
-	content.WriteString(schema.Type)
-	content.WriteString(schema.Version)
-
-	for _, file := range schema.Files {
-		content.WriteString(file.Path)
-		content.WriteString(file.Hash)
-	}
+// fullstackSkipRules holds the files left out of fullstack schemas
+var fullstackSkipRules = skipRules{
+	Files: []skipPattern{{Pattern: "/api", Reason: "compiled binary"}},
+	Dirs:  []string{"node_modules", "vendor", "bin", "tmp", "coverage", "dist", "build"},
+	Keep:  []string{".dockerignore", ".gitignore", ".golangci.yml", ".golangci.yaml", ".env.example"},
+}
 
-	hash := sha256.Sum256([]byte(content.String()))
-	return hex.EncodeToString(hash[:])
+// SkipReason returns why a file/directory is skipped during extraction, empty when it is not
+func (f *FullstackTemplate) SkipReason(path string) string {
+	return fullstackSkipRules.reason(path)
 }
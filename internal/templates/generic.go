@@ -0,0 +1,267 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+// GenericTemplate implements TemplateType for projects in any language. Rather than a file
+// list, it templates every file containing a marker token such as __PROJECT_NAME__, and leaves
+// out the paths listed in the project's .templateignore.
+type GenericTemplate struct{}
+
+// TemplateIgnoreFile lists the paths the generic template type leaves out of a schema, in
+// .gitignore syntax (see core.ParseIgnore). The project's .gitignore files apply first: a path
+// they exclude is not re-included by a ! pattern here unless ExtractOptions.NoGitignore is set.
+const TemplateIgnoreFile = ".templateignore"
+
+// TemplateTokensFile declares further marker tokens of a project for the generic template type,
+// one TOKEN=REPLACEMENT per line, e.g. __SERVICE__={{.ProjectNameKebab}}
+const TemplateTokensFile = ".templatetokens"
+
+// genericTokens are the marker tokens of every project and the templates replacing them
+var genericTokens = map[string]string{
+	"__PROJECT_NAME__":        "{{.ProjectName}}",
+	"__PROJECT_NAME_KEBAB__":  "{{.ProjectNameKebab}}",
+	"__PROJECT_NAME_SNAKE__":  "{{.ProjectNameSnake}}",
+	"__PROJECT_NAME_PASCAL__": "{{.ProjectNamePascal}}",
+	"__PROJECT_NAME_CAMEL__":  "{{.ProjectNameCamel}}",
+	"__GITHUB_REPO__":         "{{.GitHubRepo}}",
+	"__AUTHOR__":              "{{.Author}}",
+	"__DESCRIPTION__":         "{{.Description}}",
+}
+
+// Name returns the template type name
+func (g *GenericTemplate) Name() string {
+	return "generic"
+}
+
+// Description describes the projects the template type extracts
+func (g *GenericTemplate) Description() string {
+	return "Any project, templated through __PROJECT_NAME__ style marker tokens"
+}
+
+// Extract analyzes a project of any language and creates a template schema
+func (g *GenericTemplate) Extract(
+	ctx context.Context, sourceDir string, opts core.ExtractOptions,
+) (*core.TemplateSchema, error) {
+	return g.ExtractFS(ctx, core.DirFS(sourceDir), opts)
+}
+
+// ExtractFS extracts the template from any file tree, such as a go:embed bundle. The files are
+// read once up front to find those containing marker tokens, which are templated with a mapping
+// per token they contain.
+func (g *GenericTemplate) ExtractFS(
+	ctx context.Context, fsys fs.FS, opts core.ExtractOptions,
+) (*core.TemplateSchema, error) {
+	policy := &genericPolicy{GenericTemplate: g}
+	var err error
+	if policy.ignore, err = readIgnore(fsys); err != nil {
+		return nil, err
+	}
+	tokens, err := readTokens(fsys)
+	if err != nil {
+		return nil, err
+	}
+	if policy.mappings, err = policy.tokenMappings(ctx, fsys, tokens); err != nil {
+		return nil, err
+	}
+
+	schema := &core.TemplateSchema{
+		Name:        "generic-template",
+		Type:        "generic",
+		Version:     "1.0.0",
+		Description: "Generic project template",
+		Variables:   g.GetVariables(),
+	}
+
+	return newExtractor(policy).ExtractFS(ctx, fsys, schema, opts)
+}
+
+// readIgnore reads the .templateignore of fsys, if any
+func readIgnore(fsys fs.FS) (core.IgnoreRules, error) {
+	content, err := fs.ReadFile(fsys, TemplateIgnoreFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return core.ParseIgnore(string(content)), nil
+}
+
+// readTokens returns the default marker tokens with those of the .templatetokens of fsys
+func readTokens(fsys fs.FS) (map[string]string, error) {
+	tokens := maps.Clone(genericTokens)
+	content, err := fs.ReadFile(fsys, TemplateTokensFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		token, replacement, ok := strings.Cut(line, "=")
+		token, replacement = strings.TrimSpace(token), strings.TrimSpace(replacement)
+		if !ok || token == "" || replacement == "" {
+			return nil, fmt.Errorf("%s:%d: expected TOKEN=REPLACEMENT, got %q", TemplateTokensFile, i+1, line)
+		}
+		tokens[token] = replacement
+	}
+	return tokens, nil
+}
+
+// genericPolicy applies the .templateignore and marker tokens of a project to its extraction
+type genericPolicy struct {
+	*GenericTemplate
+	ignore core.IgnoreRules
+	// mappings holds the token mappings of every file containing a token, by slash-separated path
+	mappings map[string][]core.Mapping
+}
+
+// tokenMappings reads every extracted file of fsys and returns the mappings of the tokens each
+// file contains, longest tokens first so none is replaced inside another
+func (p *genericPolicy) tokenMappings(
+	ctx context.Context, fsys fs.FS, tokens map[string]string,
+) (map[string][]core.Mapping, error) {
+	ordered := slices.SortedFunc(maps.Keys(tokens), func(a, b string) int {
+		if len(a) != len(b) {
+			return len(b) - len(a)
+		}
+		return strings.Compare(a, b)
+	})
+
+	mappings := make(map[string][]core.Mapping)
+	err := fs.WalkDir(fsys, ".", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if entry.IsDir() || p.ShouldSkip(filepath.FromSlash(path)) {
+			return nil
+		}
+		if info, err := fs.Stat(fsys, path); err != nil || info.IsDir() {
+			return nil // Dangling symlinks and symlinks to directories are not templated
+		}
+
+		content, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+		for _, token := range ordered {
+			if strings.Contains(string(content), token) {
+				mappings[path] = append(mappings[path], core.Mapping{Find: token, Replace: tokens[token]})
+			}
+		}
+		return nil
+	})
+	return mappings, err
+}
+
+// ShouldTemplate reports whether the file contains a marker token
+func (p *genericPolicy) ShouldTemplate(filePath string) bool {
+	_, ok := p.mappings[filepath.ToSlash(filePath)]
+	return ok
+}
+
+// GetMappings returns the mappings of the marker tokens the file contains
+func (p *genericPolicy) GetMappings(filePath string) []core.Mapping {
+	return slices.Clone(p.mappings[filepath.ToSlash(filePath)])
+}
+
+// ShouldSkip determines if a file/directory should be skipped during extraction
+func (p *genericPolicy) ShouldSkip(path string) bool {
+	return p.SkipReason(path) != ""
+}
+
+// SkipReason adds the template configuration files and the paths of the .templateignore to the
+// skip rules of the template type
+func (p *genericPolicy) SkipReason(path string) string {
+	slashPath := filepath.ToSlash(path)
+	if slashPath == TemplateIgnoreFile || slashPath == TemplateTokensFile {
+		return "template configuration"
+	}
+	if p.ignore.Ignores(slashPath) {
+		return "matched " + TemplateIgnoreFile
+	}
+	return p.GenericTemplate.SkipReason(path)
+}
+
+// GetMappings returns no mappings: they depend on the content of the files, see ExtractFS
+func (g *GenericTemplate) GetMappings(filePath string) []core.Mapping {
+	return []core.Mapping{}
+}
+
+// GetVariables returns the variables used by this template type
+func (g *GenericTemplate) GetVariables() map[string]core.Variable {
+	return map[string]core.Variable{
+		"ProjectName": {
+			Type:        "string",
+			Required:    true,
+			Description: "Name of the project, replacing the __PROJECT_NAME__ tokens",
+		},
+		"GitHubRepo": {
+			Type:        "string",
+			Required:    true,
+			Description: "GitHub repository (e.g., username/repo-name)",
+		},
+		"Author": {
+			Type:        "string",
+			Required:    false,
+			Default:     "Developer",
+			Description: "Project author name",
+		},
+		"Description": {
+			Type:        "string",
+			Required:    false,
+			Default:     "A new project",
+			Description: "Project description",
+		},
+	}
+}
+
+// ShouldTemplate reports false: which files are templated depends on their content, see ExtractFS
+func (g *GenericTemplate) ShouldTemplate(filePath string) bool {
+	return false
+}
+
+// ShouldSkip determines if a file/directory should be skipped during extraction
+func (g *GenericTemplate) ShouldSkip(path string) bool {
+	return g.SkipReason(path) != ""
+}
+
+// genericSkipRules holds the files left out of generic schemas. The template configuration
+// files are kept so they take part in the cache key of the project (see schemacache.Key); the
+// extraction itself leaves them out.
+var genericSkipRules = skipRules{
+	Dirs: []string{"node_modules"},
+	Keep: []string{
+		".gitignore",
+		".gitattributes",
+		".dockerignore",
+		".editorconfig",
+		".env.example",
+		TemplateIgnoreFile,
+		TemplateTokensFile,
+	},
+}
+
+// SkipReason returns why a file/directory is skipped during extraction, empty when it is not
+func (g *GenericTemplate) SkipReason(path string) string {
+	return genericSkipRules.reason(path)
+}
@@ -1,14 +1,10 @@
 package templates
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
-	"os"
-	"path/filepath"
-	"strings"
+	"context"
+	"io/fs"
 
 	"github.com/acheevo/template-engine/internal/core"
-	"github.com/acheevo/template-engine/internal/envparser"
 )
 
 // GoAPITemplate implements TemplateType for Go API projects
@@ -19,133 +15,107 @@ func (g *GoAPITemplate) Name() string {
 	return "go-api"
 }
 
+// Description describes the projects the template type extracts
+func (g *GoAPITemplate) Description() string {
+	return "Go API with Gin + PostgreSQL + Clean Architecture"
+}
+
+// Category returns the kind of project the template type extracts
+func (g *GoAPITemplate) Category() string {
+	return core.CategoryBackend
+}
+
+// Tags returns the keywords of the projects the template type extracts
+func (g *GoAPITemplate) Tags() []string {
+	return []string{"go", "gin", "postgres", "rest"}
+}
+
+// Markers returns the paths a reference project of the template type contains
+func (g *GoAPITemplate) Markers() []string {
+	return []string{"go.mod", "cmd/api"}
+}
+
 // Extract analyzes a Go API project and creates a template schema
-func (g *GoAPITemplate) Extract(sourceDir string) (*core.TemplateSchema, error) {
+func (g *GoAPITemplate) Extract(
+	ctx context.Context, sourceDir string, opts core.ExtractOptions,
+) (*core.TemplateSchema, error) {
+	return g.ExtractFS(ctx, core.DirFS(sourceDir), opts)
+}
+
+// ExtractFS extracts the template from any file tree, such as a go:embed bundle
+func (g *GoAPITemplate) ExtractFS(
+	ctx context.Context, fsys fs.FS, opts core.ExtractOptions,
+) (*core.TemplateSchema, error) {
 	schema := &core.TemplateSchema{
 		Name:        "go-api-template",
 		Type:        "go-api",
 		Version:     "1.0.0",
 		Description: "Go REST API template with Gin and PostgreSQL",
 		Variables:   g.GetVariables(),
-		Files:       []core.FileSpec{},
-		EnvConfig:   []core.EnvVariable{}, // Initialize as empty slice
-		Hooks: map[string][]string{
-			"post_generate": {"go mod tidy", "go build"},
+		Tags:        g.Tags(),
+		Category:    g.Category(),
+		GoModules:   []core.GoModule{{Path: "go.mod", Module: "github.com/{{.GitHubRepo}}"}},
+		Hooks: core.Hooks{
+			{Name: "tidy", Stage: core.HookPostGenerate, Command: "go mod tidy", Condition: "command:go"},
+			{Name: "build", Stage: core.HookPostGenerate, Command: "go build ./...", Condition: "command:go"},
 		},
 	}
 
-	err := filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// Skip directories and files that should be skipped
-		if info.IsDir() || g.ShouldSkip(path) {
-			return nil
-		}
-
-		relPath, err := filepath.Rel(sourceDir, path)
-		if err != nil {
-			return err
-		}
-
-		// Read file content (go-fsck pattern: always include full content)
-		content, err := os.ReadFile(path)
-		if err != nil {
-			return err
-		}
-
-		// Calculate hash
-		hash := sha256.Sum256(content)
-		hashStr := hex.EncodeToString(hash[:])
-
-		// Determine if this file needs templating
-		isTemplate := g.ShouldTemplate(relPath)
-
-		fileSpec := core.FileSpec{
-			Path:     relPath,
-			Template: isTemplate,
-			Content:  string(content), // Always include full content
-			Size:     info.Size(),
-			Hash:     hashStr,
-		}
-
-		// Add mappings for templated files
-		if isTemplate {
-			fileSpec.Mappings = g.GetMappings(relPath)
-		}
-
-		schema.Files = append(schema.Files, fileSpec)
-		return nil
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	// Parse .env.example if it exists
-	envExamplePath := filepath.Join(sourceDir, ".env.example")
-	if _, err := os.Stat(envExamplePath); err == nil {
-		envContent, err := os.ReadFile(envExamplePath)
-		if err == nil {
-			schema.EnvConfig = envparser.ParseEnvExample(string(envContent))
-		}
-	}
-
-	// Calculate schema hash
-	schema.Hash = g.calculateSchemaHash(schema)
-
-	return schema, nil
+	return newExtractor(g).ExtractFS(ctx, fsys, schema, opts)
 }
 
-// GetMappings returns the string replacement mappings for a specific file
-func (g *GoAPITemplate) GetMappings(filePath string) []core.Mapping {
-	switch filePath {
-	case "go.mod":
-		return []core.Mapping{
-			{Find: "module github.com/acheevo/api-template", Replace: "module github.com/{{.GitHubRepo}}"},
-		}
-	case "cmd/api/main.go":
-		return []core.Mapping{
-			{Find: "\"github.com/acheevo/api-template/", Replace: "\"github.com/{{.GitHubRepo}}/"},
-		}
-	case "README.md":
-		return []core.Mapping{
+// goAPIMappingRules holds the string replacement mappings per file pattern
+var goAPIMappingRules = []core.MappingRule{
+	{
+		Pattern: ReadmeFile,
+		Mappings: []core.Mapping{
 			{Find: "# Go API Template", Replace: "# {{.ProjectName}}"},
 			{
 				Find:    "git clone https://github.com/acheevo/api-template.git",
 				Replace: "git clone https://github.com/{{.GitHubRepo}}.git",
 			},
-			{Find: "cd api-template", Replace: "cd {{.ProjectName | kebab}}"},
-		}
-	case "docker-compose.yml":
-		return []core.Mapping{
-			{Find: "api-template", Replace: "{{.ProjectName | kebab}}"},
-		}
-	case "internal/shared/config/config.go":
-		return []core.Mapping{
+			{Find: "cd api-template", Replace: "cd {{.ProjectNameKebab}}"},
+		},
+	},
+	{
+		Pattern: "docker-compose.yml",
+		Mappings: []core.Mapping{
+			{Find: "api-template", Replace: "{{.ProjectNameKebab}}"},
+		},
+	},
+	{
+		Pattern: "internal/shared/config/config.go",
+		Mappings: []core.Mapping{
 			{
 				Find:    "ServiceName    string `envconfig:\"SERVICE_NAME\" default:\"api-template\"`",
-				Replace: "ServiceName    string `envconfig:\"SERVICE_NAME\" default:\"{{.ProjectName | kebab}}\"`",
+				Replace: "ServiceName    string `envconfig:\"SERVICE_NAME\" default:\"{{.ProjectNameKebab}}\"`",
 			},
 			{
 				Find:    "DBName            string `envconfig:\"DB_NAME\" default:\"api_template\"`",
-				Replace: "DBName            string `envconfig:\"DB_NAME\" default:\"{{.ProjectName | lower}}\"`",
+				Replace: "DBName            string `envconfig:\"DB_NAME\" default:\"{{.ProjectNameSnake}}\"`",
 			},
-		}
-	case "Makefile":
-		return []core.Mapping{
-			{Find: "docker build -t api-template .", Replace: "docker build -t {{.ProjectName | kebab}} ."},
-			{Find: "docker rmi api-template", Replace: "docker rmi {{.ProjectName | kebab}}"},
-		}
-	default:
-		// Apply global replacements for import paths in all Go files
-		if strings.HasSuffix(filePath, ".go") {
-			return []core.Mapping{
-				{Find: "\"github.com/acheevo/api-template/", Replace: "\"github.com/{{.GitHubRepo}}/"},
-			}
-		}
-		return []core.Mapping{}
-	}
+		},
+	},
+	{
+		Pattern: "Makefile",
+		Mappings: []core.Mapping{
+			{Find: "docker build -t api-template .", Replace: "docker build -t {{.ProjectNameKebab}} ."},
+			{Find: "docker rmi api-template", Replace: "docker rmi {{.ProjectNameKebab}}"},
+		},
+	},
+}
+
+// goAPITemplatePatterns lists the files that need template processing
+var goAPITemplatePatterns = []string{
+	ReadmeFile,
+	"docker-compose.yml",
+	"Makefile",
+	"**/*.go",
+}
+
+// GetMappings returns the string replacement mappings for a specific file
+func (g *GoAPITemplate) GetMappings(filePath string) []core.Mapping {
+	return core.MappingsFor(goAPIMappingRules, filePath)
 }
 
 // GetVariables returns the variables used by this template type
@@ -178,75 +148,21 @@ func (g *GoAPITemplate) GetVariables() map[string]core.Variable {
 
 // ShouldTemplate determines if a file needs template processing
 func (g *GoAPITemplate) ShouldTemplate(filePath string) bool {
-	templatedFiles := []string{
-		"go.mod",
-		"README.md",
-		"docker-compose.yml",
-		"cmd/api/main.go",
-		"internal/shared/config/config.go",
-		"Makefile",
-	}
-
-	for _, file := range templatedFiles {
-		if filePath == file {
-			return true
-		}
-	}
-
-	// All Go files need import path replacements
-	if strings.HasSuffix(filePath, ".go") {
-		return true
-	}
-
-	return false
+	return core.MatchAnyGlob(goAPITemplatePatterns, filePath)
 }
 
 // ShouldSkip determines if a file/directory should be skipped during extraction
 func (g *GoAPITemplate) ShouldSkip(path string) bool {
-	baseName := filepath.Base(path)
-
-	// Always include important Go project dotfiles
-	importantDotfiles := []string{
-		".dockerignore",
-		".gitignore",
-		".golangci.yml",
-		".golangci.yaml",
-		".env.example",
-	}
-
-	for _, dotfile := range importantDotfiles {
-		if baseName == dotfile {
-			return false
-		}
-	}
-
-	// Always include .claude directory and its contents
-	if strings.Contains(path, ".claude") {
-		return false
-	}
-
-	skipDirs := []string{
-		"vendor",
-		"bin",
-		"tmp",
-		"coverage",
-	}
-	return shouldSkipCommon(path, skipDirs)
+	return g.SkipReason(path) != ""
 }
 
-// calculateSchemaHash calculates a hash for the entire schema
-func (g *GoAPITemplate) calculateSchemaHash(schema *core.TemplateSchema) string {
-	// Create a deterministic string representation of the schema
-	var content strings.Builder
-	content.WriteString(schema.Name)
-	content.WriteString(schema.Type)
-	content.WriteString(schema.Version)
-
-	for _, file := range schema.Files {
-		content.WriteString(file.Path)
-		content.WriteString(file.Hash)
-	}
+// goAPISkipRules holds the files left out of Go API schemas
+var goAPISkipRules = skipRules{
+	Dirs: []string{"vendor", "bin", "tmp", "coverage"},
+	Keep: []string{".dockerignore", ".gitignore", ".golangci.yml", ".golangci.yaml", ".env.example"},
+}
 
-	hash := sha256.Sum256([]byte(content.String()))
-	return hex.EncodeToString(hash[:])
+// SkipReason returns why a file/directory is skipped during extraction, empty when it is not
+func (g *GoAPITemplate) SkipReason(path string) string {
+	return goAPISkipRules.reason(path)
 }
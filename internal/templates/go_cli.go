@@ -0,0 +1,192 @@
+package templates
+
+import (
+	"context"
+	"io/fs"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+// GoCLITemplate implements TemplateType for Go command line tools built with Cobra
+type GoCLITemplate struct{}
+
+// goCLIBinary is the binary and root command name of the reference project
+const goCLIBinary = "cli-template"
+
+// Name returns the template type name
+func (g *GoCLITemplate) Name() string {
+	return "go-cli"
+}
+
+// Description describes the projects the template type extracts
+func (g *GoCLITemplate) Description() string {
+	return "Go CLI tool with Cobra + GoReleaser"
+}
+
+// Category returns the kind of project the template type extracts
+func (g *GoCLITemplate) Category() string {
+	return core.CategoryCLI
+}
+
+// Tags returns the keywords of the projects the template type extracts
+func (g *GoCLITemplate) Tags() []string {
+	return []string{"go", "cobra", "cli", "goreleaser"}
+}
+
+// Markers returns the paths a reference project of the template type contains
+func (g *GoCLITemplate) Markers() []string {
+	return []string{"go.mod", "cmd/root.go"}
+}
+
+// Extract analyzes a Go CLI project and creates a template schema
+func (g *GoCLITemplate) Extract(
+	ctx context.Context, sourceDir string, opts core.ExtractOptions,
+) (*core.TemplateSchema, error) {
+	return g.ExtractFS(ctx, core.DirFS(sourceDir), opts)
+}
+
+// ExtractFS extracts the template from any file tree, such as a go:embed bundle
+func (g *GoCLITemplate) ExtractFS(
+	ctx context.Context, fsys fs.FS, opts core.ExtractOptions,
+) (*core.TemplateSchema, error) {
+	schema := &core.TemplateSchema{
+		Name:        "go-cli-template",
+		Type:        "go-cli",
+		Version:     "1.0.0",
+		Description: "Go command line tool template with Cobra and GoReleaser",
+		Variables:   g.GetVariables(),
+		Tags:        g.Tags(),
+		Category:    g.Category(),
+		GoModules:   []core.GoModule{{Path: "go.mod", Module: "github.com/{{.GitHubRepo}}"}},
+		Hooks: core.Hooks{
+			{Name: "tidy", Stage: core.HookPostGenerate, Command: "go mod tidy", Condition: "command:go"},
+			{Name: "build", Stage: core.HookPostGenerate, Command: "go build ./...", Condition: "command:go"},
+		},
+	}
+
+	return newExtractor(g).ExtractFS(ctx, fsys, schema, opts)
+}
+
+// goCLIMappingRules holds the string replacement mappings per file pattern. The binary and
+// root command are named after the project.
+var goCLIMappingRules = []core.MappingRule{
+	{
+		Pattern: ReadmeFile,
+		Mappings: []core.Mapping{
+			{Find: "# Go CLI Template", Replace: "# {{.ProjectName}}"},
+			{
+				Find:    "https://github.com/acheevo/" + goCLIBinary,
+				Replace: "https://github.com/{{.GitHubRepo}}",
+			},
+			{Find: goCLIBinary, Replace: "{{.ProjectNameKebab}}"},
+		},
+	},
+	{
+		Pattern: "Makefile",
+		Mappings: []core.Mapping{
+			{Find: goCLIBinary, Replace: "{{.ProjectNameKebab}}"},
+		},
+	},
+	{
+		Pattern: "Dockerfile",
+		Mappings: []core.Mapping{
+			{Find: goCLIBinary, Replace: "{{.ProjectNameKebab}}"},
+		},
+	},
+	{
+		Pattern: "cmd/root.go",
+		Mappings: []core.Mapping{
+			{Find: `Use:(\s+)"` + goCLIBinary + `"`, Replace: `Use:${1}"{{.ProjectNameKebab}}"`, Regex: true},
+			{Find: "." + goCLIBinary, Replace: ".{{.ProjectNameKebab}}"}, // $HOME/.cli-template.yaml
+		},
+	},
+}
+
+// goCLITemplatePatterns lists the files that need template processing
+var goCLITemplatePatterns = []string{
+	ReadmeFile,
+	"Makefile",
+	"Dockerfile",
+	"**/*.go",
+}
+
+// GetMappings returns the string replacement mappings for a specific file
+func (g *GoCLITemplate) GetMappings(filePath string) []core.Mapping {
+	return core.MappingsFor(goCLIMappingRules, filePath)
+}
+
+// goReleaserPatches name the project and binaries of a GoReleaser config after the project.
+// GoReleaser configs are patched rather than templated since they use {{ }} themselves.
+var goReleaserPatches = []core.Patch{
+	{Path: "project_name", Value: "{{.ProjectNameKebab}}"},
+	{Path: "builds.*.binary", Value: "{{.ProjectNameKebab}}"},
+}
+
+// goCLIPatchRules holds the structured patches per file pattern
+var goCLIPatchRules = []core.PatchRule{
+	{Pattern: ".goreleaser.yml", Patches: goReleaserPatches},
+	{Pattern: ".goreleaser.yaml", Patches: goReleaserPatches},
+}
+
+// GetPatches returns the structured patches for a specific file
+func (g *GoCLITemplate) GetPatches(filePath string) []core.Patch {
+	return core.PatchesFor(goCLIPatchRules, filePath)
+}
+
+// GetVariables returns the variables used by this template type
+func (g *GoCLITemplate) GetVariables() map[string]core.Variable {
+	return map[string]core.Variable{
+		"ProjectName": {
+			Type:        "string",
+			Required:    true,
+			Description: "Name of the CLI tool, its kebab-case form names the binary and root command",
+		},
+		"GitHubRepo": {
+			Type:        "string",
+			Required:    true,
+			Description: "GitHub repository (e.g., username/repo-name)",
+		},
+		"Author": {
+			Type:        "string",
+			Required:    false,
+			Default:     "Developer",
+			Description: "Project author name",
+		},
+		"Description": {
+			Type:        "string",
+			Required:    false,
+			Default:     "A Go command line tool",
+			Description: "Project description",
+		},
+	}
+}
+
+// ShouldTemplate determines if a file needs template processing
+func (g *GoCLITemplate) ShouldTemplate(filePath string) bool {
+	return core.MatchAnyGlob(goCLITemplatePatterns, filePath)
+}
+
+// ShouldSkip determines if a file/directory should be skipped during extraction
+func (g *GoCLITemplate) ShouldSkip(path string) bool {
+	return g.SkipReason(path) != ""
+}
+
+// goCLISkipRules holds the files left out of Go CLI schemas
+var goCLISkipRules = skipRules{
+	Files: []skipPattern{{Pattern: "/" + goCLIBinary, Reason: "compiled binary"}},
+	Dirs:  []string{"vendor", "bin", "dist", "tmp", "coverage"},
+	Keep: []string{
+		".dockerignore",
+		".gitignore",
+		".golangci.yml",
+		".golangci.yaml",
+		".goreleaser.yml",
+		".goreleaser.yaml",
+		".env.example",
+	},
+}
+
+// SkipReason returns why a file/directory is skipped during extraction, empty when it is not
+func (g *GoCLITemplate) SkipReason(path string) string {
+	return goCLISkipRules.reason(path)
+}
@@ -0,0 +1,145 @@
+package templates
+
+import (
+	"context"
+	"io/fs"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+// GoLibraryTemplate implements TemplateType for Go library modules
+type GoLibraryTemplate struct{}
+
+// goLibraryRepo is the repository of the reference project, which badges and links point to
+const goLibraryRepo = "acheevo/library-template"
+
+// Name returns the template type name
+func (g *GoLibraryTemplate) Name() string {
+	return "go-library"
+}
+
+// Description describes the projects the template type extracts
+func (g *GoLibraryTemplate) Description() string {
+	return "Go library module with doc.go, examples and CI"
+}
+
+// Category returns the kind of project the template type extracts
+func (g *GoLibraryTemplate) Category() string {
+	return core.CategoryLibrary
+}
+
+// Tags returns the keywords of the projects the template type extracts
+func (g *GoLibraryTemplate) Tags() []string {
+	return []string{"go", "library"}
+}
+
+// Markers returns the paths a reference project of the template type contains
+func (g *GoLibraryTemplate) Markers() []string {
+	return []string{"go.mod", "doc.go"}
+}
+
+// Extract analyzes a Go library project and creates a template schema
+func (g *GoLibraryTemplate) Extract(
+	ctx context.Context, sourceDir string, opts core.ExtractOptions,
+) (*core.TemplateSchema, error) {
+	return g.ExtractFS(ctx, core.DirFS(sourceDir), opts)
+}
+
+// ExtractFS extracts the template from any file tree, such as a go:embed bundle. The root
+// package is renamed after the project (see core.GoModule.Package); drop the package of the
+// go module from the schema to keep the reference name.
+func (g *GoLibraryTemplate) ExtractFS(
+	ctx context.Context, fsys fs.FS, opts core.ExtractOptions,
+) (*core.TemplateSchema, error) {
+	schema := &core.TemplateSchema{
+		Name:        "go-library-template",
+		Type:        "go-library",
+		Version:     "1.0.0",
+		Description: "Go library module template",
+		Variables:   g.GetVariables(),
+		Tags:        g.Tags(),
+		Category:    g.Category(),
+		GoModules: []core.GoModule{
+			{Path: "go.mod", Module: "github.com/{{.GitHubRepo}}", Package: "{{.ProjectNamePackage}}"},
+		},
+		Hooks: core.Hooks{
+			{Name: "tidy", Stage: core.HookPostGenerate, Command: "go mod tidy", Condition: "command:go"},
+			{Name: "build", Stage: core.HookPostGenerate, Command: "go build ./...", Condition: "command:go"},
+		},
+	}
+
+	return newExtractor(g).ExtractFS(ctx, fsys, schema, opts)
+}
+
+// goLibraryMappingRules holds the string replacement mappings per file pattern. Go files need
+// none: imports and the package name follow the go module.
+var goLibraryMappingRules = []core.MappingRule{
+	{
+		Pattern: ReadmeFile,
+		Mappings: []core.Mapping{
+			{Find: "# Go Library Template", Replace: "# {{.ProjectName}}"},
+			// Badges (pkg.go.dev, Go Report Card, CI), links and import paths
+			{Find: "github.com/" + goLibraryRepo, Replace: "github.com/{{.GitHubRepo}}"},
+			{Find: "cd library-template", Replace: "cd {{.RepoName}}"},
+		},
+	},
+}
+
+// goLibraryTemplatePatterns lists the files that need template processing
+var goLibraryTemplatePatterns = []string{
+	ReadmeFile,
+}
+
+// GetMappings returns the string replacement mappings for a specific file
+func (g *GoLibraryTemplate) GetMappings(filePath string) []core.Mapping {
+	return core.MappingsFor(goLibraryMappingRules, filePath)
+}
+
+// GetVariables returns the variables used by this template type
+func (g *GoLibraryTemplate) GetVariables() map[string]core.Variable {
+	return map[string]core.Variable{
+		"ProjectName": {
+			Type:        "string",
+			Required:    true,
+			Description: "Name of the library, which also names its root package",
+		},
+		"GitHubRepo": {
+			Type:        "string",
+			Required:    true,
+			Description: "GitHub repository (e.g., username/repo-name)",
+		},
+		"Author": {
+			Type:        "string",
+			Required:    false,
+			Default:     "Developer",
+			Description: "Project author name",
+		},
+		"Description": {
+			Type:        "string",
+			Required:    false,
+			Default:     "A Go library",
+			Description: "Project description",
+		},
+	}
+}
+
+// ShouldTemplate determines if a file needs template processing
+func (g *GoLibraryTemplate) ShouldTemplate(filePath string) bool {
+	return core.MatchAnyGlob(goLibraryTemplatePatterns, filePath)
+}
+
+// ShouldSkip determines if a file/directory should be skipped during extraction
+func (g *GoLibraryTemplate) ShouldSkip(path string) bool {
+	return g.SkipReason(path) != ""
+}
+
+// goLibrarySkipRules holds the files left out of Go library schemas
+var goLibrarySkipRules = skipRules{
+	Dirs: []string{"vendor", "bin", "tmp", "coverage"},
+	Keep: []string{".gitignore", ".golangci.yml", ".golangci.yaml"},
+}
+
+// SkipReason returns why a file/directory is skipped during extraction, empty when it is not
+func (g *GoLibraryTemplate) SkipReason(path string) string {
+	return goLibrarySkipRules.reason(path)
+}
@@ -0,0 +1,172 @@
+package templates
+
+import (
+	"context"
+	"io/fs"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+// HelmChartTemplate implements TemplateType for Helm charts
+type HelmChartTemplate struct{}
+
+// helmChartName is the chart name of the reference project, which prefixes its named templates
+const helmChartName = "chart-template"
+
+// Name returns the template type name
+func (h *HelmChartTemplate) Name() string {
+	return "helm-chart"
+}
+
+// Description describes the projects the template type extracts
+func (h *HelmChartTemplate) Description() string {
+	return "Helm chart for Kubernetes deployments"
+}
+
+// Category returns the kind of project the template type extracts
+func (h *HelmChartTemplate) Category() string {
+	return core.CategoryInfra
+}
+
+// Tags returns the keywords of the projects the template type extracts
+func (h *HelmChartTemplate) Tags() []string {
+	return []string{"helm", "kubernetes", "k8s"}
+}
+
+// Markers returns the paths a reference project of the template type contains
+func (h *HelmChartTemplate) Markers() []string {
+	return []string{"Chart.yaml", "values.yaml"}
+}
+
+// Extract analyzes a Helm chart and creates a template schema
+func (h *HelmChartTemplate) Extract(
+	ctx context.Context, sourceDir string, opts core.ExtractOptions,
+) (*core.TemplateSchema, error) {
+	return h.ExtractFS(ctx, core.DirFS(sourceDir), opts)
+}
+
+// ExtractFS extracts the template from any file tree, such as a go:embed bundle. Chart
+// templates use {{ }} themselves, so the schema renders with [[ ]] and mappings and patches
+// are converted to them.
+func (h *HelmChartTemplate) ExtractFS(
+	ctx context.Context, fsys fs.FS, opts core.ExtractOptions,
+) (*core.TemplateSchema, error) {
+	schema := &core.TemplateSchema{
+		Name:        "helm-chart-template",
+		Type:        "helm-chart",
+		Version:     "1.0.0",
+		Description: "Helm chart template for Kubernetes deployments",
+		Variables:   h.GetVariables(),
+		Tags:        h.Tags(),
+		Category:    h.Category(),
+		Delims:      &core.Delims{Left: "[[", Right: "]]"},
+		Hooks: core.Hooks{
+			{Name: "lint", Stage: core.HookPostGenerate, Command: "helm lint .", Condition: "command:helm"},
+		},
+	}
+
+	return newExtractor(h).ExtractFS(ctx, fsys, schema, opts)
+}
+
+// helmChartMappingRules holds the string replacement mappings per file pattern. Named
+// templates (e.g. "chart-template.fullname") are defined and included under the chart name.
+var helmChartMappingRules = []core.MappingRule{
+	{
+		Pattern: ReadmeFile,
+		Mappings: []core.Mapping{
+			{Find: "# Chart Template", Replace: "# {{.ProjectName}}"},
+			{Find: "github.com/acheevo/" + helmChartName, Replace: "github.com/{{.GitHubRepo}}"},
+			{Find: helmChartName, Replace: "{{.ProjectNameKebab}}"},
+		},
+	},
+	{
+		Pattern: "templates/**/*",
+		Mappings: []core.Mapping{
+			{Find: `"` + helmChartName + ".", Replace: `"{{.ProjectNameKebab}}.`},
+		},
+	},
+}
+
+// helmChartTemplatePatterns lists the files that need template processing
+var helmChartTemplatePatterns = []string{
+	ReadmeFile,
+	"templates/**/*",
+}
+
+// GetMappings returns the string replacement mappings for a specific file
+func (h *HelmChartTemplate) GetMappings(filePath string) []core.Mapping {
+	return core.MappingsFor(helmChartMappingRules, filePath)
+}
+
+// helmChartPatchRules holds the structured patches per file pattern
+var helmChartPatchRules = []core.PatchRule{
+	{
+		Pattern: "Chart.yaml",
+		Patches: []core.Patch{
+			{Path: "name", Value: "{{.ProjectNameKebab}}"},
+			{Path: "description", Value: "{{.Description}}"},
+		},
+	},
+	{
+		Pattern: "values.yaml",
+		Patches: []core.Patch{
+			{Path: "image.repository", Value: "ghcr.io/{{.GitHubRepo}}"},
+		},
+	},
+}
+
+// GetPatches returns the structured patches for a specific file
+func (h *HelmChartTemplate) GetPatches(filePath string) []core.Patch {
+	return core.PatchesFor(helmChartPatchRules, filePath)
+}
+
+// GetVariables returns the variables used by this template type
+func (h *HelmChartTemplate) GetVariables() map[string]core.Variable {
+	return map[string]core.Variable{
+		"ProjectName": {
+			Type:        "string",
+			Required:    true,
+			Description: "Name of the chart, its kebab-case form prefixes the named templates",
+		},
+		"GitHubRepo": {
+			Type:        "string",
+			Required:    true,
+			Description: "GitHub repository (e.g., username/repo-name), also naming the ghcr.io image",
+		},
+		"Author": {
+			Type:        "string",
+			Required:    false,
+			Default:     "Developer",
+			Description: "Project author name",
+		},
+		"Description": {
+			Type:        "string",
+			Required:    false,
+			Default:     "A Helm chart for Kubernetes",
+			Description: "Project description",
+		},
+	}
+}
+
+// ShouldTemplate determines if a file needs template processing
+func (h *HelmChartTemplate) ShouldTemplate(filePath string) bool {
+	return core.MatchAnyGlob(helmChartTemplatePatterns, filePath)
+}
+
+// ShouldSkip determines if a file/directory should be skipped during extraction
+func (h *HelmChartTemplate) ShouldSkip(path string) bool {
+	return h.SkipReason(path) != ""
+}
+
+// helmChartSkipRules holds the files left out of Helm chart schemas, among which the packaged
+// charts and the dependencies fetched by helm dependency build
+var helmChartSkipRules = skipRules{
+	Files: []skipPattern{{Pattern: "*.tgz"}},
+	Dirs:  []string{"tmp"},
+	Keep:  []string{".gitignore", ".helmignore", ".yamllint", ".yamllint.yml", ".yamllint.yaml"},
+}
+
+// SkipReason returns why a file/directory is skipped during extraction, empty when it is not
+func (h *HelmChartTemplate) SkipReason(path string) string {
+	return helmChartSkipRules.reason(path)
+}
@@ -0,0 +1,165 @@
+package templates
+
+import (
+	"context"
+	"io/fs"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+// InfraTemplate implements TemplateType for Terraform module projects
+type InfraTemplate struct{}
+
+// infraModuleName is the module name of the reference project
+const infraModuleName = "infra-template"
+
+// Name returns the template type name
+func (i *InfraTemplate) Name() string {
+	return "infra"
+}
+
+// Description describes the projects the template type extracts
+func (i *InfraTemplate) Description() string {
+	return "Terraform infrastructure module"
+}
+
+// Category returns the kind of project the template type extracts
+func (i *InfraTemplate) Category() string {
+	return core.CategoryInfra
+}
+
+// Tags returns the keywords of the projects the template type extracts
+func (i *InfraTemplate) Tags() []string {
+	return []string{"terraform", "iac", "infrastructure"}
+}
+
+// Markers returns the paths a reference project of the template type contains
+func (i *InfraTemplate) Markers() []string {
+	return []string{"main.tf", "variables.tf"}
+}
+
+// Extract analyzes a Terraform module and creates a template schema
+func (i *InfraTemplate) Extract(
+	ctx context.Context, sourceDir string, opts core.ExtractOptions,
+) (*core.TemplateSchema, error) {
+	return i.ExtractFS(ctx, core.DirFS(sourceDir), opts)
+}
+
+// ExtractFS extracts the template from any file tree, such as a go:embed bundle
+func (i *InfraTemplate) ExtractFS(
+	ctx context.Context, fsys fs.FS, opts core.ExtractOptions,
+) (*core.TemplateSchema, error) {
+	schema := &core.TemplateSchema{
+		Name:        "infra-template",
+		Type:        "infra",
+		Version:     "1.0.0",
+		Description: "Terraform infrastructure module template",
+		Variables:   i.GetVariables(),
+		Tags:        i.Tags(),
+		Category:    i.Category(),
+		Hooks: core.Hooks{
+			{Name: "fmt", Stage: core.HookPostGenerate, Command: "terraform fmt -recursive", Condition: "command:terraform"},
+		},
+	}
+
+	return newExtractor(i).ExtractFS(ctx, fsys, schema, opts)
+}
+
+// infraMappingRules holds the string replacement mappings per file pattern
+var infraMappingRules = []core.MappingRule{
+	{
+		Pattern: ReadmeFile,
+		Mappings: []core.Mapping{
+			{Find: "# Infra Template", Replace: "# {{.ProjectName}}"},
+			{Find: "github.com/acheevo/" + infraModuleName, Replace: "github.com/{{.GitHubRepo}}"},
+			{Find: `module "infra_template"`, Replace: `module "{{.ProjectNameSnake}}"`},
+		},
+	},
+	{
+		Pattern: "**/variables.tf",
+		Mappings: []core.Mapping{
+			{Find: `(default\s*=\s*)"` + infraModuleName + `"`, Replace: `${1}"{{.ProjectNameKebab}}"`, Regex: true},
+			{Find: `(default\s*=\s*)"infra_template"`, Replace: `${1}"{{.ProjectNameSnake}}"`, Regex: true},
+		},
+	},
+	{
+		// State keys of the s3 backend and prefixes of the gcs backend, wherever the backend block is
+		Pattern: "**/*.tf",
+		Mappings: []core.Mapping{
+			{
+				Find:    `((?:key|prefix|workspace_key_prefix)\s*=\s*")` + infraModuleName + `\b`,
+				Replace: "${1}{{.ProjectNameKebab}}",
+				Regex:   true,
+			},
+		},
+	},
+}
+
+// infraTemplatePatterns lists the files that need template processing
+var infraTemplatePatterns = []string{
+	ReadmeFile,
+	"**/*.tf",
+}
+
+// GetMappings returns the string replacement mappings for a specific file
+func (i *InfraTemplate) GetMappings(filePath string) []core.Mapping {
+	return core.MappingsFor(infraMappingRules, filePath)
+}
+
+// GetVariables returns the variables used by this template type
+func (i *InfraTemplate) GetVariables() map[string]core.Variable {
+	return map[string]core.Variable{
+		"ProjectName": {
+			Type:        "string",
+			Required:    true,
+			Description: "Name of the infrastructure module, also prefixing its state keys",
+		},
+		"GitHubRepo": {
+			Type:        "string",
+			Required:    true,
+			Description: "GitHub repository (e.g., username/repo-name)",
+		},
+		"Author": {
+			Type:        "string",
+			Required:    false,
+			Default:     "Developer",
+			Description: "Project author name",
+		},
+		"Description": {
+			Type:        "string",
+			Required:    false,
+			Default:     "A Terraform infrastructure module",
+			Description: "Project description",
+		},
+	}
+}
+
+// ShouldTemplate determines if a file needs template processing
+func (i *InfraTemplate) ShouldTemplate(filePath string) bool {
+	return core.MatchAnyGlob(infraTemplatePatterns, filePath)
+}
+
+// ShouldSkip determines if a file/directory should be skipped during extraction
+func (i *InfraTemplate) ShouldSkip(path string) bool {
+	return i.SkipReason(path) != ""
+}
+
+// infraSkipRules holds the files left out of Terraform schemas: state holds the real
+// infrastructure and its secrets, plans and var files are per environment and .terraform holds
+// the providers and modules downloaded by terraform init
+var infraSkipRules = skipRules{
+	Files: []skipPattern{
+		{Pattern: "*.tfstate", Reason: "terraform state"},
+		{Pattern: "*.tfstate.*", Reason: "terraform state"},
+		{Pattern: "*.tfplan"},
+		{Pattern: "*.tfvars"},
+		{Pattern: "*.tfvars.json", Reason: "matched skip pattern *.tfvars"},
+	},
+	Dirs: []string{".terraform"},
+	Keep: []string{".gitignore", ".terraform.lock.hcl", ".terraform-version", ".tflint.hcl", ".pre-commit-config.yaml"},
+}
+
+// SkipReason returns why a file/directory is skipped during extraction, empty when it is not
+func (i *InfraTemplate) SkipReason(path string) string {
+	return infraSkipRules.reason(path)
+}
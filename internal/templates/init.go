@@ -15,6 +15,27 @@ func init() {
 	// Register Fullstack template
 	core.RegisterTemplate(&FullstackTemplate{})
 
+	// Register Go CLI template
+	core.RegisterTemplate(&GoCLITemplate{})
+
+	// Register Go library template
+	core.RegisterTemplate(&GoLibraryTemplate{})
+
+	// Register Python API template
+	core.RegisterTemplate(&PythonAPITemplate{})
+
+	// Register Terraform infrastructure template
+	core.RegisterTemplate(&InfraTemplate{})
+
+	// Register Helm chart template
+	core.RegisterTemplate(&HelmChartTemplate{})
+
+	// Register monorepo template
+	core.RegisterTemplate(&MonorepoTemplate{})
+
+	// Register generic template, for projects of any language
+	core.RegisterTemplate(&GenericTemplate{})
+
 	// Future template types will be registered here:
 	// core.RegisterTemplate(&MobileTemplate{})
 }
@@ -0,0 +1,268 @@
+package templates
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+// MonorepoTemplate implements TemplateType for JavaScript monorepos managed as pnpm, turbo or
+// nx workspaces
+type MonorepoTemplate struct{}
+
+// monorepoName is the name of the workspace root of the reference project
+const monorepoName = "monorepo-template"
+
+// Name returns the template type name
+func (m *MonorepoTemplate) Name() string {
+	return "monorepo"
+}
+
+// Description describes the projects the template type extracts
+func (m *MonorepoTemplate) Description() string {
+	return "JavaScript monorepo with pnpm, Turborepo or Nx workspaces"
+}
+
+// Category returns the kind of project the template type extracts
+func (m *MonorepoTemplate) Category() string {
+	return core.CategoryFullstack
+}
+
+// Tags returns the keywords of the projects the template type extracts
+func (m *MonorepoTemplate) Tags() []string {
+	return []string{"monorepo", "pnpm", "turborepo", "nx", "typescript"}
+}
+
+// Markers returns the paths a reference project of the template type contains
+func (m *MonorepoTemplate) Markers() []string {
+	return []string{"package.json", "*/*/package.json"}
+}
+
+// Extract analyzes a monorepo and creates a template schema
+func (m *MonorepoTemplate) Extract(
+	ctx context.Context, sourceDir string, opts core.ExtractOptions,
+) (*core.TemplateSchema, error) {
+	return m.ExtractFS(ctx, core.DirFS(sourceDir), opts)
+}
+
+// ExtractFS extracts the template from any file tree, such as a go:embed bundle. Every
+// package.json below the root declares a workspace package of the schema, whose name is mapped
+// to the project scope in every templated file.
+func (m *MonorepoTemplate) ExtractFS(
+	ctx context.Context, fsys fs.FS, opts core.ExtractOptions,
+) (*core.TemplateSchema, error) {
+	packages, rootName, err := m.workspacePackages(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	install := core.Hook{Name: "install", Stage: core.HookPostGenerate, Command: "npm install", Condition: "command:npm"}
+	if _, err := fs.Stat(fsys, "pnpm-workspace.yaml"); err == nil {
+		install.Command, install.Condition = "pnpm install", "command:pnpm"
+	}
+
+	schema := &core.TemplateSchema{
+		Name:        "monorepo-template",
+		Type:        "monorepo",
+		Version:     "1.0.0",
+		Description: "JavaScript monorepo template with workspace packages",
+		Variables:   m.GetVariables(),
+		Tags:        m.Tags(),
+		Category:    m.Category(),
+		Packages:    packages,
+		Hooks:       core.Hooks{install},
+	}
+
+	policy := &monorepoPolicy{MonorepoTemplate: m, packageMappings: packageMappings(packages, rootName)}
+	return newExtractor(policy).ExtractFS(ctx, fsys, schema, opts)
+}
+
+// workspacePackages reads the name of every package.json of fsys, the root one returned apart
+func (m *MonorepoTemplate) workspacePackages(fsys fs.FS) ([]core.WorkspacePackage, string, error) {
+	var packages []core.WorkspacePackage
+	var rootName string
+	err := fs.WalkDir(fsys, ".", func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || entry.Name() != "package.json" || m.ShouldSkip(filepath.FromSlash(filePath)) {
+			return nil
+		}
+
+		content, err := fs.ReadFile(fsys, filePath)
+		if err != nil {
+			return err
+		}
+		var manifest struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(content, &manifest); err != nil {
+			return fmt.Errorf("invalid %s: %w", filePath, err)
+		}
+
+		switch {
+		case filePath == "package.json":
+			rootName = manifest.Name
+		case manifest.Name != "":
+			packages = append(packages, core.WorkspacePackage{Name: manifest.Name, Dir: path.Dir(filePath)})
+		}
+		return nil
+	})
+	return packages, rootName, err
+}
+
+// packageMappings maps the name of every workspace package to its name in the generated
+// project, longest names first so a name is never replaced inside a longer one
+func packageMappings(packages []core.WorkspacePackage, rootName string) []core.Mapping {
+	var mappings []core.Mapping
+	for _, pkg := range packages {
+		if renamed, ok := renamedPackage(pkg.Name, rootName); ok {
+			mappings = append(mappings, core.Mapping{Find: pkg.Name, Replace: renamed})
+		}
+	}
+	slices.SortStableFunc(mappings, func(a, b core.Mapping) int {
+		return cmp.Compare(len(b.Find), len(a.Find))
+	})
+	return mappings
+}
+
+// renamedPackage returns the name of a workspace package in the generated project: scoped
+// packages move to the project scope and packages prefixed with the name of the workspace root
+// keep their suffix. Other names are kept.
+func renamedPackage(name, rootName string) (string, bool) {
+	if scope, rest, ok := strings.Cut(name, "/"); ok && strings.HasPrefix(scope, "@") {
+		return "@{{.ProjectNameKebab}}/" + rest, true
+	}
+	if suffix, ok := strings.CutPrefix(name, rootName+"-"); ok && rootName != "" {
+		return "{{.ProjectNameKebab}}-" + suffix, true
+	}
+	return "", false
+}
+
+// monorepoPolicy adds the package mappings read from a reference project to the mappings of
+// the template type
+type monorepoPolicy struct {
+	*MonorepoTemplate
+	packageMappings []core.Mapping
+}
+
+// GetMappings returns the mappings of the template type followed by the package mappings
+func (p *monorepoPolicy) GetMappings(filePath string) []core.Mapping {
+	return append(p.MonorepoTemplate.GetMappings(filePath), p.packageMappings...)
+}
+
+// monorepoMappingRules holds the string replacement mappings per file pattern. The package
+// names are mapped in every templated file, see packageMappings.
+var monorepoMappingRules = []core.MappingRule{
+	{
+		Pattern: ReadmeFile,
+		Mappings: []core.Mapping{
+			{Find: "# Monorepo Template", Replace: "# {{.ProjectName}}"},
+			{Find: "github.com/acheevo/" + monorepoName, Replace: "github.com/{{.GitHubRepo}}"},
+			{Find: "cd " + monorepoName, Replace: "cd {{.RepoName}}"},
+		},
+	},
+}
+
+// monorepoTemplatePatterns lists the files that need template processing: those referencing
+// workspace packages in imports, dependencies and task filters
+var monorepoTemplatePatterns = []string{
+	"**/*.md",
+	"**/package.json",
+	"**/tsconfig*.json",
+	"**/project.json",
+	"turbo.json",
+	"nx.json",
+	"**/*.ts",
+	"**/*.tsx",
+	"**/*.js",
+	"**/*.jsx",
+	"**/*.mjs",
+	"**/*.cjs",
+}
+
+// GetMappings returns the string replacement mappings for a specific file
+func (m *MonorepoTemplate) GetMappings(filePath string) []core.Mapping {
+	return core.MappingsFor(monorepoMappingRules, filePath)
+}
+
+// monorepoPatchRules holds the structured patches per file pattern
+var monorepoPatchRules = []core.PatchRule{
+	{
+		Pattern: "package.json",
+		Patches: packageJSONPatches(""),
+	},
+}
+
+// GetPatches returns the structured patches for a specific file
+func (m *MonorepoTemplate) GetPatches(filePath string) []core.Patch {
+	return core.PatchesFor(monorepoPatchRules, filePath)
+}
+
+// GetVariables returns the variables used by this template type
+func (m *MonorepoTemplate) GetVariables() map[string]core.Variable {
+	return map[string]core.Variable{
+		"ProjectName": {
+			Type:        "string",
+			Required:    true,
+			Description: "Name of the monorepo, its kebab-case form scopes the workspace packages",
+		},
+		"GitHubRepo": {
+			Type:        "string",
+			Required:    true,
+			Description: "GitHub repository (e.g., username/repo-name)",
+		},
+		"Author": {
+			Type:        "string",
+			Required:    false,
+			Default:     "Developer",
+			Description: "Project author name",
+		},
+		"Description": {
+			Type:        "string",
+			Required:    false,
+			Default:     "A TypeScript monorepo",
+			Description: "Project description",
+		},
+	}
+}
+
+// ShouldTemplate determines if a file needs template processing
+func (m *MonorepoTemplate) ShouldTemplate(filePath string) bool {
+	return core.MatchAnyGlob(monorepoTemplatePatterns, filePath)
+}
+
+// ShouldSkip determines if a file/directory should be skipped during extraction
+func (m *MonorepoTemplate) ShouldSkip(path string) bool {
+	return m.SkipReason(path) != ""
+}
+
+// monorepoSkipRules holds the files left out of monorepo schemas: installed packages, build
+// outputs and tool caches
+var monorepoSkipRules = skipRules{
+	Dirs: []string{"node_modules", ".turbo", ".nx", ".next", "dist", "build", "coverage"},
+	Keep: []string{
+		".eslintrc.cjs",
+		".eslintrc.js",
+		".eslintrc.json",
+		".prettierrc",
+		".prettierrc.json",
+		".npmrc",
+		".nvmrc",
+		".gitignore",
+		".env.example",
+	},
+}
+
+// SkipReason returns why a file/directory is skipped during extraction, empty when it is not
+func (m *MonorepoTemplate) SkipReason(path string) string {
+	return monorepoSkipRules.reason(path)
+}
@@ -0,0 +1,188 @@
+package templates
+
+import (
+	"context"
+	"io/fs"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+// PythonAPITemplate implements TemplateType for Python API services built with FastAPI
+type PythonAPITemplate struct{}
+
+// pythonAPIName is the distribution and image name of the reference project
+const pythonAPIName = "python-api-template"
+
+// Name returns the template type name
+func (p *PythonAPITemplate) Name() string {
+	return "python-api"
+}
+
+// Description describes the projects the template type extracts
+func (p *PythonAPITemplate) Description() string {
+	return "Python API with FastAPI + PostgreSQL"
+}
+
+// Category returns the kind of project the template type extracts
+func (p *PythonAPITemplate) Category() string {
+	return core.CategoryBackend
+}
+
+// Tags returns the keywords of the projects the template type extracts
+func (p *PythonAPITemplate) Tags() []string {
+	return []string{"python", "fastapi", "postgres", "rest"}
+}
+
+// Markers returns the paths a reference project of the template type contains
+func (p *PythonAPITemplate) Markers() []string {
+	return []string{"pyproject.toml", "app/main.py"}
+}
+
+// Extract analyzes a Python API project and creates a template schema
+func (p *PythonAPITemplate) Extract(
+	ctx context.Context, sourceDir string, opts core.ExtractOptions,
+) (*core.TemplateSchema, error) {
+	return p.ExtractFS(ctx, core.DirFS(sourceDir), opts)
+}
+
+// ExtractFS extracts the template from any file tree, such as a go:embed bundle
+func (p *PythonAPITemplate) ExtractFS(
+	ctx context.Context, fsys fs.FS, opts core.ExtractOptions,
+) (*core.TemplateSchema, error) {
+	schema := &core.TemplateSchema{
+		Name:        "python-api-template",
+		Type:        "python-api",
+		Version:     "1.0.0",
+		Description: "Python REST API template with FastAPI and PostgreSQL",
+		Variables:   p.GetVariables(),
+		Tags:        p.Tags(),
+		Category:    p.Category(),
+		Hooks: core.Hooks{
+			{Name: "sync", Stage: core.HookPostGenerate, Command: "uv sync", Condition: "command:uv"},
+		},
+	}
+
+	return newExtractor(p).ExtractFS(ctx, fsys, schema, opts)
+}
+
+// pythonAPIMappingRules holds the string replacement mappings per file pattern
+var pythonAPIMappingRules = []core.MappingRule{
+	{
+		Pattern: ReadmeFile,
+		Mappings: []core.Mapping{
+			{Find: "# Python API Template", Replace: "# {{.ProjectName}}"},
+			{
+				Find:    "git clone https://github.com/acheevo/" + pythonAPIName + ".git",
+				Replace: "git clone https://github.com/{{.GitHubRepo}}.git",
+			},
+			{Find: "cd " + pythonAPIName, Replace: "cd {{.ProjectNameKebab}}"},
+		},
+	},
+	{
+		Pattern: "pyproject.toml",
+		Mappings: []core.Mapping{
+			{Find: `name = "` + pythonAPIName + `"`, Replace: `name = "{{.ProjectNameKebab}}"`},
+			{Find: `(?m)^description = "[^"\n]*"`, Replace: `description = "{{.Description}}"`, Regex: true},
+			// PEP 621 author tables, then Poetry author strings
+			{Find: `(?m)^authors = \[\s*\{[^\]]*\]`, Replace: `authors = [{ name = "{{.Author}}" }]`, Regex: true},
+			{Find: `(?m)^authors = \[\s*"[^\]]*\]`, Replace: `authors = ["{{.Author}}"]`, Regex: true},
+		},
+	},
+	{
+		Pattern: "Dockerfile",
+		Mappings: []core.Mapping{
+			{Find: pythonAPIName, Replace: "{{.ProjectNameKebab}}"},
+		},
+	},
+	{
+		Pattern: "docker-compose.yml",
+		Mappings: []core.Mapping{
+			{Find: pythonAPIName, Replace: "{{.ProjectNameKebab}}"},
+			{Find: "python_api_template", Replace: "{{.ProjectNameSnake}}"},
+		},
+	},
+	{
+		Pattern: "app/core/config.py",
+		Mappings: []core.Mapping{
+			{Find: `"Python API Template"`, Replace: `"{{.ProjectName}}"`},
+			{Find: "python_api_template", Replace: "{{.ProjectNameSnake}}"},
+		},
+	},
+}
+
+// pythonAPITemplatePatterns lists the files that need template processing
+var pythonAPITemplatePatterns = []string{
+	ReadmeFile,
+	"pyproject.toml",
+	"Dockerfile",
+	"docker-compose.yml",
+	"app/core/config.py",
+}
+
+// GetMappings returns the string replacement mappings for a specific file
+func (p *PythonAPITemplate) GetMappings(filePath string) []core.Mapping {
+	return core.MappingsFor(pythonAPIMappingRules, filePath)
+}
+
+// GetVariables returns the variables used by this template type
+func (p *PythonAPITemplate) GetVariables() map[string]core.Variable {
+	return map[string]core.Variable{
+		"ProjectName": {
+			Type:        "string",
+			Required:    true,
+			Description: "Name of the API service",
+		},
+		"GitHubRepo": {
+			Type:        "string",
+			Required:    true,
+			Description: "GitHub repository (e.g., username/repo-name)",
+		},
+		"Author": {
+			Type:        "string",
+			Required:    false,
+			Default:     "Developer",
+			Description: "Project author name",
+		},
+		"Description": {
+			Type:        "string",
+			Required:    false,
+			Default:     "A Python REST API application",
+			Description: "Project description",
+		},
+	}
+}
+
+// ShouldTemplate determines if a file needs template processing
+func (p *PythonAPITemplate) ShouldTemplate(filePath string) bool {
+	return core.MatchAnyGlob(pythonAPITemplatePatterns, filePath)
+}
+
+// ShouldSkip determines if a file/directory should be skipped during extraction
+func (p *PythonAPITemplate) ShouldSkip(path string) bool {
+	return p.SkipReason(path) != ""
+}
+
+// pythonAPISkipRules holds the files left out of Python API schemas: bytecode, virtualenvs,
+// tool caches and the packaging metadata built from the sources
+var pythonAPISkipRules = skipRules{
+	Files: []skipPattern{{Pattern: "*.pyc"}, {Pattern: "*.pyo"}},
+	Dirs: []string{
+		".venv",
+		"venv",
+		"__pycache__",
+		".pytest_cache",
+		".mypy_cache",
+		".ruff_cache",
+		".tox",
+		"htmlcov",
+		"dist",
+		"build",
+		"*.egg-info",
+	},
+	Keep: []string{".dockerignore", ".gitignore", ".python-version", ".pre-commit-config.yaml", ".env.example"},
+}
+
+// SkipReason returns why a file/directory is skipped during extraction, empty when it is not
+func (p *PythonAPITemplate) SkipReason(path string) string {
+	return pythonAPISkipRules.reason(path)
+}
@@ -1,9 +1,16 @@
 package templates
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"testing/fstest"
+
+	"github.com/acheevo/template-engine/internal/core"
 )
 
 func TestFrontendTemplateExtractWithEnvExample(t *testing.T) {
@@ -44,7 +51,7 @@ API_BASE_URL=http://localhost:8000/api`,
 
 	// Test extraction
 	frontend := &FrontendTemplate{}
-	schema, err := frontend.Extract(tempDir)
+	schema, err := frontend.Extract(context.Background(), tempDir, core.ExtractOptions{})
 	if err != nil {
 		t.Fatalf("Failed to extract frontend template: %v", err)
 	}
@@ -53,6 +60,11 @@ API_BASE_URL=http://localhost:8000/api`,
 	if schema.Type != "frontend" {
 		t.Errorf("Expected schema type 'frontend', got '%s'", schema.Type)
 	}
+	for _, file := range schema.Files {
+		if file.Path == "package.json" && (file.Template || len(file.JSONPatch) != 4) {
+			t.Errorf("Expected package.json to be patched rather than templated, got %+v", file)
+		}
+	}
 
 	// Verify environment configuration was extracted
 	if len(schema.EnvConfig) == 0 {
@@ -63,7 +75,7 @@ API_BASE_URL=http://localhost:8000/api`,
 		description string
 		example     string
 	}{
-		"APP_NAME":     {"Application name displayed in UI", "\"Test Frontend\""},
+		"APP_NAME":     {"Application name displayed in UI", "Test Frontend"},
 		"PORT":         {"Port for development server", "3000"},
 		"API_BASE_URL": {"API base URL", "http://localhost:8000/api"},
 	}
@@ -87,6 +99,47 @@ API_BASE_URL=http://localhost:8000/api`,
 	}
 }
 
+func TestFrontendTemplateBuildConfig(t *testing.T) {
+	tsconfig := "{\n  \"compilerOptions\": {\n    \"paths\": { \"@frontend-template/*\": [\"./src/*\"] }\n  }\n}\n"
+	vite := "export default defineConfig({\n  base: '/frontend-template/',\n" +
+		"  resolve: { alias: { '@frontend-template': '/src' } },\n})\n"
+	app := "import { Button } from '@frontend-template/components/Button';\n" +
+		"export const App = () => <Button style={{ margin: 0 }} />;\n"
+	manifest := "{\n  \"name\": \"Frontend Template\",\n  \"short_name\": \"Frontend Template\"\n}\n"
+	fsys := fstest.MapFS{
+		"package.json":         {Data: []byte("{\"name\": \"frontend-template\"}\n")},
+		"tsconfig.app.json":    {Data: []byte(tsconfig)},
+		"vite.config.ts":       {Data: []byte(vite)},
+		"src/App.tsx":          {Data: []byte(app)},
+		"public/manifest.json": {Data: []byte(manifest)},
+	}
+
+	schema, err := (&FrontendTemplate{}).ExtractFS(context.Background(), fsys, core.ExtractOptions{})
+	if err != nil {
+		t.Fatalf("ExtractFS() error = %v", err)
+	}
+	files := map[string]core.FileSpec{}
+	for _, file := range schema.Files {
+		files[filepath.ToSlash(file.Path)] = file
+	}
+
+	for _, tc := range []struct{ path, content, want string }{
+		{"tsconfig.app.json", tsconfig, `"@{{.ProjectNameKebab}}/*": ["./src/*"]`},
+		{"vite.config.ts", vite, "base: '/{{.RepoName}}/'"},
+		{"vite.config.ts", vite, "'@{{.ProjectNameKebab}}': '/src'"},
+		{"src/App.tsx", app, "from '@{{.ProjectNameKebab}}/components/Button'"},
+		{"public/manifest.json", manifest, `"short_name": "{{.ProjectName}}"`},
+	} {
+		rendered, err := core.ApplyMappings(tc.content, files[tc.path].Mappings)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !files[tc.path].Template || !strings.Contains(rendered, tc.want) {
+			t.Errorf("%s mapped to %q, want it to contain %q", tc.path, rendered, tc.want)
+		}
+	}
+}
+
 func TestGoAPITemplateExtractWithEnvExample(t *testing.T) {
 	// Create a temporary directory for testing
 	tempDir, err := os.MkdirTemp("", "go-api-test-")
@@ -128,7 +181,7 @@ JWT_SECRET=test-secret`,
 
 	// Test extraction
 	goAPI := &GoAPITemplate{}
-	schema, err := goAPI.Extract(tempDir)
+	schema, err := goAPI.Extract(context.Background(), tempDir, core.ExtractOptions{})
 	if err != nil {
 		t.Fatalf("Failed to extract Go API template: %v", err)
 	}
@@ -137,6 +190,9 @@ JWT_SECRET=test-secret`,
 	if schema.Type != "go-api" {
 		t.Errorf("Expected schema type 'go-api', got '%s'", schema.Type)
 	}
+	if len(schema.GoModules) != 1 || schema.GoModules[0].Path != "go.mod" {
+		t.Errorf("Expected go.mod to be declared as a go module, got %v", schema.GoModules)
+	}
 
 	// Verify environment configuration was extracted
 	if len(schema.EnvConfig) == 0 {
@@ -193,7 +249,7 @@ func TestTemplateExtractWithoutEnvExample(t *testing.T) {
 
 	// Test extraction
 	frontend := &FrontendTemplate{}
-	schema, err := frontend.Extract(tempDir)
+	schema, err := frontend.Extract(context.Background(), tempDir, core.ExtractOptions{})
 	if err != nil {
 		t.Fatalf("Failed to extract frontend template: %v", err)
 	}
@@ -207,3 +263,582 @@ func TestTemplateExtractWithoutEnvExample(t *testing.T) {
 		t.Errorf("Expected no environment variables, got %d", len(schema.EnvConfig))
 	}
 }
+
+func TestTemplateExtractCancelledContext(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "cancel-test-")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := os.WriteFile(filepath.Join(tempDir, "go.mod"), []byte("module test"), 0o644); err != nil {
+		t.Fatalf("Failed to write go.mod: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, tmpl := range []core.TemplateType{&FrontendTemplate{}, &GoAPITemplate{}, &FullstackTemplate{}} {
+		if _, err := tmpl.Extract(ctx, tempDir, core.ExtractOptions{}); !errors.Is(err, context.Canceled) {
+			t.Errorf("%T.Extract() error = %v, want context.Canceled", tmpl, err)
+		}
+	}
+}
+
+func TestGlobBasedTemplatingRules(t *testing.T) {
+	goAPI := &GoAPITemplate{}
+
+	if !goAPI.ShouldTemplate("internal/user/service/deep/handler.go") {
+		t.Error("Expected nested Go files to be templated")
+	}
+	if goAPI.ShouldTemplate("docs/guide.md") {
+		t.Error("Expected docs/guide.md not to be templated")
+	}
+
+	// Only the specific rules apply to config.go, imports follow the schema's go modules
+	mappings := goAPI.GetMappings("internal/shared/config/config.go")
+	if len(mappings) != 2 {
+		t.Errorf("Expected 2 mappings for config.go, got %d: %v", len(mappings), mappings)
+	}
+	if goAPI.ShouldTemplate("go.mod") {
+		t.Error("Expected go.mod not to be templated, it is rewritten as a go module")
+	}
+
+	fullstack := &FullstackTemplate{}
+	if mappings := fullstack.GetMappings("frontend/src/config/app.ts"); len(mappings) != 1 {
+		t.Errorf("Expected 1 mapping for frontend/src/config/app.ts, got %v", mappings)
+	}
+	if mappings := fullstack.GetMappings("frontend/src/other.ts"); len(mappings) != 0 {
+		t.Errorf("Expected no mappings for frontend/src/other.ts, got %v", mappings)
+	}
+}
+
+func TestExtractCompressesEveryTemplateType(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "compress-test-")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	large := strings.Repeat("Documentation line\n", 200)
+	if err := os.WriteFile(filepath.Join(tempDir, "GUIDE.txt"), []byte(large), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	for _, tmpl := range []core.TemplateType{&FrontendTemplate{}, &GoAPITemplate{}, &FullstackTemplate{}} {
+		schema, err := tmpl.Extract(context.Background(), tempDir, core.ExtractOptions{})
+		if err != nil {
+			t.Fatalf("%T.Extract() error = %v", tmpl, err)
+		}
+		if len(schema.Files) != 1 || !schema.Files[0].Compressed {
+			t.Errorf("%T.Extract() should compress large files by default: %+v", tmpl, schema.Files)
+		}
+
+		schema, err = tmpl.Extract(context.Background(), tempDir, core.ExtractOptions{Codec: core.CodecNone})
+		if err != nil {
+			t.Fatalf("%T.Extract() error = %v", tmpl, err)
+		}
+		if schema.Files[0].Compressed || schema.Files[0].Content != large {
+			t.Errorf("%T.Extract() with codec none should store raw content", tmpl)
+		}
+	}
+}
+
+func TestExtractCarriesTypeMetadata(t *testing.T) {
+	expected := map[string]string{
+		"frontend":  core.CategoryFrontend,
+		"go-api":    core.CategoryBackend,
+		"fullstack": core.CategoryFullstack,
+	}
+
+	for _, tmpl := range []core.TemplateType{&FrontendTemplate{}, &GoAPITemplate{}, &FullstackTemplate{}} {
+		category, tags := core.TypeMetadata(tmpl)
+		if category != expected[tmpl.Name()] || len(tags) == 0 {
+			t.Errorf("TypeMetadata(%s) = %q, %v", tmpl.Name(), category, tags)
+		}
+
+		schema, err := tmpl.ExtractFS(context.Background(), fstest.MapFS{}, core.ExtractOptions{})
+		if err != nil {
+			t.Fatalf("%s.ExtractFS() error = %v", tmpl.Name(), err)
+		}
+		if schema.Category != category || !reflect.DeepEqual(schema.Tags, tags) {
+			t.Errorf("%s schema category %q, tags %v, want %q, %v", tmpl.Name(), schema.Category, schema.Tags, category, tags)
+		}
+	}
+}
+
+func TestFullstackExtractNestedEnvExample(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "env-test-")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	projectFiles := map[string]string{
+		".env.example":          "# Database host\nDB_HOST=localhost",
+		"frontend/.env.example": "VITE_API_URL=http://localhost:8080",
+	}
+	for path, content := range projectFiles {
+		fullPath := filepath.Join(tempDir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", path, err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write file %s: %v", path, err)
+		}
+	}
+
+	schema, err := (&FullstackTemplate{}).Extract(context.Background(), tempDir, core.ExtractOptions{})
+	if err != nil {
+		t.Fatalf("Failed to extract fullstack template: %v", err)
+	}
+
+	if len(schema.EnvConfig) != 2 {
+		t.Fatalf("Expected 2 environment variables, got %+v", schema.EnvConfig)
+	}
+	if schema.EnvConfig[0].Name != "DB_HOST" || schema.EnvConfig[0].Source != ".env.example" {
+		t.Errorf("Expected root DB_HOST first, got %+v", schema.EnvConfig[0])
+	}
+	if schema.EnvConfig[1].Name != "VITE_API_URL" || schema.EnvConfig[1].Source != "frontend/.env.example" {
+		t.Errorf("Expected VITE_API_URL from frontend/.env.example, got %+v", schema.EnvConfig[1])
+	}
+	if schema.EnvConfig[0].Key() != "api.DB_HOST" || schema.EnvConfig[1].Key() != "frontend.VITE_API_URL" {
+		t.Errorf("Expected variables namespaced by component, got %+v", schema.EnvConfig)
+	}
+}
+
+func TestGoCLITemplateExtract(t *testing.T) {
+	rootGo := "package cmd\n\nvar rootCmd = &cobra.Command{\n\tUse:   \"cli-template\",\n}\n\n" +
+		"// Config is read from $HOME/.cli-template.yaml\n"
+	fsys := fstest.MapFS{
+		"go.mod":           {Data: []byte("module github.com/acheevo/cli-template\n")},
+		"main.go":          {Data: []byte("package main\n")},
+		"cmd/root.go":      {Data: []byte(rootGo)},
+		"Makefile":         {Data: []byte("BINARY=cli-template\n")},
+		".goreleaser.yaml": {Data: []byte("project_name: cli-template\nbuilds:\n  - binary: cli-template\n")},
+		"cli-template":     {Data: []byte("\x7fELF\x00")},
+		"dist/checksums":   {Data: []byte("abc")},
+	}
+
+	schema, err := (&GoCLITemplate{}).ExtractFS(context.Background(), fsys, core.ExtractOptions{})
+	if err != nil {
+		t.Fatalf("ExtractFS() error = %v", err)
+	}
+
+	files := map[string]core.FileSpec{}
+	for _, file := range schema.Files {
+		files[filepath.ToSlash(file.Path)] = file
+	}
+	if _, ok := files["cli-template"]; ok {
+		t.Error("ExtractFS() should skip the compiled binary")
+	}
+	if _, ok := files["dist/checksums"]; ok {
+		t.Error("ExtractFS() should skip the GoReleaser dist directory")
+	}
+	if schema.Type != "go-cli" || schema.Category != core.CategoryCLI || len(schema.GoModules) != 1 {
+		t.Errorf("ExtractFS() schema = %s (%s), go modules %+v", schema.Type, schema.Category, schema.GoModules)
+	}
+
+	root := files["cmd/root.go"]
+	rendered, err := core.ApplyMappings(rootGo, root.Mappings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "package cmd\n\nvar rootCmd = &cobra.Command{\n\tUse:   \"{{.ProjectNameKebab}}\",\n}\n\n" +
+		"// Config is read from $HOME/.{{.ProjectNameKebab}}.yaml\n"
+	if !root.Template || rendered != want {
+		t.Errorf("cmd/root.go mapped to %q, want %q", rendered, want)
+	}
+
+	goreleaser := files[".goreleaser.yaml"]
+	if goreleaser.Template || len(goreleaser.YAMLPatch) != 2 {
+		t.Errorf(".goreleaser.yaml should be patched, not templated: %+v", goreleaser)
+	}
+	if makefile := files["Makefile"]; !makefile.Template || len(makefile.Mappings) != 1 {
+		t.Errorf("Makefile mappings = %+v", makefile.Mappings)
+	}
+}
+
+func TestGoLibraryTemplateExtract(t *testing.T) {
+	readme := "# Go Library Template\n\n" +
+		"[![Go Reference](https://pkg.go.dev/badge/github.com/acheevo/library-template.svg)]" +
+		"(https://pkg.go.dev/github.com/acheevo/library-template)\n"
+	fsys := fstest.MapFS{
+		"go.mod":    {Data: []byte("module github.com/acheevo/library-template\n")},
+		"doc.go":    {Data: []byte("// Package libtemplate does things.\npackage libtemplate\n")},
+		"README.md": {Data: []byte(readme)},
+	}
+
+	schema, err := (&GoLibraryTemplate{}).ExtractFS(context.Background(), fsys, core.ExtractOptions{})
+	if err != nil {
+		t.Fatalf("ExtractFS() error = %v", err)
+	}
+
+	if len(schema.GoModules) != 1 || schema.GoModules[0].Package != "{{.ProjectNamePackage}}" {
+		t.Errorf("ExtractFS() go modules = %+v, want the root package renamed", schema.GoModules)
+	}
+	for _, file := range schema.Files {
+		if file.Template != (file.Path == ReadmeFile) {
+			t.Errorf("%s templated = %v, only the README should be", file.Path, file.Template)
+		}
+		if file.Path != ReadmeFile {
+			continue
+		}
+		rendered, err := core.ApplyMappings(readme, file.Mappings)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(rendered, "acheevo/library-template") || !strings.HasPrefix(rendered, "# {{.ProjectName}}") {
+			t.Errorf("README.md mapped to %q", rendered)
+		}
+	}
+}
+
+func TestPythonAPITemplateExtract(t *testing.T) {
+	pyproject := "[project]\nname = \"python-api-template\"\ndescription = \"FastAPI starter\"\n" +
+		"authors = [\n  { name = \"Acheevo\", email = \"dev@acheevo.com\" },\n]\n\n" +
+		"[tool.ruff]\nline-length = 100\n"
+	fsys := fstest.MapFS{
+		"pyproject.toml":                       {Data: []byte(pyproject)},
+		"app/main.py":                          {Data: []byte("from fastapi import FastAPI\n")},
+		".env.example":                         {Data: []byte("# Database URL\nDATABASE_URL=postgresql://localhost/app\n")},
+		".python-version":                      {Data: []byte("3.12\n")},
+		".venv/.gitignore":                     {Data: []byte("*\n")},
+		".venv/lib/site.py":                    {Data: []byte("")},
+		"app/__pycache__/main.cpython-312.pyc": {Data: []byte("\x00")},
+		"app.egg-info/PKG-INFO":                {Data: []byte("Name: app\n")},
+	}
+
+	schema, err := (&PythonAPITemplate{}).ExtractFS(context.Background(), fsys, core.ExtractOptions{})
+	if err != nil {
+		t.Fatalf("ExtractFS() error = %v", err)
+	}
+
+	var paths []string
+	var project core.FileSpec
+	for _, file := range schema.Files {
+		paths = append(paths, filepath.ToSlash(file.Path))
+		if file.Path == "pyproject.toml" {
+			project = file
+		}
+	}
+	if got := strings.Join(paths, ","); got != ".env.example,.python-version,app/main.py,pyproject.toml" {
+		t.Errorf("ExtractFS() files = %s", got)
+	}
+	if len(schema.EnvConfig) != 1 || schema.EnvConfig[0].Name != "DATABASE_URL" {
+		t.Errorf("ExtractFS() env config = %+v", schema.EnvConfig)
+	}
+
+	rendered, err := core.ApplyMappings(pyproject, project.Mappings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "[project]\nname = \"{{.ProjectNameKebab}}\"\ndescription = \"{{.Description}}\"\n" +
+		"authors = [{ name = \"{{.Author}}\" }]\n\n[tool.ruff]\nline-length = 100\n"
+	if !project.Template || rendered != want {
+		t.Errorf("pyproject.toml mapped to %q, want %q", rendered, want)
+	}
+}
+
+func TestInfraTemplateExtract(t *testing.T) {
+	variables := "variable \"name\" {\n  type    = string\n  default = \"infra-template\"\n}\n"
+	backend := "terraform {\n  backend \"s3\" {\n    bucket = \"acheevo-state\"\n" +
+		"    key    = \"infra-template/terraform.tfstate\"\n  }\n}\n"
+	fsys := fstest.MapFS{
+		"main.tf":                           {Data: []byte("resource \"null_resource\" \"this\" {}\n")},
+		"variables.tf":                      {Data: []byte(variables)},
+		"backend.tf":                        {Data: []byte(backend)},
+		".terraform.lock.hcl":               {Data: []byte("# lock\n")},
+		".terraform/providers/registry.txt": {Data: []byte("")},
+		"terraform.tfstate":                 {Data: []byte("{}\n")},
+		"terraform.tfstate.backup":          {Data: []byte("{}\n")},
+		"prod.tfvars":                       {Data: []byte("name = \"prod\"\n")},
+	}
+
+	schema, err := (&InfraTemplate{}).ExtractFS(context.Background(), fsys, core.ExtractOptions{})
+	if err != nil {
+		t.Fatalf("ExtractFS() error = %v", err)
+	}
+
+	files := map[string]core.FileSpec{}
+	var paths []string
+	for _, file := range schema.Files {
+		paths = append(paths, filepath.ToSlash(file.Path))
+		files[file.Path] = file
+	}
+	if got := strings.Join(paths, ","); got != ".terraform.lock.hcl,backend.tf,main.tf,variables.tf" {
+		t.Errorf("ExtractFS() files = %s", got)
+	}
+
+	for _, tc := range []struct{ path, content, want string }{
+		{"variables.tf", variables, "default = \"{{.ProjectNameKebab}}\""},
+		{"backend.tf", backend, "key    = \"{{.ProjectNameKebab}}/terraform.tfstate\""},
+	} {
+		rendered, err := core.ApplyMappings(tc.content, files[tc.path].Mappings)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !files[tc.path].Template || !strings.Contains(rendered, tc.want) {
+			t.Errorf("%s mapped to %q, want it to contain %q", tc.path, rendered, tc.want)
+		}
+	}
+}
+
+func TestHelmChartTemplateExtract(t *testing.T) {
+	helpers := "{{- define \"chart-template.fullname\" -}}\n{{ .Release.Name }}\n{{- end }}\n"
+	fsys := fstest.MapFS{
+		"Chart.yaml":              {Data: []byte("apiVersion: v2\nname: chart-template\n")},
+		"values.yaml":             {Data: []byte("image:\n  repository: ghcr.io/acheevo/chart-template\n")},
+		"templates/_helpers.tpl":  {Data: []byte(helpers)},
+		".helmignore":             {Data: []byte(".git/\n")},
+		"charts/redis-18.0.0.tgz": {Data: []byte("\x1f\x8b")},
+	}
+
+	schema, err := (&HelmChartTemplate{}).ExtractFS(context.Background(), fsys, core.ExtractOptions{})
+	if err != nil {
+		t.Fatalf("ExtractFS() error = %v", err)
+	}
+	if left, right := core.EffectiveDelims(schema, core.FileSpec{}); left != "[[" || right != "]]" {
+		t.Errorf("ExtractFS() delims = %s %s, want [[ ]]", left, right)
+	}
+
+	files := map[string]core.FileSpec{}
+	var paths []string
+	for _, file := range schema.Files {
+		paths = append(paths, filepath.ToSlash(file.Path))
+		files[filepath.ToSlash(file.Path)] = file
+	}
+	if got := strings.Join(paths, ","); got != ".helmignore,Chart.yaml,templates/_helpers.tpl,values.yaml" {
+		t.Errorf("ExtractFS() files = %s", got)
+	}
+	if len(files["Chart.yaml"].YAMLPatch) != 2 || len(files["values.yaml"].YAMLPatch) != 1 {
+		t.Errorf("ExtractFS() patches = %+v, %+v", files["Chart.yaml"].YAMLPatch, files["values.yaml"].YAMLPatch)
+	}
+
+	helpersFile := files["templates/_helpers.tpl"]
+	rendered, err := core.ApplyMappings(helpers, helpersFile.Mappings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "{{- define \"{{.ProjectNameKebab}}.fullname\" -}}\n{{ .Release.Name }}\n{{- end }}\n"
+	if !helpersFile.Template || rendered != want {
+		t.Errorf("_helpers.tpl mapped to %q, want %q", rendered, want)
+	}
+}
+
+func TestMonorepoTemplateExtract(t *testing.T) {
+	web := `{"name": "@monorepo-template/web", "dependencies": {"@monorepo-template/ui-kit": "workspace:*"}}`
+	page := "import { Button } from '@monorepo-template/ui-kit/button'\nimport { cn } from 'monorepo-template-utils'\n"
+	fsys := fstest.MapFS{
+		"package.json":                        {Data: []byte(`{"name": "monorepo-template", "private": true}`)},
+		"pnpm-workspace.yaml":                 {Data: []byte("packages:\n  - apps/*\n  - packages/*\n")},
+		"apps/web/package.json":               {Data: []byte(web)},
+		"apps/web/src/page.tsx":               {Data: []byte(page)},
+		"packages/ui-kit/package.json":        {Data: []byte(`{"name": "@monorepo-template/ui-kit"}`)},
+		"packages/ui/package.json":            {Data: []byte(`{"name": "@monorepo-template/ui"}`)},
+		"packages/utils/package.json":         {Data: []byte(`{"name": "monorepo-template-utils"}`)},
+		"node_modules/react/package.json":     {Data: []byte(`{"name": "react"}`)},
+		"apps/web/.turbo/turbo-build.log":     {Data: []byte("built\n")},
+		"apps/web/node_modules/.bin/next":     {Data: []byte("#!/bin/sh\n")},
+		"packages/ui/node_modules/.gitignore": {Data: []byte("*\n")},
+	}
+
+	schema, err := (&MonorepoTemplate{}).ExtractFS(context.Background(), fsys, core.ExtractOptions{})
+	if err != nil {
+		t.Fatalf("ExtractFS() error = %v", err)
+	}
+
+	var dirs []string
+	for _, pkg := range schema.Packages {
+		dirs = append(dirs, pkg.Dir+"="+pkg.Name)
+	}
+	want := "apps/web=@monorepo-template/web,packages/ui=@monorepo-template/ui," +
+		"packages/ui-kit=@monorepo-template/ui-kit,packages/utils=monorepo-template-utils"
+	if got := strings.Join(dirs, ","); got != want {
+		t.Errorf("ExtractFS() packages = %s, want %s", got, want)
+	}
+	if len(schema.Hooks) != 1 || schema.Hooks[0].Command != "pnpm install" {
+		t.Errorf("ExtractFS() hooks = %+v, want pnpm install", schema.Hooks)
+	}
+
+	files := map[string]core.FileSpec{}
+	for _, file := range schema.Files {
+		files[filepath.ToSlash(file.Path)] = file
+	}
+	if len(files) != 7 {
+		t.Errorf("ExtractFS() extracted %d files, want 7 without the installed and cached ones", len(files))
+	}
+
+	for _, tc := range []struct{ path, content, want string }{
+		{"apps/web/package.json", web,
+			`{"name": "@{{.ProjectNameKebab}}/web", "dependencies": {"@{{.ProjectNameKebab}}/ui-kit": "workspace:*"}}`},
+		{"apps/web/src/page.tsx", page, "import { Button } from '@{{.ProjectNameKebab}}/ui-kit/button'\n" +
+			"import { cn } from '{{.ProjectNameKebab}}-utils'\n"},
+	} {
+		rendered, err := core.ApplyMappings(tc.content, files[tc.path].Mappings)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !files[tc.path].Template || rendered != tc.want {
+			t.Errorf("%s mapped to %q, want %q", tc.path, rendered, tc.want)
+		}
+	}
+}
+
+func TestGenericTemplateExtract(t *testing.T) {
+	fsys := fstest.MapFS{
+		"README.md":              {Data: []byte("# __PROJECT_NAME__\n\nhttps://github.com/__GITHUB_REPO__\n")},
+		"src/main.rs":            {Data: []byte("const NAME: &str = \"__PROJECT_NAME_SNAKE__\"; // __SERVICE__\n")},
+		"Cargo.toml":             {Data: []byte("[package]\nname = \"demo\"\n")},
+		"target/debug/demo":      {Data: []byte("\x7fELF")},
+		"target/keep.txt":        {Data: []byte("kept\n")},
+		"docs/internal/notes.md": {Data: []byte("__PROJECT_NAME__\n")},
+		".gitignore":             {Data: []byte("target/\n")},
+		".templateignore":        {Data: []byte("# build output\ntarget/\n!target/keep.txt\n/docs/internal\n")},
+		".templatetokens":        {Data: []byte("__SERVICE__ = {{.ProjectNameKebab}}-svc\n")},
+	}
+
+	schema, err := (&GenericTemplate{}).ExtractFS(context.Background(), fsys, core.ExtractOptions{})
+	if err != nil {
+		t.Fatalf("ExtractFS() error = %v", err)
+	}
+
+	files := map[string]core.FileSpec{}
+	var paths []string
+	for _, file := range schema.Files {
+		paths = append(paths, filepath.ToSlash(file.Path))
+		files[filepath.ToSlash(file.Path)] = file
+	}
+	if got := strings.Join(paths, ","); got != ".gitignore,Cargo.toml,README.md,src/main.rs" {
+		t.Errorf("ExtractFS() files = %s, want target/ left out by the .gitignore", got)
+	}
+	if files["Cargo.toml"].Template || files[".gitignore"].Template {
+		t.Error("ExtractFS() should only template files containing tokens")
+	}
+
+	rendered, err := core.ApplyMappings(`const NAME: &str = "__PROJECT_NAME_SNAKE__"; // __SERVICE__`,
+		files["src/main.rs"].Mappings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `const NAME: &str = "{{.ProjectNameSnake}}"; // {{.ProjectNameKebab}}-svc`; rendered != want {
+		t.Errorf("main.rs mapped to %q, want %q", rendered, want)
+	}
+	if len(files["README.md"].Mappings) != 2 {
+		t.Errorf("README.md mappings = %+v, want one per token it contains", files["README.md"].Mappings)
+	}
+
+	// The .gitignore excludes target/ before the .templateignore is read, so its re-include only
+	// applies when .gitignore files are not honored
+	schema, err = (&GenericTemplate{}).ExtractFS(context.Background(), fsys, core.ExtractOptions{NoGitignore: true})
+	if err != nil {
+		t.Fatalf("ExtractFS() error = %v", err)
+	}
+	paths = nil
+	for _, file := range schema.Files {
+		paths = append(paths, filepath.ToSlash(file.Path))
+	}
+	if got := strings.Join(paths, ","); got != ".gitignore,Cargo.toml,README.md,src/main.rs,target/keep.txt" {
+		t.Errorf("ExtractFS() with NoGitignore files = %s, want target/keep.txt re-included", got)
+	}
+
+	fsys[".templatetokens"] = &fstest.MapFile{Data: []byte("__SERVICE__\n")}
+	if _, err := (&GenericTemplate{}).ExtractFS(context.Background(), fsys, core.ExtractOptions{}); err == nil {
+		t.Error("ExtractFS() should fail for a token without replacement")
+	}
+}
+
+func TestSkipRules(t *testing.T) {
+	tests := []struct {
+		tmpl   core.TemplateType
+		path   string
+		reason string
+	}{
+		{&GoAPITemplate{}, "internal/binomial.go", ""},
+		{&GoAPITemplate{}, "environment/config.go", ""},
+		{&GoAPITemplate{}, "configs/app.git.yml", ""},
+		{&GoAPITemplate{}, "bin/api", core.SkipDirReason("bin")},
+		{&GoAPITemplate{}, "vendor/pkg/.gitignore", core.SkipDirReason("vendor")},
+		{&GoAPITemplate{}, "internal/.gitignore", ""},
+		{&GoAPITemplate{}, ".github/workflows/ci.yml", ""},
+		{&GoAPITemplate{}, ".claude/settings.json", ""},
+		{&GoAPITemplate{}, "docs/.claude.md", core.SkipReasonHidden},
+		{&GoAPITemplate{}, "sub/.git/config", core.SkipReasonGit},
+		{&FullstackTemplate{}, "frontend/src/node_modules_helper.ts", ""},
+		{&FullstackTemplate{}, "frontend/node_modules/react/index.js", core.SkipDirReason("node_modules")},
+		{&FullstackTemplate{}, "api", "compiled binary"},
+		{&FullstackTemplate{}, "cmd/api/main.go", ""},
+		{&PythonAPITemplate{}, "app.egg-info/PKG-INFO", core.SkipDirReason("*.egg-info")},
+		{&PythonAPITemplate{}, "app/__pycache__/main.cpython-312.pyc", "matched skip pattern *.pyc"},
+		{&InfraTemplate{}, "terraform.tfstate.backup", "terraform state"},
+	}
+	for _, tt := range tests {
+		path := filepath.FromSlash(tt.path)
+		if got := tt.tmpl.(core.SkipExplainer).SkipReason(path); got != tt.reason {
+			t.Errorf("%T.SkipReason(%s) = %q, want %q", tt.tmpl, tt.path, got, tt.reason)
+		}
+	}
+}
+
+func TestExtractFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"go.mod":            {Data: []byte("module github.com/test/api-template\n")},
+		"cmd/api/main.go":   {Data: []byte("package main\n")},
+		".env.example":      {Data: []byte("# Server address\nHTTP_ADDR=:8080\n")},
+		"vendor/pkg/lib.go": {Data: []byte("package pkg\n")},
+	}
+
+	schema, err := (&GoAPITemplate{}).ExtractFS(context.Background(), fsys, core.ExtractOptions{})
+	if err != nil {
+		t.Fatalf("ExtractFS() error = %v", err)
+	}
+
+	paths := map[string]bool{}
+	for _, file := range schema.Files {
+		paths[file.Path] = true
+	}
+	if !paths["go.mod"] || !paths[filepath.Join("cmd", "api", "main.go")] || !paths[".env.example"] {
+		t.Errorf("ExtractFS() files = %v", paths)
+	}
+	if paths[filepath.Join("vendor", "pkg", "lib.go")] {
+		t.Error("ExtractFS() should apply the skip rules to paths inside the file tree")
+	}
+	if len(schema.EnvConfig) != 1 || schema.EnvConfig[0].Name != "HTTP_ADDR" {
+		t.Errorf("ExtractFS() env config = %+v", schema.EnvConfig)
+	}
+}
+
+func TestExtractReportsSkippedFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"go.mod":            {Data: []byte("module github.com/test/api-template\n")},
+		".env":              {Data: []byte("SECRET=1\n")},
+		".git/HEAD":         {Data: []byte("ref: refs/heads/main\n")},
+		"vendor/pkg/lib.go": {Data: []byte("package pkg\n")},
+		"server.log":        {Data: []byte("started\n")},
+		"assets/logo.png":   {Data: []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")},
+	}
+
+	var skipped []core.SkippedFile
+	schema, err := (&GoAPITemplate{}).ExtractFS(context.Background(), fsys, core.ExtractOptions{
+		OnSkip: func(file core.SkippedFile) { skipped = append(skipped, file) },
+	})
+	if err != nil {
+		t.Fatalf("ExtractFS() error = %v", err)
+	}
+
+	// Binary files are embedded, never templated
+	if len(schema.Files) != 2 || schema.Files[0].Path != filepath.Join("assets", "logo.png") ||
+		schema.Files[0].Encoding != core.EncodingBase64 || schema.Files[1].Path != "go.mod" {
+		t.Errorf("ExtractFS() files = %+v, want the encoded logo and go.mod", schema.Files)
+	}
+
+	want := []core.SkippedFile{
+		{Path: ".env", Reason: core.SkipReasonHidden},
+		{Path: filepath.Join(".git", "HEAD"), Reason: core.SkipReasonGit},
+		{Path: "server.log", Reason: "matched skip pattern *.log"},
+		{Path: filepath.Join("vendor", "pkg", "lib.go"), Reason: core.SkipDirReason("vendor")},
+	}
+	if !reflect.DeepEqual(skipped, want) {
+		t.Errorf("skipped = %+v, want %+v", skipped, want)
+	}
+}
@@ -0,0 +1,163 @@
+// Package workspace generates several projects into one monorepo from a workspace spec
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/acheevo/template-engine/internal/generate"
+	"gopkg.in/yaml.v3"
+)
+
+// Spec lists the projects of a workspace:
+//
+//	name: acme-platform
+//	variables:            # shared by every project
+//	  GitHubRepo: acme/platform
+//	  Author: Jane Doe
+//	projects:
+//	  - schema: schemas/api.json   # relative to the workspace file
+//	    path: services/api         # relative to the workspace output directory
+//	    variables:
+//	      ProjectName: Acme API
+//	  - schema: schemas/frontend.json
+//	    path: web
+//	    variables:
+//	      ProjectName: Acme Web
+type Spec struct {
+	Name      string            `yaml:"name"`
+	Variables map[string]string `yaml:"variables"`
+	Projects  []Project         `yaml:"projects"`
+}
+
+// Project is one schema generated into a subpath of the workspace
+type Project struct {
+	Schema    string            `yaml:"schema"`
+	Path      string            `yaml:"path"`
+	Variables map[string]string `yaml:"variables"`
+}
+
+// Result summarizes a workspace generation
+type Result struct {
+	OutputDir string          `json:"output_dir"`
+	Projects  []ProjectResult `json:"projects"`
+}
+
+// ProjectResult is the outcome of one project
+type ProjectResult struct {
+	Path   string           `json:"path"`
+	Schema string           `json:"schema"`
+	Result *generate.Result `json:"result"`
+}
+
+// Load reads and validates a workspace spec. Schema paths are resolved against the spec's directory.
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workspace file: %w", err)
+	}
+
+	var spec Spec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse workspace file: %w", err)
+	}
+
+	for i := range spec.Projects {
+		project := &spec.Projects[i]
+		if project.Schema != "" && !filepath.IsAbs(project.Schema) {
+			project.Schema = filepath.Join(filepath.Dir(path), project.Schema)
+		}
+	}
+
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// Validate checks that every project has a schema, a unique relative path and,
+// once shared variables are applied, a ProjectName and GitHubRepo
+func (s *Spec) Validate() error {
+	if len(s.Projects) == 0 {
+		return fmt.Errorf("workspace must list at least one project")
+	}
+
+	seen := make(map[string]bool)
+	for i, project := range s.Projects {
+		if project.Schema == "" {
+			return fmt.Errorf("project %d must have a schema", i)
+		}
+
+		path := filepath.Clean(project.Path)
+		if project.Path == "" || filepath.IsAbs(path) || path == "." || strings.HasPrefix(path, "..") {
+			return fmt.Errorf("project %d path %q must be a subdirectory of the workspace", i, project.Path)
+		}
+		if seen[path] {
+			return fmt.Errorf("duplicate project path %q", project.Path)
+		}
+		seen[path] = true
+
+		variables := s.ProjectVariables(project)
+		for _, name := range []string{"ProjectName", "GitHubRepo"} {
+			if variables[name] == "" {
+				return fmt.Errorf("project %s has no %s, set it in its variables or the shared ones", project.Path, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ProjectVariables returns the shared variables overridden by the project's own
+func (s *Spec) ProjectVariables(project Project) map[string]string {
+	variables := make(map[string]string, len(s.Variables)+len(project.Variables))
+	for name, value := range s.Variables {
+		variables[name] = value
+	}
+	for name, value := range project.Variables {
+		variables[name] = value
+	}
+	return variables
+}
+
+// Run generates every project of spec below outputDir, stopping at the first failure.
+// Projects whose directory already exists are refused, like a single generate.
+func Run(ctx context.Context, logger *slog.Logger, spec *Spec, outputDir string, noVerify bool) (*Result, error) {
+	result := &Result{OutputDir: outputDir, Projects: []ProjectResult{}}
+	defaultAuthor := generate.DefaultAuthor(ctx)
+
+	for _, project := range spec.Projects {
+		variables := spec.ProjectVariables(project)
+		projectName, githubRepo := variables["ProjectName"], variables["GitHubRepo"]
+		delete(variables, "ProjectName")
+		delete(variables, "GitHubRepo")
+		if variables["Author"] == "" {
+			variables["Author"] = defaultAuthor
+		}
+
+		logger.Info("Generating workspace project", "path", project.Path, "schema", project.Schema)
+		generated, err := generate.RunWithParams(ctx, logger, generate.Params{
+			TemplateFile: project.Schema,
+			OutputDir:    filepath.Join(outputDir, project.Path),
+			ProjectName:  projectName,
+			GitHubRepo:   githubRepo,
+			Variables:    variables,
+			NoVerify:     noVerify,
+		})
+		if err != nil {
+			return result, fmt.Errorf("project %s: %w", project.Path, err)
+		}
+
+		result.Projects = append(result.Projects, ProjectResult{
+			Path:   project.Path,
+			Schema: project.Schema,
+			Result: generated,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,121 @@
+package workspace
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/logging"
+)
+
+func writeSchema(t *testing.T, path, content string) {
+	t.Helper()
+
+	schema := &core.TemplateSchema{
+		Name:      "test-template",
+		Type:      "frontend",
+		Version:   "1.0.0",
+		Variables: map[string]core.Variable{},
+		Files:     []core.FileSpec{{Path: "README.md", Template: true, Content: content}},
+	}
+	if err := core.SaveSchemaFile(schema, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "schemas"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeSchema(t, filepath.Join(dir, "schemas", "api.json"), "# {{.ProjectName}} ({{.GitHubRepo}}) by {{.Author}}")
+	writeSchema(t, filepath.Join(dir, "schemas", "web.json"), "# {{.ProjectNameKebab}}")
+
+	workspaceFile := filepath.Join(dir, "workspace.yaml")
+	spec := `name: acme
+variables:
+  GitHubRepo: acme/platform
+  Author: Jane Doe
+projects:
+  - schema: schemas/api.json
+    path: services/api
+    variables:
+      ProjectName: Acme API
+  - schema: schemas/web.json
+    path: web
+    variables:
+      ProjectName: Acme Web
+      GitHubRepo: acme/web
+`
+	if err := os.WriteFile(workspaceFile, []byte(spec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(workspaceFile)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	outputDir := filepath.Join(dir, "out")
+	result, err := Run(context.Background(), logging.Discard(), loaded, outputDir, false)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(result.Projects) != 2 {
+		t.Fatalf("Expected 2 project results, got %+v", result.Projects)
+	}
+
+	expected := map[string]string{
+		"services/api/README.md": "# Acme API (acme/platform) by Jane Doe",
+		"web/README.md":          "# acme-web",
+	}
+	for path, want := range expected {
+		content, err := os.ReadFile(filepath.Join(outputDir, filepath.FromSlash(path)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != want {
+			t.Errorf("%s = %q, want %q", path, content, want)
+		}
+	}
+
+	// Generating again refuses to overwrite the existing projects
+	if _, err := Run(context.Background(), logging.Discard(), loaded, outputDir, false); err == nil ||
+		!strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Expected existing output error, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	shared := map[string]string{"GitHubRepo": "acme/platform"}
+	named := map[string]string{"ProjectName": "Acme"}
+
+	tests := []struct {
+		name     string
+		projects []Project
+		wantErr  bool
+	}{
+		{"valid", []Project{{Schema: "a.json", Path: "a", Variables: named}}, false},
+		{"no projects", nil, true},
+		{"missing schema", []Project{{Path: "a", Variables: named}}, true},
+		{"missing path", []Project{{Schema: "a.json", Variables: named}}, true},
+		{"escaping path", []Project{{Schema: "a.json", Path: "../a", Variables: named}}, true},
+		{"absolute path", []Project{{Schema: "a.json", Path: "/a", Variables: named}}, true},
+		{"duplicate path", []Project{
+			{Schema: "a.json", Path: "a", Variables: named}, {Schema: "b.json", Path: "a/", Variables: named},
+		}, true},
+		{"missing project name", []Project{{Schema: "a.json", Path: "a"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &Spec{Variables: shared, Projects: tt.projects}
+			if err := spec.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
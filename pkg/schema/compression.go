@@ -0,0 +1,253 @@
+package schema
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// CompressionThreshold - files larger than this will be compressed
+	CompressionThreshold = 1024 // 1KB
+)
+
+// Codec names the compression algorithm used for file content
+type Codec string
+
+const (
+	CodecGzip Codec = "gzip"
+	CodecZstd Codec = "zstd"
+	CodecNone Codec = "none"
+)
+
+// Codecs lists the supported compression codecs
+var Codecs = []Codec{CodecGzip, CodecZstd, CodecNone}
+
+// zstd encoders and decoders are safe for concurrent EncodeAll/DecodeAll calls, so one of each is shared
+var (
+	zstdEncoder = sync.OnceValues(func() (*zstd.Encoder, error) { return zstd.NewWriter(nil) })
+	zstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) { return zstd.NewReader(nil) })
+)
+
+// ParseCodec validates a codec name
+func ParseCodec(name string) (Codec, error) {
+	for _, codec := range Codecs {
+		if string(codec) == name {
+			return codec, nil
+		}
+	}
+	return "", fmt.Errorf("unknown compression codec %q (available: %v)", name, Codecs)
+}
+
+// FileCodec returns the codec a file's content is stored with. Schemas written before codecs
+// were introduced only mark content as compressed, which always meant gzip.
+func FileCodec(file File) Codec {
+	switch {
+	case !file.Compressed:
+		return CodecNone
+	case file.Codec == "":
+		return CodecGzip
+	default:
+		return Codec(file.Codec)
+	}
+}
+
+// CompressContent compresses content with gzip if it's above the threshold
+func CompressContent(content string) (string, bool, error) {
+	return CompressWith(content, CodecGzip)
+}
+
+// CompressWith compresses content with codec if it's above the threshold and compression saves space
+func CompressWith(content string, codec Codec) (string, bool, error) {
+	if len(content) < CompressionThreshold || codec == CodecNone {
+		return content, false, nil
+	}
+
+	var data []byte
+	switch codec {
+	case CodecGzip:
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+
+		_, err := writer.Write([]byte(content))
+		if err != nil {
+			return content, false, err
+		}
+
+		err = writer.Close()
+		if err != nil {
+			return content, false, err
+		}
+		data = buf.Bytes()
+	case CodecZstd:
+		encoder, err := zstdEncoder()
+		if err != nil {
+			return content, false, err
+		}
+		data = encoder.EncodeAll([]byte(content), nil)
+	default:
+		return content, false, fmt.Errorf("unknown compression codec %q", codec)
+	}
+
+	// Encode compressed content as base64
+	compressed := base64.StdEncoding.EncodeToString(data)
+
+	// Only use compression if it actually saves space
+	if len(compressed) < len(content) {
+		return compressed, true, nil
+	}
+
+	return content, false, nil
+}
+
+// DecompressContent decompresses gzip content if it was compressed
+func DecompressContent(content string, compressed bool) (string, error) {
+	if !compressed {
+		return content, nil
+	}
+	return DecompressWith(content, CodecGzip)
+}
+
+// DecompressWith decodes base64 content compressed with codec
+func DecompressWith(content string, codec Codec) (string, error) {
+	if codec == CodecNone {
+		return content, nil
+	}
+
+	// Decode from base64
+	compressedData, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return "", err
+	}
+
+	switch codec {
+	case CodecGzip:
+		reader, err := gzip.NewReader(bytes.NewReader(compressedData))
+		if err != nil {
+			return "", err
+		}
+		defer reader.Close()
+
+		decompressed, err := io.ReadAll(reader)
+		if err != nil {
+			return "", err
+		}
+		return string(decompressed), nil
+	case CodecZstd:
+		decoder, err := zstdDecoder()
+		if err != nil {
+			return "", err
+		}
+		decompressed, err := decoder.DecodeAll(compressedData, nil)
+		if err != nil {
+			return "", err
+		}
+		return string(decompressed), nil
+	default:
+		return "", fmt.Errorf("unknown compression codec %q", codec)
+	}
+}
+
+// decompressReader returns a reader of base64 content compressed with codec, decompressing it
+// as it is read (see DecompressWith)
+func decompressReader(r io.Reader, codec Codec) (io.ReadCloser, error) {
+	if codec == CodecNone {
+		return io.NopCloser(r), nil
+	}
+
+	compressed := base64.NewDecoder(base64.StdEncoding, r)
+	switch codec {
+	case CodecGzip:
+		return gzip.NewReader(compressed)
+	case CodecZstd:
+		decoder, err := zstd.NewReader(compressed, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	default:
+		return nil, fmt.Errorf("unknown compression codec %q", codec)
+	}
+}
+
+// CompressFiles (re)compresses the inline content of files with codec, using one worker per CPU.
+// Files that share a blob through ContentRef are left untouched.
+func CompressFiles(ctx context.Context, files []File, codec Codec) error {
+	if _, err := ParseCodec(string(codec)); err != nil {
+		return err
+	}
+
+	jobs := make(chan int)
+	errs := make(chan error, 1)
+	var wg sync.WaitGroup
+
+	for range runtime.GOMAXPROCS(0) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := compressFile(&files[i], codec); err != nil {
+					select {
+					case errs <- err:
+					default:
+					}
+				}
+			}
+		}()
+	}
+
+	var err error
+	for i := range files {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		if len(errs) > 0 {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err != nil {
+		return err
+	}
+	select {
+	case err := <-errs:
+		return err
+	default:
+		return nil
+	}
+}
+
+// compressFile stores a single file's content with codec
+func compressFile(file *File, codec Codec) error {
+	if file.ContentRef != "" || FileCodec(*file) == codec {
+		return nil
+	}
+
+	content, err := DecompressWith(file.Content, FileCodec(*file))
+	if err != nil {
+		return fmt.Errorf("file %s failed to decompress: %w", file.Path, err)
+	}
+
+	compressed, isCompressed, err := CompressWith(content, codec)
+	if err != nil {
+		return fmt.Errorf("file %s failed to compress: %w", file.Path, err)
+	}
+
+	file.Content = compressed
+	file.Compressed = isCompressed
+	file.Codec = ""
+	if isCompressed {
+		file.Codec = string(codec)
+	}
+	return nil
+}
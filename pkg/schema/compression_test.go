@@ -0,0 +1,143 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCompressRoundTrip(t *testing.T) {
+	content := strings.Repeat("export const value = 42;\n", 100)
+
+	for _, codec := range Codecs {
+		t.Run(string(codec), func(t *testing.T) {
+			compressed, isCompressed, err := CompressWith(content, codec)
+			if err != nil {
+				t.Fatalf("CompressWith() error = %v", err)
+			}
+			if isCompressed != (codec != CodecNone) {
+				t.Errorf("CompressWith() compressed = %v", isCompressed)
+			}
+
+			file := File{Path: "a.ts", Content: compressed, Compressed: isCompressed, Codec: string(codec)}
+			decompressed, err := ResolveContent(&Schema{}, file)
+			if err != nil {
+				t.Fatalf("ResolveContent() error = %v", err)
+			}
+			if decompressed != content {
+				t.Error("round trip changed the content")
+			}
+		})
+	}
+}
+
+func TestCompressWithSmallContent(t *testing.T) {
+	compressed, isCompressed, err := CompressWith("tiny", CodecZstd)
+	if err != nil || isCompressed || compressed != "tiny" {
+		t.Errorf("CompressWith() = %q, %v, %v, want content unchanged", compressed, isCompressed, err)
+	}
+}
+
+func TestFileCodecLegacyGzip(t *testing.T) {
+	content := strings.Repeat("body { margin: 0; }\n", 100)
+	compressed, _, err := CompressContent(content)
+	if err != nil {
+		t.Fatalf("CompressContent() error = %v", err)
+	}
+
+	file := File{Path: "a.css", Content: compressed, Compressed: true}
+	if codec := FileCodec(file); codec != CodecGzip {
+		t.Errorf("FileCodec() = %q, want gzip for schemas without a codec", codec)
+	}
+	if got, err := ResolveContent(&Schema{}, file); err != nil || got != content {
+		t.Errorf("ResolveContent() error = %v", err)
+	}
+}
+
+func TestCompressFiles(t *testing.T) {
+	large := strings.Repeat("line of text\n", 200)
+	gzipped, _, err := CompressContent(large)
+	if err != nil {
+		t.Fatalf("CompressContent() error = %v", err)
+	}
+
+	files := []File{
+		{Path: "large.txt", Content: large},
+		{Path: "gzipped.txt", Content: gzipped, Compressed: true},
+		{Path: "small.txt", Content: "small"},
+	}
+
+	if err := CompressFiles(context.Background(), files, CodecZstd); err != nil {
+		t.Fatalf("CompressFiles() error = %v", err)
+	}
+
+	for _, file := range files[:2] {
+		if !file.Compressed || file.Codec != string(CodecZstd) {
+			t.Errorf("file %s = compressed %v codec %q, want zstd", file.Path, file.Compressed, file.Codec)
+		}
+		if got, err := ResolveContent(&Schema{}, file); err != nil || got != large {
+			t.Errorf("ResolveContent(%s) error = %v", file.Path, err)
+		}
+	}
+	if files[2].Compressed {
+		t.Error("files below the threshold must stay uncompressed")
+	}
+
+	if err := CompressFiles(context.Background(), files, CodecNone); err != nil {
+		t.Fatalf("CompressFiles(none) error = %v", err)
+	}
+	if files[0].Compressed || files[0].Content != large {
+		t.Error("codec none should store content inline")
+	}
+}
+
+func TestCompressFilesErrors(t *testing.T) {
+	if err := CompressFiles(context.Background(), nil, "brotli"); err == nil {
+		t.Error("CompressFiles() with an unknown codec should fail")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	files := []File{{Path: "a.txt", Content: strings.Repeat("a", 2048)}}
+	if err := CompressFiles(ctx, files, CodecGzip); !errors.Is(err, context.Canceled) {
+		t.Errorf("CompressFiles() error = %v, want context.Canceled", err)
+	}
+}
+
+func BenchmarkCompressWith(b *testing.B) {
+	content := strings.Repeat("export const value = 42;\n", 4000)
+
+	for _, codec := range Codecs {
+		b.Run(string(codec), func(b *testing.B) {
+			b.SetBytes(int64(len(content)))
+			for i := 0; i < b.N; i++ {
+				if _, _, err := CompressWith(content, codec); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkResolveContent(b *testing.B) {
+	content := strings.Repeat("export const value = 42;\n", 4000)
+
+	for _, codec := range Codecs {
+		b.Run(string(codec), func(b *testing.B) {
+			compressed, isCompressed, err := CompressWith(content, codec)
+			if err != nil {
+				b.Fatal(err)
+			}
+			file := File{Path: "a.ts", Content: compressed, Compressed: isCompressed, Codec: string(codec)}
+
+			b.SetBytes(int64(len(content)))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := ResolveContent(&Schema{}, file); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package schema
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// StoredContent returns the content as stored in the schema (possibly compressed),
+// following a ContentRef into the schema's blob table or the sidecar blobs of external files
+func StoredContent(schema *Schema, file File) (string, error) {
+	if file.External && file.Content == "" {
+		return externalContent(schema, file)
+	}
+	if file.ContentRef == "" {
+		return file.Content, nil
+	}
+
+	blob, exists := schema.Blobs[file.ContentRef]
+	if !exists {
+		return "", fmt.Errorf("file %s references missing blob %s", file.Path, file.ContentRef)
+	}
+	return blob, nil
+}
+
+// ResolveContent returns the original content of a file, resolving blob references, compression
+// and the base64 encoding of binary files
+func ResolveContent(schema *Schema, file File) (string, error) {
+	stored, err := StoredContent(schema, file)
+	if err != nil {
+		return "", err
+	}
+	content, err := DecompressWith(stored, FileCodec(file))
+	if err != nil {
+		return "", err
+	}
+	return decodeContent(content, file.Encoding)
+}
+
+// OpenContent returns a reader of the original content of a file, like ResolveContent but
+// reading sidecar blobs, decompressing and decoding as the content is read instead of all at
+// once
+func OpenContent(schema *Schema, file File) (io.ReadCloser, error) {
+	var stored io.ReadCloser
+	if file.External && file.Content == "" {
+		path, err := externalPath(schema, file)
+		if err != nil {
+			return nil, err
+		}
+		if stored, err = os.Open(path); err != nil {
+			return nil, fmt.Errorf("external file %s: %w", file.Path, err)
+		}
+	} else {
+		content, err := StoredContent(schema, file)
+		if err != nil {
+			return nil, err
+		}
+		stored = io.NopCloser(strings.NewReader(content))
+	}
+
+	reader, err := decompressReader(stored, FileCodec(file))
+	if err != nil {
+		stored.Close()
+		return nil, err
+	}
+	content := contentReader{Reader: reader, closers: []io.Closer{reader, stored}}
+	switch file.Encoding {
+	case "":
+		return content, nil
+	case EncodingBase64:
+		content.Reader = base64.NewDecoder(base64.StdEncoding, reader)
+		return content, nil
+	default:
+		content.Close()
+		return nil, fmt.Errorf("unknown content encoding %q", file.Encoding)
+	}
+}
+
+// contentReader reads the content of a file, closing the decompressor and the stored content
+// with it
+type contentReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r contentReader) Close() error {
+	var errs []error
+	for _, closer := range r.closers {
+		errs = append(errs, closer.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// BlobDir returns the directory holding the sidecar blobs of the external files of the schema
+// file at path: the path without its extension, suffixed with .blobs
+func BlobDir(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".blobs"
+}
+
+// externalPath returns the path of the sidecar blob of an external file
+func externalPath(schema *Schema, file File) (string, error) {
+	if _, err := hex.DecodeString(file.ContentRef); err != nil || file.ContentRef == "" {
+		return "", fmt.Errorf("external file %s has an invalid content_ref %q", file.Path, file.ContentRef)
+	}
+	if schema.path == "" {
+		return "", fmt.Errorf("external file %s is stored next to the schema file, load the schema from it",
+			file.Path)
+	}
+	return filepath.Join(BlobDir(schema.path), file.ContentRef), nil
+}
+
+// externalContent reads the sidecar blob of an external file
+func externalContent(schema *Schema, file File) (string, error) {
+	path, err := externalPath(schema, file)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("external file %s: %w", file.Path, err)
+	}
+	return string(data), nil
+}
+
+// InlineExternal reads the content of external files back into the schema, so it can be
+// published or parsed elsewhere as a single document
+func InlineExternal(schema *Schema) error {
+	for i := range schema.Files {
+		file := &schema.Files[i]
+		if !file.External {
+			continue
+		}
+
+		stored, err := StoredContent(schema, *file)
+		if err != nil {
+			return err
+		}
+		file.Content = stored
+		file.ContentRef = ""
+		file.External = false
+	}
+	return nil
+}
+
+// Dedupe stores identical file contents once in the schema blob table, keyed by
+// content hash, and returns the number of stored bytes saved
+func Dedupe(schema *Schema) int {
+	type candidate struct {
+		indexes  []int
+		codec    Codec
+		encoding string
+	}
+
+	groups := make(map[string]*candidate)
+	var order []string
+	for i, file := range schema.Files {
+		if file.Hash == "" || file.ContentRef != "" || file.IsSymlink() || file.External {
+			continue
+		}
+		group, exists := groups[file.Hash]
+		if !exists {
+			group = &candidate{codec: FileCodec(file), encoding: file.Encoding}
+			groups[file.Hash] = group
+			order = append(order, file.Hash)
+		}
+		// Only share blobs between files stored with the same encoding
+		if FileCodec(file) == group.codec && file.Encoding == group.encoding {
+			group.indexes = append(group.indexes, i)
+		}
+	}
+
+	saved := 0
+	for _, hash := range order {
+		group := groups[hash]
+		if len(group.indexes) < 2 {
+			continue
+		}
+
+		if schema.Blobs == nil {
+			schema.Blobs = make(map[string]string)
+		}
+		content := schema.Files[group.indexes[0]].Content
+		schema.Blobs[hash] = content
+
+		for _, index := range group.indexes {
+			schema.Files[index].ContentRef = hash
+			schema.Files[index].Content = ""
+		}
+		saved += len(content) * (len(group.indexes) - 1)
+	}
+
+	return saved
+}
@@ -0,0 +1,196 @@
+package schema
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDedupeSchema(t *testing.T) {
+	license := strings.Repeat("MIT License\n", 200)
+	compressed, ok, err := CompressContent(license)
+	if err != nil || !ok {
+		t.Fatalf("CompressContent() = %v, %v", ok, err)
+	}
+
+	schema := &Schema{
+		Name:      "test",
+		Type:      "go-api",
+		Version:   "1.0.0",
+		Variables: map[string]Variable{},
+		Files: []File{
+			{Path: "LICENSE", Content: license, Hash: ContentHash(license), Size: int64(len(license))},
+			{Path: "docs/LICENSE", Content: license, Hash: ContentHash(license), Size: int64(len(license))},
+			{Path: "pkg/LICENSE", Content: compressed, Compressed: true,
+				Hash: ContentHash(license), Size: int64(len(license))},
+			{Path: "main.go", Content: "package main", Hash: ContentHash("package main"), Size: 12},
+		},
+	}
+
+	saved := Dedupe(schema)
+	if saved != len(license) {
+		t.Errorf("Dedupe() saved = %d, want %d", saved, len(license))
+	}
+
+	hash := ContentHash(license)
+	if schema.Blobs[hash] != license {
+		t.Errorf("blob %s = %q, want %q", hash, schema.Blobs[hash], license)
+	}
+	for _, i := range []int{0, 1} {
+		if schema.Files[i].ContentRef != hash || schema.Files[i].Content != "" {
+			t.Errorf("file %s was not deduplicated: %+v", schema.Files[i].Path, schema.Files[i])
+		}
+	}
+	if schema.Files[2].ContentRef != "" || schema.Files[3].ContentRef != "" {
+		t.Error("files with a different encoding or unique content must keep their content")
+	}
+
+	if err := Validate(schema); err != nil {
+		t.Fatalf("Validate() after dedupe error = %v", err)
+	}
+
+	for _, file := range schema.Files[:3] {
+		content, err := ResolveContent(schema, file)
+		if err != nil {
+			t.Fatalf("ResolveContent(%s) error = %v", file.Path, err)
+		}
+		if content != license {
+			t.Errorf("ResolveContent(%s) = %q, want %q", file.Path, content, license)
+		}
+	}
+}
+
+func TestResolveContentMissingBlob(t *testing.T) {
+	schema := &Schema{}
+	file := File{Path: "LICENSE", ContentRef: "missing"}
+
+	if _, err := ResolveContent(schema, file); err == nil {
+		t.Error("ResolveContent() with a missing blob should fail")
+	}
+}
+
+func TestOpenContent(t *testing.T) {
+	text := strings.Repeat("streamed content\n", 300)
+	binary, encoding := EncodeContent([]byte{0x89, 'P', 'N', 'G', 0, 1, 2, 3})
+	raw, _ := decodeContent(binary, encoding)
+
+	for _, codec := range Codecs {
+		for _, tt := range []struct {
+			content, encoding, want string
+		}{
+			{text, "", text},
+			{binary, encoding, string(raw)},
+		} {
+			stored, ok, err := CompressWith(tt.content, codec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			file := File{Path: "file", Content: stored, Compressed: ok, Codec: string(codec), Encoding: tt.encoding}
+
+			reader, err := OpenContent(&Schema{}, file)
+			if err != nil {
+				t.Fatalf("OpenContent(%s) error = %v", codec, err)
+			}
+			data, err := io.ReadAll(reader)
+			if err != nil || string(data) != tt.want {
+				t.Errorf("OpenContent(%s, %q) read %d bytes, %v", codec, tt.encoding, len(data), err)
+			}
+			if err := reader.Close(); err != nil {
+				t.Errorf("Close() error = %v", err)
+			}
+		}
+	}
+
+	if _, err := OpenContent(&Schema{}, File{Path: "file", Encoding: "hex"}); err == nil {
+		t.Error("OpenContent() with an unknown encoding should fail")
+	}
+}
+
+func TestExternalFiles(t *testing.T) {
+	fixture := strings.Repeat("fixture data\n", 500)
+	compressed, _, err := CompressContent(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := &Schema{
+		Name:      "test",
+		Type:      "go-api",
+		Version:   "1.0.0",
+		Variables: map[string]Variable{},
+		Files: []File{
+			{Path: "main.go", Content: "package main", Hash: ContentHash("package main"), Size: 12},
+			{Path: "testdata/big.txt", Content: compressed, Compressed: true, Codec: string(CodecGzip),
+				Hash: ContentHash(fixture), Size: int64(len(fixture)), External: true},
+		},
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "template.json")
+	if err := Save(schema, path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if schema.Files[1].Content != compressed {
+		t.Error("Save() modified the schema")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), compressed) {
+		t.Error("external file content was inlined into the schema file")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "template.blobs", ContentHash(compressed))); err != nil {
+		t.Errorf("sidecar blob missing: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := Validate(loaded); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if content, err := ResolveContent(loaded, loaded.Files[1]); err != nil || content != fixture {
+		t.Errorf("ResolveContent() = %d bytes, %v, want the fixture", len(content), err)
+	}
+	reader, err := OpenContent(loaded, loaded.Files[1])
+	if err != nil {
+		t.Fatalf("OpenContent() error = %v", err)
+	}
+	if content, err := io.ReadAll(reader); err != nil || string(content) != fixture {
+		t.Errorf("OpenContent() read %d bytes, %v, want the fixture", len(content), err)
+	}
+	reader.Close()
+
+	// Saving elsewhere carries the blob along
+	copyPath := filepath.Join(t.TempDir(), "copy.json")
+	if err := Save(loaded, copyPath); err != nil {
+		t.Fatalf("Save() copy error = %v", err)
+	}
+	if copied, err := Load(copyPath); err != nil || Validate(copied) != nil {
+		t.Errorf("copied schema does not validate: %v", err)
+	}
+
+	parsed, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ResolveContent(parsed, parsed.Files[1]); err == nil {
+		t.Error("ResolveContent() of an external file without the schema file should fail")
+	}
+
+	if err := InlineExternal(loaded); err != nil {
+		t.Fatalf("InlineExternal() error = %v", err)
+	}
+	if file := loaded.Files[1]; file.External || file.ContentRef != "" || file.Content != compressed {
+		t.Errorf("InlineExternal() file = %+v", file)
+	}
+
+	loaded.Files[1] = File{Path: "evil", External: true, ContentRef: "../../etc/passwd"}
+	if _, err := StoredContent(loaded, loaded.Files[1]); err == nil {
+		t.Error("StoredContent() should refuse content refs that are not hashes")
+	}
+}
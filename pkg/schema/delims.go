@@ -0,0 +1,38 @@
+package schema
+
+import "fmt"
+
+// Default Go template delimiters
+const (
+	DefaultLeftDelim  = "{{"
+	DefaultRightDelim = "}}"
+)
+
+// EffectiveDelims returns the delimiters used to render a file: the file override,
+// then the schema default, then the Go template defaults
+func EffectiveDelims(schema *Schema, file File) (string, string) {
+	if file.Delims != nil {
+		return file.Delims.Left, file.Delims.Right
+	}
+	if schema != nil && schema.Delims != nil {
+		return schema.Delims.Left, schema.Delims.Right
+	}
+	return DefaultLeftDelim, DefaultRightDelim
+}
+
+// validateDelims validates a delimiter override if present
+func validateDelims(delims *Delims, owner string) error {
+	if delims == nil {
+		return nil
+	}
+
+	if delims.Left == "" || delims.Right == "" {
+		return fmt.Errorf("%s delimiters must define both left and right", owner)
+	}
+
+	if delims.Left == delims.Right {
+		return fmt.Errorf("%s delimiters must differ, got %q for both", owner, delims.Left)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"unicode/utf8"
+)
+
+// EncodingBase64 is the File.Encoding of binary files, whose content is base64 encoded so
+// it survives JSON
+const EncodingBase64 = "base64"
+
+// binarySniffLen is how much of a file is searched for NUL bytes, as git does
+const binarySniffLen = 8000
+
+// IsBinary reports whether content is not text: it holds a NUL byte or is not valid UTF-8
+func IsBinary(content []byte) bool {
+	sniff := content[:min(len(content), binarySniffLen)]
+	return bytes.IndexByte(sniff, 0) >= 0 || !utf8.Valid(content)
+}
+
+// EncodeContent returns raw as the content of a schema file with its encoding: text as is,
+// binary content (see IsBinary) base64 encoded with EncodingBase64
+func EncodeContent(raw []byte) (content, encoding string) {
+	if IsBinary(raw) {
+		return base64.StdEncoding.EncodeToString(raw), EncodingBase64
+	}
+	return string(raw), ""
+}
+
+// decodeContent reverses EncodeContent for a file with the given encoding
+func decodeContent(content, encoding string) (string, error) {
+	switch encoding {
+	case "":
+		return content, nil
+	case EncodingBase64:
+		raw, err := base64.StdEncoding.DecodeString(content)
+		return string(raw), err
+	default:
+		return "", fmt.Errorf("unknown content encoding %q", encoding)
+	}
+}
+
+// validateEncoding validates the encoding of a file: binary files are never templated
+func validateEncoding(file File) error {
+	switch file.Encoding {
+	case "":
+		return nil
+	case EncodingBase64:
+		if file.Template {
+			return fmt.Errorf("binary file %s cannot be templated", file.Path)
+		}
+		return nil
+	default:
+		return fmt.Errorf("file %s has unknown encoding %q", file.Path, file.Encoding)
+	}
+}
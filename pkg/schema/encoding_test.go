@@ -0,0 +1,70 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsBinary(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"text", "package main\n", false},
+		{"utf-8", "héllo wörld ✓", false},
+		{"empty", "", false},
+		{"nul byte", "GIF89a\x00\x01", true},
+		{"invalid utf-8", "\xff\xfe\xfd", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsBinary([]byte(tt.content)); got != tt.want {
+				t.Errorf("IsBinary(%q) = %v, want %v", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBinaryFileContent(t *testing.T) {
+	image := "\x89PNG\r\n\x1a\n" + strings.Repeat("\x00\xff", 1000)
+	encoded, encoding := EncodeContent([]byte(image))
+	if encoding != EncodingBase64 {
+		t.Fatalf("EncodeContent() encoding = %q, want base64", encoding)
+	}
+	stored, compressed, err := CompressContent(encoded)
+	if err != nil || !compressed {
+		t.Fatalf("CompressContent() = %v, %v", compressed, err)
+	}
+
+	schema := &Schema{
+		Name:      "test",
+		Type:      "frontend",
+		Version:   "1.0.0",
+		Variables: map[string]Variable{},
+		Files: []File{{
+			Path: "logo.png", Content: stored, Compressed: true, Codec: string(CodecGzip), Encoding: encoding,
+			Hash: ContentHash(image), Size: int64(len(image)),
+		}},
+	}
+	if err := Validate(schema); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if content, err := ResolveContent(schema, schema.Files[0]); err != nil || content != image {
+		t.Errorf("ResolveContent() = %d bytes, %v, want the raw image", len(content), err)
+	}
+
+	schema.Files[0].Template = true
+	if err := Validate(schema); err == nil || !strings.Contains(err.Error(), "cannot be templated") {
+		t.Errorf("Validate() templated binary error = %v", err)
+	}
+	schema.Files[0].Template = false
+	schema.Files[0].Encoding = "uuencode"
+	if err := Validate(schema); err == nil || !strings.Contains(err.Error(), "unknown encoding") {
+		t.Errorf("Validate() unknown encoding error = %v", err)
+	}
+
+	if content, encoding := EncodeContent([]byte("plain text")); content != "plain text" || encoding != "" {
+		t.Errorf("EncodeContent(text) = %q, %q", content, encoding)
+	}
+}
@@ -0,0 +1,53 @@
+package schema
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Feature is an optional part of a template, e.g. "auth", "postgres" or "ci", which generation
+// includes or leaves out (--enable / --disable): its files are only written and its hooks only
+// run while it is enabled.
+type Feature struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Default enables the feature unless it is disabled
+	Default bool `json:"default,omitempty"`
+	// Files are globs of the files belonging to the feature, or of directories holding them,
+	// e.g. "internal/auth" or "**/*_postgres.go"
+	Files []string `json:"files,omitempty"`
+	// Hooks are the names of the hooks belonging to the feature
+	Hooks []string `json:"hooks,omitempty"`
+}
+
+// validateFeatures validates that every feature has a unique name usable on the command line and
+// toggles existing hooks
+func validateFeatures(schema *Schema) error {
+	names := make(map[string]bool)
+	for i, feature := range schema.Features {
+		if feature.Name == "" || strings.ContainsAny(feature.Name, ", \t=") {
+			return fmt.Errorf("feature %d must have a name without spaces, commas or =, got %q", i, feature.Name)
+		}
+		if names[feature.Name] {
+			return fmt.Errorf("duplicate feature %s", feature.Name)
+		}
+		names[feature.Name] = true
+
+		if len(feature.Files) == 0 && len(feature.Hooks) == 0 {
+			return fmt.Errorf("feature %s toggles no files or hooks", feature.Name)
+		}
+		for _, pattern := range feature.Files {
+			if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+				return fmt.Errorf("feature %s has invalid file pattern %q", feature.Name, pattern)
+			}
+		}
+		for _, name := range feature.Hooks {
+			if !slices.ContainsFunc(schema.Hooks, func(hook Hook) bool { return hook.Name == name }) {
+				return fmt.Errorf("feature %s toggles unknown hook %s", feature.Name, name)
+			}
+		}
+	}
+	return nil
+}
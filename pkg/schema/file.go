@@ -0,0 +1,117 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// Load reads and parses a template schema JSON file. The content of its external files is read
+// from the sidecar blobs next to it when needed.
+func Load(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema file: %w", err)
+	}
+
+	schema, err := ParseFile(path, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schema file: %w", err)
+	}
+
+	return schema, nil
+}
+
+// ParseFile decodes the schema read from the file at path, whose external files are read from
+// the sidecar blobs next to it
+func ParseFile(path string, data []byte) (*Schema, error) {
+	schema, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	schema.path = path
+	return schema, nil
+}
+
+// Parse decodes a JSON schema and migrates it to the current schema format
+func Parse(data []byte) (*Schema, error) {
+	var schema Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+	if schema.SchemaVersion < 0 {
+		return nil, fmt.Errorf("invalid schema_version %d", schema.SchemaVersion)
+	}
+
+	Migrate(&schema)
+	return &schema, nil
+}
+
+// Save writes a template schema as indented JSON. The content of external files is written
+// to sidecar blobs in BlobDir(path) instead, named by the hash of their stored content.
+func Save(schema *Schema, path string) error {
+	return save(schema, path, true)
+}
+
+// SaveCompact writes a template schema like Save, as JSON without any whitespace
+func SaveCompact(schema *Schema, path string) error {
+	return save(schema, path, false)
+}
+
+// save writes a template schema as JSON, indented when indent is set
+func save(schema *Schema, path string, indent bool) error {
+	if slices.ContainsFunc(schema.Files, func(file File) bool { return file.External }) {
+		var err error
+		if schema, err = externalize(schema, path); err != nil {
+			return err
+		}
+	}
+
+	var data []byte
+	var err error
+	if indent {
+		data, err = json.MarshalIndent(schema, "", "  ")
+	} else {
+		data, err = json.Marshal(schema)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write schema file: %w", err)
+	}
+
+	return nil
+}
+
+// externalize writes the content of the external files of schema to the blob directory of the
+// schema file at path, and returns a copy of schema referencing the blobs
+func externalize(schema *Schema, path string) (*Schema, error) {
+	dir := BlobDir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	saved := schema.Clone()
+	for i := range saved.Files {
+		file := &saved.Files[i]
+		if !file.External {
+			continue
+		}
+
+		stored, err := StoredContent(schema, *file)
+		if err != nil {
+			return nil, err
+		}
+		ref := ContentHash(stored)
+		if err := os.WriteFile(filepath.Join(dir, ref), []byte(stored), 0o600); err != nil {
+			return nil, fmt.Errorf("failed to write external file %s: %w", file.Path, err)
+		}
+		file.Content = ""
+		file.ContentRef = ref
+	}
+	return saved, nil
+}
@@ -0,0 +1,43 @@
+package schema
+
+import (
+	"fmt"
+	"path"
+	"slices"
+)
+
+// GoModule declares a go.mod of the schema that generation rewrites structurally: its module
+// directive is set to Module and the imports of the old module path in every Go file follow,
+// however the reference project names its module.
+type GoModule struct {
+	// Path of the go.mod file in the schema, e.g. "go.mod" or "backend/go.mod"
+	Path string `json:"path"`
+	// Module path of the generated module, a template such as "github.com/{{.GitHubRepo}}"
+	Module string `json:"module"`
+	// Package optionally renames the package in the directory of the go.mod, a template such as
+	// "{{.ProjectNamePackage}}": its package clauses and doc comment, and the references to it
+	// in the Go files importing it, are rewritten syntactically rather than by string replace
+	Package string `json:"package,omitempty"`
+}
+
+// validateGoModules validates that every go module names a go.mod file of the schema
+func validateGoModules(schema *Schema) error {
+	seen := make(map[string]bool)
+	for _, module := range schema.GoModules {
+		if path.Base(module.Path) != "go.mod" {
+			return fmt.Errorf("go module %q must be a go.mod file", module.Path)
+		}
+		if module.Module == "" {
+			return fmt.Errorf("go module %s must have a module path", module.Path)
+		}
+		if seen[module.Path] {
+			return fmt.Errorf("duplicate go module %s", module.Path)
+		}
+		seen[module.Path] = true
+
+		if !slices.ContainsFunc(schema.Files, func(file File) bool { return file.Path == module.Path }) {
+			return fmt.Errorf("go module %s is not a file of the schema", module.Path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HookStage is the point of the project lifecycle at which a hook runs
+type HookStage string
+
+const (
+	// HookPreGenerate runs in the empty output directory before any file is written
+	HookPreGenerate HookStage = "pre_generate"
+	// HookPostGenerate runs once every file of a new project has been written
+	HookPostGenerate HookStage = "post_generate"
+	// HookVerify checks a generated project, e.g. with go build ./... or npm run typecheck. Verify
+	// hooks only run when asked for, after the post_generate hooks, and report instead of failing.
+	HookVerify HookStage = "verify"
+	// HookPostUpdate runs after template files were applied to an existing project
+	HookPostUpdate HookStage = "post_update"
+)
+
+// HookStages lists the stages in lifecycle order
+var HookStages = []HookStage{HookPreGenerate, HookPostGenerate, HookVerify, HookPostUpdate}
+
+// Hook conditions, written as "<kind>:<argument>"
+const (
+	ConditionExists  = "exists"  // exists:<path> runs when the project contains path
+	ConditionMissing = "missing" // missing:<path> runs when the project lacks path
+	ConditionCommand = "command" // command:<binary> runs when binary is on PATH
+)
+
+// Hook is a shell command run at a stage of generation. Template variables are passed to
+// the command as TE_VAR_<Name> environment variables.
+type Hook struct {
+	Name    string    `json:"name,omitempty"`
+	Stage   HookStage `json:"stage"`
+	Command string    `json:"command"`
+	// Workdir is the directory the command runs in, relative to the project root
+	Workdir string `json:"workdir,omitempty"`
+	// Env adds environment variables to the command
+	Env map[string]string `json:"env,omitempty"`
+	// Timeout limits the command, as a duration such as "5m"; no limit when empty
+	Timeout string `json:"timeout,omitempty"`
+	// Condition skips the hook unless it holds, e.g. "command:npm" or "exists:go.mod"
+	Condition string `json:"condition,omitempty"`
+}
+
+// Label identifies the hook in logs and errors: its name, or its command when unnamed
+func (h Hook) Label() string {
+	if h.Name != "" {
+		return h.Name
+	}
+	return h.Command
+}
+
+// TimeoutDuration returns the parsed Timeout, zero when unset
+func (h Hook) TimeoutDuration() (time.Duration, error) {
+	if h.Timeout == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(h.Timeout)
+}
+
+// ParseCondition splits a hook condition into its kind and argument
+func ParseCondition(condition string) (kind, argument string, err error) {
+	kind, argument, found := strings.Cut(condition, ":")
+	if !found || argument == "" {
+		return "", "", fmt.Errorf("invalid hook condition %q, expected kind:argument", condition)
+	}
+
+	switch kind {
+	case ConditionExists, ConditionMissing, ConditionCommand:
+		return kind, argument, nil
+	default:
+		return "", "", fmt.Errorf("unknown hook condition %q, expected %s, %s or %s",
+			kind, ConditionExists, ConditionMissing, ConditionCommand)
+	}
+}
+
+// Hooks is the ordered list of hooks of a schema. Schemas written before typed hooks stored a
+// map from stage to commands ({"post_generate": ["go mod tidy"]}), which is converted when read.
+type Hooks []Hook
+
+// UnmarshalJSON accepts both the hook list and the legacy stage map
+func (h *Hooks) UnmarshalJSON(data []byte) error {
+	var hooks []Hook
+	if err := json.Unmarshal(data, &hooks); err == nil {
+		*h = hooks
+		return nil
+	}
+
+	var legacy map[string][]string
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return fmt.Errorf("hooks must be a list of hooks or a map of stage to commands: %w", err)
+	}
+
+	stages := make([]string, 0, len(legacy))
+	for stage := range legacy {
+		stages = append(stages, stage)
+	}
+	sort.Slice(stages, func(i, j int) bool {
+		return stageOrder(HookStage(stages[i])) < stageOrder(HookStage(stages[j])) ||
+			stageOrder(HookStage(stages[i])) == stageOrder(HookStage(stages[j])) && stages[i] < stages[j]
+	})
+
+	*h = Hooks{}
+	for _, stage := range stages {
+		for _, command := range legacy[stage] {
+			*h = append(*h, Hook{Stage: HookStage(stage), Command: command})
+		}
+	}
+	return nil
+}
+
+// ForStage returns the hooks of a stage in schema order
+func (h Hooks) ForStage(stage HookStage) []Hook {
+	var hooks []Hook
+	for _, hook := range h {
+		if hook.Stage == stage {
+			hooks = append(hooks, hook)
+		}
+	}
+	return hooks
+}
+
+// stageOrder returns the lifecycle position of a stage, unknown stages last
+func stageOrder(stage HookStage) int {
+	for i, known := range HookStages {
+		if stage == known {
+			return i
+		}
+	}
+	return len(HookStages)
+}
+
+// validateHooks validates every hook definition
+func validateHooks(schema *Schema) error {
+	for i, hook := range schema.Hooks {
+		if stageOrder(hook.Stage) == len(HookStages) {
+			return fmt.Errorf("hook %d has unknown stage %q, expected one of %v", i, hook.Stage, HookStages)
+		}
+		if strings.TrimSpace(hook.Command) == "" {
+			return fmt.Errorf("hook %d must have a command", i)
+		}
+		if hook.Workdir != "" {
+			if workdir := path.Clean(hook.Workdir); !fs.ValidPath(workdir) {
+				return fmt.Errorf("hook %s workdir %q must be relative to the project root", hook.Label(), hook.Workdir)
+			}
+		}
+		if timeout, err := hook.TimeoutDuration(); err != nil || timeout < 0 {
+			return fmt.Errorf("hook %s has invalid timeout %q", hook.Label(), hook.Timeout)
+		}
+		if hook.Condition != "" {
+			if _, _, err := ParseCondition(hook.Condition); err != nil {
+				return fmt.Errorf("hook %s: %w", hook.Label(), err)
+			}
+		}
+	}
+
+	return nil
+}
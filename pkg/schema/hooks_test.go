@@ -0,0 +1,76 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestHooksUnmarshalLegacy(t *testing.T) {
+	data := `{"post_update": ["make lint"], "post_generate": ["go mod tidy", "go build"], "pre_generate": ["true"]}`
+
+	var hooks Hooks
+	if err := json.Unmarshal([]byte(data), &hooks); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	expected := Hooks{
+		{Stage: HookPreGenerate, Command: "true"},
+		{Stage: HookPostGenerate, Command: "go mod tidy"},
+		{Stage: HookPostGenerate, Command: "go build"},
+		{Stage: HookPostUpdate, Command: "make lint"},
+	}
+	if !reflect.DeepEqual(hooks, expected) {
+		t.Errorf("Unmarshal() = %+v, want %+v", hooks, expected)
+	}
+}
+
+func TestHooksRoundTrip(t *testing.T) {
+	hooks := Hooks{{
+		Name: "install", Stage: HookPostGenerate, Command: "npm install", Workdir: "frontend",
+		Env: map[string]string{"CI": "1"}, Timeout: "5m", Condition: "command:npm",
+	}}
+
+	data, err := json.Marshal(hooks)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var decoded Hooks
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded, hooks) {
+		t.Errorf("round trip = %+v, want %+v", decoded, hooks)
+	}
+	if got := decoded.ForStage(HookPreGenerate); len(got) != 0 {
+		t.Errorf("ForStage(pre_generate) = %+v, want none", got)
+	}
+}
+
+func TestValidateHooks(t *testing.T) {
+	tests := []struct {
+		name    string
+		hook    Hook
+		wantErr bool
+	}{
+		{"valid", Hook{Stage: HookPostGenerate, Command: "go mod tidy", Timeout: "2m", Condition: "exists:go.mod"}, false},
+		{"unknown stage", Hook{Stage: "post_install", Command: "true"}, true},
+		{"missing command", Hook{Stage: HookPreGenerate, Command: " "}, true},
+		{"invalid timeout", Hook{Stage: HookPostGenerate, Command: "true", Timeout: "soon"}, true},
+		{"negative timeout", Hook{Stage: HookPostGenerate, Command: "true", Timeout: "-1s"}, true},
+		{"unknown condition", Hook{Stage: HookPostGenerate, Command: "true", Condition: "os:linux"}, true},
+		{"condition without argument", Hook{Stage: HookPostGenerate, Command: "true", Condition: "command:"}, true},
+		{"absolute workdir", Hook{Stage: HookPostGenerate, Command: "true", Workdir: "/tmp"}, true},
+		{"escaping workdir", Hook{Stage: HookPostGenerate, Command: "true", Workdir: "../other"}, true},
+		{"nested workdir", Hook{Stage: HookPostUpdate, Command: "true", Workdir: "web/app/"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := &Schema{Hooks: Hooks{tt.hook}}
+			if err := validateHooks(schema); (err != nil) != tt.wantErr {
+				t.Errorf("validateHooks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ApplyMappings applies each mapping to content in order. Literal mappings replace every
+// occurrence of Find; regex mappings replace every match and may reference capture groups
+// in Replace ($1, ${name}).
+func ApplyMappings(content string, mappings []Mapping) (string, error) {
+	content, _, err := ApplyMappingsVerified(content, mappings)
+	return content, err
+}
+
+// ApplyMappingsVerified applies mappings like ApplyMappings and also returns the required
+// mappings whose Find string was not in the content by the time they were applied
+func ApplyMappingsVerified(content string, mappings []Mapping) (string, []Mapping, error) {
+	var missing []Mapping
+	for _, mapping := range mappings {
+		if !mapping.Regex {
+			if mapping.Required && !strings.Contains(content, mapping.Find) {
+				missing = append(missing, mapping)
+			}
+			content = strings.ReplaceAll(content, mapping.Find, mapping.Replace)
+			continue
+		}
+
+		re, err := regexp.Compile(mapping.Find)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid regex mapping %q: %w", mapping.Find, err)
+		}
+		if mapping.Required && !re.MatchString(content) {
+			missing = append(missing, mapping)
+		}
+		content = re.ReplaceAllString(content, mapping.Replace)
+	}
+
+	return content, missing, nil
+}
+
+// MappingMatches reports whether mapping would change anything in content
+func MappingMatches(content string, mapping Mapping) bool {
+	if !mapping.Regex {
+		return strings.Contains(content, mapping.Find)
+	}
+
+	re, err := regexp.Compile(mapping.Find)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(content)
+}
+
+// validateMappings validates the mappings of a file
+func validateMappings(file File) error {
+	for _, mapping := range file.Mappings {
+		if mapping.Find == "" {
+			return fmt.Errorf("file %s has a mapping with an empty find string", file.Path)
+		}
+
+		if mapping.Regex {
+			if _, err := regexp.Compile(mapping.Find); err != nil {
+				return fmt.Errorf("file %s has an invalid regex mapping %q: %w", file.Path, mapping.Find, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package schema
+
+import "testing"
+
+func TestApplyMappings(t *testing.T) {
+	content := `import "github.com/acheevo/api-template/internal/user"
+version: 1.2.3`
+
+	got, err := ApplyMappings(content, []Mapping{
+		{Find: `"github.com/[^/]+/api-template/(\w+)/`, Replace: `"github.com/{{.GitHubRepo}}/$1/`, Regex: true},
+		{Find: `version: (?P<major>\d+)\.\d+\.\d+`, Replace: "version: ${major}.0.0", Regex: true},
+		{Find: "user", Replace: "account"},
+	})
+	if err != nil {
+		t.Fatalf("ApplyMappings() error = %v", err)
+	}
+
+	expected := `import "github.com/{{.GitHubRepo}}/internal/account"
+version: 1.0.0`
+	if got != expected {
+		t.Errorf("ApplyMappings() mismatch.\nExpected: %q\nGot: %q", expected, got)
+	}
+}
+
+func TestApplyMappingsInvalidRegex(t *testing.T) {
+	if _, err := ApplyMappings("content", []Mapping{{Find: "(", Regex: true}}); err == nil {
+		t.Error("Expected error for invalid regex")
+	}
+}
+
+func TestApplyMappingsVerified(t *testing.T) {
+	got, missing, err := ApplyMappingsVerified("name: acme-api", []Mapping{
+		{Find: "acme-api", Replace: "{{.ProjectName}}", Required: true},
+		{Find: "acme", Replace: "{{.Owner}}", Required: true}, // Replaced away by the mapping before
+		{Find: `port: \d+`, Replace: "port: 80", Regex: true, Required: true},
+		{Find: "version", Replace: "v"},
+	})
+	if err != nil {
+		t.Fatalf("ApplyMappingsVerified() error = %v", err)
+	}
+	if got != "name: {{.ProjectName}}" {
+		t.Errorf("ApplyMappingsVerified() = %q", got)
+	}
+	if len(missing) != 2 || missing[0].Find != "acme" || missing[1].Find != `port: \d+` {
+		t.Errorf("missing = %+v, want the required acme and port mappings", missing)
+	}
+}
+
+func TestMappingMatches(t *testing.T) {
+	if !MappingMatches("v1.2.3", Mapping{Find: `v\d+`, Regex: true}) {
+		t.Error("Expected regex mapping to match")
+	}
+	if MappingMatches("v1.2.3", Mapping{Find: `v\d+`}) {
+		t.Error("Expected literal mapping not to match")
+	}
+}
+
+func TestValidateRejectsInvalidRegexMapping(t *testing.T) {
+	schema := &Schema{
+		Name:      "test",
+		Type:      "go-api",
+		Version:   "1.0.0",
+		Variables: map[string]Variable{},
+		Files: []File{{
+			Path:     "main.go",
+			Template: true,
+			Content:  "package main",
+			Mappings: []Mapping{{Find: "[", Replace: "x", Regex: true}},
+		}},
+	}
+
+	if err := Validate(schema); err == nil {
+		t.Error("Expected Validate to reject an invalid regex mapping")
+	}
+}
@@ -0,0 +1,65 @@
+package schema
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Normalize strips the fields of a schema that have no effect: codecs of uncompressed files,
+// mappings and delimiters of files that are not templated, delimiters equal to the ones
+// already in effect and blobs no file references. It returns what was stripped, one entry per
+// change. Hashes are left alone, see FixHashes.
+func Normalize(schema *Schema) []string {
+	stripped := []string{}
+
+	if schema.Delims != nil && schema.Delims.Left == DefaultLeftDelim && schema.Delims.Right == DefaultRightDelim {
+		schema.Delims = nil
+		stripped = append(stripped, "schema delimiters equal to the defaults")
+	}
+
+	referenced := make(map[string]bool)
+	for i := range schema.Files {
+		file := &schema.Files[i]
+		if file.ContentRef != "" && !file.External {
+			referenced[file.ContentRef] = true
+		}
+
+		if !file.Compressed && file.Codec != "" {
+			file.Codec = ""
+			stripped = append(stripped, fmt.Sprintf("%s: codec of uncompressed content", file.Path))
+		}
+
+		if !file.Template {
+			if len(file.Mappings) > 0 {
+				file.Mappings = nil
+				stripped = append(stripped, fmt.Sprintf("%s: mappings of a file that is not templated", file.Path))
+			}
+			if file.Delims != nil {
+				file.Delims = nil
+				stripped = append(stripped, fmt.Sprintf("%s: delimiters of a file that is not templated", file.Path))
+			}
+			continue
+		}
+
+		if file.Delims != nil {
+			left, right := EffectiveDelims(schema, File{})
+			if file.Delims.Left == left && file.Delims.Right == right {
+				file.Delims = nil
+				stripped = append(stripped, fmt.Sprintf("%s: delimiters equal to the schema ones", file.Path))
+			}
+		}
+	}
+
+	for _, hash := range slices.Sorted(maps.Keys(schema.Blobs)) {
+		if !referenced[hash] {
+			delete(schema.Blobs, hash)
+			stripped = append(stripped, fmt.Sprintf("blob %s referenced by no file", hash))
+		}
+	}
+	if len(schema.Blobs) == 0 {
+		schema.Blobs = nil
+	}
+
+	return stripped
+}
@@ -0,0 +1,79 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	readme := "# service"
+	schema := &Schema{
+		Name:      "test",
+		Type:      "go-api",
+		Version:   "1.0.0",
+		Variables: map[string]Variable{},
+		Delims:    &Delims{Left: "{{", Right: "}}"},
+		Blobs:     map[string]string{"unused": "stale"},
+		Files: []File{
+			{Path: "README.md", Content: readme, Codec: "gzip",
+				Mappings: []Mapping{{Find: "service", Replace: "{{.ProjectName}}"}},
+				Delims:   &Delims{Left: "[[", Right: "]]"}},
+			{Path: "main.go", Template: true, Content: "package main", Delims: &Delims{Left: "{{", Right: "}}"}},
+			{Path: "Makefile", Template: true, Content: "all:", Delims: &Delims{Left: "[[", Right: "]]"}},
+		},
+	}
+
+	stripped := Normalize(schema)
+	if len(stripped) != 6 {
+		t.Errorf("Normalize() stripped = %v, want 6 entries", stripped)
+	}
+	if schema.Delims != nil || schema.Blobs != nil {
+		t.Errorf("schema delims = %v, blobs = %v, want them stripped", schema.Delims, schema.Blobs)
+	}
+	readmeFile := schema.Files[0]
+	if readmeFile.Codec != "" || readmeFile.Mappings != nil || readmeFile.Delims != nil {
+		t.Errorf("README.md = %+v, want codec, mappings and delims stripped", readmeFile)
+	}
+	if schema.Files[1].Delims != nil {
+		t.Error("main.go default delimiters were not stripped")
+	}
+	if schema.Files[2].Delims == nil {
+		t.Error("Makefile custom delimiters were stripped")
+	}
+
+	if again := Normalize(schema); len(again) != 0 {
+		t.Errorf("Normalize() of a normalized schema stripped %v", again)
+	}
+}
+
+func TestSaveCompact(t *testing.T) {
+	schema := &Schema{
+		Name:      "test",
+		Type:      "go-api",
+		Version:   "1.0.0",
+		Variables: map[string]Variable{},
+		Files:     []File{{Path: "main.go", Content: "package main"}},
+	}
+	path := filepath.Join(t.TempDir(), "schema.json")
+
+	if err := SaveCompact(schema, path); err != nil {
+		t.Fatalf("SaveCompact() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "\n") || strings.Contains(string(data), `": `) {
+		t.Errorf("SaveCompact() wrote whitespace: %s", data)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded.Files[0].Content != "package main" {
+		t.Errorf("loaded content = %q", loaded.Files[0].Content)
+	}
+}
@@ -0,0 +1,107 @@
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Patch operations, see Patch.Op
+const (
+	// PatchSet sets the value at the path, creating the objects leading to it
+	PatchSet = "set"
+	// PatchRemove removes the value at the path, if any
+	PatchRemove = "remove"
+	// PatchRename renames the key at the path to Value, e.g. a docker-compose service
+	PatchRename = "rename"
+)
+
+// PatchWildcard is a path key matching every key of an object or element of an array, e.g.
+// "services.*.restart"
+const PatchWildcard = "*"
+
+// Patch edits a value of a structured file during generation, after the file is rendered, so
+// the edit survives formatting differences in the reference project that break string mappings
+type Patch struct {
+	// Op is PatchSet (the default), PatchRemove or PatchRename
+	Op string `json:"op,omitempty"`
+	// Path to the value: keys separated by dots and array indexes as numbers, e.g. "name",
+	// "repository.url" or "keywords.0", PatchWildcard matching every key or element. A dot
+	// within a key is escaped as "\.".
+	Path string `json:"path"`
+	// Value set by PatchSet, any JSON value, or the new key of PatchRename; its strings are
+	// templates rendered with the template variables, written with {{ }} whatever the delimiters
+	Value any `json:"value,omitempty"`
+}
+
+// Operation returns the operation of the patch, PatchSet when empty
+func (p Patch) Operation() string {
+	if p.Op == "" {
+		return PatchSet
+	}
+	return p.Op
+}
+
+// SplitPatchPath splits a patch path into its keys, unescaping dots within keys
+func SplitPatchPath(path string) []string {
+	var keys []string
+	var key strings.Builder
+	for i := 0; i < len(path); i++ {
+		switch {
+		case path[i] == '\\' && i+1 < len(path) && path[i+1] == '.':
+			key.WriteByte('.')
+			i++
+		case path[i] == '.':
+			keys = append(keys, key.String())
+			key.Reset()
+		default:
+			key.WriteByte(path[i])
+		}
+	}
+	return append(keys, key.String())
+}
+
+// IsPatched reports whether the file has structured patches
+func (f File) IsPatched() bool {
+	return len(f.JSONPatch) > 0 || len(f.YAMLPatch) > 0
+}
+
+// validatePatches validates the structured patches of a file
+func validatePatches(file File) error {
+	if file.IsPatched() && file.Encoding != "" {
+		return fmt.Errorf("file %s is binary and cannot be patched", file.Path)
+	}
+	if len(file.JSONPatch) > 0 && len(file.YAMLPatch) > 0 {
+		return fmt.Errorf("file %s cannot have both json_patch and yaml_patch", file.Path)
+	}
+
+	for _, patch := range append(slices.Clone(file.JSONPatch), file.YAMLPatch...) {
+		if err := validatePatch(patch); err != nil {
+			return fmt.Errorf("file %s has an invalid patch of %q: %w", file.Path, patch.Path, err)
+		}
+	}
+	return nil
+}
+
+// validatePatch validates a single patch
+func validatePatch(patch Patch) error {
+	keys := SplitPatchPath(patch.Path)
+	if slices.Contains(keys, "") {
+		return fmt.Errorf("empty key in path")
+	}
+
+	switch patch.Operation() {
+	case PatchSet, PatchRemove:
+		return nil
+	case PatchRename:
+		if name, ok := patch.Value.(string); !ok || name == "" {
+			return fmt.Errorf("rename needs the new key as value")
+		}
+		if keys[len(keys)-1] == PatchWildcard {
+			return fmt.Errorf("rename needs a key, not %s", PatchWildcard)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown op %q, expected %s, %s or %s", patch.Op, PatchSet, PatchRemove, PatchRename)
+	}
+}
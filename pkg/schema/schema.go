@@ -0,0 +1,216 @@
+// Package schema defines template schemas, the JSON documents the template engine generates
+// projects from, with the helpers to build, validate, compress and transform them in code.
+// A schema embeds the full content of every file of a reference project, optionally
+// compressed, along with the mappings and variables that turn it into a template.
+package schema
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// BuiltinVariables are always available to templates, whether or not the schema declares them
+var BuiltinVariables = []string{"ProjectName", "GitHubRepo", "Author", "Description"}
+
+// Schema represents the complete template configuration
+type Schema struct {
+	// Format version of the schema itself (see CurrentVersion), 0 for schemas predating it
+	SchemaVersion int `json:"schema_version,omitempty"`
+	// Oldest engine able to generate from this schema, as a semantic version
+	MinEngineVersion string `json:"min_engine_version,omitempty"`
+
+	Name        string              `json:"name"`
+	Type        string              `json:"type"`
+	Version     string              `json:"version"`
+	Description string              `json:"description"`
+	Variables   map[string]Variable `json:"variables"`
+	Files       []File              `json:"files"`
+	Hooks       Hooks               `json:"hooks,omitempty"`
+	Hash        string              `json:"hash,omitempty"`
+	// Environment variables documented by every .env.example of the reference project.
+	// Always present (possibly empty) in extracted schemas.
+	EnvConfig []EnvVariable `json:"env_config"`
+	// Template functions the schema relies on, so engines lacking one fail clearly
+	RequiredFuncs []string `json:"required_funcs,omitempty"`
+	// Default delimiters for templated files, overridable per file
+	Delims *Delims `json:"delims,omitempty"`
+	// Content shared by several files, keyed by content hash (see File.ContentRef)
+	Blobs map[string]string `json:"blobs,omitempty"`
+	// Copyright header injected at the top of source files when generating with a license, a
+	// template rendered with the template variables plus License and Year
+	Header string `json:"header,omitempty"`
+	// go.mod files whose module path generation rewrites, with the imports of their Go files
+	GoModules []GoModule `json:"go_modules,omitempty"`
+	// Packages of a monorepo workspace, which generation can select a subset of
+	Packages []WorkspacePackage `json:"packages,omitempty"`
+	// Optional features generation can enable or disable, e.g. "auth" or "postgres"
+	Features []Feature `json:"features,omitempty"`
+	// Sample variable sets the template is tested with by `template-engine test`
+	TestMatrix []TestCase `json:"test_matrix,omitempty"`
+	// Commands run in every generated test project, e.g. "go build ./..." or "npm run build"
+	TestCommands []string `json:"test_commands,omitempty"`
+	// Free-form keywords to search schemas by, e.g. "react" or "postgres"
+	Tags []string `json:"tags,omitempty"`
+	// Broad kind of project, e.g. "backend", "frontend" or "fullstack"
+	Category string `json:"category,omitempty"`
+	// Origin records where a pulled schema was downloaded from (registry URL or OCI reference).
+	// Hooks of such remote schemas only run when explicitly allowed.
+	Origin string `json:"origin,omitempty"`
+	// Deprecated schemas still generate but warn, pointing to ReplacedBy when set
+	Deprecated bool `json:"deprecated,omitempty"`
+	// Successor of a deprecated schema: a schema name or template type
+	ReplacedBy string `json:"replaced_by,omitempty"`
+
+	// path is the file the schema was loaded from, holding its externalized contents nearby
+	path string
+}
+
+// DeprecationWarning describes the deprecation of the schema, empty when it is not deprecated
+func (s *Schema) DeprecationWarning() string {
+	if !s.Deprecated {
+		return ""
+	}
+	if s.ReplacedBy == "" {
+		return fmt.Sprintf("template schema %s is deprecated", s.Name)
+	}
+	return fmt.Sprintf("template schema %s is deprecated, use %s instead", s.Name, s.ReplacedBy)
+}
+
+// IsRemote reports whether the schema was downloaded from a registry
+func (s *Schema) IsRemote() bool {
+	return s.Origin != ""
+}
+
+// Clone returns a deep copy of the schema, so it can be modified without affecting s
+func (s *Schema) Clone() *Schema {
+	clone := *s
+	clone.Variables = maps.Clone(s.Variables)
+	clone.EnvConfig = slices.Clone(s.EnvConfig)
+	clone.RequiredFuncs = slices.Clone(s.RequiredFuncs)
+	clone.Delims = cloneDelims(s.Delims)
+	clone.Blobs = maps.Clone(s.Blobs)
+	clone.TestCommands = slices.Clone(s.TestCommands)
+	clone.Tags = slices.Clone(s.Tags)
+	clone.GoModules = slices.Clone(s.GoModules)
+	clone.Packages = slices.Clone(s.Packages)
+
+	clone.Files = slices.Clone(s.Files)
+	for i := range clone.Files {
+		clone.Files[i].Mappings = slices.Clone(clone.Files[i].Mappings)
+		clone.Files[i].Delims = cloneDelims(clone.Files[i].Delims)
+		clone.Files[i].JSONPatch = slices.Clone(clone.Files[i].JSONPatch)
+		clone.Files[i].YAMLPatch = slices.Clone(clone.Files[i].YAMLPatch)
+	}
+
+	clone.Hooks = slices.Clone(s.Hooks)
+	for i := range clone.Hooks {
+		clone.Hooks[i].Env = maps.Clone(clone.Hooks[i].Env)
+	}
+
+	clone.TestMatrix = slices.Clone(s.TestMatrix)
+	for i := range clone.TestMatrix {
+		clone.TestMatrix[i].Variables = maps.Clone(clone.TestMatrix[i].Variables)
+		clone.TestMatrix[i].Commands = slices.Clone(clone.TestMatrix[i].Commands)
+	}
+
+	return &clone
+}
+
+// cloneDelims copies optional delimiters
+func cloneDelims(delims *Delims) *Delims {
+	if delims == nil {
+		return nil
+	}
+	clone := *delims
+	return &clone
+}
+
+// TestCase is one entry of a schema's test matrix
+type TestCase struct {
+	Name string `json:"name"`
+	// Values for the built-in variables (ProjectName, GitHubRepo, Author, Description)
+	Variables map[string]string `json:"variables"`
+	// Commands replace the schema's TestCommands for this case when set
+	Commands []string `json:"commands,omitempty"`
+}
+
+// Delims overrides the template action delimiters for files whose content already
+// uses {{ }} natively (Vue, Angular, Helm charts)
+type Delims struct {
+	Left  string `json:"left"`
+	Right string `json:"right"`
+}
+
+// Variable represents a template variable definition. Its type is free-form (string, bool,
+// ...) but for the collection types VariableTypeList and VariableTypeMap.
+type Variable struct {
+	Type        string `json:"type"`
+	Required    bool   `json:"required"`
+	Default     string `json:"default,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// EnvVariable represents an environment variable from .env.example
+type EnvVariable struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Example     string `json:"example,omitempty"`
+	// Secret values (passwords, keys) must not be copied from the example into generated env files
+	Secret bool `json:"secret,omitempty"`
+	// Required variables must have a non-empty value in generated env files
+	Required bool `json:"required,omitempty"`
+	// Env file declaring the variable, relative to the project root (e.g. frontend/.env.example)
+	Source string `json:"source,omitempty"`
+	// Component of a multi-component project the variable belongs to (e.g. api or frontend),
+	// empty for single-component projects
+	Component string `json:"component,omitempty"`
+}
+
+// Key identifies the variable across components: "component.NAME", or NAME without a component
+func (v EnvVariable) Key() string {
+	if v.Component == "" {
+		return v.Name
+	}
+	return v.Component + "." + v.Name
+}
+
+// File represents a file in the template (go-fsck pattern: all content embedded)
+type File struct {
+	Path       string    `json:"path"`
+	Template   bool      `json:"template"`
+	Content    string    `json:"content"`              // Always includes full content
+	Size       int64     `json:"size"`                 // Original file size
+	Hash       string    `json:"hash,omitempty"`       // Content hash for validation
+	Compressed bool      `json:"compressed,omitempty"` // If content is compressed
+	Codec      string    `json:"codec,omitempty"`      // Compression codec, gzip when empty
+	Mappings   []Mapping `json:"mappings,omitempty"`
+	Delims     *Delims   `json:"delims,omitempty"`      // Overrides the schema delimiters
+	ContentRef string    `json:"content_ref,omitempty"` // Blob holding the content when deduplicated
+	// Type is FileTypeSymlink for symbolic links, whose Target is recorded instead of content;
+	// regular files leave it empty
+	Type   string `json:"type,omitempty"`
+	Target string `json:"target,omitempty"` // Link target, relative to the directory of the link
+	// Encoding is EncodingBase64 for binary files, empty for text
+	Encoding string `json:"encoding,omitempty"`
+	// External files are stored in a sidecar blob named ContentRef in the BlobDir of the schema
+	// file instead of the schema itself, for large files. Content is only set until saved.
+	External bool `json:"external,omitempty"`
+	// JSONPatch edits the file as JSON during generation, e.g. the name of a package.json
+	JSONPatch []Patch `json:"json_patch,omitempty"`
+	// YAMLPatch edits the file as YAML during generation, e.g. the services of a docker-compose.yml
+	YAMLPatch []Patch `json:"yaml_patch,omitempty"`
+	// ForEach names a list variable the file is generated once per item of, its path and content
+	// seeing the item as {{.item}}, e.g. internal/{{.item}}/handler.go
+	ForEach string `json:"for_each,omitempty"`
+}
+
+// Mapping represents a string replacement mapping
+type Mapping struct {
+	Find    string `json:"find"`
+	Replace string `json:"replace"`
+	Regex   bool   `json:"regex,omitempty"` // Find is a regular expression, Replace may use $1 / ${name}
+	// Required fails generation when Find is not in the file, catching templates whose reference
+	// project changed under them
+	Required bool `json:"required,omitempty"`
+}
@@ -0,0 +1,226 @@
+package schema
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// FileTypeSymlink is the File.Type of symbolic links
+const FileTypeSymlink = "symlink"
+
+// maxSymlinkDepth bounds the number of links followed to resolve a path, as the kernel does
+const maxSymlinkDepth = 40
+
+// IsSymlink reports whether the file is a symbolic link
+func (f File) IsSymlink() bool {
+	return f.Type == FileTypeSymlink
+}
+
+// SymlinkTarget returns the project path the symlink at linkPath points to, and whether it
+// stays inside the project. Absolute targets are never inside the project. The target is
+// resolved lexically: a schema's other symlinks may lead it elsewhere (see validateSymlink).
+func SymlinkTarget(linkPath, target string) (string, bool) {
+	if target == "" || path.IsAbs(target) || strings.HasPrefix(target, "\\") {
+		return "", false
+	}
+	resolved := path.Join(path.Dir(linkPath), target)
+	if resolved == ".." || strings.HasPrefix(resolved, "../") {
+		return "", false
+	}
+	return resolved, true
+}
+
+// validateSymlink validates a symbolic link: it has a target inside the project, no content,
+// and no file of the schema lives below it
+func validateSymlink(schema *Schema, file File) error {
+	if file.Type != FileTypeSymlink {
+		return fmt.Errorf("file %s has unknown type %q", file.Path, file.Type)
+	}
+	if file.Target == "" {
+		return fmt.Errorf("symlink %s must have a target", file.Path)
+	}
+	// Follow the schema's other symlinks along the target, as d/l -> .. makes d/l/.. the parent
+	// of the project rather than d
+	if _, inside, err := newSymlinkResolver(schema.Files).target(file.Path, file.Target, 0); err != nil {
+		return err
+	} else if !inside {
+		return fmt.Errorf("symlink %s points outside the project: %s", file.Path, file.Target)
+	}
+	if file.Content != "" || file.ContentRef != "" || file.Template || file.IsPatched() {
+		return fmt.Errorf("symlink %s cannot have content, be templated or patched", file.Path)
+	}
+
+	prefix := file.Path + "/"
+	for _, other := range schema.Files {
+		if strings.HasPrefix(other.Path, prefix) {
+			return fmt.Errorf("file %s is below symlink %s", other.Path, file.Path)
+		}
+	}
+	return nil
+}
+
+// hashedContent returns what the hash of a file covers: the content of regular files and the
+// target of symlinks
+func hashedContent(schema *Schema, file File) (string, error) {
+	if file.IsSymlink() {
+		return file.Target, nil
+	}
+	return ResolveContent(schema, file)
+}
+
+// MaterializeSymlinks returns files with every symlink replaced by copies of what it points to:
+// a link to a file becomes a copy of that file, a link to a directory copies of every file
+// below it. Links pointing to nothing in the schema are an error.
+func MaterializeSymlinks(files []File) ([]File, error) {
+	resolver := newSymlinkResolver(files)
+	for link, target := range resolver.targets {
+		resolved, inside, err := resolver.target(link, target, 0)
+		if err != nil {
+			return nil, err
+		}
+		if !inside {
+			return nil, fmt.Errorf("symlink %s points outside the project: %s", link, target)
+		}
+		resolver.links[link] = resolved
+	}
+
+	materialized := make([]File, 0, len(files))
+	for _, file := range files {
+		if !file.IsSymlink() {
+			materialized = append(materialized, file)
+			continue
+		}
+		copies, err := resolver.materialize(file.Path, file.Path, 0)
+		if err != nil {
+			return nil, err
+		}
+		materialized = append(materialized, copies...)
+	}
+	return materialized, nil
+}
+
+// symlinkResolver follows the symlinks of a schema
+type symlinkResolver struct {
+	paths   []string
+	files   map[string]File   // Regular files by path
+	targets map[string]string // Targets of the symlinks as written, by link path
+	links   map[string]string // Project paths the symlinks point to, by link path
+}
+
+// newSymlinkResolver returns a resolver of the symlinks among files, whose links are left to
+// fill with the resolved targets
+func newSymlinkResolver(files []File) symlinkResolver {
+	resolver := symlinkResolver{
+		files:   make(map[string]File),
+		targets: make(map[string]string),
+		links:   make(map[string]string),
+	}
+	for _, file := range files {
+		resolver.paths = append(resolver.paths, file.Path)
+		if file.IsSymlink() {
+			resolver.targets[file.Path] = file.Target
+		} else {
+			resolver.files[file.Path] = file
+		}
+	}
+	return resolver
+}
+
+// target returns the project path the symlink at linkPath points to, and whether it stays
+// inside the project, walking target one name at a time like the filesystem: a symlink met
+// along the way is followed before any ".." after it applies
+func (r symlinkResolver) target(linkPath, target string, depth int) (string, bool, error) {
+	if depth > maxSymlinkDepth {
+		return "", false, fmt.Errorf("too many levels of symlinks resolving %s", linkPath)
+	}
+	if _, inside := SymlinkTarget(linkPath, target); !inside {
+		return "", false, nil
+	}
+
+	// Files cannot live below symlinks, so the directory of the link is a real one
+	var resolved []string
+	if dir := path.Dir(linkPath); dir != "." {
+		resolved = strings.Split(dir, "/")
+	}
+	for _, name := range strings.Split(target, "/") {
+		switch name {
+		case "", ".":
+			continue
+		case "..":
+			if len(resolved) == 0 {
+				return "", false, nil
+			}
+			resolved = resolved[:len(resolved)-1]
+			continue
+		}
+
+		resolved = append(resolved, name)
+		current := strings.Join(resolved, "/")
+		next, isLink := r.targets[current]
+		if !isLink {
+			continue
+		}
+		followed, inside, err := r.target(current, next, depth+1)
+		if err != nil || !inside {
+			return "", false, err
+		}
+		resolved = nil
+		if followed != "." {
+			resolved = strings.Split(followed, "/")
+		}
+	}
+	if len(resolved) == 0 {
+		return ".", true, nil
+	}
+	return strings.Join(resolved, "/"), true, nil
+}
+
+// resolve returns the path p designates once every symlink along it is followed
+func (r symlinkResolver) resolve(p string, depth int) (string, error) {
+	if depth > maxSymlinkDepth {
+		return "", fmt.Errorf("too many levels of symlinks resolving %s", p)
+	}
+
+	parts := strings.Split(p, "/")
+	for i := range parts {
+		target, isLink := r.links[strings.Join(parts[:i+1], "/")]
+		if isLink {
+			return r.resolve(path.Join(append([]string{target}, parts[i+1:]...)...), depth+1)
+		}
+	}
+	return p, nil
+}
+
+// materialize returns copies at dest of the regular files p designates
+func (r symlinkResolver) materialize(p, dest string, depth int) ([]File, error) {
+	resolved, err := r.resolve(p, depth)
+	if err != nil {
+		return nil, err
+	}
+	if file, exists := r.files[resolved]; exists {
+		file.Path = dest
+		return []File{file}, nil
+	}
+
+	// Anything else must be a directory holding files of the schema
+	var copies []File
+	for _, entry := range r.paths {
+		rest, below := strings.CutPrefix(entry, resolved+"/")
+		if !below {
+			continue
+		}
+		if depth >= maxSymlinkDepth {
+			return nil, fmt.Errorf("too many levels of symlinks materializing %s", dest)
+		}
+		entryCopies, err := r.materialize(entry, dest+"/"+rest, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		copies = append(copies, entryCopies...)
+	}
+	if len(copies) == 0 {
+		return nil, fmt.Errorf("symlink %s points to %s, which is not in the schema", dest, resolved)
+	}
+	return copies, nil
+}
@@ -0,0 +1,117 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+// symlink returns a symlink file at path pointing to target
+func symlink(path, target string) File {
+	return File{Path: path, Type: FileTypeSymlink, Target: target, Hash: ContentHash(target)}
+}
+
+func TestValidateSymlinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    File
+		wantErr string
+	}{
+		{"valid", symlink("pkg/api/.eslintrc", "../../.eslintrc"), ""},
+		{"no target", File{Path: "link", Type: FileTypeSymlink}, "must have a target"},
+		{"escapes project", symlink("pkg/link", "../../outside"), "points outside the project"},
+		{"absolute", symlink("link", "/etc/passwd"), "points outside the project"},
+		{"with content", File{Path: "link", Type: FileTypeSymlink, Target: "a", Content: "x"}, "cannot have content"},
+		{"unknown type", File{Path: "link", Type: "fifo"}, "unknown type"},
+		{"hash mismatch", File{Path: "link", Type: FileTypeSymlink, Target: "a", Hash: "bad"}, "hash mismatch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := &Schema{
+				Name: "test", Type: "test", Version: "1.0.0", Variables: map[string]Variable{},
+				Files: []File{{Path: ".eslintrc", Content: "{}"}, tt.file},
+			}
+			err := Validate(schema)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	schema := &Schema{
+		Name: "test", Type: "test", Version: "1.0.0", Variables: map[string]Variable{},
+		Files: []File{symlink("shared", "config"), {Path: "shared/app.json", Content: "{}"}},
+	}
+	if err := Validate(schema); err == nil || !strings.Contains(err.Error(), "below symlink") {
+		t.Errorf("Validate() error = %v, want files below a symlink refused", err)
+	}
+
+	// d/l2 points to the project root, so d/l2/.. is its parent, not d as read lexically
+	schema = &Schema{
+		Name: "test", Type: "test", Version: "1.0.0", Variables: map[string]Variable{},
+		Files: []File{{Path: "d/file.txt", Content: "x"}, symlink("d/l2", ".."), symlink("l1", "d/l2/..")},
+	}
+	if err := Validate(schema); err == nil || !strings.Contains(err.Error(), "symlink l1 points outside") {
+		t.Errorf("Validate() error = %v, want l1 escaping through d/l2 refused", err)
+	}
+	if _, err := MaterializeSymlinks(schema.Files); err == nil {
+		t.Error("MaterializeSymlinks() should refuse l1 escaping through d/l2")
+	}
+	schema.Files[2] = symlink("l1", "d/l2/d/file.txt")
+	if err := Validate(schema); err != nil {
+		t.Errorf("Validate() of a link through a link staying inside error = %v", err)
+	}
+}
+
+func TestMaterializeSymlinks(t *testing.T) {
+	files := []File{
+		{Path: "config/app.json", Content: "{}"},
+		{Path: "config/base/tsconfig.json", Content: "base"},
+		symlink("config/tsconfig.json", "base/tsconfig.json"),
+		symlink("pkg/web/config", "../../config"),
+		symlink("pkg/web/tsconfig.json", "config/tsconfig.json"),
+	}
+
+	materialized, err := MaterializeSymlinks(files)
+	if err != nil {
+		t.Fatalf("MaterializeSymlinks() error = %v", err)
+	}
+
+	got := map[string]string{}
+	for _, file := range materialized {
+		if file.IsSymlink() {
+			t.Errorf("%s is still a symlink", file.Path)
+		}
+		got[file.Path] = file.Content
+	}
+	want := map[string]string{
+		"config/app.json":                   "{}",
+		"config/base/tsconfig.json":         "base",
+		"config/tsconfig.json":              "base",
+		"pkg/web/config/app.json":           "{}",
+		"pkg/web/config/base/tsconfig.json": "base",
+		"pkg/web/config/tsconfig.json":      "base",
+		"pkg/web/tsconfig.json":             "base",
+	}
+	if len(got) != len(want) || len(materialized) != len(want) {
+		t.Errorf("MaterializeSymlinks() files = %v, want %v", got, want)
+	}
+	for path, content := range want {
+		if got[path] != content {
+			t.Errorf("%s = %q, want %q", path, got[path], content)
+		}
+	}
+
+	if _, err := MaterializeSymlinks([]File{symlink("link", "missing")}); err == nil {
+		t.Error("MaterializeSymlinks() of a dangling symlink should fail")
+	}
+	if _, err := MaterializeSymlinks([]File{symlink("a", "b"), symlink("b", "a")}); err == nil {
+		t.Error("MaterializeSymlinks() of a symlink loop should fail")
+	}
+}
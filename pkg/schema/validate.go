@@ -0,0 +1,275 @@
+package schema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ValidateOptions relaxes schema validation
+type ValidateOptions struct {
+	// SkipHashes accepts files whose content no longer matches their recorded hash,
+	// for schemas that were edited by hand on purpose
+	SkipHashes bool
+}
+
+// Validate validates a template schema for integrity and completeness
+func Validate(schema *Schema) error {
+	return ValidateWithOptions(schema, ValidateOptions{})
+}
+
+// ValidateWithOptions validates a template schema as configured by opts
+func ValidateWithOptions(schema *Schema, opts ValidateOptions) error {
+	if err := validateBasicFields(schema); err != nil {
+		return err
+	}
+
+	if err := validateSchemaVariables(schema); err != nil {
+		return err
+	}
+
+	if err := validateTestMatrix(schema); err != nil {
+		return err
+	}
+
+	if err := validateHooks(schema); err != nil {
+		return err
+	}
+
+	if err := validateGoModules(schema); err != nil {
+		return err
+	}
+
+	if err := validatePackages(schema); err != nil {
+		return err
+	}
+
+	if err := validateFeatures(schema); err != nil {
+		return err
+	}
+
+	return validateSchemaFiles(schema, opts)
+}
+
+// ValidateFields validates everything but the files of a schema, returning every failure
+// instead of stopping at the first one
+func ValidateFields(schema *Schema) []error {
+	var errs []error
+	for _, validate := range []func(*Schema) error{
+		validateBasicFields, validateSchemaVariables, validateTestMatrix, validateHooks, validateGoModules,
+		validatePackages, validateFeatures,
+	} {
+		if err := validate(schema); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// ValidateFile validates the file at index of the schema, including its hash
+func ValidateFile(schema *Schema, index int) error {
+	if err := validateFile(schema, schema.Files[index], index); err != nil {
+		return err
+	}
+	return validateFileHash(schema, schema.Files[index])
+}
+
+// validateBasicFields validates the basic required fields
+func validateBasicFields(schema *Schema) error {
+	if schema.Name == "" {
+		return fmt.Errorf("schema name is required")
+	}
+
+	if schema.Type == "" {
+		return fmt.Errorf("schema type is required")
+	}
+
+	if schema.Version == "" {
+		return fmt.Errorf("schema version is required")
+	}
+
+	if schema.MinEngineVersion != "" {
+		if _, err := parseVersion(schema.MinEngineVersion); err != nil {
+			return fmt.Errorf("schema min_engine_version: %w", err)
+		}
+	}
+
+	return validateDelims(schema.Delims, "schema")
+}
+
+// validateSchemaVariables validates the variables section
+func validateSchemaVariables(schema *Schema) error {
+	if schema.Variables == nil {
+		return fmt.Errorf("schema variables is required")
+	}
+
+	for name, variable := range schema.Variables {
+		if err := validateVariable(name, variable); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateTestMatrix validates the test cases
+func validateTestMatrix(schema *Schema) error {
+	seen := make(map[string]bool)
+	for i, testCase := range schema.TestMatrix {
+		if testCase.Name == "" {
+			return fmt.Errorf("test case %d must have a name", i)
+		}
+		if seen[testCase.Name] {
+			return fmt.Errorf("duplicate test case %q", testCase.Name)
+		}
+		seen[testCase.Name] = true
+
+		for name := range testCase.Variables {
+			if !slices.Contains(BuiltinVariables, name) {
+				return fmt.Errorf("test case %q sets unsupported variable %q, expected one of %v",
+					testCase.Name, name, BuiltinVariables)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateSchemaFiles validates the files section
+func validateSchemaFiles(schema *Schema, opts ValidateOptions) error {
+	if len(schema.Files) == 0 {
+		return fmt.Errorf("schema must contain at least one file")
+	}
+
+	for i, file := range schema.Files {
+		if err := validateFile(schema, file, i); err != nil {
+			return err
+		}
+
+		if !opts.SkipHashes {
+			if err := validateFileHash(schema, file); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateFile validates a single file specification
+func validateFile(schema *Schema, file File, index int) error {
+	if file.Path == "" {
+		return fmt.Errorf("file %d must have a path", index)
+	}
+
+	if err := validateForEach(schema, file); err != nil {
+		return err
+	}
+
+	if file.Type != "" || file.Target != "" {
+		return validateSymlink(schema, file)
+	}
+
+	stored, err := StoredContent(schema, file)
+	if err != nil {
+		return err
+	}
+
+	if stored == "" {
+		return fmt.Errorf("file %s must have content", file.Path)
+	}
+
+	if file.Codec != "" {
+		if _, err := ParseCodec(file.Codec); err != nil {
+			return fmt.Errorf("file %s: %w", file.Path, err)
+		}
+	}
+
+	if err := validateEncoding(file); err != nil {
+		return err
+	}
+
+	if err := validateDelims(file.Delims, "file "+file.Path); err != nil {
+		return err
+	}
+
+	if err := validatePatches(file); err != nil {
+		return err
+	}
+
+	return validateMappings(file)
+}
+
+// validateFileHash validates the hash of a file if present
+func validateFileHash(schema *Schema, file File) error {
+	if file.Hash == "" {
+		return nil
+	}
+
+	content, err := hashedContent(schema, file)
+	if err != nil {
+		return fmt.Errorf("file %s failed to decompress for validation: %w", file.Path, err)
+	}
+
+	calculatedHash := ContentHash(content)
+	if file.Hash != calculatedHash {
+		return fmt.Errorf("file %s hash mismatch: expected %s, got %s",
+			file.Path, file.Hash, calculatedHash)
+	}
+
+	return nil
+}
+
+// ContentHash calculates SHA256 hash of content
+func ContentHash(content string) string {
+	hash := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(hash[:])
+}
+
+// Hash calculates a hash for the entire schema from its identity and file hashes
+func Hash(schema *Schema) string {
+	// Create a deterministic string representation of the schema
+	var content strings.Builder
+	content.WriteString(schema.Name)
+	content.WriteString(schema.Type)
+	content.WriteString(schema.Version)
+
+	for _, file := range schema.Files {
+		content.WriteString(file.Path)
+		content.WriteString(file.Hash)
+	}
+
+	hash := sha256.Sum256([]byte(content.String()))
+	return hex.EncodeToString(hash[:])
+}
+
+// FixHashes recomputes the size and hash of every file from its content, then the schema hash,
+// so a schema edited by hand validates again. It returns the paths of the files that changed.
+func FixHashes(schema *Schema) ([]string, error) {
+	fixed := []string{}
+
+	for i := range schema.Files {
+		file := &schema.Files[i]
+
+		content, err := hashedContent(schema, *file)
+		if err != nil {
+			return nil, fmt.Errorf("file %s failed to decompress: %w", file.Path, err)
+		}
+
+		hash := ContentHash(content)
+		size := int64(len(content))
+		if file.IsSymlink() {
+			size = 0
+		}
+		if file.Hash != hash || file.Size != size {
+			file.Hash = hash
+			file.Size = size
+			fixed = append(fixed, file.Path)
+		}
+	}
+
+	schema.Hash = Hash(schema)
+	return fixed, nil
+}
@@ -0,0 +1,234 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFixHashes(t *testing.T) {
+	schema := &Schema{
+		Name:      "test",
+		Type:      "go-api",
+		Version:   "1.0.0",
+		Variables: map[string]Variable{},
+		Files: []File{
+			{Path: "README.md", Content: "# Edited by hand", Hash: ContentHash("# Original"), Size: 10},
+			{Path: "main.go", Content: "package main", Hash: ContentHash("package main"), Size: 12},
+		},
+	}
+
+	if err := Validate(schema); err == nil {
+		t.Fatal("Validate() should reject the edited file")
+	}
+	if err := ValidateWithOptions(schema, ValidateOptions{SkipHashes: true}); err != nil {
+		t.Fatalf("ValidateWithOptions(SkipHashes) error = %v", err)
+	}
+
+	fixed, err := FixHashes(schema)
+	if err != nil {
+		t.Fatalf("FixHashes() error = %v", err)
+	}
+	if len(fixed) != 1 || fixed[0] != "README.md" {
+		t.Errorf("FixHashes() fixed = %v, want [README.md]", fixed)
+	}
+	if schema.Files[0].Size != int64(len("# Edited by hand")) {
+		t.Errorf("Size = %d, want the edited content length", schema.Files[0].Size)
+	}
+	if schema.Hash != Hash(schema) {
+		t.Error("schema hash was not recomputed")
+	}
+	if err := Validate(schema); err != nil {
+		t.Errorf("Validate() after FixHashes error = %v", err)
+	}
+}
+
+func TestValidateTestMatrix(t *testing.T) {
+	tests := []struct {
+		name    string
+		matrix  []TestCase
+		wantErr bool
+	}{
+		{"valid", []TestCase{{Name: "a", Variables: map[string]string{"ProjectName": "A"}}, {Name: "b"}}, false},
+		{"missing name", []TestCase{{Variables: map[string]string{"ProjectName": "A"}}}, true},
+		{"duplicate name", []TestCase{{Name: "a"}, {Name: "a"}}, true},
+		{"unknown variable", []TestCase{{Name: "a", Variables: map[string]string{"Team": "core"}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := &Schema{TestMatrix: tt.matrix}
+			if err := validateTestMatrix(schema); (err != nil) != tt.wantErr {
+				t.Errorf("validateTestMatrix() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateGoModules(t *testing.T) {
+	tests := []struct {
+		name    string
+		modules []GoModule
+		wantErr bool
+	}{
+		{"valid", []GoModule{{Path: "go.mod", Module: "github.com/{{.GitHubRepo}}"}}, false},
+		{"not a go.mod", []GoModule{{Path: "main.go", Module: "example.com/app"}}, true},
+		{"missing module", []GoModule{{Path: "go.mod"}}, true},
+		{"missing file", []GoModule{{Path: "backend/go.mod", Module: "example.com/app"}}, true},
+		{"duplicate", []GoModule{{Path: "go.mod", Module: "a"}, {Path: "go.mod", Module: "b"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := &Schema{GoModules: tt.modules, Files: []File{{Path: "go.mod"}, {Path: "main.go"}}}
+			if err := validateGoModules(schema); (err != nil) != tt.wantErr {
+				t.Errorf("validateGoModules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePackages(t *testing.T) {
+	tests := []struct {
+		name     string
+		packages []WorkspacePackage
+		wantErr  bool
+	}{
+		{"valid", []WorkspacePackage{{Name: "@acme/ui", Dir: "packages/ui"}}, false},
+		{"missing name", []WorkspacePackage{{Dir: "packages/ui"}}, true},
+		{"root dir", []WorkspacePackage{{Name: "acme", Dir: "."}}, true},
+		{"unclean dir", []WorkspacePackage{{Name: "@acme/ui", Dir: "packages/ui/"}}, true},
+		{"missing package.json", []WorkspacePackage{{Name: "@acme/web", Dir: "apps/web"}}, true},
+		{"duplicate", []WorkspacePackage{{Name: "@acme/ui", Dir: "packages/ui"}, {Name: "@acme/ui", Dir: "ui"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := &Schema{Packages: tt.packages, Files: []File{{Path: "package.json"}, {Path: "packages/ui/package.json"}}}
+			if err := validatePackages(schema); (err != nil) != tt.wantErr {
+				t.Errorf("validatePackages() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    File
+		wantErr bool
+	}{
+		{"valid", File{JSONPatch: []Patch{{Path: "name", Value: "x"}, {Op: PatchRemove, Path: "a\\.b.c"}}}, false},
+		{"unknown op", File{JSONPatch: []Patch{{Op: "move", Path: "name"}}}, true},
+		{"empty key", File{JSONPatch: []Patch{{Path: "scripts..dev"}}}, true},
+		{"binary", File{Encoding: EncodingBase64, JSONPatch: []Patch{{Path: "name"}}}, true},
+		{"rename", File{YAMLPatch: []Patch{{Op: PatchRename, Path: "services.api", Value: "web"}}}, false},
+		{"rename without key", File{YAMLPatch: []Patch{{Op: PatchRename, Path: "services.api"}}}, true},
+		{"rename wildcard", File{YAMLPatch: []Patch{{Op: PatchRename, Path: "services.*", Value: "web"}}}, true},
+		{"json and yaml", File{JSONPatch: []Patch{{Path: "a"}}, YAMLPatch: []Patch{{Path: "a"}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validatePatches(tt.file); (err != nil) != tt.wantErr {
+				t.Errorf("validatePatches() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if keys := SplitPatchPath("scripts.lint\\.fix.0"); len(keys) != 3 || keys[1] != "lint.fix" {
+		t.Errorf("SplitPatchPath() = %q", keys)
+	}
+}
+
+func TestValidateVariables(t *testing.T) {
+	tests := []struct {
+		name     string
+		variable Variable
+		wantErr  bool
+	}{
+		{"list", Variable{Type: VariableTypeList, Default: "auth, billing"}, false},
+		{"map", Variable{Type: VariableTypeMap, Default: "api=8080,web=3000"}, false},
+		{"empty map", Variable{Type: VariableTypeMap}, false},
+		{"missing type", Variable{Default: "x"}, true},
+		{"invalid map default", Variable{Type: VariableTypeMap, Default: "api:8080"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := &Schema{Variables: map[string]Variable{"Services": tt.variable}}
+			if err := validateSchemaVariables(schema); (err != nil) != tt.wantErr {
+				t.Errorf("validateSchemaVariables() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	schema := &Schema{Variables: map[string]Variable{"ProjectName": {Type: VariableTypeList}}}
+	if err := validateSchemaVariables(schema); err == nil {
+		t.Error("validateSchemaVariables() should reject a list-typed built-in variable")
+	}
+}
+
+func TestVariableParseValue(t *testing.T) {
+	list, err := Variable{Type: VariableTypeList}.ParseValue(" auth,, billing ")
+	if err != nil || !reflect.DeepEqual(list, []string{"auth", "billing"}) {
+		t.Errorf("ParseValue(list) = %v, %v", list, err)
+	}
+	entries, err := Variable{Type: VariableTypeMap}.ParseValue("api = 8080, web=3000")
+	if err != nil || !reflect.DeepEqual(entries, map[string]string{"api": "8080", "web": "3000"}) {
+		t.Errorf("ParseValue(map) = %v, %v", entries, err)
+	}
+	if value, _ := (Variable{Type: "string"}).ParseValue("a,b"); value != "a,b" {
+		t.Errorf("ParseValue(string) = %v, want a,b", value)
+	}
+}
+
+func TestValidateForEach(t *testing.T) {
+	schema := &Schema{Variables: map[string]Variable{
+		"Services": {Type: VariableTypeList},
+		"Ports":    {Type: VariableTypeMap},
+	}}
+	tests := []struct {
+		name    string
+		forEach string
+		wantErr bool
+	}{
+		{"not repeated", "", false},
+		{"list", "Services", false},
+		{"map", "Ports", true},
+		{"undeclared", "Modules", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := File{Path: "internal/{{.item}}/handler.go", ForEach: tt.forEach}
+			if err := validateForEach(schema, file); (err != nil) != tt.wantErr {
+				t.Errorf("validateForEach() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateFeatures(t *testing.T) {
+	tests := []struct {
+		name     string
+		features []Feature
+		wantErr  bool
+	}{
+		{"valid", []Feature{{Name: "auth", Files: []string{"internal/auth"}}, {Name: "ci", Hooks: []string{"lint"}}}, false},
+		{"missing name", []Feature{{Files: []string{"internal/auth"}}}, true},
+		{"name with comma", []Feature{{Name: "auth,sso", Files: []string{"internal/auth"}}}, true},
+		{"duplicate", []Feature{{Name: "auth", Files: []string{"a"}}, {Name: "auth", Files: []string{"b"}}}, true},
+		{"toggles nothing", []Feature{{Name: "auth"}}, true},
+		{"invalid pattern", []Feature{{Name: "auth", Files: []string{"internal/[auth"}}}, true},
+		{"unknown hook", []Feature{{Name: "ci", Hooks: []string{"deploy"}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := &Schema{Features: tt.features, Hooks: Hooks{{Name: "lint", Stage: HookPostGenerate, Command: "make lint"}}}
+			if err := validateFeatures(schema); (err != nil) != tt.wantErr {
+				t.Errorf("validateFeatures() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
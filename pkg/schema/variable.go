@@ -0,0 +1,81 @@
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Types of the variables whose values are collections, which templates range over. Their
+// values are written as strings all the same, in --var flags, variable files and defaults:
+// lists as comma-separated items (auth,billing) and maps as comma-separated key=value pairs
+// (api=8080,web=3000).
+const (
+	VariableTypeList = "list"
+	VariableTypeMap  = "map"
+)
+
+// ParseValue returns the value templates see for the string value of the variable: a []string
+// for lists, a map[string]string for maps and value itself for other types. Items, keys and
+// values are trimmed and empty items dropped.
+func (v Variable) ParseValue(value string) (any, error) {
+	switch v.Type {
+	case VariableTypeList:
+		return splitItems(value), nil
+	case VariableTypeMap:
+		entries := map[string]string{}
+		for _, item := range splitItems(value) {
+			key, entry, found := strings.Cut(item, "=")
+			key = strings.TrimSpace(key)
+			if !found || key == "" {
+				return nil, fmt.Errorf("invalid map entry %q, expected key=value", item)
+			}
+			entries[key] = strings.TrimSpace(entry)
+		}
+		return entries, nil
+	default:
+		return value, nil
+	}
+}
+
+// splitItems splits a comma-separated value into its trimmed, non-empty items
+func splitItems(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// validateVariable validates the type and default of a variable declared by a schema
+func validateVariable(name string, variable Variable) error {
+	if variable.Type == "" {
+		return fmt.Errorf("variable %s must have a type", name)
+	}
+	collection := variable.Type == VariableTypeList || variable.Type == VariableTypeMap
+	if collection && slices.Contains(BuiltinVariables, name) {
+		return fmt.Errorf("variable %s is built in and cannot be a %s", name, variable.Type)
+	}
+	if _, err := variable.ParseValue(variable.Default); err != nil {
+		return fmt.Errorf("variable %s default: %w", name, err)
+	}
+	return nil
+}
+
+// validateForEach checks that a file generated per item names a list variable of the schema
+func validateForEach(schema *Schema, file File) error {
+	if file.ForEach == "" {
+		return nil
+	}
+	variable, ok := schema.Variables[file.ForEach]
+	if !ok {
+		return fmt.Errorf("file %s is generated for each item of undeclared variable %s", file.Path, file.ForEach)
+	}
+	if variable.Type != VariableTypeList {
+		return fmt.Errorf("file %s is generated for each item of %s, which is a %s rather than a list",
+			file.Path, file.ForEach, variable.Type)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package schema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CurrentVersion is the schema format written by this engine.
+//
+//	1: original format (schemas without schema_version)
+//	2: explicit compression codec per file and env_config always present
+//	3: hooks are a list of typed hooks instead of a map of stage to commands
+//	4: binary files (base64 encoding), symlinks and external files, which older engines misread
+const CurrentVersion = 4
+
+// schemaMigrations upgrade a schema from version i+1 to i+2
+var schemaMigrations = []func(*Schema){
+	migrateV1ToV2,
+	migrateV2ToV3,
+	migrateV3ToV4,
+}
+
+// Migrate upgrades a schema loaded from an older format to CurrentVersion.
+// Schemas written by a newer engine are left untouched, engines refuse to generate from them, as
+// are invalid negative versions, which Parse rejects.
+func Migrate(schema *Schema) {
+	if schema.SchemaVersion == 0 {
+		schema.SchemaVersion = 1
+	}
+	if schema.SchemaVersion < 1 {
+		return
+	}
+	for schema.SchemaVersion < CurrentVersion {
+		schemaMigrations[schema.SchemaVersion-1](schema)
+		schema.SchemaVersion++
+	}
+}
+
+// migrateV1ToV2 records the implicit gzip codec and guarantees env_config is present
+func migrateV1ToV2(schema *Schema) {
+	for i := range schema.Files {
+		if schema.Files[i].Compressed && schema.Files[i].Codec == "" {
+			schema.Files[i].Codec = string(CodecGzip)
+		}
+	}
+	if schema.EnvConfig == nil {
+		schema.EnvConfig = []EnvVariable{}
+	}
+}
+
+// migrateV2ToV3 has nothing left to do: legacy hook maps are converted while decoding (see Hooks)
+func migrateV2ToV3(*Schema) {}
+
+// migrateV3ToV4 has nothing to do: version 3 schemas only hold regular text files
+func migrateV3ToV4(*Schema) {}
+
+// CompareVersions compares the semantic versions a and b, returning -1, 0 or 1 as a is lower
+// than, equal to or higher than b. Versions are MAJOR[.MINOR[.PATCH]] with an optional "v"
+// prefix; pre-release and build suffixes are ignored.
+func CompareVersions(a, b string) (int, error) {
+	va, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range va {
+		if va[i] != vb[i] {
+			if va[i] < vb[i] {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// parseVersion parses MAJOR[.MINOR[.PATCH]] with an optional "v" prefix. Pre-release and
+// build suffixes are ignored.
+func parseVersion(version string) ([3]int, error) {
+	var parsed [3]int
+
+	trimmed := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if end := strings.IndexAny(trimmed, "-+"); end >= 0 {
+		trimmed = trimmed[:end]
+	}
+
+	parts := strings.Split(trimmed, ".")
+	if trimmed == "" || len(parts) > 3 {
+		return parsed, fmt.Errorf("invalid version %q, expected MAJOR.MINOR.PATCH", version)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return parsed, fmt.Errorf("invalid version %q, expected MAJOR.MINOR.PATCH", version)
+		}
+		parsed[i] = n
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,36 @@
+package schema
+
+import "testing"
+
+func TestParseSchemaMigratesV1(t *testing.T) {
+	data := `{"name":"legacy","type":"go-api","version":"1.0.0","variables":{},
+		"files":[{"path":"a.txt","template":false,"content":"H4sI","size":1,"compressed":true}]}`
+
+	schema, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if schema.SchemaVersion != CurrentVersion {
+		t.Errorf("SchemaVersion = %d, want %d", schema.SchemaVersion, CurrentVersion)
+	}
+	if schema.Files[0].Codec != string(CodecGzip) {
+		t.Errorf("Codec = %q, want gzip for legacy compressed files", schema.Files[0].Codec)
+	}
+	if schema.EnvConfig == nil {
+		t.Error("EnvConfig should be initialized by the migration")
+	}
+}
+
+func TestParseSchemaRejectsNegativeVersion(t *testing.T) {
+	if _, err := Parse([]byte(`{"name":"bad","schema_version":-1,"files":[]}`)); err == nil {
+		t.Error("Parse() should reject a negative schema_version")
+	}
+
+	// Migrate leaves schemas built in code with a negative version alone instead of panicking
+	schema := &Schema{SchemaVersion: -1}
+	Migrate(schema)
+	if schema.SchemaVersion != -1 {
+		t.Errorf("SchemaVersion = %d, want -1 untouched", schema.SchemaVersion)
+	}
+}
@@ -0,0 +1,44 @@
+package schema
+
+import (
+	"fmt"
+	"path"
+	"slices"
+)
+
+// WorkspacePackage declares a package of a monorepo workspace (pnpm, turbo or nx), so
+// generation can leave it out: its directory is dropped and the remaining packages stop
+// depending on it.
+type WorkspacePackage struct {
+	// Name of the package in the reference project, as in its package.json
+	Name string `json:"name"`
+	// Dir of the package in the schema, e.g. "packages/ui"
+	Dir string `json:"dir"`
+}
+
+// validatePackages validates that every workspace package names a package.json of the schema
+func validatePackages(schema *Schema) error {
+	names := make(map[string]bool)
+	dirs := make(map[string]bool)
+	for _, pkg := range schema.Packages {
+		if pkg.Name == "" {
+			return fmt.Errorf("workspace package %s must have a name", pkg.Dir)
+		}
+		if pkg.Dir == "" || pkg.Dir == "." || path.Clean(pkg.Dir) != pkg.Dir {
+			return fmt.Errorf("workspace package %s must have a clean directory, got %q", pkg.Name, pkg.Dir)
+		}
+		if names[pkg.Name] {
+			return fmt.Errorf("duplicate workspace package %s", pkg.Name)
+		}
+		if dirs[pkg.Dir] {
+			return fmt.Errorf("duplicate workspace package directory %s", pkg.Dir)
+		}
+		names[pkg.Name], dirs[pkg.Dir] = true, true
+
+		manifest := path.Join(pkg.Dir, "package.json")
+		if !slices.ContainsFunc(schema.Files, func(file File) bool { return file.Path == manifest }) {
+			return fmt.Errorf("workspace package %s has no %s in the schema", pkg.Name, manifest)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"os/user"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/generate"
+)
+
+// redacted replaces the values of secret variables in audit records
+const redacted = "[REDACTED]"
+
+// secretWords are the words of variable names holding credentials, redacted from audit records
+var secretWords = []string{"password", "passphrase", "secret", "token", "key", "credential", "credentials"}
+
+// AuditRecord records a generation, successful or not, see WithAuditLog
+type AuditRecord struct {
+	Time time.Time `json:"time"`
+	// User is who generated the project: the name given WithAuditUser, or the OS user
+	User      string `json:"user,omitempty"`
+	Host      string `json:"host,omitempty"`
+	Operation string `json:"operation"`
+	Schema    string `json:"schema"`
+	Version   string `json:"version"`
+	// Origin is the registry or OCI reference the schema was pulled from, if any
+	Origin      string `json:"origin,omitempty"`
+	ProjectName string `json:"project_name"`
+	GitHubRepo  string `json:"github_repo"`
+	// Variables holds the variables given, those whose name looks like a credential (ApiToken,
+	// DbPassword) redacted
+	Variables  map[string]string `json:"variables,omitempty"`
+	OutputDir  string            `json:"output_dir,omitempty"`
+	Files      int               `json:"files"`
+	DurationMS int64             `json:"duration_ms"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// WithAuditLog writes an AuditRecord per generation to w as a JSON line, for platform teams
+// tracking scaffolding activity. Records are written whole, even from concurrent generations;
+// write errors are logged and do not fail generation.
+func WithAuditLog(w io.Writer) Option {
+	var mu sync.Mutex
+	encoder := json.NewEncoder(w)
+	return WithAuditFunc(func(record AuditRecord) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return encoder.Encode(record)
+	})
+}
+
+// WithAuditFunc calls fn with an AuditRecord per generation, e.g. to send it to a log pipeline.
+// fn may be called concurrently by GenerateMany; its errors are logged and do not fail
+// generation.
+func WithAuditFunc(fn func(AuditRecord) error) Option {
+	return func(c *Client) {
+		c.audit = append(c.audit, fn)
+	}
+}
+
+// WithAuditUser sets the user audit records name, for services generating on behalf of their
+// users. By default it is the OS user running the client.
+func WithAuditUser(name string) Option {
+	return func(c *Client) {
+		c.auditUser = name
+	}
+}
+
+// generate runs generator for operation, recording the generation to the audit sinks
+func (c *Client) generate(ctx context.Context, operation string, generator *generate.Generator,
+	schema *TemplateSchema, variables Variables,
+) error {
+	err := generator.Generate(ctx)
+	if len(c.audit) == 0 {
+		return err
+	}
+
+	result := generator.Result()
+	record := AuditRecord{
+		Time:        time.Now().UTC(),
+		User:        c.auditUser,
+		Operation:   operation,
+		Schema:      schema.Name,
+		Version:     schema.Version,
+		Origin:      schema.Origin,
+		ProjectName: variables.ProjectName,
+		GitHubRepo:  core.NormalizeGitHubRepo(variables.GitHubRepo),
+		Variables:   auditVariables(variables),
+		OutputDir:   variables.OutputDir,
+		Files:       result.FileCount,
+		DurationMS:  result.DurationMS,
+	}
+	if record.User == "" {
+		if current, userErr := user.Current(); userErr == nil {
+			record.User = current.Username
+		}
+	}
+	record.Host, _ = os.Hostname()
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	for _, sink := range c.audit {
+		if sinkErr := sink(record); sinkErr != nil {
+			c.logger.Warn("Failed to record audit log", "operation", operation, "error", sinkErr)
+		}
+	}
+	return err
+}
+
+// auditVariables returns the variables of an audit record, secrets redacted
+func auditVariables(variables Variables) map[string]string {
+	recorded := map[string]string{}
+	if variables.Author != "" {
+		recorded["Author"] = variables.Author
+	}
+	if variables.Description != "" {
+		recorded["Description"] = variables.Description
+	}
+	for name, value := range variables.Custom {
+		if isSecretVariable(name) {
+			value = redacted
+		}
+		recorded[name] = value
+	}
+	return recorded
+}
+
+// isSecretVariable reports whether a variable name looks like it holds a credential (ApiToken,
+// DB_PASSWORD, signingKey)
+func isSecretVariable(name string) bool {
+	for _, word := range core.SplitWords(name) {
+		if slices.Contains(secretWords, strings.ToLower(word)) {
+			return true
+		}
+	}
+	return false
+}
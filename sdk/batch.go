@@ -0,0 +1,102 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// GenerateRequest is one project of a GenerateMany batch
+type GenerateRequest struct {
+	// Schema to generate from; when nil, Template names a registered schema (by name or type)
+	Schema    *TemplateSchema
+	Template  string
+	Variables Variables
+}
+
+// GenerateMany generates the projects of requests concurrently, at most WithConcurrency of them
+// (one per CPU by default) at a time. Requests may share a schema. The results are in the
+// order of requests; a failed request leaves its result empty and does not stop the others.
+// The returned error joins the errors of all failed requests.
+func (c *Client) GenerateMany(ctx context.Context, requests []GenerateRequest) ([]GenerateResult, error) {
+	if err := checkDistinctOutputDirs(requests); err != nil {
+		return nil, err
+	}
+
+	workers := c.concurrency
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(requests))
+
+	results := make([]GenerateResult, len(requests))
+	errs := make([]error, len(requests))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result, err := c.generateRequest(ctx, requests[i])
+				if err != nil {
+					errs[i] = fmt.Errorf("request %d (%s): %w", i, requests[i].Variables.ProjectName, err)
+					continue
+				}
+				results[i] = *result
+			}
+		}()
+	}
+
+	for i := range requests {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var failed []error
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	if len(failed) > 0 {
+		message := fmt.Sprintf("%d of %d projects failed", len(failed), len(requests))
+		return results, newGenerationError("GenerateMany", message, errors.Join(failed...))
+	}
+	return results, nil
+}
+
+// generateRequest generates the project of a single GenerateMany request
+func (c *Client) generateRequest(ctx context.Context, request GenerateRequest) (*GenerateResult, error) {
+	if request.Schema != nil {
+		return c.GenerateFromTemplate(ctx, request.Schema, request.Variables)
+	}
+	return c.Generate(ctx, GenerateOptions{
+		Template:    request.Template,
+		ProjectName: request.Variables.ProjectName,
+		GitHubRepo:  request.Variables.GitHubRepo,
+		OutputDir:   request.Variables.OutputDir,
+		Author:      request.Variables.Author,
+		Description: request.Variables.Description,
+		Variables:   request.Variables.Custom,
+	})
+}
+
+// checkDistinctOutputDirs refuses batches in which two requests would write the same directory
+func checkDistinctOutputDirs(requests []GenerateRequest) error {
+	seen := make(map[string]int, len(requests))
+	for i, request := range requests {
+		dir := filepath.Clean(request.Variables.OutputDir)
+		if first, exists := seen[dir]; exists && request.Variables.OutputDir != "" {
+			return newValidationError("GenerateMany",
+				fmt.Sprintf("requests %d and %d share the output directory %s", first, i, dir), "")
+		}
+		seen[dir] = i
+	}
+	return nil
+}
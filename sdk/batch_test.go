@@ -0,0 +1,77 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+func TestGenerateMany(t *testing.T) {
+	schema := &core.TemplateSchema{
+		Name:      "service",
+		Type:      "go-api",
+		Version:   "1.0.0",
+		Variables: map[string]core.Variable{},
+		Files:     []core.FileSpec{{Path: "README.md", Template: true, Content: "# {{.ProjectName}}"}},
+	}
+	baseDir := t.TempDir()
+
+	var requests []GenerateRequest
+	for i := range 5 {
+		requests = append(requests, GenerateRequest{Schema: schema, Variables: Variables{
+			ProjectName: fmt.Sprintf("service-%d", i),
+			GitHubRepo:  fmt.Sprintf("acme/service-%d", i),
+			OutputDir:   filepath.Join(baseDir, fmt.Sprintf("service-%d", i)),
+		}})
+	}
+
+	results, err := New(WithConcurrency(2)).GenerateMany(context.Background(), requests)
+	if err != nil {
+		t.Fatalf("GenerateMany() error = %v", err)
+	}
+	if len(results) != len(requests) {
+		t.Fatalf("GenerateMany() returned %d results, want %d", len(results), len(requests))
+	}
+	for i, result := range results {
+		if result.OutputDir != requests[i].Variables.OutputDir || result.FileCount != 1 {
+			t.Errorf("result %d = %+v", i, result)
+		}
+		content, err := os.ReadFile(filepath.Join(requests[i].Variables.OutputDir, "README.md"))
+		if err != nil || string(content) != fmt.Sprintf("# service-%d", i) {
+			t.Errorf("service-%d README.md = %q (%v)", i, content, err)
+		}
+	}
+}
+
+func TestGenerateManyFailures(t *testing.T) {
+	client := createMockClient()
+	baseDir := t.TempDir()
+
+	requests := []GenerateRequest{
+		{Template: "mock-api", Variables: Variables{
+			ProjectName: "good", GitHubRepo: "acme/good", OutputDir: filepath.Join(baseDir, "good"),
+		}},
+		{Template: "missing", Variables: Variables{
+			ProjectName: "bad", GitHubRepo: "acme/bad", OutputDir: filepath.Join(baseDir, "bad"),
+		}},
+	}
+
+	results, err := client.GenerateMany(context.Background(), requests)
+	var sdkErr *SDKError
+	if !errors.As(err, &sdkErr) || sdkErr.Type != ErrorTypeGeneration {
+		t.Fatalf("GenerateMany() error = %v, want a generation error", err)
+	}
+	if results[0].FileCount != 1 || results[1].FileCount != 0 {
+		t.Errorf("GenerateMany() results = %+v, want only the first project generated", results)
+	}
+
+	requests[1] = requests[0]
+	if _, err := client.GenerateMany(context.Background(), requests); err == nil {
+		t.Error("GenerateMany() should refuse requests sharing an output directory")
+	}
+}
@@ -2,67 +2,119 @@ package sdk
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
 	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 
+	"github.com/acheevo/template-engine/internal/archive"
 	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/internal/download"
 	"github.com/acheevo/template-engine/internal/generate"
+	"github.com/acheevo/template-engine/internal/harness"
+	"github.com/acheevo/template-engine/internal/logging"
+	"github.com/acheevo/template-engine/internal/oci"
+	"github.com/acheevo/template-engine/internal/policy"
+	"github.com/acheevo/template-engine/internal/schemacache"
 	_ "github.com/acheevo/template-engine/internal/templates" // Import to register templates
+	"github.com/acheevo/template-engine/pkg/schema"
 )
 
 // Client provides programmatic access to the template engine
 type Client struct {
-	templates map[string]*core.TemplateSchema
+	mu          sync.RWMutex // Guards templates
+	templates   map[string]*core.TemplateSchema
+	logger      *slog.Logger
+	hooks       generate.HookOptions
+	cache       *schemacache.Cache
+	registry    *core.TemplateRegistry
+	concurrency int
+	download    download.Options // HTTP client and retries of remote schema downloads
+	policies    []Policy
+	audit       []func(AuditRecord) error // Audit sinks, see WithAuditFunc
+	auditUser   string
 }
 
 // New creates a new SDK client
-func New() *Client {
+func New(opts ...Option) *Client {
 	templates := make(map[string]*core.TemplateSchema)
 
-	return &Client{
+	client := &Client{
 		templates: templates,
+		logger:    logging.Discard(),
+		registry:  core.GlobalRegistry,
 	}
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	return client
+}
+
+// NewWithRegistry creates a client resolving template types in registry instead of the
+// process-wide registry, so clients serving different tenants can register conflicting or
+// tenant-specific template types. Start from NewTemplateRegistry to keep the built-in types.
+func NewWithRegistry(registry *TemplateRegistry, opts ...Option) *Client {
+	client := New(opts...)
+	if registry != nil {
+		client.registry = registry
+	}
+	return client
+}
+
+// NewTemplateRegistry returns a registry holding the built-in template types, independent of
+// the process-wide registry and of other registries it returns
+func NewTemplateRegistry() *TemplateRegistry {
+	return core.GlobalRegistry.Clone()
 }
 
 // GenerateOptions contains options for generating a project
 type GenerateOptions struct {
 	Template    string            // Template name (e.g., "frontend", "go-api")
 	ProjectName string            // Name of the project
-	GitHubRepo  string            // GitHub repository (e.g., "user/repo")
+	GitHubRepo  string            // GitHub repository ("user/repo" or its github.com URL)
 	OutputDir   string            // Output directory
+	Author      string            // Optional: defaults to the git user ("Name <email>")
+	Description string            // Optional: defaults to "A <project name> application"
 	Variables   map[string]string // Additional template variables
 }
 
+// ExtractResult is an extracted schema with the files left out of it, see ExtractWithDiagnostics
+type ExtractResult struct {
+	Schema  *TemplateSchema `json:"schema"`
+	Skipped []SkippedFile   `json:"skipped"`
+}
+
 // ExtractOptions contains options for extracting a template
 type ExtractOptions struct {
 	SourceDir string // Source directory to extract from
 	Type      string // Template type
 	OutputDir string // Optional: directory to save template file
+	Codec     string // Optional: compression codec (gzip by default, zstd, or none)
+	FS        fs.FS  // Optional: file tree to extract from instead of SourceDir (go:embed, zip.Reader, MemFS)
+
+	// NoGitignore extracts the files the .gitignore files of the source exclude. Such
+	// extractions bypass the schema cache.
+	NoGitignore bool
 }
 
 // Generate creates a new project from a registered template schema
 // Note: This method works with pre-registered template schemas, not template types.
 // For template types, use ExtractAndGenerate() workflow instead.
-func (c *Client) Generate(ctx context.Context, opts GenerateOptions) error {
+func (c *Client) Generate(ctx context.Context, opts GenerateOptions) (*GenerateResult, error) {
 	if err := c.ValidateGenerateOptions(opts); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Get template schema - try by name first, then by type
-	schema, exists := c.templates[opts.Template]
-	if !exists {
-		// Try to find by template type
-		for _, s := range c.templates {
-			if s.Type == opts.Template {
-				schema = s
-				exists = true
-				break
-			}
-		}
-	}
+	schema, exists := c.findSchema(opts.Template)
 	if !exists {
-		return newTemplateTypeError("Generate", opts.Template)
+		return nil, newTemplateTypeError("Generate", opts.Template)
 	}
 
 	// Create variables from options
@@ -70,84 +122,251 @@ func (c *Client) Generate(ctx context.Context, opts GenerateOptions) error {
 		ProjectName: opts.ProjectName,
 		GitHubRepo:  opts.GitHubRepo,
 		OutputDir:   opts.OutputDir,
+		Author:      opts.Author,
+		Description: opts.Description,
 		Custom:      opts.Variables,
 	}
 
-	// Set defaults if not provided
-	if variables.Author == "" {
-		variables.Author = "Developer"
-	}
-	if variables.Description == "" {
-		variables.Description = fmt.Sprintf("A %s application", opts.ProjectName)
-	}
-
 	return c.GenerateFromTemplate(ctx, schema, variables)
 }
 
-// Extract creates a template schema from a source directory using the global registry
+// Extract creates a template schema from a source directory using the client's registry
 func (c *Client) Extract(ctx context.Context, opts ExtractOptions) (*TemplateSchema, error) {
+	result, err := c.extract(ctx, "Extract", opts, false)
+	if err != nil {
+		return nil, err
+	}
+	return result.Schema, nil
+}
+
+// ExtractWithDiagnostics is Extract that also reports the files left out of the schema and why,
+// such as "matched skip dir node_modules" or "hidden file". It never uses the schema cache,
+// which does not keep these diagnostics.
+func (c *Client) ExtractWithDiagnostics(ctx context.Context, opts ExtractOptions) (*ExtractResult, error) {
+	return c.extract(ctx, "ExtractWithDiagnostics", opts, true)
+}
+
+// extract extracts a schema as operation, collecting the skipped files when explainSkips is set
+func (c *Client) extract(ctx context.Context, operation string, opts ExtractOptions, explainSkips bool,
+) (*ExtractResult, error) {
 	if err := c.ValidateExtractOptions(opts); err != nil {
 		return nil, err
 	}
 
-	// Use the global template registry for extraction
-	templateType, err := core.GetTemplate(opts.Type)
+	if err := ctx.Err(); err != nil {
+		return nil, newExtractionError(operation, "extraction cancelled", err)
+	}
+
+	templateType, err := c.registry.Get(opts.Type)
 	if err != nil {
-		return nil, newTemplateTypeError("Extract", opts.Type)
+		return nil, newTemplateTypeError(operation, opts.Type)
+	}
+	if deprecation, deprecated := c.registry.Deprecation(opts.Type); deprecated {
+		c.logger.Warn("Template type is deprecated", "type", opts.Type, "replaced_by", deprecation.ReplacedBy)
 	}
 
-	schema, err := templateType.Extract(opts.SourceDir)
+	c.logger.Debug("Extracting template", "type", opts.Type, "source", opts.SourceDir)
+
+	result := &ExtractResult{}
+	extractOpts := core.ExtractOptions{Codec: core.Codec(opts.Codec), NoGitignore: opts.NoGitignore}
+	if explainSkips {
+		result.Skipped = []SkippedFile{}
+		extractOpts.OnSkip = func(file SkippedFile) {
+			result.Skipped = append(result.Skipped, file)
+		}
+	}
+
+	switch {
+	case opts.FS != nil:
+		result.Schema, err = templateType.ExtractFS(ctx, opts.FS, extractOpts)
+	case explainSkips || opts.NoGitignore:
+		result.Schema, err = templateType.Extract(ctx, opts.SourceDir, extractOpts)
+	default:
+		result.Schema, err = c.extractCached(ctx, templateType, opts.SourceDir, extractOpts)
+	}
 	if err != nil {
-		return nil, newExtractionError("Extract", "failed to extract template from source directory", err)
+		return nil, newExtractionError(operation, "failed to extract template from source directory", err)
+	}
+
+	for _, miss := range core.LintMappings(result.Schema) {
+		c.logger.Warn("Mapping did not match", "find", miss.Find, "files", len(miss.Paths))
 	}
 
+	return result, nil
+}
+
+// extractCached extracts sourceDir, reusing the schema of an earlier extraction of the same
+// content when the client has a schema cache
+func (c *Client) extractCached(ctx context.Context, templateType core.TemplateType, sourceDir string,
+	opts core.ExtractOptions,
+) (*TemplateSchema, error) {
+	if c.cache == nil {
+		return templateType.Extract(ctx, sourceDir, opts)
+	}
+
+	key, err := schemacache.Key(ctx, core.DirFS(sourceDir), templateType, opts.Codec)
+	if err != nil {
+		return nil, err
+	}
+	if schema, ok := c.cache.Get(key); ok {
+		c.logger.Debug("Using cached schema", "type", templateType.Name(), "source", sourceDir)
+		return schema, nil
+	}
+
+	schema, err := templateType.Extract(ctx, sourceDir, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.cache.Put(key, schema); err != nil {
+		c.logger.Warn("Failed to cache schema", "error", err)
+	}
 	return schema, nil
 }
 
-// GenerateFromTemplate creates a project from a template schema
-func (c *Client) GenerateFromTemplate(ctx context.Context, schema *TemplateSchema, variables Variables) error {
+// GenerateFromTemplate creates a project from a template schema and reports the files written
+func (c *Client) GenerateFromTemplate(ctx context.Context, schema *TemplateSchema, variables Variables,
+) (*GenerateResult, error) {
 	if err := c.ValidateVariables(variables); err != nil {
-		return err
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, newGenerationError("GenerateFromTemplate", "generation cancelled", err)
 	}
 
 	if err := c.Validate(schema); err != nil {
-		return newSchemaError("GenerateFromTemplate", "invalid template schema", err)
+		return nil, newSchemaError("GenerateFromTemplate", "invalid template schema", err)
 	}
 
-	// Create temporary file for the schema
-	tempFile, err := os.CreateTemp("", "template-schema-*.json")
-	if err != nil {
-		return newFileSystemError("GenerateFromTemplate", "failed to create temporary file", err)
+	generator := c.newGenerator(ctx, schema, variables)
+
+	c.logger.Debug("Generating project", "schema", schema.Name, "output", variables.OutputDir)
+
+	if err := c.generate(ctx, "GenerateFromTemplate", generator, schema, variables); err != nil {
+		return nil, newGenerationError("GenerateFromTemplate", "failed to generate project", err)
 	}
-	defer os.Remove(tempFile.Name())
-	defer tempFile.Close()
 
-	// Marshal schema to JSON
-	schemaJSON, err := json.MarshalIndent(schema, "", "  ")
+	generator.PrintSummary()
+
+	return generator.Result(), nil
+}
+
+// GenerateToOutput generates a project from a template schema into out instead of the OS filesystem,
+// e.g. a MemFS or an adapter for object storage. variables.OutputDir is ignored.
+func (c *Client) GenerateToOutput(ctx context.Context, schema *TemplateSchema, variables Variables, out Output) error {
+	return c.generateToOutput(ctx, "GenerateToOutput", schema, variables, out)
+}
+
+// GenerateToWriter generates a project from a template schema and streams it to w as an archive
+// (variables.ArchiveFormat, tar.gz by default) without touching the disk. variables.OutputDir is ignored.
+func (c *Client) GenerateToWriter(ctx context.Context, schema *TemplateSchema, variables Variables, w io.Writer) error {
+	format := archive.FormatTarGz
+	if variables.ArchiveFormat != "" {
+		parsed, err := archive.ParseFormat(variables.ArchiveFormat)
+		if err != nil {
+			return newValidationError("GenerateToWriter", err.Error(), "")
+		}
+		format = parsed
+	}
+
+	archiveWriter, err := archive.NewWriter(w, format)
 	if err != nil {
-		return newSchemaError("GenerateFromTemplate", "failed to marshal schema to JSON", err)
+		return newGenerationError("GenerateToWriter", "failed to create archive", err)
 	}
 
-	// Write schema to temporary file
-	if _, err := tempFile.Write(schemaJSON); err != nil {
-		return newFileSystemError("GenerateFromTemplate", "failed to write schema file", err)
+	if err := c.generateToOutput(ctx, "GenerateToWriter", schema, variables, archiveWriter); err != nil {
+		return err
+	}
+	if err := archiveWriter.Close(); err != nil {
+		return newGenerationError("GenerateToWriter", "failed to finish archive", err)
 	}
-	tempFile.Close()
 
-	// Create generator (reuse existing logic)
-	generator, err := generate.NewGenerator(tempFile.Name(), variables.OutputDir,
-		variables.ProjectName, variables.GitHubRepo)
-	if err != nil {
-		return newGenerationError("GenerateFromTemplate", "failed to create generator", err)
+	return nil
+}
+
+// generateToOutput generates a project into out, reporting errors as operation
+func (c *Client) generateToOutput(ctx context.Context, operation string, schema *TemplateSchema,
+	variables Variables, out Output,
+) error {
+	if variables.ProjectName == "" {
+		return newValidationError(operation, "project name is required", "")
+	}
+	if variables.GitHubRepo == "" {
+		return newValidationError(operation, "github repo is required", "")
+	}
+	if err := validateOptions(operation, variables); err != nil {
+		return err
 	}
 
-	if err := generator.Generate(); err != nil {
-		return newGenerationError("GenerateFromTemplate", "failed to generate project", err)
+	if err := c.Validate(schema); err != nil {
+		return newSchemaError(operation, "invalid template schema", err)
+	}
+
+	generator := c.newGenerator(ctx, schema, variables)
+	generator.SetOutput(out)
+
+	c.logger.Debug("Generating project", "schema", schema.Name, "operation", operation)
+
+	if err := c.generate(ctx, operation, generator, schema, variables); err != nil {
+		return newGenerationError(operation, "failed to generate project", err)
 	}
 
 	return nil
 }
 
+// newGenerator creates a generator for schema writing to variables.OutputDir, configured with
+// the variables, the file filter, the package selection and the client's logger and hook options. The author defaults
+// to the git user.
+func (c *Client) newGenerator(ctx context.Context, schema *TemplateSchema, variables Variables) *generate.Generator {
+	author := variables.Author
+	if author == "" {
+		author = generate.DefaultAuthor(ctx)
+	}
+
+	generator := generate.NewGeneratorFromSchema(schema, core.TemplateVariables{
+		ProjectName: variables.ProjectName,
+		GitHubRepo:  variables.GitHubRepo,
+		Author:      author,
+		Description: variables.Description,
+		Custom:      variables.Custom,
+	}, variables.OutputDir)
+	generator.SetFileFilter(variables.FilterFiles)
+	generator.SetPackages(variables.Packages)
+	generator.SetFeatures(variables.EnableFeatures, variables.DisableFeatures)
+	generator.SetOverwritePolicy(variables.Overwrite)
+	generator.SetMaterializeSymlinks(variables.MaterializeSymlinks)
+	generator.SetLicense(variables.License)
+	generator.SetGoModOptions(variables.GoMod)
+	generator.SetWarnMissingMappings(variables.WarnMissingMappings)
+	generator.SetLogger(c.logger)
+	generator.SetHookOptions(c.hooks)
+	generator.SetVerify(variables.Verify)
+	generator.SetOffline(c.download.Offline)
+	generator.SetPortablePaths(variables.PortablePaths)
+	generator.SetPolicies(c.policies...)
+	return generator
+}
+
+// TestTemplate generates the schema once per test matrix entry into temporary directories and runs
+// its verification commands, see `template-engine test`. A failing entry is reported in its result,
+// not as an error. The programs allowed by WithHooks and WithRemoteHooks apply unless opts sets
+// them.
+func (c *Client) TestTemplate(ctx context.Context, schema *TemplateSchema, opts TestOptions) ([]TestResult, error) {
+	if err := c.Validate(schema); err != nil {
+		return nil, newSchemaError("TestTemplate", "invalid template schema", err)
+	}
+	opts.AllowRemote = opts.AllowRemote || c.hooks.AllowRemote
+	if len(opts.AllowedBinaries) == 0 {
+		opts.AllowedBinaries = c.hooks.AllowedBinaries
+	}
+
+	results, err := harness.Run(ctx, c.logger, schema, opts)
+	if err != nil {
+		return nil, newGenerationError("TestTemplate", "failed to run template tests", err)
+	}
+	return results, nil
+}
+
 // Validate checks if a template schema is valid
 func (c *Client) Validate(schema *TemplateSchema) error {
 	return core.ValidateSchema(schema)
@@ -168,44 +387,162 @@ func (c *Client) RegisterTemplate(templatePath string) error {
 		return newFileSystemError("RegisterTemplate", "failed to read template file", err)
 	}
 
-	var schema core.TemplateSchema
-	if err := json.Unmarshal(data, &schema); err != nil {
-		return newSchemaError("RegisterTemplate", "failed to parse template file", err)
+	return c.registerSchema("RegisterTemplate", "", templatePath, data)
+}
+
+// RegisterRemoteSchema downloads a template schema and registers it under its own name. source
+// is an http(s) URL or an OCI reference such as ghcr.io/org/templates/frontend:1.2.0. Failed
+// downloads are retried and interrupted ones resumed (see WithRetries and WithHTTPClient). The
+// schema is verified against digest (sha256:<hex>, the manifest digest for OCI references)
+// when not empty, or else against the digest the server publishes. The source is recorded as
+// the schema's origin, so its hooks never run without WithRemoteHooks.
+func (c *Client) RegisterRemoteSchema(ctx context.Context, source, digest string) error {
+	const operation = "RegisterRemoteSchema"
+
+	data, err := c.fetchSchema(ctx, source, digest)
+	if err != nil {
+		return newSchemaError(operation, "failed to download template schema", err)
 	}
+	schema, err := c.parseSchema(operation, "", data)
+	if err != nil {
+		return err
+	}
+	schema.Origin = source
 
-	// Validate the schema
-	if err := c.Validate(&schema); err != nil {
-		return newSchemaError("RegisterTemplate", "invalid template schema", err)
+	c.mu.Lock()
+	c.templates[schema.Name] = schema
+	c.mu.Unlock()
+	return nil
+}
+
+// fetchSchema downloads the schema at an http(s) URL or in an OCI artifact
+func (c *Client) fetchSchema(ctx context.Context, source, digest string) ([]byte, error) {
+	if strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://") {
+		opts := c.download
+		opts.Digest = digest
+		opts.Logger = c.logger
+		return download.Bytes(ctx, source, opts)
+	}
+
+	ref, err := oci.ParseReference(source)
+	if err != nil {
+		return nil, err
+	}
+	if digest != "" {
+		if ref.Digest != "" && ref.Digest != digest {
+			return nil, fmt.Errorf("%s does not have digest %s", source, digest)
+		}
+		ref.Digest = digest
+	}
+
+	client := oci.NewClient()
+	client.HTTPClient = c.download.Client
+	client.Retries = c.download.Retries
+	client.Backoff = c.download.Backoff
+	client.Offline = c.download.Offline
+	return client.Pull(ctx, ref)
+}
+
+// registerSchema parses, validates and registers a schema under name, or its own name when
+// name is empty, reporting errors as operation. path is the file data was read from, if any.
+func (c *Client) registerSchema(operation, name, path string, data []byte) error {
+	schema, err := c.parseSchema(operation, path, data)
+	if err != nil {
+		return err
+	}
+
+	if name == "" {
+		name = schema.Name
 	}
 
 	// Register the template using its name in the client's local cache
 	// This is separate from the global template type registry
-	c.templates[schema.Name] = &schema
+	c.mu.Lock()
+	c.templates[name] = schema
+	c.mu.Unlock()
 
 	return nil
 }
 
+// parseSchema parses and validates a schema, reporting errors as operation
+func (c *Client) parseSchema(operation, path string, data []byte) (*TemplateSchema, error) {
+	schema, err := core.ParseSchemaFile(path, data)
+	if err != nil {
+		return nil, newSchemaError(operation, "failed to parse template schema", err)
+	}
+
+	// Validate the schema
+	if err := c.Validate(schema); err != nil {
+		return nil, newSchemaError(operation, "invalid template schema", err)
+	}
+	return schema, nil
+}
+
 // ========================================
 // Template Types API (Built-in Extractors)
 // ========================================
 
-// ListTemplateTypes returns available built-in template types for extraction
+// ListTemplateTypes returns the sorted names of the template types available for extraction
 func (c *Client) ListTemplateTypes() []string {
-	return core.ListTemplates()
+	return c.registry.Names()
+}
+
+// Registry returns the registry the client resolves template types in: the process-wide one
+// unless the client was created with NewWithRegistry
+func (c *Client) Registry() *TemplateRegistry {
+	return c.registry
+}
+
+// RegisterTemplateType adds a template type to the client's registry, replacing any of the
+// same name
+func (c *Client) RegisterTemplateType(templateType TemplateType) {
+	c.registry.Register(templateType)
+}
+
+// ReplaceTemplateType swaps a template type of the client's registry for one of the same name
+func (c *Client) ReplaceTemplateType(templateType TemplateType) error {
+	if err := c.registry.Replace(templateType); err != nil {
+		return newTemplateTypeError("ReplaceTemplateType", templateType.Name())
+	}
+	return nil
+}
+
+// UnregisterTemplateType removes a template type from the client's registry
+func (c *Client) UnregisterTemplateType(name string) error {
+	if err := c.registry.Unregister(name); err != nil {
+		return newTemplateTypeError("UnregisterTemplateType", name)
+	}
+	return nil
+}
+
+// DeprecateTemplateType marks a template type of the client's registry as deprecated, naming
+// its successor if replacedBy is not empty
+func (c *Client) DeprecateTemplateType(name, replacedBy string) error {
+	if err := c.registry.Deprecate(name, core.Deprecation{ReplacedBy: replacedBy}); err != nil {
+		return newTemplateTypeError("DeprecateTemplateType", name)
+	}
+	return nil
 }
 
 // GetTemplateTypeInfo returns metadata for a built-in template type
 func (c *Client) GetTemplateTypeInfo(templateType string) (*TemplateTypeInfo, error) {
-	tmpl, err := core.GetTemplate(templateType)
+	tmpl, err := c.registry.Get(templateType)
 	if err != nil {
 		return nil, newTemplateTypeError("GetTemplateTypeInfo", templateType)
 	}
 
-	return &TemplateTypeInfo{
+	info := &TemplateTypeInfo{
 		Name:        tmpl.Name(),
-		Description: fmt.Sprintf("%s template type", tmpl.Name()),
-		Variables:   tmpl.GetVariables(), // Direct use since Variable = core.Variable
-	}, nil
+		Description: core.TypeDescription(tmpl),
+		Variables:   tmpl.GetVariables(), // Direct use since Variable = schema.Variable
+	}
+	info.Category, info.Tags = core.TypeMetadata(tmpl)
+	if deprecation, deprecated := c.registry.Deprecation(templateType); deprecated {
+		info.Deprecated = true
+		info.ReplacedBy = deprecation.ReplacedBy
+		info.DeprecationWarning = deprecation.Warning(templateType)
+	}
+	return info, nil
 }
 
 // ExtractSchema extracts a template schema from a source directory using a template type
@@ -217,7 +554,8 @@ func (c *Client) ExtractSchema(templateType, sourceDir string) (*TemplateSchema,
 }
 
 // ExtractAndGenerateFromType is a convenience method that extracts and generates in one step
-func (c *Client) ExtractAndGenerateFromType(templateType, sourceDir, projectName, githubRepo, outputDir string) error {
+func (c *Client) ExtractAndGenerateFromType(templateType, sourceDir, projectName, githubRepo, outputDir string,
+) (*GenerateResult, error) {
 	return c.ExtractAndGenerate(context.Background(), sourceDir, templateType, projectName, githubRepo, outputDir)
 }
 
@@ -230,8 +568,46 @@ func (c *Client) RegisterSchema(schemaFile string) error {
 	return c.RegisterTemplate(schemaFile) // Delegate to existing method
 }
 
+// RegisterSchemaFromBytes registers a JSON template schema held in memory, such as an embedded
+// asset, under name, or under the schema's own name when name is empty
+func (c *Client) RegisterSchemaFromBytes(name string, data []byte) error {
+	return c.registerSchema("RegisterSchemaFromBytes", name, "", data)
+}
+
+// RegisterSchemaFromReader registers a JSON template schema read from r, such as a request body,
+// under the schema's own name
+func (c *Client) RegisterSchemaFromReader(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return newFileSystemError("RegisterSchemaFromReader", "failed to read template schema", err)
+	}
+	return c.registerSchema("RegisterSchemaFromReader", "", "", data)
+}
+
+// UnregisterSchema removes a registered template schema
+func (c *Client) UnregisterSchema(schemaName string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.templates[schemaName]; !exists {
+		return newTemplateTypeError("UnregisterSchema", schemaName)
+	}
+	delete(c.templates, schemaName)
+	return nil
+}
+
+// ClearSchemas removes every registered template schema
+func (c *Client) ClearSchemas() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.templates)
+}
+
 // ListSchemas returns registered template schema names
 func (c *Client) ListSchemas() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	names := make([]string, 0, len(c.templates))
 	for name := range c.templates {
 		names = append(names, name)
@@ -239,38 +615,101 @@ func (c *Client) ListSchemas() []string {
 	return names
 }
 
+// lookupSchema returns the registered template schema named schemaName
+func (c *Client) lookupSchema(schemaName string) (*TemplateSchema, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	schema, exists := c.templates[schemaName]
+	return schema, exists
+}
+
+// findSchema returns the registered template schema named template, or else one of type template
+func (c *Client) findSchema(template string) (*TemplateSchema, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if schema, exists := c.templates[template]; exists {
+		return schema, true
+	}
+	for _, schema := range c.templates {
+		if schema.Type == template {
+			return schema, true
+		}
+	}
+	return nil, false
+}
+
 // GetSchemaInfo returns detailed information about a registered template schema
 func (c *Client) GetSchemaInfo(schemaName string) (*TemplateSchemaInfo, error) {
-	schema, exists := c.templates[schemaName]
+	schema, exists := c.lookupSchema(schemaName)
 	if !exists {
 		return nil, newTemplateTypeError("GetSchemaInfo", schemaName)
 	}
+	return schemaInfo(schema), nil
+}
 
+// schemaInfo describes a template schema
+func schemaInfo(schema *TemplateSchema) *TemplateSchemaInfo {
 	return &TemplateSchemaInfo{
 		Name:        schema.Name,
 		Type:        schema.Type,
 		Version:     schema.Version,
 		Description: schema.Description,
-		Variables:   schema.Variables, // Direct use since Variable = core.Variable
+		Tags:        schema.Tags,
+		Category:    schema.Category,
+		Variables:   schema.Variables, // Direct use since Variable = schema.Variable
 		FileCount:   len(schema.Files),
 		EnvVarCount: len(schema.EnvConfig),
-	}, nil
+
+		Deprecated:         schema.Deprecated,
+		ReplacedBy:         schema.ReplacedBy,
+		DeprecationWarning: schema.DeprecationWarning(),
+	}
 }
 
-// GetSchemaEnvConfig returns environment configuration for a registered template schema
-func (c *Client) GetSchemaEnvConfig(schemaName string) ([]EnvVariable, error) {
-	schema, exists := c.templates[schemaName]
+// SearchSchemas returns the registered template schemas matching query, sorted by name
+func (c *Client) SearchSchemas(query SchemaQuery) []TemplateSchemaInfo {
+	names := c.ListSchemas()
+	sort.Strings(names)
+
+	results := []TemplateSchemaInfo{}
+	for _, name := range names {
+		schema, exists := c.lookupSchema(name)
+		if !exists || !query.Matches(schema) {
+			continue // Unregistered meanwhile, or not matching
+		}
+		results = append(results, *schemaInfo(schema))
+	}
+	return results
+}
+
+// GetSchemaEnvConfig returns environment configuration for a registered template schema. With
+// components, only the variables of those components are returned (see EnvVariable.Component),
+// e.g. the frontend variables of a fullstack schema.
+func (c *Client) GetSchemaEnvConfig(schemaName string, components ...string) ([]EnvVariable, error) {
+	schema, exists := c.lookupSchema(schemaName)
 	if !exists {
 		return nil, newTemplateTypeError("GetSchemaEnvConfig", schemaName)
 	}
-	return schema.EnvConfig, nil
+	if len(components) == 0 {
+		return schema.EnvConfig, nil
+	}
+
+	envConfig := []EnvVariable{}
+	for _, variable := range schema.EnvConfig {
+		if slices.Contains(components, variable.Component) {
+			envConfig = append(envConfig, variable)
+		}
+	}
+	return envConfig, nil
 }
 
 // GenerateFromSchema generates a project from a registered template schema
-func (c *Client) GenerateFromSchema(ctx context.Context, schemaName string, variables Variables) error {
-	schema, exists := c.templates[schemaName]
+func (c *Client) GenerateFromSchema(ctx context.Context, schemaName string, variables Variables,
+) (*GenerateResult, error) {
+	schema, exists := c.lookupSchema(schemaName)
 	if !exists {
-		return newTemplateTypeError("GenerateFromSchema", schemaName)
+		return nil, newTemplateTypeError("GenerateFromSchema", schemaName)
 	}
 	return c.GenerateFromTemplate(ctx, schema, variables)
 }
@@ -278,11 +717,48 @@ func (c *Client) GenerateFromSchema(ctx context.Context, schemaName string, vari
 // Variables contains template variables
 type Variables struct {
 	ProjectName string
-	GitHubRepo  string
+	GitHubRepo  string // owner/repo, or a github.com URL of it reduced to owner/repo
 	OutputDir   string
-	Author      string
-	Description string
-	Custom      map[string]string
+	Author      string // Defaults to the git user ("Name <email>"), or "Developer" without one
+	Description string // Defaults to the schema default, or "A <project name> application"
+	// Custom sets the variables the schema declares beyond the built-in ones, unset ones take
+	// their schema default
+	Custom map[string]string
+	// FilterFiles generates only the schema files matching one of these globs (see core.MatchGlob),
+	// or below a directory named by one, e.g. []string{".github", "**/Dockerfile"}
+	FilterFiles []string
+	// Packages generates only these packages of a monorepo schema (see TemplateSchema.Packages),
+	// by package name or directory, dropping the dependencies on the others
+	Packages []string
+	// ArchiveFormat is the archive GenerateToWriter produces: tar.gz (default) or zip
+	ArchiveFormat string
+	// Overwrite set to OverwriteMerge leaves files already up to date in OutputDir untouched and
+	// counts the files created, updated and unchanged in the result
+	Overwrite OverwritePolicy
+	// MaterializeSymlinks writes copies of the files symlinks of the schema point to instead of
+	// recreating the symlinks; archives from GenerateToWriter keep them unless set
+	MaterializeSymlinks bool
+	// License writes this license to LICENSE, replacing the license files of the schema, and
+	// injects the schema's copyright header into source files (see TemplateSchema.Header)
+	License License
+	// GoMod raises the go directive of the schema's go.mod files and runs go mod tidy in them,
+	// see GoModOptions
+	GoMod GoModOptions
+	// WarnMissingMappings logs required mappings whose Find string is missing from their file and
+	// lists them in GenerateResult.MissingMappings, instead of failing generation
+	WarnMissingMappings bool
+	// EnableFeatures and DisableFeatures switch optional features of the schema (see
+	// TemplateSchema.Features) on and off by name; the others keep their default
+	EnableFeatures  []string
+	DisableFeatures []string
+	// Verify runs the verify hooks of the schema (e.g. go build ./...) once the project is
+	// written and reports them in GenerateResult.Verification; failing checks do not fail
+	// generation
+	Verify bool
+	// PortablePaths rejects output paths Windows cannot hold (reserved names such as CON or NUL,
+	// invalid characters, paths over 260 characters) on every OS, not only on Windows. Every such
+	// path is reported at once in an *InvalidPathsError before anything is written.
+	PortablePaths bool
 }
 
 // TemplateInfo represents template metadata and structure
@@ -293,18 +769,77 @@ type TemplateInfo struct {
 	Variables   map[string]Variable `json:"variables"`
 }
 
-// Type aliases to avoid repetitive conversions
+// Type aliases to avoid repetitive conversions. Schema types come from the public pkg/schema
+// package, whose helpers build, validate and compress schemas without going through files.
 type (
-	Variable       = core.Variable
-	EnvVariable    = core.EnvVariable
-	TemplateSchema = core.TemplateSchema
+	Variable       = schema.Variable
+	EnvVariable    = schema.EnvVariable
+	TemplateSchema = schema.Schema
+	SchemaFile     = schema.File
+	SchemaQuery    = core.SchemaQuery
+
+	// Output receives generated files, see GenerateToOutput and MemFS
+	Output = generate.Output
+	// SkippedFile is a file of the source left out of an extracted schema, with the reason
+	SkippedFile = core.SkippedFile
+	// GenerateResult lists the files a generation wrote, their size, the hook results and the duration
+	GenerateResult = generate.Result
+	// OverwritePolicy decides how generation treats files already in the output directory
+	OverwritePolicy = generate.OverwritePolicy
+	// License is a license generated projects are published under, see Variables.License
+	License = generate.License
+	// GoModOptions configure the rewrite of the go.mod files declared by a schema
+	GoModOptions = generate.GoModOptions
+	// InvalidPathsError lists the output paths that cannot be written, see errors.As and
+	// Variables.PortablePaths
+	InvalidPathsError = generate.InvalidPathsError
+	PathProblem       = generate.PathProblem
+
+	// Policy decides whether a generation request may proceed, see WithPolicies. PolicyRules is
+	// the declarative policy of LoadPolicy and RegoPolicy evaluates rego with the opa tool.
+	Policy               = policy.Policy
+	PolicyRequest        = policy.Request
+	PolicyViolation      = policy.Violation
+	PolicyViolationError = policy.ViolationError
+	PolicyRules          = policy.Rules
+	RegoPolicy           = policy.Rego
+
+	// TestOptions and TestResult configure and report TestTemplate runs
+	TestOptions = harness.Options
+	TestResult  = harness.CaseResult
+	TestCase    = schema.TestCase
+
+	// TemplateType extracts schemas from reference projects, see RegisterTemplateType.
+	// TypeExtractOptions and Mapping appear in its methods.
+	TemplateType       = core.TemplateType
+	TemplateRegistry   = core.TemplateRegistry
+	RegisteredType     = core.RegisteredType
+	TypeExtractOptions = core.ExtractOptions
+	Mapping            = schema.Mapping
+)
+
+// OverwriteMerge regenerates into an existing output directory, rewriting only changed files
+const OverwriteMerge = generate.OverwriteMerge
+
+// Licenses available for Variables.License
+const (
+	LicenseMIT         = generate.LicenseMIT
+	LicenseApache      = generate.LicenseApache
+	LicenseProprietary = generate.LicenseProprietary
 )
 
 // TemplateTypeInfo represents metadata for a built-in template type (extractor)
 type TemplateTypeInfo struct {
 	Name        string              `json:"name"`
 	Description string              `json:"description"`
+	Category    string              `json:"category,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
 	Variables   map[string]Variable `json:"variables"`
+
+	// Deprecated types still work; DeprecationWarning is a message to show users
+	Deprecated         bool   `json:"deprecated,omitempty"`
+	ReplacedBy         string `json:"replaced_by,omitempty"`
+	DeprecationWarning string `json:"deprecation_warning,omitempty"`
 }
 
 // TemplateSchemaInfo represents detailed information about a registered template schema
@@ -313,36 +848,55 @@ type TemplateSchemaInfo struct {
 	Type        string              `json:"type"`
 	Version     string              `json:"version"`
 	Description string              `json:"description"`
+	Tags        []string            `json:"tags,omitempty"`
+	Category    string              `json:"category,omitempty"`
 	Variables   map[string]Variable `json:"variables"`
 	FileCount   int                 `json:"file_count"`
 	EnvVarCount int                 `json:"env_var_count"`
+
+	// Deprecated schemas still generate; DeprecationWarning is a message to show users
+	Deprecated         bool   `json:"deprecated,omitempty"`
+	ReplacedBy         string `json:"replaced_by,omitempty"`
+	DeprecationWarning string `json:"deprecation_warning,omitempty"`
 }
 
 // ExtractAndGenerate extracts a template from a source directory and immediately generates a project
 // This is the main workflow method that combines extraction and generation in one step
 func (c *Client) ExtractAndGenerate(ctx context.Context, sourceDir, templateType,
 	projectName, githubRepo, outputDir string,
-) error {
+) (*GenerateResult, error) {
+	return c.ExtractAndGenerateWithVariables(ctx, sourceDir, templateType, Variables{
+		ProjectName: projectName,
+		GitHubRepo:  githubRepo,
+		OutputDir:   outputDir,
+	})
+}
+
+// ExtractAndGenerateWithVariables is ExtractAndGenerate with full control over the variables,
+// such as Author and Description
+func (c *Client) ExtractAndGenerateWithVariables(ctx context.Context, sourceDir, templateType string,
+	variables Variables,
+) (*GenerateResult, error) {
 	// Validate inputs
 	if sourceDir == "" {
-		return newValidationError("ExtractAndGenerate", "source directory is required", "")
+		return nil, newValidationError("ExtractAndGenerate", "source directory is required", "")
 	}
 	if templateType == "" {
-		return newValidationError("ExtractAndGenerate", "template type is required", "")
+		return nil, newValidationError("ExtractAndGenerate", "template type is required", "")
 	}
-	if projectName == "" {
-		return newValidationError("ExtractAndGenerate", "project name is required", "")
+	if variables.ProjectName == "" {
+		return nil, newValidationError("ExtractAndGenerate", "project name is required", "")
 	}
-	if githubRepo == "" {
-		return newValidationError("ExtractAndGenerate", "github repo is required", "")
+	if variables.GitHubRepo == "" {
+		return nil, newValidationError("ExtractAndGenerate", "github repo is required", "")
 	}
-	if outputDir == "" {
-		return newValidationError("ExtractAndGenerate", "output directory is required", "")
+	if variables.OutputDir == "" {
+		return nil, newValidationError("ExtractAndGenerate", "output directory is required", "")
 	}
 
 	// Check if source directory exists
 	if _, err := os.Stat(sourceDir); os.IsNotExist(err) {
-		return newFileSystemError("ExtractAndGenerate", "source directory does not exist", err)
+		return nil, newFileSystemError("ExtractAndGenerate", "source directory does not exist", err)
 	}
 
 	// Step 1: Extract template schema from source directory
@@ -351,51 +905,39 @@ func (c *Client) ExtractAndGenerate(ctx context.Context, sourceDir, templateType
 		Type:      templateType,
 	})
 	if err != nil {
-		return err // Error already wrapped by Extract method
+		return nil, err // Error already wrapped by Extract method
 	}
 
 	// Step 2: Generate project from extracted schema
-	variables := Variables{
-		ProjectName: projectName,
-		GitHubRepo:  githubRepo,
-		OutputDir:   outputDir,
-		Author:      "Developer", // Default value
-		Description: fmt.Sprintf("A %s application", projectName),
-	}
-
-	err = c.GenerateFromTemplate(ctx, schema, variables)
-	if err != nil {
-		return err // Error already wrapped by GenerateFromTemplate method
-	}
-
-	return nil
+	return c.GenerateFromTemplate(ctx, schema, variables)
 }
 
 // GenerateFromFile loads a template schema from a file and generates a project
 // This is a convenience method for when you already have a template.json file
-func (c *Client) GenerateFromFile(ctx context.Context, templateFile string, variables Variables) error {
+func (c *Client) GenerateFromFile(ctx context.Context, templateFile string, variables Variables,
+) (*GenerateResult, error) {
 	if err := c.ValidateVariables(variables); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Check if template file exists
 	if _, err := os.Stat(templateFile); os.IsNotExist(err) {
-		return newFileSystemError("GenerateFromFile", "template file does not exist", err)
+		return nil, newFileSystemError("GenerateFromFile", "template file does not exist", err)
 	}
 
 	// Load template schema from file
 	data, err := os.ReadFile(templateFile)
 	if err != nil {
-		return newFileSystemError("GenerateFromFile", "failed to read template file", err)
+		return nil, newFileSystemError("GenerateFromFile", "failed to read template file", err)
 	}
 
-	var schema core.TemplateSchema
-	if err := json.Unmarshal(data, &schema); err != nil {
-		return newSchemaError("GenerateFromFile", "failed to parse template file", err)
+	schema, err := core.ParseSchemaFile(templateFile, data)
+	if err != nil {
+		return nil, newSchemaError("GenerateFromFile", "failed to parse template file", err)
 	}
 
 	// Generate from the loaded schema
-	return c.GenerateFromTemplate(ctx, &schema, variables)
+	return c.GenerateFromTemplate(ctx, schema, variables)
 }
 
 // ValidateGenerateOptions validates GenerateOptions
@@ -414,15 +956,22 @@ func (c *Client) ValidateGenerateOptions(opts GenerateOptions) error {
 
 // ValidateExtractOptions validates ExtractOptions
 func (c *Client) ValidateExtractOptions(opts ExtractOptions) error {
-	if opts.SourceDir == "" {
+	if opts.SourceDir == "" && opts.FS == nil {
 		return newValidationError("Extract", "source directory is required", "")
 	}
 	if opts.Type == "" {
 		return newValidationError("Extract", "template type is required", "")
 	}
+	if opts.Codec != "" {
+		if _, err := core.ParseCodec(opts.Codec); err != nil {
+			return newValidationError("Extract", err.Error(), "")
+		}
+	}
 	// Check if source directory exists
-	if _, err := os.Stat(opts.SourceDir); os.IsNotExist(err) {
-		return newFileSystemError("Extract", "source directory does not exist", err)
+	if opts.FS == nil {
+		if _, err := os.Stat(opts.SourceDir); os.IsNotExist(err) {
+			return newFileSystemError("Extract", "source directory does not exist", err)
+		}
 	}
 	return nil
 }
@@ -438,5 +987,17 @@ func (c *Client) ValidateVariables(variables Variables) error {
 	if variables.OutputDir == "" {
 		return newValidationError("GenerateFromTemplate", "output directory is required", "")
 	}
+	return validateOptions("GenerateFromTemplate", variables)
+}
+
+// validateOptions checks the license and Go version of variables, which every generation
+// consumes whatever its output
+func validateOptions(operation string, variables Variables) error {
+	if _, err := generate.ParseLicense(string(variables.License)); err != nil {
+		return newValidationError(operation, "invalid license", err.Error())
+	}
+	if _, err := generate.ParseGoVersion(variables.GoMod.GoVersion); err != nil {
+		return newValidationError(operation, "invalid Go version", err.Error())
+	}
 	return nil
 }
@@ -1,11 +1,26 @@
 package sdk
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/acheevo/template-engine/internal/core"
 	_ "github.com/acheevo/template-engine/internal/templates" // Register template types
@@ -322,7 +337,7 @@ func TestGenerateFromFile(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := client.GenerateFromFile(context.Background(), tt.templateFile, tt.variables)
+			_, err := client.GenerateFromFile(context.Background(), tt.templateFile, tt.variables)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GenerateFromFile() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -380,7 +395,9 @@ func TestListTemplates(t *testing.T) {
 	templateTypes := client.ListTemplateTypes()
 
 	// Should contain the registered template types
-	expectedTypes := map[string]bool{testTemplateFrontend: true, "go-api": true, "fullstack": true}
+	expectedTypes := map[string]bool{testTemplateFrontend: true, "go-api": true, "fullstack": true, "go-cli": true,
+		"go-library": true, "python-api": true, "infra": true,
+		"helm-chart": true, "monorepo": true, "generic": true}
 	if len(templateTypes) != len(expectedTypes) {
 		t.Errorf("Expected %d template types, got %d", len(expectedTypes), len(templateTypes))
 	}
@@ -699,6 +716,13 @@ func TestTemplateTypesAPI(t *testing.T) {
 			testTemplateFrontend: false,
 			"go-api":             false,
 			"fullstack":          false,
+			"go-cli":             false,
+			"go-library":         false,
+			"python-api":         false,
+			"infra":              false,
+			"helm-chart":         false,
+			"monorepo":           false,
+			"generic":            false,
 		}
 
 		for _, templateType := range types {
@@ -895,10 +919,16 @@ func TestTemplateSchemasAPI(t *testing.T) {
 			OutputDir:   outputDir,
 		}
 
-		err := client.GenerateFromSchema(context.Background(), "test-template", variables)
+		result, err := client.GenerateFromSchema(context.Background(), "test-template", variables)
 		if err != nil {
 			t.Fatalf("GenerateFromSchema failed: %v", err)
 		}
+		if result.OutputDir != outputDir || result.FileCount != 1 || result.Files[0] != "package.json" {
+			t.Errorf("GenerateFromSchema() result = %+v, want package.json in %s", result, outputDir)
+		}
+		if result.BytesWritten == 0 {
+			t.Error("Expected the result to count the bytes written")
+		}
 
 		// Verify the generated file exists
 		generatedFile := filepath.Join(outputDir, "package.json")
@@ -1014,6 +1044,29 @@ func TestTypeAliases(t *testing.T) {
 	})
 }
 
+func TestGetSchemaEnvConfigComponents(t *testing.T) {
+	client := New()
+	client.templates["fullstack"] = &core.TemplateSchema{
+		Name: "fullstack",
+		EnvConfig: []core.EnvVariable{
+			{Name: "DB_HOST", Component: "api"},
+			{Name: "VITE_API_URL", Component: "frontend"},
+		},
+	}
+
+	envConfig, err := client.GetSchemaEnvConfig("fullstack", "frontend")
+	if err != nil {
+		t.Fatalf("GetSchemaEnvConfig() error = %v", err)
+	}
+	if len(envConfig) != 1 || envConfig[0].Name != "VITE_API_URL" {
+		t.Errorf("GetSchemaEnvConfig(frontend) = %+v", envConfig)
+	}
+
+	if envConfig, _ := client.GetSchemaEnvConfig("fullstack"); len(envConfig) != 2 {
+		t.Errorf("GetSchemaEnvConfig() = %+v, want every component", envConfig)
+	}
+}
+
 func TestGetTemplateEnvConfigEmptyConfig(t *testing.T) {
 	client := New()
 
@@ -1043,3 +1096,600 @@ func TestGetTemplateEnvConfigEmptyConfig(t *testing.T) {
 		t.Errorf("GetSchemaEnvConfig() returned %d env vars, expected 0", len(envConfig))
 	}
 }
+
+func TestGenerateToWriter(t *testing.T) {
+	client := New()
+
+	schema := &core.TemplateSchema{
+		Name:    "test-template",
+		Type:    "frontend",
+		Version: "1.0.0",
+		Variables: map[string]core.Variable{
+			"ProjectName": {Type: "string", Required: true},
+			"GitHubRepo":  {Type: "string", Required: true},
+		},
+		Files: []core.FileSpec{
+			{Path: "src/README.md", Template: true, Content: "# {{.ProjectName}}"},
+		},
+	}
+	variables := Variables{ProjectName: "test-project", GitHubRepo: "user/test-repo", ArchiveFormat: "zip"}
+
+	var buf bytes.Buffer
+	if err := client.GenerateToWriter(context.Background(), schema, variables, &buf); err != nil {
+		t.Fatalf("GenerateToWriter failed: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Output is not a zip archive: %v", err)
+	}
+	if len(zr.File) != 1 || zr.File[0].Name != "src/README.md" {
+		t.Fatalf("Unexpected archive entries: %v", zr.File)
+	}
+	rc, err := zr.File[0].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	content, _ := io.ReadAll(rc)
+	if string(content) != "# test-project" {
+		t.Errorf("Expected rendered README, got %q", content)
+	}
+
+	variables.ArchiveFormat = "rar"
+	err = client.GenerateToWriter(context.Background(), schema, variables, io.Discard)
+	if sdkErr, ok := err.(*SDKError); !ok || sdkErr.Type != ErrorTypeValidation {
+		t.Errorf("Expected validation error for unknown format, got %v", err)
+	}
+}
+
+func TestExtractFromFS(t *testing.T) {
+	client := New()
+
+	source := NewMemFS()
+	files := map[string]string{
+		"package.json": `{"name": "frontend-template"}`,
+		"src/App.tsx":  "export default function App() {}",
+		".env.example": "# API base URL\nAPI_URL=http://localhost:8080",
+	}
+	for name, content := range files {
+		if err := source.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	schema, err := client.Extract(context.Background(), ExtractOptions{Type: testTemplateFrontend, FS: source})
+	if err != nil {
+		t.Fatalf("Extract from FS failed: %v", err)
+	}
+
+	if len(schema.Files) != 3 {
+		t.Errorf("Expected 3 files, got %d", len(schema.Files))
+	}
+	if len(schema.EnvConfig) != 1 || schema.EnvConfig[0].Name != "API_URL" {
+		t.Errorf("Expected API_URL env variable, got %+v", schema.EnvConfig)
+	}
+}
+
+func TestExtractWithDiagnostics(t *testing.T) {
+	source := NewMemFS()
+	files := map[string]string{
+		"package.json":          `{"name": "frontend-template"}`,
+		"node_modules/react.js": "module.exports = {}",
+		"public/favicon.ico":    "\x00\x00\x01\x00",
+	}
+	for name, content := range files {
+		if err := source.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opts := ExtractOptions{Type: testTemplateFrontend, FS: source}
+	result, err := New().ExtractWithDiagnostics(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("ExtractWithDiagnostics failed: %v", err)
+	}
+
+	// Binary files are embedded base64 encoded rather than skipped
+	if len(result.Schema.Files) != 2 {
+		t.Errorf("Expected 2 files, got %d", len(result.Schema.Files))
+	}
+	for _, file := range result.Schema.Files {
+		if filepath.ToSlash(file.Path) == "public/favicon.ico" && (file.Encoding != "base64" || file.Template) {
+			t.Errorf("favicon.ico = %+v, want a base64 encoded static file", file)
+		}
+	}
+	reasons := map[string]string{}
+	for _, skipped := range result.Skipped {
+		reasons[filepath.ToSlash(skipped.Path)] = skipped.Reason
+	}
+	if len(reasons) != 1 || reasons["node_modules/react.js"] != "matched skip dir node_modules" {
+		t.Errorf("Unexpected skipped files: %+v", result.Skipped)
+	}
+}
+
+func TestTestTemplate(t *testing.T) {
+	client := New()
+
+	schema := &core.TemplateSchema{
+		Name:    "test-template",
+		Type:    "frontend",
+		Version: "1.0.0",
+		Variables: map[string]core.Variable{
+			"ProjectName": {Type: "string", Required: true},
+		},
+		Files: []core.FileSpec{
+			{Path: "README.md", Template: true, Content: "# {{.ProjectName}}"},
+		},
+		TestMatrix: []TestCase{
+			{Name: "named", Variables: map[string]string{"ProjectName": "Named App"}},
+		},
+		TestCommands: []string{"grep -q 'Named App' README.md"},
+	}
+
+	results, err := client.TestTemplate(context.Background(), schema, TestOptions{})
+	if err != nil {
+		t.Fatalf("TestTemplate failed: %v", err)
+	}
+	if len(results) != 1 || !results[0].Passed {
+		t.Errorf("Expected the named case to pass, got %+v", results)
+	}
+}
+
+func TestGenerateAuthorAndDescription(t *testing.T) {
+	client := New()
+
+	schema := &core.TemplateSchema{
+		Name:      "test-template",
+		Type:      "frontend",
+		Version:   "1.0.0",
+		Variables: map[string]core.Variable{},
+		Files: []core.FileSpec{
+			{Path: "README.md", Template: true, Content: "{{.Description}} by {{.Author}}"},
+		},
+	}
+
+	memfs := NewMemFS()
+	variables := Variables{
+		ProjectName: "test-project",
+		GitHubRepo:  "user/test-repo",
+		Author:      "Jane Doe <jane@example.com>",
+		Description: "Billing service",
+	}
+	if err := client.GenerateToOutput(context.Background(), schema, variables, memfs); err != nil {
+		t.Fatalf("GenerateToOutput failed: %v", err)
+	}
+
+	content, err := memfs.ReadFile("README.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "Billing service by Jane Doe <jane@example.com>" {
+		t.Errorf("Expected author and description in README, got %q", content)
+	}
+}
+
+func TestGenerateFilterFiles(t *testing.T) {
+	client := New()
+
+	schema := &core.TemplateSchema{
+		Name:      "test-template",
+		Type:      "frontend",
+		Version:   "1.0.0",
+		Variables: map[string]core.Variable{},
+		Files: []core.FileSpec{
+			{Path: "README.md", Content: "readme"},
+			{Path: ".github/workflows/ci.yml", Content: "ci"},
+		},
+	}
+
+	memfs := NewMemFS()
+	variables := Variables{ProjectName: "test-project", GitHubRepo: "user/test-repo", FilterFiles: []string{".github"}}
+	if err := client.GenerateToOutput(context.Background(), schema, variables, memfs); err != nil {
+		t.Fatalf("GenerateToOutput failed: %v", err)
+	}
+
+	if got := memfs.Files(); len(got) != 1 || got[0] != ".github/workflows/ci.yml" {
+		t.Errorf("Expected only the CI workflow, got %v", got)
+	}
+}
+
+func TestGenerateWithHooks(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	schema := &core.TemplateSchema{
+		Name:      "test-template",
+		Type:      "frontend",
+		Version:   "1.0.0",
+		Variables: map[string]core.Variable{},
+		Files:     []core.FileSpec{{Path: "README.md", Content: "readme"}},
+		Hooks:     core.Hooks{{Stage: core.HookPostGenerate, Command: "echo $TE_VAR_RepoName > hook.txt"}},
+		Origin:    "https://templates.example.com/schemas/frontend",
+	}
+	variables := Variables{ProjectName: "test-project", GitHubRepo: "user/test-repo"}
+
+	variables.OutputDir = filepath.Join(t.TempDir(), "project")
+	if _, err := New().GenerateFromTemplate(context.Background(), schema, variables); err != nil {
+		t.Fatalf("GenerateFromTemplate failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(variables.OutputDir, "hook.txt")); !os.IsNotExist(err) {
+		t.Error("Expected hooks not to run by default")
+	}
+
+	// The schema was pulled from a registry, so its hooks only run with WithRemoteHooks
+	variables.OutputDir = filepath.Join(t.TempDir(), "project")
+	if _, err := New(WithHooks()).GenerateFromTemplate(context.Background(), schema, variables); err != nil {
+		t.Fatalf("GenerateFromTemplate with hooks failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(variables.OutputDir, "hook.txt")); !os.IsNotExist(err) {
+		t.Error("Expected the hooks of a remote schema not to run without WithRemoteHooks")
+	}
+
+	variables.OutputDir = filepath.Join(t.TempDir(), "project")
+	result, err := New(WithRemoteHooks(), WithHooks()).GenerateFromTemplate(context.Background(), schema, variables)
+	if err != nil {
+		t.Fatalf("GenerateFromTemplate with hooks failed: %v", err)
+	}
+	if len(result.Hooks) != 1 {
+		t.Errorf("Expected the result to report 1 hook, got %+v", result.Hooks)
+	}
+	content, err := os.ReadFile(filepath.Join(variables.OutputDir, "hook.txt"))
+	if err != nil || string(content) != "test-repo\n" {
+		t.Errorf("Expected the hook to write the repo name, got %q (%v)", content, err)
+	}
+}
+
+func TestSchemaDeprecation(t *testing.T) {
+	client := New()
+
+	schema := &core.TemplateSchema{
+		Name:       "legacy-template",
+		Type:       testTemplateFrontend,
+		Version:    "1.0.0",
+		Variables:  map[string]core.Variable{},
+		Files:      []core.FileSpec{{Path: "README.md", Content: "readme"}},
+		Deprecated: true,
+		ReplacedBy: "modern-template",
+	}
+	schemaFile := filepath.Join(t.TempDir(), "legacy-template.json")
+	if err := core.SaveSchemaFile(schema, schemaFile); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.RegisterSchema(schemaFile); err != nil {
+		t.Fatalf("RegisterSchema failed: %v", err)
+	}
+
+	info, err := client.GetSchemaInfo("legacy-template")
+	if err != nil {
+		t.Fatalf("GetSchemaInfo failed: %v", err)
+	}
+	if !info.Deprecated || info.ReplacedBy != "modern-template" {
+		t.Errorf("Expected deprecation in schema info, got %+v", info)
+	}
+	expected := "template schema legacy-template is deprecated, use modern-template instead"
+	if info.DeprecationWarning != expected {
+		t.Errorf("DeprecationWarning = %q, want %q", info.DeprecationWarning, expected)
+	}
+
+	typeInfo, err := client.GetTemplateTypeInfo(testTemplateFrontend)
+	if err != nil {
+		t.Fatalf("GetTemplateTypeInfo failed: %v", err)
+	}
+	if typeInfo.Deprecated || typeInfo.DeprecationWarning != "" {
+		t.Errorf("Expected %s not to be deprecated, got %+v", testTemplateFrontend, typeInfo)
+	}
+	if typeInfo.Category != core.CategoryFrontend || len(typeInfo.Tags) == 0 {
+		t.Errorf("Expected the frontend category and tags, got %+v", typeInfo)
+	}
+}
+
+func TestSearchSchemas(t *testing.T) {
+	client := New()
+
+	schemas := []*core.TemplateSchema{
+		{Name: "react-app", Type: testTemplateFrontend, Description: "React SPA", Tags: []string{"react"}},
+		{Name: "vue-app", Type: testTemplateFrontend, Description: "Vue SPA", Tags: []string{"vue"}},
+		{Name: "billing-api", Type: "go-api", Description: "Billing service for the React dashboard"},
+	}
+	for _, schema := range schemas {
+		schema.Version = "1.0.0"
+		schema.Variables = map[string]core.Variable{}
+		schema.Files = []core.FileSpec{{Path: "README.md", Content: "readme"}}
+
+		schemaFile := filepath.Join(t.TempDir(), schema.Name+".json")
+		if err := core.SaveSchemaFile(schema, schemaFile); err != nil {
+			t.Fatal(err)
+		}
+		if err := client.RegisterSchema(schemaFile); err != nil {
+			t.Fatalf("RegisterSchema failed: %v", err)
+		}
+	}
+
+	names := func(infos []TemplateSchemaInfo) []string {
+		result := []string{}
+		for _, info := range infos {
+			result = append(result, info.Name)
+		}
+		return result
+	}
+
+	got := names(client.SearchSchemas(SchemaQuery{Search: "react"}))
+	if strings.Join(got, ",") != "billing-api,react-app" {
+		t.Errorf("Search react = %v, want [billing-api react-app]", got)
+	}
+	got = names(client.SearchSchemas(SchemaQuery{Search: "react", Type: testTemplateFrontend}))
+	if strings.Join(got, ",") != "react-app" {
+		t.Errorf("Search react in frontend = %v, want [react-app]", got)
+	}
+	if got := client.SearchSchemas(SchemaQuery{Tags: []string{"vue"}}); len(got) != 1 || got[0].Tags[0] != "vue" {
+		t.Errorf("Search tag vue = %+v", got)
+	}
+	if got := client.SearchSchemas(SchemaQuery{Search: "angular"}); len(got) != 0 {
+		t.Errorf("Expected no results, got %v", names(got))
+	}
+}
+
+// tenantType is a template type registered by a single tenant
+type tenantType struct {
+	TemplateType
+	name string
+}
+
+func (t tenantType) Name() string                      { return t.name }
+func (t tenantType) GetVariables() map[string]Variable { return nil }
+
+func TestNewWithRegistry(t *testing.T) {
+	tenantA := NewWithRegistry(NewTemplateRegistry())
+	tenantB := NewWithRegistry(NewTemplateRegistry())
+
+	tenantA.RegisterTemplateType(tenantType{name: "billing-api"})
+	if _, err := tenantA.GetTemplateTypeInfo("billing-api"); err != nil {
+		t.Errorf("tenant A GetTemplateTypeInfo(billing-api) error = %v", err)
+	}
+	if _, err := tenantB.GetTemplateTypeInfo("billing-api"); err == nil {
+		t.Error("tenant B sees a template type registered by tenant A")
+	}
+	if _, err := core.GetTemplate("billing-api"); err == nil {
+		t.Error("the global registry sees a template type registered by a client")
+	}
+
+	// Both registries start with the built-in types and change independently
+	if err := tenantB.UnregisterTemplateType(testTemplateFrontend); err != nil {
+		t.Fatalf("UnregisterTemplateType() error = %v", err)
+	}
+	if _, err := tenantA.GetTemplateTypeInfo(testTemplateFrontend); err != nil {
+		t.Errorf("tenant A lost %s: %v", testTemplateFrontend, err)
+	}
+	_, err := tenantB.Extract(context.Background(), ExtractOptions{SourceDir: "/tmp", Type: testTemplateFrontend})
+	if err == nil {
+		t.Errorf("tenant B extracted with unregistered %s", testTemplateFrontend)
+	}
+
+	if err := tenantA.DeprecateTemplateType("billing-api", "go-api"); err != nil {
+		t.Fatalf("DeprecateTemplateType() error = %v", err)
+	}
+	if info, _ := tenantA.GetTemplateTypeInfo("billing-api"); !info.Deprecated || info.ReplacedBy != "go-api" {
+		t.Errorf("GetTemplateTypeInfo(billing-api) = %+v, want deprecated", info)
+	}
+	if err := tenantB.ReplaceTemplateType(tenantType{name: "billing-api"}); err == nil {
+		t.Error("ReplaceTemplateType() should reject types the registry does not hold")
+	}
+
+	if NewWithRegistry(nil).Registry() != core.GlobalRegistry {
+		t.Error("NewWithRegistry(nil) should use the global registry")
+	}
+}
+
+func TestSchemaRegistrationConcurrency(t *testing.T) {
+	client := New()
+	schema := &core.TemplateSchema{
+		Name:      "service",
+		Type:      "go-api",
+		Version:   "1.0.0",
+		Variables: map[string]core.Variable{},
+		Files:     []core.FileSpec{{Path: "README.md", Content: "readme"}},
+	}
+	schemaFile := filepath.Join(t.TempDir(), "service.json")
+	if err := core.SaveSchemaFile(schema, schemaFile); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.RegisterSchema(schemaFile); err != nil {
+		t.Fatalf("RegisterSchema failed: %v", err)
+	}
+
+	baseDir := t.TempDir()
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := client.RegisterSchema(schemaFile); err != nil {
+				t.Errorf("RegisterSchema failed: %v", err)
+			}
+			_, err := client.GenerateFromSchema(context.Background(), "service", Variables{
+				ProjectName: "service", GitHubRepo: "acme/service", OutputDir: filepath.Join(baseDir, strconv.Itoa(i)),
+			})
+			if err != nil {
+				t.Errorf("GenerateFromSchema failed: %v", err)
+			}
+			client.SearchSchemas(SchemaQuery{Search: "service"})
+		}()
+	}
+	wg.Wait()
+
+	if err := client.UnregisterSchema("service"); err != nil {
+		t.Fatalf("UnregisterSchema failed: %v", err)
+	}
+	if err := client.UnregisterSchema("service"); err == nil {
+		t.Error("Expected an error unregistering an unknown schema")
+	}
+	if err := client.RegisterSchema(schemaFile); err != nil {
+		t.Fatalf("RegisterSchema failed: %v", err)
+	}
+	client.ClearSchemas()
+	if names := client.ListSchemas(); len(names) != 0 {
+		t.Errorf("Expected no schemas after ClearSchemas, got %v", names)
+	}
+}
+
+func TestRegisterSchemaFromBytes(t *testing.T) {
+	client := New()
+	schema := &core.TemplateSchema{
+		Name:      "service",
+		Type:      "go-api",
+		Version:   "1.0.0",
+		Variables: map[string]core.Variable{},
+		Files:     []core.FileSpec{{Path: "README.md", Content: "readme"}},
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.RegisterSchemaFromBytes("embedded-service", data); err != nil {
+		t.Fatalf("RegisterSchemaFromBytes failed: %v", err)
+	}
+	if err := client.RegisterSchemaFromReader(bytes.NewReader(data)); err != nil {
+		t.Fatalf("RegisterSchemaFromReader failed: %v", err)
+	}
+	names := client.ListSchemas()
+	sort.Strings(names)
+	if strings.Join(names, ",") != "embedded-service,service" {
+		t.Errorf("Expected embedded-service and service, got %v", names)
+	}
+
+	if err := client.RegisterSchemaFromBytes("broken", []byte("{")); err == nil {
+		t.Error("Expected an error registering invalid JSON")
+	}
+	if err := client.RegisterSchemaFromReader(strings.NewReader(`{"name":"empty"}`)); err == nil {
+		t.Error("Expected an error registering an invalid schema")
+	}
+}
+
+func TestRegisterRemoteSchema(t *testing.T) {
+	schema := &core.TemplateSchema{
+		Name:      "remote-service",
+		Type:      "go-api",
+		Version:   "1.0.0",
+		Variables: map[string]core.Variable{},
+		Files:     []core.FileSpec{{Path: "README.md", Content: "readme"}},
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	client := New(WithHTTPClient(server.Client()), WithRetries(2, time.Millisecond))
+	ctx := context.Background()
+	if err := client.RegisterRemoteSchema(ctx, server.URL+"/schema.json", ""); err != nil {
+		t.Fatalf("RegisterRemoteSchema failed: %v", err)
+	}
+	registered, exists := client.lookupSchema("remote-service")
+	if !exists || registered.Origin != server.URL+"/schema.json" {
+		t.Fatalf("Expected remote-service registered with its URL as origin, got %+v", registered)
+	}
+
+	sum := sha256.Sum256(data)
+	if err := client.RegisterRemoteSchema(ctx, server.URL, "sha256:"+hex.EncodeToString(sum[:])); err != nil {
+		t.Errorf("RegisterRemoteSchema with the right digest failed: %v", err)
+	}
+	if err := client.RegisterRemoteSchema(ctx, server.URL, "sha256:"+strings.Repeat("0", 64)); err == nil {
+		t.Error("Expected an error registering a schema with a wrong digest")
+	}
+
+	offline := New(WithOffline())
+	for _, source := range []string{server.URL, "ghcr.io/org/templates/service:1.0.0"} {
+		if err := offline.RegisterRemoteSchema(ctx, source, ""); !errors.Is(err, ErrOffline) {
+			t.Errorf("RegisterRemoteSchema(%s) offline error = %v, want ErrOffline", source, err)
+		}
+	}
+}
+
+func TestGenerateFromTemplateWithoutTempFiles(t *testing.T) {
+	// Generation works from the schema in memory, an unusable temp directory does not matter
+	t.Setenv("TMPDIR", filepath.Join(t.TempDir(), "missing"))
+
+	schema := &core.TemplateSchema{
+		Name:      "service",
+		Type:      "go-api",
+		Version:   "1.0.0",
+		Variables: map[string]core.Variable{},
+		Files:     []core.FileSpec{{Path: "README.md", Template: true, Content: "# {{.ProjectName}}"}},
+	}
+	outputDir := filepath.Join(t.TempDir(), "service")
+
+	result, err := New().GenerateFromTemplate(context.Background(), schema, Variables{
+		ProjectName: "service", GitHubRepo: "acme/service", OutputDir: outputDir,
+	})
+	if err != nil {
+		t.Fatalf("GenerateFromTemplate failed: %v", err)
+	}
+	if result.FileCount != 1 {
+		t.Errorf("Expected 1 file, got %+v", result)
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	var log bytes.Buffer
+	client := New(WithAuditLog(&log), WithAuditUser("jane"))
+
+	schema := &core.TemplateSchema{
+		Name:    "service",
+		Type:    "go-api",
+		Version: "1.2.0",
+		Variables: map[string]core.Variable{
+			"Team":     {Type: "string"},
+			"ApiToken": {Type: "string"},
+		},
+		Files: []core.FileSpec{{Path: "README.md", Template: true, Content: "# {{.ProjectName}} ({{.Team}})"}},
+	}
+	outputDir := filepath.Join(t.TempDir(), "service")
+	variables := Variables{
+		ProjectName: "service", GitHubRepo: "https://github.com/acme/service", OutputDir: outputDir,
+		Custom: map[string]string{"Team": "payments", "ApiToken": "s3cr3t"},
+	}
+	ctx := context.Background()
+	if _, err := client.GenerateFromTemplate(ctx, schema, variables); err != nil {
+		t.Fatalf("GenerateFromTemplate failed: %v", err)
+	}
+	// Failed generations are recorded as well
+	rejecting := New(WithAuditLog(&log), WithPolicies(&PolicyRules{AllowedOwners: []string{"other"}}))
+	if _, err := rejecting.GenerateFromTemplate(ctx, schema, variables); err == nil {
+		t.Fatal("Expected the policy to reject the generation")
+	}
+
+	if strings.Contains(log.String(), "s3cr3t") {
+		t.Errorf("Audit log leaks a secret: %s", log.String())
+	}
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 audit records, got %d: %s", len(lines), log.String())
+	}
+	var record AuditRecord
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.User != "jane" || record.Operation != "GenerateFromTemplate" || record.Schema != "service" ||
+		record.Version != "1.2.0" || record.GitHubRepo != "acme/service" || record.OutputDir != outputDir ||
+		record.Files != 1 || record.Error != "" || record.Time.IsZero() {
+		t.Errorf("Unexpected audit record %+v", record)
+	}
+	if record.Variables["Team"] != "payments" || record.Variables["ApiToken"] != "[REDACTED]" {
+		t.Errorf("Audit record variables = %v", record.Variables)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &record); err != nil || record.Error == "" {
+		t.Errorf("Expected the failed generation to be recorded with its error, got %+v, %v", record, err)
+	}
+}
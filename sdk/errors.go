@@ -1,6 +1,10 @@
 package sdk
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/acheevo/template-engine/internal/download"
+)
 
 // ErrorType represents different categories of SDK errors
 type ErrorType string
@@ -14,6 +18,10 @@ const (
 	ErrorTypeSchema       ErrorType = "schema"
 )
 
+// ErrOffline is wrapped by the errors of network access attempted by a client created
+// WithOffline, see errors.Is
+var ErrOffline = download.ErrOffline
+
 // SDKError provides structured error information for SDK operations
 type SDKError struct {
 	Type       ErrorType `json:"type"`
@@ -0,0 +1,206 @@
+package sdk
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemFS is an in-memory generation target. It implements Output for GenerateToOutput
+// and fs.FS for reading the generated project back, e.g. in tests or before uploading
+// the files to object storage.
+type MemFS struct {
+	mu    sync.RWMutex
+	files map[string]*memFileData
+}
+
+type memFileData struct {
+	data    []byte
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+// NewMemFS creates an empty in-memory filesystem
+func NewMemFS() *MemFS {
+	return &MemFS{files: make(map[string]*memFileData)}
+}
+
+// WriteFile stores a file, replacing any previous content. name is a slash-separated relative path.
+func (m *MemFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.isDir(name) {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrExist}
+	}
+	m.files[name] = &memFileData{data: append([]byte(nil), data...), mode: perm.Perm(), modTime: time.Now()}
+	return nil
+}
+
+// Files returns the paths of all stored files, sorted
+func (m *MemFS) Files() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make([]string, 0, len(m.files))
+	for name := range m.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ReadFile returns the content of a stored file
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	file, exists := m.files[name]
+	if !exists {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+	return append([]byte(nil), file.data...), nil
+}
+
+// Open implements fs.FS
+func (m *MemFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if file, exists := m.files[name]; exists {
+		info := memFileInfo{name: path.Base(name), file: file}
+		return &memFile{info: info, reader: bytes.NewReader(file.data)}, nil
+	}
+
+	if !m.isDir(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return &memDir{info: memFileInfo{name: path.Base(name)}, entries: m.readDir(name)}, nil
+}
+
+// isDir reports whether name is "." or a parent of a stored file
+func (m *MemFS) isDir(name string) bool {
+	if name == "." {
+		return true
+	}
+	prefix := name + "/"
+	for fileName := range m.files {
+		if strings.HasPrefix(fileName, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// readDir lists the direct children of dir, sorted by name
+func (m *MemFS) readDir(dir string) []fs.DirEntry {
+	prefix := dir + "/"
+	if dir == "." {
+		prefix = ""
+	}
+
+	children := make(map[string]memFileInfo)
+	for fileName, file := range m.files {
+		rest, found := strings.CutPrefix(fileName, prefix)
+		if !found {
+			continue
+		}
+		if child, _, isNested := strings.Cut(rest, "/"); isNested {
+			children[child] = memFileInfo{name: child}
+		} else {
+			children[child] = memFileInfo{name: child, file: file}
+		}
+	}
+
+	entries := make([]fs.DirEntry, 0, len(children))
+	for _, info := range children {
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries
+}
+
+// memFileInfo describes a file, or a directory when file is nil
+type memFileInfo struct {
+	name string
+	file *memFileData
+}
+
+func (i memFileInfo) Name() string { return i.name }
+func (i memFileInfo) Sys() any     { return nil }
+func (i memFileInfo) IsDir() bool  { return i.file == nil }
+
+func (i memFileInfo) Size() int64 {
+	if i.file == nil {
+		return 0
+	}
+	return int64(len(i.file.data))
+}
+
+func (i memFileInfo) Mode() fs.FileMode {
+	if i.file == nil {
+		return fs.ModeDir | 0o755
+	}
+	return i.file.mode
+}
+
+func (i memFileInfo) ModTime() time.Time {
+	if i.file == nil {
+		return time.Time{}
+	}
+	return i.file.modTime
+}
+
+// memFile is an open regular file
+type memFile struct {
+	info   memFileInfo
+	reader *bytes.Reader
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memFile) Read(p []byte) (int, error) { return f.reader.Read(p) }
+func (f *memFile) Close() error               { return nil }
+
+// memDir is an open directory
+type memDir struct {
+	info    memFileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *memDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *memDir) Close() error               { return nil }
+
+func (d *memDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+// ReadDir implements fs.ReadDirFile
+func (d *memDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(remaining) {
+		n = len(remaining)
+	}
+	d.offset += n
+	return remaining[:n], nil
+}
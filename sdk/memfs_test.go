@@ -0,0 +1,82 @@
+package sdk
+
+import (
+	"context"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+func TestMemFS(t *testing.T) {
+	memfs := NewMemFS()
+	files := map[string]string{
+		"README.md":           "# App",
+		"src/main.go":         "package main",
+		"src/internal/app.go": "package internal",
+	}
+	for name, content := range files {
+		if err := memfs.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatalf("WriteFile(%s) error = %v", name, err)
+		}
+	}
+
+	if err := fstest.TestFS(memfs, "README.md", "src/main.go", "src/internal/app.go"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"../escape", "/abs", "src"} {
+		if err := memfs.WriteFile(name, []byte("x"), 0o644); err == nil {
+			t.Errorf("WriteFile(%q) should fail", name)
+		}
+	}
+}
+
+func TestGenerateToOutput(t *testing.T) {
+	client := New()
+
+	schema := &core.TemplateSchema{
+		Name:    "test-template",
+		Type:    "frontend",
+		Version: "1.0.0",
+		Variables: map[string]core.Variable{
+			"ProjectName": {Type: "string", Required: true},
+		},
+		Files: []core.FileSpec{
+			{Path: "README.md", Template: true, Content: "# {{.ProjectName}}"},
+			{Path: "src/index.ts", Content: "export {}"},
+		},
+	}
+
+	memfs := NewMemFS()
+	variables := Variables{ProjectName: "test-project", GitHubRepo: "user/test-repo"}
+	if err := client.GenerateToOutput(context.Background(), schema, variables, memfs); err != nil {
+		t.Fatalf("GenerateToOutput failed: %v", err)
+	}
+
+	if got := memfs.Files(); len(got) != 2 {
+		t.Fatalf("Expected 2 generated files, got %v", got)
+	}
+	content, err := fs.ReadFile(memfs, "README.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "# test-project" {
+		t.Errorf("Expected rendered README, got %q", content)
+	}
+
+	for _, invalid := range []Variables{
+		{ProjectName: "test-project", GitHubRepo: "user/test-repo", GoMod: GoModOptions{GoVersion: "1.23\ntoolchain evil"}},
+		{ProjectName: "test-project", GitHubRepo: "user/test-repo", License: "GPL-9"},
+	} {
+		memfs := NewMemFS()
+		err := client.GenerateToOutput(context.Background(), schema, invalid, memfs)
+		if sdkErr, ok := err.(*SDKError); !ok || sdkErr.Type != ErrorTypeValidation {
+			t.Errorf("Expected validation error for %+v, got %v", invalid, err)
+		}
+		if got := memfs.Files(); len(got) != 0 {
+			t.Errorf("Expected nothing generated for invalid variables, got %v", got)
+		}
+	}
+}
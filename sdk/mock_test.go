@@ -44,10 +44,10 @@ func createMockClient() *Client {
 		},
 	}
 
-	return &Client{
-		templates: map[string]*core.TemplateSchema{
-			"mock-frontend": mockFrontendSchema,
-			"mock-api":      mockAPISchema,
-		},
+	client := New()
+	client.templates = map[string]*core.TemplateSchema{
+		"mock-frontend": mockFrontendSchema,
+		"mock-api":      mockAPISchema,
 	}
+	return client
 }
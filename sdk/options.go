@@ -0,0 +1,114 @@
+package sdk
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/acheevo/template-engine/internal/logging"
+	"github.com/acheevo/template-engine/internal/policy"
+	"github.com/acheevo/template-engine/internal/schemacache"
+)
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithLogger sets the logger used for SDK progress output.
+// By default the client discards all log output so embedding tools stay quiet.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		if logger == nil {
+			logger = logging.Discard()
+		}
+		c.logger = logger
+	}
+}
+
+// WithHooks runs the schema hooks when generating into a directory; by default the SDK never
+// runs them. Hooks of schemas pulled from a registry are still skipped, see WithRemoteHooks.
+// When allowedBinaries are given, hooks may only invoke those programs.
+func WithHooks(allowedBinaries ...string) Option {
+	return func(c *Client) {
+		c.hooks.Enabled = true
+		c.hooks.AllowedBinaries = allowedBinaries
+	}
+}
+
+// WithRemoteHooks also runs the hooks of schemas pulled from a registry or OCI reference when
+// WithHooks is given, and lets TestTemplate run their commands, like --allow-hooks. Only use it
+// for registries you trust.
+func WithRemoteHooks() Option {
+	return func(c *Client) {
+		c.hooks.AllowRemote = true
+	}
+}
+
+// WithSchemaCache reuses schemas extracted from an unchanged reference project, stored in dir or
+// in the user cache directory when dir is empty. Entries are keyed by the content of the project,
+// the template type and the engine version, so any change to the project extracts it anew.
+func WithSchemaCache(dir string) Option {
+	return func(c *Client) {
+		if dir == "" {
+			cache, err := schemacache.Default()
+			if err != nil {
+				c.logger.Warn("Schema cache disabled", "error", err)
+				return
+			}
+			c.cache = cache
+			return
+		}
+		c.cache = schemacache.New(dir)
+	}
+}
+
+// WithHTTPClient sets the HTTP client remote schemas are downloaded with (see
+// RegisterRemoteSchema), to configure proxies, TLS or timeouts. By default http.DefaultClient is
+// used, which honors the HTTP_PROXY and HTTPS_PROXY environment variables.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.download.Client = client
+	}
+}
+
+// WithRetries sets how many times a failed download of a remote schema is retried and the delay
+// before the first retry, doubled for each further one. By default downloads are retried 3 times
+// starting after half a second; negative retries disable retrying.
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.download.Retries = retries
+		c.download.Backoff = backoff
+	}
+}
+
+// WithOffline forbids network access, for air-gapped environments: downloads of remote schemas
+// fail with an error wrapping ErrOffline, and go mod tidy only resolves modules from the module
+// cache. Hooks are commands of the schema and are not restricted.
+func WithOffline() Option {
+	return func(c *Client) {
+		c.download.Offline = true
+	}
+}
+
+// LoadPolicy reads a declarative policy file, such as
+//
+//	{"allowed_owners": ["acme"], "project_name_pattern": "^[a-z][a-z0-9-]+$",
+//	 "required_variables": {"go-api": ["Team"]}, "rego": "policy.rego"}
+func LoadPolicy(path string) (*PolicyRules, error) {
+	return policy.Load(path)
+}
+
+// WithPolicies rejects generation requests violating any of the policies (e.g. a *PolicyRules
+// or a custom Policy) before anything is written, with a *PolicyViolationError listing every
+// violation, see errors.As
+func WithPolicies(policies ...Policy) Option {
+	return func(c *Client) {
+		c.policies = append(c.policies, policies...)
+	}
+}
+
+// WithConcurrency bounds how many projects GenerateMany generates at once, one per CPU by default
+func WithConcurrency(n int) Option {
+	return func(c *Client) {
+		c.concurrency = n
+	}
+}
@@ -0,0 +1,178 @@
+// Package sdktest helps template authors test their schemas, e.g. with golden-file snapshots
+// that catch unintended output changes when mappings are tweaked.
+package sdktest
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/acheevo/template-engine/sdk"
+)
+
+// updateGolden rewrites golden directories instead of comparing against them:
+//
+//	go test ./... -update-golden
+var updateGolden = flag.Bool("update-golden", false, "Rewrite golden directories with the generated output")
+
+// Difference is a file whose generated content does not match the golden directory
+type Difference struct {
+	Path   string
+	Reason string
+}
+
+func (d Difference) String() string {
+	return d.Path + ": " + d.Reason
+}
+
+// AssertGolden generates schema with variables in memory and fails t when the output differs
+// from the files in goldenDir. With -update-golden, goldenDir is rewritten instead.
+func AssertGolden(t testing.TB, schema *sdk.TemplateSchema, variables sdk.Variables, goldenDir string) {
+	t.Helper()
+
+	generated, err := Render(schema, variables)
+	if err != nil {
+		t.Fatalf("failed to generate %s: %v", schema.Name, err)
+	}
+
+	if *updateGolden {
+		if err := WriteGolden(generated, goldenDir); err != nil {
+			t.Fatalf("failed to update golden directory: %v", err)
+		}
+		return
+	}
+
+	differences, err := Compare(generated, goldenDir)
+	if err != nil {
+		t.Fatalf("failed to compare with golden directory: %v", err)
+	}
+	for _, difference := range differences {
+		t.Errorf("%s", difference)
+	}
+	if len(differences) > 0 {
+		t.Errorf("generated output differs from %s, rerun with -update-golden to accept it", goldenDir)
+	}
+}
+
+// Render generates schema with variables into memory. An empty Author is rendered as "Developer"
+// rather than the git user, so goldens don't depend on the machine they were written on.
+func Render(schema *sdk.TemplateSchema, variables sdk.Variables) (*sdk.MemFS, error) {
+	if variables.Author == "" {
+		variables.Author = "Developer"
+	}
+
+	generated := sdk.NewMemFS()
+	if err := sdk.New().GenerateToOutput(context.Background(), schema, variables, generated); err != nil {
+		return nil, err
+	}
+	return generated, nil
+}
+
+// Compare returns the differences between a generated project and goldenDir
+func Compare(generated *sdk.MemFS, goldenDir string) ([]Difference, error) {
+	golden, err := readTree(goldenDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var differences []Difference
+	for _, path := range generated.Files() {
+		content, err := generated.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		expected, exists := golden[path]
+		delete(golden, path)
+		switch {
+		case !exists:
+			differences = append(differences, Difference{Path: path, Reason: "generated but missing from golden"})
+		case !bytes.Equal(content, expected):
+			differences = append(differences, Difference{Path: path, Reason: firstDifference(expected, content)})
+		}
+	}
+
+	extra := make([]string, 0, len(golden))
+	for path := range golden {
+		extra = append(extra, path)
+	}
+	sort.Strings(extra)
+	for _, path := range extra {
+		differences = append(differences, Difference{Path: path, Reason: "in golden but no longer generated"})
+	}
+
+	return differences, nil
+}
+
+// WriteGolden replaces goldenDir with the generated project
+func WriteGolden(generated *sdk.MemFS, goldenDir string) error {
+	if err := os.RemoveAll(goldenDir); err != nil {
+		return err
+	}
+
+	for _, path := range generated.Files() {
+		content, err := generated.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		destPath := filepath.Join(goldenDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(destPath, content, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readTree reads every file below dir, keyed by slash-separated relative path.
+// A missing directory reads as empty, so the first run reports every file as missing.
+func readTree(dir string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return files, nil
+	}
+
+	err := fs.WalkDir(os.DirFS(dir), ".", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+		if err != nil {
+			return err
+		}
+		files[path] = content
+		return nil
+	})
+	return files, err
+}
+
+// firstDifference describes the first line where got differs from want
+func firstDifference(want, got []byte) string {
+	wantLines := strings.Split(string(want), "\n")
+	gotLines := strings.Split(string(got), "\n")
+
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var wantLine, gotLine string
+		if i < len(wantLines) {
+			wantLine = wantLines[i]
+		}
+		if i < len(gotLines) {
+			gotLine = gotLines[i]
+		}
+		if wantLine != gotLine || i >= len(wantLines) || i >= len(gotLines) {
+			return fmt.Sprintf("line %d: want %q, got %q", i+1, wantLine, gotLine)
+		}
+	}
+	return "content differs"
+}
@@ -0,0 +1,88 @@
+package sdktest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/acheevo/template-engine/internal/core"
+	"github.com/acheevo/template-engine/sdk"
+)
+
+// recorder captures failures instead of failing the test
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func testSchema(readme string) *sdk.TemplateSchema {
+	return &core.TemplateSchema{
+		Name:    "test-template",
+		Type:    "frontend",
+		Version: "1.0.0",
+		Variables: map[string]core.Variable{
+			"ProjectName": {Type: "string", Required: true},
+		},
+		Files: []core.FileSpec{
+			{Path: "README.md", Template: true, Content: readme},
+			{Path: "src/index.ts", Content: "export {}\n"},
+		},
+	}
+}
+
+var testVariables = sdk.Variables{ProjectName: "My App", GitHubRepo: "user/my-app"}
+
+func TestAssertGolden(t *testing.T) {
+	goldenDir := filepath.Join(t.TempDir(), "golden")
+	schema := testSchema("# {{.ProjectName}}\n")
+
+	generated, err := Render(schema, testVariables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteGolden(generated, goldenDir); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(goldenDir, "README.md"))
+	if err != nil || string(content) != "# My App\n" {
+		t.Fatalf("golden README.md = %q, %v", content, err)
+	}
+
+	// Matching output passes
+	AssertGolden(t, schema, testVariables, goldenDir)
+
+	// Changed output is reported with the first differing line
+	rec := &recorder{TB: t}
+	AssertGolden(rec, testSchema("# {{.ProjectName}} v2\n"), testVariables, goldenDir)
+	if len(rec.errors) != 2 || rec.errors[0] != `README.md: line 1: want "# My App", got "# My App v2"` {
+		t.Errorf("unexpected failures: %q", rec.errors)
+	}
+}
+
+func TestCompareMissingAndExtraFiles(t *testing.T) {
+	goldenDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(goldenDir, "OLD.md"), []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	generated := sdk.NewMemFS()
+	if err := generated.WriteFile("NEW.md", []byte("new"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	differences, err := Compare(generated, goldenDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(differences) != 2 || differences[0].Path != "NEW.md" || differences[1].Path != "OLD.md" {
+		t.Errorf("Compare() = %v", differences)
+	}
+}
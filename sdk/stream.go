@@ -0,0 +1,65 @@
+package sdk
+
+import (
+	"context"
+
+	"github.com/acheevo/template-engine/internal/generate"
+)
+
+// Event reports the progress of GenerateStream
+type (
+	Event     = generate.Event
+	EventType = generate.EventType
+)
+
+// Event types, in the order a generation emits them
+const (
+	EventFile       = generate.EventFile
+	EventHookStart  = generate.EventHookStart
+	EventHookOutput = generate.EventHookOutput
+	EventHook       = generate.EventHook
+	EventDone       = generate.EventDone
+	EventError      = generate.EventError
+)
+
+// streamBuffer is the number of events GenerateStream buffers for a slow reader
+const streamBuffer = 64
+
+// GenerateStream generates a project from a template schema in the background and reports its
+// progress on the returned channel as it happens: an EventFile per file written, hook runs with
+// their output line by line, and finally EventDone with the result or EventError, after which
+// the channel is closed. Invalid input is reported right away as an error instead.
+//
+// Generation blocks while the channel buffer is full, so a reader going away must cancel ctx.
+// Events that cannot be delivered once ctx is cancelled are dropped; the channel is still closed.
+func (c *Client) GenerateStream(ctx context.Context, schema *TemplateSchema, variables Variables,
+) (<-chan Event, error) {
+	if err := c.ValidateVariables(variables); err != nil {
+		return nil, err
+	}
+	if err := c.Validate(schema); err != nil {
+		return nil, newSchemaError("GenerateStream", "invalid template schema", err)
+	}
+
+	events := make(chan Event, streamBuffer)
+	generator := c.newGenerator(ctx, schema, variables)
+	generator.SetEventHandler(func(event Event) {
+		select {
+		case events <- event:
+			return
+		default:
+		}
+		select {
+		case events <- event:
+		case <-ctx.Done():
+		}
+	})
+
+	c.logger.Debug("Generating project", "schema", schema.Name, "output", variables.OutputDir)
+
+	go func() {
+		defer close(events)
+		_ = c.generate(ctx, "GenerateStream", generator, schema, variables) // Reported as EventError
+	}()
+	return events, nil
+}
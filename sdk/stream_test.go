@@ -0,0 +1,86 @@
+package sdk
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+func TestGenerateStream(t *testing.T) {
+	schema := &core.TemplateSchema{
+		Name:      "service",
+		Type:      "go-api",
+		Version:   "1.0.0",
+		Variables: map[string]core.Variable{},
+		Files: []core.FileSpec{
+			{Path: "README.md", Template: true, Content: "# {{.ProjectName}}"},
+			{Path: "main.go", Content: "package main"},
+		},
+		Hooks: core.Hooks{{Name: "greet", Stage: core.HookPostGenerate, Command: "echo one; printf two"}},
+	}
+	variables := Variables{
+		ProjectName: "service", GitHubRepo: "acme/service", OutputDir: filepath.Join(t.TempDir(), "service"),
+	}
+
+	events, err := New(WithHooks()).GenerateStream(context.Background(), schema, variables)
+	if err != nil {
+		t.Fatalf("GenerateStream() error = %v", err)
+	}
+
+	var got []string
+	var last Event
+	for event := range events {
+		switch event.Type {
+		case EventFile:
+			got = append(got, "file "+event.Path)
+		case EventHookOutput:
+			got = append(got, "output "+event.Line)
+		default:
+			got = append(got, string(event.Type)+" "+event.Hook)
+		}
+		last = event
+	}
+
+	want := "file README.md,file main.go,hook_start greet,output one,output two,hook greet,done "
+	if strings.Join(got, ",") != want {
+		t.Errorf("events = %q, want %q", strings.Join(got, ","), want)
+	}
+	if last.Result == nil || last.Result.FileCount != 2 {
+		t.Errorf("done event result = %+v", last.Result)
+	}
+}
+
+func TestGenerateStreamErrors(t *testing.T) {
+	schema := &core.TemplateSchema{
+		Name:      "service",
+		Type:      "go-api",
+		Version:   "1.0.0",
+		Variables: map[string]core.Variable{},
+		Files:     []core.FileSpec{{Path: "README.md", Content: "# service"}},
+		Hooks:     core.Hooks{{Stage: core.HookPostGenerate, Command: "exit 3"}},
+	}
+	client := New(WithHooks())
+
+	if _, err := client.GenerateStream(context.Background(), schema, Variables{}); err == nil {
+		t.Error("GenerateStream() without variables should fail right away")
+	}
+
+	variables := Variables{
+		ProjectName: "service", GitHubRepo: "acme/service", OutputDir: filepath.Join(t.TempDir(), "service"),
+	}
+	events, err := client.GenerateStream(context.Background(), schema, variables)
+	if err != nil {
+		t.Fatalf("GenerateStream() error = %v", err)
+	}
+
+	var last Event
+	for event := range events {
+		last = event
+	}
+	if last.Type != EventError || !strings.Contains(last.Error, "exited with code 3") {
+		t.Errorf("last event = %+v, want the hook failure", last)
+	}
+}
@@ -0,0 +1,126 @@
+package sdk
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+// Transform modifies a schema before generation, see TransformSchema
+type Transform func(schema *TemplateSchema) error
+
+// TransformSchema applies transforms in order to a copy of schema, leaving schema itself
+// untouched, so wrappers can customize shared templates on the fly. The schema hash of the
+// copy is recomputed and the copy is validated before it is returned.
+func (c *Client) TransformSchema(schema *TemplateSchema, transforms ...Transform) (*TemplateSchema, error) {
+	if schema == nil {
+		return nil, newValidationError("TransformSchema", "schema is required", "")
+	}
+
+	transformed := schema.Clone()
+	for i, transform := range transforms {
+		if err := transform(transformed); err != nil {
+			return nil, newSchemaError("TransformSchema", fmt.Sprintf("transform %d failed", i), err)
+		}
+	}
+	transformed.Hash = core.CalculateSchemaHash(transformed)
+
+	if err := core.ValidateSchema(transformed); err != nil {
+		return nil, newSchemaError("TransformSchema", "transformed schema is invalid", err)
+	}
+	return transformed, nil
+}
+
+// AddFile adds a file with content at path, replacing the file already there. Templated
+// files are rendered with the schema variables like extracted ones; binary content is stored
+// base64 encoded and cannot be templated.
+func AddFile(path, content string, template bool) Transform {
+	return func(schema *TemplateSchema) error {
+		encoded, encoding := core.EncodeContent([]byte(content))
+		if encoding != "" && template {
+			return fmt.Errorf("file %s is binary and cannot be templated", path)
+		}
+		stored, compressed, err := core.CompressContent(encoded)
+		if err != nil {
+			return fmt.Errorf("file %s failed to compress: %w", path, err)
+		}
+
+		file := SchemaFile{
+			Path:       path,
+			Template:   template,
+			Content:    stored,
+			Encoding:   encoding,
+			Size:       int64(len(content)),
+			Hash:       core.CalculateContentHash(content),
+			Compressed: compressed,
+		}
+		if compressed {
+			file.Codec = string(core.CodecGzip)
+		}
+
+		index := slices.IndexFunc(schema.Files, func(existing SchemaFile) bool { return existing.Path == path })
+		if index < 0 {
+			schema.Files = append(schema.Files, file)
+		} else {
+			schema.Files[index] = file
+		}
+		return nil
+	}
+}
+
+// RemoveFiles removes the files matching any of patterns. Patterns are globs in which "**"
+// matches any number of directories, e.g. "docs/**" or "**/*.test.ts".
+func RemoveFiles(patterns ...string) Transform {
+	return func(schema *TemplateSchema) error {
+		schema.Files = slices.DeleteFunc(schema.Files, func(file SchemaFile) bool {
+			return core.MatchAnyGlob(patterns, file.Path)
+		})
+		return nil
+	}
+}
+
+// AddMapping appends mapping to every templated file matching pattern (see RemoveFiles)
+func AddMapping(pattern string, mapping Mapping) Transform {
+	return func(schema *TemplateSchema) error {
+		for i := range schema.Files {
+			if schema.Files[i].Template && core.MatchGlob(pattern, schema.Files[i].Path) {
+				schema.Files[i].Mappings = append(schema.Files[i].Mappings, mapping)
+			}
+		}
+		return nil
+	}
+}
+
+// RewriteMappings passes every mapping of every file to rewrite with the file path, and
+// keeps the mapping it returns; mappings for which keep is false are dropped
+func RewriteMappings(rewrite func(path string, mapping Mapping) (rewritten Mapping, keep bool)) Transform {
+	return func(schema *TemplateSchema) error {
+		for i := range schema.Files {
+			file := &schema.Files[i]
+			mappings := file.Mappings[:0]
+			for _, mapping := range file.Mappings {
+				if rewritten, keep := rewrite(file.Path, mapping); keep {
+					mappings = append(mappings, rewritten)
+				}
+			}
+			file.Mappings = mappings
+		}
+		return nil
+	}
+}
+
+// SetVariable declares the variable name, replacing any existing definition. Values for
+// custom variables are passed to generation through Variables.Custom.
+func SetVariable(name string, variable Variable) Transform {
+	return func(schema *TemplateSchema) error {
+		if variable.Type == "" {
+			return fmt.Errorf("variable %s must have a type", name)
+		}
+		if schema.Variables == nil {
+			schema.Variables = make(map[string]Variable)
+		}
+		schema.Variables[name] = variable
+		return nil
+	}
+}
@@ -0,0 +1,115 @@
+package sdk
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/acheevo/template-engine/internal/core"
+)
+
+func TestTransformSchema(t *testing.T) {
+	schema := &core.TemplateSchema{
+		Name:      "service",
+		Type:      "go-api",
+		Version:   "1.0.0",
+		Variables: map[string]core.Variable{},
+		Files: []core.FileSpec{
+			{Path: "README.md", Template: true, Content: "# acme-service", Mappings: []core.Mapping{
+				{Find: "acme-service", Replace: "{{.ProjectName}}"},
+			}},
+			{Path: "docs/internal.md", Content: "internal notes"},
+			{Path: "main.go", Template: true, Content: "package main // acme"},
+		},
+	}
+
+	transformed, err := New().TransformSchema(schema,
+		RemoveFiles("docs/**"),
+		AddFile("CODEOWNERS", "* @{{.Team}}\n", true),
+		SetVariable("Team", Variable{Type: "string", Required: true}),
+		AddMapping("*.go", Mapping{Find: "acme", Replace: "{{.Team}}"}),
+		RewriteMappings(func(path string, mapping Mapping) (Mapping, bool) {
+			return mapping, path != "README.md"
+		}),
+	)
+	if err != nil {
+		t.Fatalf("TransformSchema() error = %v", err)
+	}
+
+	var paths []string
+	for _, file := range transformed.Files {
+		paths = append(paths, file.Path)
+	}
+	if got := strings.Join(paths, ","); got != "README.md,main.go,CODEOWNERS" {
+		t.Errorf("files = %s", got)
+	}
+	if len(transformed.Files[0].Mappings) != 0 {
+		t.Errorf("README.md mappings = %v, want them dropped", transformed.Files[0].Mappings)
+	}
+	if mappings := transformed.Files[1].Mappings; len(mappings) != 1 || mappings[0].Replace != "{{.Team}}" {
+		t.Errorf("main.go mappings = %v", mappings)
+	}
+	if _, ok := transformed.Variables["Team"]; !ok {
+		t.Error("variable Team was not added")
+	}
+	if transformed.Hash == "" {
+		t.Error("schema hash was not recomputed")
+	}
+
+	if len(schema.Files) != 3 || len(schema.Files[0].Mappings) != 1 || len(schema.Variables) != 0 {
+		t.Error("TransformSchema() modified the original schema")
+	}
+}
+
+func TestTransformSchemaErrors(t *testing.T) {
+	schema := &core.TemplateSchema{
+		Name:      "service",
+		Type:      "go-api",
+		Version:   "1.0.0",
+		Variables: map[string]core.Variable{},
+		Files:     []core.FileSpec{{Path: "README.md", Content: "# service"}},
+	}
+	client := New()
+
+	failing := errors.New("boom")
+	_, err := client.TransformSchema(schema, func(*TemplateSchema) error { return failing })
+	if !errors.Is(err, failing) {
+		t.Errorf("TransformSchema() error = %v, want the transform error", err)
+	}
+
+	if _, err := client.TransformSchema(schema, RemoveFiles("**")); err == nil {
+		t.Error("TransformSchema() removing every file should fail validation")
+	}
+
+	if _, err := client.TransformSchema(schema, SetVariable("Team", Variable{})); err == nil {
+		t.Error("SetVariable() without a type should fail")
+	}
+}
+
+func TestTransformSchemaBinaryFile(t *testing.T) {
+	schema := &core.TemplateSchema{
+		Name:      "service",
+		Type:      "go-api",
+		Version:   "1.0.0",
+		Variables: map[string]core.Variable{},
+		Files:     []core.FileSpec{{Path: "README.md", Content: "# service"}},
+	}
+	logo := "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+	client := New()
+
+	transformed, err := client.TransformSchema(schema, AddFile("assets/logo.png", logo, false))
+	if err != nil {
+		t.Fatalf("TransformSchema() error = %v", err)
+	}
+	file := transformed.Files[1]
+	if file.Encoding != "base64" {
+		t.Errorf("logo.png encoding = %q, want base64", file.Encoding)
+	}
+	if content, err := core.ResolveContent(transformed, file); err != nil || content != logo {
+		t.Errorf("ResolveContent(logo.png) = %q, %v", content, err)
+	}
+
+	if _, err := client.TransformSchema(schema, AddFile("assets/logo.png", logo, true)); err == nil {
+		t.Error("AddFile() of a templated binary file should fail")
+	}
+}